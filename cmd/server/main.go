@@ -1,56 +1,90 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"os"
-	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"event-management-backend/internal/backup"
 	"event-management-backend/internal/config"
+	"event-management-backend/internal/fieldcrypt"
+	"event-management-backend/internal/graph"
 	"event-management-backend/internal/handlers"
+	"event-management-backend/internal/jobs"
+	"event-management-backend/internal/jwtkeys"
+	"event-management-backend/internal/lifecycle"
+	"event-management-backend/internal/mail"
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/ratelimit"
 	"event-management-backend/internal/repositories"
 	"event-management-backend/internal/services"
+	"event-management-backend/internal/whatsapp"
 	"event-management-backend/pkg/database"
 	"event-management-backend/pkg/logger"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/joho/godotenv"
 )
 
+//go:generate go run github.com/swaggo/swag/cmd/swag@v1.16.6 init -g cmd/server/main.go -d ../../ -o ../../docs --outputTypes json,yaml --parseInternal
+
+// @title Event Management API
+// @version 1.0
+// @description Event registration, participant management and QR check-in API.
+// @BasePath /api/v1
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Type "Bearer" followed by a space and the JWT.
 func main() {
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
-		log.Printf("Warning: .env file not found: %v", err)
+		logger.Log.Warnf(".env file not found: %v", err)
 	}
 
-	// Initialize logger
-	logger.Init()
-
 	// Load configuration
 	cfg, err := config.NewConfigFromEnv()
 	if err != nil {
-		log.Fatalf("Config error: %v", err)
+		logger.Log.Fatalf("Config error: %v", err)
+	}
+
+	// Initialize logger
+	logger.Init(cfg.LogLevel, cfg.Env)
+
+	// Encrypt participant PII at rest when keys are configured
+	if err := fieldcrypt.Configure(cfg.PIIEncryptionKeys, cfg.PIIPrimaryKey, cfg.PIIHashKey); err != nil {
+		logger.Log.Fatalf("Encryption key error: %v", err)
+	}
+
+	// Load the keys signing and verifying access tokens
+	jwtKeys, err := jwtkeys.Load(cfg)
+	if err != nil {
+		logger.Log.Fatalf("JWT key error: %v", err)
 	}
 
 	// Initialize database
-	db, err := database.NewPostgresDB(cfg)
+	db, err := database.NewDB(cfg)
 	if err != nil {
-		log.Fatalf("Database connection error: %v", err)
+		logger.Log.Fatalf("Database connection error: %v", err)
 	}
 
 	// Run migrations
 	if err := repositories.AutoMigrate(db); err != nil {
-		log.Fatalf("Migration error: %v", err)
+		logger.Log.Fatalf("Migration error: %v", err)
 	}
 
 	// Initialize repositories
 	repo := repositories.NewRepository(db)
 
 	// Initialize services
-	authSvc := services.NewAuthService(repo, cfg)
+	authSvc := services.NewAuthService(repo, jwtKeys, cfg)
 	eventSvc := services.NewEventService(repo, cfg)
 	participantSvc := services.NewParticipantService(repo, cfg)
 	verificationSvc := services.NewVerificationService(
@@ -58,59 +92,320 @@ func main() {
 		repo.EventRepo,
 		repo.UserRepo,
 		repo.ParticipantRepo,
+		repo.SessionRepo,
+		repo.WaiverRepo,
+		repo.SeatingRepo,
+		repo.MealRepo,
+		repo.ShiftRepo,
+		repo.DeviceRepo,
 		cfg,
 	)
 
+	kioskSvc := services.NewKioskService(repo, cfg)
+	drawSvc := services.NewDrawService(repo, cfg)
+	sessionSvc := services.NewSessionService(repo, cfg)
+	speakerSvc := services.NewSpeakerService(repo, cfg)
+	sponsorSvc := services.NewSponsorService(repo, cfg)
+	widgetSvc := services.NewWidgetService(repo, participantSvc, cfg)
+	waiverSvc := services.NewWaiverService(repo, participantSvc, cfg)
+	seatingSvc := services.NewSeatingService(repo, cfg)
+	mealSvc := services.NewMealService(repo, cfg)
+	shiftSvc := services.NewShiftService(repo, cfg)
+	liveStatsSvc := services.NewLiveStatsService(repo, cfg)
+	onlineSvc := services.NewOnlineService(repo, mail.NewSender(cfg), cfg)
+	notificationSvc := services.NewNotificationService(repo, mail.NewSender(cfg), whatsapp.NewSender(cfg), cfg)
+	apiKeySvc := services.NewAPIKeyService(repo, cfg)
+	auditSvc := services.NewAuditService(repo, cfg)
+	deviceSvc := services.NewDeviceService(repo, cfg)
+
+	// Initialize background job queue
+	jobQueue := jobs.NewQueue(repo.JobRepo, cfg.JobWorkers, cfg.JobPollInterval)
+	jobQueue.Register("idempotency.cleanup", func(ctx context.Context, payload json.RawMessage) error {
+		_, err := repo.IdempotencyRepo.DeleteExpired(time.Now())
+		return err
+	})
+	jobQueue.Every(time.Hour, "idempotency.cleanup", nil)
+	jobQueue.Register(jobs.TypeTokenCleanup, func(ctx context.Context, payload json.RawMessage) error {
+		_, err := authSvc.DeleteExpiredRevokedTokens()
+		return err
+	})
+	jobQueue.Every(time.Hour, jobs.TypeTokenCleanup, nil)
+	jobQueue.Register(jobs.TypeLoginCleanup, func(ctx context.Context, payload json.RawMessage) error {
+		_, err := authSvc.DeleteOldLogins()
+		return err
+	})
+	jobQueue.Every(24*time.Hour, jobs.TypeLoginCleanup, nil)
+	jobQueue.Register(jobs.TypeLogoVariants, func(ctx context.Context, payload json.RawMessage) error {
+		var p jobs.EventPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		return eventSvc.GenerateLogoVariants(p.EventID)
+	})
+	jobQueue.Register(jobs.TypePhotoVariants, func(ctx context.Context, payload json.RawMessage) error {
+		var p jobs.SpeakerPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		return speakerSvc.GeneratePhotoVariants(p.SpeakerID)
+	})
+	jobQueue.Register(jobs.TypeJoinLinks, func(ctx context.Context, payload json.RawMessage) error {
+		var p jobs.EventPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		_, err := onlineSvc.SendJoinLinks(ctx, p.EventID)
+		return err
+	})
+	jobQueue.Register(jobs.TypeCancellationNotices, func(ctx context.Context, payload json.RawMessage) error {
+		var p jobs.EventPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		_, err := notificationSvc.SendCancellationNotices(ctx, p.EventID)
+		return err
+	})
+	jobQueue.Register(jobs.TypeTicketResend, func(ctx context.Context, payload json.RawMessage) error {
+		var p jobs.ParticipantPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		_, err := notificationSvc.ResendTicket(ctx, "", p.ParticipantID)
+		if errors.Is(err, services.ErrTicketNotIssued) || errors.Is(err, services.ErrTicketCancelled) || errors.Is(err, services.ErrUnknownParticipant) {
+			// Nothing to send; retrying would not change that
+			return nil
+		}
+		return err
+	})
+	jobQueue.Register(jobs.TypeWaitlistPromote, func(ctx context.Context, payload json.RawMessage) error {
+		var p jobs.EventPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		promoted, err := participantSvc.PromoteWaitlist(p.EventID)
+		for _, participant := range promoted {
+			if _, err := jobQueue.Enqueue(jobs.TypeTicketResend, jobs.ParticipantPayload{ParticipantID: participant.ID.String()}); err != nil {
+				logger.Log.WithError(err).WithField("participant_id", participant.ID.String()).Warn("failed to enqueue ticket of promoted participant")
+			}
+		}
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return nil
+		}
+		return err
+	})
+	jobQueue.Register(jobs.TypeConfirmationEmail, func(ctx context.Context, payload json.RawMessage) error {
+		var p jobs.ParticipantPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		err := notificationSvc.SendRegistrationConfirmation(ctx, p.ParticipantID)
+		if errors.Is(err, services.ErrRegistrationNotPendingConfirmation) || errors.Is(err, services.ErrUnknownParticipant) {
+			// Confirmed or expired already
+			return nil
+		}
+		return err
+	})
+	jobQueue.Register(jobs.TypeReviewDecision, func(ctx context.Context, payload json.RawMessage) error {
+		var p jobs.ParticipantPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		err := notificationSvc.SendReviewDecision(ctx, p.ParticipantID)
+		if errors.Is(err, services.ErrNoReviewDecision) || errors.Is(err, services.ErrTicketCancelled) || errors.Is(err, services.ErrUnknownParticipant) {
+			// Nothing to tell anymore
+			return nil
+		}
+		return err
+	})
+	jobQueue.Register(jobs.TypeConfirmationExpiry, func(ctx context.Context, payload json.RawMessage) error {
+		expired, err := participantSvc.ExpireUnconfirmedRegistrations(ctx)
+		// Freed tickets go to the waitlists of the events
+		promoted := map[string]bool{}
+		for _, participant := range expired {
+			eventID := participant.EventID.String()
+			if promoted[eventID] {
+				continue
+			}
+			promoted[eventID] = true
+			if _, err := jobQueue.Enqueue(jobs.TypeWaitlistPromote, jobs.EventPayload{EventID: eventID}); err != nil {
+				logger.Log.WithError(err).WithField("event_id", eventID).Warn("failed to enqueue waitlist promotion")
+			}
+		}
+		return err
+	})
+	jobQueue.Every(15*time.Minute, jobs.TypeConfirmationExpiry, nil)
+	jobQueue.Register(jobs.TypeParticipantImport, func(ctx context.Context, payload json.RawMessage) error {
+		var p jobs.ImportPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return err
+		}
+		return participantSvc.RunParticipantImport(ctx, p.ImportJobID)
+	})
+
+	// Database and upload backups
+	backupSvc := backup.NewService(backup.NewStore(cfg), cfg)
+	jobQueue.Register(jobs.TypeBackup, func(ctx context.Context, payload json.RawMessage) error {
+		_, err := backupSvc.Run(ctx)
+		return err
+	})
+	if cfg.BackupEnabled {
+		jobQueue.Every(cfg.BackupInterval, jobs.TypeBackup, nil)
+	}
+
+	// Data retention rules
+	retentionSvc := services.NewRetentionService(repo, cfg)
+	jobQueue.Register(jobs.TypeRetention, func(ctx context.Context, payload json.RawMessage) error {
+		_, err := retentionSvc.Run(ctx, false)
+		return err
+	})
+	if cfg.RetentionEnabled {
+		jobQueue.Every(cfg.RetentionInterval, jobs.TypeRetention, nil)
+	}
+
+	// Rate limits on login and public registration
+	var rateLimits ratelimit.Store
+	if cfg.RateLimitEnabled {
+		rateLimits, err = ratelimit.NewStore(cfg)
+		if err != nil {
+			logger.Log.Fatalf("Rate limit store error: %v", err)
+		}
+	}
+
 	// Initialize handlers
-	handler := handlers.NewHandler(authSvc, eventSvc, participantSvc, verificationSvc, cfg)
+	handler := handlers.NewHandler(authSvc, eventSvc, participantSvc, verificationSvc, kioskSvc, drawSvc, sessionSvc, speakerSvc, sponsorSvc, widgetSvc, waiverSvc, seatingSvc, mealSvc, shiftSvc, liveStatsSvc, onlineSvc, notificationSvc, retentionSvc, apiKeySvc, auditSvc, deviceSvc, jobQueue, repo.IdempotencyRepo, rateLimits, graph.NewServer(repo, eventSvc), backupSvc, jwtKeys, cfg)
 
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
 		AppName:      "Event Management API",
 		ErrorHandler: handlers.ErrorHandler,
+		ProxyHeader:  cfg.ProxyHeader,
+		// The proxy header is only read on requests from these proxies
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.TrustedProxyList(),
 	})
 
 	// Global middlewares
 	app.Use(recover.New())
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept,Authorization",
+	app.Use(middleware.RequestLogger())
+	app.Use(compress.New(compress.Config{
+		Level: compress.LevelBestSpeed,
+		// Server-sent events must reach the client unbuffered
+		Next: func(c *fiber.Ctx) bool {
+			return strings.HasSuffix(c.Path(), "/stream")
+		},
 	}))
+	var widgetOrigin func(string) (bool, error)
+	if cfg.CORSWidgetOrigins {
+		widgetOrigin = repo.EventRepo.HasWidgetOrigin
+	}
+	app.Use(middleware.CORS(cfg, widgetOrigin))
 
 	// Create upload directories
 	if err := os.MkdirAll(cfg.QRDir, 0755); err != nil {
-		log.Fatalf("Failed to create QR directory: %v", err)
+		logger.Log.Fatalf("Failed to create QR directory: %v", err)
 	}
 	if err := os.MkdirAll(cfg.LogoDir, 0755); err != nil {
-		log.Fatalf("Failed to create logo directory: %v", err)
+		logger.Log.Fatalf("Failed to create logo directory: %v", err)
+	}
+	if err := os.MkdirAll(cfg.PhotoDir, 0755); err != nil {
+		logger.Log.Fatalf("Failed to create photo directory: %v", err)
+	}
+	if err := os.MkdirAll(cfg.ImageDir, 0755); err != nil {
+		logger.Log.Fatalf("Failed to create image directory: %v", err)
+	}
+	if err := os.MkdirAll(cfg.SignatureDir, 0700); err != nil {
+		logger.Log.Fatalf("Failed to create signature directory: %v", err)
+	}
+	if err := os.MkdirAll(cfg.ImportDir, 0700); err != nil {
+		logger.Log.Fatalf("Failed to create import directory: %v", err)
 	}
 
 	// Static file serving
-	app.Static("/qrcodes", cfg.QRDir)
-	app.Static("/logos", cfg.LogoDir)
+	// QR code files are named by UUID and never change, so they can be cached
+	// for a long time. Last-Modified and byte ranges are handled by fasthttp.
+	app.Static("/qrcodes", cfg.QRDir, fiber.Static{
+		Compress:  true,
+		ByteRange: true,
+		MaxAge:    int((30 * 24 * time.Hour).Seconds()),
+	})
+	app.Static("/logos", cfg.LogoDir, fiber.Static{
+		Compress:  true,
+		ByteRange: true,
+		MaxAge:    int(cfg.StaticCacheMaxAge.Seconds()),
+	})
+	app.Static("/photos", cfg.PhotoDir, fiber.Static{
+		Compress:  true,
+		ByteRange: true,
+		MaxAge:    int(cfg.StaticCacheMaxAge.Seconds()),
+	})
+	app.Static("/images", cfg.ImageDir, fiber.Static{
+		Compress:  true,
+		ByteRange: true,
+		MaxAge:    int(cfg.StaticCacheMaxAge.Seconds()),
+	})
 
-	// Register routes
-	api := app.Group("/api/v1")
-	handler.RegisterRoutes(api)
+	// Public keys of the JWT signing keys, for services verifying our tokens
+	handler.RegisterWellKnownRoutes(app)
 
-	// Start server
-	go func() {
-		addr := fmt.Sprintf(":%s", cfg.Port)
-		log.Printf("🚀 Server starting on %s", addr)
-		if err := app.Listen(addr); err != nil {
-			log.Fatalf("Server error: %v", err)
-		}
-	}()
+	// API documentation
+	if cfg.DocsEnabled {
+		handler.RegisterDocsRoutes(app)
+	}
+
+	// Register routes. Both versions share handlers and services; responses
+	// are shaped per version by the handlers' DTO presenters.
+	apiV1 := app.Group("/api/v1", middleware.APIVersion(handlers.APIVersionV1), middleware.Deprecated(cfg.APIV1Sunset, "/api/v2"))
+	handler.RegisterRoutes(apiV1)
+
+	apiV2 := app.Group("/api/v2", middleware.APIVersion(handlers.APIVersionV2))
+	handler.RegisterRoutes(apiV2)
+
+	// Components start in order and stop in reverse: the HTTP server stops
+	// accepting requests first, then workers drain, then the database closes.
+	lc := lifecycle.New(cfg.ShutdownTimeout)
+	lc.Append(lifecycle.Hook{
+		Name: "database",
+		OnStop: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.Close()
+		},
+	})
+	lc.Append(lifecycle.Hook{
+		Name: "job queue",
+		OnStart: func(ctx context.Context) error {
+			jobQueue.Start(ctx)
+			return nil
+		},
+		OnStop: jobQueue.Stop,
+	})
+	lc.Append(lifecycle.Hook{
+		Name: "http server",
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				addr := fmt.Sprintf(":%s", cfg.Port)
+				logger.Log.Infof("Server starting on %s", addr)
+				if err := app.Listen(addr); err != nil {
+					logger.Log.Fatalf("Server error: %v", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: app.ShutdownWithContext,
+	})
+
+	if err := lc.Start(); err != nil {
+		logger.Log.Fatalf("Startup error: %v", err)
+	}
 
 	// Graceful shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-	log.Println("Shutting down server...")
+	sig := lc.Wait(syscall.SIGINT, syscall.SIGTERM)
+	logger.Log.WithField("signal", sig.String()).Info("Shutting down server...")
 
-	if err := app.Shutdown(); err != nil {
-		log.Fatalf("Server shutdown error: %v", err)
+	if err := lc.Shutdown(); err != nil {
+		logger.Log.Fatalf("Shutdown error: %v", err)
 	}
-	log.Println("Server stopped gracefully")
-}
\ No newline at end of file
+	logger.Log.Info("Server stopped gracefully")
+}
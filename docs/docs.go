@@ -0,0 +1,8 @@
+// Package docs embeds the OpenAPI spec generated by swag from the handler
+// annotations. Regenerate with `go generate ./cmd/server` after changing them.
+package docs
+
+import _ "embed"
+
+//go:embed swagger.json
+var SwaggerJSON []byte
@@ -0,0 +1,382 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	archivePrefix = "backup-"
+	archiveSuffix = ".tar.gz"
+	dumpEntry     = "db.dump"
+	uploadsEntry  = "uploads"
+)
+
+func isArchiveName(name string) bool {
+	return strings.HasPrefix(name, archivePrefix) && strings.HasSuffix(name, archiveSuffix)
+}
+
+// Status describes the most recent backup run
+type Status struct {
+	Running    bool       `json:"running"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	LastBackup string     `json:"last_backup,omitempty"`
+	LastError  string     `json:"last_error,omitempty"`
+	DurationMs int64      `json:"duration_ms,omitempty"`
+}
+
+// Service creates archives holding a pg_dump of the database and the upload
+// directories, stores them and prunes old ones
+type Service struct {
+	store Store
+	cfg   *config.Config
+
+	mu     sync.Mutex
+	status Status
+}
+
+func NewService(store Store, cfg *config.Config) *Service {
+	return &Service{store: store, cfg: cfg}
+}
+
+// NewStore returns the store selected by BACKUP_STORE
+func NewStore(cfg *config.Config) Store {
+	if cfg.BackupStore == "s3" {
+		return &S3Store{
+			Endpoint:  cfg.BackupS3Endpoint,
+			Region:    cfg.BackupS3Region,
+			Bucket:    cfg.BackupS3Bucket,
+			Prefix:    cfg.BackupS3Prefix,
+			AccessKey: cfg.BackupS3AccessKey,
+			SecretKey: cfg.BackupS3SecretKey,
+		}
+	}
+	return &LocalStore{Dir: cfg.BackupDir}
+}
+
+// Status returns the state of the latest run
+func (s *Service) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// List returns the stored archives, newest first
+func (s *Service) List(ctx context.Context) ([]Object, error) {
+	return s.store.List(ctx)
+}
+
+// Run creates and uploads a new archive, then applies the retention policy.
+// Returns the archive name.
+func (s *Service) Run(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	if s.status.Running {
+		s.mu.Unlock()
+		return "", errors.New("a backup is already running")
+	}
+	s.status.Running = true
+	s.mu.Unlock()
+
+	start := time.Now()
+	name, err := s.run(ctx, start)
+
+	s.mu.Lock()
+	s.status = Status{LastRunAt: &start, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		s.status.LastError = err.Error()
+	} else {
+		s.status.LastBackup = name
+	}
+	s.mu.Unlock()
+
+	return name, err
+}
+
+func (s *Service) run(ctx context.Context, start time.Time) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "backup-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dumpPath := filepath.Join(tmpDir, dumpEntry)
+	if err := s.dumpDatabase(ctx, dumpPath); err != nil {
+		return "", err
+	}
+
+	name := archivePrefix + start.UTC().Format("20060102T150405Z") + archiveSuffix
+	archivePath := filepath.Join(tmpDir, name)
+	if err := s.writeArchive(archivePath, dumpPath); err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if err := s.store.Put(ctx, name, f, info.Size()); err != nil {
+		return "", fmt.Errorf("failed to upload backup: %w", err)
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"backup": name,
+		"bytes":  info.Size(),
+	}).Info("backup completed")
+
+	if err := s.prune(ctx); err != nil {
+		logger.Log.WithError(err).Warn("failed to apply backup retention")
+	}
+
+	return name, nil
+}
+
+func (s *Service) dumpDatabase(ctx context.Context, path string) error {
+	cmd := exec.CommandContext(ctx, s.cfg.BackupPGDumpPath,
+		"--format=custom",
+		"--no-owner",
+		"--file", path,
+	)
+	cmd.Env = append(os.Environ(), pgEnv(s.cfg)...)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("pg_dump failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// pgEnv passes connection settings to pg_dump/pg_restore without exposing
+// the password on the command line
+func pgEnv(cfg *config.Config) []string {
+	return []string{
+		"PGHOST=" + cfg.DBHost,
+		"PGPORT=" + cfg.DBPort,
+		"PGUSER=" + cfg.DBUser,
+		"PGPASSWORD=" + cfg.DBPass,
+		"PGDATABASE=" + cfg.DBName,
+		"PGSSLMODE=" + cfg.DBSSLMode,
+	}
+}
+
+func (s *Service) writeArchive(archivePath, dumpPath string) error {
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	if err := addFile(tw, dumpPath, dumpEntry); err != nil {
+		return err
+	}
+	for _, dir := range s.uploadDirs() {
+		if err := addDir(tw, dir.path, uploadsEntry+"/"+dir.name); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+type uploadDir struct {
+	name string
+	path string
+}
+
+func (s *Service) uploadDirs() []uploadDir {
+	return []uploadDir{
+		{name: "qrcodes", path: s.cfg.QRDir},
+		{name: "logos", path: s.cfg.LogoDir},
+		{name: "signatures", path: s.cfg.SignatureDir},
+	}
+}
+
+func addFile(tw *tar.Writer, path, entry string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = entry
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+func addDir(tw *tar.Writer, dir, prefix string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		return addFile(tw, path, prefix+"/"+filepath.ToSlash(rel))
+	})
+}
+
+// prune deletes archives beyond the newest BackupRetentionCount
+func (s *Service) prune(ctx context.Context) error {
+	if s.cfg.BackupRetentionCount <= 0 {
+		return nil
+	}
+
+	objects, err := s.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := s.cfg.BackupRetentionCount; i < len(objects); i++ {
+		if err := s.store.Delete(ctx, objects[i].Name); err != nil {
+			return err
+		}
+		logger.Log.WithField("backup", objects[i].Name).Info("pruned old backup")
+	}
+	return nil
+}
+
+// Restore downloads an archive, restores the database with pg_restore and,
+// unless skipUploads is set, extracts the upload directories over the
+// configured ones. Existing database objects are dropped first.
+func (s *Service) Restore(ctx context.Context, name string, skipUploads bool) error {
+	if !isArchiveName(name) {
+		return fmt.Errorf("invalid backup name %q", name)
+	}
+
+	rc, err := s.store.Get(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to download backup: %w", err)
+	}
+	defer rc.Close()
+
+	tmpDir, err := os.MkdirTemp("", "restore-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := s.extract(rc, tmpDir, skipUploads); err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, s.cfg.BackupPGRestorePath,
+		"--clean",
+		"--if-exists",
+		"--no-owner",
+		"--dbname", s.cfg.DBName,
+		filepath.Join(tmpDir, dumpEntry),
+	)
+	cmd.Env = append(os.Environ(), pgEnv(s.cfg)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("pg_restore failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// extract writes the dump into tmpDir and upload files into their
+// configured directories
+func (s *Service) extract(r io.Reader, tmpDir string, skipUploads bool) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("invalid backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	targets := make(map[string]string)
+	for _, dir := range s.uploadDirs() {
+		targets[dir.name] = dir.path
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid backup archive: %w", err)
+		}
+
+		var dest string
+		switch {
+		case hdr.Name == dumpEntry:
+			dest = filepath.Join(tmpDir, dumpEntry)
+		case strings.HasPrefix(hdr.Name, uploadsEntry+"/") && !skipUploads:
+			parts := strings.SplitN(strings.TrimPrefix(hdr.Name, uploadsEntry+"/"), "/", 2)
+			base, ok := targets[parts[0]]
+			if !ok || len(parts) != 2 {
+				continue
+			}
+			// Reject entries that would escape the target directory
+			rel := filepath.Clean("/" + parts[1])
+			dest = filepath.Join(base, rel)
+		default:
+			continue
+		}
+
+		if err := writeEntry(tr, dest); err != nil {
+			return err
+		}
+	}
+}
+
+func writeEntry(r io.Reader, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
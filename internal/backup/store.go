@@ -0,0 +1,94 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Object is a stored backup archive
+type Object struct {
+	Name         string    `json:"name"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// Store persists backup archives
+type Store interface {
+	Put(ctx context.Context, name string, r io.ReadSeeker, size int64) error
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	List(ctx context.Context) ([]Object, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// LocalStore keeps archives in a directory, e.g. a mounted volume
+type LocalStore struct {
+	Dir string
+}
+
+func (s *LocalStore) Put(ctx context.Context, name string, r io.ReadSeeker, size int64) error {
+	if err := os.MkdirAll(s.Dir, 0750); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	// Write to a temporary name first so a partial file is never listed
+	tmp := filepath.Join(s.Dir, "."+name+".tmp")
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(s.Dir, name))
+}
+
+func (s *LocalStore) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.Dir, filepath.Base(name)))
+}
+
+func (s *LocalStore) List(ctx context.Context) ([]Object, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	var objects []Object
+	for _, entry := range entries {
+		if entry.IsDir() || !isArchiveName(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		objects = append(objects, Object{Name: entry.Name(), Size: info.Size(), LastModified: info.ModTime()})
+	}
+
+	sortNewestFirst(objects)
+	return objects, nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, name string) error {
+	return os.Remove(filepath.Join(s.Dir, filepath.Base(name)))
+}
+
+func sortNewestFirst(objects []Object) {
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Name > objects[j].Name
+	})
+}
@@ -1,56 +1,676 @@
 package config
 
 import (
-	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/fieldcrypt"
+
+	"github.com/sirupsen/logrus"
 )
 
 type Config struct {
-	DBHost        string
-	DBPort        string
-	DBUser        string
-	DBPass        string
-	DBName        string
-	DBSSLMode     string
+	DBDriver  string // postgres|mysql|sqlite
+	DBPath    string // SQLite database file
+	DBHost    string
+	DBPort    string
+	DBUser    string
+	DBPass    string
+	DBName    string
+	DBSSLMode string
+
+	DBMaxOpenConns       int
+	DBMaxIdleConns       int
+	DBConnMaxLifetime    time.Duration
+	DBStatementTimeout   time.Duration // 0 disables the server-side timeout
+	DBSlowQueryThreshold time.Duration
+
 	JWTSecret     string
+	TOTPIssuer    string // shown next to the account in authenticator apps
 	Port          string
 	Env           string
 	QRDir         string
 	LogoDir       string
+	PhotoDir      string
+	ImageDir      string
+	SignatureDir  string // not served publicly
+	ImportDir     string // uploaded import files waiting for a worker; not served publicly
 	MaxUploadSize int64
 	LogLevel      string
+
+	// Signs participant QR credentials and online join links; kept apart
+	// from JWTSecret so rotating one does not invalidate the other
+	CredentialSigningKey string
+
+	JWTAlgorithm      string // HS256|RS256|EdDSA
+	JWTPrivateKeyFile string // PEM signing key for RS256 and EdDSA
+	// Comma separated PEM public key files of previous signing keys, still
+	// accepted while the tokens they signed are valid
+	JWTPreviousPublicKeys string
+	JWTPreviousSecret     string // previous JWT_SECRET, accepted during rotation
+
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration // lifetime of remember-me logins
+
+	// Rules for new passwords; existing passwords keep working
+	PasswordMinLength        int
+	PasswordRequireMixedCase bool
+	PasswordRequireDigit     bool
+	PasswordRequireSymbol    bool
+
+	// Hashing of new passwords; hashes made with other settings are replaced
+	// at the next login
+	PasswordHash      string // bcrypt|argon2id
+	BcryptCost        int
+	Argon2Memory      int64 // bytes
+	Argon2Iterations  int
+	Argon2Parallelism int
+
+	JobWorkers      int
+	JobPollInterval time.Duration
+
+	ImportBatchSize int
+	ImportQRWorkers int // 0 uses one worker per CPU
+
+	IdempotencyTTL time.Duration
+
+	// Per client IP token buckets on login and public registration
+	RateLimitEnabled           bool
+	RateLimitAuthPerMinute     int
+	RateLimitAuthBurst         int
+	RateLimitRegisterPerMinute int
+	RateLimitRegisterBurst     int
+	RateLimitRedisURL          string // empty keeps limits in memory, per instance
+	RateLimitRedisTimeout      time.Duration
+
+	// Tickets staff may resend to one participant per hour
+	RateLimitResendTicketPerHour int
+
+	// Limits of public registrations beyond the per IP buckets
+	RateLimitRegisterEmailPerHour int           // registrations one email address can submit per hour
+	RegisterMinInterval           time.Duration // shortest time between registrations from one IP; 0 disables
+	// Comma separated domains of throwaway email services refused on public
+	// registrations, subdomains included
+	DisposableEmailDomains string
+
+	// Header holding the client IP when running behind a reverse proxy, e.g.
+	// X-Forwarded-For; empty uses the connection address
+	ProxyHeader string
+	// Comma separated IPs or CIDR ranges of the proxies allowed to set
+	// ProxyHeader; it is ignored on requests from anywhere else
+	TrustedProxies string
+
+	APIV1Sunset string // HTTP date announced in the Sunset header of v1 responses
+
+	DocsEnabled bool
+
+	ShutdownTimeout time.Duration // drain deadline for in-flight requests and jobs
+
+	CORSAllowOrigins     string // comma separated; "*" allows any origin
+	CORSAllowMethods     string
+	CORSAllowHeaders     string
+	CORSExposeHeaders    string
+	CORSAllowCredentials bool
+	CORSMaxAge           time.Duration
+	CORSWidgetOrigins    bool // also allow origins registered on active events
+
+	PIIEncryptionKeys string // "id:base64key" pairs; empty stores PII in plaintext
+	PIIPrimaryKey     string // key ID used to encrypt new values
+	PIIHashKey        string // base64 key for deterministic email lookup hashes
+
+	BackupEnabled        bool
+	BackupInterval       time.Duration
+	BackupRetentionCount int    // newest archives kept; 0 keeps everything
+	BackupStore          string // local|s3
+	BackupDir            string
+	BackupS3Endpoint     string
+	BackupS3Region       string
+	BackupS3Bucket       string
+	BackupS3Prefix       string
+	BackupS3AccessKey    string
+	BackupS3SecretKey    string
+	BackupPGDumpPath     string
+	BackupPGRestorePath  string
+
+	RetentionEnabled  bool // run retention rules on a schedule
+	RetentionInterval time.Duration
+
+	ClamAVAddr    string // clamd TCP address; empty disables virus scanning
+	ClamAVTimeout time.Duration
+
+	// CAPTCHA checks of public registrations. Events opt in unless
+	// CaptchaRequired is set.
+	CaptchaProvider string // recaptcha|hcaptcha; empty disables CAPTCHA checks
+	CaptchaSecret   string
+	CaptchaRequired bool // check every public registration
+	CaptchaTimeout  time.Duration
+
+	PublicCacheMaxAge time.Duration // Cache-Control max-age of public event responses
+	StaticCacheMaxAge time.Duration // Cache-Control max-age of uploaded logos
+
+	KioskRefreshInterval time.Duration // how often kiosk streams check for new check-ins
+	KioskRecentLimit     int
+
+	// How long public live stats are served from memory before they are
+	// recomputed; also their Cache-Control max-age
+	LiveStatsCacheTTL time.Duration
+
+	// Payment page registrations from the widget are handed off to; supports
+	// {participant_id}, {event_slug} and {amount} placeholders
+	WidgetPaymentURL string
+
+	// How long before and after their shift staff may still scan
+	ShiftGracePeriod time.Duration
+
+	// Base URL of the API as reached by participants, used in emailed links
+	PublicBaseURL string
+
+	SMTPHost     string // empty logs emails instead of sending them
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	// How tickets are resent: email, or whatsapp to participants who gave a
+	// phone number
+	TicketChannel string
+	// WhatsApp Cloud API URL up to the sender's phone number ID; empty logs
+	// messages instead of sending them
+	WhatsAppAPIURL             string
+	WhatsAppToken              string
+	WhatsAppTimeout            time.Duration
+	WhatsAppDefaultCountryCode string // replaces the leading 0 of local numbers
+
+	OnlineLinkLeadTime  time.Duration // how long before an online event join links are emailed
+	OnlineWebhookSecret string        // HMAC key of attendance report webhooks; empty disables them
 }
 
+// NewConfigFromEnv loads the configuration from the environment. When
+// CONFIG_FILE points to a YAML file its values override the environment.
+// All invalid values are reported together.
 func NewConfigFromEnv() (*Config, error) {
-	maxUploadSize, _ := strconv.ParseInt(getenv("MAX_UPLOAD_SIZE", "10485760"), 10, 64)
+	var file map[string]string
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		values, err := readConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		file = values
+	}
 
-	cfg := &Config{
-		DBHost:        getenv("DB_HOST", "localhost"),
-		DBPort:        getenv("DB_PORT", "5432"),
-		DBUser:        getenv("DB_USER", "postgres"),
-		DBPass:        getenv("DB_PASSWORD", "postgres"),
-		DBName:        getenv("DB_NAME", "eventdb"),
-		DBSSLMode:     getenv("DB_SSLMODE", "disable"),
-		JWTSecret:     getenv("JWT_SECRET", ""),
-		Port:          getenv("PORT", "3000"),
-		Env:           getenv("ENV", "development"),
-		QRDir:         getenv("QR_DIR", "./uploads/qrcodes"),
-		LogoDir:       getenv("LOGO_DIR", "./uploads/logos"),
-		MaxUploadSize: maxUploadSize,
-		LogLevel:      getenv("LOG_LEVEL", "info"),
+	l := newLoader(file, os.Getenv)
+	cfg := load(l)
+	if len(l.errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n  %s", strings.Join(l.errs, "\n  "))
 	}
 
-	if cfg.JWTSecret == "" {
-		return nil, errors.New("JWT_SECRET is required")
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
 	return cfg, nil
 }
 
-func getenv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+func load(l *loader) *Config {
+	env := l.string("ENV", "development", "Runtime environment: development, staging, production or test")
+
+	return &Config{
+		DBDriver:  l.string("DB_DRIVER", "postgres", "Database driver: postgres, mysql (MySQL 8 / MariaDB), or sqlite for local development and tests"),
+		DBPath:    l.string("DB_PATH", "./data/dev.db", "SQLite database file, :memory: for a throwaway database"),
+		DBHost:    l.string("DB_HOST", "localhost", "Database host"),
+		DBPort:    l.string("DB_PORT", "5432", "Database port (3306 for mysql)"),
+		DBUser:    l.string("DB_USER", "postgres", "Database user"),
+		DBPass:    l.string("DB_PASSWORD", "postgres", "Database password"),
+		DBName:    l.string("DB_NAME", "eventdb", "Database name"),
+		DBSSLMode: l.string("DB_SSLMODE", "disable", "PostgreSQL sslmode (disable, require, verify-ca, verify-full)"),
+
+		DBMaxOpenConns:       l.int("DB_MAX_OPEN_CONNS", 25, "Maximum open database connections"),
+		DBMaxIdleConns:       l.int("DB_MAX_IDLE_CONNS", 5, "Maximum idle database connections"),
+		DBConnMaxLifetime:    l.duration("DB_CONN_MAX_LIFETIME", "5m", "Maximum lifetime of a database connection"),
+		DBStatementTimeout:   l.duration("DB_STATEMENT_TIMEOUT", "30s", "Server-side statement timeout, 0 to disable"),
+		DBSlowQueryThreshold: l.duration("DB_SLOW_QUERY_THRESHOLD", "200ms", "Queries slower than this are logged"),
+
+		JWTSecret:     l.string("JWT_SECRET", "", "Secret used to sign JWTs (required with HS256, at least 32 characters in production)"),
+		TOTPIssuer:    l.string("TOTP_ISSUER", "Event Management", "Issuer name shown in authenticator apps for two-factor authentication"),
+		Port:          l.string("PORT", "3000", "HTTP listen port"),
+		Env:           env,
+		QRDir:         l.string("QR_DIR", "./uploads/qrcodes", "Directory for generated QR codes"),
+		LogoDir:       l.string("LOGO_DIR", "./uploads/logos", "Directory for uploaded event logos"),
+		PhotoDir:      l.string("PHOTO_DIR", "./uploads/photos", "Directory for uploaded speaker photos"),
+		ImageDir:      l.string("IMAGE_DIR", "./uploads/images", "Directory for uploaded event gallery images"),
+		SignatureDir:  l.string("SIGNATURE_DIR", "./uploads/signatures", "Directory for drawn waiver signatures; keep it out of public static serving"),
+		ImportDir:     l.string("IMPORT_DIR", "./uploads/imports", "Directory for participant import files waiting to be processed; keep it out of public static serving"),
+		MaxUploadSize: l.size("MAX_UPLOAD_SIZE", "10MB", "Maximum upload size (bytes or KB/MB/GB)"),
+		LogLevel:      l.string("LOG_LEVEL", "info", "Log level: trace, debug, info, warn, error"),
+
+		CredentialSigningKey: l.string("CREDENTIAL_SIGNING_KEY", "", "Key used to sign QR credentials and online join links (required, at least 32 characters in production, must differ from JWT_SECRET)"),
+
+		JWTAlgorithm:          l.string("JWT_ALGORITHM", "HS256", "JWT signing algorithm: HS256 (JWT_SECRET), RS256 or EdDSA (JWT_PRIVATE_KEY_FILE)"),
+		JWTPrivateKeyFile:     l.string("JWT_PRIVATE_KEY_FILE", "", "PEM private key signing JWTs with RS256 or EdDSA; its public key is served at /.well-known/jwks.json"),
+		JWTPreviousPublicKeys: l.string("JWT_PREVIOUS_PUBLIC_KEYS", "", "Comma separated PEM public key files of previous signing keys, accepted until their tokens expire"),
+		JWTPreviousSecret:     l.string("JWT_PREVIOUS_SECRET", "", "Previous JWT_SECRET, accepted until its tokens expire"),
+
+		AccessTokenTTL:  l.duration("ACCESS_TOKEN_TTL", "24h", "Lifetime of access tokens issued at login"),
+		RefreshTokenTTL: l.duration("REFRESH_TOKEN_TTL", "720h", "Lifetime of refresh tokens issued to remember-me logins"),
+
+		PasswordMinLength:        l.int("PASSWORD_MIN_LENGTH", 8, "Minimum length of new passwords"),
+		PasswordRequireMixedCase: l.bool("PASSWORD_REQUIRE_MIXED_CASE", false, "New passwords must contain upper and lower case letters"),
+		PasswordRequireDigit:     l.bool("PASSWORD_REQUIRE_DIGIT", false, "New passwords must contain a digit"),
+		PasswordRequireSymbol:    l.bool("PASSWORD_REQUIRE_SYMBOL", false, "New passwords must contain a symbol"),
+
+		PasswordHash:      l.string("PASSWORD_HASH", "bcrypt", "Password hashing algorithm: bcrypt or argon2id; existing hashes are upgraded at login"),
+		BcryptCost:        l.int("BCRYPT_COST", 10, "bcrypt cost factor (4-31)"),
+		Argon2Memory:      l.size("ARGON2_MEMORY", "64MB", "Argon2id memory per hash"),
+		Argon2Iterations:  l.int("ARGON2_ITERATIONS", 3, "Argon2id passes over the memory"),
+		Argon2Parallelism: l.int("ARGON2_PARALLELISM", 2, "Argon2id threads per hash"),
+
+		JobWorkers:      l.int("JOB_WORKERS", 4, "Number of background job workers"),
+		JobPollInterval: l.duration("JOB_POLL_INTERVAL", "2s", "How often idle workers poll for jobs"),
+
+		ImportBatchSize: l.int("IMPORT_BATCH_SIZE", 500, "Participants inserted per statement during CSV imports"),
+		ImportQRWorkers: l.int("IMPORT_QR_WORKERS", 0, "Concurrent QR code writers during CSV imports, 0 for one per CPU"),
+
+		IdempotencyTTL: l.duration("IDEMPOTENCY_TTL", "24h", "How long Idempotency-Key responses are kept"),
+
+		RateLimitEnabled:           l.bool("RATE_LIMIT_ENABLED", true, "Rate limit login, user signup and participant registration per client IP"),
+		RateLimitAuthPerMinute:     l.int("RATE_LIMIT_AUTH_PER_MINUTE", 10, "Sustained login and signup requests per minute and IP"),
+		RateLimitAuthBurst:         l.int("RATE_LIMIT_AUTH_BURST", 5, "Login and signup requests an IP can make at once"),
+		RateLimitRegisterPerMinute: l.int("RATE_LIMIT_REGISTER_PER_MINUTE", 30, "Sustained participant registrations and ticket lookups per minute and IP"),
+		RateLimitRegisterBurst:     l.int("RATE_LIMIT_REGISTER_BURST", 10, "Participant registrations and ticket lookups an IP can make at once"),
+		RateLimitRedisURL:          l.string("RATE_LIMIT_REDIS_URL", "", "redis:// or rediss:// URL to share rate limits between instances, empty to keep them in memory"),
+		RateLimitRedisTimeout:      l.duration("RATE_LIMIT_REDIS_TIMEOUT", "500ms", "Timeout of rate limit lookups in Redis"),
+
+		RateLimitResendTicketPerHour: l.int("RATE_LIMIT_RESEND_TICKET_PER_HOUR", 3, "Tickets that can be resent to one participant per hour, by staff or ticket lookups"),
+
+		RateLimitRegisterEmailPerHour: l.int("RATE_LIMIT_REGISTER_EMAIL_PER_HOUR", 5, "Public registrations one email address can submit per hour, across events"),
+		RegisterMinInterval:           l.duration("REGISTER_MIN_INTERVAL", "0s", "Shortest time between two public registrations from one IP, e.g. 10s; 0 disables the check"),
+		DisposableEmailDomains: l.string("DISPOSABLE_EMAIL_DOMAINS", "mailinator.com,guerrillamail.com,sharklasers.com,10minutemail.com,temp-mail.org,yopmail.com,trashmail.com,maildrop.cc,getnada.com,dispostable.com",
+			"Comma separated email domains refused on public registrations, subdomains included; empty allows every domain"),
+
+		ProxyHeader:    l.string("PROXY_HEADER", "", "Header with the client IP set by a trusted reverse proxy, e.g. X-Forwarded-For; empty uses the connection address"),
+		TrustedProxies: l.string("TRUSTED_PROXIES", "", "Comma separated IPs or CIDR ranges of the reverse proxies allowed to set PROXY_HEADER (required with PROXY_HEADER)"),
+
+		APIV1Sunset: l.string("API_V1_SUNSET", "", "HTTP date announced in the Sunset header of v1 responses"),
+
+		// Docs are served by default everywhere except production
+		DocsEnabled: l.bool("DOCS_ENABLED", env != "production", "Serve Swagger UI at /docs"),
+
+		ShutdownTimeout: l.duration("SHUTDOWN_TIMEOUT", "30s", "How long shutdown waits for in-flight requests and jobs"),
+
+		// Any origin is allowed by default only outside staging and production
+		CORSAllowOrigins:     l.string("CORS_ALLOW_ORIGINS", corsDefaultOrigins(env), "Comma separated allowed origins, * for any"),
+		CORSAllowMethods:     l.string("CORS_ALLOW_METHODS", "GET,POST,PUT,PATCH,DELETE,OPTIONS", "Comma separated allowed methods"),
+		CORSAllowHeaders:     l.string("CORS_ALLOW_HEADERS", "Origin,Content-Type,Accept,Authorization,X-Request-ID,Idempotency-Key,If-None-Match,If-Modified-Since,X-Widget-Key", "Comma separated allowed request headers"),
+		CORSExposeHeaders:    l.string("CORS_EXPOSE_HEADERS", "X-Request-ID,API-Version,Deprecation,Sunset,Link,ETag,Last-Modified,Idempotent-Replayed", "Comma separated response headers readable by browsers"),
+		CORSAllowCredentials: l.bool("CORS_ALLOW_CREDENTIALS", false, "Allow cookies and credentials on cross-origin requests"),
+		CORSMaxAge:           l.duration("CORS_MAX_AGE", "10m", "How long browsers may cache preflight responses"),
+		CORSWidgetOrigins:    l.bool("CORS_WIDGET_ORIGINS", true, "Also allow origins registered as widget origins on active events"),
+
+		PIIEncryptionKeys: l.string("PII_ENCRYPTION_KEYS", "", "Comma separated id:base64 AES-256 keys encrypting participant PII, empty to disable"),
+		PIIPrimaryKey:     l.string("PII_PRIMARY_KEY", "", "ID of the key used to encrypt new PII values"),
+		PIIHashKey:        l.string("PII_HASH_KEY", "", "Base64 key (32+ bytes) for searchable email hashes"),
+
+		BackupEnabled:        l.bool("BACKUP_ENABLED", false, "Run scheduled database and upload backups"),
+		BackupInterval:       l.duration("BACKUP_INTERVAL", "24h", "Time between scheduled backups"),
+		BackupRetentionCount: l.int("BACKUP_RETENTION_COUNT", 7, "Number of newest backups kept, 0 keeps all"),
+		BackupStore:          l.string("BACKUP_STORE", "local", "Backup storage: local or s3"),
+		BackupDir:            l.string("BACKUP_DIR", "./backups", "Directory used by the local backup store"),
+		BackupS3Endpoint:     l.string("BACKUP_S3_ENDPOINT", "https://s3.amazonaws.com", "S3-compatible endpoint URL"),
+		BackupS3Region:       l.string("BACKUP_S3_REGION", "us-east-1", "S3 region"),
+		BackupS3Bucket:       l.string("BACKUP_S3_BUCKET", "", "S3 bucket for backups"),
+		BackupS3Prefix:       l.string("BACKUP_S3_PREFIX", "", "Key prefix of backups in the bucket, e.g. eventdb/"),
+		BackupS3AccessKey:    l.string("BACKUP_S3_ACCESS_KEY", "", "S3 access key ID"),
+		BackupS3SecretKey:    l.string("BACKUP_S3_SECRET_KEY", "", "S3 secret access key"),
+		BackupPGDumpPath:     l.string("BACKUP_PG_DUMP_PATH", "pg_dump", "Path to the pg_dump binary"),
+		BackupPGRestorePath:  l.string("BACKUP_PG_RESTORE_PATH", "pg_restore", "Path to the pg_restore binary"),
+
+		RetentionEnabled:  l.bool("RETENTION_ENABLED", false, "Apply data retention rules on a schedule"),
+		RetentionInterval: l.duration("RETENTION_INTERVAL", "24h", "Time between scheduled retention runs"),
+
+		ClamAVAddr:    l.string("CLAMAV_ADDR", "", "clamd TCP address (host:port) used to scan uploads, empty to disable"),
+		ClamAVTimeout: l.duration("CLAMAV_TIMEOUT", "30s", "Timeout of a single virus scan"),
+
+		CaptchaProvider: l.string("CAPTCHA_PROVIDER", "", "CAPTCHA provider checking public registrations: recaptcha or hcaptcha, empty to disable"),
+		CaptchaSecret:   l.string("CAPTCHA_SECRET", "", "Secret key of the CAPTCHA provider"),
+		CaptchaRequired: l.bool("CAPTCHA_REQUIRED", false, "Require a CAPTCHA on every public registration, not only on events that enable it"),
+		CaptchaTimeout:  l.duration("CAPTCHA_TIMEOUT", "5s", "Timeout of CAPTCHA verification requests"),
+
+		PublicCacheMaxAge: l.duration("PUBLIC_CACHE_MAX_AGE", "60s", "Cache-Control max-age of public event endpoints"),
+		StaticCacheMaxAge: l.duration("STATIC_CACHE_MAX_AGE", "1h", "Cache-Control max-age of uploaded logos"),
+
+		KioskRefreshInterval: l.duration("KIOSK_REFRESH_INTERVAL", "2s", "How often kiosk display streams check for new check-ins"),
+		KioskRecentLimit:     l.int("KIOSK_RECENT_LIMIT", 10, "Recent check-ins shown on kiosk displays"),
+
+		LiveStatsCacheTTL: l.duration("LIVE_STATS_CACHE_TTL", "30s", "How long public live stats are cached before they are recomputed"),
+
+		WidgetPaymentURL: l.string("WIDGET_PAYMENT_URL", "", "Payment page URL for paid widget registrations, with {participant_id}, {event_slug} and {amount} placeholders"),
+
+		ShiftGracePeriod: l.duration("SHIFT_GRACE_PERIOD", "15m", "How long before and after their shift staff may scan, on events with shifts"),
+
+		PublicBaseURL: l.string("PUBLIC_BASE_URL", "http://localhost:3000", "Base URL of the API as reached by participants, used in emailed links"),
+
+		SMTPHost:     l.string("SMTP_HOST", "", "SMTP relay host, empty to log emails instead of sending them"),
+		SMTPPort:     l.string("SMTP_PORT", "587", "SMTP relay port"),
+		SMTPUsername: l.string("SMTP_USERNAME", "", "SMTP username, empty to send without authentication"),
+		SMTPPassword: l.string("SMTP_PASSWORD", "", "SMTP password"),
+		SMTPFrom:     l.string("SMTP_FROM", "", "Sender address of outgoing emails"),
+
+		TicketChannel:              l.string("TICKET_CHANNEL", "email", "How tickets are resent to participants: email, or whatsapp for those who gave a phone number"),
+		WhatsAppAPIURL:             l.string("WHATSAPP_API_URL", "", "WhatsApp Cloud API URL up to the sender phone number ID, e.g. https://graph.facebook.com/v19.0/123456789; empty to log messages instead of sending them"),
+		WhatsAppToken:              l.string("WHATSAPP_TOKEN", "", "Access token of the WhatsApp Cloud API"),
+		WhatsAppTimeout:            l.duration("WHATSAPP_TIMEOUT", "10s", "Timeout of WhatsApp Cloud API requests"),
+		WhatsAppDefaultCountryCode: l.string("WHATSAPP_DEFAULT_COUNTRY_CODE", "62", "Country code replacing the leading 0 of local phone numbers messaged on WhatsApp"),
+
+		OnlineLinkLeadTime:  l.duration("ONLINE_LINK_LEAD_TIME", "24h", "How long before an online event participants are emailed their join links"),
+		OnlineWebhookSecret: l.string("ONLINE_WEBHOOK_SECRET", "", "HMAC key signing webinar attendance webhooks, empty to disable them"),
+	}
+}
+
+func corsDefaultOrigins(env string) string {
+	if env == "production" || env == "staging" {
+		return ""
+	}
+	return "*"
+}
+
+// Validate checks value ranges and cross-field constraints
+func (c *Config) Validate() error {
+	var errs []string
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, args...))
+	}
+
+	switch c.Env {
+	case "development", "staging", "production", "test":
+	default:
+		fail("ENV: %q must be one of development, staging, production, test", c.Env)
+	}
+
+	switch c.DBDriver {
+	case "postgres", "mysql":
+	case "sqlite":
+		if c.Env == "production" || c.Env == "staging" {
+			fail("DB_DRIVER: sqlite is only supported in development and test")
+		}
+	default:
+		fail("DB_DRIVER: %q must be postgres, mysql or sqlite", c.DBDriver)
+	}
+
+	switch c.JWTAlgorithm {
+	case "HS256":
+		if c.JWTSecret == "" {
+			fail("JWT_SECRET: is required")
+		}
+	case "RS256", "EdDSA":
+		if c.JWTPrivateKeyFile == "" {
+			fail("JWT_PRIVATE_KEY_FILE: is required with JWT_ALGORITHM=%s", c.JWTAlgorithm)
+		}
+	default:
+		fail("JWT_ALGORITHM: %q must be HS256, RS256 or EdDSA", c.JWTAlgorithm)
+	}
+	if c.Env == "production" && c.JWTSecret != "" && len(c.JWTSecret) < 32 {
+		fail("JWT_SECRET: must be at least 32 characters in production")
+	}
+	if c.AccessTokenTTL <= 0 {
+		fail("ACCESS_TOKEN_TTL: must be positive")
+	}
+	if c.RefreshTokenTTL <= c.AccessTokenTTL {
+		fail("REFRESH_TOKEN_TTL: must be longer than ACCESS_TOKEN_TTL")
+	}
+	if c.PasswordMinLength < 6 || c.PasswordMinLength > 72 {
+		fail("PASSWORD_MIN_LENGTH: must be between 6 and 72")
+	}
+	switch c.PasswordHash {
+	case "bcrypt":
+		if c.BcryptCost < 4 || c.BcryptCost > 31 {
+			fail("BCRYPT_COST: must be between 4 and 31")
+		}
+	case "argon2id":
+		if c.Argon2Memory < 8*1024 || c.Argon2Memory > 4<<30 {
+			fail("ARGON2_MEMORY: must be between 8KB and 4GB")
+		}
+		if c.Argon2Iterations < 1 {
+			fail("ARGON2_ITERATIONS: must be at least 1")
+		}
+		if c.Argon2Parallelism < 1 || c.Argon2Parallelism > 255 {
+			fail("ARGON2_PARALLELISM: must be between 1 and 255")
+		}
+	default:
+		fail("PASSWORD_HASH: %q must be bcrypt or argon2id", c.PasswordHash)
+	}
+	if c.CredentialSigningKey == "" {
+		fail("CREDENTIAL_SIGNING_KEY: is required")
+	} else if c.CredentialSigningKey == c.JWTSecret || c.CredentialSigningKey == c.JWTPreviousSecret {
+		fail("CREDENTIAL_SIGNING_KEY: must differ from JWT_SECRET and JWT_PREVIOUS_SECRET")
+	} else if c.Env == "production" && len(c.CredentialSigningKey) < 32 {
+		fail("CREDENTIAL_SIGNING_KEY: must be at least 32 characters in production")
+	}
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port <= 0 || port > 65535 {
+		fail("PORT: %q is not a valid port", c.Port)
+	}
+	if port, err := strconv.Atoi(c.DBPort); err != nil || port <= 0 || port > 65535 {
+		fail("DB_PORT: %q is not a valid port", c.DBPort)
+	}
+
+	switch c.DBSSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		fail("DB_SSLMODE: %q is not a valid sslmode", c.DBSSLMode)
+	}
+
+	if c.DBMaxOpenConns <= 0 {
+		fail("DB_MAX_OPEN_CONNS: must be greater than 0")
+	}
+	if c.DBMaxIdleConns < 0 || c.DBMaxIdleConns > c.DBMaxOpenConns {
+		fail("DB_MAX_IDLE_CONNS: must be between 0 and DB_MAX_OPEN_CONNS (%d)", c.DBMaxOpenConns)
+	}
+	if c.DBConnMaxLifetime < 0 || c.DBStatementTimeout < 0 || c.DBSlowQueryThreshold < 0 {
+		fail("DB_CONN_MAX_LIFETIME, DB_STATEMENT_TIMEOUT and DB_SLOW_QUERY_THRESHOLD must not be negative")
+	}
+
+	if _, err := logrus.ParseLevel(c.LogLevel); err != nil {
+		fail("LOG_LEVEL: %q is not a valid log level", c.LogLevel)
+	}
+	if c.MaxUploadSize <= 0 {
+		fail("MAX_UPLOAD_SIZE: must be greater than 0")
+	}
+	if c.JobWorkers <= 0 {
+		fail("JOB_WORKERS: must be greater than 0")
+	}
+	if c.ImportBatchSize <= 0 {
+		fail("IMPORT_BATCH_SIZE: must be greater than 0")
+	}
+	if c.ImportQRWorkers < 0 {
+		fail("IMPORT_QR_WORKERS: must not be negative")
+	}
+	if c.JobPollInterval <= 0 {
+		fail("JOB_POLL_INTERVAL: must be greater than 0")
+	}
+	if c.CORSAllowCredentials && strings.Contains(c.CORSAllowOrigins, "*") {
+		fail("CORS_ALLOW_CREDENTIALS: cannot be combined with CORS_ALLOW_ORIGINS=*; list the allowed origins explicitly")
+	}
+	for _, origin := range strings.Split(c.CORSAllowOrigins, ",") {
+		origin = strings.TrimSpace(origin)
+		if origin != "" && origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			fail("CORS_ALLOW_ORIGINS: %q must start with http:// or https://", origin)
+		}
+	}
+	if c.CORSMaxAge < 0 {
+		fail("CORS_MAX_AGE: must not be negative")
+	}
+	if c.PIIEncryptionKeys != "" {
+		if _, err := fieldcrypt.ParseKeyring(c.PIIEncryptionKeys, c.PIIPrimaryKey, c.PIIHashKey); err != nil {
+			fail("PII_ENCRYPTION_KEYS: %v", err)
+		}
+	}
+	switch c.BackupStore {
+	case "local":
+	case "s3":
+		if c.BackupS3Bucket == "" || c.BackupS3AccessKey == "" || c.BackupS3SecretKey == "" {
+			fail("BACKUP_STORE: s3 requires BACKUP_S3_BUCKET, BACKUP_S3_ACCESS_KEY and BACKUP_S3_SECRET_KEY")
+		}
+	default:
+		fail("BACKUP_STORE: %q must be local or s3", c.BackupStore)
+	}
+	if c.BackupEnabled && c.DBDriver != "postgres" {
+		fail("BACKUP_ENABLED: backups use pg_dump and require DB_DRIVER=postgres")
+	}
+	if c.BackupEnabled && c.BackupInterval <= 0 {
+		fail("BACKUP_INTERVAL: must be greater than 0")
+	}
+	if c.BackupRetentionCount < 0 {
+		fail("BACKUP_RETENTION_COUNT: must not be negative")
+	}
+	if c.RetentionEnabled && c.RetentionInterval <= 0 {
+		fail("RETENTION_INTERVAL: must be greater than 0")
+	}
+	if c.ClamAVTimeout <= 0 {
+		fail("CLAMAV_TIMEOUT: must be greater than 0")
+	}
+	switch c.CaptchaProvider {
+	case "":
+	case "recaptcha", "hcaptcha":
+		if c.CaptchaSecret == "" {
+			fail("CAPTCHA_SECRET: is required with CAPTCHA_PROVIDER")
+		}
+		if c.CaptchaTimeout <= 0 {
+			fail("CAPTCHA_TIMEOUT: must be greater than 0")
+		}
+	default:
+		fail("CAPTCHA_PROVIDER: %q must be recaptcha or hcaptcha", c.CaptchaProvider)
+	}
+	if c.TicketChannel != "email" && c.TicketChannel != "whatsapp" {
+		fail("TICKET_CHANNEL: %q must be email or whatsapp", c.TicketChannel)
+	}
+	if c.WhatsAppAPIURL != "" {
+		if c.WhatsAppToken == "" {
+			fail("WHATSAPP_TOKEN: is required with WHATSAPP_API_URL")
+		}
+		if c.WhatsAppTimeout <= 0 {
+			fail("WHATSAPP_TIMEOUT: must be greater than 0")
+		}
+	}
+	if c.KioskRefreshInterval <= 0 {
+		fail("KIOSK_REFRESH_INTERVAL: must be greater than 0")
+	}
+	if c.KioskRecentLimit <= 0 || c.KioskRecentLimit > 100 {
+		fail("KIOSK_RECENT_LIMIT: must be between 1 and 100")
+	}
+	if c.LiveStatsCacheTTL <= 0 {
+		fail("LIVE_STATS_CACHE_TTL: must be greater than 0")
+	}
+	if c.WidgetPaymentURL != "" && !strings.HasPrefix(c.WidgetPaymentURL, "https://") && !strings.HasPrefix(c.WidgetPaymentURL, "http://") {
+		fail("WIDGET_PAYMENT_URL: must start with http:// or https://")
+	}
+	if c.ShiftGracePeriod < 0 {
+		fail("SHIFT_GRACE_PERIOD: must not be negative")
+	}
+	if !strings.HasPrefix(c.PublicBaseURL, "https://") && !strings.HasPrefix(c.PublicBaseURL, "http://") {
+		fail("PUBLIC_BASE_URL: must start with http:// or https://")
+	}
+	if c.SMTPHost != "" && c.SMTPFrom == "" {
+		fail("SMTP_FROM: required when SMTP_HOST is set")
+	}
+	if c.OnlineLinkLeadTime < 0 {
+		fail("ONLINE_LINK_LEAD_TIME: must not be negative")
+	}
+	if c.PublicCacheMaxAge < 0 || c.StaticCacheMaxAge < 0 {
+		fail("PUBLIC_CACHE_MAX_AGE and STATIC_CACHE_MAX_AGE must not be negative")
+	}
+	if c.ShutdownTimeout <= 0 {
+		fail("SHUTDOWN_TIMEOUT: must be greater than 0")
+	}
+	if c.IdempotencyTTL <= 0 {
+		fail("IDEMPOTENCY_TTL: must be greater than 0")
+	}
+	if c.RateLimitEnabled {
+		if c.RateLimitAuthPerMinute <= 0 || c.RateLimitRegisterPerMinute <= 0 {
+			fail("RATE_LIMIT_AUTH_PER_MINUTE and RATE_LIMIT_REGISTER_PER_MINUTE: must be greater than 0")
+		}
+		if c.RateLimitAuthBurst <= 0 || c.RateLimitRegisterBurst <= 0 {
+			fail("RATE_LIMIT_AUTH_BURST and RATE_LIMIT_REGISTER_BURST: must be greater than 0")
+		}
+		if c.RateLimitRedisURL != "" && !strings.HasPrefix(c.RateLimitRedisURL, "redis://") && !strings.HasPrefix(c.RateLimitRedisURL, "rediss://") {
+			fail("RATE_LIMIT_REDIS_URL: must start with redis:// or rediss://")
+		}
+		if c.RateLimitRedisTimeout <= 0 {
+			fail("RATE_LIMIT_REDIS_TIMEOUT: must be greater than 0")
+		}
+		if c.RateLimitResendTicketPerHour <= 0 {
+			fail("RATE_LIMIT_RESEND_TICKET_PER_HOUR: must be greater than 0")
+		}
+		if c.RateLimitRegisterEmailPerHour <= 0 {
+			fail("RATE_LIMIT_REGISTER_EMAIL_PER_HOUR: must be greater than 0")
+		}
+	}
+	if c.RegisterMinInterval < 0 {
+		fail("REGISTER_MIN_INTERVAL: must not be negative")
+	}
+	if c.ProxyHeader != "" && len(c.TrustedProxyList()) == 0 {
+		fail("TRUSTED_PROXIES: is required with PROXY_HEADER")
+	}
+	for _, proxy := range c.TrustedProxyList() {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			fail("TRUSTED_PROXIES: %q is not an IP or CIDR range", proxy)
+		}
+	}
+	if c.APIV1Sunset != "" {
+		if _, err := http.ParseTime(c.APIV1Sunset); err != nil {
+			fail("API_V1_SUNSET: %q is not an HTTP date (e.g. Sat, 01 Mar 2025 00:00:00 GMT)", c.APIV1Sunset)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+// TrustedProxyList returns the entries of TrustedProxies
+func (c *Config) TrustedProxyList() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(c.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// DisposableEmailDomainList returns the entries of DisposableEmailDomains,
+// lowercased
+func (c *Config) DisposableEmailDomainList() []string {
+	var domains []string
+	for _, domain := range strings.Split(c.DisposableEmailDomains, ",") {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// WriteEnvExample writes a .env.example listing every setting with its
+// description and default value
+func WriteEnvExample(w io.Writer) error {
+	l := newLoader(nil, func(string) string { return "" })
+	load(l)
+
+	header := "# Generated by `go run ./scripts/envexample`. Do not edit by hand.\n" +
+		"# Set CONFIG_FILE to a YAML file (same keys) to override these values.\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+	for _, v := range l.vars {
+		if _, err := fmt.Fprintf(w, "\n# %s\n%s=%s\n", v.Description, v.Key, v.Default); err != nil {
+			return err
+		}
 	}
-	return defaultValue
+	return nil
 }
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// variable describes one configuration setting, recorded while loading so
+// the same definitions drive both parsing and the .env.example generator
+type variable struct {
+	Key         string
+	Default     string
+	Description string
+}
+
+// loader resolves settings from the YAML file first, then the environment,
+// then the default, collecting parse errors instead of stopping at the first
+type loader struct {
+	file map[string]string
+	env  func(string) string
+	vars []variable
+	errs []string
+}
+
+func newLoader(file map[string]string, env func(string) string) *loader {
+	return &loader{file: file, env: env}
+}
+
+func (l *loader) lookup(key, defaultValue, description string) string {
+	l.vars = append(l.vars, variable{Key: key, Default: defaultValue, Description: description})
+
+	if value, ok := l.file[key]; ok && value != "" {
+		return value
+	}
+	if value := l.env(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func (l *loader) fail(key, format string, args ...interface{}) {
+	l.errs = append(l.errs, fmt.Sprintf("%s: %s", key, fmt.Sprintf(format, args...)))
+}
+
+func (l *loader) string(key, defaultValue, description string) string {
+	return l.lookup(key, defaultValue, description)
+}
+
+func (l *loader) int(key string, defaultValue int, description string) int {
+	raw := l.lookup(key, strconv.Itoa(defaultValue), description)
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		l.fail(key, "%q is not a valid integer", raw)
+		return defaultValue
+	}
+	return value
+}
+
+func (l *loader) bool(key string, defaultValue bool, description string) bool {
+	raw := l.lookup(key, strconv.FormatBool(defaultValue), description)
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		l.fail(key, "%q is not a valid boolean (use true or false)", raw)
+		return defaultValue
+	}
+	return value
+}
+
+func (l *loader) duration(key, defaultValue, description string) time.Duration {
+	raw := l.lookup(key, defaultValue, description)
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		l.fail(key, "%q is not a valid duration (e.g. 30s, 5m, 24h)", raw)
+		value, _ = time.ParseDuration(defaultValue)
+	}
+	return value
+}
+
+func (l *loader) size(key, defaultValue, description string) int64 {
+	raw := l.lookup(key, defaultValue, description)
+	value, err := ParseSize(raw)
+	if err != nil {
+		l.fail(key, "%q is not a valid size (e.g. 10485760, 512KB, 10MB)", raw)
+		value, _ = ParseSize(defaultValue)
+	}
+	return value
+}
+
+// ParseSize parses a byte size such as "10485760", "512KB" or "10MB".
+// Units are binary (1KB = 1024 bytes).
+func ParseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		factor int64
+	}{
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(s, unit.suffix) {
+			multiplier = unit.factor
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			break
+		}
+	}
+
+	value, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return value * multiplier, nil
+}
+
+// readConfigFile loads a flat YAML file whose keys are the environment
+// variable names, in either case (db_host or DB_HOST)
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		if value == nil {
+			continue
+		}
+		if _, nested := value.(map[string]interface{}); nested {
+			return nil, fmt.Errorf("config file %s: %s must be a scalar value", path, key)
+		}
+		values[strings.ToUpper(key)] = fmt.Sprint(value)
+	}
+
+	return values, nil
+}
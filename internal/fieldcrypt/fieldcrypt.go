@@ -0,0 +1,277 @@
+// Package fieldcrypt encrypts individual database columns with envelope
+// encryption: every value gets a fresh data key, which is wrapped with the
+// current key-encryption key (KEK). Ciphertexts name the KEK that wrapped
+// them, so old keys keep decrypting after rotation.
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm/schema"
+)
+
+const prefix = "enc:v1:"
+
+// Keyring holds the key-encryption keys by ID, the ID used for new values and
+// the key for deterministic lookup hashes
+type Keyring struct {
+	keys    map[string][]byte
+	primary string
+	hashKey []byte
+}
+
+var (
+	mu      sync.RWMutex
+	current *Keyring
+)
+
+func init() {
+	schema.RegisterSerializer("encrypted", Serializer{})
+}
+
+// ParseKeyring builds a keyring from "id:base64key" pairs separated by
+// commas. Keys must decode to 32 bytes (AES-256).
+func ParseKeyring(keys, primary, hashKey string) (*Keyring, error) {
+	kr := &Keyring{keys: make(map[string][]byte), primary: primary}
+
+	for _, pair := range strings.Split(keys, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(pair, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("invalid key %q, expected id:base64key", pair)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("key %s must be 32 bytes encoded as base64", id)
+		}
+		kr.keys[id] = key
+	}
+
+	if len(kr.keys) == 0 {
+		return nil, errors.New("no encryption keys configured")
+	}
+	if _, ok := kr.keys[primary]; !ok {
+		return nil, fmt.Errorf("primary key %q is not among the configured keys", primary)
+	}
+
+	hk, err := base64.StdEncoding.DecodeString(hashKey)
+	if err != nil || len(hk) < 32 {
+		return nil, errors.New("hash key must be at least 32 bytes encoded as base64")
+	}
+	kr.hashKey = hk
+
+	return kr, nil
+}
+
+// Use installs kr as the keyring used by the serializer and Hash. Without a
+// keyring values are stored in plaintext.
+func Use(kr *Keyring) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = kr
+}
+
+func keyring() *Keyring {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Enabled reports whether a keyring is installed
+func Enabled() bool {
+	return keyring() != nil
+}
+
+// Encrypt seals plaintext with a fresh data key wrapped by the primary KEK
+func Encrypt(plaintext string) (string, error) {
+	kr := keyring()
+	if kr == nil || plaintext == "" {
+		return plaintext, nil
+	}
+
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return "", err
+	}
+
+	wrapped, err := seal(kr.keys[kr.primary], dek)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(dek, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + kr.primary + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt. Values without the encryption
+// prefix are returned unchanged so existing plaintext rows stay readable
+// until they are re-encrypted.
+func Decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, prefix) {
+		return value, nil
+	}
+
+	kr := keyring()
+	if kr == nil {
+		return "", errors.New("encrypted value found but no encryption keys are configured")
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	kek, ok := kr.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("unknown encryption key %q", parts[0])
+	}
+
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("malformed encrypted value")
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	dek, err := open(kek, wrapped)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dek, sealed)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether value is plaintext or wrapped by a key other
+// than the primary one
+func NeedsRotation(value string) bool {
+	kr := keyring()
+	if kr == nil || value == "" {
+		return false
+	}
+	return !strings.HasPrefix(value, prefix+kr.primary+":")
+}
+
+// Hash returns a deterministic keyed hash of the normalized value, used to
+// look up rows by an encrypted column. Without a keyring a plain SHA-256 is
+// used so lookups keep working in development.
+func Hash(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if kr := keyring(); kr != nil {
+		mac := hmac.New(sha256.New, kr.hashKey)
+		mac.Write([]byte(value))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("malformed encrypted value")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt value")
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Serializer is the GORM serializer registered as "encrypted". Tag string
+// fields with `gorm:"serializer:encrypted"` to encrypt them at rest.
+type Serializer struct{}
+
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var raw string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("unsupported type %T for encrypted field %s", dbValue, field.Name)
+	}
+
+	plaintext, err := Decrypt(raw)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s: %w", field.Name, err)
+	}
+	return field.Set(ctx, dst, plaintext)
+}
+
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	s, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("encrypted field %s must be a string", field.Name)
+	}
+	return Encrypt(s)
+}
+
+// Configure parses and installs the keyring. Empty keys leave encryption
+// disabled.
+func Configure(keys, primary, hashKey string) error {
+	if keys == "" {
+		return nil
+	}
+
+	kr, err := ParseKeyring(keys, primary, hashKey)
+	if err != nil {
+		return err
+	}
+	Use(kr)
+	return nil
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"sync"
+	"time"
+)
+
+// batchFunc loads values for many keys at once. Keys missing from the
+// returned map resolve to the zero value.
+type batchFunc[K comparable, V any] func(keys []K) (map[K]V, error)
+
+// DataLoader coalesces Load calls made within a short window into a single
+// batchFunc call and caches results for the lifetime of the loader (one
+// GraphQL request).
+type DataLoader[K comparable, V any] struct {
+	fetch    batchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[K]*loaderResult[V]
+	batch *loaderBatch[K, V]
+}
+
+type loaderResult[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type loaderBatch[K comparable, V any] struct {
+	keys    []K
+	results []*loaderResult[V]
+	closed  bool
+}
+
+func NewDataLoader[K comparable, V any](fetch batchFunc[K, V]) *DataLoader[K, V] {
+	return &DataLoader[K, V]{
+		fetch:    fetch,
+		wait:     2 * time.Millisecond,
+		maxBatch: 100,
+		cache:    make(map[K]*loaderResult[V]),
+	}
+}
+
+// Load returns the value for key, waiting for the batch it was added to
+func (l *DataLoader[K, V]) Load(key K) (V, error) {
+	l.mu.Lock()
+	if res, ok := l.cache[key]; ok {
+		l.mu.Unlock()
+		<-res.done
+		return res.value, res.err
+	}
+
+	res := &loaderResult[V]{done: make(chan struct{})}
+	l.cache[key] = res
+
+	if l.batch == nil {
+		l.batch = &loaderBatch[K, V]{}
+		go l.dispatchAfter(l.batch, l.wait)
+	}
+	b := l.batch
+	b.keys = append(b.keys, key)
+	b.results = append(b.results, res)
+
+	if len(b.keys) >= l.maxBatch {
+		l.batch = nil
+		l.mu.Unlock()
+		l.dispatch(b)
+	} else {
+		l.mu.Unlock()
+	}
+
+	<-res.done
+	return res.value, res.err
+}
+
+func (l *DataLoader[K, V]) dispatchAfter(b *loaderBatch[K, V], wait time.Duration) {
+	time.Sleep(wait)
+
+	l.mu.Lock()
+	if l.batch == b {
+		l.batch = nil
+	}
+	l.mu.Unlock()
+
+	l.dispatch(b)
+}
+
+func (l *DataLoader[K, V]) dispatch(b *loaderBatch[K, V]) {
+	l.mu.Lock()
+	if b.closed {
+		l.mu.Unlock()
+		return
+	}
+	b.closed = true
+	l.mu.Unlock()
+
+	values, err := l.fetch(b.keys)
+	for i, key := range b.keys {
+		res := b.results[i]
+		if err != nil {
+			res.err = err
+		} else {
+			res.value = values[key]
+		}
+		close(res.done)
+	}
+}
@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+)
+
+type loadersKey struct{}
+
+// Loaders holds the DataLoaders for one GraphQL request
+type Loaders struct {
+	DaysByEvent       *DataLoader[string, []models.EventDay]
+	ActionsByDay      *DataLoader[string, []models.EventAction]
+	ParticipantCount  *DataLoader[string, int64]
+	VerificationCount *DataLoader[string, int64]
+}
+
+func NewLoaders(repo *repositories.Repository) *Loaders {
+	return &Loaders{
+		DaysByEvent: NewDataLoader(func(eventIDs []string) (map[string][]models.EventDay, error) {
+			days, err := repo.EventRepo.GetEventDaysByEventIDs(eventIDs)
+			if err != nil {
+				return nil, err
+			}
+			grouped := make(map[string][]models.EventDay, len(eventIDs))
+			for _, day := range days {
+				key := day.EventID.String()
+				grouped[key] = append(grouped[key], day)
+			}
+			return grouped, nil
+		}),
+		ActionsByDay: NewDataLoader(func(dayIDs []string) (map[string][]models.EventAction, error) {
+			actions, err := repo.EventRepo.GetEventActionsByDayIDs(dayIDs)
+			if err != nil {
+				return nil, err
+			}
+			grouped := make(map[string][]models.EventAction, len(dayIDs))
+			for _, action := range actions {
+				key := action.EventDayID.String()
+				grouped[key] = append(grouped[key], action)
+			}
+			return grouped, nil
+		}),
+		ParticipantCount:  NewDataLoader(repo.ParticipantRepo.CountParticipantsByEventIDs),
+		VerificationCount: NewDataLoader(repo.ActionRepo.CountActionLogsByActionIDs),
+	}
+}
+
+// Middleware attaches a fresh set of loaders to every request so cached
+// results never leak between requests
+func Middleware(repo *repositories.Repository, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), loadersKey{}, NewLoaders(repo))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// For returns the loaders of the current request
+func For(ctx context.Context) *Loaders {
+	return ctx.Value(loadersKey{}).(*Loaders)
+}
@@ -0,0 +1,15 @@
+package graph
+
+//go:generate go run github.com/99designs/gqlgen@v0.17.45 generate --config ../../gqlgen.yml
+
+import (
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/services"
+)
+
+// Resolver is the root GraphQL resolver. Nested fields go through the
+// per-request DataLoaders (see For) instead of the repositories directly.
+type Resolver struct {
+	repo     *repositories.Repository
+	eventSvc *services.EventService
+}
@@ -0,0 +1,111 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.45
+
+import (
+	"context"
+
+	"event-management-backend/internal/graph/generated"
+	"event-management-backend/internal/graph/model"
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ParticipantCount is the resolver for the participantCount field.
+func (r *eventResolver) ParticipantCount(ctx context.Context, obj *models.Event) (int, error) {
+	count, err := For(ctx).ParticipantCount.Load(obj.ID.String())
+	return int(count), err
+}
+
+// Days is the resolver for the days field.
+func (r *eventResolver) Days(ctx context.Context, obj *models.Event) ([]*models.EventDay, error) {
+	days, err := For(ctx).DaysByEvent.Load(obj.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.EventDay, len(days))
+	for i := range days {
+		result[i] = &days[i]
+	}
+	return result, nil
+}
+
+// VerificationCount is the resolver for the verificationCount field.
+func (r *eventActionResolver) VerificationCount(ctx context.Context, obj *models.EventAction) (int, error) {
+	count, err := For(ctx).VerificationCount.Load(obj.ID.String())
+	return int(count), err
+}
+
+// Actions is the resolver for the actions field.
+func (r *eventDayResolver) Actions(ctx context.Context, obj *models.EventDay) ([]*models.EventAction, error) {
+	actions, err := For(ctx).ActionsByDay.Load(obj.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*models.EventAction, len(actions))
+	for i := range actions {
+		result[i] = &actions[i]
+	}
+	return result, nil
+}
+
+// Events is the resolver for the events field.
+func (r *queryResolver) Events(ctx context.Context, page *int, pageSize *int) (*model.EventPage, error) {
+	p, size := 1, 20
+	if page != nil {
+		p = *page
+	}
+	if pageSize != nil {
+		size = *pageSize
+	}
+
+	events, total, totalPages, err := r.eventSvc.ListEvents(p, size, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*models.Event, len(events))
+	for i := range events {
+		nodes[i] = &events[i]
+	}
+
+	return &model.EventPage{
+		Nodes:      nodes,
+		Total:      int(total),
+		Page:       p,
+		PageSize:   size,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Event is the resolver for the event field.
+func (r *queryResolver) Event(ctx context.Context, id uuid.UUID) (*models.Event, error) {
+	return r.eventSvc.GetEvent(id.String())
+}
+
+// EventBySlug is the resolver for the eventBySlug field.
+func (r *queryResolver) EventBySlug(ctx context.Context, slug string) (*models.Event, error) {
+	return r.eventSvc.GetEventBySlug(slug)
+}
+
+// Event returns generated.EventResolver implementation.
+func (r *Resolver) Event() generated.EventResolver { return &eventResolver{r} }
+
+// EventAction returns generated.EventActionResolver implementation.
+func (r *Resolver) EventAction() generated.EventActionResolver { return &eventActionResolver{r} }
+
+// EventDay returns generated.EventDayResolver implementation.
+func (r *Resolver) EventDay() generated.EventDayResolver { return &eventDayResolver{r} }
+
+// Query returns generated.QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
+
+type eventResolver struct{ *Resolver }
+type eventActionResolver struct{ *Resolver }
+type eventDayResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
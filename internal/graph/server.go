@@ -0,0 +1,20 @@
+package graph
+
+import (
+	"net/http"
+
+	"event-management-backend/internal/graph/generated"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/services"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+)
+
+// NewServer builds the GraphQL HTTP handler with per-request loaders
+func NewServer(repo *repositories.Repository, eventSvc *services.EventService) http.Handler {
+	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
+		Resolvers: &Resolver{repo: repo, eventSvc: eventSvc},
+	}))
+
+	return Middleware(repo, srv)
+}
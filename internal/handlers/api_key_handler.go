@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CreateAPIKeyRequest struct {
+	Name     string   `json:"name" validate:"required,max=100"`
+	Scopes   []string `json:"scopes" validate:"required,min=1,dive,oneof=registration verification"`
+	EventIDs []string `json:"event_ids" validate:"required,min=1,max=100,dive,uuid"`
+	// RFC3339; the key never expires when omitted
+	ExpiresAt string `json:"expires_at"`
+}
+
+// APIKeyMiddleware authenticates integrations by their X-API-Key header.
+// Requests carry no user; what they record is attributed to the key itself.
+func (h *Handler) APIKeyMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get("X-API-Key")
+		if key == "" {
+			return utils.Error(c, "API key required", fiber.StatusUnauthorized)
+		}
+
+		apiKey, err := h.apiKeySvc.Authenticate(key)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidAPIKey) {
+				return utils.Error(c, err.Error(), fiber.StatusUnauthorized)
+			}
+			return utils.Error(c, "Failed to check API key", fiber.StatusInternalServerError)
+		}
+
+		c.Locals("api_key", apiKey)
+		c.Locals("api_key_id", apiKey.ID.String())
+		return c.Next()
+	}
+}
+
+// APIKeyScopeMiddleware refuses keys that were not issued for a scope
+func (h *Handler) APIKeyScopeMiddleware(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := apiKeyFromContext(c)
+		if key == nil || !services.APIKeyHasScope(key, scope) {
+			return utils.Error(c, "API key is not allowed to call this endpoint", fiber.StatusForbidden)
+		}
+		return c.Next()
+	}
+}
+
+// apiKeyFromContext returns the API key of the request, or nil for requests
+// authenticated otherwise
+func apiKeyFromContext(c *fiber.Ctx) *models.APIKey {
+	key, _ := c.Locals("api_key").(*models.APIKey)
+	return key
+}
+
+// IntegrationRegister registers a participant on behalf of a partner site
+// @Summary Register participant (API key)
+// @Description Same as /register, for integrations authenticated by X-API-Key with the registration scope. The event must be one the key was issued for.
+// @Tags Integrations
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "API key"
+// @Param request body RegisterParticipantRequest true "Participant data"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /integrations/register [post]
+func (h *Handler) IntegrationRegister(c *fiber.Ctx) error {
+	var req RegisterParticipantRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	key := apiKeyFromContext(c)
+	allowed := false
+	for _, id := range services.APIKeyEventIDs(key) {
+		if id == req.EventID {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return utils.Error(c, "API key is not allowed to register for this event", fiber.StatusForbidden)
+	}
+
+	return h.registerParticipant(c, req)
+}
+
+// IntegrationVerify verifies a participant scan from a kiosk
+// @Summary Verify action (API key)
+// @Description Same as /verify, for integrations authenticated by X-API-Key with the verification scope. Only actions of the key's events can be verified.
+// @Tags Integrations
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "API key"
+// @Param request body VerifyActionRequest true "Scan"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /integrations/verify [post]
+func (h *Handler) IntegrationVerify(c *fiber.Ctx) error {
+	return h.VerifyAction(c)
+}
+
+// CreateAPIKey issues an API key for an external system
+// @Summary Create API key
+// @Description The key is only returned in this response.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateAPIKeyRequest true "Key"
+// @Success 201 {object} utils.Response{data=services.CreatedAPIKey}
+// @Failure 400 {object} utils.Response
+// @Router /admin/api-keys [post]
+func (h *Handler) CreateAPIKey(c *fiber.Ctx) error {
+	var req CreateAPIKeyRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	createReq := services.CreateAPIKeyRequest{
+		Name:     req.Name,
+		Scopes:   req.Scopes,
+		EventIDs: req.EventIDs,
+	}
+	if req.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			return utils.Error(c, "Invalid expires_at format", fiber.StatusBadRequest)
+		}
+		createReq.ExpiresAt = &expiresAt
+	}
+
+	created, err := h.apiKeySvc.Create(userID, createReq)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, created, "API key created successfully", fiber.StatusCreated)
+}
+
+// ListAPIKeys returns all API keys, without the keys themselves
+// @Summary List API keys
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/api-keys [get]
+func (h *Handler) ListAPIKeys(c *fiber.Ctx) error {
+	keys, err := h.apiKeySvc.List()
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve API keys", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, keys, "API keys retrieved successfully")
+}
+
+// RevokeAPIKey disables an API key immediately
+// @Summary Revoke API key
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/api-keys/{id} [delete]
+func (h *Handler) RevokeAPIKey(c *fiber.Ctx) error {
+	keyID := c.Params("id")
+	if _, err := uuid.Parse(keyID); err != nil {
+		return utils.Error(c, "Invalid API key ID", fiber.StatusBadRequest)
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	if err := h.apiKeySvc.Revoke(actorID, keyID); err != nil {
+		if errors.Is(err, services.ErrAPIKeyNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to revoke API key", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "API key revoked")
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"strconv"
+
+	"event-management-backend/internal/jobs"
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ApproveRegistrationsRequest struct {
+	ParticipantIDs []string `json:"participant_ids" validate:"required,min=1,max=500,dive,uuid"`
+}
+
+type RejectRegistrationsRequest struct {
+	ParticipantIDs []string `json:"participant_ids" validate:"required,min=1,max=500,dive,uuid"`
+	// Shown to the participant
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
+
+type RejectParticipantRequest struct {
+	// Shown to the participant
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
+
+// ListRegistrationsForReview returns the registrations of an event by
+// approval status
+// @Summary List registrations for review
+// @Tags Approvals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param approval_status query string false "pending (default), approved, rejected or pending_confirmation"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/registrations [get]
+func (h *Handler) ListRegistrationsForReview(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
+
+	participants, total, totalPages, err := h.participantSvc.ListRegistrationsForReview(eventID, c.Query("approval_status", "pending"), page, pageSize)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	meta := &utils.Meta{
+		Page:      page,
+		PageSize:  pageSize,
+		Total:     total,
+		TotalPage: totalPages,
+	}
+
+	return utils.SuccessWithMeta(c, present(c, participants), meta, "Registrations retrieved successfully")
+}
+
+// ApproveRegistrations approves registrations in bulk
+// @Summary Approve registrations
+// @Description Approves the given registrations and issues the QR codes of participants who signed every waiver. Approved participants are sent their ticket, or told they are approved while waivers remain to sign. IDs of other events are reported as not found.
+// @Tags Approvals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body ApproveRegistrationsRequest true "Participants to approve"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/registrations/approve [post]
+func (h *Handler) ApproveRegistrations(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	reviewerID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	var req ApproveRegistrationsRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	result, err := h.participantSvc.ApproveRegistrations(eventID, req.ParticipantIDs, reviewerID)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	h.sendReviewDecisions(c, result.Updated)
+	return utils.Success(c, result, "Registrations approved")
+}
+
+// RejectRegistrations rejects registrations in bulk
+// @Summary Reject registrations
+// @Description Rejects the given registrations with a reason. Rejected participants are emailed the reason and are refused at verification.
+// @Tags Approvals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body RejectRegistrationsRequest true "Participants to reject"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/registrations/reject [post]
+func (h *Handler) RejectRegistrations(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	reviewerID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	var req RejectRegistrationsRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	result, err := h.participantSvc.RejectRegistrations(eventID, req.ParticipantIDs, req.Reason, reviewerID)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	h.sendReviewDecisions(c, result.Updated)
+	return utils.Success(c, result, "Registrations rejected")
+}
+
+// ApproveParticipant approves one registration
+// @Summary Approve a registration
+// @Description Approves the participant's registration and issues their QR code if they signed every waiver. The participant is sent their ticket, or told they are approved while waivers remain to sign.
+// @Tags Approvals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Participant ID"
+// @Success 200 {object} utils.Response{data=services.ReviewResult}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "Not assigned to the participant's event"
+// @Failure 404 {object} utils.Response
+// @Router /participants/{id}/approve [post]
+func (h *Handler) ApproveParticipant(c *fiber.Ctx) error {
+	reviewerID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+	eventID, err := h.participantReviewEvent(c)
+	if err != nil {
+		return eventAccessError(c, err)
+	}
+
+	result, err := h.participantSvc.ApproveRegistrations(eventID, []string{c.Params("id")}, reviewerID)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	h.sendReviewDecisions(c, result.Updated)
+	return utils.Success(c, result, "Registration approved")
+}
+
+// RejectParticipant rejects one registration
+// @Summary Reject a registration
+// @Description Rejects the participant's registration with a reason. The participant is emailed the reason and is refused at verification.
+// @Tags Approvals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Participant ID"
+// @Param request body RejectParticipantRequest true "Rejection"
+// @Success 200 {object} utils.Response{data=services.ReviewResult}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "Not assigned to the participant's event"
+// @Failure 404 {object} utils.Response
+// @Router /participants/{id}/reject [post]
+func (h *Handler) RejectParticipant(c *fiber.Ctx) error {
+	reviewerID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+	eventID, err := h.participantReviewEvent(c)
+	if err != nil {
+		return eventAccessError(c, err)
+	}
+
+	var req RejectParticipantRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	result, err := h.participantSvc.RejectRegistrations(eventID, []string{c.Params("id")}, req.Reason, reviewerID)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	h.sendReviewDecisions(c, result.Updated)
+	return utils.Success(c, result, "Registration rejected")
+}
+
+// participantReviewEvent returns the event of the participant in the path,
+// once the user is found to have access to it
+func (h *Handler) participantReviewEvent(c *fiber.Ctx) (string, error) {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return "", fiber.NewError(fiber.StatusBadRequest, "Invalid participant ID")
+	}
+	participant, err := h.participantSvc.GetParticipant(participantID)
+	if err != nil {
+		return "", fiber.NewError(fiber.StatusNotFound, "Participant not found")
+	}
+	eventID := participant.EventID.String()
+	if err := h.checkEventAccess(c, eventID); err != nil {
+		return "", err
+	}
+	return eventID, nil
+}
+
+// sendReviewDecisions queues the notices of review decisions; the decisions
+// stand even if queueing fails
+func (h *Handler) sendReviewDecisions(c *fiber.Ctx, participantIDs []string) {
+	for _, id := range participantIDs {
+		if _, err := h.jobQueue.Enqueue(jobs.TypeReviewDecision, jobs.ParticipantPayload{ParticipantID: id}); err != nil {
+			middleware.GetLogger(c).WithError(err).WithField("participant_id", id).Warn("failed to enqueue review decision")
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/jobs"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/repositories/memory"
+	"event-management-backend/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+type reviewTestEnv struct {
+	repo *repositories.Repository
+	fx   *memory.Fixtures
+	h    *Handler
+}
+
+func newReviewTestEnv(t *testing.T) *reviewTestEnv {
+	t.Helper()
+
+	cfg := &config.Config{CredentialSigningKey: "test-credential-key", QRDir: t.TempDir()}
+	store := memory.New()
+	repo := store.Repository()
+	return &reviewTestEnv{
+		repo: repo,
+		fx:   store.Fixtures(),
+		h: &Handler{
+			eventSvc:       services.NewEventService(repo, cfg),
+			participantSvc: services.NewParticipantService(repo, cfg),
+			jobQueue:       jobs.NewQueue(repo.JobRepo, 1, time.Second),
+			cfg:            cfg,
+		},
+	}
+}
+
+// request sends a review of participantID as an organizer, with a token
+// limited to eventIDs when any are given
+func (e *reviewTestEnv) request(t *testing.T, decision, participantID, body string, eventIDs ...string) int {
+	t.Helper()
+
+	organizer := e.fx.User("organizer")
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		claims := jwt.MapClaims{"user_id": organizer.ID.String(), "role": "organizer"}
+		if eventIDs != nil {
+			scope := make([]interface{}, len(eventIDs))
+			for i, id := range eventIDs {
+				scope[i] = id
+			}
+			claims["event_ids"] = scope
+		}
+		c.Locals("user", &jwt.Token{Claims: claims})
+		c.Locals("user_id", organizer.ID.String())
+		c.Locals("user_role", "organizer")
+		return c.Next()
+	})
+	app.Post("/participants/:id/approve", e.h.ApproveParticipant)
+	app.Post("/participants/:id/reject", e.h.RejectParticipant)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/participants/"+participantID+"/"+decision, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+// reviewNotices returns the participants a review decision was queued for
+func (e *reviewTestEnv) reviewNotices(t *testing.T) []string {
+	t.Helper()
+
+	queued, _, err := e.repo.JobRepo.ListJobs(0, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, job := range queued {
+		if job.Type != jobs.TypeReviewDecision {
+			continue
+		}
+		var payload jobs.ParticipantPayload
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, payload.ParticipantID)
+	}
+	return ids
+}
+
+func pendingReview(p *models.Participant) {
+	p.ApprovalStatus, p.QRPath = services.ApprovalPending, ""
+}
+
+func TestApproveParticipant(t *testing.T) {
+	e := newReviewTestEnv(t)
+	event := e.fx.Event(func(ev *models.Event) { ev.RequiresApproval = true })
+	participant := e.fx.Participant(event, pendingReview)
+	id := participant.ID.String()
+
+	if code := e.request(t, "approve", uuid.NewString(), ""); code != fiber.StatusNotFound {
+		t.Fatalf("unknown participant: status %d, want %d", code, fiber.StatusNotFound)
+	}
+	if code := e.request(t, "approve", id, "", e.fx.Event().ID.String()); code != fiber.StatusForbidden {
+		t.Fatalf("token for another event: status %d, want %d", code, fiber.StatusForbidden)
+	}
+	if got, _ := e.repo.ParticipantRepo.GetParticipantByID(id); got.ApprovalStatus != services.ApprovalPending {
+		t.Fatalf("approval status after a refused request = %q, want pending", got.ApprovalStatus)
+	}
+
+	if code := e.request(t, "approve", id, "", event.ID.String()); code != fiber.StatusOK {
+		t.Fatalf("approve: status %d, want %d", code, fiber.StatusOK)
+	}
+	got, _ := e.repo.ParticipantRepo.GetParticipantByID(id)
+	if got.ApprovalStatus != services.ApprovalApproved || got.QRPath == "" {
+		t.Fatalf("approved participant: approval %q, QR %q; want approved with a QR code", got.ApprovalStatus, got.QRPath)
+	}
+	if notices := e.reviewNotices(t); len(notices) != 1 || notices[0] != id {
+		t.Fatalf("review decisions queued for %v, want [%s]", notices, id)
+	}
+}
+
+func TestRejectParticipant(t *testing.T) {
+	e := newReviewTestEnv(t)
+	event := e.fx.Event(func(ev *models.Event) { ev.RequiresApproval = true })
+	participant := e.fx.Participant(event, pendingReview)
+	id := participant.ID.String()
+
+	if code := e.request(t, "reject", id, `{}`); code != fiber.StatusBadRequest {
+		t.Fatalf("no reason: status %d, want %d", code, fiber.StatusBadRequest)
+	}
+	if code := e.request(t, "reject", id, `{"reason":"Full"}`, e.fx.Event().ID.String()); code != fiber.StatusForbidden {
+		t.Fatalf("token for another event: status %d, want %d", code, fiber.StatusForbidden)
+	}
+	if notices := e.reviewNotices(t); len(notices) != 0 {
+		t.Fatalf("review decisions queued for refused requests: %v", notices)
+	}
+
+	if code := e.request(t, "reject", id, `{"reason":"Seats are reserved for members"}`); code != fiber.StatusOK {
+		t.Fatalf("reject: status %d, want %d", code, fiber.StatusOK)
+	}
+	got, _ := e.repo.ParticipantRepo.GetParticipantByID(id)
+	if got.ApprovalStatus != services.ApprovalRejected || got.RejectionReason != "Seats are reserved for members" {
+		t.Fatalf("rejected participant: approval %q, reason %q", got.ApprovalStatus, got.RejectionReason)
+	}
+	if notices := e.reviewNotices(t); len(notices) != 1 || notices[0] != id {
+		t.Fatalf("review decisions queued for %v, want [%s]", notices, id)
+	}
+}
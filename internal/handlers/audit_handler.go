@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListAuditLogs returns the audit trail of administrative changes
+// @Summary List audit logs
+// @Description Newest first. from is inclusive and to exclusive.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string false "Filter by the user who made the change"
+// @Param entity_type query string false "Filter by entity type (event, participant, user, api_key)"
+// @Param entity_id query string false "Filter by entity ID"
+// @Param from query string false "Changes at or after this time (RFC3339)"
+// @Param to query string false "Changes before this time (RFC3339)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /admin/audit-logs [get]
+func (h *Handler) ListAuditLogs(c *fiber.Ctx) error {
+	from, err := timeQuery(c, "from")
+	if err != nil {
+		return utils.Error(c, "Invalid from format", fiber.StatusBadRequest)
+	}
+	to, err := timeQuery(c, "to")
+	if err != nil {
+		return utils.Error(c, "Invalid to format", fiber.StatusBadRequest)
+	}
+
+	query := services.AuditLogQuery{
+		UserID:     c.Query("user_id"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		From:       from,
+		To:         to,
+	}
+	query.Page, _ = strconv.Atoi(c.Query("page", "1"))
+	query.PageSize, _ = strconv.Atoi(c.Query("page_size", "20"))
+
+	logs, total, totalPages, err := h.auditSvc.List(query)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAuditFilter) {
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
+		return utils.Error(c, "Failed to fetch audit logs", fiber.StatusInternalServerError)
+	}
+
+	meta := &utils.Meta{
+		Page:      query.Page,
+		PageSize:  query.PageSize,
+		Total:     total,
+		TotalPage: totalPages,
+	}
+
+	return utils.SuccessWithMeta(c, logs, meta, "Audit logs retrieved successfully")
+}
+
+// timeQuery parses an optional RFC3339 query parameter
+func timeQuery(c *fiber.Ctx, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -1,7 +1,10 @@
 package handlers
 
 import (
+	"errors"
+
 	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
 	"event-management-backend/internal/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -10,16 +13,40 @@ import (
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
+	// Code from the authenticator app, required once two-factor
+	// authentication is enabled
+	OTP string `json:"otp" validate:"omitempty,len=6,numeric"`
+	// Also issue a refresh token, to stay logged in past the access token
+	RememberMe bool `json:"remember_me"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type LogoutRequest struct {
+	// Ends a remember-me login as well
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
 }
 
 type RegisterUserRequest struct {
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Password string `json:"password" validate:"required"`
 	Role     string `json:"role" validate:"required,oneof=admin organizer staff"`
 }
 
 // Login handles user authentication
 // @Summary User login
+// @Description Accounts with two-factor authentication enabled also need `otp`. Without it the response is 401 with the message "two-factor code required". With `remember_me` the response also has a refresh token for POST /auth/refresh. Tokens of staff carry an `event_ids` claim with the events they are assigned to; verification is limited to those events, so refresh the token after new assignments.
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -34,7 +61,10 @@ func (h *Handler) Login(c *fiber.Ctx) error {
 		return err
 	}
 
-	loginResp, err := h.authSvc.Authenticate(req.Email, req.Password)
+	loginResp, err := h.authSvc.Authenticate(req.Email, req.Password, req.OTP, req.RememberMe, services.LoginClient{
+		IP:        middleware.ClientIP(c),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	})
 	if err != nil {
 		return utils.Error(c, err.Error(), fiber.StatusUnauthorized)
 	}
@@ -42,6 +72,74 @@ func (h *Handler) Login(c *fiber.Ctx) error {
 	return utils.Success(c, loginResp, "Login successful")
 }
 
+// RefreshToken exchanges a refresh token for a new access token
+// @Summary Refresh access token
+// @Description The refresh token is rotated: use the one in the response next time. The presented one is rejected from now on.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} utils.Response{data=services.LoginResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /auth/refresh [post]
+func (h *Handler) RefreshToken(c *fiber.Ctx) error {
+	var req RefreshTokenRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	resp, err := h.authSvc.Refresh(req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrUserDeactivated) {
+			return utils.Error(c, err.Error(), fiber.StatusUnauthorized)
+		}
+		return utils.Error(c, "Failed to refresh token", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, resp, "Token refreshed")
+}
+
+// Logout revokes the token of the request
+// @Summary Log out
+// @Description The token is rejected from now on. Other tokens of the user stay valid. Send the refresh token of a remember-me login to end it too.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body LogoutRequest false "Refresh token"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /auth/logout [post]
+func (h *Handler) Logout(c *fiber.Ctx) error {
+	claims, err := middleware.GetTokenClaims(c)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusUnauthorized)
+	}
+
+	if err := h.authSvc.Logout(claims.UserID, claims.JTI, claims.ExpiresAt); err != nil {
+		if errors.Is(err, services.ErrTokenNotRevocable) {
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
+		return utils.Error(c, "Failed to log out", fiber.StatusInternalServerError)
+	}
+
+	var req LogoutRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.Error(c, "Invalid request body", fiber.StatusBadRequest)
+		}
+	}
+	if req.RefreshToken != "" {
+		if err := h.authSvc.RevokeRefreshToken(claims.UserID, req.RefreshToken); err != nil {
+			return utils.Error(c, "Failed to log out", fiber.StatusInternalServerError)
+		}
+	}
+
+	return utils.Success(c, nil, "Logged out")
+}
+
 // RegisterUser handles user registration (Admin only)
 // @Summary Register new user
 // @Tags Auth
@@ -107,3 +205,97 @@ func (h *Handler) GetProfile(c *fiber.Ctx) error {
 
 	return utils.Success(c, user, "Profile retrieved successfully")
 }
+
+// ChangePassword sets the current user's password
+// @Summary Change password
+// @Description The new password must follow the password policy. All tokens of the user are revoked, including the one of this request, so the user logs in again.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /profile/password [put]
+func (h *Handler) ChangePassword(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusUnauthorized)
+	}
+
+	var req ChangePasswordRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	if err := h.authSvc.ChangePassword(userID, req.CurrentPassword, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, services.ErrWrongPassword), errors.Is(err, utils.ErrWeakPassword):
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		case errors.Is(err, services.ErrUserNotFound):
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to change password", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "Password changed, please log in again")
+}
+
+// SetupTOTP starts two-factor enrollment for the current user
+// @Summary Set up two-factor authentication
+// @Description Returns a new authenticator secret and its otpauth URI. Login is unchanged until the secret is confirmed.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=services.TOTPSetup}
+// @Failure 409 {object} utils.Response
+// @Router /profile/2fa/setup [post]
+func (h *Handler) SetupTOTP(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusUnauthorized)
+	}
+
+	setup, err := h.authSvc.SetupTOTP(userID)
+	if err != nil {
+		if errors.Is(err, services.ErrTOTPAlreadyEnabled) {
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, setup, "Scan the secret with your authenticator app, then confirm a code")
+}
+
+// ConfirmTOTP enables two-factor authentication for the current user
+// @Summary Confirm two-factor authentication
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ConfirmTOTPRequest true "Code from the authenticator app"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /profile/2fa/confirm [post]
+func (h *Handler) ConfirmTOTP(c *fiber.Ctx) error {
+	var req ConfirmTOTPRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusUnauthorized)
+	}
+
+	if err := h.authSvc.ConfirmTOTP(userID, req.Code); err != nil {
+		if errors.Is(err, services.ErrTOTPAlreadyEnabled) {
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, nil, "Two-factor authentication enabled")
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"event-management-backend/internal/jobs"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListBackups returns stored backups and the status of the latest run
+// @Summary List backups
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/backups [get]
+func (h *Handler) ListBackups(c *fiber.Ctx) error {
+	backups, err := h.backupSvc.List(c.UserContext())
+	if err != nil {
+		return utils.Error(c, "Failed to list backups", fiber.StatusInternalServerError)
+	}
+
+	result := fiber.Map{
+		"enabled":  h.cfg.BackupEnabled,
+		"interval": h.cfg.BackupInterval.String(),
+		"status":   h.backupSvc.Status(),
+		"backups":  backups,
+	}
+
+	return utils.Success(c, result, "Backups retrieved successfully")
+}
+
+// CreateBackup schedules an immediate backup
+// @Summary Start a backup
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/backups [post]
+func (h *Handler) CreateBackup(c *fiber.Ctx) error {
+	job, err := h.jobQueue.EnqueueWithOptions(jobs.TypeBackup, nil, jobs.Options{MaxAttempts: 1})
+	if err != nil {
+		return utils.Error(c, "Failed to schedule backup", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, job, "Backup scheduled", fiber.StatusAccepted)
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"event-management-backend/internal/jobs"
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ReissueCredentialRequest struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+	// Ask gate staff to check the participant's ID at the next scan
+	RequireIDCheck bool `json:"require_id_check"`
+}
+
+// ReissueCredential replaces a lost badge
+// @Summary Reissue participant credential
+// @Description Issues a new signed QR code for a lost badge and logs the incident. Every earlier QR code of the participant is refused at the gates with CREDENTIAL_REVOKED.
+// @Tags Participants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Participant ID"
+// @Param request body ReissueCredentialRequest true "Reissue"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "Not assigned to the participant's event"
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "The credential was reissued concurrently"
+// @Router /participants/{id}/credentials/reissue [post]
+func (h *Handler) ReissueCredential(c *fiber.Ctx) error {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	var req ReissueCredentialRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+	if err := h.checkParticipantAccess(c, participantID); err != nil {
+		return eventAccessError(c, err)
+	}
+
+	result, err := h.participantSvc.ReissueCredential(participantID, req.Reason, req.RequireIDCheck, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, result, "Credential reissued successfully", fiber.StatusCreated)
+}
+
+type RegenerateQRRequest struct {
+	Reason string `json:"reason" validate:"max=1000"`
+	// Email the participant their ticket with the new QR code
+	SendTicket bool `json:"send_ticket"`
+}
+
+// RegenerateQR rotates a participant's QR code after it leaked
+// @Summary Regenerate participant QR code
+// @Description Deletes the participant's QR image and issues a new signed QR code; the old one is refused at the gates with CREDENTIAL_REVOKED. The rotation is logged as a credential incident, with reason "QR code leaked" unless another is given. With send_ticket, the participant is emailed their ticket with the new code.
+// @Tags Participants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Participant ID"
+// @Param request body RegenerateQRRequest false "Regeneration options"
+// @Success 201 {object} utils.Response{data=services.ReissueResult}
+// @Failure 400 {object} utils.Response "The registration is cancelled or has no QR code yet"
+// @Failure 403 {object} utils.Response "Not assigned to the participant's event"
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "The credential was rotated concurrently"
+// @Router /participants/{id}/regenerate-qr [post]
+func (h *Handler) RegenerateQR(c *fiber.Ctx) error {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	var req RegenerateQRRequest
+	if len(c.Body()) > 0 {
+		if err := middleware.ValidateBody(&req)(c); err != nil {
+			return err
+		}
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+	if err := h.checkParticipantAccess(c, participantID); err != nil {
+		return eventAccessError(c, err)
+	}
+
+	result, err := h.participantSvc.RegenerateQR(userID, participantID, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownParticipant):
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		case errors.Is(err, services.ErrTicketCancelled), errors.Is(err, services.ErrCredentialNotIssued):
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		case errors.Is(err, repositories.ErrVersionConflict):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		middleware.GetLogger(c).WithError(err).Error("Failed to regenerate QR code")
+		return utils.Error(c, "Failed to regenerate the QR code", fiber.StatusInternalServerError)
+	}
+
+	if req.SendTicket {
+		if _, err := h.jobQueue.Enqueue(jobs.TypeTicketResend, jobs.ParticipantPayload{ParticipantID: participantID}); err != nil {
+			middleware.GetLogger(c).WithError(err).WithField("participant_id", participantID).Warn("failed to enqueue ticket with regenerated QR code")
+		}
+	}
+
+	return utils.Success(c, result, "QR code regenerated", fiber.StatusCreated)
+}
+
+// ListCredentialIncidents returns the lost-badge reissues of a participant
+// @Summary List credential incidents
+// @Tags Participants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Participant ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "Not assigned to the participant's event"
+// @Failure 404 {object} utils.Response
+// @Router /participants/{id}/credentials/incidents [get]
+func (h *Handler) ListCredentialIncidents(c *fiber.Ctx) error {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+	if err := h.checkParticipantAccess(c, participantID); err != nil {
+		return eventAccessError(c, err)
+	}
+
+	incidents, err := h.participantSvc.ListCredentialIncidents(participantID)
+	if err != nil {
+		return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+	}
+
+	return utils.Success(c, incidents, "Credential incidents retrieved successfully")
+}
+
+// ResendTicket sends a participant their ticket again
+// @Summary Resend participant ticket
+// @Description Sends the participant their ticket with the QR code attached, on WhatsApp when TICKET_CHANNEL is whatsapp and they gave a phone number, by email otherwise. Limited to RATE_LIMIT_RESEND_TICKET_PER_HOUR per participant.
+// @Tags Participants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Participant ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response "The registration is cancelled or has no ticket yet"
+// @Failure 403 {object} utils.Response "Not assigned to the participant's event"
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Failure 502 {object} utils.Response "The ticket could not be sent"
+// @Router /participants/{id}/resend-ticket [post]
+func (h *Handler) ResendTicket(c *fiber.Ctx) error {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+	if err := h.checkParticipantAccess(c, participantID); err != nil {
+		return eventAccessError(c, err)
+	}
+
+	delivery, err := h.notifySvc.ResendTicket(c.UserContext(), userID, participantID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownParticipant):
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		case errors.Is(err, services.ErrTicketNotIssued), errors.Is(err, services.ErrTicketCancelled):
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
+		middleware.GetLogger(c).WithError(err).Error("Failed to resend ticket")
+		return utils.Error(c, "Failed to send the ticket, try again later", fiber.StatusBadGateway)
+	}
+
+	return utils.Success(c, delivery, "Ticket sent successfully")
+}
+
+// GetTicketPDF returns a participant's ticket as a printable PDF
+// @Summary Download participant ticket as PDF
+// @Description A4 ticket with the event logo and details, the participant, their QR code and ticket code, and the schedule of every event day.
+// @Tags Participants
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Participant ID"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response "The registration is cancelled or has no ticket yet"
+// @Failure 403 {object} utils.Response "Not assigned to the participant's event"
+// @Failure 404 {object} utils.Response
+// @Router /participants/{id}/ticket.pdf [get]
+func (h *Handler) GetTicketPDF(c *fiber.Ctx) error {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+	if err := h.checkParticipantAccess(c, participantID); err != nil {
+		return eventAccessError(c, err)
+	}
+
+	ticket, err := h.participantSvc.TicketPDF(participantID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownParticipant):
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		case errors.Is(err, services.ErrTicketNotIssued), errors.Is(err, services.ErrTicketCancelled):
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
+		middleware.GetLogger(c).WithError(err).Error("Failed to render ticket")
+		return utils.Error(c, "Failed to render the ticket", fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="ticket-%s.pdf"`, participantID))
+	return c.Send(ticket)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RegisterDeviceRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Platform string `json:"platform" validate:"required,oneof=android ios web other"`
+	// Installation ID generated by the app and kept across restarts
+	Identifier string `json:"identifier" validate:"required,max=100"`
+}
+
+// RegisterDevice registers the device the scanner app runs on
+// @Summary Register scanner device
+// @Description Called by the scanner app on start. Registering the same identifier again updates the device and returns the same ID; send it as device_id with each scan to record which device made it.
+// @Tags Verification
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RegisterDeviceRequest true "Device"
+// @Success 200 {object} utils.Response{data=models.Device}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /devices/register [post]
+func (h *Handler) RegisterDevice(c *fiber.Ctx) error {
+	var req RegisterDeviceRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	device, err := h.deviceSvc.Register(userID, services.RegisterDeviceRequest{
+		Name:       req.Name,
+		Platform:   req.Platform,
+		Identifier: req.Identifier,
+	})
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, device, "Device registered successfully")
+}
+
+// ListDevices returns the registered scanner devices and when they last
+// scanned
+// @Summary List scanner devices
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]models.Device}
+// @Failure 500 {object} utils.Response
+// @Router /admin/devices [get]
+func (h *Handler) ListDevices(c *fiber.Ctx) error {
+	devices, err := h.deviceSvc.List()
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve devices", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, devices, "Devices retrieved successfully")
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"event-management-backend/docs"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <title>Event Management API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "/docs/swagger.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>`
+
+// RegisterDocsRoutes serves the generated OpenAPI spec and a Swagger UI page
+// under /docs. The spec is embedded at build time from docs/swagger.json.
+func (h *Handler) RegisterDocsRoutes(router fiber.Router) {
+	router.Get("/docs", h.SwaggerUI)
+	router.Get("/docs/swagger.json", h.SwaggerSpec)
+}
+
+func (h *Handler) SwaggerUI(c *fiber.Ctx) error {
+	c.Type("html", "utf-8")
+	return c.SendString(swaggerUIPage)
+}
+
+func (h *Handler) SwaggerSpec(c *fiber.Ctx) error {
+	c.Type("json", "utf-8")
+	return c.Send(docs.SwaggerJSON)
+}
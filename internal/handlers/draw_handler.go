@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CreateDrawRequest struct {
+	Prize                  string `json:"prize" validate:"required,max=200"`
+	Winners                int    `json:"winners" validate:"required,gt=0,lte=1000"`
+	PaidOnly               bool   `json:"paid_only"`
+	CheckedInToday         bool   `json:"checked_in_today"`
+	ActionCode             string `json:"action_code"`
+	ExcludePreviousWinners bool   `json:"exclude_previous_winners"`
+}
+
+// CreateDraw draws winners for the next prize round of an event
+// @Summary Run lucky draw
+// @Description Picks random winners among participants matching the criteria. The seed is generated by the server and recorded, with the eligible pool hash, for auditing.
+// @Tags Draws
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body CreateDrawRequest true "Draw criteria"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /events/{id}/draws [post]
+func (h *Handler) CreateDraw(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	var req CreateDrawRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	draw, err := h.drawSvc.Draw(eventID, userID, services.DrawRequest{
+		Prize:                  req.Prize,
+		Winners:                req.Winners,
+		PaidOnly:               req.PaidOnly,
+		CheckedInToday:         req.CheckedInToday,
+		ActionCode:             req.ActionCode,
+		ExcludePreviousWinners: req.ExcludePreviousWinners,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrNoEligibleParticipants) {
+			return utils.Error(c, err.Error(), fiber.StatusUnprocessableEntity)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, draw, "Draw completed", fiber.StatusCreated)
+}
+
+// ListDraws returns the draws of an event with their winners
+// @Summary List lucky draws
+// @Tags Draws
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/draws [get]
+func (h *Handler) ListDraws(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	draws, err := h.drawSvc.ListDraws(eventID)
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve draws", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, draws, "Draws retrieved successfully")
+}
+
+// VerifyDraw replays a recorded draw with its stored seed
+// @Summary Verify lucky draw
+// @Description Read-only: rebuilds the eligible pool of the draw and replays the shuffle with the recorded seed, reporting whether the pool and winners still match.
+// @Tags Draws
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param draw_id path string true "Draw ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/draws/{draw_id}/verify [get]
+func (h *Handler) VerifyDraw(c *fiber.Ctx) error {
+	eventID, drawID := c.Params("id"), c.Params("draw_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(drawID); err != nil {
+		return utils.Error(c, "Invalid draw ID", fiber.StatusBadRequest)
+	}
+
+	verification, err := h.drawSvc.VerifyDraw(eventID, drawID)
+	if err != nil {
+		if errors.Is(err, services.ErrDrawNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to verify draw", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, verification, "Draw verified")
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// EventV2 is the v2 representation of an event. Scheduling and ticketing
+// fields are grouped, and the active flag is exposed as a status string.
+type EventV2 struct {
+	ID           uuid.UUID           `json:"id"`
+	Title        string              `json:"title"`
+	Slug         string              `json:"slug"`
+	Description  string              `json:"description"`
+	Status       string              `json:"status"`
+	Format       string              `json:"format"`
+	Visibility   string              `json:"visibility"`
+	LogoURL      string              `json:"logo_url,omitempty"`
+	BannerURL    string              `json:"banner_url,omitempty"`
+	LogoSizes    map[string]string   `json:"logo_sizes,omitempty"`
+	Schedule     EventScheduleV2     `json:"schedule"`
+	Ticket       EventTicketV2       `json:"ticket"`
+	Registration EventRegistrationV2 `json:"registration"`
+	Venue        *models.Venue       `json:"venue,omitempty"`
+	Days         []EventDayV2        `json:"days,omitempty"`
+	Version      int                 `json:"version"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+type EventScheduleV2 struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+type EventRegistrationV2 struct {
+	OpensAt  *time.Time `json:"opens_at,omitempty"`
+	ClosesAt *time.Time `json:"closes_at,omitempty"`
+	// upcoming|open|closed
+	State string `json:"state,omitempty"`
+	// Hours to confirm the email address; 0 when not required
+	ConfirmationHours int `json:"confirmation_hours"`
+	// Custom questions of the registration form
+	FormFields []models.FormField `json:"form_fields,omitempty"`
+}
+
+type EventTicketV2 struct {
+	Price            float64 `json:"price"`
+	Quota            *int    `json:"quota"`
+	RequiresApproval bool    `json:"requires_approval"`
+	// off|manual|fifo|priority
+	Waitlist string `json:"waitlist"`
+}
+
+type EventDayV2 struct {
+	ID        uuid.UUID `json:"id"`
+	DayNumber int       `json:"day_number"`
+	Label     string    `json:"label"`
+	Date      time.Time `json:"date"`
+	// Set only when the day is held elsewhere than the event
+	Venue   *models.Venue        `json:"venue,omitempty"`
+	Actions []models.EventAction `json:"actions,omitempty"`
+}
+
+// ParticipantV2 is the v2 representation of a participant. The embedded
+// event relation is dropped in favour of the event ID.
+type ParticipantV2 struct {
+	ID             uuid.UUID       `json:"id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	Name           string          `json:"name"`
+	Email          string          `json:"email"`
+	Phone          string          `json:"phone"`
+	Division       string          `json:"division"`
+	Address        string          `json:"address"`
+	MealPreference string          `json:"meal_preference"`
+	TicketTypeID   *uuid.UUID      `json:"ticket_type_id,omitempty"`
+	QRURL          string          `json:"qr_url"`
+	Tags           []string        `json:"tags"`
+	Payment        PaymentV2       `json:"payment"`
+	Approval       ApprovalV2      `json:"approval"`
+	Cancellation   *CancellationV2 `json:"cancellation,omitempty"`
+	Version        int             `json:"version"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+type PaymentV2 struct {
+	Status string `json:"status"`
+}
+
+type ApprovalV2 struct {
+	Status          string     `json:"status"`
+	RejectionReason string     `json:"rejection_reason,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	// Set while the registration is pending_confirmation
+	ConfirmationExpiresAt *time.Time `json:"confirmation_expires_at,omitempty"`
+}
+
+// CancellationV2 is set on cancelled registrations
+type CancellationV2 struct {
+	CancelledAt time.Time `json:"cancelled_at"`
+	Reason      string    `json:"reason,omitempty"`
+}
+
+// ParticipantDetailV2 is the v2 participant detail view
+type ParticipantDetailV2 struct {
+	Participant ParticipantV2            `json:"participant"`
+	Notes       []models.ParticipantNote `json:"notes"`
+}
+
+// RegistrationV2 replaces the untagged v1 registration payload
+type RegistrationV2 struct {
+	Participant ParticipantV2      `json:"participant"`
+	TicketType  *models.TicketType `json:"ticket_type,omitempty"`
+	QRURL       string             `json:"qr_url"`
+	// Waivers to sign before the QR code is released
+	PendingWaivers []models.Waiver `json:"pending_waivers,omitempty"`
+}
+
+// EventLandingV2 is the public event page with the event in its v2 shape
+type EventLandingV2 struct {
+	Event        EventV2                           `json:"event"`
+	Availability services.WidgetAvailability       `json:"availability"`
+	TicketTypes  []services.TicketTypeAvailability `json:"ticket_types,omitempty"`
+}
+
+// EventCancellationV2 is a cancelled event in its v2 shape with what
+// happened to its registrations
+type EventCancellationV2 struct {
+	Event          EventV2 `json:"event"`
+	Cancelled      int64   `json:"cancelled_participants"`
+	RefundsPending int64   `json:"refunds_pending"`
+}
+
+func NewEventV2(event *models.Event) EventV2 {
+	status := "inactive"
+	if event.IsActive {
+		status = "active"
+	}
+
+	dto := EventV2{
+		ID:          event.ID,
+		Title:       event.Title,
+		Slug:        event.Slug,
+		Description: event.Description,
+		Status:      status,
+		Format:      event.Format,
+		Visibility:  event.Visibility,
+		LogoURL:     event.LogoPath,
+		LogoSizes:   event.LogoVariants,
+		Venue:       event.Venue,
+		Schedule: EventScheduleV2{
+			StartsAt: event.StartsAt,
+			EndsAt:   event.EndsAt,
+		},
+		Ticket: EventTicketV2{
+			Price:            event.TicketPrice,
+			Quota:            event.TicketQuota,
+			RequiresApproval: event.RequiresApproval,
+			Waitlist:         event.Waitlist,
+		},
+		Registration: EventRegistrationV2{
+			OpensAt:           event.RegistrationOpensAt,
+			ClosesAt:          event.RegistrationClosesAt,
+			State:             event.RegistrationState,
+			ConfirmationHours: event.ConfirmationHours,
+
+			FormFields: event.FormFields,
+		},
+		Version:   event.Version,
+		CreatedAt: event.CreatedAt,
+		UpdatedAt: event.UpdatedAt,
+	}
+	if event.Banner != nil {
+		dto.BannerURL = event.Banner.Path
+	}
+
+	for _, day := range event.EventDays {
+		dto.Days = append(dto.Days, EventDayV2{
+			ID:        day.ID,
+			DayNumber: day.DayNumber,
+			Label:     day.Label,
+			Date:      day.Date,
+			Venue:     day.Venue,
+			Actions:   day.EventActions,
+		})
+	}
+
+	return dto
+}
+
+func NewParticipantV2(participant *models.Participant) ParticipantV2 {
+	var cancellation *CancellationV2
+	if participant.CancelledAt != nil {
+		cancellation = &CancellationV2{CancelledAt: *participant.CancelledAt, Reason: participant.CancellationReason}
+	}
+	tags := participant.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return ParticipantV2{
+		ID:             participant.ID,
+		EventID:        participant.EventID,
+		Name:           participant.Name,
+		Email:          participant.Email,
+		Phone:          participant.Phone,
+		Division:       participant.Division,
+		Address:        participant.Address,
+		MealPreference: participant.MealPreference,
+		TicketTypeID:   participant.TicketTypeID,
+		QRURL:          participant.QRPath,
+		Tags:           tags,
+		Payment:        PaymentV2{Status: participant.PaymentStatus},
+		Approval: ApprovalV2{
+			Status:          participant.ApprovalStatus,
+			RejectionReason: participant.RejectionReason,
+			ReviewedAt:      participant.ReviewedAt,
+
+			ConfirmationExpiresAt: participant.ConfirmationExpiresAt,
+		},
+		Cancellation: cancellation,
+		Version:      participant.Version,
+		CreatedAt:    participant.CreatedAt,
+		UpdatedAt:    participant.UpdatedAt,
+	}
+}
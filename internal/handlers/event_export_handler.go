@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ExportEvent downloads the portable definition of an event
+// @Summary Export event definition
+// @Description Exports the event with its days, actions and sessions as JSON for import into another environment. Participants and check-ins are not included; GET /admin/events/{id}/export exports them too.
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} services.EventExport
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/export.json [get]
+func (h *Handler) ExportEvent(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	export, err := h.eventSvc.ExportEvent(eventID)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusNotFound)
+	}
+
+	body, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return utils.Error(c, "Failed to export event", fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="event-%s.json"`, export.Event.Slug))
+	return c.Send(body)
+}
+
+// ExportEventBundle downloads the complete export of an event
+// @Summary Export event bundle
+// @Description Streams the event definition with its participants and their check-ins as one JSON document, for backup or migration between environments. exclude_pii leaves out the names and contact details of participants. Who made each check-in is not exported.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param exclude_pii query bool false "Leave out participant names and contact details"
+// @Success 200 {object} services.EventBundle
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/events/{id}/export [get]
+func (h *Handler) ExportEventBundle(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	excludePII := false
+	if value := c.Query("exclude_pii"); value != "" {
+		var err error
+		if excludePII, err = strconv.ParseBool(value); err != nil {
+			return utils.Error(c, "exclude_pii must be true or false", fiber.StatusBadRequest)
+		}
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	bundle, err := h.eventSvc.ExportEventBundle(actorID, eventID, excludePII)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to export event", fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="event-%s-bundle.json"`, bundle.Definition.Event.Slug))
+
+	// The writer runs after the handler returns, when c may already be
+	// reused, so the logger is captured here
+	log := middleware.GetLogger(c)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := bundle.Write(w); err != nil {
+			log.WithError(err).Error("failed to write event bundle")
+		}
+	})
+	return nil
+}
+
+// ImportEvent creates an event from an exported definition
+// @Summary Import event definition
+// @Description Creates a new event from a definition produced by the export endpoint. All records get new IDs. Use slug and code_suffix to import a copy into the environment it was exported from.
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.EventExport true "Event definition"
+// @Param slug query string false "Slug for the imported event"
+// @Param code_suffix query string false "Suffix appended to every action code"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /events/import [post]
+func (h *Handler) ImportEvent(c *fiber.Ctx) error {
+	var export services.EventExport
+	if err := c.BodyParser(&export); err != nil {
+		return utils.Error(c, "Invalid request body", fiber.StatusBadRequest)
+	}
+
+	event, err := h.eventSvc.ImportEvent(&export, services.ImportOptions{
+		Slug:       c.Query("slug"),
+		CodeSuffix: c.Query("code_suffix"),
+	})
+	if err != nil {
+		var importErr *services.EventImportError
+		if errors.As(err, &importErr) {
+			return utils.ErrorWithData(c, "Invalid event definition", importErr.Problems, fiber.StatusUnprocessableEntity)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, event, "Event imported successfully", fiber.StatusCreated)
+}
+
+// ImportEventBundle recreates an event from a bundle or a calendar file
+// @Summary Import event bundle or calendar
+// @Description Recreates an event from a bundle produced by GET /admin/events/{id}/export, with its participants and check-ins when participants is true. All records get new IDs and the event starts as a draft. Check-ins are recorded in the name of the importing admin, and approved participants get new QR codes. An iCalendar (.ics) file, uploaded as "file" or sent as text/calendar, creates a draft event spanning its calendar events with a day for every date they cover.
+// @Tags Admin
+// @Accept json
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param request body services.EventBundle false "Event bundle"
+// @Param file formData file false "Event bundle (.json) or calendar (.ics)"
+// @Param slug query string false "Slug for the imported event; derived from the calendar title for .ics files"
+// @Param code_suffix query string false "Suffix appended to every action code"
+// @Param participants query bool false "Also import participants and their check-ins"
+// @Success 201 {object} utils.Response{data=services.EventBundleImport}
+// @Failure 400 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /admin/events/import [post]
+func (h *Handler) ImportEventBundle(c *fiber.Ctx) error {
+	opts := services.BundleImportOptions{
+		ImportOptions: services.ImportOptions{
+			Slug:       c.Query("slug"),
+			CodeSuffix: c.Query("code_suffix"),
+		},
+	}
+	if value := c.Query("participants"); value != "" {
+		var err error
+		if opts.Participants, err = strconv.ParseBool(value); err != nil {
+			return utils.Error(c, "participants must be true or false", fiber.StatusBadRequest)
+		}
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	body, calendar := c.Body(), strings.HasPrefix(c.Get(fiber.HeaderContentType), "text/calendar")
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		file, err := c.FormFile("file")
+		if err != nil {
+			return utils.Error(c, "File is required", fiber.StatusBadRequest)
+		}
+		if file.Size > h.cfg.MaxUploadSize {
+			return utils.Error(c, "File too large", fiber.StatusBadRequest)
+		}
+		src, err := file.Open()
+		if err != nil {
+			return utils.Error(c, "Failed to read file", fiber.StatusInternalServerError)
+		}
+		defer src.Close()
+		if body, err = io.ReadAll(src); err != nil {
+			return utils.Error(c, "Failed to read file", fiber.StatusInternalServerError)
+		}
+		calendar = strings.EqualFold(filepath.Ext(file.Filename), ".ics") ||
+			strings.HasPrefix(file.Header.Get(fiber.HeaderContentType), "text/calendar")
+	}
+
+	var result *services.EventBundleImport
+	if calendar {
+		var event *models.Event
+		event, err = h.eventSvc.ImportEventCalendar(bytes.NewReader(body), opts.ImportOptions)
+		result = &services.EventBundleImport{Event: event}
+	} else {
+		var bundle services.EventBundle
+		if err := json.Unmarshal(body, &bundle); err != nil {
+			return utils.Error(c, "Invalid event bundle", fiber.StatusBadRequest)
+		}
+		result, err = h.eventSvc.ImportEventBundle(actorID, &bundle, opts)
+	}
+	if err != nil {
+		var importErr *services.EventImportError
+		if errors.As(err, &importErr) {
+			return utils.ErrorWithData(c, "Invalid event bundle", importErr.Problems, fiber.StatusUnprocessableEntity)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, result, "Event imported successfully", fiber.StatusCreated)
+}
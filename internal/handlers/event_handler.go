@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
+	"event-management-backend/internal/jobs"
 	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
 	"event-management-backend/internal/services"
 	"event-management-backend/internal/utils"
 
@@ -20,17 +26,127 @@ type CreateEventRequest struct {
 	EndsAt      string  `json:"ends_at" validate:"required"`
 	TicketPrice float64 `json:"ticket_price" validate:"gte=0"`
 	TicketQuota *int    `json:"ticket_quota" validate:"omitempty,gt=0"`
+	// Registrations wait for organizer approval before the QR code is issued
+	RequiresApproval bool `json:"requires_approval" form:"requires_approval"`
+	// Hours registrants have to confirm their email address before their
+	// registration expires; 0 (default) for no confirmation
+	ConfirmationHours int `json:"confirmation_hours" form:"confirmation_hours" validate:"gte=0,lte=168"`
+	// Public registrations must pass a CAPTCHA, when CAPTCHA checks are
+	// configured
+	RequiresCaptcha bool `json:"requires_captcha" form:"requires_captcha"`
+	// in_person (default), online or hybrid
+	Format string `json:"format" form:"format" validate:"omitempty,oneof=in_person online hybrid"`
+	// Origins allowed to embed the registration widget, e.g. https://example.com
+	WidgetOrigins []string `json:"widget_origins" form:"widget_origins" validate:"omitempty,dive,url"`
+	// Venue from GET /venues
+	VenueID string `json:"venue_id" form:"venue_id" validate:"omitempty,uuid"`
+	// Registration window (RFC 3339); open from now until the event ends
+	// when omitted
+	RegistrationOpensAt  string `json:"registration_opens_at" form:"registration_opens_at"`
+	RegistrationClosesAt string `json:"registration_closes_at" form:"registration_closes_at"`
+	// public (default), unlisted or private
+	Visibility string `json:"visibility" form:"visibility" validate:"omitempty,oneof=public unlisted private"`
+	// Required to view and register for a private event; none when omitted
+	AccessCode string `json:"access_code" form:"access_code" validate:"max=100"`
+	// Registrations beyond the ticket quota join a waitlist unless off
+	// (default); fifo and priority promote waiting people automatically
+	Waitlist string `json:"waitlist" form:"waitlist" validate:"omitempty,oneof=off manual fifo priority"`
+	// Custom questions of the registration form; JSON bodies only
+	FormFields []models.FormField `json:"form_fields" validate:"omitempty,max=50"`
+}
+
+type UpdateEventRequest struct {
+	Title       string  `json:"title" validate:"required"`
+	Slug        string  `json:"slug" validate:"required,alphanum"`
+	Description string  `json:"description"`
+	StartsAt    string  `json:"starts_at" validate:"required"`
+	EndsAt      string  `json:"ends_at" validate:"required"`
+	TicketPrice float64 `json:"ticket_price" validate:"gte=0"`
+	TicketQuota *int    `json:"ticket_quota" validate:"omitempty,gt=0"`
+
+	RequiresApproval bool `json:"requires_approval"`
+	RequiresCaptcha  bool `json:"requires_captcha"`
+	// 0 turns email confirmation off
+	ConfirmationHours int `json:"confirmation_hours" validate:"gte=0,lte=168"`
+	// Unchanged when omitted
+	Format        string   `json:"format" validate:"omitempty,oneof=in_person online hybrid"`
+	WidgetOrigins []string `json:"widget_origins" validate:"omitempty,dive,url"`
+	// The event has no venue when omitted
+	VenueID string `json:"venue_id" validate:"omitempty,uuid"`
+	// Registration window (RFC 3339); open from now until the event ends
+	// when omitted
+	RegistrationOpensAt  string `json:"registration_opens_at"`
+	RegistrationClosesAt string `json:"registration_closes_at"`
+	// Unchanged when omitted
+	Visibility string `json:"visibility" validate:"omitempty,oneof=public unlisted private"`
+	// Unchanged when omitted, removed when empty
+	AccessCode *string `json:"access_code" validate:"omitempty,max=100"`
+	// off, manual, fifo or priority; unchanged when omitted
+	Waitlist string `json:"waitlist" validate:"omitempty,oneof=off manual fifo priority"`
+	// Custom questions of the registration form; unchanged when omitted,
+	// removed when empty. Answers already given are kept.
+	FormFields []models.FormField `json:"form_fields" validate:"omitempty,max=50"`
+	// Version of the event the client last read; enables conflict detection
+	Version *int `json:"version" validate:"omitempty,min=1"`
 }
 
 type AddEventDayRequest struct {
 	DayNumber int    `json:"day_number" validate:"required,gt=0"`
 	Label     string `json:"label" validate:"required"`
 	Date      string `json:"date" validate:"required"`
+	// Only for days held elsewhere than the event's venue
+	VenueID string `json:"venue_id" validate:"omitempty,uuid"`
+	// Most participants admitted on the day; unlimited when omitted
+	Capacity *int `json:"capacity" validate:"omitempty,gt=0"`
+}
+
+type BulkAddEventDaysRequest struct {
+	// Every day from start_date through end_date (RFC 3339) ...
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	// ... or these dates (RFC 3339)
+	Dates []string `json:"dates" validate:"omitempty,max=366"`
+	// One label per date, in the order of the dates; "Day <number>" when
+	// omitted
+	Labels []string `json:"labels" validate:"omitempty,max=366,dive,max=255"`
+	// Apply to every day
+	VenueID  string `json:"venue_id" validate:"omitempty,uuid"`
+	Capacity *int   `json:"capacity" validate:"omitempty,gt=0"`
 }
 
 type AddEventActionRequest struct {
 	Name string `json:"name" validate:"required"`
 	Code string `json:"code" validate:"required,alphanum"`
+	// Restricts the action to credentials granted this zone, e.g. backstage
+	Zone string `json:"zone" validate:"omitempty,max=50,alphanum"`
+	// Scans are only accepted from starts_at until ends_at; open-ended on
+	// either side when omitted
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+func (req AddEventActionRequest) toService() services.EventActionRequest {
+	return services.EventActionRequest{
+		Name:     req.Name,
+		Code:     req.Code,
+		Zone:     req.Zone,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+	}
+}
+
+type ReorderEventActionsRequest struct {
+	// Every active action of the day, first to last
+	ActionIDs []string `json:"action_ids" validate:"required,min=1,dive,uuid"`
+}
+
+type CancelEventRequest struct {
+	// Told to participants in their cancellation notice
+	Reason string `json:"reason" validate:"max=500"`
+	// Paid registrations move to refund_pending
+	Refund bool `json:"refund"`
+	// Email cancelled participants, true when omitted
+	Notify *bool `json:"notify"`
 }
 
 // CreateEvent creates a new event
@@ -63,6 +179,10 @@ func (h *Handler) CreateEvent(c *fiber.Ctx) error {
 	if endsAt.Before(startsAt) {
 		return utils.Error(c, "End date must be after start date", fiber.StatusBadRequest)
 	}
+	opensAt, closesAt, err := registrationWindow(req.RegistrationOpensAt, req.RegistrationClosesAt)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
 
 	// Handle file upload
 	logoPath := ""
@@ -71,6 +191,13 @@ func (h *Handler) CreateEvent(c *fiber.Ctx) error {
 		if err := utils.ValidateImageFile(file); err != nil {
 			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
 		}
+		if err := utils.ScanUploadedFile(c.UserContext(), h.scanner, file); err != nil {
+			if errors.Is(err, utils.ErrInfected) {
+				return utils.Error(c, "File rejected by virus scan", fiber.StatusBadRequest)
+			}
+			middleware.GetLogger(c).WithError(err).Error("virus scan failed")
+			return utils.Error(c, "File could not be scanned, try again later", fiber.StatusServiceUnavailable)
+		}
 
 		filename := utils.GenerateUniqueFilename(file.Filename)
 		if err := utils.SaveUploadedFile(file, h.cfg.LogoDir, filename); err != nil {
@@ -89,29 +216,447 @@ func (h *Handler) CreateEvent(c *fiber.Ctx) error {
 		LogoPath:    logoPath,
 		TicketPrice: req.TicketPrice,
 		TicketQuota: req.TicketQuota,
+
+		RequiresApproval:  req.RequiresApproval,
+		RequiresCaptcha:   req.RequiresCaptcha,
+		Format:            req.Format,
+		ConfirmationHours: req.ConfirmationHours,
+		WidgetOrigins:     req.WidgetOrigins,
+		VenueID:           req.VenueID,
+
+		RegistrationOpensAt:  opensAt,
+		RegistrationClosesAt: closesAt,
+
+		Visibility: req.Visibility,
+		AccessCode: req.AccessCode,
+		Waitlist:   req.Waitlist,
+		FormFields: req.FormFields,
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
 	}
 
-	event, err := h.eventSvc.CreateEvent(eventReq)
+	event, err := h.eventSvc.CreateEvent(actorID, eventReq)
 	if err != nil {
 		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
 	}
 
-	return utils.Success(c, event, "Event created successfully", fiber.StatusCreated)
+	if event.LogoPath != "" {
+		if _, err := h.jobQueue.Enqueue(jobs.TypeLogoVariants, jobs.EventPayload{EventID: event.ID.String()}); err != nil {
+			middleware.GetLogger(c).WithError(err).Warn("failed to enqueue logo processing")
+		}
+	}
+
+	return utils.Success(c, present(c, event), "Event created successfully", fiber.StatusCreated)
+}
+
+// UpdateEvent replaces the details of an event
+// @Summary Update event
+// @Description Replaces every field of the event except its logo. Send the version last read to be told when someone else changed the event meanwhile.
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body UpdateEventRequest true "Event data"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "Slug in use, or version conflict with data holding the latest event"
+// @Router /events/{id} [put]
+func (h *Handler) UpdateEvent(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req UpdateEventRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	startsAt, err := time.Parse(time.RFC3339, req.StartsAt)
+	if err != nil {
+		return utils.Error(c, "Invalid starts_at format", fiber.StatusBadRequest)
+	}
+	endsAt, err := time.Parse(time.RFC3339, req.EndsAt)
+	if err != nil {
+		return utils.Error(c, "Invalid ends_at format", fiber.StatusBadRequest)
+	}
+	opensAt, closesAt, err := registrationWindow(req.RegistrationOpensAt, req.RegistrationClosesAt)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	event, err := h.eventSvc.UpdateEvent(actorID, eventID, services.UpdateEventRequest{
+		CreateEventRequest: services.CreateEventRequest{
+			Title:       req.Title,
+			Slug:        req.Slug,
+			Description: req.Description,
+			StartsAt:    startsAt,
+			EndsAt:      endsAt,
+			TicketPrice: req.TicketPrice,
+			TicketQuota: req.TicketQuota,
+
+			RequiresApproval:  req.RequiresApproval,
+			RequiresCaptcha:   req.RequiresCaptcha,
+			Format:            req.Format,
+			ConfirmationHours: req.ConfirmationHours,
+			WidgetOrigins:     req.WidgetOrigins,
+			VenueID:           req.VenueID,
+
+			RegistrationOpensAt:  opensAt,
+			RegistrationClosesAt: closesAt,
+
+			Visibility: req.Visibility,
+			Waitlist:   req.Waitlist,
+			FormFields: req.FormFields,
+		},
+		AccessCode: req.AccessCode,
+		Version:    req.Version,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownEvent):
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		case errors.Is(err, services.ErrEventSlugTaken), errors.Is(err, services.ErrEventArchived):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		case errors.Is(err, repositories.ErrVersionConflict):
+			latest, getErr := h.eventSvc.GetEvent(eventID)
+			if getErr != nil {
+				return utils.Error(c, "Event not found", fiber.StatusNotFound)
+			}
+			return utils.ErrorWithData(c, err.Error(), present(c, latest), fiber.StatusConflict)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	// A raised quota or a new waitlist mode may let people in
+	if event.Waitlist == services.WaitlistFIFO || event.Waitlist == services.WaitlistPriority {
+		h.promoteWaitlist(c, eventID)
+	}
+
+	return utils.Success(c, present(c, event), "Event updated successfully")
+}
+
+// GetEventRevisions returns the change history of an event
+// @Summary List event revisions
+// @Description Every update that changed an event, newest first, with the editor and the previous and new value of each changed field. Access codes are only recorded as set or not.
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} utils.Response{data=[]models.EventRevision}
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/revisions [get]
+func (h *Handler) GetEventRevisions(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
+
+	revisions, total, totalPages, err := h.eventSvc.ListEventRevisions(eventID, page, pageSize)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to fetch event revisions", fiber.StatusInternalServerError)
+	}
+
+	meta := &utils.Meta{
+		Page:      page,
+		PageSize:  pageSize,
+		Total:     total,
+		TotalPage: totalPages,
+	}
+
+	return utils.SuccessWithMeta(c, revisions, meta, "Event revisions retrieved successfully")
+}
+
+// DeleteEvent deactivates an event
+// @Summary Delete event
+// @Description The event is deactivated rather than removed: its participants and verifications are kept.
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id} [delete]
+func (h *Handler) DeleteEvent(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	if err := h.eventSvc.DeleteEvent(actorID, eventID); err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to delete event", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "Event deleted successfully")
+}
+
+// PublishEvent lists a draft event publicly and opens its registration
+// @Summary Publish event
+// @Description Events start as drafts. Only published events are listed by GET /events and accept registrations.
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "The event's status does not allow it"
+// @Router /events/{id}/publish [post]
+func (h *Handler) PublishEvent(c *fiber.Ctx) error {
+	return h.changeEventStatus(c, h.eventSvc.PublishEvent, "Event published successfully")
+}
+
+// CancelEvent closes registration of a published event and cancels its
+// registrations
+// @Summary Cancel event
+// @Description Every registration is cancelled with the event in one transaction and its QR code is refused from then on. Participants are emailed in the background unless notify is false.
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body CancelEventRequest false "Cancellation options"
+// @Success 200 {object} utils.Response{data=services.EventCancellation}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "The event's status does not allow it"
+// @Router /events/{id}/cancel [post]
+func (h *Handler) CancelEvent(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req CancelEventRequest
+	if len(c.Body()) > 0 {
+		if err := middleware.ValidateBody(&req)(c); err != nil {
+			return err
+		}
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	cancellation, err := h.eventSvc.CancelEvent(actorID, eventID, services.CancelEventRequest{
+		Reason: req.Reason,
+		Refund: req.Refund,
+	})
+	if err != nil {
+		var transitionErr *services.InvalidTransitionError
+		switch {
+		case errors.Is(err, services.ErrUnknownEvent):
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		case errors.As(err, &transitionErr), errors.Is(err, repositories.ErrVersionConflict):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, "Failed to cancel event", fiber.StatusInternalServerError)
+	}
+
+	if (req.Notify == nil || *req.Notify) && cancellation.Cancelled > 0 {
+		if _, err := h.jobQueue.Enqueue(jobs.TypeCancellationNotices, jobs.EventPayload{EventID: eventID}); err != nil {
+			middleware.GetLogger(c).WithError(err).Warn("failed to enqueue cancellation notices")
+		}
+	}
+
+	return utils.Success(c, present(c, cancellation), "Event cancelled successfully")
+}
+
+// ArchiveEvent freezes an event
+// @Summary Archive event
+// @Description Archived events can no longer be edited or change status.
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "The event's status does not allow it"
+// @Router /events/{id}/archive [post]
+func (h *Handler) ArchiveEvent(c *fiber.Ctx) error {
+	return h.changeEventStatus(c, h.eventSvc.ArchiveEvent, "Event archived successfully")
+}
+
+func (h *Handler) changeEventStatus(c *fiber.Ctx, change func(actorID, eventID string) (*models.Event, error), message string) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	event, err := change(actorID, eventID)
+	if err != nil {
+		var transitionErr *services.InvalidTransitionError
+		switch {
+		case errors.Is(err, services.ErrUnknownEvent):
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		case errors.As(err, &transitionErr), errors.Is(err, repositories.ErrVersionConflict):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, "Failed to change event status", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, present(c, event), message)
+}
+
+// registrationWindow parses the optional RFC 3339 bounds of a registration
+// window
+func registrationWindow(opensAt, closesAt string) (*time.Time, *time.Time, error) {
+	opens, err := optionalTime("registration_opens_at", opensAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	closes, err := optionalTime("registration_closes_at", closesAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return opens, closes, nil
+}
+
+// optionalTime parses an RFC 3339 time, nil when value is empty
+func optionalTime(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 time", name)
+	}
+	return &t, nil
 }
 
-// ListEvents returns paginated list of events
+// checkAccessCode refuses a private event unless code is its access code
+func checkAccessCode(event *models.Event, code string) error {
+	if err := services.CheckAccessCode(event, code); err != nil {
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	}
+	return nil
+}
+
+// eventFilters reads the event list filters from the query string
+func eventFilters(c *fiber.Ctx) (*repositories.EventFilters, error) {
+	filters := &repositories.EventFilters{Search: strings.TrimSpace(c.Query("search"))}
+
+	if value := c.Query("is_active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("is_active must be true or false")
+		}
+		filters.IsActive = &active
+	}
+
+	for _, param := range []struct {
+		name string
+		dest **time.Time
+	}{
+		{"starts_after", &filters.StartsAfter},
+		{"ends_before", &filters.EndsBefore},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an RFC 3339 time", param.name)
+		}
+		*param.dest = &t
+	}
+
+	return filters, nil
+}
+
+// ListEvents returns paginated list of published events
 // @Summary List events
+// @Description Only active, published events are listed.
 // @Tags Events
 // @Produce json
+// @Param search query string false "Part of the title or description"
+// @Param starts_after query string false "Only events starting at or after this RFC 3339 time"
+// @Param ends_before query string false "Only events ending at or before this RFC 3339 time"
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(20)
 // @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
 // @Router /events [get]
 func (h *Handler) ListEvents(c *fiber.Ctx) error {
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
 
-	events, total, totalPages, err := h.eventSvc.ListEvents(page, pageSize)
+	filters, err := eventFilters(c)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	events, total, totalPages, err := h.eventSvc.ListPublishedEvents(page, pageSize, filters)
+	if err != nil {
+		return utils.Error(c, "Failed to fetch events", fiber.StatusInternalServerError)
+	}
+
+	meta := &utils.Meta{
+		Page:      page,
+		PageSize:  pageSize,
+		Total:     total,
+		TotalPage: totalPages,
+	}
+
+	return utils.SuccessWithMeta(c, present(c, events), meta, "Events retrieved successfully")
+}
+
+// ListAllEvents returns events of any status for administration
+// @Summary List all events
+// @Description Includes drafts, cancelled, archived and deleted (inactive) events.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "Part of the title or description"
+// @Param is_active query bool false "Only active (true) or deleted (false) events"
+// @Param starts_after query string false "Only events starting at or after this RFC 3339 time"
+// @Param ends_before query string false "Only events ending at or before this RFC 3339 time"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /admin/events [get]
+func (h *Handler) ListAllEvents(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
+
+	filters, err := eventFilters(c)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	events, total, totalPages, err := h.eventSvc.ListEvents(page, pageSize, filters)
 	if err != nil {
 		return utils.Error(c, "Failed to fetch events", fiber.StatusInternalServerError)
 	}
@@ -123,7 +668,7 @@ func (h *Handler) ListEvents(c *fiber.Ctx) error {
 		TotalPage: totalPages,
 	}
 
-	return utils.SuccessWithMeta(c, events, meta, "Events retrieved successfully")
+	return utils.SuccessWithMeta(c, present(c, events), meta, "Events retrieved successfully")
 }
 
 // GetEvent returns event by ID
@@ -145,7 +690,11 @@ func (h *Handler) GetEvent(c *fiber.Ctx) error {
 		return utils.Error(c, "Event not found", fiber.StatusNotFound)
 	}
 
-	return utils.Success(c, event, "Event retrieved successfully")
+	if middleware.NotModified(c, event.UpdatedAt) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return utils.Success(c, present(c, event), "Event retrieved successfully")
 }
 
 // GetEventBySlug returns event by slug
@@ -153,7 +702,9 @@ func (h *Handler) GetEvent(c *fiber.Ctx) error {
 // @Tags Events
 // @Produce json
 // @Param slug path string true "Event slug"
+// @Param access_code query string false "Access code of a private event"
 // @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response "The event is private and the access code is missing or wrong"
 // @Failure 404 {object} utils.Response
 // @Router /events/slug/{slug} [get]
 func (h *Handler) GetEventBySlug(c *fiber.Ctx) error {
@@ -163,8 +714,41 @@ func (h *Handler) GetEventBySlug(c *fiber.Ctx) error {
 	if err != nil {
 		return utils.Error(c, "Event not found", fiber.StatusNotFound)
 	}
+	if err := checkAccessCode(event, c.Query("access_code")); err != nil {
+		return err
+	}
 
-	return utils.Success(c, event, "Event retrieved successfully")
+	if middleware.NotModified(c, event.UpdatedAt) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return utils.Success(c, present(c, event), "Event retrieved successfully")
+}
+
+// GetEventLanding returns everything the public event page shows
+// @Summary Get event page
+// @Description The event with its venue, banner, days, active actions, registration state, remaining quota and ticket types. Drafts are not found.
+// @Tags Events
+// @Produce json
+// @Param slug path string true "Event slug"
+// @Param access_code query string false "Access code of a private event"
+// @Success 200 {object} utils.Response{data=services.EventLanding}
+// @Failure 403 {object} utils.Response "The event is private and the access code is missing or wrong"
+// @Failure 404 {object} utils.Response
+// @Router /events/slug/{slug}/full [get]
+func (h *Handler) GetEventLanding(c *fiber.Ctx) error {
+	landing, err := h.eventSvc.GetEventLanding(c.Params("slug"), c.Query("access_code"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownEvent):
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		case errors.Is(err, services.ErrAccessCodeRequired), errors.Is(err, services.ErrInvalidAccessCode):
+			return utils.Error(c, err.Error(), fiber.StatusForbidden)
+		}
+		return utils.Error(c, "Failed to retrieve event", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, present(c, landing), "Event retrieved successfully")
 }
 
 // AddEventDay adds a day to an event
@@ -194,7 +778,13 @@ func (h *Handler) AddEventDay(c *fiber.Ctx) error {
 		return utils.Error(c, "Invalid date format", fiber.StatusBadRequest)
 	}
 
-	day, err := h.eventSvc.AddEventDay(eventID, req.DayNumber, req.Label, date)
+	day, err := h.eventSvc.AddEventDay(eventID, services.EventDayRequest{
+		DayNumber: req.DayNumber,
+		Label:     req.Label,
+		Date:      date,
+		VenueID:   req.VenueID,
+		Capacity:  req.Capacity,
+	})
 	if err != nil {
 		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
 	}
@@ -202,6 +792,92 @@ func (h *Handler) AddEventDay(c *fiber.Ctx) error {
 	return utils.Success(c, day, "Event day added successfully", fiber.StatusCreated)
 }
 
+// BulkAddEventDays adds several days to an event at once
+// @Summary Add event days in bulk
+// @Description Creates one day per date of a range or list in a single transaction. Days are numbered after the event's last day, in date order.
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body BulkAddEventDaysRequest true "Dates and labels"
+// @Success 201 {object} utils.Response{data=[]models.EventDay}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/days/bulk [post]
+func (h *Handler) BulkAddEventDays(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req BulkAddEventDaysRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	startDate, err := optionalTime("start_date", req.StartDate)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+	endDate, err := optionalTime("end_date", req.EndDate)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+	dates := make([]time.Time, 0, len(req.Dates))
+	for _, value := range req.Dates {
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return utils.Error(c, "dates must be RFC 3339 times", fiber.StatusBadRequest)
+		}
+		dates = append(dates, date)
+	}
+
+	days, err := h.eventSvc.AddEventDays(eventID, services.BulkEventDaysRequest{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Dates:     dates,
+		Labels:    req.Labels,
+		VenueID:   req.VenueID,
+		Capacity:  req.Capacity,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, days, "Event days added successfully", fiber.StatusCreated)
+}
+
+// GetDayAttendance returns the admissions of each event day
+// @Summary Get event day attendance
+// @Description Distinct participants scanned on each day, against the day's capacity. Scans admitting a new participant to a full day fail with EVENT_DAY_FULL.
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response{data=[]services.DayAttendance}
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/days/attendance [get]
+func (h *Handler) GetDayAttendance(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	attendance, err := h.eventSvc.GetDayAttendance(eventID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to retrieve attendance", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, attendance, "Attendance retrieved successfully")
+}
+
 // AddEventAction adds an action to an event day
 // @Summary Add event action
 // @Tags Events
@@ -230,10 +906,132 @@ func (h *Handler) AddEventAction(c *fiber.Ctx) error {
 		return err
 	}
 
-	action, err := h.eventSvc.AddEventAction(eventID, dayID, req.Name, req.Code)
+	action, err := h.eventSvc.AddEventAction(eventID, dayID, req.toService())
 	if err != nil {
 		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
 	}
 
 	return utils.Success(c, action, "Event action added successfully", fiber.StatusCreated)
 }
+
+// UpdateEventAction replaces the details of an event action
+// @Summary Update event action
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param day_id path string true "Event Day ID"
+// @Param action_id path string true "Event Action ID"
+// @Param request body AddEventActionRequest true "Event action data"
+// @Success 200 {object} utils.Response{data=models.EventAction}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/days/{day_id}/actions/{action_id} [put]
+func (h *Handler) UpdateEventAction(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	dayID := c.Params("day_id")
+	if _, err := uuid.Parse(dayID); err != nil {
+		return utils.Error(c, "Invalid day ID", fiber.StatusBadRequest)
+	}
+	actionID := c.Params("action_id")
+	if _, err := uuid.Parse(actionID); err != nil {
+		return utils.Error(c, "Invalid action ID", fiber.StatusBadRequest)
+	}
+
+	var req AddEventActionRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	action, err := h.eventSvc.UpdateEventAction(eventID, dayID, actionID, req.toService())
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEventAction) {
+			return utils.Error(c, "Event action not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, action, "Event action updated successfully")
+}
+
+// DeleteEventAction deactivates an event action
+// @Summary Delete event action
+// @Description The action's code can no longer be scanned; its verification history is kept.
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param day_id path string true "Event Day ID"
+// @Param action_id path string true "Event Action ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/days/{day_id}/actions/{action_id} [delete]
+func (h *Handler) DeleteEventAction(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	dayID := c.Params("day_id")
+	if _, err := uuid.Parse(dayID); err != nil {
+		return utils.Error(c, "Invalid day ID", fiber.StatusBadRequest)
+	}
+	actionID := c.Params("action_id")
+	if _, err := uuid.Parse(actionID); err != nil {
+		return utils.Error(c, "Invalid action ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.eventSvc.DeleteEventAction(eventID, dayID, actionID); err != nil {
+		if errors.Is(err, services.ErrUnknownEventAction) {
+			return utils.Error(c, "Event action not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to delete event action", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "Event action deleted successfully")
+}
+
+// ReorderEventActions sets the display order of a day's actions
+// @Summary Reorder event actions
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param day_id path string true "Event Day ID"
+// @Param request body ReorderEventActionsRequest true "Action order"
+// @Success 200 {object} utils.Response{data=[]models.EventAction}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/days/{day_id}/actions/order [put]
+func (h *Handler) ReorderEventActions(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	dayID := c.Params("day_id")
+	if _, err := uuid.Parse(dayID); err != nil {
+		return utils.Error(c, "Invalid day ID", fiber.StatusBadRequest)
+	}
+
+	var req ReorderEventActionsRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	actions, err := h.eventSvc.ReorderEventActions(eventID, dayID, req.ActionIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownEventDay):
+			return utils.Error(c, "Event day not found", fiber.StatusNotFound)
+		case errors.Is(err, services.ErrActionOrder):
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
+		return utils.Error(c, "Failed to reorder event actions", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, actions, "Event actions reordered successfully")
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"errors"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type UploadEventImageRequest struct {
+	Caption string `form:"caption" validate:"max=300"`
+	// Makes the image the event's banner, replacing the previous one
+	Banner bool `form:"banner"`
+}
+
+type ReorderEventImagesRequest struct {
+	// Every image of the event, first to last
+	ImageIDs []string `json:"image_ids" validate:"required,min=1,dive,uuid"`
+}
+
+// UploadEventImage adds an image to the gallery of an event
+// @Summary Upload event image
+// @Description Appends an image (multipart field "image") to the end of the event's gallery. With banner=true it becomes the banner returned in event responses.
+// @Tags Event Images
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param image formData file true "Image file"
+// @Param caption formData string false "Caption"
+// @Param banner formData bool false "Use as banner"
+// @Success 201 {object} utils.Response{data=models.EventImage}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/images [post]
+func (h *Handler) UploadEventImage(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req UploadEventImageRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil || file == nil {
+		return utils.Error(c, "Image file is required", fiber.StatusBadRequest)
+	}
+	if err := utils.ValidateImageFile(file); err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+	if err := utils.ScanUploadedFile(c.UserContext(), h.scanner, file); err != nil {
+		if errors.Is(err, utils.ErrInfected) {
+			return utils.Error(c, "File rejected by virus scan", fiber.StatusBadRequest)
+		}
+		middleware.GetLogger(c).WithError(err).Error("virus scan failed")
+		return utils.Error(c, "File could not be scanned, try again later", fiber.StatusServiceUnavailable)
+	}
+
+	filename := utils.GenerateUniqueFilename(file.Filename)
+	if err := utils.SaveUploadedFile(file, h.cfg.ImageDir, filename); err != nil {
+		return utils.Error(c, "Failed to save image", fiber.StatusInternalServerError)
+	}
+
+	image, err := h.eventSvc.AddEventImage(eventID, "/images/"+filename, req.Caption, req.Banner)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to add image", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, image, "Image uploaded successfully", fiber.StatusCreated)
+}
+
+// ListEventImages returns the gallery of an event
+// @Summary List event images
+// @Tags Event Images
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response{data=[]models.EventImage}
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/images [get]
+func (h *Handler) ListEventImages(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	images, err := h.eventSvc.ListEventImages(eventID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to retrieve images", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, images, "Images retrieved successfully")
+}
+
+// ReorderEventImages sets the gallery order of an event
+// @Summary Reorder event images
+// @Tags Event Images
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body ReorderEventImagesRequest true "Image order"
+// @Success 200 {object} utils.Response{data=[]models.EventImage}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/images/order [put]
+func (h *Handler) ReorderEventImages(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req ReorderEventImagesRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	images, err := h.eventSvc.ReorderEventImages(eventID, req.ImageIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownEvent):
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		case errors.Is(err, services.ErrImageOrder):
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
+		return utils.Error(c, "Failed to reorder images", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, images, "Images reordered successfully")
+}
+
+// SetBannerImage makes a gallery image the banner of its event
+// @Summary Set banner image
+// @Tags Event Images
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param image_id path string true "Image ID"
+// @Success 200 {object} utils.Response{data=models.EventImage}
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/images/{image_id}/banner [put]
+func (h *Handler) SetBannerImage(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	imageID := c.Params("image_id")
+	if _, err := uuid.Parse(imageID); err != nil {
+		return utils.Error(c, "Invalid image ID", fiber.StatusBadRequest)
+	}
+
+	image, err := h.eventSvc.SetBannerImage(eventID, imageID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEventImage) {
+			return utils.Error(c, "Image not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to set banner", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, image, "Banner set successfully")
+}
+
+// DeleteEventImage removes an image from the gallery of an event
+// @Summary Delete event image
+// @Tags Event Images
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param image_id path string true "Image ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/images/{image_id} [delete]
+func (h *Handler) DeleteEventImage(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	imageID := c.Params("image_id")
+	if _, err := uuid.Parse(imageID); err != nil {
+		return utils.Error(c, "Invalid image ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.eventSvc.DeleteEventImage(eventID, imageID); err != nil {
+		if errors.Is(err, services.ErrUnknownEventImage) {
+			return utils.Error(c, "Image not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to delete image", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "Image deleted successfully")
+}
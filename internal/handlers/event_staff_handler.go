@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ListEventStaff returns the staff users assigned to an event
+// @Summary List event staff
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/staff [get]
+func (h *Handler) ListEventStaff(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	staff, err := h.eventSvc.ListStaff(eventID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to retrieve event staff", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, staff, "Event staff retrieved successfully")
+}
+
+// AssignEventStaff lets a staff user verify and list participants of an event
+// @Summary Assign staff to event
+// @Description Staff users may only verify participants of, and list, the events they are assigned to. Organizers and admins are not restricted.
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param user_id path string true "Staff user ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/staff/{user_id} [put]
+func (h *Handler) AssignEventStaff(c *fiber.Ctx) error {
+	eventID, userID := c.Params("id"), c.Params("user_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		return utils.Error(c, "Invalid user ID", fiber.StatusBadRequest)
+	}
+
+	assignedBy, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	staff, err := h.eventSvc.AssignStaff(eventID, userID, assignedBy)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, staff, "Staff assigned to event")
+}
+
+// UnassignEventStaff takes a staff user off an event
+// @Summary Unassign staff from event
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param user_id path string true "Staff user ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/staff/{user_id} [delete]
+func (h *Handler) UnassignEventStaff(c *fiber.Ctx) error {
+	eventID, userID := c.Params("id"), c.Params("user_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		return utils.Error(c, "Invalid user ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.eventSvc.UnassignStaff(eventID, userID); err != nil {
+		if errors.Is(err, services.ErrStaffNotAssigned) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to unassign staff", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "Staff unassigned from event")
+}
+
+// checkEventAccess returns services.ErrEventAccessDenied when the signed-in
+// staff user is not assigned to the event, or their token was not issued for
+// it
+func (h *Handler) checkEventAccess(c *fiber.Ctx, eventID string) error {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	if claims, err := middleware.GetTokenClaims(c); err == nil {
+		if len(services.IntersectEventScopes(claims.EventIDs, []string{eventID})) == 0 {
+			return services.ErrEventAccessDenied
+		}
+	}
+	role, _ := c.Locals("user_role").(string)
+	return h.eventSvc.CheckEventAccess(userID, role, eventID)
+}
+
+// checkParticipantAccess limits staff users to the participants of the
+// events they are assigned to
+func (h *Handler) checkParticipantAccess(c *fiber.Ctx, participantID string) error {
+	participant, err := h.participantSvc.GetParticipant(participantID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Participant not found")
+	}
+	return h.checkEventAccess(c, participant.EventID.String())
+}
+
+func eventAccessError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, services.ErrEventAccessDenied) {
+		return utils.Error(c, err.Error(), fiber.StatusForbidden)
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return utils.Error(c, fiberErr.Message, fiberErr.Code)
+	}
+	return utils.Error(c, "Failed to check event assignments", fiber.StatusInternalServerError)
+}
+
+// EventAccessMiddleware limits staff users to the events, taken from the
+// :id route parameter, they are assigned to
+func (h *Handler) EventAccessMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		eventID := c.Params("id")
+		if _, err := uuid.Parse(eventID); err != nil {
+			return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+		}
+		if err := h.checkEventAccess(c, eventID); err != nil {
+			return eventAccessError(c, err)
+		}
+		return c.Next()
+	}
+}
@@ -1,11 +1,21 @@
 package handlers
 
 import (
+	"errors"
+	"net/http"
+
+	"event-management-backend/internal/backup"
 	"event-management-backend/internal/config"
+	"event-management-backend/internal/jobs"
+	"event-management-backend/internal/jwtkeys"
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/ratelimit"
+	"event-management-backend/internal/repositories"
 	"event-management-backend/internal/services"
 	"event-management-backend/internal/utils"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 )
 
 type Handler struct {
@@ -13,6 +23,31 @@ type Handler struct {
 	eventSvc       *services.EventService
 	participantSvc *services.ParticipantService
 	verifySvc      services.VerificationService
+	kioskSvc       *services.KioskService
+	drawSvc        *services.DrawService
+	sessionSvc     *services.SessionService
+	speakerSvc     *services.SpeakerService
+	sponsorSvc     *services.SponsorService
+	widgetSvc      *services.WidgetService
+	waiverSvc      *services.WaiverService
+	seatingSvc     *services.SeatingService
+	mealSvc        *services.MealService
+	shiftSvc       *services.ShiftService
+	liveStatsSvc   *services.LiveStatsService
+	onlineSvc      *services.OnlineService
+	notifySvc      *services.NotificationService
+	retentionSvc   *services.RetentionService
+	apiKeySvc      *services.APIKeyService
+	auditSvc       *services.AuditService
+	deviceSvc      *services.DeviceService
+	jobQueue       *jobs.Queue
+	idempotency    repositories.IdempotencyRepository
+	rateLimits     ratelimit.Store
+	graphql        http.Handler
+	scanner        utils.Scanner
+	captcha        utils.CaptchaVerifier
+	backupSvc      *backup.Service
+	jwtKeys        *jwtkeys.Keyset
 	cfg            *config.Config
 }
 
@@ -21,6 +56,29 @@ func NewHandler(
 	eventSvc *services.EventService,
 	participantSvc *services.ParticipantService,
 	verifySvc services.VerificationService,
+	kioskSvc *services.KioskService,
+	drawSvc *services.DrawService,
+	sessionSvc *services.SessionService,
+	speakerSvc *services.SpeakerService,
+	sponsorSvc *services.SponsorService,
+	widgetSvc *services.WidgetService,
+	waiverSvc *services.WaiverService,
+	seatingSvc *services.SeatingService,
+	mealSvc *services.MealService,
+	shiftSvc *services.ShiftService,
+	liveStatsSvc *services.LiveStatsService,
+	onlineSvc *services.OnlineService,
+	notifySvc *services.NotificationService,
+	retentionSvc *services.RetentionService,
+	apiKeySvc *services.APIKeyService,
+	auditSvc *services.AuditService,
+	deviceSvc *services.DeviceService,
+	jobQueue *jobs.Queue,
+	idempotency repositories.IdempotencyRepository,
+	rateLimits ratelimit.Store,
+	graphql http.Handler,
+	backupSvc *backup.Service,
+	jwtKeys *jwtkeys.Keyset,
 	cfg *config.Config,
 ) *Handler {
 	return &Handler{
@@ -28,60 +86,299 @@ func NewHandler(
 		eventSvc:       eventSvc,
 		participantSvc: participantSvc,
 		verifySvc:      verifySvc,
+		kioskSvc:       kioskSvc,
+		drawSvc:        drawSvc,
+		sessionSvc:     sessionSvc,
+		speakerSvc:     speakerSvc,
+		sponsorSvc:     sponsorSvc,
+		widgetSvc:      widgetSvc,
+		waiverSvc:      waiverSvc,
+		seatingSvc:     seatingSvc,
+		mealSvc:        mealSvc,
+		shiftSvc:       shiftSvc,
+		liveStatsSvc:   liveStatsSvc,
+		onlineSvc:      onlineSvc,
+		notifySvc:      notifySvc,
+		retentionSvc:   retentionSvc,
+		apiKeySvc:      apiKeySvc,
+		auditSvc:       auditSvc,
+		deviceSvc:      deviceSvc,
+		jobQueue:       jobQueue,
+		idempotency:    idempotency,
+		rateLimits:     rateLimits,
+		graphql:        graphql,
+		backupSvc:      backupSvc,
+		jwtKeys:        jwtKeys,
+		scanner:        utils.NewScanner(cfg.ClamAVAddr, cfg.ClamAVTimeout),
+		captcha:        utils.NewCaptchaVerifier(cfg.CaptchaProvider, cfg.CaptchaSecret, cfg.CaptchaTimeout),
 		cfg:            cfg,
 	}
 }
 
 func (h *Handler) RegisterRoutes(router fiber.Router) {
+	// Replays responses for retried mutating requests carrying Idempotency-Key
+	idempotent := middleware.Idempotency(h.idempotency, h.cfg.IdempotencyTTL)
+
+	// Per-IP limits against password guessing, registration spam and ticket
+	// code guessing
+	authLimit := middleware.RateLimit(h.rateLimits, "auth", ratelimit.PerMinute(h.cfg.RateLimitAuthPerMinute, h.cfg.RateLimitAuthBurst))
+	registerLimit := middleware.RateLimit(h.rateLimits, "register", ratelimit.PerMinute(h.cfg.RateLimitRegisterPerMinute, h.cfg.RateLimitRegisterBurst))
+	ticketLookupLimit := middleware.RateLimit(h.rateLimits, "ticket-lookup", ratelimit.PerMinute(h.cfg.RateLimitRegisterPerMinute, h.cfg.RateLimitRegisterBurst))
+	// Per participant, so staff cannot flood anyone's inbox
+	resendLimit := middleware.RateLimitBy(h.rateLimits, "resend-ticket",
+		ratelimit.PerHour(h.cfg.RateLimitResendTicketPerHour, h.cfg.RateLimitResendTicketPerHour),
+		func(c *fiber.Ctx) string { return c.Params("id") })
+
 	// Public routes
-	public := router.Group("/auth")
+	public := router.Group("/auth", authLimit)
 	{
 		public.Post("/login", h.Login)
+		public.Post("/refresh", h.RefreshToken)
 		public.Post("/register", h.RegisterUser)
+		public.Post("/logout", h.AuthMiddleware(), h.Logout)
 	}
 
-	// Event public routes
+	// Event public routes. Caching is attached per route because group
+	// middleware would also apply to the protected /events routes below.
+	cache := middleware.PublicCache(h.cfg.PublicCacheMaxAge)
 	events := router.Group("/events")
 	{
-		events.Get("/", h.ListEvents)
-		events.Get("/:id", h.GetEvent)
-		events.Get("/slug/:slug", h.GetEventBySlug)
+		events.Get("/", cache, h.ListEvents)
+		events.Get("/:id", cache, h.GetEvent)
+		events.Get("/slug/:slug", cache, h.GetEventBySlug)
+		events.Get("/slug/:slug/full", cache, h.GetEventLanding)
+		events.Get("/:id/ticket-types", cache, h.ListTicketTypes)
+		events.Get("/:id/images", cache, h.ListEventImages)
 	}
 
 	// Participant public registration
-	router.Post("/register", h.RegisterParticipant)
+	router.Post("/register", registerLimit, idempotent, h.RegisterParticipant)
+	router.Post("/register/group", registerLimit, idempotent, h.RegisterGroup)
+	router.Get("/register/confirm/:token", h.ConfirmRegistration)
+
+	// Participants finding their own ticket
+	router.Post("/tickets/lookup", ticketLookupLimit, h.LookupTicket)
+
+	// Cancelling a registration, by staff or by the participant with their
+	// ticket code. Only the latter count against the ticket code limit.
+	router.Post("/participants/:id/cancel", h.OptionalAuthMiddleware(), func(c *fiber.Ctx) error {
+		if c.Locals("user_role") != nil {
+			return c.Next()
+		}
+		return ticketLookupLimit(c)
+	}, idempotent, h.CancelParticipant)
+
+	// Embeddable registration widget, authorized by the event's public key
+	// and widget origins
+	widget := router.Group("/public/widget/:slug", h.WidgetMiddleware())
+	{
+		widget.Get("/", h.GetWidget)
+		widget.Post("/register", registerLimit, idempotent, h.WidgetRegister)
+	}
+
+	// Live counters for event websites and big screens, for events that
+	// opted in
+	router.Get("/public/events/:slug/live", middleware.PublicCache(h.cfg.LiveStatsCacheTTL), h.GetLiveStats)
+
+	// Personal join links of online events, and attendance reports pushed by
+	// meeting providers
+	router.Get("/online/join/:token", h.JoinOnline)
+	router.Post("/webhooks/online-attendance", h.OnlineAttendanceWebhook)
+
+	// Waiver signing, addressed by the participant ID returned on registration
+	waivers := router.Group("/waivers")
+	{
+		waivers.Get("/:participant_id", h.GetParticipantWaivers)
+		waivers.Post("/:participant_id/sign", idempotent, h.SignWaiver)
+	}
+
+	// Lobby screens, authenticated by their kiosk token
+	kiosk := router.Group("/kiosk")
+	{
+		kiosk.Get("/:token", h.GetKioskDisplay)
+		kiosk.Get("/:token/stream", h.StreamKioskDisplay)
+	}
+
+	// Machine integrations such as ticket kiosks and partner registration
+	// sites, authenticated by X-API-Key and limited to the key's events
+	integrations := router.Group("/integrations", h.APIKeyMiddleware())
+	{
+		integrations.Post("/register", h.APIKeyScopeMiddleware(services.ScopeRegistration), idempotent, h.IntegrationRegister)
+		integrations.Post("/verify", h.APIKeyScopeMiddleware(services.ScopeVerification), idempotent, h.IntegrationVerify)
+	}
+
+	// Verification (Staff or above, and scanner service accounts). It is
+	// registered before the protected routes, whose authentication rejects
+	// service tokens.
+	verification := router.Group("/verify", h.VerifierAuthMiddleware(), h.VerifierMiddleware())
+	{
+		verification.Post("/", idempotent, h.VerifyAction)
+	}
+	router.Post("/devices/register", h.VerifierAuthMiddleware(), h.VerifierMiddleware(), h.RegisterDevice)
+	router.Get("/participants/:id/status", h.VerifierAuthMiddleware(), h.VerifierMiddleware(), h.GetParticipantStatus)
 
 	// Protected routes (JWT required)
 	protected := router.Group("", h.AuthMiddleware())
 	{
 		// User profile
 		protected.Get("/profile", h.GetProfile)
+		protected.Put("/profile/password", h.ChangePassword)
+		protected.Post("/profile/2fa/setup", h.SetupTOTP)
+		protected.Post("/profile/2fa/confirm", h.ConfirmTOTP)
+		protected.Get("/me/shifts", h.StaffOrAboveMiddleware(), h.GetMyShifts)
+
+		// Event listings, also open to staff assigned to the event. They are
+		// registered before the organizer group, whose middleware covers all
+		// of /events.
+		eventsStaff := protected.Group("/events")
+		{
+			eventsStaff.Get("/:id/participants", h.StaffOrAboveMiddleware(), h.EventAccessMiddleware(), h.ListParticipants)
+			eventsStaff.Get("/:id/participants/duplicates", h.StaffOrAboveMiddleware(), h.EventAccessMiddleware(), h.FindDuplicateParticipants)
+			eventsStaff.Get("/:id/verifications", h.StaffOrAboveMiddleware(), h.EventAccessMiddleware(), h.GetEventVerifications)
+			eventsStaff.Get("/:id/days/attendance", h.StaffOrAboveMiddleware(), h.EventAccessMiddleware(), h.GetDayAttendance)
+		}
 
 		// Event management (Admin/Organizer only)
 		eventsAdmin := protected.Group("/events")
 		eventsAdmin.Use(h.OrganizerOrAdminMiddleware())
 		{
 			eventsAdmin.Post("/", h.CreateEvent)
+			eventsAdmin.Put("/:id", h.UpdateEvent)
+			eventsAdmin.Delete("/:id", h.DeleteEvent)
+			eventsAdmin.Post("/:id/publish", h.PublishEvent)
+			eventsAdmin.Post("/:id/cancel", h.CancelEvent)
+			eventsAdmin.Post("/:id/archive", h.ArchiveEvent)
+			eventsAdmin.Post("/import", h.ImportEvent)
+			eventsAdmin.Get("/:id/export.json", h.ExportEvent)
+			eventsAdmin.Get("/:id/revisions", h.GetEventRevisions)
+			eventsAdmin.Get("/:id/participants/export.csv", h.ExportParticipantsCSV)
+			eventsAdmin.Get("/:id/participants/export.xlsx", h.ExportParticipantsXLSX)
+			eventsAdmin.Get("/:id/badges.pdf", h.GetBadgeSheet)
 			eventsAdmin.Post("/:id/days", h.AddEventDay)
+			eventsAdmin.Post("/:id/days/bulk", h.BulkAddEventDays)
 			eventsAdmin.Post("/:id/days/:day_id/actions", h.AddEventAction)
-			eventsAdmin.Get("/:id/participants", h.ListParticipants)
-			eventsAdmin.Get("/:id/verifications", h.GetEventVerifications)
+			eventsAdmin.Put("/:id/days/:day_id/actions/order", h.ReorderEventActions)
+			eventsAdmin.Put("/:id/days/:day_id/actions/:action_id", h.UpdateEventAction)
+			eventsAdmin.Delete("/:id/days/:day_id/actions/:action_id", h.DeleteEventAction)
+			eventsAdmin.Post("/:id/ticket-types", h.CreateTicketType)
+			eventsAdmin.Put("/:id/ticket-types/:ticket_type_id", h.UpdateTicketType)
+			eventsAdmin.Delete("/:id/ticket-types/:ticket_type_id", h.DeleteTicketType)
+			eventsAdmin.Get("/:id/quota-rules", h.ListQuotaRules)
+			eventsAdmin.Post("/:id/quota-rules", h.CreateQuotaRule)
+			eventsAdmin.Put("/:id/quota-rules/:rule_id", h.UpdateQuotaRule)
+			eventsAdmin.Delete("/:id/quota-rules/:rule_id", h.DeleteQuotaRule)
+			eventsAdmin.Post("/:id/images", h.UploadEventImage)
+			eventsAdmin.Put("/:id/images/order", h.ReorderEventImages)
+			eventsAdmin.Put("/:id/images/:image_id/banner", h.SetBannerImage)
+			eventsAdmin.Delete("/:id/images/:image_id", h.DeleteEventImage)
+			eventsAdmin.Get("/:id/waitlist", h.GetWaitlist)
+			eventsAdmin.Get("/:id/registrations", h.ListRegistrationsForReview)
+			eventsAdmin.Post("/:id/registrations/approve", idempotent, h.ApproveRegistrations)
+			eventsAdmin.Post("/:id/registrations/reject", idempotent, h.RejectRegistrations)
+			eventsAdmin.Post("/:id/participants/tags", idempotent, h.TagParticipants)
+			eventsAdmin.Get("/:id/staff", h.ListEventStaff)
+			eventsAdmin.Put("/:id/staff/:user_id", h.AssignEventStaff)
+			eventsAdmin.Delete("/:id/staff/:user_id", h.UnassignEventStaff)
+			eventsAdmin.Post("/:id/kiosk-token", h.IssueKioskToken)
+			eventsAdmin.Delete("/:id/kiosk-token", h.RevokeKioskToken)
+			eventsAdmin.Post("/:id/widget-key", h.IssueWidgetKey)
+			eventsAdmin.Delete("/:id/widget-key", h.RevokeWidgetKey)
+			eventsAdmin.Put("/:id/live-stats", h.EnableLiveStats)
+			eventsAdmin.Delete("/:id/live-stats", h.DisableLiveStats)
+			eventsAdmin.Get("/:id/draws", h.ListDraws)
+			eventsAdmin.Post("/:id/draws", idempotent, h.CreateDraw)
+			eventsAdmin.Get("/:id/draws/:draw_id/verify", h.VerifyDraw)
+			eventsAdmin.Get("/:id/sessions", h.ListSessions)
+			eventsAdmin.Post("/:id/sessions", h.CreateSession)
+			eventsAdmin.Get("/:id/sessions/:session_id/attendance", h.GetSessionAttendance)
+			eventsAdmin.Get("/:id/speakers", h.ListSpeakers)
+			eventsAdmin.Post("/:id/speakers", h.CreateSpeaker)
+			eventsAdmin.Get("/:id/speakers/:speaker_id", h.GetSpeaker)
+			eventsAdmin.Get("/:id/sponsors", h.ListSponsors)
+			eventsAdmin.Post("/:id/sponsors", h.CreateSponsor)
+			eventsAdmin.Post("/:id/sponsors/:sponsor_id/staff", h.CreateSponsorStaff)
+			eventsAdmin.Get("/:id/sponsors/:sponsor_id/leads", h.GetSponsorLeads)
+			eventsAdmin.Get("/:id/waivers", h.ListWaivers)
+			eventsAdmin.Post("/:id/waivers", h.CreateWaiver)
+			eventsAdmin.Delete("/:id/waivers/:waiver_id", h.DeactivateWaiver)
+			eventsAdmin.Get("/:id/waivers/signatures", h.GetSignedWaivers)
+			eventsAdmin.Get("/:id/waivers/signatures/:signature_id/image", h.GetWaiverSignatureImage)
+			eventsAdmin.Get("/:id/days/:day_id/tables", h.ListTables)
+			eventsAdmin.Post("/:id/days/:day_id/tables", h.CreateTable)
+			eventsAdmin.Get("/:id/days/:day_id/seating", h.GetDaySeating)
+			eventsAdmin.Post("/:id/days/:day_id/seating/auto-assign", h.AutoAssignSeats)
+			eventsAdmin.Put("/:id/days/:day_id/seating/:participant_id", h.AssignSeat)
+			eventsAdmin.Delete("/:id/days/:day_id/seating/:participant_id", h.UnassignSeat)
+			eventsAdmin.Get("/:id/seating/badges", h.GetBadgeSeats)
+			eventsAdmin.Get("/:id/meals", h.ListMealCoupons)
+			eventsAdmin.Post("/:id/meals", h.CreateMealCoupons)
+			eventsAdmin.Get("/:id/meals/report", h.GetMealReport)
+			eventsAdmin.Get("/:id/shifts", h.ListShifts)
+			eventsAdmin.Post("/:id/shifts", h.CreateShift)
+			eventsAdmin.Delete("/:id/shifts/:shift_id", h.DeleteShift)
+			eventsAdmin.Put("/:id/shifts/:shift_id/staff/:user_id", h.AssignShiftStaff)
+			eventsAdmin.Delete("/:id/shifts/:shift_id/staff/:user_id", h.UnassignShiftStaff)
+			eventsAdmin.Get("/:id/online/meetings", h.ListMeetings)
+			eventsAdmin.Post("/:id/online/meetings", h.CreateMeeting)
+			eventsAdmin.Delete("/:id/online/meetings/:meeting_id", h.DeleteMeeting)
+			eventsAdmin.Post("/:id/online/meetings/:meeting_id/attendance", idempotent, h.ImportMeetingAttendance)
+			eventsAdmin.Post("/:id/online/join-links", idempotent, h.SendJoinLinks)
+			eventsAdmin.Get("/:id/online/attendance", h.GetAttendanceSummary)
+		}
+
+		// Waitlist entries of full events (Admin/Organizer only)
+		waitlist := protected.Group("/waitlist")
+		waitlist.Use(h.OrganizerOrAdminMiddleware())
+		{
+			waitlist.Patch("/:id", h.UpdateWaitlistEntry)
+			waitlist.Post("/:id/promote", idempotent, h.PromoteWaitlistEntry)
+		}
+
+		// Venues events and days are held at (Admin/Organizer only)
+		venues := protected.Group("/venues")
+		venues.Use(h.OrganizerOrAdminMiddleware())
+		{
+			venues.Get("/", h.ListVenues)
+			venues.Post("/", h.CreateVenue)
+			venues.Get("/:id", h.GetVenue)
+			venues.Put("/:id", h.UpdateVenue)
+			venues.Delete("/:id", h.DeleteVenue)
 		}
 
+		// GraphQL read models for dashboards (Admin/Organizer only)
+		protected.All("/graphql", h.OrganizerOrAdminMiddleware(), adaptor.HTTPHandler(h.graphql))
+
 		// Participant management
 		participants := protected.Group("/participants")
 		participants.Use(h.StaffOrAboveMiddleware())
 		{
 			participants.Post("/import", h.ImportParticipants)
-			participants.Patch("/:id/payment-status", h.UpdatePaymentStatus)
+			participants.Get("/import/:job_id", h.GetImportJob)
+			participants.Get("/import/:job_id/errors.csv", h.GetImportErrorReport)
+			participants.Patch("/:id/payment-status", idempotent, h.UpdatePaymentStatus)
+			participants.Get("/:id", h.GetParticipant)
+			participants.Get("/:id/notes", h.ListParticipantNotes)
+			participants.Post("/:id/notes", idempotent, h.AddParticipantNote)
 			participants.Get("/:id/verifications", h.GetParticipantVerifications)
+			participants.Get("/:id/data-export", h.AdminOnlyMiddleware(), h.ExportParticipantData)
+			participants.Post("/:id/anonymize", h.AdminOnlyMiddleware(), idempotent, h.AnonymizeParticipant)
+			participants.Post("/:id/approve", h.OrganizerOrAdminMiddleware(), idempotent, h.ApproveParticipant)
+			participants.Post("/:id/reject", h.OrganizerOrAdminMiddleware(), idempotent, h.RejectParticipant)
+			participants.Post("/:id/merge", idempotent, h.MergeParticipants)
+			participants.Post("/:id/transfer", idempotent, h.TransferParticipant)
+			participants.Post("/:id/credentials/reissue", idempotent, h.ReissueCredential)
+			participants.Post("/:id/regenerate-qr", idempotent, h.RegenerateQR)
+			participants.Post("/:id/resend-ticket", resendLimit, h.ResendTicket)
+			participants.Get("/:id/ticket.pdf", h.GetTicketPDF)
+			participants.Get("/:id/credentials/incidents", h.ListCredentialIncidents)
 		}
 
-		// Verification (Staff or above)
-		verification := protected.Group("/verify")
-		verification.Use(h.StaffOrAboveMiddleware())
+		// Lead capture (Sponsor booth staff only)
+		leads := protected.Group("/leads")
+		leads.Use(h.SponsorOnlyMiddleware())
 		{
-			verification.Post("/", h.VerifyAction)
+			leads.Post("/", idempotent, h.CaptureLead)
+			leads.Get("/", h.ListMyLeads)
 		}
 
 		// Admin only routes
@@ -89,7 +386,40 @@ func (h *Handler) RegisterRoutes(router fiber.Router) {
 		admin.Use(h.AdminOnlyMiddleware())
 		{
 			admin.Get("/stats", h.GetStats)
+			admin.Get("/users", h.ListUsers)
+			admin.Get("/events", h.ListAllEvents)
+			admin.Post("/events/import", h.ImportEventBundle)
+			admin.Get("/events/:id/export", h.ExportEventBundle)
 			admin.Post("/users", h.CreateUser)
+			admin.Put("/users/:id", h.UpdateUser)
+			admin.Delete("/users/:id", h.DeactivateUser)
+			admin.Post("/users/:id/delete", h.DeleteUser)
+			admin.Get("/role-changes", h.ListRoleChanges)
+			admin.Post("/role-changes", h.RequestRoleChange)
+			admin.Post("/role-changes/:id/approve", h.ApproveRoleChange)
+			admin.Post("/role-changes/:id/reject", h.RejectRoleChange)
+			admin.Post("/users/:id/revoke-tokens", h.RevokeUserTokens)
+			admin.Get("/users/:id/activity", h.GetUserActivity)
+			admin.Post("/service-accounts", h.CreateServiceAccount)
+			admin.Get("/service-accounts/:id/tokens", h.ListServiceTokens)
+			admin.Post("/service-accounts/:id/tokens", h.IssueServiceToken)
+			admin.Delete("/service-accounts/:id/tokens/:token_id", h.RevokeServiceToken)
+			admin.Get("/api-keys", h.ListAPIKeys)
+			admin.Post("/api-keys", h.CreateAPIKey)
+			admin.Delete("/api-keys/:id", h.RevokeAPIKey)
+			admin.Get("/devices", h.ListDevices)
+			admin.Get("/jobs", h.ListJobs)
+			admin.Get("/audit-logs", h.ListAuditLogs)
+			admin.Get("/backups", h.ListBackups)
+			admin.Post("/backups", h.CreateBackup)
+			admin.Get("/retention/rules", h.ListRetentionRules)
+			admin.Post("/retention/rules", h.CreateRetentionRule)
+			admin.Delete("/retention/rules/:id", h.DeleteRetentionRule)
+			admin.Get("/retention/preview", h.PreviewRetention)
+			admin.Post("/retention/run", h.RunRetention)
+			admin.Get("/legal-holds", h.ListLegalHolds)
+			admin.Put("/legal-holds/:event_id", h.PlaceLegalHold)
+			admin.Delete("/legal-holds/:event_id", h.ReleaseLegalHold)
 		}
 	}
 }
@@ -108,22 +438,63 @@ func ErrorHandler(c *fiber.Ctx, err error) error {
 
 	// Log internal errors
 	if code >= 500 {
-		// You can log to your logging service here
-		println("Internal Error:", err.Error())
+		middleware.GetLogger(c).WithError(err).Error("internal server error")
 	}
 
 	return utils.Error(c, message, code)
 }
 
+// cursorParam reports whether the request asked for cursor pagination.
+// An empty `?cursor=` requests the first page in cursor mode.
+func cursorParam(c *fiber.Ctx) (string, bool) {
+	if !c.Context().QueryArgs().Has("cursor") {
+		return "", false
+	}
+	return c.Query("cursor"), true
+}
+
 // Auth middleware
 func (h *Handler) AuthMiddleware() fiber.Handler {
+	return middleware.JWTMiddleware(h.jwtKeys, func(c *fiber.Ctx) error {
+		if c.Locals("user_role") == services.RoleScanner {
+			return utils.Error(c, "Service tokens can only be used for verification", fiber.StatusForbidden)
+		}
+		return h.checkToken(c)
+	})
+}
+
+// OptionalAuthMiddleware authenticates requests carrying a bearer token like
+// AuthMiddleware and lets the others through anonymously
+func (h *Handler) OptionalAuthMiddleware() fiber.Handler {
+	auth := h.AuthMiddleware()
 	return func(c *fiber.Ctx) error {
-		userID := c.Locals("user_id")
-		if userID == nil {
-			return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+		if c.Get(fiber.HeaderAuthorization) == "" {
+			return c.Next()
 		}
-		return c.Next()
+		return auth(c)
+	}
+}
+
+// VerifierAuthMiddleware authenticates like AuthMiddleware, but also accepts
+// the service tokens of scanner devices
+func (h *Handler) VerifierAuthMiddleware() fiber.Handler {
+	return middleware.JWTMiddleware(h.jwtKeys, h.checkToken)
+}
+
+// checkToken rejects revoked tokens and tokens of deactivated users
+func (h *Handler) checkToken(c *fiber.Ctx) error {
+	claims, err := middleware.GetTokenClaims(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	if err := h.authSvc.ValidateToken(claims.UserID, claims.JTI, claims.IssuedAt); err != nil {
+		if errors.Is(err, services.ErrTokenRevoked) || errors.Is(err, services.ErrUserDeactivated) || errors.Is(err, services.ErrUserNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusUnauthorized)
+		}
+		return utils.Error(c, "Failed to check token", fiber.StatusInternalServerError)
 	}
+	return c.Next()
 }
 
 // Role-based middlewares
@@ -147,6 +518,27 @@ func (h *Handler) OrganizerOrAdminMiddleware() fiber.Handler {
 	}
 }
 
+func (h *Handler) SponsorOnlyMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userRole := c.Locals("user_role")
+		if userRole != services.RoleSponsor {
+			return utils.Error(c, "Sponsor staff access required", fiber.StatusForbidden)
+		}
+		return c.Next()
+	}
+}
+
+// VerifierMiddleware admits staff or above and scanner service accounts
+func (h *Handler) VerifierMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userRole := c.Locals("user_role")
+		if userRole != "admin" && userRole != "organizer" && userRole != "staff" && userRole != services.RoleScanner {
+			return utils.Error(c, "Staff or above access required", fiber.StatusForbidden)
+		}
+		return c.Next()
+	}
+}
+
 func (h *Handler) StaffOrAboveMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		userRole := c.Locals("user_role")
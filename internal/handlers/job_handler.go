@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"strconv"
+
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListJobs returns background jobs and their status
+// @Summary List background jobs
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status (pending, running, completed, failed)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /admin/jobs [get]
+func (h *Handler) ListJobs(c *fiber.Ctx) error {
+	status := c.Query("status")
+	allowedStatus := map[string]bool{"": true, "pending": true, "running": true, "completed": true, "failed": true}
+	if !allowedStatus[status] {
+		return utils.Error(c, "Invalid status filter", fiber.StatusBadRequest)
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
+
+	jobList, total, totalPages, err := h.jobQueue.ListJobs(status, page, pageSize)
+	if err != nil {
+		return utils.Error(c, "Failed to fetch jobs", fiber.StatusInternalServerError)
+	}
+
+	meta := &utils.Meta{
+		Page:      page,
+		PageSize:  pageSize,
+		Total:     total,
+		TotalPage: totalPages,
+	}
+
+	return utils.SuccessWithMeta(c, jobList, meta, "Jobs retrieved successfully")
+}
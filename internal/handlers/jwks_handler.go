@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegisterWellKnownRoutes serves the public keys of the JWT signing keys at
+// the standard location, outside the versioned API
+func (h *Handler) RegisterWellKnownRoutes(router fiber.Router) {
+	router.Get("/.well-known/jwks.json", h.GetJWKS)
+}
+
+// GetJWKS returns the JSON Web Key Set other services verify our tokens
+// with. Previous keys are listed until they are removed from the
+// configuration, so verifiers that cache the set keep working across a
+// rotation. The set is empty while tokens are signed with JWT_SECRET.
+func (h *Handler) GetJWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(h.jwtKeys.JWKS())
+}
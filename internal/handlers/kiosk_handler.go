@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// kioskKeepAlive is how long a stream may stay silent before a comment line
+// is sent, so proxies do not drop idle connections
+const kioskKeepAlive = 15 * time.Second
+
+type IssueKioskTokenRequest struct {
+	Message string `json:"message" validate:"max=280"`
+}
+
+// IssueKioskToken creates the token a lobby screen uses to display an event
+// @Summary Issue kiosk token
+// @Description Creates a new kiosk display token, revoking the previous one. The token is only shown once.
+// @Tags Kiosk
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body IssueKioskTokenRequest true "Welcome message"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/kiosk-token [post]
+func (h *Handler) IssueKioskToken(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req IssueKioskTokenRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	token, err := h.kioskSvc.IssueToken(eventID, req.Message)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	result := fiber.Map{
+		"token":      token,
+		"stream_url": fmt.Sprintf("/api/%s/kiosk/%s/stream", middleware.GetAPIVersion(c), token),
+	}
+
+	return utils.Success(c, result, "Kiosk token issued", fiber.StatusCreated)
+}
+
+// RevokeKioskToken disconnects the lobby screens of an event
+// @Summary Revoke kiosk token
+// @Tags Kiosk
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/kiosk-token [delete]
+func (h *Handler) RevokeKioskToken(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.kioskSvc.RevokeToken(eventID); err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, nil, "Kiosk token revoked")
+}
+
+// GetKioskDisplay returns the current lobby screen state
+// @Summary Get kiosk display
+// @Tags Kiosk
+// @Produce json
+// @Param token path string true "Kiosk token"
+// @Success 200 {object} utils.Response{data=services.KioskSnapshot}
+// @Failure 401 {object} utils.Response
+// @Router /kiosk/{token} [get]
+func (h *Handler) GetKioskDisplay(c *fiber.Ctx) error {
+	snapshot, err := h.kioskSvc.Snapshot(c.Params("token"))
+	if err != nil {
+		return kioskError(c, err)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return utils.Success(c, snapshot, "Kiosk display retrieved successfully")
+}
+
+// StreamKioskDisplay pushes the lobby screen state as server-sent events. A
+// "snapshot" event is sent on connect and whenever a check-in changes the
+// display; a "revoked" event ends the stream when the token is revoked.
+// @Summary Stream kiosk display
+// @Tags Kiosk
+// @Produce text/event-stream
+// @Param token path string true "Kiosk token"
+// @Success 200 {string} string "SSE stream of snapshot events"
+// @Failure 401 {object} utils.Response
+// @Router /kiosk/{token}/stream [get]
+func (h *Handler) StreamKioskDisplay(c *fiber.Ctx) error {
+	token := c.Params("token")
+	snapshot, err := h.kioskSvc.Snapshot(token)
+	if err != nil {
+		return kioskError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	// The writer runs after the handler returns, when c may already be
+	// reused, so everything it needs is captured here
+	log := middleware.GetLogger(c)
+	interval := h.cfg.KioskRefreshInterval
+	kioskSvc := h.kioskSvc
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var last []byte
+		lastWrite := time.Now()
+
+		fmt.Fprintf(w, "retry: %d\n\n", interval.Milliseconds())
+		for {
+			if snapshot != nil {
+				data, err := json.Marshal(snapshot)
+				if err != nil {
+					log.WithError(err).Error("failed to encode kiosk snapshot")
+					return
+				}
+				if string(data) != string(last) {
+					fmt.Fprintf(w, "event: snapshot\ndata: %s\n\n", data)
+					last, lastWrite = data, time.Now()
+				}
+			}
+			if time.Since(lastWrite) >= kioskKeepAlive {
+				fmt.Fprint(w, ": keep-alive\n\n")
+				lastWrite = time.Now()
+			}
+
+			// Flush fails once the screen disconnects
+			if err := w.Flush(); err != nil {
+				return
+			}
+
+			<-ticker.C
+			snapshot, err = kioskSvc.Snapshot(token)
+			if errors.Is(err, services.ErrInvalidKioskToken) {
+				fmt.Fprint(w, "event: revoked\ndata: {}\n\n")
+				_ = w.Flush()
+				return
+			}
+			if err != nil {
+				// Keep the screen connected through transient database errors
+				log.WithError(err).Warn("failed to refresh kiosk display")
+				snapshot = nil
+			}
+		}
+	})
+
+	return nil
+}
+
+func kioskError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, services.ErrInvalidKioskToken) {
+		return utils.Error(c, "Invalid kiosk token", fiber.StatusUnauthorized)
+	}
+	middleware.GetLogger(c).WithError(err).Error("failed to load kiosk display")
+	return utils.Error(c, "Failed to load kiosk display", fiber.StatusInternalServerError)
+}
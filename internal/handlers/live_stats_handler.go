@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// EnableLiveStats publishes the live counters of an event
+// @Summary Enable public live stats
+// @Description Publishes check-in and session counters at /public/events/{slug}/live. No participant data is exposed.
+// @Tags Live Stats
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/live-stats [put]
+func (h *Handler) EnableLiveStats(c *fiber.Ctx) error {
+	return h.setLiveStatsPublic(c, true, "Live stats enabled")
+}
+
+// DisableLiveStats takes the live counters of an event offline
+// @Summary Disable public live stats
+// @Tags Live Stats
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/live-stats [delete]
+func (h *Handler) DisableLiveStats(c *fiber.Ctx) error {
+	return h.setLiveStatsPublic(c, false, "Live stats disabled")
+}
+
+func (h *Handler) setLiveStatsPublic(c *fiber.Ctx, public bool, message string) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.liveStatsSvc.SetPublic(eventID, public); err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, nil, message)
+}
+
+// GetLiveStats returns the live counters of an event for its website or big
+// screens. Responses are cached for LIVE_STATS_CACHE_TTL.
+// @Summary Get public live stats
+// @Tags Live Stats
+// @Produce json
+// @Param slug path string true "Event slug"
+// @Success 200 {object} utils.Response{data=services.LiveStats}
+// @Failure 404 {object} utils.Response
+// @Router /public/events/{slug}/live [get]
+func (h *Handler) GetLiveStats(c *fiber.Ctx) error {
+	stats, err := h.liveStatsSvc.Get(c.Params("slug"))
+	if err != nil {
+		if errors.Is(err, services.ErrLiveStatsNotFound) {
+			return utils.Error(c, "Live stats not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to retrieve live stats", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, stats, "Live stats retrieved successfully")
+}
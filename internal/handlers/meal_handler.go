@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CreateMealCouponsRequest struct {
+	Meal string `json:"meal" validate:"required,max=100"`
+	// Every day of the event when omitted
+	DayIDs []string `json:"day_ids" validate:"omitempty,dive,uuid"`
+	// Meals prepared per preference; defaults to one per approved participant
+	// who chose it
+	Quotas map[string]int `json:"quotas"`
+}
+
+// CreateMealCoupons creates the meal coupon actions of an event
+// @Summary Create meal coupons
+// @Description Creates one coupon action per day and meal preference. Catering scans only serve participants of the coupon's preference, up to its quota.
+// @Tags Meals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body CreateMealCouponsRequest true "Meal"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/meals [post]
+func (h *Handler) CreateMealCoupons(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req CreateMealCouponsRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	coupons, err := h.mealSvc.CreateMealCoupons(eventID, services.CreateMealCouponsRequest{
+		Meal:   req.Meal,
+		DayIDs: req.DayIDs,
+		Quotas: req.Quotas,
+	})
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, coupons, "Meal coupons created successfully", fiber.StatusCreated)
+}
+
+// ListMealCoupons returns the meal coupons of an event
+// @Summary List meal coupons
+// @Tags Meals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/meals [get]
+func (h *Handler) ListMealCoupons(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	coupons, err := h.mealSvc.ListMealCoupons(eventID)
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve meal coupons", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, coupons, "Meal coupons retrieved successfully")
+}
+
+// GetMealReport returns the meals served by coupon and by preference
+// @Summary Meal consumption report
+// @Description Meals served against each coupon's quota, with totals per preference. Use format=csv to export the coupons.
+// @Tags Meals
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/meals/report [get]
+func (h *Handler) GetMealReport(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	report, err := h.mealSvc.MealReport(eventID)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusNotFound)
+	}
+
+	if c.Query("format") != "csv" {
+		return utils.Success(c, report, "Meal report retrieved successfully")
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"meal", "day", "preference", "action_code", "quota", "served"})
+	for _, row := range report.Coupons {
+		quota := ""
+		if row.Quota != nil {
+			quota = strconv.Itoa(*row.Quota)
+		}
+		w.Write([]string{
+			row.Meal,
+			row.DayLabel,
+			row.Preference,
+			row.ActionCode,
+			quota,
+			strconv.FormatInt(row.Served, 10),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return utils.Error(c, "Failed to export meal report", fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="event-%s-meals.csv"`, eventID))
+	return c.Send(buf.Bytes())
+}
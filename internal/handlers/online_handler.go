@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"event-management-backend/internal/jobs"
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CreateMeetingRequest struct {
+	// Action online attendance is recorded under; on hybrid events, the
+	// action scanned at the venue counts both audiences together
+	ActionID string `json:"action_id" validate:"required,uuid"`
+	Provider string `json:"provider" validate:"required,oneof=zoom meet other"`
+	JoinURL  string `json:"join_url" validate:"required,url,max=2000"`
+	// Meeting or webinar ID at the provider, sent with attendance webhooks
+	ExternalID string `json:"external_id" validate:"max=100"`
+	// Minutes a participant must attend to be counted
+	MinMinutes int `json:"min_minutes" validate:"gte=0"`
+}
+
+type AttendanceReportEntry struct {
+	// Token of the participant's join link, when the provider reports it
+	JoinToken       string    `json:"join_token" validate:"max=200"`
+	Email           string    `json:"email" validate:"omitempty,email"`
+	JoinedAt        time.Time `json:"joined_at"`
+	DurationMinutes int       `json:"duration_minutes" validate:"gte=0"`
+}
+
+type AttendanceReportRequest struct {
+	Attendees []AttendanceReportEntry `json:"attendees" validate:"required,max=10000,dive"`
+}
+
+type AttendanceWebhookRequest struct {
+	// External ID of the meeting the report is about
+	MeetingID string                  `json:"meeting_id" validate:"required,max=100"`
+	Attendees []AttendanceReportEntry `json:"attendees" validate:"max=10000,dive"`
+}
+
+func attendanceRecords(entries []AttendanceReportEntry) []services.AttendanceRecord {
+	records := make([]services.AttendanceRecord, 0, len(entries))
+	for _, entry := range entries {
+		records = append(records, services.AttendanceRecord{
+			JoinToken:       entry.JoinToken,
+			Email:           entry.Email,
+			JoinedAt:        entry.JoinedAt,
+			DurationMinutes: entry.DurationMinutes,
+		})
+	}
+	return records
+}
+
+// CreateMeeting adds the video call of an online or hybrid event
+// @Summary Create online meeting
+// @Description Participants are emailed their personal join link ONLINE_LINK_LEAD_TIME before the event starts.
+// @Tags Online
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body CreateMeetingRequest true "Meeting"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/online/meetings [post]
+func (h *Handler) CreateMeeting(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req CreateMeetingRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	meeting, err := h.onlineSvc.CreateMeeting(eventID, userID, services.CreateMeetingRequest{
+		ActionID:   req.ActionID,
+		Provider:   req.Provider,
+		JoinURL:    req.JoinURL,
+		ExternalID: req.ExternalID,
+		MinMinutes: req.MinMinutes,
+	})
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	// Sending is idempotent, so every meeting of an event may schedule it
+	dueAt, err := h.onlineSvc.JoinLinksDueAt(eventID)
+	if err == nil {
+		_, err = h.jobQueue.Schedule(jobs.TypeJoinLinks, jobs.EventPayload{EventID: eventID}, dueAt)
+	}
+	if err != nil {
+		middleware.GetLogger(c).WithError(err).Warn("failed to schedule join links")
+	}
+
+	return utils.Success(c, meeting, "Online meeting created successfully", fiber.StatusCreated)
+}
+
+// ListMeetings returns the online meetings of an event
+// @Summary List online meetings
+// @Tags Online
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/online/meetings [get]
+func (h *Handler) ListMeetings(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	meetings, err := h.onlineSvc.ListMeetings(eventID)
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve online meetings", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, meetings, "Online meetings retrieved successfully")
+}
+
+// DeleteMeeting removes an online meeting. Attendance already recorded is
+// kept.
+// @Summary Delete online meeting
+// @Tags Online
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param meeting_id path string true "Meeting ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/online/meetings/{meeting_id} [delete]
+func (h *Handler) DeleteMeeting(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	meetingID := c.Params("meeting_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(meetingID); err != nil {
+		return utils.Error(c, "Invalid meeting ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.onlineSvc.DeleteMeeting(eventID, meetingID); err != nil {
+		if errors.Is(err, services.ErrMeetingNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to delete online meeting", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "Online meeting deleted successfully")
+}
+
+// SendJoinLinks emails their join link to participants who have not
+// received it yet, e.g. after late registrations
+// @Summary Send join links
+// @Tags Online
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 202 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/online/join-links [post]
+func (h *Handler) SendJoinLinks(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	if _, err := h.onlineSvc.JoinLinksDueAt(eventID); err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	job, err := h.jobQueue.Enqueue(jobs.TypeJoinLinks, jobs.EventPayload{EventID: eventID})
+	if err != nil {
+		return utils.Error(c, "Failed to schedule join links", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, job, "Join links scheduled", fiber.StatusAccepted)
+}
+
+// ImportMeetingAttendance records an attendance report downloaded from the
+// meeting provider
+// @Summary Import online attendance
+// @Description Attendees are matched by join token, then by email. Those attending at least the meeting's min_minutes are recorded as verifications of the meeting's action.
+// @Tags Online
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param meeting_id path string true "Meeting ID"
+// @Param request body AttendanceReportRequest true "Attendance report"
+// @Success 200 {object} utils.Response{data=services.AttendanceImportResult}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/online/meetings/{meeting_id}/attendance [post]
+func (h *Handler) ImportMeetingAttendance(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	meetingID := c.Params("meeting_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(meetingID); err != nil {
+		return utils.Error(c, "Invalid meeting ID", fiber.StatusBadRequest)
+	}
+
+	var req AttendanceReportRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	result, err := h.onlineSvc.ImportAttendance(eventID, meetingID, userID, attendanceRecords(req.Attendees))
+	if err != nil {
+		if errors.Is(err, services.ErrMeetingNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to import attendance", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, result, "Attendance imported successfully")
+}
+
+// GetAttendanceSummary counts on-site and online attendance of an event
+// @Summary Get attendance summary
+// @Tags Online
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response{data=services.AttendanceSummary}
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/online/attendance [get]
+func (h *Handler) GetAttendanceSummary(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	summary, err := h.onlineSvc.AttendanceSummary(eventID)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusNotFound)
+	}
+
+	return utils.Success(c, summary, "Attendance summary retrieved successfully")
+}
+
+// JoinOnline sends a participant from their personal join link to the
+// meeting held today, or the next one
+// @Summary Join online event
+// @Tags Online
+// @Param token path string true "Join token"
+// @Success 302
+// @Failure 404 {object} utils.Response
+// @Router /online/join/{token} [get]
+func (h *Handler) JoinOnline(c *fiber.Ctx) error {
+	joinURL, err := h.onlineSvc.ResolveJoinLink(c.Params("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidJoinToken) || errors.Is(err, services.ErrNoMeeting) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to resolve join link", fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Redirect(joinURL, fiber.StatusFound)
+}
+
+// OnlineAttendanceWebhook ingests attendance reports pushed by a meeting
+// provider or an integration relaying them. Requests are signed with
+// ONLINE_WEBHOOK_SECRET: X-Signature is "sha256=" followed by the hex
+// HMAC-SHA256 of the raw body.
+// @Summary Online attendance webhook
+// @Tags Online
+// @Accept json
+// @Produce json
+// @Param X-Signature header string true "sha256=<hex HMAC of the body>"
+// @Param request body AttendanceWebhookRequest true "Attendance report"
+// @Success 200 {object} utils.Response{data=services.AttendanceImportResult}
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /webhooks/online-attendance [post]
+func (h *Handler) OnlineAttendanceWebhook(c *fiber.Ctx) error {
+	if !h.onlineSvc.VerifyWebhookSignature(c.Body(), c.Get("X-Signature")) {
+		return utils.Error(c, "Invalid signature", fiber.StatusUnauthorized)
+	}
+
+	var req AttendanceWebhookRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	result, err := h.onlineSvc.IngestWebhook(req.MeetingID, attendanceRecords(req.Attendees))
+	if err != nil {
+		if errors.Is(err, services.ErrMeetingNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to ingest attendance", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, result, "Attendance ingested successfully")
+}
@@ -1,15 +1,28 @@
 package handlers
 
 import (
-	"encoding/csv"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
 	"strconv"
+	"strings"
 
+	"event-management-backend/internal/jobs"
 	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/ratelimit"
+	"event-management-backend/internal/repositories"
 	"event-management-backend/internal/services"
 	"event-management-backend/internal/utils"
+	"event-management-backend/internal/xlsx"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type RegisterParticipantRequest struct {
@@ -19,20 +32,62 @@ type RegisterParticipantRequest struct {
 	Phone    string `json:"phone" validate:"required"`
 	Division string `json:"division"`
 	Address  string `json:"address"`
+	// regular when omitted
+	MealPreference string `json:"meal_preference" validate:"omitempty,oneof=regular vegetarian vegan halal gluten_free"`
+	// Token of the CAPTCHA widget, when the event requires one
+	CaptchaToken string `json:"captcha_token"`
+	// Required on events with ticket types, see GET /events/{id}/ticket-types
+	TicketTypeID string `json:"ticket_type_id" validate:"omitempty,uuid"`
+	// Required on private events with an access code
+	AccessCode string `json:"access_code"`
+	// Answers to the event's form fields by key; see form_fields of the event
+	Answers map[string]interface{} `json:"answers"`
+}
+
+type RegisterGroupRequest struct {
+	EventID      string                    `json:"event_id" validate:"required,uuid"`
+	Participants []GroupParticipantRequest `json:"participants" validate:"required,min=1,max=100"`
+	// Token of the CAPTCHA widget, when the event requires one
+	CaptchaToken string `json:"captcha_token"`
+	// Required on private events with an access code
+	AccessCode string `json:"access_code"`
+}
+
+// GroupParticipantRequest is one participant of a group registration; rows
+// are checked by the service so every problem is reported by row
+type GroupParticipantRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Division string `json:"division"`
+	Address  string `json:"address"`
+	// regular when omitted
+	MealPreference string `json:"meal_preference"`
+	// Required on events with ticket types
+	TicketTypeID string `json:"ticket_type_id"`
+	// Answers to the event's form fields by key
+	Answers map[string]interface{} `json:"answers"`
 }
 
 type UpdatePaymentStatusRequest struct {
 	Status string `json:"status" validate:"required,oneof=unpaid pending paid"`
+	// Version of the participant the client last read; enables conflict detection
+	Version *int `json:"version" validate:"omitempty,min=1"`
 }
 
 // RegisterParticipant handles participant registration
 // @Summary Register participant
+// @Description Events requiring a CAPTCHA also need `captcha_token`; without a valid one the response is 400 with the message "CAPTCHA verification failed". Private events with an access code answer 403 without the right `access_code`. When the tickets are sold out and the event has a waitlist, the registration joins it instead and the response is 202 with the place in line. On events with `confirmation_hours` the registration is pending_confirmation until the participant follows the link emailed to them, see /register/confirm/{token}. Email addresses of disposable email services are refused with 400; an email address registering too often, or an IP registering again too soon, gets 429 with Retry-After.
 // @Tags Participants
 // @Accept json
 // @Produce json
 // @Param request body RegisterParticipantRequest true "Participant data"
 // @Success 201 {object} utils.Response
+// @Success 202 {object} utils.Response{data=services.WaitlistPosition} "On the waitlist"
 // @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Failure 503 {object} utils.Response
 // @Router /register [post]
 func (h *Handler) RegisterParticipant(c *fiber.Ctx) error {
 	var req RegisterParticipantRequest
@@ -40,32 +95,144 @@ func (h *Handler) RegisterParticipant(c *fiber.Ctx) error {
 		return err
 	}
 
+	// Unknown events fail registration below; only the global setting applies
+	event, _ := h.eventSvc.GetEvent(req.EventID)
+	if err := h.checkCaptcha(c, event, req.CaptchaToken); err != nil {
+		return err
+	}
+	if event != nil {
+		if err := checkAccessCode(event, req.AccessCode); err != nil {
+			return err
+		}
+	}
+	if err := h.checkRegistrationAbuse(c, req.Email); err != nil {
+		return err
+	}
+
+	return h.registerParticipant(c, req)
+}
+
+func (h *Handler) registerParticipant(c *fiber.Ctx, req RegisterParticipantRequest) error {
 	participantReq := services.RegisterParticipantRequest{
-		EventID:  req.EventID,
-		Name:     req.Name,
-		Email:    req.Email,
-		Phone:    req.Phone,
-		Division: req.Division,
-		Address:  req.Address,
+		EventID:        req.EventID,
+		Name:           req.Name,
+		Email:          req.Email,
+		Phone:          req.Phone,
+		Division:       req.Division,
+		Address:        req.Address,
+		MealPreference: req.MealPreference,
+		TicketTypeID:   req.TicketTypeID,
+		Answers:        req.Answers,
 	}
 
 	result, err := h.participantSvc.RegisterParticipant(participantReq)
+	if errors.Is(err, repositories.ErrTicketQuotaExceeded) {
+		position, waitErr := h.participantSvc.JoinWaitlist(participantReq)
+		switch {
+		case waitErr == nil:
+			return utils.Success(c, position, "Event is full, you are on the waitlist", fiber.StatusAccepted)
+		case !errors.Is(waitErr, services.ErrWaitlistOff):
+			return utils.Error(c, waitErr.Error(), fiber.StatusBadRequest)
+		}
+	}
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+	if result.Participant.ApprovalStatus == services.ApprovalPendingConfirmation {
+		h.sendConfirmationEmail(c, result.Participant.ID.String())
+		return utils.Success(c, present(c, result), "Participant registered, confirm the email address to complete the registration", fiber.StatusCreated)
+	}
+
+	return utils.Success(c, present(c, result), "Participant registered successfully", fiber.StatusCreated)
+}
+
+// RegisterGroup registers several participants for one event at once
+// @Summary Register a group of participants
+// @Description Registers up to 100 participants, e.g. the employees of a company, entirely or not at all. Every row is checked first; when any is refused the response is 400 with the errors by row in `data.rows`. The group must fit in the tickets left as a whole. Events requiring a CAPTCHA also need `captcha_token`, private events with an access code `access_code`. Disposable email addresses and registration limits are checked as on /register, for every participant.
+// @Tags Participants
+// @Accept json
+// @Produce json
+// @Param request body RegisterGroupRequest true "Event and participants"
+// @Success 201 {object} utils.Response{data=services.GroupRegistrationResult}
+// @Failure 400 {object} utils.Response{data=services.GroupRegistrationResult}
+// @Failure 403 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /register/group [post]
+func (h *Handler) RegisterGroup(c *fiber.Ctx) error {
+	var req RegisterGroupRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	event, _ := h.eventSvc.GetEvent(req.EventID)
+	if err := h.checkCaptcha(c, event, req.CaptchaToken); err != nil {
+		return err
+	}
+	if event != nil {
+		if err := checkAccessCode(event, req.AccessCode); err != nil {
+			return err
+		}
+	}
+	emails := make([]string, len(req.Participants))
+	for i, p := range req.Participants {
+		emails[i] = p.Email
+	}
+	if err := h.checkRegistrationAbuse(c, emails...); err != nil {
+		return err
+	}
+
+	rows := make([]services.RegisterParticipantRequest, len(req.Participants))
+	for i, p := range req.Participants {
+		rows[i] = services.RegisterParticipantRequest{
+			EventID:        req.EventID,
+			Name:           p.Name,
+			Email:          p.Email,
+			Phone:          p.Phone,
+			Division:       p.Division,
+			Address:        p.Address,
+			MealPreference: p.MealPreference,
+			TicketTypeID:   p.TicketTypeID,
+			Answers:        p.Answers,
+		}
+	}
+
+	result, err := h.participantSvc.RegisterGroup(req.EventID, rows)
 	if err != nil {
+		if errors.Is(err, services.ErrGroupRejected) {
+			return utils.ErrorWithData(c, err.Error(), result, fiber.StatusBadRequest)
+		}
 		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
 	}
+	if event != nil && event.ConfirmationHours > 0 {
+		for _, row := range result.Rows {
+			if row.ParticipantID != nil {
+				h.sendConfirmationEmail(c, row.ParticipantID.String())
+			}
+		}
+	}
 
-	return utils.Success(c, result, "Participant registered successfully", fiber.StatusCreated)
+	return utils.Success(c, result, "Group registered successfully", fiber.StatusCreated)
 }
 
 // ListParticipants returns paginated list of participants for an event
 // @Summary List participants
+// @Description search matches part of the name, or the whole email address or phone number: those are stored encrypted.
 // @Tags Participants
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Event ID"
+// @Param search query string false "Part of the name, or the whole email or phone number"
+// @Param payment_status query string false "unpaid, pending, paid or refund_pending"
+// @Param division query string false "Division, ignoring case"
+// @Param registered_after query string false "Only participants registered at or after this RFC 3339 time"
+// @Param registered_before query string false "Only participants registered at or before this RFC 3339 time"
+// @Param answers.{key} query string false "Only participants who answered the form field key with this value, ignoring case; booleans are true or false"
+// @Param tag query string false "Only participants with this tag, ignoring case"
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(20)
+// @Param cursor query string false "Keyset cursor; pass empty for the first page, then meta.next_cursor"
 // @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
 // @Router /events/{id}/participants [get]
 func (h *Handler) ListParticipants(c *fiber.Ctx) error {
 	eventID := c.Params("id")
@@ -73,11 +240,32 @@ func (h *Handler) ListParticipants(c *fiber.Ctx) error {
 		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
 	}
 
+	filters, err := participantFilters(c)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
 
-	participants, total, totalPages, err := h.participantSvc.ListParticipants(eventID, page, pageSize)
+	if cursor, ok := cursorParam(c); ok {
+		participants, nextCursor, err := h.participantSvc.ListParticipantsByCursor(eventID, cursor, pageSize, filters)
+		if err != nil {
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
+
+		meta := &utils.Meta{
+			PageSize:   pageSize,
+			NextCursor: nextCursor,
+		}
+		return utils.SuccessWithMeta(c, present(c, participants), meta, "Participants retrieved successfully")
+	}
+
+	participants, total, totalPages, err := h.participantSvc.ListParticipants(eventID, page, pageSize, filters)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidParticipantFilter) {
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
 		return utils.Error(c, "Failed to fetch participants", fiber.StatusInternalServerError)
 	}
 
@@ -88,19 +276,132 @@ func (h *Handler) ListParticipants(c *fiber.Ctx) error {
 		TotalPage: totalPages,
 	}
 
-	return utils.SuccessWithMeta(c, participants, meta, "Participants retrieved successfully")
+	return utils.SuccessWithMeta(c, present(c, participants), meta, "Participants retrieved successfully")
+}
+
+// participantFilters reads the participant listing filters from the query
+// string
+func participantFilters(c *fiber.Ctx) (*repositories.ParticipantFilters, error) {
+	filters := &repositories.ParticipantFilters{
+		Search:        c.Query("search"),
+		PaymentStatus: c.Query("payment_status"),
+		Division:      c.Query("division"),
+		Tag:           strings.ToLower(strings.TrimSpace(c.Query("tag"))),
+	}
+	var err error
+	if filters.RegisteredAfter, err = timeQuery(c, "registered_after"); err != nil {
+		return nil, errors.New("Invalid registered_after format")
+	}
+	if filters.RegisteredBefore, err = timeQuery(c, "registered_before"); err != nil {
+		return nil, errors.New("Invalid registered_before format")
+	}
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		if field, ok := strings.CutPrefix(string(key), "answers."); ok && field != "" {
+			if filters.Answers == nil {
+				filters.Answers = map[string]string{}
+			}
+			filters.Answers[field] = string(value)
+		}
+	})
+	return filters, nil
 }
 
-// ImportParticipants imports participants from CSV
+// ExportParticipantsCSV downloads the participants of an event as CSV
+// @Summary Export participants as CSV
+// @Description Streams every participant with their payment status, QR code path, number of verifications and tags, newest first, then a column `answers.<key>` per registration form field. Takes the same filters as the participant listing.
+// @Tags Participants
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param search query string false "Part of the name, or the whole email or phone number"
+// @Param payment_status query string false "unpaid, pending, paid or refund_pending"
+// @Param division query string false "Division, ignoring case"
+// @Param registered_after query string false "Only participants registered at or after this RFC 3339 time"
+// @Param registered_before query string false "Only participants registered at or before this RFC 3339 time"
+// @Param answers.{key} query string false "Only participants who answered the form field key with this value, ignoring case; booleans are true or false"
+// @Param tag query string false "Only participants with this tag, ignoring case"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/participants/export.csv [get]
+func (h *Handler) ExportParticipantsCSV(c *fiber.Ctx) error {
+	return h.streamParticipantExport(c, "csv", "text/csv", (*services.ParticipantExport).Write)
+}
+
+// ExportParticipantsXLSX downloads the participants of an event as an Excel
+// workbook
+// @Summary Export participants as XLSX
+// @Description A Participants sheet with the CSV export columns, then a sheet per event day listing who attended it, the actions they were scanned at and their first and last scans. Takes the same filters as the participant listing.
+// @Tags Participants
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param search query string false "Part of the name, or the whole email or phone number"
+// @Param payment_status query string false "unpaid, pending, paid or refund_pending"
+// @Param division query string false "Division, ignoring case"
+// @Param registered_after query string false "Only participants registered at or after this RFC 3339 time"
+// @Param registered_before query string false "Only participants registered at or before this RFC 3339 time"
+// @Param answers.{key} query string false "Only participants who answered the form field key with this value, ignoring case; booleans are true or false"
+// @Param tag query string false "Only participants with this tag, ignoring case"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/participants/export.xlsx [get]
+func (h *Handler) ExportParticipantsXLSX(c *fiber.Ctx) error {
+	return h.streamParticipantExport(c, "xlsx", xlsx.ContentType, (*services.ParticipantExport).WriteXLSX)
+}
+
+// streamParticipantExport streams the participant export of the event in the
+// path with write
+func (h *Handler) streamParticipantExport(c *fiber.Ctx, ext, contentType string, write func(*services.ParticipantExport, io.Writer) error) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	filters, err := participantFilters(c)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	export, err := h.participantSvc.ExportParticipants(eventID, filters)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidParticipantFilter):
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		case errors.Is(err, services.ErrUnknownEvent):
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to export participants", fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="event-%s-participants.%s"`, export.Event.Slug, ext))
+
+	// The writer runs after the handler returns, when c may already be
+	// reused, so the logger is captured here
+	log := middleware.GetLogger(c)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := write(export, w); err != nil {
+			log.WithError(err).Error("failed to write participant export")
+		}
+	})
+	return nil
+}
+
+// ImportParticipants schedules the import of participants from CSV or Excel
 // @Summary Import participants
+// @Description The file starts with a header row. Columns are found by their header, ignoring case: name and email are required; phone, division, address and meal_preference are optional, and other columns are ignored. Headers such as "Full Name" or "E-mail" are recognized too; for others, map the fields to their header with `column_mapping`, e.g. {"name":"Nama","email":"Surel"}. Excel (.xlsx) files are read from their first sheet. The file is imported in the background; follow the returned import job at /participants/import/{job_id}. With dry_run, every row is checked as an import would, for duplicates, the ticket quota, the quota rules of the division and email format, and the job reports the problems by row and how many rows would be imported, without registering anyone.
 // @Tags Participants
 // @Accept multipart/form-data
 // @Produce json
 // @Security BearerAuth
 // @Param event_id formData string true "Event ID"
-// @Param file formData file true "CSV file"
-// @Success 200 {object} utils.Response
+// @Param file formData file true "CSV or XLSX file"
+// @Param column_mapping formData string false "JSON object of participant field to column header"
+// @Param dry_run formData bool false "Only check the rows"
+// @Success 202 {object} utils.Response{data=models.ImportJob}
 // @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
 // @Router /participants/import [post]
 func (h *Handler) ImportParticipants(c *fiber.Ctx) error {
 	eventID := c.FormValue("event_id")
@@ -119,40 +420,118 @@ func (h *Handler) ImportParticipants(c *fiber.Ctx) error {
 	}
 
 	// Validate file type
-	if file.Header.Get("Content-Type") != "text/csv" {
-		return utils.Error(c, "Only CSV files are allowed", fiber.StatusBadRequest)
+	format := services.ImportFormatCSV
+	if file.Header.Get("Content-Type") == xlsx.ContentType || strings.EqualFold(filepath.Ext(file.Filename), ".xlsx") {
+		format = services.ImportFormatXLSX
+	} else if file.Header.Get("Content-Type") != "text/csv" {
+		return utils.Error(c, "Only CSV and XLSX files are allowed", fiber.StatusBadRequest)
+	}
+
+	var mapping map[string]string
+	if raw := c.FormValue("column_mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			return utils.Error(c, "column_mapping must be a JSON object of field to column header", fiber.StatusBadRequest)
+		}
+	}
+	dryRun := false
+	if value := c.FormValue("dry_run"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			return utils.Error(c, "dry_run must be true or false", fiber.StatusBadRequest)
+		}
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
 	}
 
-	// Read CSV file
 	src, err := file.Open()
 	if err != nil {
 		return utils.Error(c, "Failed to read file", fiber.StatusInternalServerError)
 	}
 	defer src.Close()
 
-	reader := csv.NewReader(src)
-	rows, err := reader.ReadAll()
+	job, err := h.participantSvc.StartParticipantImport(actorID, eventID, file.Filename, format, mapping, dryRun, src)
 	if err != nil {
-		return utils.Error(c, "Invalid CSV format", fiber.StatusBadRequest)
+		switch {
+		case errors.Is(err, services.ErrUnknownEvent):
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		case errors.Is(err, services.ErrInvalidColumnMapping):
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
+		return utils.Error(c, "Failed to import participants", fiber.StatusInternalServerError)
 	}
 
-	if len(rows) < 2 {
-		return utils.Error(c, "CSV file is empty or missing header", fiber.StatusBadRequest)
+	// A retry resumes an import interrupted by a shutdown
+	_, err = h.jobQueue.EnqueueWithOptions(jobs.TypeParticipantImport, jobs.ImportPayload{ImportJobID: job.ID.String()}, jobs.Options{MaxAttempts: 3})
+	if err != nil {
+		if failErr := h.participantSvc.FailParticipantImport(job.ID.String(), "failed to schedule import"); failErr != nil {
+			middleware.GetLogger(c).WithError(failErr).Error("failed to update import job")
+		}
+		return utils.Error(c, "Failed to schedule import", fiber.StatusInternalServerError)
 	}
 
-	// Skip header row
-	success, fail, errors, err := h.participantSvc.ImportParticipantsCSV(eventID, rows[1:])
-	if err != nil {
-		return utils.Error(c, "Failed to import participants", fiber.StatusInternalServerError)
+	message := "Import scheduled"
+	if dryRun {
+		message = "Dry run scheduled"
 	}
+	return utils.Success(c, job, message, fiber.StatusAccepted)
+}
 
-	result := fiber.Map{
-		"success": success,
-		"failed":  fail,
-		"errors":  errors,
+// GetImportJob returns the progress of a participant import
+// @Summary Get participant import
+// @Description Progress of an import with the problems found so far by row, numbered from the first row after the header. Status is pending, running, completed or failed; error explains a failed import.
+// @Tags Participants
+// @Produce json
+// @Security BearerAuth
+// @Param job_id path string true "Import job ID"
+// @Success 200 {object} utils.Response{data=models.ImportJob}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /participants/import/{job_id} [get]
+func (h *Handler) GetImportJob(c *fiber.Ctx) error {
+	return h.withImportJob(c, func(job *models.ImportJob) error {
+		return utils.Success(c, job, "Import job retrieved successfully")
+	})
+}
+
+// GetImportErrorReport downloads the row problems of a participant import
+// @Summary Download import error report
+// @Description CSV with the row number and problem of every row that was not imported cleanly.
+// @Tags Participants
+// @Produce text/csv
+// @Security BearerAuth
+// @Param job_id path string true "Import job ID"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /participants/import/{job_id}/errors.csv [get]
+func (h *Handler) GetImportErrorReport(c *fiber.Ctx) error {
+	return h.withImportJob(c, func(job *models.ImportJob) error {
+		c.Set(fiber.HeaderContentType, "text/csv")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="import-%s-errors.csv"`, job.ID))
+		if err := services.WriteImportErrorReport(job, c); err != nil {
+			return utils.Error(c, "Failed to write error report", fiber.StatusInternalServerError)
+		}
+		return nil
+	})
+}
+
+// withImportJob answers with respond for the import job in the path
+func (h *Handler) withImportJob(c *fiber.Ctx, respond func(job *models.ImportJob) error) error {
+	jobID := c.Params("job_id")
+	if _, err := uuid.Parse(jobID); err != nil {
+		return utils.Error(c, "Invalid import job ID", fiber.StatusBadRequest)
 	}
 
-	return utils.Success(c, result, "Import completed")
+	job, err := h.participantSvc.GetImportJob(jobID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownImportJob) {
+			return utils.Error(c, "Import job not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to retrieve import job", fiber.StatusInternalServerError)
+	}
+	return respond(job)
 }
 
 // UpdatePaymentStatus updates participant payment status
@@ -165,6 +544,7 @@ func (h *Handler) ImportParticipants(c *fiber.Ctx) error {
 // @Param request body UpdatePaymentStatusRequest true "Payment status"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response "Version conflict, data holds the latest participant"
 // @Router /participants/{id}/payment-status [patch]
 func (h *Handler) UpdatePaymentStatus(c *fiber.Ctx) error {
 	participantID := c.Params("id")
@@ -177,9 +557,341 @@ func (h *Handler) UpdatePaymentStatus(c *fiber.Ctx) error {
 		return err
 	}
 
-	if err := h.participantSvc.UpdatePaymentStatus(participantID, req.Status); err != nil {
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	if err := h.participantSvc.UpdatePaymentStatus(actorID, participantID, req.Status, req.Version); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrVersionConflict):
+			latest, getErr := h.participantSvc.GetParticipant(participantID)
+			if getErr != nil {
+				return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+			}
+			return utils.ErrorWithData(c, err.Error(), present(c, latest), fiber.StatusConflict)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+		}
 		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
 	}
 
 	return utils.Success(c, nil, "Payment status updated successfully")
 }
+
+// FindDuplicateParticipants lists registrations that look like the same person
+// @Summary Find duplicate participants
+// @Description Groups the participants of an event sharing an email address or phone number once normalized: case, +tags, the dots of Gmail addresses and phone notation are ignored.
+// @Tags Participants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/participants/duplicates [get]
+func (h *Handler) FindDuplicateParticipants(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	groups, err := h.participantSvc.FindDuplicateParticipants(eventID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to find duplicate participants", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, groups, "Duplicate participants retrieved successfully")
+}
+
+// GetParticipant returns the detail view of a participant
+// @Summary Get participant
+// @Description Returns a participant with the notes staff kept on them, newest first.
+// @Tags Participants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Participant ID"
+// @Success 200 {object} utils.Response{data=services.ParticipantDetail}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "Not assigned to the participant's event"
+// @Failure 404 {object} utils.Response
+// @Router /participants/{id} [get]
+func (h *Handler) GetParticipant(c *fiber.Ctx) error {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	detail, err := h.participantSvc.GetParticipantDetail(participantID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownParticipant) {
+			return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to fetch participant", fiber.StatusInternalServerError)
+	}
+	if err := h.checkEventAccess(c, detail.Participant.EventID.String()); err != nil {
+		return eventAccessError(c, err)
+	}
+
+	return utils.Success(c, present(c, detail), "Participant retrieved successfully")
+}
+
+type MergeParticipantsRequest struct {
+	// Participant merged into the one in the path, then deleted
+	DuplicateID string `json:"duplicate_id" validate:"required,uuid"`
+}
+
+// MergeParticipants merges a duplicate registration into a participant
+// @Summary Merge duplicate participants
+// @Description Keeps the participant in the path and deletes duplicate_id after moving its scans, leads, waiver signatures, seats, credential incidents and draw wins over; those the kept participant already has a counterpart of are dropped. Missing contact details, a paid status and an approval are taken from the duplicate. The kept QR code stays valid and the duplicate's is refused.
+// @Tags Participants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Participant ID to keep"
+// @Param request body MergeParticipantsRequest true "Duplicate"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "Not assigned to the event of either participant"
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "Either participant changed concurrently"
+// @Router /participants/{id}/merge [post]
+func (h *Handler) MergeParticipants(c *fiber.Ctx) error {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	var req MergeParticipantsRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+	for _, id := range []string{participantID, req.DuplicateID} {
+		if err := h.checkParticipantAccess(c, id); err != nil {
+			return eventAccessError(c, err)
+		}
+	}
+
+	result, err := h.participantSvc.MergeParticipants(actorID, participantID, req.DuplicateID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownParticipant):
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		case errors.Is(err, repositories.ErrVersionConflict):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		case errors.Is(err, services.ErrMergeSelf), errors.Is(err, services.ErrMergeOtherEvent), errors.Is(err, services.ErrMergeCancelled):
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
+		return utils.Error(c, "Failed to merge participants", fiber.StatusInternalServerError)
+	}
+	h.promoteWaitlist(c, result.Participant.EventID.String())
+
+	return utils.Success(c, result, "Participants merged successfully")
+}
+
+type TransferParticipantRequest struct {
+	EventID string `json:"event_id" validate:"required,uuid"`
+	// Required when the target event has ticket types
+	TicketTypeID string `json:"ticket_type_id" validate:"omitempty,uuid"`
+}
+
+// TransferParticipant moves a participant to another event
+// @Summary Transfer participant
+// @Description Moves a registration to another published event, e.g. a later batch. The target's quota, day capacities, ticket type and quota rules are checked as for a new registration. A paid participant stays paid unless the new ticket costs more; `amount_due` is what is left to pay. Seats of the former event are released and a new QR code is sent, the old one being refused at the gates; targets requiring approval put the registration up for review again and send the QR code once it is approved. Participants already scanned cannot be transferred.
+// @Tags Participants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Participant ID"
+// @Param request body TransferParticipantRequest true "Target event"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "Not assigned to the participant's event or the target event"
+// @Failure 404 {object} utils.Response "Unknown participant or target event"
+// @Failure 409 {object} utils.Response "Target full, participant cancelled or scanned, or changed concurrently"
+// @Router /participants/{id}/transfer [post]
+func (h *Handler) TransferParticipant(c *fiber.Ctx) error {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	var req TransferParticipantRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	// Staff move people only between events they are assigned to
+	participant, err := h.participantSvc.GetParticipant(participantID)
+	if err != nil {
+		return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+	}
+	for _, eventID := range []string{participant.EventID.String(), req.EventID} {
+		if err := h.checkEventAccess(c, eventID); err != nil {
+			return eventAccessError(c, err)
+		}
+	}
+
+	result, err := h.participantSvc.TransferParticipant(actorID, participantID, services.TransferParticipantRequest{
+		EventID:      req.EventID,
+		TicketTypeID: req.TicketTypeID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownParticipant), errors.Is(err, services.ErrUnknownEvent):
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		case errors.Is(err, repositories.ErrTicketQuotaExceeded), errors.Is(err, repositories.ErrEventDayFull),
+			errors.Is(err, repositories.ErrVersionConflict), errors.Is(err, services.ErrTicketCancelled),
+			errors.Is(err, services.ErrTransferCheckedIn):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	if result.Participant.QRPath != "" {
+		if _, err := h.jobQueue.Enqueue(jobs.TypeTicketResend, jobs.ParticipantPayload{ParticipantID: participantID}); err != nil {
+			middleware.GetLogger(c).WithError(err).Warn("failed to enqueue ticket of transferred participant")
+		}
+	}
+	h.promoteWaitlist(c, result.FromEventID.String())
+
+	return utils.Success(c, result, "Participant transferred successfully")
+}
+
+type CancelParticipantRequest struct {
+	// Code printed on the ticket; required unless staff cancel with a
+	// bearer token
+	TicketCode string `json:"ticket_code" validate:"max=20"`
+	Reason     string `json:"reason" validate:"max=1000"`
+}
+
+// CancelParticipant cancels a registration
+// @Summary Cancel registration
+// @Description Cancels a registration, by staff with a bearer token or by the participant with the `ticket_code` printed on their ticket. The QR code is refused from then on and the ticket no longer counts against the quota; events with an automatic waitlist promote the next person into it.
+// @Tags Participants
+// @Accept json
+// @Produce json
+// @Param id path string true "Participant ID"
+// @Param request body CancelParticipantRequest true "Cancellation"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "Wrong ticket code, a token below staff, or staff not assigned to the participant's event"
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "Already cancelled, or changed concurrently"
+// @Router /participants/{id}/cancel [post]
+func (h *Handler) CancelParticipant(c *fiber.Ctx) error {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	var req CancelParticipantRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	var participant *models.Participant
+	var err error
+	if role := c.Locals("user_role"); role != nil {
+		if role != "admin" && role != "organizer" && role != "staff" {
+			return utils.Error(c, "Staff or above access required", fiber.StatusForbidden)
+		}
+		if err := h.checkParticipantAccess(c, participantID); err != nil {
+			return eventAccessError(c, err)
+		}
+		actorID, _ := middleware.GetUserIDFromContext(c)
+		participant, err = h.participantSvc.CancelRegistration(actorID, participantID, req.Reason)
+	} else {
+		if req.TicketCode == "" {
+			return utils.Error(c, "ticket_code is required", fiber.StatusBadRequest)
+		}
+		participant, err = h.participantSvc.CancelOwnRegistration(participantID, req.TicketCode, req.Reason)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownParticipant):
+			return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+		case errors.Is(err, services.ErrTicketCodeMismatch):
+			return utils.Error(c, err.Error(), fiber.StatusForbidden)
+		case errors.Is(err, services.ErrTicketCancelled), errors.Is(err, repositories.ErrVersionConflict):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, "Failed to cancel registration", fiber.StatusInternalServerError)
+	}
+	h.promoteWaitlist(c, participant.EventID.String())
+
+	return utils.Success(c, present(c, participant), "Registration cancelled successfully")
+}
+
+// checkCaptcha verifies the CAPTCHA token of a public registration when
+// CAPTCHA checks are configured and required globally or by the event
+func (h *Handler) checkCaptcha(c *fiber.Ctx, event *models.Event, token string) error {
+	if h.captcha == nil || !(h.cfg.CaptchaRequired || (event != nil && event.RequiresCaptcha)) {
+		return nil
+	}
+
+	if err := h.captcha.Verify(c.UserContext(), token, middleware.ClientIP(c)); err != nil {
+		if errors.Is(err, utils.ErrCaptchaFailed) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		middleware.GetLogger(c).WithError(err).Error("CAPTCHA verification unavailable")
+		return fiber.NewError(fiber.StatusServiceUnavailable, "CAPTCHA verification unavailable, try again later")
+	}
+	return nil
+}
+
+// checkRegistrationAbuse holds off scripted public registrations draining
+// the tickets: it refuses disposable email addresses, registrations from an IP
+// sooner than REGISTER_MIN_INTERVAL after its previous one, and email
+// addresses that registered too often in the last hour. Limits pass when
+// rate limiting is disabled or its store fails.
+func (h *Handler) checkRegistrationAbuse(c *fiber.Ctx, emails ...string) error {
+	for _, email := range emails {
+		if err := services.CheckEmailDomain(h.cfg, email); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+	if h.rateLimits == nil {
+		return nil
+	}
+
+	if interval := h.cfg.RegisterMinInterval; interval > 0 {
+		limit := ratelimit.Limit{Rate: 1 / interval.Seconds(), Burst: 1}
+		if err := h.takeRegistrationToken(c, "ratelimit:register-interval:"+middleware.ClientIP(c), limit); err != nil {
+			return err
+		}
+	}
+	limit := ratelimit.PerHour(h.cfg.RateLimitRegisterEmailPerHour, h.cfg.RateLimitRegisterEmailPerHour)
+	for _, email := range emails {
+		if err := h.takeRegistrationToken(c, "ratelimit:register-email:"+strings.ToLower(strings.TrimSpace(email)), limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *Handler) takeRegistrationToken(c *fiber.Ctx, key string, limit ratelimit.Limit) error {
+	allowed, wait, err := h.rateLimits.Take(c.UserContext(), key, limit)
+	if err != nil {
+		middleware.GetLogger(c).WithError(err).Warn("Rate limit check failed")
+		return nil
+	}
+	if !allowed {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Max(1, math.Ceil(wait.Seconds())))))
+		return fiber.NewError(fiber.StatusTooManyRequests, "Too many registrations, please try again later")
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type AddParticipantNoteRequest struct {
+	Body string `json:"body" validate:"required,max=2000"`
+}
+
+// AddParticipantNote records a staff note on a participant
+// @Summary Add participant note
+// @Description Records a note on a participant, e.g. "ID mismatch, approved by supervisor", in the name of the signed-in user. Notes cannot be edited and are erased when the event's participants are anonymized.
+// @Tags Participants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Participant ID"
+// @Param request body AddParticipantNoteRequest true "Note"
+// @Success 201 {object} utils.Response{data=models.ParticipantNote}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "Not assigned to the participant's event"
+// @Failure 404 {object} utils.Response
+// @Router /participants/{id}/notes [post]
+func (h *Handler) AddParticipantNote(c *fiber.Ctx) error {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	var req AddParticipantNoteRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	authorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+	participant, err := h.participantSvc.GetParticipant(participantID)
+	if err != nil {
+		return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+	}
+	if err := h.checkEventAccess(c, participant.EventID.String()); err != nil {
+		return eventAccessError(c, err)
+	}
+
+	note, err := h.participantSvc.AddParticipantNote(authorID, participantID, req.Body)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownParticipant) {
+			return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, note, "Note added successfully", fiber.StatusCreated)
+}
+
+// ListParticipantNotes returns the staff notes on a participant
+// @Summary List participant notes
+// @Tags Participants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Participant ID"
+// @Success 200 {object} utils.Response{data=[]models.ParticipantNote}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "Not assigned to the participant's event"
+// @Failure 404 {object} utils.Response
+// @Router /participants/{id}/notes [get]
+func (h *Handler) ListParticipantNotes(c *fiber.Ctx) error {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+	participant, err := h.participantSvc.GetParticipant(participantID)
+	if err != nil {
+		return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+	}
+	if err := h.checkEventAccess(c, participant.EventID.String()); err != nil {
+		return eventAccessError(c, err)
+	}
+
+	notes, err := h.participantSvc.ListParticipantNotes(participantID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownParticipant) {
+			return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to fetch notes", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, notes, "Notes retrieved successfully")
+}
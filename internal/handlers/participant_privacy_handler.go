@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ExportParticipantData returns everything stored about a participant
+// @Summary Export participant data
+// @Description Returns the personal data stored about a participant with their scans, waiver signatures, leads, seats, draw wins, waitlist entries, credential incidents, staff notes and audit trail, to answer a data subject access request. Admin only; the export is audited.
+// @Tags Participants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Participant ID"
+// @Success 200 {object} utils.Response{data=services.ParticipantDataExport}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /participants/{id}/data-export [get]
+func (h *Handler) ExportParticipantData(c *fiber.Ctx) error {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	export, err := h.participantSvc.ExportParticipantData(actorID, participantID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownParticipant) {
+			return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to export participant data", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, export, "Participant data exported successfully")
+}
+
+// AnonymizeParticipant erases the personal data of a participant
+// @Summary Anonymize participant
+// @Description Erases the personal data of a participant on request: contact details, answers, waiver signer details, staff and sponsor notes and the waitlist entry they came from. The QR image is deleted. Scans are kept, anonymized, so event statistics stay intact. Admin only; audited.
+// @Tags Participants
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Participant ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "Anonymized already, or the event is under legal hold"
+// @Router /participants/{id}/anonymize [post]
+func (h *Handler) AnonymizeParticipant(c *fiber.Ctx) error {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	participant, err := h.participantSvc.AnonymizeParticipant(actorID, participantID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownParticipant):
+			return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+		case errors.Is(err, services.ErrParticipantAnonymized), errors.Is(err, services.ErrEventUnderLegalHold):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, "Failed to anonymize participant", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, present(c, participant), "Participant anonymized successfully")
+}
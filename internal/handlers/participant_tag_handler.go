@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type TagParticipantsRequest struct {
+	ParticipantIDs []string `json:"participant_ids" validate:"required,min=1,max=500,dive,uuid"`
+	Add            []string `json:"add" validate:"max=20"`
+	Remove         []string `json:"remove" validate:"max=20"`
+}
+
+// TagParticipants adds and removes tags on participants in bulk
+// @Summary Tag participants
+// @Description Adds and removes tags such as vip, speaker or press on the given participants. Tags are lowercased and may hold letters, digits, dashes and underscores. Participants tagged complimentary are let in at paid events without paying. Participants that would carry more than 20 tags are left unchanged and reported as over the limit; IDs of other events are reported as not found.
+// @Tags Participants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body TagParticipantsRequest true "Participants and tags"
+// @Success 200 {object} utils.Response{data=services.TagResult}
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/participants/tags [post]
+func (h *Handler) TagParticipants(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	var req TagParticipantsRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	result, err := h.participantSvc.TagParticipants(actorID, eventID, req.ParticipantIDs, req.Add, req.Remove)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, result, "Participants tagged")
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type QuotaRuleRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	// Registrations for this ticket type count; any ticket type when omitted
+	TicketTypeID string `json:"ticket_type_id" validate:"omitempty,uuid"`
+	// Registrations from this division count, ignoring case; any division
+	// when omitted
+	Division         string `json:"division" validate:"max=100"`
+	MaxRegistrations int    `json:"max_registrations" validate:"required,gt=0"`
+}
+
+func (req QuotaRuleRequest) toService() services.QuotaRuleRequest {
+	return services.QuotaRuleRequest{
+		Name:             req.Name,
+		TicketTypeID:     req.TicketTypeID,
+		Division:         req.Division,
+		MaxRegistrations: req.MaxRegistrations,
+	}
+}
+
+// ListQuotaRules returns the quota rules of an event
+// @Summary List quota rules
+// @Description Quota rules with the registrations counted against them. A registration is refused once any rule it counts against is full.
+// @Tags Quota Rules
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response{data=[]services.QuotaRuleUsage}
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/quota-rules [get]
+func (h *Handler) ListQuotaRules(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	rules, err := h.eventSvc.ListQuotaRules(eventID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to retrieve quota rules", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, rules, "Quota rules retrieved successfully")
+}
+
+// CreateQuotaRule adds a quota rule to an event
+// @Summary Create quota rule
+// @Description A rule needs a ticket type, a division or both, e.g. at most 50 registrations from the Finance division.
+// @Tags Quota Rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body QuotaRuleRequest true "Quota rule"
+// @Success 201 {object} utils.Response{data=models.QuotaRule}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/quota-rules [post]
+func (h *Handler) CreateQuotaRule(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req QuotaRuleRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	rule, err := h.eventSvc.CreateQuotaRule(eventID, req.toService())
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, rule, "Quota rule created successfully", fiber.StatusCreated)
+}
+
+// UpdateQuotaRule replaces a quota rule
+// @Summary Update quota rule
+// @Description Lowering the maximum below the registrations already made only refuses new ones.
+// @Tags Quota Rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param rule_id path string true "Quota rule ID"
+// @Param request body QuotaRuleRequest true "Quota rule"
+// @Success 200 {object} utils.Response{data=models.QuotaRule}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/quota-rules/{rule_id} [put]
+func (h *Handler) UpdateQuotaRule(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	ruleID := c.Params("rule_id")
+	if _, err := uuid.Parse(ruleID); err != nil {
+		return utils.Error(c, "Invalid quota rule ID", fiber.StatusBadRequest)
+	}
+
+	var req QuotaRuleRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	rule, err := h.eventSvc.UpdateQuotaRule(eventID, ruleID, req.toService())
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownQuotaRule) {
+			return utils.Error(c, "Quota rule not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, rule, "Quota rule updated successfully")
+}
+
+// DeleteQuotaRule removes a quota rule
+// @Summary Delete quota rule
+// @Tags Quota Rules
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param rule_id path string true "Quota rule ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/quota-rules/{rule_id} [delete]
+func (h *Handler) DeleteQuotaRule(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	ruleID := c.Params("rule_id")
+	if _, err := uuid.Parse(ruleID); err != nil {
+		return utils.Error(c, "Invalid quota rule ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.eventSvc.DeleteQuotaRule(eventID, ruleID); err != nil {
+		if errors.Is(err, services.ErrUnknownQuotaRule) {
+			return utils.Error(c, "Quota rule not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to delete quota rule", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "Quota rule deleted successfully")
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+
+	"event-management-backend/internal/jobs"
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ConfirmRegistration confirms the email address of a registration from the
+// link emailed to the participant
+// @Summary Confirm registration
+// @Description Events with confirmation_hours hold new registrations as pending_confirmation until the participant follows the link emailed to them. Confirming issues the QR code, which is emailed too, or hands the registration to organizer review on events requiring approval. Registrations not confirmed in time are deleted and their ticket freed.
+// @Tags Participants
+// @Produce json
+// @Param token path string true "Confirmation token"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /register/confirm/{token} [get]
+func (h *Handler) ConfirmRegistration(c *fiber.Ctx) error {
+	participant, confirmed, err := h.participantSvc.ConfirmRegistration(c.Params("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidConfirmationLink) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to confirm registration", fiber.StatusInternalServerError)
+	}
+
+	if confirmed && participant.QRPath != "" {
+		if _, err := h.jobQueue.Enqueue(jobs.TypeTicketResend, jobs.ParticipantPayload{ParticipantID: participant.ID.String()}); err != nil {
+			middleware.GetLogger(c).WithError(err).WithField("participant_id", participant.ID.String()).Warn("failed to enqueue ticket of confirmed participant")
+		}
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return utils.Success(c, present(c, participant), "Registration confirmed")
+}
+
+// sendConfirmationEmail queues the email with the confirmation link of a new
+// registration; the registration stands even if queueing fails
+func (h *Handler) sendConfirmationEmail(c *fiber.Ctx, participantID string) {
+	if _, err := h.jobQueue.Enqueue(jobs.TypeConfirmationEmail, jobs.ParticipantPayload{ParticipantID: participantID}); err != nil {
+		middleware.GetLogger(c).WithError(err).WithField("participant_id", participantID).Warn("failed to enqueue confirmation email")
+	}
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+
+	"event-management-backend/internal/jobs"
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CreateRetentionRuleRequest struct {
+	Action string `json:"action" validate:"required,oneof=anonymize_pii delete_qr_files"`
+	// Days after the event ends before the action applies
+	AfterDays int `json:"after_days" validate:"required,min=1,max=36500"`
+}
+
+type LegalHoldRequest struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
+
+// ListRetentionRules returns the retention rules and the report of the latest
+// run
+// @Summary List retention rules
+// @Tags Retention
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/retention/rules [get]
+func (h *Handler) ListRetentionRules(c *fiber.Ctx) error {
+	rules, err := h.retentionSvc.ListRules()
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve retention rules", fiber.StatusInternalServerError)
+	}
+
+	result := fiber.Map{
+		"enabled":  h.cfg.RetentionEnabled,
+		"interval": h.cfg.RetentionInterval.String(),
+		"rules":    rules,
+		"last_run": h.retentionSvc.LastRun(),
+	}
+
+	return utils.Success(c, result, "Retention rules retrieved successfully")
+}
+
+// CreateRetentionRule adds a retention rule
+// @Summary Create retention rule
+// @Description anonymize_pii erases participant contact details and waiver signer details; delete_qr_files removes QR code images. Attendance records are kept.
+// @Tags Retention
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateRetentionRuleRequest true "Rule"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /admin/retention/rules [post]
+func (h *Handler) CreateRetentionRule(c *fiber.Ctx) error {
+	var req CreateRetentionRuleRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	rule, err := h.retentionSvc.CreateRule(userID, req.Action, req.AfterDays)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, rule, "Retention rule created successfully", fiber.StatusCreated)
+}
+
+// DeleteRetentionRule removes a retention rule
+// @Summary Delete retention rule
+// @Tags Retention
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Rule ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/retention/rules/{id} [delete]
+func (h *Handler) DeleteRetentionRule(c *fiber.Ctx) error {
+	ruleID := c.Params("id")
+	if _, err := uuid.Parse(ruleID); err != nil {
+		return utils.Error(c, "Invalid rule ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.retentionSvc.DeleteRule(ruleID); err != nil {
+		if errors.Is(err, services.ErrRetentionRuleNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to delete retention rule", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "Retention rule deleted successfully")
+}
+
+// PreviewRetention reports what a retention run would change without
+// changing anything
+// @Summary Preview retention run
+// @Tags Retention
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=services.RetentionReport}
+// @Failure 500 {object} utils.Response
+// @Router /admin/retention/preview [get]
+func (h *Handler) PreviewRetention(c *fiber.Ctx) error {
+	report, err := h.retentionSvc.Run(c.UserContext(), true)
+	if err != nil {
+		return utils.Error(c, "Failed to preview retention", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, report, "Retention preview generated")
+}
+
+// RunRetention schedules an immediate retention run
+// @Summary Run retention rules
+// @Tags Retention
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/retention/run [post]
+func (h *Handler) RunRetention(c *fiber.Ctx) error {
+	job, err := h.jobQueue.EnqueueWithOptions(jobs.TypeRetention, nil, jobs.Options{MaxAttempts: 1})
+	if err != nil {
+		return utils.Error(c, "Failed to schedule retention run", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, job, "Retention run scheduled", fiber.StatusAccepted)
+}
+
+// ListLegalHolds returns the events exempt from retention
+// @Summary List legal holds
+// @Tags Retention
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/legal-holds [get]
+func (h *Handler) ListLegalHolds(c *fiber.Ctx) error {
+	holds, err := h.retentionSvc.ListLegalHolds()
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve legal holds", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, holds, "Legal holds retrieved successfully")
+}
+
+// PlaceLegalHold exempts an event from retention rules
+// @Summary Place legal hold
+// @Tags Retention
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param event_id path string true "Event ID"
+// @Param request body LegalHoldRequest true "Hold"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /admin/legal-holds/{event_id} [put]
+func (h *Handler) PlaceLegalHold(c *fiber.Ctx) error {
+	eventID := c.Params("event_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req LegalHoldRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	hold, err := h.retentionSvc.PlaceLegalHold(eventID, req.Reason, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrLegalHoldExists) {
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, hold, "Legal hold placed", fiber.StatusCreated)
+}
+
+// ReleaseLegalHold lets retention rules apply to an event again
+// @Summary Release legal hold
+// @Tags Retention
+// @Produce json
+// @Security BearerAuth
+// @Param event_id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/legal-holds/{event_id} [delete]
+func (h *Handler) ReleaseLegalHold(c *fiber.Ctx) error {
+	eventID := c.Params("event_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.retentionSvc.ReleaseLegalHold(eventID); err != nil {
+		if errors.Is(err, services.ErrLegalHoldNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to release legal hold", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "Legal hold released")
+}
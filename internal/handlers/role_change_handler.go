@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type RequestRoleChangeRequest struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+	Role   string `json:"role" validate:"required,oneof=admin organizer staff"`
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// RequestRoleChange asks for a user's role to be changed
+// @Summary Request role change
+// @Description The change is applied once another admin approves it. Promotions can only be made this way; PUT /admin/users/{id} rejects them.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RequestRoleChangeRequest true "Role change"
+// @Success 201 {object} utils.Response{data=models.RoleChangeRequest}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /admin/role-changes [post]
+func (h *Handler) RequestRoleChange(c *fiber.Ctx) error {
+	var req RequestRoleChangeRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	change, err := h.authSvc.RequestRoleChange(actorID, req.UserID, req.Role, req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserNotFound):
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		case errors.Is(err, services.ErrRoleChangePending):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, change, "Role change requested", fiber.StatusCreated)
+}
+
+// ListRoleChanges returns role change requests
+// @Summary List role change requests
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Only requests of this status (pending, approved, rejected)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /admin/role-changes [get]
+func (h *Handler) ListRoleChanges(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
+
+	changes, total, totalPages, err := h.authSvc.ListRoleChanges(c.Query("status"), page, pageSize)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	meta := &utils.Meta{
+		Page:      page,
+		PageSize:  pageSize,
+		Total:     total,
+		TotalPage: totalPages,
+	}
+
+	return utils.SuccessWithMeta(c, changes, meta, "Role change requests retrieved successfully")
+}
+
+// ApproveRoleChange applies a pending role change
+// @Summary Approve role change
+// @Description Must be done by an admin other than the requester. The user's tokens are revoked, so they log in again with the new role.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role change request ID"
+// @Success 200 {object} utils.Response{data=models.RoleChangeRequest}
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /admin/role-changes/{id}/approve [post]
+func (h *Handler) ApproveRoleChange(c *fiber.Ctx) error {
+	return h.reviewRoleChange(c, true)
+}
+
+// RejectRoleChange closes a pending role change without applying it
+// @Summary Reject role change
+// @Description Must be done by an admin other than the requester.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Role change request ID"
+// @Success 200 {object} utils.Response{data=models.RoleChangeRequest}
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /admin/role-changes/{id}/reject [post]
+func (h *Handler) RejectRoleChange(c *fiber.Ctx) error {
+	return h.reviewRoleChange(c, false)
+}
+
+func (h *Handler) reviewRoleChange(c *fiber.Ctx, approve bool) error {
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return utils.Error(c, "Invalid role change request ID", fiber.StatusBadRequest)
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	review, message := h.authSvc.RejectRoleChange, "Role change rejected"
+	if approve {
+		review, message = h.authSvc.ApproveRoleChange, "Role change approved"
+	}
+	change, err := review(actorID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRoleChangeNotFound):
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		case errors.Is(err, services.ErrRoleChangeSelfReview):
+			return utils.Error(c, err.Error(), fiber.StatusForbidden)
+		case errors.Is(err, services.ErrRoleChangeReviewed), errors.Is(err, services.ErrRoleChangeStale):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, "Failed to review role change", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, change, message)
+}
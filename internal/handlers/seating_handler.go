@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CreateTableRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Capacity int    `json:"capacity" validate:"required,min=1,max=100"`
+	// Reserves the table for a division when auto-assigning
+	Division string `json:"division" validate:"max=100"`
+}
+
+type AssignSeatRequest struct {
+	TableID string `json:"table_id" validate:"required,uuid"`
+	// Omit to take the first free seat
+	SeatNumber int `json:"seat_number" validate:"min=0"`
+}
+
+// seatingError maps seating errors to a response
+func seatingError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrSeatingDayNotFound), errors.Is(err, services.ErrTableNotFound):
+		return utils.Error(c, err.Error(), fiber.StatusNotFound)
+	case errors.Is(err, repositories.ErrSeatTaken), errors.Is(err, repositories.ErrTableFull):
+		return utils.Error(c, err.Error(), fiber.StatusConflict)
+	default:
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+}
+
+// CreateTable adds a table to a seated event day
+// @Summary Create table
+// @Tags Seating
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param day_id path string true "Event day ID"
+// @Param request body CreateTableRequest true "Table"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/days/{day_id}/tables [post]
+func (h *Handler) CreateTable(c *fiber.Ctx) error {
+	eventID, dayID := c.Params("id"), c.Params("day_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(dayID); err != nil {
+		return utils.Error(c, "Invalid day ID", fiber.StatusBadRequest)
+	}
+
+	var req CreateTableRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	table, err := h.seatingSvc.CreateTable(eventID, dayID, services.CreateTableRequest{
+		Name:     req.Name,
+		Capacity: req.Capacity,
+		Division: req.Division,
+	})
+	if err != nil {
+		return seatingError(c, err)
+	}
+
+	return utils.Success(c, table, "Table created successfully", fiber.StatusCreated)
+}
+
+// ListTables returns the tables of an event day
+// @Summary List tables
+// @Tags Seating
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param day_id path string true "Event day ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/days/{day_id}/tables [get]
+func (h *Handler) ListTables(c *fiber.Ctx) error {
+	eventID, dayID := c.Params("id"), c.Params("day_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(dayID); err != nil {
+		return utils.Error(c, "Invalid day ID", fiber.StatusBadRequest)
+	}
+
+	tables, err := h.seatingSvc.ListTables(eventID, dayID)
+	if err != nil {
+		return seatingError(c, err)
+	}
+
+	return utils.Success(c, tables, "Tables retrieved successfully")
+}
+
+// GetDaySeating returns the seating plan of an event day
+// @Summary Seating plan
+// @Description Lists every table with its occupied seats, and how many approved participants have no seat.
+// @Tags Seating
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param day_id path string true "Event day ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/days/{day_id}/seating [get]
+func (h *Handler) GetDaySeating(c *fiber.Ctx) error {
+	eventID, dayID := c.Params("id"), c.Params("day_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(dayID); err != nil {
+		return utils.Error(c, "Invalid day ID", fiber.StatusBadRequest)
+	}
+
+	plan, err := h.seatingSvc.DaySeating(eventID, dayID)
+	if err != nil {
+		return seatingError(c, err)
+	}
+
+	return utils.Success(c, plan, "Seating retrieved successfully")
+}
+
+// AssignSeat seats a participant
+// @Summary Assign seat
+// @Description Seats a participant at a table, moving them if they already have a seat that day. Without seat_number the first free seat is taken.
+// @Tags Seating
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param day_id path string true "Event day ID"
+// @Param participant_id path string true "Participant ID"
+// @Param request body AssignSeatRequest true "Seat"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /events/{id}/days/{day_id}/seating/{participant_id} [put]
+func (h *Handler) AssignSeat(c *fiber.Ctx) error {
+	eventID, dayID := c.Params("id"), c.Params("day_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(dayID); err != nil {
+		return utils.Error(c, "Invalid day ID", fiber.StatusBadRequest)
+	}
+	participantID := c.Params("participant_id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	var req AssignSeatRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	assignment, err := h.seatingSvc.AssignSeat(eventID, dayID, participantID, req.TableID, req.SeatNumber)
+	if err != nil {
+		return seatingError(c, err)
+	}
+
+	return utils.Success(c, assignment, "Seat assigned successfully")
+}
+
+// UnassignSeat frees the seat of a participant
+// @Summary Unassign seat
+// @Tags Seating
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param day_id path string true "Event day ID"
+// @Param participant_id path string true "Participant ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/days/{day_id}/seating/{participant_id} [delete]
+func (h *Handler) UnassignSeat(c *fiber.Ctx) error {
+	eventID, dayID := c.Params("id"), c.Params("day_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(dayID); err != nil {
+		return utils.Error(c, "Invalid day ID", fiber.StatusBadRequest)
+	}
+	participantID := c.Params("participant_id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.seatingSvc.Unassign(eventID, dayID, participantID); err != nil {
+		return seatingError(c, err)
+	}
+
+	return utils.Success(c, nil, "Seat unassigned")
+}
+
+// AutoAssignSeats seats every approved participant without a seat
+// @Summary Auto-assign seats
+// @Description Seats approved participants who have no seat yet, keeping divisions together. Tables reserved for a division only receive that division.
+// @Tags Seating
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param day_id path string true "Event day ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/days/{day_id}/seating/auto-assign [post]
+func (h *Handler) AutoAssignSeats(c *fiber.Ctx) error {
+	eventID, dayID := c.Params("id"), c.Params("day_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(dayID); err != nil {
+		return utils.Error(c, "Invalid day ID", fiber.StatusBadRequest)
+	}
+
+	result, err := h.seatingSvc.AutoAssign(eventID, dayID)
+	if err != nil {
+		return seatingError(c, err)
+	}
+
+	return utils.Success(c, result, "Seats assigned")
+}
+
+// GetBadgeSeats returns the seat of every seated participant for badge
+// printing
+// @Summary Seats for badges
+// @Description One row per participant and seated day. Use format=csv to feed a badge printer.
+// @Tags Seating
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/seating/badges [get]
+func (h *Handler) GetBadgeSeats(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	rows, err := h.seatingSvc.BadgeSeats(eventID)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusNotFound)
+	}
+
+	if c.Query("format") != "csv" {
+		return utils.Success(c, rows, "Badge seats retrieved successfully")
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"participant_id", "name", "division", "day", "table", "seat"})
+	for _, row := range rows {
+		w.Write([]string{
+			row.ParticipantID,
+			row.ParticipantName,
+			row.Division,
+			row.DayLabel,
+			row.TableName,
+			strconv.Itoa(row.SeatNumber),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return utils.Error(c, "Failed to export badge seats", fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="event-%s-seats.csv"`, eventID))
+	return c.Send(buf.Bytes())
+}
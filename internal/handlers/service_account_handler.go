@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CreateServiceAccountRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type IssueServiceTokenRequest struct {
+	// Usually the device the token is installed on
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// CreateServiceAccount creates an account for shared scanner devices
+// @Summary Create scanner service account
+// @Description Service accounts cannot log in. Issue them tokens, and assign them to events like staff: they can only verify for their events. List them with GET /admin/users?role=scanner.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateServiceAccountRequest true "Account"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /admin/service-accounts [post]
+func (h *Handler) CreateServiceAccount(c *fiber.Ctx) error {
+	var req CreateServiceAccountRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	account, err := h.authSvc.CreateServiceAccount(actorID, req.Name)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, account, "Service account created successfully", fiber.StatusCreated)
+}
+
+// IssueServiceToken creates a token for a scanner device
+// @Summary Issue service token
+// @Description The token never expires and is only accepted by the /verify endpoints. It is shown once; revoke it when the device is lost or retired.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Service account ID"
+// @Param request body IssueServiceTokenRequest true "Token"
+// @Success 201 {object} utils.Response{data=services.IssuedServiceToken}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/service-accounts/{id}/tokens [post]
+func (h *Handler) IssueServiceToken(c *fiber.Ctx) error {
+	accountID := c.Params("id")
+	if _, err := uuid.Parse(accountID); err != nil {
+		return utils.Error(c, "Invalid service account ID", fiber.StatusBadRequest)
+	}
+
+	var req IssueServiceTokenRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	issued, err := h.authSvc.IssueServiceToken(actorID, accountID, req.Name)
+	if err != nil {
+		if errors.Is(err, services.ErrServiceAccountNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, issued, "Service token issued successfully", fiber.StatusCreated)
+}
+
+// ListServiceTokens returns the tokens of a service account
+// @Summary List service tokens
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Service account ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/service-accounts/{id}/tokens [get]
+func (h *Handler) ListServiceTokens(c *fiber.Ctx) error {
+	accountID := c.Params("id")
+	if _, err := uuid.Parse(accountID); err != nil {
+		return utils.Error(c, "Invalid service account ID", fiber.StatusBadRequest)
+	}
+
+	tokens, err := h.authSvc.ListServiceTokens(accountID)
+	if err != nil {
+		if errors.Is(err, services.ErrServiceAccountNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to retrieve service tokens", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, tokens, "Service tokens retrieved successfully")
+}
+
+// RevokeServiceToken rejects a service token from now on
+// @Summary Revoke service token
+// @Description Deactivating the service account with DELETE /admin/users/{id} rejects all of its tokens.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Service account ID"
+// @Param token_id path string true "Token ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/service-accounts/{id}/tokens/{token_id} [delete]
+func (h *Handler) RevokeServiceToken(c *fiber.Ctx) error {
+	accountID := c.Params("id")
+	if _, err := uuid.Parse(accountID); err != nil {
+		return utils.Error(c, "Invalid service account ID", fiber.StatusBadRequest)
+	}
+	tokenID := c.Params("token_id")
+	if _, err := uuid.Parse(tokenID); err != nil {
+		return utils.Error(c, "Invalid token ID", fiber.StatusBadRequest)
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	if err := h.authSvc.RevokeServiceToken(actorID, accountID, tokenID); err != nil {
+		if errors.Is(err, services.ErrServiceTokenNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to revoke service token", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "Service token revoked")
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CreateSessionRequest struct {
+	EventDayID  string    `json:"event_day_id" validate:"required,uuid"`
+	Title       string    `json:"title" validate:"required,max=200"`
+	Room        string    `json:"room" validate:"max=100"`
+	Capacity    *int      `json:"capacity" validate:"omitempty,gt=0"`
+	StartsAt    time.Time `json:"starts_at" validate:"required"`
+	EndsAt      time.Time `json:"ends_at" validate:"required"`
+	CreditHours float64   `json:"credit_hours" validate:"gte=0"`
+	// Optional scan code for the session; generated when omitted
+	ActionCode string `json:"action_code" validate:"max=50"`
+}
+
+// CreateSession adds a session to the agenda of an event
+// @Summary Create session
+// @Description Creates a session and the action used to scan attendees into it. Check-ins are refused once capacity is reached.
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body CreateSessionRequest true "Session details"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/sessions [post]
+func (h *Handler) CreateSession(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req CreateSessionRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	session, err := h.sessionSvc.CreateSession(eventID, services.CreateSessionRequest{
+		EventDayID:  req.EventDayID,
+		Title:       req.Title,
+		Room:        req.Room,
+		Capacity:    req.Capacity,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		CreditHours: req.CreditHours,
+		ActionCode:  req.ActionCode,
+	})
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, session, "Session created successfully", fiber.StatusCreated)
+}
+
+// ListSessions returns the agenda of an event
+// @Summary List sessions
+// @Tags Sessions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/sessions [get]
+func (h *Handler) ListSessions(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	sessions, err := h.sessionSvc.ListSessions(eventID)
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve sessions", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, sessions, "Sessions retrieved successfully")
+}
+
+// GetSessionAttendance reports who attended a session
+// @Summary Session attendance
+// @Description Lists the participants scanned into a session with the credit hours earned. Use format=csv for a CPD export.
+// @Tags Sessions
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param session_id path string true "Session ID"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/sessions/{session_id}/attendance [get]
+func (h *Handler) GetSessionAttendance(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	sessionID := c.Params("session_id")
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return utils.Error(c, "Invalid session ID", fiber.StatusBadRequest)
+	}
+
+	attendance, err := h.sessionSvc.GetAttendance(eventID, sessionID)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusNotFound)
+	}
+
+	if c.Query("format") != "csv" {
+		return utils.Success(c, attendance, "Attendance retrieved successfully")
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"participant_id", "name", "email", "division", "session", "checked_in_at", "credit_hours"})
+	for _, a := range attendance.Attendees {
+		w.Write([]string{
+			a.ParticipantID,
+			a.Name,
+			a.Email,
+			a.Division,
+			attendance.Session.Title,
+			a.CheckedInAt.UTC().Format(time.RFC3339),
+			strconv.FormatFloat(a.CreditHours, 'f', -1, 64),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return utils.Error(c, "Failed to export attendance", fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="session-%s-attendance.csv"`, sessionID))
+	return c.Send(buf.Bytes())
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CreateShiftRequest struct {
+	EventDayID string `json:"event_day_id" validate:"required,uuid"`
+	// Gate worked during the shift; every action of the day when omitted
+	ActionID string    `json:"action_id" validate:"omitempty,uuid"`
+	Name     string    `json:"name" validate:"required,max=100"`
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required"`
+}
+
+// CreateShift adds a staff shift to an event
+// @Summary Create shift
+// @Description Once an event has shifts, staff users may only scan during their shifts (plus SHIFT_GRACE_PERIOD) at the shift's gate. Organizers and admins are not restricted.
+// @Tags Shifts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body CreateShiftRequest true "Shift"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/shifts [post]
+func (h *Handler) CreateShift(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req CreateShiftRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	shift, err := h.shiftSvc.CreateShift(eventID, services.CreateShiftRequest{
+		EventDayID: req.EventDayID,
+		ActionID:   req.ActionID,
+		Name:       req.Name,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+	})
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, shift, "Shift created successfully", fiber.StatusCreated)
+}
+
+// ListShifts returns the staff rota of an event
+// @Summary List shifts
+// @Tags Shifts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/shifts [get]
+func (h *Handler) ListShifts(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	shifts, err := h.shiftSvc.ListShifts(eventID)
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve shifts", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, shifts, "Shifts retrieved successfully")
+}
+
+// DeleteShift removes a shift and its staff assignments
+// @Summary Delete shift
+// @Tags Shifts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param shift_id path string true "Shift ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/shifts/{shift_id} [delete]
+func (h *Handler) DeleteShift(c *fiber.Ctx) error {
+	eventID, shiftID := c.Params("id"), c.Params("shift_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(shiftID); err != nil {
+		return utils.Error(c, "Invalid shift ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.shiftSvc.DeleteShift(eventID, shiftID); err != nil {
+		if errors.Is(err, services.ErrShiftNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to delete shift", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "Shift deleted")
+}
+
+// AssignShiftStaff puts a staff user on a shift
+// @Summary Assign staff to shift
+// @Tags Shifts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param shift_id path string true "Shift ID"
+// @Param user_id path string true "Staff user ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/shifts/{shift_id}/staff/{user_id} [put]
+func (h *Handler) AssignShiftStaff(c *fiber.Ctx) error {
+	eventID, shiftID, userID := c.Params("id"), c.Params("shift_id"), c.Params("user_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(shiftID); err != nil {
+		return utils.Error(c, "Invalid shift ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		return utils.Error(c, "Invalid user ID", fiber.StatusBadRequest)
+	}
+
+	shift, err := h.shiftSvc.AssignStaff(eventID, shiftID, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrShiftNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, shift, "Staff assigned to shift")
+}
+
+// UnassignShiftStaff takes a staff user off a shift
+// @Summary Unassign staff from shift
+// @Tags Shifts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param shift_id path string true "Shift ID"
+// @Param user_id path string true "Staff user ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/shifts/{shift_id}/staff/{user_id} [delete]
+func (h *Handler) UnassignShiftStaff(c *fiber.Ctx) error {
+	eventID, shiftID, userID := c.Params("id"), c.Params("shift_id"), c.Params("user_id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(shiftID); err != nil {
+		return utils.Error(c, "Invalid shift ID", fiber.StatusBadRequest)
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		return utils.Error(c, "Invalid user ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.shiftSvc.UnassignStaff(eventID, shiftID, userID); err != nil {
+		if errors.Is(err, services.ErrShiftNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to unassign staff", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "Staff unassigned from shift")
+}
+
+// GetMyShifts returns the current and upcoming shifts of the signed-in user
+// @Summary My shifts
+// @Tags Shifts
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /me/shifts [get]
+func (h *Handler) GetMyShifts(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	shifts, err := h.shiftSvc.MyShifts(userID)
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve shifts", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, shifts, "Shifts retrieved successfully")
+}
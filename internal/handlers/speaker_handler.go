@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+
+	"event-management-backend/internal/jobs"
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CreateSpeakerRequest struct {
+	Name       string   `json:"name" form:"name" validate:"required,max=200"`
+	Email      string   `json:"email" form:"email" validate:"omitempty,email"`
+	Bio        string   `json:"bio" form:"bio" validate:"max=5000"`
+	SessionIDs []string `json:"session_ids" form:"session_ids" validate:"omitempty,dive,uuid"`
+	// Zones the speaker credential opens; defaults to backstage
+	Zones []string `json:"zones" form:"zones" validate:"omitempty,dive,alphanum,max=50"`
+}
+
+// CreateSpeaker adds a speaker to an event
+// @Summary Create speaker
+// @Description Registers a speaker with an optional photo (multipart field "photo") and issues their QR credential, which is also admitted at actions in the speaker's zones.
+// @Tags Speakers
+// @Accept json
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body CreateSpeakerRequest true "Speaker details"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/speakers [post]
+func (h *Handler) CreateSpeaker(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req CreateSpeakerRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	photoPath := ""
+	file, err := c.FormFile("photo")
+	if err == nil && file != nil {
+		if err := utils.ValidateImageFile(file); err != nil {
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
+		if err := utils.ScanUploadedFile(c.UserContext(), h.scanner, file); err != nil {
+			if errors.Is(err, utils.ErrInfected) {
+				return utils.Error(c, "File rejected by virus scan", fiber.StatusBadRequest)
+			}
+			middleware.GetLogger(c).WithError(err).Error("virus scan failed")
+			return utils.Error(c, "File could not be scanned, try again later", fiber.StatusServiceUnavailable)
+		}
+
+		filename := utils.GenerateUniqueFilename(file.Filename)
+		if err := utils.SaveUploadedFile(file, h.cfg.PhotoDir, filename); err != nil {
+			return utils.Error(c, "Failed to save photo", fiber.StatusInternalServerError)
+		}
+		photoPath = "/photos/" + filename
+	}
+
+	speaker, err := h.speakerSvc.CreateSpeaker(eventID, services.CreateSpeakerRequest{
+		Name:       req.Name,
+		Email:      req.Email,
+		Bio:        req.Bio,
+		PhotoPath:  photoPath,
+		SessionIDs: req.SessionIDs,
+		Zones:      req.Zones,
+	})
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	if speaker.PhotoPath != "" {
+		if _, err := h.jobQueue.Enqueue(jobs.TypePhotoVariants, jobs.SpeakerPayload{SpeakerID: speaker.ID.String()}); err != nil {
+			middleware.GetLogger(c).WithError(err).Warn("failed to enqueue photo processing")
+		}
+	}
+
+	return utils.Success(c, speaker, "Speaker created successfully", fiber.StatusCreated)
+}
+
+// ListSpeakers returns the speakers of an event with their sessions
+// @Summary List speakers
+// @Tags Speakers
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/speakers [get]
+func (h *Handler) ListSpeakers(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	speakers, err := h.speakerSvc.ListSpeakers(eventID)
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve speakers", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, speakers, "Speakers retrieved successfully")
+}
+
+// GetSpeaker returns a speaker with their sessions and check-in history
+// @Summary Get speaker
+// @Tags Speakers
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param speaker_id path string true "Speaker ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/speakers/{speaker_id} [get]
+func (h *Handler) GetSpeaker(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	speakerID := c.Params("speaker_id")
+	if _, err := uuid.Parse(speakerID); err != nil {
+		return utils.Error(c, "Invalid speaker ID", fiber.StatusBadRequest)
+	}
+
+	speaker, err := h.speakerSvc.GetSpeaker(eventID, speakerID)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusNotFound)
+	}
+
+	return utils.Success(c, speaker, "Speaker retrieved successfully")
+}
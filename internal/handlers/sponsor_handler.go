@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CreateSponsorRequest struct {
+	Name string `json:"name" validate:"required,max=200"`
+}
+
+type CreateSponsorStaffRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type CaptureLeadRequest struct {
+	QRCode string `json:"qr_code" validate:"required"`
+	// The participant agreed to share their contact details with the sponsor
+	Consent bool   `json:"consent"`
+	Notes   string `json:"notes" validate:"max=2000"`
+}
+
+// CreateSponsor adds a sponsor to an event
+// @Summary Create sponsor
+// @Tags Sponsors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body CreateSponsorRequest true "Sponsor details"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/sponsors [post]
+func (h *Handler) CreateSponsor(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req CreateSponsorRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	sponsor, err := h.sponsorSvc.CreateSponsor(eventID, req.Name)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, sponsor, "Sponsor created successfully", fiber.StatusCreated)
+}
+
+// ListSponsors returns the sponsors of an event
+// @Summary List sponsors
+// @Tags Sponsors
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/sponsors [get]
+func (h *Handler) ListSponsors(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	sponsors, err := h.sponsorSvc.ListSponsors(eventID)
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve sponsors", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, sponsors, "Sponsors retrieved successfully")
+}
+
+// CreateSponsorStaff creates a booth staff account for a sponsor
+// @Summary Create sponsor booth staff
+// @Description Creates a user with the sponsor role. Sponsor staff can only capture and export leads for their sponsor.
+// @Tags Sponsors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param sponsor_id path string true "Sponsor ID"
+// @Param request body CreateSponsorStaffRequest true "Account details"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/sponsors/{sponsor_id}/staff [post]
+func (h *Handler) CreateSponsorStaff(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	sponsorID := c.Params("sponsor_id")
+	if _, err := uuid.Parse(sponsorID); err != nil {
+		return utils.Error(c, "Invalid sponsor ID", fiber.StatusBadRequest)
+	}
+
+	var req CreateSponsorStaffRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	user, err := h.sponsorSvc.CreateStaff(eventID, sponsorID, req.Email, req.Password)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, user, "Sponsor staff created successfully", fiber.StatusCreated)
+}
+
+// GetSponsorLeads returns the leads captured by a sponsor
+// @Summary List sponsor leads
+// @Description Contact details are only included for participants who consented. Use format=csv to export.
+// @Tags Sponsors
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param sponsor_id path string true "Sponsor ID"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/sponsors/{sponsor_id}/leads [get]
+func (h *Handler) GetSponsorLeads(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	sponsorID := c.Params("sponsor_id")
+	if _, err := uuid.Parse(sponsorID); err != nil {
+		return utils.Error(c, "Invalid sponsor ID", fiber.StatusBadRequest)
+	}
+
+	sponsor, err := h.sponsorSvc.GetSponsor(eventID, sponsorID)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusNotFound)
+	}
+
+	return h.sendLeads(c, sponsor)
+}
+
+// CaptureLead records a participant scanned at the sponsor's booth
+// @Summary Capture lead
+// @Description Scans a participant QR code for the sponsor of the authenticated booth staff. Scanning the same participant again updates consent and notes.
+// @Tags Leads
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CaptureLeadRequest true "Scanned QR code"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /leads [post]
+func (h *Handler) CaptureLead(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	var req CaptureLeadRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	lead, err := h.sponsorSvc.CaptureLead(userID, services.CaptureLeadRequest{
+		QRCodeData: req.QRCode,
+		Consent:    req.Consent,
+		Notes:      req.Notes,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrNotSponsorStaff) {
+			return utils.Error(c, err.Error(), fiber.StatusForbidden)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, lead, "Lead captured successfully")
+}
+
+// ListMyLeads returns the leads of the authenticated booth staff's sponsor
+// @Summary List own leads
+// @Description Contact details are only included for participants who consented. Use format=csv to export.
+// @Tags Leads
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /leads [get]
+func (h *Handler) ListMyLeads(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	sponsor, err := h.sponsorSvc.SponsorOf(userID)
+	if err != nil {
+		return utils.Error(c, services.ErrNotSponsorStaff.Error(), fiber.StatusForbidden)
+	}
+
+	return h.sendLeads(c, sponsor)
+}
+
+// sendLeads responds with the leads of sponsor as JSON, or as a CSV download
+// with ?format=csv
+func (h *Handler) sendLeads(c *fiber.Ctx, sponsor *models.Sponsor) error {
+	leads, err := h.sponsorSvc.ListLeads(sponsor.ID.String())
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve leads", fiber.StatusInternalServerError)
+	}
+
+	if c.Query("format") != "csv" {
+		return utils.Success(c, leads, "Leads retrieved successfully")
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"participant_id", "name", "division", "email", "phone", "consent", "notes", "captured_at"})
+	for _, lead := range leads {
+		w.Write([]string{
+			lead.ParticipantID,
+			lead.Name,
+			lead.Division,
+			lead.Email,
+			lead.Phone,
+			strconv.FormatBool(lead.Consent),
+			lead.Notes,
+			lead.CapturedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return utils.Error(c, "Failed to export leads", fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="sponsor-%s-leads.csv"`, sponsor.ID))
+	return c.Send(buf.Bytes())
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+
+	"event-management-backend/internal/jobs"
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/ratelimit"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TicketLookupRequest struct {
+	Email     string `json:"email" validate:"required,email"`
+	EventSlug string `json:"event_slug" validate:"required,max=255"`
+	// Ticket code printed on the ticket, e.g. K7QD-2MXA
+	Code string `json:"code" validate:"max=20"`
+}
+
+// LookupTicket lets participants find their registration
+// @Summary Look up a ticket
+// @Description With the ticket code printed on the ticket, returns the registration status and QR code. Without a code, or with a wrong one, the ticket is sent to the participant's registered contact instead and the same answer is given whether or not a registration matches, so the endpoint cannot tell who registered.
+// @Tags Participants
+// @Accept json
+// @Produce json
+// @Param request body TicketLookupRequest true "Lookup"
+// @Success 200 {object} utils.Response "The registration"
+// @Success 202 {object} utils.Response "The ticket was sent if a registration matches"
+// @Failure 400 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /tickets/lookup [post]
+func (h *Handler) LookupTicket(c *fiber.Ctx) error {
+	var req TicketLookupRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	lookup, err := h.participantSvc.LookupTicket(req.EventSlug, req.Email, req.Code)
+	switch {
+	case err == nil:
+		return utils.Success(c, lookup, "Ticket retrieved successfully")
+	case errors.Is(err, services.ErrTicketCodeMismatch):
+		h.sendLookedUpTicket(c, lookup.ParticipantID.String())
+	case !errors.Is(err, services.ErrTicketNotFound):
+		return utils.Error(c, "Failed to look up the ticket", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "If a registration matches, its ticket has been sent to the registered contact", fiber.StatusAccepted)
+}
+
+// sendLookedUpTicket queues the ticket of a participant found by a lookup.
+// Lookups share the per participant limit of staff resends; beyond it the
+// ticket is silently not sent, as the answer must not differ.
+func (h *Handler) sendLookedUpTicket(c *fiber.Ctx, participantID string) {
+	log := middleware.GetLogger(c).WithField("participant_id", participantID)
+	if h.rateLimits != nil {
+		limit := ratelimit.PerHour(h.cfg.RateLimitResendTicketPerHour, h.cfg.RateLimitResendTicketPerHour)
+		allowed, _, err := h.rateLimits.Take(c.UserContext(), "ratelimit:resend-ticket:"+participantID, limit)
+		if err != nil {
+			log.WithError(err).Warn("Rate limit check failed")
+		} else if !allowed {
+			return
+		}
+	}
+
+	if _, err := h.jobQueue.Enqueue(jobs.TypeTicketResend, jobs.ParticipantPayload{ParticipantID: participantID}); err != nil {
+		log.WithError(err).Warn("failed to enqueue ticket resend")
+	}
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type TicketTypeRequest struct {
+	Name  string  `json:"name" validate:"required,max=100"`
+	Price float64 `json:"price" validate:"min=0"`
+	// Unlimited when omitted
+	Quota *int `json:"quota" validate:"omitempty,gt=0"`
+	// Sale window; open-ended on either side when omitted
+	SalesStartAt *time.Time `json:"sales_start_at"`
+	SalesEndAt   *time.Time `json:"sales_end_at"`
+}
+
+func (req TicketTypeRequest) toService() services.TicketTypeRequest {
+	return services.TicketTypeRequest{
+		Name:         req.Name,
+		Price:        req.Price,
+		Quota:        req.Quota,
+		SalesStartAt: req.SalesStartAt,
+		SalesEndAt:   req.SalesEndAt,
+	}
+}
+
+// ListTicketTypes returns the ticket types of an event
+// @Summary List ticket types
+// @Description Ticket types with what is left of them. Registrations for events with ticket types must pick one that is on sale.
+// @Tags Ticket Types
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response{data=[]services.TicketTypeAvailability}
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/ticket-types [get]
+func (h *Handler) ListTicketTypes(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	ticketTypes, err := h.eventSvc.ListTicketTypes(eventID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to retrieve ticket types", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, ticketTypes, "Ticket types retrieved successfully")
+}
+
+// CreateTicketType adds a ticket type to an event
+// @Summary Create ticket type
+// @Tags Ticket Types
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body TicketTypeRequest true "Ticket type"
+// @Success 201 {object} utils.Response{data=models.TicketType}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/ticket-types [post]
+func (h *Handler) CreateTicketType(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req TicketTypeRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	ticketType, err := h.eventSvc.CreateTicketType(eventID, req.toService())
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, ticketType, "Ticket type created successfully", fiber.StatusCreated)
+}
+
+// UpdateTicketType replaces the details of a ticket type
+// @Summary Update ticket type
+// @Tags Ticket Types
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param ticket_type_id path string true "Ticket type ID"
+// @Param request body TicketTypeRequest true "Ticket type"
+// @Success 200 {object} utils.Response{data=models.TicketType}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/ticket-types/{ticket_type_id} [put]
+func (h *Handler) UpdateTicketType(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	ticketTypeID := c.Params("ticket_type_id")
+	if _, err := uuid.Parse(ticketTypeID); err != nil {
+		return utils.Error(c, "Invalid ticket type ID", fiber.StatusBadRequest)
+	}
+
+	var req TicketTypeRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	ticketType, err := h.eventSvc.UpdateTicketType(eventID, ticketTypeID, req.toService())
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownTicketType) {
+			return utils.Error(c, "Ticket type not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, ticketType, "Ticket type updated successfully")
+}
+
+// DeleteTicketType removes a ticket type nobody registered for
+// @Summary Delete ticket type
+// @Description Ticket types with registrations cannot be deleted; end their sale window instead.
+// @Tags Ticket Types
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param ticket_type_id path string true "Ticket type ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /events/{id}/ticket-types/{ticket_type_id} [delete]
+func (h *Handler) DeleteTicketType(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	ticketTypeID := c.Params("ticket_type_id")
+	if _, err := uuid.Parse(ticketTypeID); err != nil {
+		return utils.Error(c, "Invalid ticket type ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.eventSvc.DeleteTicketType(eventID, ticketTypeID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownTicketType):
+			return utils.Error(c, "Ticket type not found", fiber.StatusNotFound)
+		case errors.Is(err, services.ErrTicketTypeSold):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, "Failed to delete ticket type", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "Ticket type deleted successfully")
+}
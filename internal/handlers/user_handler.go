@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type UpdateUserRequest struct {
+	Email    *string `json:"email" validate:"omitempty,email"`
+	Password *string `json:"password" validate:"omitempty"`
+	Role     *string `json:"role" validate:"omitempty,oneof=admin organizer staff"`
+	IsActive *bool   `json:"is_active"`
+}
+
+var userRoles = map[string]bool{"admin": true, "organizer": true, "staff": true, "sponsor": true, services.RoleScanner: true}
+
+// ListUsers returns a paginated list of users
+// @Summary List users
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param role query string false "Only users of this role (admin, organizer, staff, sponsor, scanner)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /admin/users [get]
+func (h *Handler) ListUsers(c *fiber.Ctx) error {
+	role := c.Query("role")
+	if role != "" && !userRoles[role] {
+		return utils.Error(c, "Invalid role", fiber.StatusBadRequest)
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
+
+	users, total, totalPages, err := h.authSvc.ListUsers(role, page, pageSize)
+	if err != nil {
+		return utils.Error(c, "Failed to fetch users", fiber.StatusInternalServerError)
+	}
+
+	meta := &utils.Meta{
+		Page:      page,
+		PageSize:  pageSize,
+		Total:     total,
+		TotalPage: totalPages,
+	}
+
+	return utils.SuccessWithMeta(c, users, meta, "Users retrieved successfully")
+}
+
+// UpdateUser changes a user's email, password, role or active state
+// @Summary Update user
+// @Description Only the given fields are changed. Admins cannot demote or deactivate themselves. Promotions are rejected; request them at POST /admin/role-changes.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body UpdateUserRequest true "Fields to change"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/users/{id} [put]
+func (h *Handler) UpdateUser(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		return utils.Error(c, "Invalid user ID", fiber.StatusBadRequest)
+	}
+
+	var req UpdateUserRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	user, err := h.authSvc.UpdateUser(actorID, userID, services.UpdateUserRequest{
+		Email:    req.Email,
+		Password: req.Password,
+		Role:     req.Role,
+		IsActive: req.IsActive,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, user, "User updated successfully")
+}
+
+// GetUserActivity shows whether an account is being used
+// @Summary Get user activity
+// @Description Last login, recent logins with their IP, and the verifications the user made per event in the last days.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param days query int false "Days of verifications to count (max 365)" default(30)
+// @Success 200 {object} utils.Response{data=services.UserActivity}
+// @Failure 404 {object} utils.Response
+// @Router /admin/users/{id}/activity [get]
+func (h *Handler) GetUserActivity(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		return utils.Error(c, "Invalid user ID", fiber.StatusBadRequest)
+	}
+	days, _ := strconv.Atoi(c.Query("days", "30"))
+
+	activity, err := h.authSvc.GetUserActivity(userID, days)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to fetch user activity", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, activity, "User activity retrieved successfully")
+}
+
+// RevokeUserTokens logs a user out everywhere
+// @Summary Revoke all tokens of a user
+// @Description Every token issued to the user so far is rejected. The user can log in again.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/users/{id}/revoke-tokens [post]
+func (h *Handler) RevokeUserTokens(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		return utils.Error(c, "Invalid user ID", fiber.StatusBadRequest)
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	if err := h.authSvc.RevokeUserTokens(actorID, userID); err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to revoke tokens", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "All tokens of the user have been revoked")
+}
+
+type DeleteUserRequest struct {
+	// keep (default): they stay in the deleted user's name; reassign: they
+	// move to reassign_to; anonymize: the deleted user's email is replaced
+	Verifications string `json:"verifications" validate:"omitempty,oneof=keep reassign anonymize"`
+	ReassignTo    string `json:"reassign_to" validate:"omitempty,uuid"`
+}
+
+// DeleteUser soft deletes a user
+// @Summary Delete user
+// @Description Deactivates the user, hides them from user listings and removes their event assignments. The verifications they recorded are kept in their name, moved to another user, or kept with the user anonymized. Users are never deleted for good while logs reference them.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body DeleteUserRequest false "What happens to the user's verifications"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/users/{id}/delete [post]
+func (h *Handler) DeleteUser(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		return utils.Error(c, "Invalid user ID", fiber.StatusBadRequest)
+	}
+
+	var req DeleteUserRequest
+	if len(c.Body()) > 0 {
+		if err := middleware.ValidateBody(&req)(c); err != nil {
+			return err
+		}
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	if err := h.authSvc.DeleteUser(actorID, userID, services.DeleteUserRequest{
+		Verifications: req.Verifications,
+		ReassignTo:    req.ReassignTo,
+	}); err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, nil, "User deleted")
+}
+
+// DeactivateUser blocks a user from logging in
+// @Summary Deactivate user
+// @Description The account is kept and can be reactivated by updating is_active.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/users/{id} [delete]
+func (h *Handler) DeactivateUser(c *fiber.Ctx) error {
+	userID := c.Params("id")
+	if _, err := uuid.Parse(userID); err != nil {
+		return utils.Error(c, "Invalid user ID", fiber.StatusBadRequest)
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	if err := h.authSvc.DeactivateUser(actorID, userID); err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		}
+		if errors.Is(err, services.ErrModifyOwnAccount) {
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
+		return utils.Error(c, "Failed to deactivate user", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "User deactivated")
+}
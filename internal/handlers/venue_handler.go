@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type VenueRequest struct {
+	Name      string   `json:"name" validate:"required,max=200"`
+	Address   string   `json:"address" validate:"max=500"`
+	Latitude  *float64 `json:"latitude" validate:"omitempty,latitude"`
+	Longitude *float64 `json:"longitude" validate:"omitempty,longitude"`
+	Capacity  *int     `json:"capacity" validate:"omitempty,gt=0"`
+}
+
+func (req VenueRequest) toService() services.VenueRequest {
+	return services.VenueRequest{
+		Name:      req.Name,
+		Address:   req.Address,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		Capacity:  req.Capacity,
+	}
+}
+
+// ListVenues returns venues by name
+// @Summary List venues
+// @Tags Venues
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "Part of the name or address"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} utils.Response{data=[]models.Venue}
+// @Router /venues [get]
+func (h *Handler) ListVenues(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
+
+	venues, total, totalPages, err := h.eventSvc.ListVenues(c.Query("search"), page, pageSize)
+	if err != nil {
+		return utils.Error(c, "Failed to fetch venues", fiber.StatusInternalServerError)
+	}
+
+	meta := &utils.Meta{
+		Page:      page,
+		PageSize:  pageSize,
+		Total:     total,
+		TotalPage: totalPages,
+	}
+	return utils.SuccessWithMeta(c, venues, meta, "Venues retrieved successfully")
+}
+
+// CreateVenue adds a venue events can be held at
+// @Summary Create venue
+// @Tags Venues
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body VenueRequest true "Venue"
+// @Success 201 {object} utils.Response{data=models.Venue}
+// @Failure 400 {object} utils.Response
+// @Router /venues [post]
+func (h *Handler) CreateVenue(c *fiber.Ctx) error {
+	var req VenueRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	venue, err := h.eventSvc.CreateVenue(req.toService())
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, venue, "Venue created successfully", fiber.StatusCreated)
+}
+
+// GetVenue returns a venue
+// @Summary Get venue
+// @Tags Venues
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Venue ID"
+// @Success 200 {object} utils.Response{data=models.Venue}
+// @Failure 404 {object} utils.Response
+// @Router /venues/{id} [get]
+func (h *Handler) GetVenue(c *fiber.Ctx) error {
+	venueID := c.Params("id")
+	if _, err := uuid.Parse(venueID); err != nil {
+		return utils.Error(c, "Invalid venue ID", fiber.StatusBadRequest)
+	}
+
+	venue, err := h.eventSvc.GetVenue(venueID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownVenue) {
+			return utils.Error(c, "Venue not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to fetch venue", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, venue, "Venue retrieved successfully")
+}
+
+// UpdateVenue replaces the details of a venue
+// @Summary Update venue
+// @Tags Venues
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Venue ID"
+// @Param request body VenueRequest true "Venue"
+// @Success 200 {object} utils.Response{data=models.Venue}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /venues/{id} [put]
+func (h *Handler) UpdateVenue(c *fiber.Ctx) error {
+	venueID := c.Params("id")
+	if _, err := uuid.Parse(venueID); err != nil {
+		return utils.Error(c, "Invalid venue ID", fiber.StatusBadRequest)
+	}
+
+	var req VenueRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	venue, err := h.eventSvc.UpdateVenue(venueID, req.toService())
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownVenue) {
+			return utils.Error(c, "Venue not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, venue, "Venue updated successfully")
+}
+
+// DeleteVenue removes a venue no event is held at
+// @Summary Delete venue
+// @Tags Venues
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Venue ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "Events or days are held at the venue"
+// @Router /venues/{id} [delete]
+func (h *Handler) DeleteVenue(c *fiber.Ctx) error {
+	venueID := c.Params("id")
+	if _, err := uuid.Parse(venueID); err != nil {
+		return utils.Error(c, "Invalid venue ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.eventSvc.DeleteVenue(venueID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownVenue):
+			return utils.Error(c, "Venue not found", fiber.StatusNotFound)
+		case errors.Is(err, repositories.ErrVenueInUse):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, "Failed to delete venue", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, nil, "Venue deleted successfully")
+}
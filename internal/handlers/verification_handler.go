@@ -50,6 +50,7 @@ type VerificationHistoryResponse struct {
 	Page          int                  `json:"page"`
 	PageSize      int                  `json:"page_size"`
 	TotalPages    int                  `json:"total_pages"`
+	NextCursor    string               `json:"next_cursor,omitempty"`
 }
 
 // VerificationDetail represents detailed verification information
@@ -169,6 +170,7 @@ func (h *VerificationHandler) GetParticipantVerifications(c *fiber.Ctx) error {
 // @Param id path string true "Event ID"
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(20)
+// @Param cursor query string false "Keyset cursor; pass empty for the first page, then next_cursor"
 // @Param date_from query string false "Start date (RFC3339)"
 // @Param date_to query string false "End date (RFC3339)"
 // @Param action_id query string false "Filter by action ID"
@@ -189,8 +191,13 @@ func (h *VerificationHandler) GetEventVerifications(c *fiber.Ctx) error {
 		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
 	}
 
-	// Get verifications
-	verificationList, err := h.verificationService.GetEventVerifications(eventID, filters)
+	// Get verifications, using keyset pagination when a cursor is supplied
+	var verificationList *services.VerificationList
+	if cursor, ok := cursorParam(c); ok {
+		verificationList, err = h.verificationService.GetEventVerificationsByCursor(eventID, cursor, filters.PageSize)
+	} else {
+		verificationList, err = h.verificationService.GetEventVerifications(eventID, filters)
+	}
 	if err != nil {
 		return h.handleVerificationError(c, err)
 	}
@@ -343,15 +350,15 @@ func (h *VerificationHandler) GetDailyVerifications(c *fiber.Ctx) error {
 func (h *VerificationHandler) handleVerificationError(c *fiber.Ctx, err error) error {
 	if verr, ok := err.(*services.VerificationError); ok {
 		switch verr.Code {
-		case services.ErrInvalidInput, services.ErrInvalidQRCode:
+		case services.ErrInvalidInput, services.ErrInvalidQRCode, services.ErrDeviceNotFound:
 			return utils.Error(c, verr.Message, fiber.StatusBadRequest)
-		case services.ErrParticipantNotFound, services.ErrActionNotFound, services.ErrEventNotFound:
+		case services.ErrParticipantNotFound, services.ErrSpeakerNotFound, services.ErrActionNotFound, services.ErrEventNotFound:
 			return utils.Error(c, verr.Message, fiber.StatusNotFound)
 		case services.ErrVerifierNotFound:
 			return utils.Error(c, verr.Message, fiber.StatusUnauthorized)
-		case services.ErrPaymentRequired, services.ErrAlreadyVerified, services.ErrActionInactive:
+		case services.ErrPaymentRequired, services.ErrAlreadyVerified, services.ErrActionInactive, services.ErrSessionFull, services.ErrDayFull, services.ErrWaiverNotSigned, services.ErrMealQuotaReached:
 			return utils.Error(c, verr.Message, fiber.StatusConflict)
-		case services.ErrEventMismatch, services.ErrEventNotStarted:
+		case services.ErrEventMismatch, services.ErrEventNotStarted, services.ErrActionNotOpen, services.ErrZoneRestricted, services.ErrNotApproved, services.ErrMealMismatch, services.ErrNotOnShift, services.ErrCredentialRevoked, services.ErrRegistrationCancelled:
 			return utils.Error(c, verr.Message, fiber.StatusForbidden)
 		case services.ErrPermissionDenied:
 			return utils.Error(c, verr.Message, fiber.StatusForbidden)
@@ -422,7 +429,7 @@ func (h *VerificationHandler) transformVerificationsToDetail(verifications []*mo
 			ParticipantName: log.Participant.Name,
 			ActionName:      log.Action.Name,
 			ActionCode:      log.Action.Code,
-			VerifiedBy:      log.Verifier.Email,
+			VerifiedBy:      verifiedBy(log),
 			VerifiedAt:      log.VerifiedAt,
 			EventName:       log.Participant.Event.Title,
 		}
@@ -432,6 +439,14 @@ func (h *VerificationHandler) transformVerificationsToDetail(verifications []*mo
 	return details
 }
 
+// verifiedBy names who made a scan: the verifier's email, or the API key
+func verifiedBy(log *models.ActionLog) string {
+	if log.APIKey != nil {
+		return "API key: " + log.APIKey.Name
+	}
+	return log.Verifier.Email
+}
+
 // transformToVerificationHistoryResponse transforms service response to HTTP response
 func (h *VerificationHandler) transformToVerificationHistoryResponse(list *services.VerificationList) *VerificationHistoryResponse {
 	var verifications []VerificationDetail
@@ -443,7 +458,7 @@ func (h *VerificationHandler) transformToVerificationHistoryResponse(list *servi
 			ParticipantName: log.Participant.Name,
 			ActionName:      log.Action.Name,
 			ActionCode:      log.Action.Code,
-			VerifiedBy:      log.Verifier.Email,
+			VerifiedBy:      verifiedBy(log),
 			VerifiedAt:      log.VerifiedAt,
 			EventName:       log.Participant.Event.Title,
 		}
@@ -456,6 +471,7 @@ func (h *VerificationHandler) transformToVerificationHistoryResponse(list *servi
 		Page:          list.Page,
 		PageSize:      list.PageSize,
 		TotalPages:    list.TotalPages,
+		NextCursor:    list.NextCursor,
 	}
 }
 
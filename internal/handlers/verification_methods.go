@@ -14,12 +14,19 @@ import (
 type VerifyActionRequest struct {
 	QRCode     string `json:"qr_code" validate:"required"`
 	ActionCode string `json:"action_code" validate:"required"`
+	// ID returned by POST /devices/register, recorded on the scan
+	DeviceID string `json:"device_id" validate:"omitempty,uuid"`
 }
 
 func (h *Handler) VerifyAction(c *fiber.Ctx) error {
-	verifierID, err := middleware.GetUserIDFromContext(c)
-	if err != nil {
-		return utils.Error(c, err.Error(), fiber.StatusUnauthorized)
+	key := apiKeyFromContext(c)
+	verifierID := ""
+	if key == nil {
+		var err error
+		verifierID, err = middleware.GetUserIDFromContext(c)
+		if err != nil {
+			return utils.Error(c, err.Error(), fiber.StatusUnauthorized)
+		}
 	}
 
 	var req VerifyActionRequest
@@ -31,22 +38,80 @@ func (h *Handler) VerifyAction(c *fiber.Ctx) error {
 		QRCodeData: req.QRCode,
 		ActionCode: req.ActionCode,
 		VerifierID: verifierID,
+		DeviceID:   req.DeviceID,
+	}
+
+	// API keys may only verify participants of the events they were issued
+	// for, and are recorded as the verifier; speaker check-ins are left to
+	// staff
+	if key != nil {
+		if services.IsSpeakerCredential(req.QRCode) {
+			return utils.Error(c, "API keys cannot check in speakers", fiber.StatusForbidden)
+		}
+		verifyReq.APIKeyID = key.ID.String()
+		verifyReq.EventIDs = services.APIKeyEventIDs(key)
+	} else {
+		// Staff may only verify for the events they are assigned to, and
+		// that their token was issued for
+		role, _ := c.Locals("user_role").(string)
+		eventIDs, err := h.eventSvc.StaffEventIDs(verifierID, role)
+		if err != nil {
+			return utils.Error(c, "Failed to check event assignments", fiber.StatusInternalServerError)
+		}
+		claims, err := middleware.GetTokenClaims(c)
+		if err != nil {
+			return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+		}
+		verifyReq.EventIDs = services.IntersectEventScopes(eventIDs, claims.EventIDs)
+	}
+
+	// Speaker credentials are scanned at the same doors as tickets
+	if services.IsSpeakerCredential(req.QRCode) {
+		result, err := h.speakerSvc.CheckIn(verifyReq)
+		if err != nil {
+			if isVerifierForbidden(err) {
+				return utils.Error(c, err.Error(), fiber.StatusForbidden)
+			}
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
+		return utils.Success(c, result, "Speaker checked in successfully")
 	}
 
 	result, err := h.verifySvc.VerifyParticipantAction(verifyReq)
 	if err != nil {
+		if isVerifierForbidden(err) {
+			return utils.Error(c, err.Error(), fiber.StatusForbidden)
+		}
 		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
 	}
 
 	return utils.Success(c, result, "Action verified successfully")
 }
 
+// isVerifierForbidden reports whether a verification failed because of who
+// scanned, rather than what was scanned
+func isVerifierForbidden(err error) bool {
+	switch services.GetVerificationErrorCode(err) {
+	case services.ErrPermissionDenied, services.ErrNotOnShift:
+		return true
+	}
+	return false
+}
+
 func (h *Handler) GetParticipantVerifications(c *fiber.Ctx) error {
 	participantID := c.Params("id")
 	if _, err := uuid.Parse(participantID); err != nil {
 		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
 	}
 
+	participant, err := h.participantSvc.GetParticipant(participantID)
+	if err != nil {
+		return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+	}
+	if err := h.checkEventAccess(c, participant.EventID.String()); err != nil {
+		return eventAccessError(c, err)
+	}
+
 	verifications, err := h.verifySvc.GetParticipantVerificationHistory(participantID)
 	if err != nil {
 		return utils.Error(c, "Failed to fetch verifications", fiber.StatusInternalServerError)
@@ -55,6 +120,43 @@ func (h *Handler) GetParticipantVerifications(c *fiber.Ctx) error {
 	return utils.Success(c, verifications, "Verifications retrieved successfully")
 }
 
+// GetParticipantStatus returns the checklist of a participant
+// @Summary Get participant check-in status
+// @Description Lists every active action of the participant's event in agenda order, with whether the participant was verified for it, when and by whom, so the scanner app can show a checklist after a scan.
+// @Tags Verification
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Participant ID"
+// @Success 200 {object} utils.Response{data=services.ParticipantStatus}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response "Not assigned to the participant's event"
+// @Failure 404 {object} utils.Response
+// @Router /participants/{id}/status [get]
+func (h *Handler) GetParticipantStatus(c *fiber.Ctx) error {
+	participantID := c.Params("id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	participant, err := h.participantSvc.GetParticipant(participantID)
+	if err != nil {
+		return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+	}
+	if err := h.checkEventAccess(c, participant.EventID.String()); err != nil {
+		return eventAccessError(c, err)
+	}
+
+	status, err := h.verifySvc.GetParticipantStatus(participantID)
+	if err != nil {
+		if services.GetVerificationErrorCode(err) == services.ErrParticipantNotFound {
+			return utils.Error(c, "Participant not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to fetch participant status", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, status, "Participant status retrieved successfully")
+}
+
 func (h *Handler) GetEventVerifications(c *fiber.Ctx) error {
 	eventID := c.Params("id")
 	if _, err := uuid.Parse(eventID); err != nil {
@@ -64,6 +166,22 @@ func (h *Handler) GetEventVerifications(c *fiber.Ctx) error {
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
 
+	if cursor, ok := cursorParam(c); ok {
+		result, err := h.verifySvc.GetEventVerificationsByCursor(eventID, cursor, pageSize)
+		if err != nil {
+			if services.GetVerificationErrorCode(err) == services.ErrInvalidInput {
+				return utils.Error(c, "Invalid cursor", fiber.StatusBadRequest)
+			}
+			return utils.Error(c, "Failed to fetch verification logs", fiber.StatusInternalServerError)
+		}
+
+		meta := &utils.Meta{
+			PageSize:   result.PageSize,
+			NextCursor: result.NextCursor,
+		}
+		return utils.SuccessWithMeta(c, result.Verifications, meta, "Verification logs retrieved successfully")
+	}
+
 	filters := &services.VerificationFilters{
 		Page:     page,
 		PageSize: pageSize,
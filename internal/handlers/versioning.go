@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	APIVersionV1 = "v1"
+	APIVersionV2 = "v2"
+)
+
+// presenter converts a service result into the DTO of a specific API version.
+// It returns false when it does not handle the given value.
+type presenter func(data interface{}) (interface{}, bool)
+
+// presenters lists the DTO transformers per API version. Versions without an
+// entry (v1) return service models as-is so existing clients keep working.
+var presenters = map[string][]presenter{
+	APIVersionV2: {
+		presentEventV2,
+		presentParticipantV2,
+		presentParticipantDetailV2,
+		presentRegistrationV2,
+		presentEventLandingV2,
+		presentEventCancellationV2,
+	},
+}
+
+// present shapes data for the API version of the current request
+func present(c *fiber.Ctx, data interface{}) interface{} {
+	for _, p := range presenters[middleware.GetAPIVersion(c)] {
+		if out, ok := p(data); ok {
+			return out
+		}
+	}
+	return data
+}
+
+func presentEventV2(data interface{}) (interface{}, bool) {
+	switch v := data.(type) {
+	case *models.Event:
+		return NewEventV2(v), true
+	case []models.Event:
+		out := make([]EventV2, 0, len(v))
+		for i := range v {
+			out = append(out, NewEventV2(&v[i]))
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+func presentEventLandingV2(data interface{}) (interface{}, bool) {
+	v, ok := data.(*services.EventLanding)
+	if !ok {
+		return nil, false
+	}
+	return EventLandingV2{
+		Event:        NewEventV2(v.Event),
+		Availability: v.Availability,
+		TicketTypes:  v.TicketTypes,
+	}, true
+}
+
+func presentEventCancellationV2(data interface{}) (interface{}, bool) {
+	v, ok := data.(*services.EventCancellation)
+	if !ok {
+		return nil, false
+	}
+	return EventCancellationV2{
+		Event:          NewEventV2(v.Event),
+		Cancelled:      v.Cancelled,
+		RefundsPending: v.RefundsPending,
+	}, true
+}
+
+func presentParticipantV2(data interface{}) (interface{}, bool) {
+	switch v := data.(type) {
+	case *models.Participant:
+		return NewParticipantV2(v), true
+	case []models.Participant:
+		out := make([]ParticipantV2, 0, len(v))
+		for i := range v {
+			out = append(out, NewParticipantV2(&v[i]))
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+func presentParticipantDetailV2(data interface{}) (interface{}, bool) {
+	v, ok := data.(*services.ParticipantDetail)
+	if !ok {
+		return nil, false
+	}
+	return ParticipantDetailV2{Participant: NewParticipantV2(v.Participant), Notes: v.Notes}, true
+}
+
+func presentRegistrationV2(data interface{}) (interface{}, bool) {
+	v, ok := data.(*services.RegisterParticipantResponse)
+	if !ok {
+		return nil, false
+	}
+	return RegistrationV2{
+		Participant:    NewParticipantV2(v.Participant),
+		TicketType:     v.TicketType,
+		QRURL:          v.QRPath,
+		PendingWaivers: v.PendingWaivers,
+	}, true
+}
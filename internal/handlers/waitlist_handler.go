@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+
+	"event-management-backend/internal/jobs"
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type UpdateWaitlistEntryRequest struct {
+	// Higher goes first on events promoting by priority
+	Priority *int `json:"priority" validate:"required"`
+}
+
+// GetWaitlist returns the waitlist of an event
+// @Summary Get event waitlist
+// @Description Lists the people waiting for tickets in the order they are promoted: by priority, then oldest first, on events with waitlist `priority`; oldest first otherwise. `tickets_left` is null when the event has no ticket quota.
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response{data=services.Waitlist}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/waitlist [get]
+func (h *Handler) GetWaitlist(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	waitlist, err := h.participantSvc.GetWaitlist(eventID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownEvent) {
+			return utils.Error(c, "Event not found", fiber.StatusNotFound)
+		}
+		return utils.Error(c, "Failed to get waitlist", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, waitlist, "Waitlist retrieved successfully")
+}
+
+// PromoteWaitlistEntry registers someone from the waitlist
+// @Summary Promote waitlist entry
+// @Description Registers a waiting entry as a participant, ahead of its turn if need be, and sends them their ticket. The ticket quota and day capacities still apply.
+// @Tags Events
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Waitlist entry ID"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "No ticket left, or the entry was already promoted"
+// @Router /waitlist/{id}/promote [post]
+func (h *Handler) PromoteWaitlistEntry(c *fiber.Ctx) error {
+	entryID := c.Params("id")
+	if _, err := uuid.Parse(entryID); err != nil {
+		return utils.Error(c, "Invalid waitlist entry ID", fiber.StatusBadRequest)
+	}
+
+	actorID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return utils.Error(c, "Authentication required", fiber.StatusUnauthorized)
+	}
+
+	result, err := h.participantSvc.PromoteWaitlistEntry(actorID, entryID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownWaitlistEntry):
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		case errors.Is(err, services.ErrWaitlistEntryPromoted), errors.Is(err, repositories.ErrVersionConflict),
+			errors.Is(err, repositories.ErrTicketQuotaExceeded), errors.Is(err, repositories.ErrEventDayFull):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	if _, err := h.jobQueue.Enqueue(jobs.TypeTicketResend, jobs.ParticipantPayload{ParticipantID: result.Participant.ID.String()}); err != nil {
+		middleware.GetLogger(c).WithError(err).Warn("failed to enqueue ticket of promoted participant")
+	}
+
+	return utils.Success(c, present(c, result), "Waitlist entry promoted successfully", fiber.StatusCreated)
+}
+
+// UpdateWaitlistEntry changes the priority of a waitlist entry
+// @Summary Update waitlist entry
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Waitlist entry ID"
+// @Param request body UpdateWaitlistEntryRequest true "Priority"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "The entry was already promoted"
+// @Router /waitlist/{id} [patch]
+func (h *Handler) UpdateWaitlistEntry(c *fiber.Ctx) error {
+	entryID := c.Params("id")
+	if _, err := uuid.Parse(entryID); err != nil {
+		return utils.Error(c, "Invalid waitlist entry ID", fiber.StatusBadRequest)
+	}
+
+	var req UpdateWaitlistEntryRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	entry, err := h.participantSvc.SetWaitlistPriority(entryID, *req.Priority)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownWaitlistEntry):
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		case errors.Is(err, services.ErrWaitlistEntryPromoted):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		}
+		return utils.Error(c, "Failed to update waitlist entry", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, entry, "Waitlist entry updated successfully")
+}
+
+// promoteWaitlist queues the automatic promotion of an event's waitlist
+// after tickets may have been freed
+func (h *Handler) promoteWaitlist(c *fiber.Ctx, eventID string) {
+	if _, err := h.jobQueue.Enqueue(jobs.TypeWaitlistPromote, jobs.EventPayload{EventID: eventID}); err != nil {
+		middleware.GetLogger(c).WithError(err).WithField("event_id", eventID).Warn("failed to enqueue waitlist promotion")
+	}
+}
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CreateWaiverRequest struct {
+	Title string `json:"title" validate:"required,max=200"`
+	Body  string `json:"body" validate:"required"`
+	// Participants must draw their signature instead of typing their name
+	RequireDrawnSignature bool `json:"require_drawn_signature"`
+}
+
+type SignWaiverRequest struct {
+	WaiverID  string `json:"waiver_id" form:"waiver_id" validate:"required,uuid"`
+	TypedName string `json:"typed_name" form:"typed_name" validate:"max=200"`
+}
+
+// CreateWaiver adds a waiver participants must sign before their QR code is
+// released
+// @Summary Create waiver
+// @Tags Waivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param request body CreateWaiverRequest true "Waiver document"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/waivers [post]
+func (h *Handler) CreateWaiver(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	var req CreateWaiverRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	waiver, err := h.waiverSvc.CreateWaiver(eventID, services.CreateWaiverRequest{
+		Title:                 req.Title,
+		Body:                  req.Body,
+		RequireDrawnSignature: req.RequireDrawnSignature,
+	})
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, waiver, "Waiver created successfully", fiber.StatusCreated)
+}
+
+// ListWaivers returns the waivers of an event
+// @Summary List waivers
+// @Tags Waivers
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/waivers [get]
+func (h *Handler) ListWaivers(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	waivers, err := h.waiverSvc.ListWaivers(eventID)
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve waivers", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, waivers, "Waivers retrieved successfully")
+}
+
+// DeactivateWaiver stops requiring a waiver
+// @Summary Deactivate waiver
+// @Description Participants no longer need to sign the waiver. Signatures already collected stay in the report.
+// @Tags Waivers
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param waiver_id path string true "Waiver ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/waivers/{waiver_id} [delete]
+func (h *Handler) DeactivateWaiver(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	waiverID := c.Params("waiver_id")
+	if _, err := uuid.Parse(waiverID); err != nil {
+		return utils.Error(c, "Invalid waiver ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.waiverSvc.DeactivateWaiver(eventID, waiverID); err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusNotFound)
+	}
+
+	return utils.Success(c, nil, "Waiver deactivated")
+}
+
+// GetSignedWaivers returns the signed-waiver report of an event
+// @Summary Signed-waiver report
+// @Description Lists every signature with typed name, IP address and time. Use format=csv to export.
+// @Tags Waivers
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/waivers/signatures [get]
+func (h *Handler) GetSignedWaivers(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	report, err := h.waiverSvc.SignedWaivers(eventID)
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve signed waivers", fiber.StatusInternalServerError)
+	}
+
+	if c.Query("format") != "csv" {
+		return utils.Success(c, report, "Signed waivers retrieved successfully")
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"signature_id", "waiver", "participant_id", "name", "email", "typed_name", "drawn_signature", "ip_address", "user_agent", "signed_at"})
+	for _, row := range report {
+		w.Write([]string{
+			row.SignatureID,
+			row.WaiverTitle,
+			row.ParticipantID,
+			row.ParticipantName,
+			row.Email,
+			row.TypedName,
+			strconv.FormatBool(row.DrawnSignature),
+			row.IPAddress,
+			row.UserAgent,
+			row.SignedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return utils.Error(c, "Failed to export signed waivers", fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="event-%s-waivers.csv"`, eventID))
+	return c.Send(buf.Bytes())
+}
+
+// GetWaiverSignatureImage returns a drawn signature
+// @Summary Get drawn signature
+// @Tags Waivers
+// @Produce image/png
+// @Produce image/jpeg
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Param signature_id path string true "Signature ID"
+// @Success 200 {file} file
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/waivers/signatures/{signature_id}/image [get]
+func (h *Handler) GetWaiverSignatureImage(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+	signatureID := c.Params("signature_id")
+	if _, err := uuid.Parse(signatureID); err != nil {
+		return utils.Error(c, "Invalid signature ID", fiber.StatusBadRequest)
+	}
+
+	path, err := h.waiverSvc.SignatureImagePath(eventID, signatureID)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusNotFound)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.SendFile(path)
+}
+
+// GetParticipantWaivers returns the waivers a participant still has to sign
+// @Summary Get participant waivers
+// @Description Returns pending and signed waivers. The QR code path is included once every waiver has been signed.
+// @Tags Waivers
+// @Produce json
+// @Param participant_id path string true "Participant ID from the registration response"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /waivers/{participant_id} [get]
+func (h *Handler) GetParticipantWaivers(c *fiber.Ctx) error {
+	participantID := c.Params("participant_id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	waivers, err := h.waiverSvc.ParticipantWaivers(participantID)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusNotFound)
+	}
+
+	return utils.Success(c, waivers, "Waivers retrieved successfully")
+}
+
+// SignWaiver records a participant accepting a waiver
+// @Summary Sign waiver
+// @Description Accepts a waiver with a typed name, or a drawn signature image (multipart field "signature"). The signer's IP address and time are recorded. Signing the last pending waiver releases the QR code.
+// @Tags Waivers
+// @Accept json
+// @Accept multipart/form-data
+// @Produce json
+// @Param participant_id path string true "Participant ID from the registration response"
+// @Param request body SignWaiverRequest true "Signature"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /waivers/{participant_id}/sign [post]
+func (h *Handler) SignWaiver(c *fiber.Ctx) error {
+	participantID := c.Params("participant_id")
+	if _, err := uuid.Parse(participantID); err != nil {
+		return utils.Error(c, "Invalid participant ID", fiber.StatusBadRequest)
+	}
+
+	var req SignWaiverRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+
+	signatureFile := ""
+	file, err := c.FormFile("signature")
+	if err == nil && file != nil {
+		if err := utils.ValidateImageFile(file); err != nil {
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
+		if err := utils.ScanUploadedFile(c.UserContext(), h.scanner, file); err != nil {
+			if errors.Is(err, utils.ErrInfected) {
+				return utils.Error(c, "File rejected by virus scan", fiber.StatusBadRequest)
+			}
+			middleware.GetLogger(c).WithError(err).Error("virus scan failed")
+			return utils.Error(c, "File could not be scanned, try again later", fiber.StatusServiceUnavailable)
+		}
+
+		filename := utils.GenerateUniqueFilename(file.Filename)
+		if err := utils.SaveUploadedFile(file, h.cfg.SignatureDir, filename); err != nil {
+			return utils.Error(c, "Failed to save signature", fiber.StatusInternalServerError)
+		}
+		signatureFile = filename
+	}
+
+	waivers, err := h.waiverSvc.Sign(services.SignWaiverRequest{
+		ParticipantID: participantID,
+		WaiverID:      req.WaiverID,
+		TypedName:     req.TypedName,
+		SignatureFile: signatureFile,
+		IPAddress:     middleware.ClientIP(c),
+		UserAgent:     c.Get(fiber.HeaderUserAgent),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWaiverAlreadySigned):
+			return utils.Error(c, err.Error(), fiber.StatusConflict)
+		case errors.Is(err, services.ErrWaiverNotFound):
+			return utils.Error(c, err.Error(), fiber.StatusNotFound)
+		default:
+			return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+		}
+	}
+
+	return utils.Success(c, waivers, "Waiver signed successfully", fiber.StatusCreated)
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"event-management-backend/internal/middleware"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/services"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type WidgetRegisterRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
+	Phone    string `json:"phone" validate:"required"`
+	Division string `json:"division"`
+	Address  string `json:"address"`
+	// regular when omitted
+	MealPreference string `json:"meal_preference" validate:"omitempty,oneof=regular vegetarian vegan halal gluten_free"`
+	// Token of the CAPTCHA widget, when the event requires one
+	CaptchaToken string `json:"captcha_token"`
+	// Required when the widget lists ticket types
+	TicketTypeID string `json:"ticket_type_id" validate:"omitempty,uuid"`
+	// Answers to the form fields the widget lists, by key
+	Answers map[string]interface{} `json:"answers"`
+}
+
+// IssueWidgetKey creates the public key of an event's registration widget
+// @Summary Issue widget key
+// @Description Creates a new public widget key, replacing the previous one. Widget requests must come from one of the event's widget origins.
+// @Tags Widget
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/widget-key [post]
+func (h *Handler) IssueWidgetKey(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	key, slug, err := h.widgetSvc.IssueKey(eventID)
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	result := fiber.Map{
+		"key":      key,
+		"endpoint": fmt.Sprintf("/api/%s/public/widget/%s", middleware.GetAPIVersion(c), slug),
+	}
+
+	return utils.Success(c, result, "Widget key issued", fiber.StatusCreated)
+}
+
+// RevokeWidgetKey disables the registration widget of an event
+// @Summary Revoke widget key
+// @Tags Widget
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /events/{id}/widget-key [delete]
+func (h *Handler) RevokeWidgetKey(c *fiber.Ctx) error {
+	eventID := c.Params("id")
+	if _, err := uuid.Parse(eventID); err != nil {
+		return utils.Error(c, "Invalid event ID", fiber.StatusBadRequest)
+	}
+
+	if err := h.widgetSvc.RevokeKey(eventID); err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+
+	return utils.Success(c, nil, "Widget key revoked")
+}
+
+// WidgetMiddleware authorizes widget requests by the X-Widget-Key header (or
+// key query parameter) and the Origin of the embedding site
+func (h *Handler) WidgetMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get("X-Widget-Key")
+		if key == "" {
+			key = c.Query("key")
+		}
+
+		event, err := h.widgetSvc.Authorize(c.Params("slug"), key, c.Get(fiber.HeaderOrigin))
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrWidgetNotFound):
+				return utils.Error(c, err.Error(), fiber.StatusNotFound)
+			case errors.Is(err, services.ErrInvalidWidgetKey):
+				return utils.Error(c, err.Error(), fiber.StatusUnauthorized)
+			default:
+				return utils.Error(c, err.Error(), fiber.StatusForbidden)
+			}
+		}
+
+		c.Locals("widget_event", event)
+		return c.Next()
+	}
+}
+
+// GetWidget returns the event details and availability shown by the widget
+// @Summary Get registration widget
+// @Tags Widget
+// @Produce json
+// @Param slug path string true "Event slug"
+// @Param X-Widget-Key header string true "Public widget key"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /public/widget/{slug} [get]
+func (h *Handler) GetWidget(c *fiber.Ctx) error {
+	event := c.Locals("widget_event").(*models.Event)
+
+	info, err := h.widgetSvc.Info(event)
+	if err != nil {
+		return utils.Error(c, "Failed to retrieve availability", fiber.StatusInternalServerError)
+	}
+
+	return utils.Success(c, info, "Widget retrieved successfully")
+}
+
+// WidgetRegister registers a participant from the embedded widget
+// @Summary Register through widget
+// @Description Registers a participant. For paid events the response carries the payment page to hand off to, when one is configured. Disposable email addresses and registration limits are checked as on /register.
+// @Tags Widget
+// @Accept json
+// @Produce json
+// @Param slug path string true "Event slug"
+// @Param X-Widget-Key header string true "Public widget key"
+// @Param request body WidgetRegisterRequest true "Participant data"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Failure 503 {object} utils.Response
+// @Router /public/widget/{slug}/register [post]
+func (h *Handler) WidgetRegister(c *fiber.Ctx) error {
+	event := c.Locals("widget_event").(*models.Event)
+
+	var req WidgetRegisterRequest
+	if err := middleware.ValidateBody(&req)(c); err != nil {
+		return err
+	}
+	if err := h.checkCaptcha(c, event, req.CaptchaToken); err != nil {
+		return err
+	}
+	if err := h.checkRegistrationAbuse(c, req.Email); err != nil {
+		return err
+	}
+
+	registration, err := h.widgetSvc.Register(event, services.RegisterParticipantRequest{
+		Name:           req.Name,
+		Email:          req.Email,
+		Phone:          req.Phone,
+		Division:       req.Division,
+		Address:        req.Address,
+		MealPreference: req.MealPreference,
+		TicketTypeID:   req.TicketTypeID,
+		Answers:        req.Answers,
+	})
+	if err != nil {
+		return utils.Error(c, err.Error(), fiber.StatusBadRequest)
+	}
+	if registration.ApprovalStatus == services.ApprovalPendingConfirmation {
+		h.sendConfirmationEmail(c, registration.ParticipantID)
+	}
+
+	return utils.Success(c, registration, "Participant registered successfully", fiber.StatusCreated)
+}
@@ -0,0 +1,307 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type periodicJob struct {
+	interval time.Duration
+	jobType  string
+	payload  interface{}
+}
+
+// HandlerFunc processes a single job payload. Returning an error schedules a
+// retry until the job runs out of attempts.
+type HandlerFunc func(ctx context.Context, payload json.RawMessage) error
+
+// Options tune how a job is enqueued
+type Options struct {
+	RunAt       time.Time
+	MaxAttempts int
+}
+
+// Queue is a persistent job queue backed by the jobs table, processed by a
+// pool of polling workers.
+type Queue struct {
+	repo         repositories.JobRepository
+	workers      int
+	pollInterval time.Duration
+	staleAfter   time.Duration
+
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+	periodic []periodicJob
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewQueue(repo repositories.JobRepository, workers int, pollInterval time.Duration) *Queue {
+	if workers <= 0 {
+		workers = 1
+	}
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+
+	return &Queue{
+		repo:         repo,
+		workers:      workers,
+		pollInterval: pollInterval,
+		staleAfter:   15 * time.Minute,
+		handlers:     make(map[string]HandlerFunc),
+	}
+}
+
+// Register binds a handler to a job type. Must be called before Start.
+func (q *Queue) Register(jobType string, handler HandlerFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// Enqueue stores a job to be processed as soon as a worker is free
+func (q *Queue) Enqueue(jobType string, payload interface{}) (*models.Job, error) {
+	return q.EnqueueWithOptions(jobType, payload, Options{})
+}
+
+// Schedule stores a job that will not be picked up before runAt
+func (q *Queue) Schedule(jobType string, payload interface{}, runAt time.Time) (*models.Job, error) {
+	return q.EnqueueWithOptions(jobType, payload, Options{RunAt: runAt})
+}
+
+func (q *Queue) EnqueueWithOptions(jobType string, payload interface{}, opts Options) (*models.Job, error) {
+	if jobType == "" {
+		return nil, errors.New("job type is required")
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	runAt := opts.RunAt
+	if runAt.IsZero() {
+		runAt = time.Now()
+	}
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+
+	job := &models.Job{
+		ID:          uuid.New(),
+		Type:        jobType,
+		Payload:     string(data),
+		Status:      "pending",
+		MaxAttempts: maxAttempts,
+		RunAt:       runAt,
+	}
+
+	if err := q.repo.CreateJob(job); err != nil {
+		return nil, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	return job, nil
+}
+
+// Every enqueues a job of the given type at a fixed interval while the queue
+// is running. Useful for periodic maintenance work. Must be called before Start.
+func (q *Queue) Every(interval time.Duration, jobType string, payload interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.periodic = append(q.periodic, periodicJob{interval: interval, jobType: jobType, payload: payload})
+}
+
+func (q *Queue) schedulePeriodic(ctx context.Context, p periodicJob) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := q.Enqueue(p.jobType, p.payload); err != nil {
+				logger.Log.WithError(err).WithField("job_type", p.jobType).Error("failed to enqueue periodic job")
+			}
+		}
+	}
+}
+
+// Start launches the worker pool. Workers stop when ctx is cancelled or Stop
+// is called.
+func (q *Queue) Start(ctx context.Context) {
+	ctx, q.cancel = context.WithCancel(ctx)
+
+	if n, err := q.repo.ResetStaleJobs(time.Now().Add(-q.staleAfter)); err != nil {
+		logger.Log.WithError(err).Warn("failed to reset stale jobs")
+	} else if n > 0 {
+		logger.Log.WithField("count", n).Info("requeued stale jobs")
+	}
+
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work(ctx, i)
+	}
+
+	q.mu.RLock()
+	for _, p := range q.periodic {
+		q.wg.Add(1)
+		go q.schedulePeriodic(ctx, p)
+	}
+	q.mu.RUnlock()
+
+	logger.Log.WithField("workers", q.workers).Info("job queue started")
+}
+
+// Stop cancels the workers and waits for in-flight jobs to finish, giving up
+// when ctx expires. Jobs still running at that point are requeued as stale
+// on the next start.
+func (q *Queue) Stop(ctx context.Context) error {
+	if q.cancel != nil {
+		q.cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Log.Info("job queue stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("job queue did not drain: %w", ctx.Err())
+	}
+}
+
+// ListJobs returns a page of jobs for the admin status endpoint
+func (q *Queue) ListJobs(status string, page, pageSize int) ([]models.Job, int64, int, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+	jobs, total, err := q.repo.ListJobs(offset, pageSize, status)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	totalPages := (int(total) + pageSize - 1) / pageSize
+	return jobs, total, totalPages, nil
+}
+
+func (q *Queue) work(ctx context.Context, id int) {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain everything that is due before sleeping again
+		for ctx.Err() == nil {
+			job, err := q.repo.ClaimNextJob(time.Now())
+			if err != nil {
+				logger.Log.WithError(err).WithField("worker", id).Error("failed to poll jobs")
+				break
+			}
+			if job == nil {
+				break
+			}
+			q.process(ctx, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *Queue) process(ctx context.Context, job *models.Job) {
+	log := logger.Log.WithFields(logrus.Fields{
+		"job_id":   job.ID.String(),
+		"job_type": job.Type,
+		"attempt":  job.Attempts,
+	})
+
+	q.mu.RLock()
+	handler, ok := q.handlers[job.Type]
+	q.mu.RUnlock()
+
+	if !ok {
+		log.Error("no handler registered for job type")
+		if err := q.repo.MarkJobFailed(job.ID.String(), "no handler registered for job type", nil); err != nil {
+			log.WithError(err).Error("failed to update job status")
+		}
+		return
+	}
+
+	start := time.Now()
+	err := q.runHandler(ctx, handler, job)
+	if err == nil {
+		if err := q.repo.MarkJobCompleted(job.ID.String()); err != nil {
+			log.WithError(err).Error("failed to update job status")
+		}
+		log.WithField("duration_ms", time.Since(start).Milliseconds()).Info("job completed")
+		return
+	}
+
+	var retryAt *time.Time
+	if job.Attempts < job.MaxAttempts {
+		next := time.Now().Add(backoff(job.Attempts))
+		retryAt = &next
+	}
+
+	if markErr := q.repo.MarkJobFailed(job.ID.String(), err.Error(), retryAt); markErr != nil {
+		log.WithError(markErr).Error("failed to update job status")
+	}
+
+	if retryAt != nil {
+		log.WithError(err).WithField("retry_at", retryAt).Warn("job failed, retry scheduled")
+	} else {
+		log.WithError(err).Error("job failed permanently")
+	}
+}
+
+// runHandler shields the worker from panics inside job handlers
+func (q *Queue) runHandler(ctx context.Context, handler HandlerFunc, job *models.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, json.RawMessage(job.Payload))
+}
+
+// backoff returns an exponential retry delay capped at one hour
+func backoff(attempt int) time.Duration {
+	delay := time.Duration(math.Pow(2, float64(attempt))) * 10 * time.Second
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+	return delay
+}
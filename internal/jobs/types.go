@@ -0,0 +1,40 @@
+package jobs
+
+// Job types enqueued by the API
+const (
+	TypeLogoVariants        = "image.logo_variants"
+	TypePhotoVariants       = "image.photo_variants"
+	TypeBackup              = "backup.run"
+	TypeJoinLinks           = "online.join_links"
+	TypeCancellationNotices = "participants.cancellation_notices"
+	TypeParticipantImport   = "participants.import"
+	TypeTicketResend        = "participants.resend_ticket"
+	TypeWaitlistPromote     = "participants.promote_waitlist"
+	TypeConfirmationEmail   = "participants.confirmation_email"
+	TypeConfirmationExpiry  = "participants.expire_unconfirmed"
+	TypeReviewDecision      = "participants.review_decision"
+	TypeRetention           = "retention.run"
+	TypeTokenCleanup        = "tokens.cleanup"
+	TypeLoginCleanup        = "logins.cleanup"
+)
+
+// EventPayload is the payload of jobs that operate on a single event
+type EventPayload struct {
+	EventID string `json:"event_id"`
+}
+
+// SpeakerPayload is the payload of jobs that operate on a single speaker
+type SpeakerPayload struct {
+	SpeakerID string `json:"speaker_id"`
+}
+
+// ImportPayload is the payload of participant import jobs
+type ImportPayload struct {
+	ImportJobID string `json:"import_job_id"`
+}
+
+// ParticipantPayload is the payload of jobs that operate on a single
+// participant
+type ParticipantPayload struct {
+	ParticipantID string `json:"participant_id"`
+}
@@ -0,0 +1,301 @@
+// Package jwtkeys signs and verifies access tokens. Tokens name their key in
+// the kid header, so keys can be rotated without logging everyone out: the
+// new key signs, while the previous keys keep verifying until the tokens
+// they signed have expired.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"event-management-backend/internal/config"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Supported signing algorithms
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+var ErrUnknownKey = errors.New("token signed with an unknown key")
+
+// verificationKey is a key accepted for tokens of one algorithm
+type verificationKey struct {
+	method jwt.SigningMethod
+	key    interface{}
+	// Nil for shared secrets, which are never published
+	public crypto.PublicKey
+}
+
+// Keyset holds the key that signs new tokens and every key that verifies
+// them, by key ID
+type Keyset struct {
+	method     jwt.SigningMethod
+	signingKID string
+	signingKey interface{}
+	keys       map[string]verificationKey
+	// Verifies tokens issued before tokens carried a kid
+	legacy *verificationKey
+}
+
+// Load builds the keyset from the configuration. With RS256 or EdDSA, a
+// configured JWT_SECRET keeps verifying HS256 tokens so switching algorithms
+// does not log everyone out.
+func Load(cfg *config.Config) (*Keyset, error) {
+	ks := &Keyset{keys: make(map[string]verificationKey)}
+
+	for _, secret := range []string{cfg.JWTSecret, cfg.JWTPreviousSecret} {
+		if secret != "" {
+			ks.keys[secretKID(secret)] = verificationKey{method: jwt.SigningMethodHS256, key: []byte(secret)}
+		}
+	}
+	if cfg.JWTSecret != "" {
+		ks.legacy = &verificationKey{method: jwt.SigningMethodHS256, key: []byte(cfg.JWTSecret)}
+	}
+
+	switch cfg.JWTAlgorithm {
+	case HS256:
+		if cfg.JWTSecret == "" {
+			return nil, errors.New("HS256 requires JWT_SECRET")
+		}
+		ks.method = jwt.SigningMethodHS256
+		ks.signingKID = secretKID(cfg.JWTSecret)
+		ks.signingKey = []byte(cfg.JWTSecret)
+
+	case RS256, EdDSA:
+		private, err := readPrivateKey(cfg.JWTPrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := private.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("JWT private key cannot sign")
+		}
+		key, err := newPublicKey(signer.Public())
+		if err != nil {
+			return nil, err
+		}
+		if key.method.Alg() != cfg.JWTAlgorithm {
+			return nil, fmt.Errorf("JWT private key is not a %s key", cfg.JWTAlgorithm)
+		}
+		kid, err := thumbprint(key.public)
+		if err != nil {
+			return nil, err
+		}
+		ks.method = key.method
+		ks.signingKID = kid
+		ks.signingKey = private
+		ks.keys[kid] = key
+
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.JWTAlgorithm)
+	}
+
+	for _, path := range strings.Split(cfg.JWTPreviousPublicKeys, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		public, err := readPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := newPublicKey(public)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		kid, err := thumbprint(public)
+		if err != nil {
+			return nil, err
+		}
+		ks.keys[kid] = key
+	}
+
+	return ks, nil
+}
+
+// Sign returns a signed token with the current key
+func (ks *Keyset) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.method, claims)
+	token.Header["kid"] = ks.signingKID
+	return token.SignedString(ks.signingKey)
+}
+
+// Keyfunc returns the key verifying a token. The key must be known and of
+// the token's algorithm, so a public key is never used as an HMAC secret.
+func (ks *Keyset) Keyfunc(token *jwt.Token) (interface{}, error) {
+	var key *verificationKey
+	if kid, ok := token.Header["kid"].(string); ok {
+		if k, found := ks.keys[kid]; found {
+			key = &k
+		}
+	} else {
+		key = ks.legacy
+	}
+
+	if key == nil || token.Method.Alg() != key.method.Alg() {
+		return nil, ErrUnknownKey
+	}
+	return key.key, nil
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys other services verify tokens with. It is
+// empty while tokens are signed with a shared secret.
+func (ks *Keyset) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for kid, key := range ks.keys {
+		if key.public == nil {
+			continue
+		}
+		jwk := toJWK(key.public)
+		jwk.KeyID = kid
+		jwk.Use = "sig"
+		jwk.Algorithm = key.method.Alg()
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
+
+func newPublicKey(public crypto.PublicKey) (verificationKey, error) {
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < 2048 {
+			return verificationKey{}, errors.New("RSA keys must be at least 2048 bits")
+		}
+		return verificationKey{method: jwt.SigningMethodRS256, key: key, public: key}, nil
+	case ed25519.PublicKey:
+		return verificationKey{method: jwt.SigningMethodEdDSA, key: key, public: key}, nil
+	default:
+		return verificationKey{}, fmt.Errorf("unsupported key type %T, expected RSA or Ed25519", public)
+	}
+}
+
+func toJWK(public crypto.PublicKey) JWK {
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			KeyType: "RSA",
+			N:       base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		return JWK{KeyType: "OKP", Curve: "Ed25519", X: base64.RawURLEncoding.EncodeToString(key)}
+	}
+	return JWK{}
+}
+
+// thumbprint is the RFC 7638 thumbprint of a public key, used as its kid
+func thumbprint(public crypto.PublicKey) (string, error) {
+	jwk := toJWK(public)
+	// Required members only, in lexicographic order
+	var members interface{}
+	switch jwk.KeyType {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.KeyType, jwk.N}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Curve, jwk.KeyType, jwk.X}
+	default:
+		return "", fmt.Errorf("unsupported key type %T", public)
+	}
+
+	raw, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// secretKID names a shared secret without revealing it
+func secretKID(secret string) string {
+	sum := sha256.Sum256([]byte("jwt-secret:" + secret))
+	return "hs-" + base64.RawURLEncoding.EncodeToString(sum[:9])
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	if path == "" {
+		return nil, errors.New("no key file configured")
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data found", path)
+	}
+	return block, nil
+}
+
+// readPrivateKey reads a PKCS#8 or PKCS#1 PEM private key
+func readPrivateKey(path string) (crypto.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%s: unsupported private key format", path)
+}
+
+// readPublicKey reads a PKIX or PKCS#1 PEM public key
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%s: unsupported public key format", path)
+}
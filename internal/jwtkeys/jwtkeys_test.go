@@ -0,0 +1,134 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"event-management-backend/internal/config"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// writeKey writes a PEM private key and its public key, returning the paths
+func writeKey(t *testing.T, private interface{ Public() crypto.PublicKey }) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privatePath := filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	der, err = x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPath := filepath.Join(dir, "key.pub")
+	if err := os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return privatePath, publicPath
+}
+
+func verify(ks *Keyset, token string) error {
+	_, err := jwt.Parse(token, ks.Keyfunc)
+	return err
+}
+
+func TestRotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPrivate, rsaPublic := writeKey(t, rsaKey)
+	edPrivate, _ := writeKey(t, edKey)
+
+	load := func(cfg config.Config) *Keyset {
+		t.Helper()
+		ks, err := Load(&cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ks
+	}
+	sign := func(ks *Keyset) string {
+		t.Helper()
+		token, err := ks.Sign(jwt.MapClaims{"user_id": "u1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	hs := load(config.Config{JWTAlgorithm: HS256, JWTSecret: "old-secret"})
+	hsToken := sign(hs)
+	legacyToken, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{}).SignedString([]byte("old-secret"))
+	if len(hs.JWKS().Keys) != 0 {
+		t.Fatal("shared secrets must not be published")
+	}
+
+	// Rotating the secret keeps the old one verifying
+	rotated := load(config.Config{JWTAlgorithm: HS256, JWTSecret: "new-secret", JWTPreviousSecret: "old-secret"})
+	if err := verify(rotated, hsToken); err != nil {
+		t.Fatalf("token of the previous secret: %v", err)
+	}
+	if err := verify(load(config.Config{JWTAlgorithm: HS256, JWTSecret: "new-secret"}), hsToken); err == nil {
+		t.Fatal("token of a removed secret accepted")
+	}
+
+	// Switching to RS256 keeps JWT_SECRET verifying its tokens
+	rs := load(config.Config{JWTAlgorithm: RS256, JWTPrivateKeyFile: rsaPrivate, JWTSecret: "old-secret"})
+	rsToken := sign(rs)
+	for name, token := range map[string]string{"RS256": rsToken, "HS256": hsToken, "without kid": legacyToken} {
+		if err := verify(rs, token); err != nil {
+			t.Fatalf("%s token: %v", name, err)
+		}
+	}
+	if keys := rs.JWKS().Keys; len(keys) != 1 || keys[0].KeyType != "RSA" || keys[0].Algorithm != RS256 {
+		t.Fatalf("JWKS = %+v, want the RSA key", keys)
+	}
+
+	// Rotating to EdDSA with the RSA key as a previous key
+	ed := load(config.Config{JWTAlgorithm: EdDSA, JWTPrivateKeyFile: edPrivate, JWTPreviousPublicKeys: rsaPublic})
+	if err := verify(ed, rsToken); err != nil {
+		t.Fatalf("token of the previous key: %v", err)
+	}
+	if err := verify(ed, sign(ed)); err != nil {
+		t.Fatalf("EdDSA token: %v", err)
+	}
+	if err := verify(ed, hsToken); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("HS256 token without JWT_SECRET: error = %v, want %v", err, ErrUnknownKey)
+	}
+	if len(ed.JWKS().Keys) != 2 {
+		t.Fatalf("JWKS has %d keys, want the current and the previous one", len(ed.JWKS().Keys))
+	}
+
+	// A public key must never verify an HMAC token
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{})
+	forged.Header["kid"] = rs.signingKID
+	pub, _ := os.ReadFile(rsaPublic)
+	forgedToken, _ := forged.SignedString(pub)
+	if err := verify(rs, forgedToken); err == nil {
+		t.Fatal("HS256 token signed with the public key accepted")
+	}
+
+	if _, err := Load(&config.Config{JWTAlgorithm: EdDSA, JWTPrivateKeyFile: rsaPrivate}); err == nil {
+		t.Fatal("RSA key accepted for EdDSA")
+	}
+}
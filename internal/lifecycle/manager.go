@@ -0,0 +1,131 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"event-management-backend/pkg/logger"
+)
+
+// Hook is a component with a start and stop step. Either step may be nil.
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Manager starts components in registration order and stops them in reverse
+// order, so that e.g. the HTTP server stops taking writes before the workers
+// and the database it depends on are drained.
+type Manager struct {
+	drainTimeout time.Duration
+
+	mu      sync.Mutex
+	hooks   []Hook
+	started int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func New(drainTimeout time.Duration) *Manager {
+	if drainTimeout <= 0 {
+		drainTimeout = 30 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		drainTimeout: drainTimeout,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// Append registers a component. Must be called before Start.
+func (m *Manager) Append(hook Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook)
+}
+
+// Context is cancelled as soon as shutdown begins. Long-running components
+// should derive their contexts from it.
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// Start runs every OnStart in order. If one fails, the components already
+// started are stopped again and the error is returned.
+func (m *Manager) Start() error {
+	m.mu.Lock()
+	hooks := m.hooks
+	m.mu.Unlock()
+
+	for i, hook := range hooks {
+		if hook.OnStart != nil {
+			if err := hook.OnStart(m.ctx); err != nil {
+				m.setStarted(i)
+				_ = m.Shutdown()
+				return fmt.Errorf("failed to start %s: %w", hook.Name, err)
+			}
+		}
+		m.setStarted(i + 1)
+	}
+
+	return nil
+}
+
+func (m *Manager) setStarted(n int) {
+	m.mu.Lock()
+	m.started = n
+	m.mu.Unlock()
+}
+
+// Wait blocks until one of the given signals is received
+func (m *Manager) Wait(signals ...os.Signal) os.Signal {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, signals...)
+	defer signal.Stop(quit)
+
+	return <-quit
+}
+
+// Shutdown cancels the manager context and stops the started components in
+// reverse order. All components share one drain deadline; a component that
+// does not finish in time is reported and the remaining ones are still
+// stopped.
+func (m *Manager) Shutdown() error {
+	m.cancel()
+
+	m.mu.Lock()
+	hooks := m.hooks[:m.started]
+	m.started = 0
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.drainTimeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := hooks[i]
+		if hook.OnStop == nil {
+			continue
+		}
+
+		start := time.Now()
+		log := logger.Log.WithField("component", hook.Name)
+		if err := hook.OnStop(ctx); err != nil {
+			log.WithError(err).Error("component did not stop cleanly")
+			errs = append(errs, fmt.Errorf("%s: %w", hook.Name, err))
+			continue
+		}
+		log.WithField("duration_ms", time.Since(start).Milliseconds()).Info("component stopped")
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,130 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Message is a plain text email to a single recipient
+type Message struct {
+	To          string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to a Message
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Sender delivers emails
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewSender returns an SMTP sender for SMTP_HOST, or a sender that only logs
+// messages when no host is configured
+func NewSender(cfg *config.Config) Sender {
+	if cfg.SMTPHost == "" {
+		return logSender{}
+	}
+	return &SMTPSender{
+		Addr:     net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort),
+		Host:     cfg.SMTPHost,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+	}
+}
+
+type logSender struct{}
+
+func (logSender) Send(_ context.Context, msg Message) error {
+	logger.Log.WithFields(logrus.Fields{
+		"to":          msg.To,
+		"subject":     msg.Subject,
+		"attachments": len(msg.Attachments),
+	}).Info("SMTP_HOST not set, email not sent")
+	return nil
+}
+
+// SMTPSender sends through an SMTP relay, upgrading to TLS when the server
+// offers STARTTLS
+type SMTPSender struct {
+	Addr     string
+	Host     string
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTPSender) Send(_ context.Context, msg Message) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	if err := smtp.SendMail(s.Addr, auth, s.From, []string{msg.To}, s.render(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// textHeader is the header of the plain text body of multipart messages
+var textHeader = textproto.MIMEHeader{
+	"Content-Type":              {"text/plain; charset=utf-8"},
+	"Content-Transfer-Encoding": {"8bit"},
+}
+
+func (s *SMTPSender) render(msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	if len(msg.Attachments) == 0 {
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+		buf.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+		return buf.Bytes()
+	}
+
+	// multipart/mixed: the body, then every attachment in base64
+	parts := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", parts.Boundary())
+	body, _ := parts.CreatePart(textHeader)
+	body.Write([]byte(strings.ReplaceAll(msg.Body, "\n", "\r\n") + "\r\n"))
+	for _, attachment := range msg.Attachments {
+		part, _ := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+		})
+		encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+		for len(encoded) > 76 {
+			fmt.Fprintf(part, "%s\r\n", encoded[:76])
+			encoded = encoded[76:]
+		}
+		fmt.Fprintf(part, "%s\r\n", encoded)
+	}
+	parts.Close()
+	return buf.Bytes()
+}
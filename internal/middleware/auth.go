@@ -1,7 +1,9 @@
 package middleware
 
 import (
-	"event-management-backend/internal/config"
+	"time"
+
+	"event-management-backend/internal/jwtkeys"
 	"event-management-backend/internal/utils"
 
 	"github.com/gofiber/fiber/v2"
@@ -9,9 +11,13 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
-func JWTMiddleware(cfg *config.Config) fiber.Handler {
+// JWTMiddleware authenticates requests by their bearer token, signed with
+// any key of the keyset. check, when set, runs once the claims are in the
+// context and decides whether the request continues, e.g. to reject revoked
+// tokens.
+func JWTMiddleware(keys *jwtkeys.Keyset, check fiber.Handler) fiber.Handler {
 	return jwtware.New(jwtware.Config{
-		SigningKey:   []byte(cfg.JWTSecret),
+		KeyFunc:      keys.Keyfunc,
 		ContextKey:   "user",
 		ErrorHandler: jwtError,
 		SuccessHandler: func(c *fiber.Ctx) error {
@@ -19,11 +25,56 @@ func JWTMiddleware(cfg *config.Config) fiber.Handler {
 			claims := user.Claims.(jwt.MapClaims)
 			c.Locals("user_id", claims["user_id"])
 			c.Locals("user_role", claims["role"])
+			if check != nil {
+				return check(c)
+			}
 			return c.Next()
 		},
 	})
 }
 
+// TokenClaims identify the JWT of a request
+type TokenClaims struct {
+	UserID string
+	// Empty for tokens issued before tokens carried an ID
+	JTI       string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// Events the token is limited to; nil when the token has no event_ids
+	// claim
+	EventIDs []string
+}
+
+// GetTokenClaims returns the claims of the request's JWT
+func GetTokenClaims(c *fiber.Ctx) (*TokenClaims, error) {
+	token, ok := c.Locals("user").(*jwt.Token)
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "User not authenticated")
+	}
+	claims := token.Claims.(jwt.MapClaims)
+
+	userID, _ := claims["user_id"].(string)
+	jti, _ := claims["jti"].(string)
+	iat, _ := claims["iat"].(float64)
+	exp, _ := claims["exp"].(float64)
+	var eventIDs []string
+	if ids, ok := claims["event_ids"].([]interface{}); ok {
+		eventIDs = make([]string, 0, len(ids))
+		for _, id := range ids {
+			if s, ok := id.(string); ok {
+				eventIDs = append(eventIDs, s)
+			}
+		}
+	}
+	return &TokenClaims{
+		UserID:    userID,
+		JTI:       jti,
+		IssuedAt:  time.Unix(int64(iat), 0),
+		ExpiresAt: time.Unix(int64(exp), 0),
+		EventIDs:  eventIDs,
+	}, nil
+}
+
 func jwtError(c *fiber.Ctx, err error) error {
 	return utils.Error(c, "Unauthorized", fiber.StatusUnauthorized)
 }
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+)
+
+// PublicCache marks successful GET responses as cacheable by browsers and
+// proxies for maxAge and adds a weak ETag so unchanged responses are answered
+// with 304 Not Modified.
+func PublicCache(maxAge time.Duration) fiber.Handler {
+	tag := etag.New(etag.Config{Weak: true})
+
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return c.Next()
+		}
+
+		if err := tag(c); err != nil {
+			return err
+		}
+
+		if c.Response().StatusCode() == fiber.StatusOK {
+			c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+			c.Vary(fiber.HeaderAcceptEncoding)
+		}
+		return nil
+	}
+}
+
+// NotModified sets Last-Modified to modTime and reports whether the client's
+// If-Modified-Since already covers it, in which case the handler should reply
+// with 304 Not Modified.
+func NotModified(c *fiber.Ctx, modTime time.Time) bool {
+	if modTime.IsZero() {
+		return false
+	}
+
+	// HTTP dates have second precision
+	modTime = modTime.UTC().Truncate(time.Second)
+	c.Set(fiber.HeaderLastModified, modTime.Format(http.TimeFormat))
+
+	since := c.Get(fiber.HeaderIfModifiedSince)
+	if since == "" || c.Get(fiber.HeaderIfNoneMatch) != "" {
+		return false
+	}
+
+	t, err := http.ParseTime(since)
+	if err != nil {
+		return false
+	}
+	return !modTime.After(t)
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+)
+
+// CORS builds the CORS middleware from the configuration. widgetOrigin, when
+// non-nil, is consulted for origins outside the static allow list so that
+// per-event registration widgets can be embedded on organizer sites.
+func CORS(cfg *config.Config, widgetOrigin func(origin string) (bool, error)) fiber.Handler {
+	corsCfg := cors.Config{
+		AllowOrigins:     cfg.CORSAllowOrigins,
+		AllowMethods:     cfg.CORSAllowMethods,
+		AllowHeaders:     cfg.CORSAllowHeaders,
+		ExposeHeaders:    cfg.CORSExposeHeaders,
+		AllowCredentials: cfg.CORSAllowCredentials,
+		MaxAge:           int(cfg.CORSMaxAge.Seconds()),
+	}
+
+	// The cors middleware only consults AllowOriginsFunc when the static
+	// list does not already allow every origin
+	if widgetOrigin != nil && !strings.Contains(cfg.CORSAllowOrigins, "*") {
+		corsCfg.AllowOriginsFunc = cachedOriginCheck(widgetOrigin, time.Minute)
+	}
+	if corsCfg.AllowOrigins == "" && corsCfg.AllowOriginsFunc == nil {
+		// Same-origin only: an allow list no browser origin can match
+		corsCfg.AllowOriginsFunc = func(string) bool { return false }
+	}
+
+	return cors.New(corsCfg)
+}
+
+type originEntry struct {
+	allowed   bool
+	expiresAt time.Time
+}
+
+// cachedOriginCheck memoizes lookups so preflight requests do not hit the
+// database every time
+func cachedOriginCheck(check func(origin string) (bool, error), ttl time.Duration) func(string) bool {
+	var mu sync.Mutex
+	cache := make(map[string]originEntry)
+
+	return func(origin string) bool {
+		if origin == "" {
+			return false
+		}
+		now := time.Now()
+
+		mu.Lock()
+		entry, ok := cache[origin]
+		mu.Unlock()
+		if ok && now.Before(entry.expiresAt) {
+			return entry.allowed
+		}
+
+		allowed, err := check(origin)
+		if err != nil {
+			logger.Log.WithError(err).WithField("origin", origin).Warn("failed to check widget origin")
+			return false
+		}
+
+		mu.Lock()
+		if len(cache) > 10000 {
+			cache = make(map[string]originEntry)
+		}
+		cache[origin] = originEntry{allowed: allowed, expiresAt: now.Add(ttl)}
+		mu.Unlock()
+
+		return allowed
+	}
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const idempotencyHeader = "Idempotency-Key"
+
+// Idempotency replays the stored response when a mutating request is retried
+// with the same Idempotency-Key, so client retries never create duplicates.
+// Requests without the header are passed through unchanged.
+func Idempotency(repo repositories.IdempotencyRepository, ttl time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodPost && c.Method() != fiber.MethodPatch && c.Method() != fiber.MethodPut {
+			return c.Next()
+		}
+
+		clientKey := c.Get(idempotencyHeader)
+		if clientKey == "" {
+			return c.Next()
+		}
+		if len(clientKey) > 255 {
+			return utils.Error(c, "Idempotency-Key is too long", fiber.StatusBadRequest)
+		}
+
+		// Keys are scoped to the caller: a user, or an integration's API key
+		caller, _ := c.Locals("user_id").(string)
+		if keyID, ok := c.Locals("api_key_id").(string); ok && caller == "" {
+			caller = "api_key:" + keyID
+		}
+		key := hashParts(caller, c.Method(), c.Path(), clientKey)
+		requestHash := hashParts(string(c.Body()))
+
+		existing, err := repo.GetByKey(key)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.Error(c, "Failed to check idempotency key", fiber.StatusInternalServerError)
+		}
+
+		if existing != nil && existing.ExpiresAt.Before(time.Now()) {
+			if err := repo.Delete(key); err != nil {
+				return utils.Error(c, "Failed to check idempotency key", fiber.StatusInternalServerError)
+			}
+			existing = nil
+		}
+
+		if existing != nil {
+			return replayIdempotent(c, existing, requestHash)
+		}
+
+		record := &models.IdempotencyKey{
+			ID:          uuid.New(),
+			Key:         key,
+			RequestHash: requestHash,
+			ExpiresAt:   time.Now().Add(ttl),
+		}
+		if err := repo.Create(record); err != nil {
+			// Lost the race against a concurrent request with the same key
+			return utils.Error(c, "A request with this Idempotency-Key is already in progress", fiber.StatusConflict)
+		}
+
+		if err := c.Next(); err != nil {
+			_ = repo.Delete(key)
+			return err
+		}
+
+		// Server errors are not cached so the client can retry them
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			_ = repo.Delete(key)
+			return nil
+		}
+
+		body := append([]byte(nil), c.Response().Body()...)
+		contentType := string(c.Response().Header.ContentType())
+		if err := repo.SaveResponse(key, status, body, contentType); err != nil {
+			GetLogger(c).WithError(err).Warn("failed to store idempotent response")
+		}
+
+		return nil
+	}
+}
+
+func replayIdempotent(c *fiber.Ctx, record *models.IdempotencyKey, requestHash string) error {
+	if record.RequestHash != requestHash {
+		return utils.Error(c, "Idempotency-Key was already used with a different request body", fiber.StatusUnprocessableEntity)
+	}
+
+	if record.StatusCode == 0 {
+		return utils.Error(c, "A request with this Idempotency-Key is already in progress", fiber.StatusConflict)
+	}
+
+	c.Set("Idempotent-Replayed", "true")
+	if record.ContentType != "" {
+		c.Set(fiber.HeaderContentType, record.ContentType)
+	}
+	return c.Status(record.StatusCode).Send(record.ResponseBody)
+}
+
+func hashParts(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"time"
+
+	"event-management-backend/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// RequestLogger assigns a request ID, exposes a request-scoped log entry via
+// GetLogger and writes one access log line per request.
+func RequestLogger() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		requestID := c.Get(requestIDHeader)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		c.Set(requestIDHeader, requestID)
+		c.Locals("request_id", requestID)
+
+		err := c.Next()
+
+		// Surface handler errors through the app ErrorHandler so the logged
+		// status matches what the client receives
+		if err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		entry := GetLogger(c).WithFields(logrus.Fields{
+			"method":     c.Method(),
+			"path":       c.Path(),
+			"status":     status,
+			"latency_ms": time.Since(start).Milliseconds(),
+			"ip":         ClientIP(c),
+		})
+
+		switch {
+		case status >= 500:
+			entry.Error("request completed")
+		case status >= 400:
+			entry.Warn("request completed")
+		default:
+			entry.Info("request completed")
+		}
+
+		return nil
+	}
+}
+
+// GetLogger returns a log entry populated with the request ID, matched route
+// and authenticated user (when available) for the current request.
+func GetLogger(c *fiber.Ctx) *logrus.Entry {
+	fields := logrus.Fields{}
+
+	if requestID, ok := c.Locals("request_id").(string); ok {
+		fields["request_id"] = requestID
+	}
+	if userID, ok := c.Locals("user_id").(string); ok && userID != "" {
+		fields["user_id"] = userID
+	}
+	if route := c.Route(); route != nil && route.Path != "" {
+		fields["route"] = route.Path
+	}
+
+	return logger.With(fields)
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"strconv"
+	"strings"
+
+	"event-management-backend/internal/ratelimit"
+	"event-management-backend/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimit allows each client IP the requests of limit and answers 429 with
+// Retry-After once they are used up. Routes sharing a name share buckets.
+// Requests pass when the store fails, so an unavailable Redis does not lock
+// everyone out; a nil store disables limiting.
+func RateLimit(store ratelimit.Store, name string, limit ratelimit.Limit) fiber.Handler {
+	return RateLimitBy(store, name, limit, ClientIP)
+}
+
+// RateLimitBy is RateLimit with buckets keyed by key instead of the client
+// IP, e.g. by a route parameter
+func RateLimitBy(store ratelimit.Store, name string, limit ratelimit.Limit, key func(*fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if store == nil {
+			return c.Next()
+		}
+
+		allowed, wait, err := store.Take(c.UserContext(), "ratelimit:"+name+":"+key(c), limit)
+		if err != nil {
+			GetLogger(c).WithError(err).Warn("Rate limit check failed")
+			return c.Next()
+		}
+		if !allowed {
+			retryAfter := int(math.Max(1, math.Ceil(wait.Seconds())))
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			return utils.Error(c, "Too many requests, please try again later", fiber.StatusTooManyRequests)
+		}
+		return c.Next()
+	}
+}
+
+// ClientIP returns the address of the client. Behind a trusted proxy it is
+// the last address of the proxy header that is not a trusted proxy itself:
+// entries before it were sent by the client and could be anything. Requests
+// not coming through a trusted proxy use the connection address.
+func ClientIP(c *fiber.Ctx) string {
+	cfg := c.App().Config()
+	if cfg.ProxyHeader == "" || !c.IsProxyTrusted() {
+		return c.Context().RemoteIP().String()
+	}
+
+	entries := strings.Split(c.Get(cfg.ProxyHeader), ",")
+	for i := len(entries) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(entries[i]))
+		if ip == nil {
+			break
+		}
+		if !isTrustedProxy(cfg.TrustedProxies, ip) {
+			return ip.String()
+		}
+	}
+	return c.Context().RemoteIP().String()
+}
+
+func isTrustedProxy(proxies []string, ip net.IP) bool {
+	for _, proxy := range proxies {
+		if _, ipNet, err := net.ParseCIDR(proxy); err == nil {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		} else if ip.Equal(net.ParseIP(proxy)) {
+			return true
+		}
+	}
+	return false
+}
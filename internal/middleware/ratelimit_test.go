@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestClientIP(t *testing.T) {
+	// app.Test connections come from 0.0.0.0
+	tests := []struct {
+		name   string
+		config fiber.Config
+		header string
+		wantIP string
+	}{
+		{
+			name:   "no proxy header",
+			config: fiber.Config{EnableTrustedProxyCheck: true},
+			header: "1.1.1.1",
+			wantIP: "0.0.0.0",
+		},
+		{
+			name:   "untrusted proxy",
+			config: fiber.Config{ProxyHeader: fiber.HeaderXForwardedFor, EnableTrustedProxyCheck: true, TrustedProxies: []string{"10.0.0.1"}},
+			header: "1.1.1.1",
+			wantIP: "0.0.0.0",
+		},
+		{
+			name:   "spoofed entries before the proxy's",
+			config: fiber.Config{ProxyHeader: fiber.HeaderXForwardedFor, EnableTrustedProxyCheck: true, TrustedProxies: []string{"0.0.0.0"}},
+			header: "1.1.1.1, 2.2.2.2",
+			wantIP: "2.2.2.2",
+		},
+		{
+			name:   "chained trusted proxies",
+			config: fiber.Config{ProxyHeader: fiber.HeaderXForwardedFor, EnableTrustedProxyCheck: true, TrustedProxies: []string{"0.0.0.0", "10.0.0.0/8"}},
+			header: "1.1.1.1, 2.2.2.2, 10.1.2.3",
+			wantIP: "2.2.2.2",
+		},
+		{
+			name:   "malformed entry",
+			config: fiber.Config{ProxyHeader: fiber.HeaderXForwardedFor, EnableTrustedProxyCheck: true, TrustedProxies: []string{"0.0.0.0"}},
+			header: "not-an-ip",
+			wantIP: "0.0.0.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(tt.config)
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendString(ClientIP(c))
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			req.Header.Set(fiber.HeaderXForwardedFor, tt.header)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.wantIP {
+				t.Fatalf("ClientIP = %q, want %q", body, tt.wantIP)
+			}
+		})
+	}
+}
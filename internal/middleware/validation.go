@@ -1,18 +1,20 @@
 package middleware
 
 import (
-	"event-management-backend/internal/utils"
-
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 )
 
 var validate = validator.New()
 
+// ValidateBody parses and validates the request body into dest. Handlers call
+// the returned handler inline and return its error, a 400 *fiber.Error
+// rendered by the global error handler, when the body is invalid. It does not
+// call c.Next, which would fall through to the next route.
 func ValidateBody(dest interface{}) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if err := c.BodyParser(dest); err != nil {
-			return utils.Error(c, "Invalid request body", fiber.StatusBadRequest)
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 		}
 
 		if err := validate.Struct(dest); err != nil {
@@ -37,10 +39,10 @@ func ValidateBody(dest interface{}) fiber.Handler {
 				errorMessage = "Validation failed for " + firstError.Field()
 			}
 
-			return utils.Error(c, errorMessage, fiber.StatusBadRequest)
+			return fiber.NewError(fiber.StatusBadRequest, errorMessage)
 		}
 
 		c.Locals("validatedBody", dest)
-		return c.Next()
+		return nil
 	}
 }
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIVersion tags the request with the API version of the route group it was
+// mounted on so handlers can shape responses for that version.
+func APIVersion(version string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("api_version", version)
+		c.Set("API-Version", version)
+		return c.Next()
+	}
+}
+
+// Deprecated advertises that a version is deprecated using the Deprecation,
+// Sunset (optional, HTTP date) and Link successor-version headers.
+func Deprecated(sunset, successor string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set("Deprecation", "true")
+		if sunset != "" {
+			c.Set("Sunset", sunset)
+		}
+		if successor != "" {
+			c.Set(fiber.HeaderLink, fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+		}
+		return c.Next()
+	}
+}
+
+// GetAPIVersion returns the version set by APIVersion, defaulting to v1
+func GetAPIVersion(c *fiber.Ctx) string {
+	if version, ok := c.Locals("api_version").(string); ok && version != "" {
+		return version
+	}
+	return "v1"
+}
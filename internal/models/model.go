@@ -1,93 +1,941 @@
 package models
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
+	"event-management-backend/internal/fieldcrypt"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type User struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Email     string    `gorm:"uniqueIndex;not null" json:"email"`
-	Password  string    `gorm:"not null" json:"-"`
-	Role      string    `gorm:"type:varchar(20);not null;default:'staff'" json:"role"` // admin|organizer|staff
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Email    string    `gorm:"uniqueIndex;not null" json:"email"`
+	Password string    `gorm:"not null" json:"-"`
+	Role     string    `gorm:"type:varchar(20);not null;default:'staff'" json:"role"` // admin|organizer|staff|sponsor|scanner
+	// Set for service accounts, whose email is only a placeholder
+	Name string `gorm:"type:varchar(100)" json:"name,omitempty"`
+	// Set for sponsor booth staff, who can only capture leads for this sponsor
+	SponsorID *uuid.UUID `gorm:"type:uuid;index" json:"sponsor_id,omitempty"`
+	// Deactivated users keep their history but can no longer log in
+	IsActive bool `gorm:"not null;default:true" json:"is_active"`
+	// Tokens issued up to this time are rejected; set when an admin revokes
+	// all of the user's sessions
+	TokensRevokedAt *time.Time `json:"-"`
+	// Authenticator secret; it is pending until a code confirms enrollment
+	// and TOTPEnabled is set
+	TOTPSecret  string `gorm:"type:text;serializer:encrypted" json:"-"`
+	TOTPEnabled bool   `gorm:"not null;default:false" json:"totp_enabled"`
+	// Time step of the last accepted code, so each code is accepted once
+	TOTPLastStep int64      `gorm:"not null;default:0" json:"-"`
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
+	LastLoginIP  string     `gorm:"type:varchar(45)" json:"last_login_ip,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	// Deleted users are hidden but kept, so the verifications and audit
+	// entries recorded in their name still resolve
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty" swaggertype:"string" format:"date-time"`
+}
+
+// LoginEvent records a successful login
+type LoginEvent struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index:idx_login_events_user" json:"user_id"`
+	IP        string    `gorm:"type:varchar(45)" json:"ip"`
+	UserAgent string    `gorm:"type:varchar(255)" json:"user_agent"`
+	CreatedAt time.Time `gorm:"index:idx_login_events_user;index" json:"created_at"`
 }
 
 type Event struct {
-	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
 	Title       string    `gorm:"not null" json:"title"`
 	Slug        string    `gorm:"uniqueIndex;not null" json:"slug"`
 	Description string    `gorm:"type:text" json:"description"`
 	StartsAt    time.Time `json:"starts_at"`
 	EndsAt      time.Time `json:"ends_at"`
 	LogoPath    string    `json:"logo_path"`
-	TicketPrice float64   `gorm:"default:0" json:"ticket_price"`
-	TicketQuota *int      `json:"ticket_quota"` // nil = unlimited
-	IsActive    bool      `gorm:"default:true" json:"is_active"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	// Resized logo URLs keyed by variant (thumb, small, medium), filled in
+	// by a background job after upload
+	LogoVariants map[string]string `gorm:"type:jsonb;serializer:json" json:"logo_variants,omitempty"`
+	TicketPrice  float64           `gorm:"default:0" json:"ticket_price"`
+	TicketQuota  *int              `json:"ticket_quota"` // nil = unlimited
+	IsActive     bool              `gorm:"default:true" json:"is_active"`
+	// draft|published|cancelled|archived. Only published events are listed
+	// publicly and take registrations. Rows predating the lifecycle default
+	// to published.
+	Status string `gorm:"type:varchar(20);not null;default:'published';index" json:"status"`
+	// public|unlisted|private. Only public events are listed publicly;
+	// private events with an access code need it to be viewed by slug and
+	// registered for.
+	Visibility string `gorm:"type:varchar(20);not null;default:'public';index" json:"visibility"`
+	// Only the hash of the access code is stored
+	AccessCodeHash string `gorm:"type:varchar(64)" json:"-"`
+	// Registrations wait for organizer approval before the QR code is issued
+	RequiresApproval bool `gorm:"not null;default:false" json:"requires_approval"`
+	// Hours participants have to confirm their email address through an
+	// emailed link; unconfirmed registrations expire after that and free
+	// their ticket. 0 turns email confirmation off.
+	ConfirmationHours int `gorm:"not null;default:0" json:"confirmation_hours"`
+	// off|manual|fifo|priority. Registrations beyond the ticket quota join
+	// the waitlist unless off; fifo and priority promote waiting people as
+	// tickets free up, in that order, manual leaves it to organizers.
+	Waitlist string `gorm:"type:varchar(20);not null;default:'off'" json:"waitlist"`
+	// Questions of the registration form beyond the fixed participant
+	// details; answers are stored in Participant.Answers
+	FormFields []FormField `gorm:"type:jsonb;serializer:json" json:"form_fields,omitempty"`
+	// Registration window. Registration is open from creation when opens_at
+	// is nil and until the event ends when closes_at is nil.
+	RegistrationOpensAt  *time.Time `json:"registration_opens_at,omitempty"`
+	RegistrationClosesAt *time.Time `json:"registration_closes_at,omitempty"`
+	// upcoming|open|closed; computed on read, not stored
+	RegistrationState string `gorm:"-" json:"registration_state,omitempty"`
+	// Public registrations must pass a CAPTCHA, when CAPTCHA_PROVIDER is set
+	RequiresCaptcha bool `gorm:"not null;default:false" json:"requires_captcha"`
+	// in_person|online|hybrid; online and hybrid events hold OnlineMeetings
+	Format string `gorm:"type:varchar(20);not null;default:'in_person'" json:"format"`
+	// Comma separated origins allowed to embed this event's registration widget
+	WidgetOrigins string `gorm:"type:text" json:"widget_origins,omitempty"`
+	// Public key the embedded widget sends with its requests; empty when the
+	// widget is disabled
+	WidgetKey string `gorm:"type:varchar(64);index" json:"-"`
+	// Lobby screen settings; only the hash of the kiosk token is stored
+	KioskTokenHash string `gorm:"type:varchar(64);index" json:"-"`
+	KioskMessage   string `gorm:"type:text" json:"kiosk_message,omitempty"`
+	// Live counters are published at /public/events/:slug/live
+	LiveStatsPublic bool `gorm:"not null;default:false" json:"live_stats_public"`
+	// Where the event takes place; days may be held elsewhere
+	VenueID   *uuid.UUID `gorm:"type:uuid;index" json:"venue_id,omitempty"`
+	Version   int        `gorm:"not null;default:1" json:"version"` // optimistic locking
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
 
 	// Relations
+	Venue *Venue `gorm:"foreignKey:VenueID" json:"venue,omitempty"`
+	// The gallery image marked as banner; preload with the is_banner
+	// condition
+	Banner       *EventImage   `gorm:"foreignKey:EventID" json:"banner,omitempty"`
 	EventDays    []EventDay    `gorm:"foreignKey:EventID" json:"event_days,omitempty"`
 	Participants []Participant `gorm:"foreignKey:EventID" json:"participants,omitempty"`
 }
 
+// FormField is a custom question of an event's registration form
+type FormField struct {
+	// Lowercase letters, digits and underscores; answers are keyed by it
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	// text|number|boolean|select|date
+	Type     string `json:"type"`
+	Required bool   `json:"required,omitempty"`
+	// Values a select field accepts
+	Options []string `json:"options,omitempty"`
+}
+
+// EventImage is a picture of an event's gallery. At most one image per event
+// is its banner.
+type EventImage struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID   uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	Path      string    `gorm:"not null" json:"path"`
+	Caption   string    `gorm:"type:varchar(300)" json:"caption,omitempty"`
+	Position  int       `gorm:"not null;default:0" json:"position"` // gallery order, from 0
+	IsBanner  bool      `gorm:"not null;default:false" json:"is_banner"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 type EventDay struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
 	EventID   uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
 	DayNumber int       `gorm:"not null" json:"day_number"`
 	Label     string    `gorm:"not null" json:"label"`
 	Date      time.Time `gorm:"not null" json:"date"`
+	// Set when the day is held somewhere else than the event's venue
+	VenueID *uuid.UUID `gorm:"type:uuid;index" json:"venue_id,omitempty"`
+	// Most participants admitted on the day; unlimited when nil
+	Capacity  *int      `json:"capacity,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
 	// Relations
+	Venue        *Venue        `gorm:"foreignKey:VenueID" json:"venue,omitempty"`
 	EventActions []EventAction `gorm:"foreignKey:EventDayID" json:"event_actions,omitempty"`
 }
 
+// Venue is a place events and event days are held at
+type Venue struct {
+	ID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name    string    `gorm:"not null" json:"name"`
+	Address string    `gorm:"type:text" json:"address"`
+	// WGS84 coordinates, for maps and directions
+	Latitude  *float64  `json:"latitude,omitempty"`
+	Longitude *float64  `json:"longitude,omitempty"`
+	Capacity  *int      `json:"capacity,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TicketType is a price tier of an event, such as Early Bird or VIP.
+// Registrations for events with ticket types pick one of them.
+type TicketType struct {
+	ID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	Name    string    `gorm:"type:varchar(100);not null" json:"name"`
+	Price   float64   `gorm:"type:decimal(10,2);default:0" json:"price"`
+	Quota   *int      `json:"quota"` // nil = unlimited
+	// Sale window; open-ended on either side when nil
+	SalesStartAt *time.Time `json:"sales_start_at,omitempty"`
+	SalesEndAt   *time.Time `json:"sales_end_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// QuotaRule caps the registrations of an event for a ticket type, a
+// division or both, e.g. "at most 50 from Finance"
+type QuotaRule struct {
+	ID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	Name    string    `gorm:"type:varchar(100);not null" json:"name"`
+	// Registrations for this ticket type count; any ticket type when nil
+	TicketTypeID *uuid.UUID `gorm:"type:uuid" json:"ticket_type_id,omitempty"`
+	// Registrations from this division count, ignoring case; any division
+	// when empty
+	Division         string    `gorm:"type:varchar(100)" json:"division,omitempty"`
+	MaxRegistrations int       `gorm:"not null" json:"max_registrations"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Applies reports whether a registration for ticketTypeID, nil for none, from
+// division counts against the rule
+func (r *QuotaRule) Applies(ticketTypeID *uuid.UUID, division string) bool {
+	if r.TicketTypeID != nil && (ticketTypeID == nil || *ticketTypeID != *r.TicketTypeID) {
+		return false
+	}
+	return r.Division == "" || strings.EqualFold(r.Division, strings.TrimSpace(division))
+}
+
+// EventRevision records the fields an update changed on an event
+type EventRevision struct {
+	ID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID uuid.UUID `gorm:"type:uuid;not null;index:idx_event_revisions_event" json:"event_id"`
+	// Nil for changes made by the system or an API key
+	EditorID *uuid.UUID `gorm:"type:uuid" json:"editor_id,omitempty"`
+	// Version of the event after the update
+	Version   int                    `gorm:"not null" json:"version"`
+	Changes   map[string]FieldChange `gorm:"type:jsonb;serializer:json" json:"changes"`
+	CreatedAt time.Time              `gorm:"index:idx_event_revisions_event" json:"created_at"`
+}
+
+// FieldChange is the value of a field before and after an update
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
 type EventAction struct {
-	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
 	EventID    uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
 	EventDayID uuid.UUID `gorm:"type:uuid;index;not null" json:"event_day_id"`
 	Name       string    `gorm:"not null" json:"name"`
 	Code       string    `gorm:"uniqueIndex;not null" json:"code"`
-	IsActive   bool      `gorm:"default:true" json:"is_active"`
+	// Restricted area scanned at this action, e.g. "backstage". Empty means
+	// the action is open to every registered participant.
+	Zone     string `gorm:"type:varchar(50)" json:"zone,omitempty"`
+	IsActive bool   `gorm:"default:true" json:"is_active"`
+	// Scans are only accepted within this window; open-ended on either side
+	// when nil
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+	// Display order within the day, from 0
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Session is a talk or workshop on the agenda. Attendance is scanned through
+// the session's own EventAction, created together with the session.
+type Session struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID     uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	EventDayID  uuid.UUID `gorm:"type:uuid;index;not null" json:"event_day_id"`
+	ActionID    uuid.UUID `gorm:"type:uuid;uniqueIndex;not null" json:"action_id"`
+	Title       string    `gorm:"not null" json:"title"`
+	Room        string    `json:"room"`
+	Capacity    *int      `json:"capacity"` // nil = unlimited
+	StartsAt    time.Time `gorm:"not null" json:"starts_at"`
+	EndsAt      time.Time `gorm:"not null" json:"ends_at"`
+	CreditHours float64   `gorm:"default:0" json:"credit_hours"` // CPD credit earned by attending
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relations
+	Action EventAction `gorm:"foreignKey:ActionID" json:"action,omitempty"`
+}
+
+// Speaker presents sessions at an event. Speakers get their own QR
+// credential, which is also admitted at actions in the zones they are granted.
+type Speaker struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID   uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	Name      string    `gorm:"not null" json:"name"`
+	Email     string    `gorm:"type:text;serializer:encrypted" json:"email"`
+	Bio       string    `gorm:"type:text" json:"bio"`
+	PhotoPath string    `json:"photo_path"`
+	// Square crops of the photo by variant name, see utils.PhotoVariants
+	PhotoVariants map[string]string `gorm:"type:jsonb;serializer:json" json:"photo_variants,omitempty"`
+	QRPath        string            `json:"qr_path"`
+	Zones         []string          `gorm:"type:jsonb;serializer:json" json:"zones"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+
+	// Relations
+	Sessions []Session `gorm:"many2many:speaker_sessions" json:"sessions,omitempty"`
+}
+
+// SpeakerSession is the join table between speakers and their sessions
+type SpeakerSession struct {
+	SpeakerID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	SessionID uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+// Sponsor is an exhibitor of an event whose booth staff capture leads
+type Sponsor struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID   uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	Name      string    `gorm:"not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Lead is a participant scanned at a sponsor booth. Leads are kept apart
+// from action logs so they never count as verifications.
+type Lead struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	SponsorID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_leads_sponsor_participant" json:"sponsor_id"`
+	ParticipantID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_leads_sponsor_participant" json:"participant_id"`
+	EventID       uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	CapturedBy    uuid.UUID `gorm:"type:uuid;not null" json:"captured_by"`
+	// Whether the participant agreed to share their contact details with the
+	// sponsor; exports leave them out otherwise
+	Consent    bool      `gorm:"not null;default:false" json:"consent"`
+	Notes      string    `gorm:"type:text" json:"notes"`
+	CapturedAt time.Time `json:"captured_at"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Relations
+	Participant Participant `gorm:"foreignKey:ParticipantID" json:"participant,omitempty"`
+}
+
+// Waiver is a document participants of an event must accept before their
+// QR code is released
+type Waiver struct {
+	ID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	Title   string    `gorm:"not null" json:"title"`
+	Body    string    `gorm:"type:text;not null" json:"body"`
+	// Participants must draw their signature instead of typing their name
+	RequireDrawnSignature bool      `gorm:"not null;default:false" json:"require_drawn_signature"`
+	IsActive              bool      `gorm:"default:true" json:"is_active"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// WaiverSignature records a participant accepting a waiver
+type WaiverSignature struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	WaiverID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_waiver_signatures_waiver_participant" json:"waiver_id"`
+	ParticipantID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_waiver_signatures_waiver_participant" json:"participant_id"`
+	EventID       uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	TypedName     string    `json:"typed_name"`
+	// File name of the drawn signature in SIGNATURE_DIR; empty when the
+	// participant typed their name
+	SignatureFile string    `json:"-"`
+	IPAddress     string    `gorm:"type:varchar(45)" json:"ip_address"`
+	UserAgent     string    `gorm:"type:text" json:"user_agent"`
+	SignedAt      time.Time `json:"signed_at"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Relations
+	Waiver      Waiver      `gorm:"foreignKey:WaiverID" json:"waiver,omitempty"`
+	Participant Participant `gorm:"foreignKey:ParticipantID" json:"participant,omitempty"`
+}
+
+// MealCoupon is a meal served on an event day to participants of one
+// dietary preference. Catering scans it through its own EventAction, created
+// together with the coupon.
+type MealCoupon struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID    uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	EventDayID uuid.UUID `gorm:"type:uuid;index;not null" json:"event_day_id"`
+	ActionID   uuid.UUID `gorm:"type:uuid;uniqueIndex;not null" json:"action_id"`
+	Meal       string    `gorm:"not null" json:"meal"` // e.g. "Lunch"
+	Preference string    `gorm:"type:varchar(30);not null" json:"preference"`
+	Quota      *int      `json:"quota"` // meals prepared; nil = unlimited
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Relations
+	Action EventAction `gorm:"foreignKey:ActionID" json:"action,omitempty"`
+}
+
+// Shift is a staff rota slot on an event day. Once an event has shifts,
+// staff may only scan at actions covered by a shift they are assigned to.
+type Shift struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID    uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	EventDayID uuid.UUID `gorm:"type:uuid;index;not null" json:"event_day_id"`
+	// Gate worked during the shift; nil covers every action of the day
+	ActionID  *uuid.UUID `gorm:"type:uuid;index" json:"action_id,omitempty"`
+	Name      string     `gorm:"not null" json:"name"`
+	StartsAt  time.Time  `gorm:"not null" json:"starts_at"`
+	EndsAt    time.Time  `gorm:"not null" json:"ends_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Relations
+	Action      *EventAction      `gorm:"foreignKey:ActionID" json:"action,omitempty"`
+	Assignments []ShiftAssignment `gorm:"foreignKey:ShiftID" json:"assignments,omitempty"`
+}
+
+// ShiftAssignment puts a staff user on a shift
+type ShiftAssignment struct {
+	ShiftID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"shift_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// EventStaff assigns a staff user to an event. Staff can only verify and
+// list participants of events they are assigned to.
+type EventStaff struct {
+	EventID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"event_id"`
+	UserID     uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"user_id"`
+	AssignedBy uuid.UUID `gorm:"type:uuid;not null" json:"assigned_by"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Relations
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// SeatingTable is a table of a seated event day, e.g. at a gala dinner. Its
+// seats are numbered from 1 to Capacity.
+type SeatingTable struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID    uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	EventDayID uuid.UUID `gorm:"type:uuid;index;not null" json:"event_day_id"`
+	Name       string    `gorm:"not null" json:"name"`
+	Capacity   int       `gorm:"not null" json:"capacity"`
+	// Reserves the table for a division when auto-assigning; empty for open
+	// tables
+	Division  string    `json:"division,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SeatAssignment places a participant on a seat for an event day
+type SeatAssignment struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventDayID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_seat_assignments_day_participant" json:"event_day_id"`
+	ParticipantID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_seat_assignments_day_participant" json:"participant_id"`
+	TableID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_seat_assignments_table_seat" json:"table_id"`
+	SeatNumber    int       `gorm:"not null;uniqueIndex:idx_seat_assignments_table_seat" json:"seat_number"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Relations
+	Table       SeatingTable `gorm:"foreignKey:TableID" json:"table,omitempty"`
+	Participant Participant  `gorm:"foreignKey:ParticipantID" json:"participant,omitempty"`
+}
+
+// SpeakerCheckIn records a scan of a speaker credential. Speakers come and go
+// from restricted zones, so every scan is kept rather than one per action.
+type SpeakerCheckIn struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	SpeakerID  uuid.UUID `gorm:"type:uuid;index;not null" json:"speaker_id"`
+	ActionID   uuid.UUID `gorm:"type:uuid;index;not null" json:"action_id"`
+	VerifiedBy uuid.UUID `gorm:"type:uuid;not null" json:"verified_by"`
+	VerifiedAt time.Time `json:"verified_at"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Relations
+	Action EventAction `gorm:"foreignKey:ActionID" json:"action,omitempty"`
 }
 
 type Participant struct {
-	ID            uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	EventID       uuid.UUID      `gorm:"type:uuid;index;not null" json:"event_id"`
-	Name          string         `gorm:"not null" json:"name"`
-	Email         string         `gorm:"not null" json:"email"`
-	Phone         string         `json:"phone"`
-	Division      string         `json:"division"`
-	Address       string         `json:"address"`
-	QRPath        string         `json:"qr_path"`
-	PaymentStatus string         `gorm:"type:varchar(20);default:'unpaid'" json:"payment_status"` // unpaid|pending|paid
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID       uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	Name          string    `gorm:"not null" json:"name"`
+	Email         string    `gorm:"type:text;not null;serializer:encrypted" json:"email"`
+	EmailHash     string    `gorm:"type:varchar(64);index" json:"-"` // keyed hash for lookups, see fieldcrypt.Hash
+	Phone         string    `gorm:"type:text;serializer:encrypted" json:"phone"`
+	PhoneHash     string    `gorm:"type:varchar(64);index" json:"-"` // keyed hash of the phone digits, see PhoneDigits
+	Division      string    `json:"division"`
+	Address       string    `gorm:"type:text;serializer:encrypted" json:"address"`
+	QRPath        string    `json:"qr_path"`
+	PaymentStatus string    `gorm:"type:varchar(20);default:'unpaid'" json:"payment_status"` // unpaid|pending|paid|refund_pending
+	// Ticket type registered for, on events that have ticket types
+	TicketTypeID *uuid.UUID `gorm:"type:uuid;index" json:"ticket_type_id,omitempty"`
+	// Dietary preference, which decides the meal coupons a participant may
+	// redeem
+	MealPreference string `gorm:"type:varchar(30);default:'regular'" json:"meal_preference"`
+	// Answers to the event's form fields keyed by FormField.Key: strings for
+	// text, select and date (YYYY-MM-DD) fields, numbers and booleans
+	Answers map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"answers,omitempty"`
+	// Labels organizers flag participants with, such as vip, speaker or
+	// press; lowercase
+	Tags []string `gorm:"type:jsonb;serializer:json" json:"tags,omitempty"`
+	// Organizer review of the registration: pending|approved|rejected. Only
+	// events requiring approval start registrations as pending; events
+	// requiring email confirmation start them as pending_confirmation.
+	ApprovalStatus  string     `gorm:"type:varchar(20);default:'approved';index" json:"approval_status"`
+	RejectionReason string     `gorm:"type:text" json:"rejection_reason,omitempty"`
+	ReviewedBy      *uuid.UUID `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	// Registrations pending email confirmation are deleted when not
+	// confirmed by then
+	ConfirmationExpiresAt *time.Time `gorm:"index" json:"confirmation_expires_at,omitempty"`
+	// Bumped when a lost badge is reissued; QR codes of older versions are
+	// refused. Version 0 is the original, unsigned QR code.
+	CredentialVersion int `gorm:"not null;default:0" json:"credential_version"`
+	// Gate staff must check the participant's ID at the next scan
+	RequiresIDCheck bool `gorm:"not null;default:false" json:"requires_id_check"`
+	// When the personal join link of an online event was emailed
+	JoinLinkSentAt *time.Time `json:"join_link_sent_at,omitempty"`
+	// Set when the registration was cancelled; cancelled participants are
+	// refused at every scan
+	CancelledAt        *time.Time `gorm:"index" json:"cancelled_at,omitempty"`
+	CancellationReason string     `gorm:"type:text" json:"cancellation_reason,omitempty"`
+	// When the participant was emailed that their registration was cancelled
+	CancellationNoticeSentAt *time.Time `json:"cancellation_notice_sent_at,omitempty"`
+	// Set when a retention rule erased the participant's personal data
+	AnonymizedAt *time.Time     `gorm:"index" json:"anonymized_at,omitempty"`
+	Version      int            `gorm:"not null;default:1" json:"version"` // optimistic locking
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relations
 	Event      Event       `gorm:"foreignKey:EventID" json:"event,omitempty"`
 	ActionLogs []ActionLog `gorm:"foreignKey:ParticipantID" json:"action_logs,omitempty"`
 }
 
+// BeforeSave keeps the lookup hashes in sync with the encrypted email and
+// phone
+func (p *Participant) BeforeSave(tx *gorm.DB) error {
+	if p.Email != "" {
+		p.EmailHash = fieldcrypt.Hash(p.Email)
+	}
+	p.PhoneHash = ""
+	if digits := PhoneDigits(p.Phone); digits != "" {
+		p.PhoneHash = fieldcrypt.Hash(digits)
+	}
+	return nil
+}
+
+// TagComplimentary marks participants who attend for free: they are let in
+// at paid events whatever their payment status
+const TagComplimentary = "complimentary"
+
+// HasTag reports whether the participant is tagged with tag
+func (p *Participant) HasTag(tag string) bool {
+	for _, t := range p.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeTags returns tags with add appended and remove taken out, in order and
+// without duplicates
+func MergeTags(tags, add, remove []string) []string {
+	drop := make(map[string]bool, len(remove))
+	for _, tag := range remove {
+		drop[tag] = true
+	}
+	merged := []string{}
+	seen := map[string]bool{}
+	for _, list := range [][]string{tags, add} {
+		for _, tag := range list {
+			if !drop[tag] && !seen[tag] {
+				seen[tag] = true
+				merged = append(merged, tag)
+			}
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// PhoneDigits normalizes a phone number for lookups, so "+62 812-345" and
+// "62812345" match
+func PhoneDigits(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+}
+
+// AnswerText formats an answer of Participant.Answers as text, the way
+// PostgreSQL's ->> operator does
+func AnswerText(answer interface{}) string {
+	switch v := answer.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+// WaitlistEntry is someone waiting for a ticket of a full event, with the
+// details they would have registered with
+type WaitlistEntry struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID        uuid.UUID  `gorm:"type:uuid;not null;index:idx_waitlist_event_status" json:"event_id"`
+	Name           string     `gorm:"not null" json:"name"`
+	Email          string     `gorm:"type:text;not null;serializer:encrypted" json:"email"`
+	EmailHash      string     `gorm:"type:varchar(64);index" json:"-"` // keyed hash for lookups, see fieldcrypt.Hash
+	Phone          string     `gorm:"type:text;serializer:encrypted" json:"phone"`
+	Division       string     `json:"division"`
+	Address        string     `gorm:"type:text;serializer:encrypted" json:"address"`
+	MealPreference string     `gorm:"type:varchar(30);default:'regular'" json:"meal_preference"`
+	TicketTypeID   *uuid.UUID `gorm:"type:uuid" json:"ticket_type_id,omitempty"`
+	// Answers to the event's form fields, see Participant.Answers
+	Answers map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"answers,omitempty"`
+	// Higher goes first on events promoting by priority
+	Priority int `gorm:"not null;default:0" json:"priority"`
+	// waiting|promoted
+	Status string `gorm:"type:varchar(20);not null;default:'waiting';index:idx_waitlist_event_status" json:"status"`
+	// The participant registered on promotion
+	ParticipantID *uuid.UUID `gorm:"type:uuid" json:"participant_id,omitempty"`
+	PromotedAt    *time.Time `json:"promoted_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// BeforeSave keeps the email lookup hash in sync with the encrypted email
+func (w *WaitlistEntry) BeforeSave(tx *gorm.DB) error {
+	if w.Email != "" {
+		w.EmailHash = fieldcrypt.Hash(w.Email)
+	}
+	return nil
+}
+
+// OnlineMeeting is the video call of an event action, e.g. the webinar of a
+// day. Attendance reports of the meeting are recorded as verifications of the
+// action, so online and on-site attendance are counted together.
+type OnlineMeeting struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID  uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	ActionID uuid.UUID `gorm:"type:uuid;uniqueIndex;not null" json:"action_id"`
+	Provider string    `gorm:"type:varchar(20);not null" json:"provider"` // zoom|meet|other
+	JoinURL  string    `gorm:"type:text;not null" json:"join_url"`
+	// Meeting or webinar ID at the provider, matched against attendance
+	// reports
+	ExternalID string `gorm:"type:varchar(100);index" json:"external_id,omitempty"`
+	// Minutes a participant must attend for the report to count
+	MinMinutes int       `gorm:"not null;default:0" json:"min_minutes"`
+	CreatedBy  uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Relations
+	Action EventAction `gorm:"foreignKey:ActionID" json:"action,omitempty"`
+}
+
+// CredentialIncident records the reissue of a participant credential, e.g.
+// after a lost badge
+type CredentialIncident struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID       uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	ParticipantID uuid.UUID `gorm:"type:uuid;index;not null" json:"participant_id"`
+	Reason        string    `gorm:"type:text;not null" json:"reason"`
+	ReportedBy    uuid.UUID `gorm:"type:uuid;not null" json:"reported_by"`
+	// Credential version issued by the reissue
+	CredentialVersion int       `gorm:"not null" json:"credential_version"`
+	RequireIDCheck    bool      `json:"require_id_check"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// ParticipantNote is a remark staff recorded about a participant, e.g. "ID
+// mismatch, approved by supervisor". Notes cannot be edited.
+type ParticipantNote struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	ParticipantID uuid.UUID `gorm:"type:uuid;index;not null" json:"participant_id"`
+	Body          string    `gorm:"type:text;not null" json:"body"`
+	AuthorID      uuid.UUID `gorm:"type:uuid;not null" json:"author_id"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Relations
+	Author User `gorm:"foreignKey:AuthorID;constraint:OnDelete:RESTRICT" json:"author,omitempty"`
+}
+
+// APIKey authenticates an external system, e.g. a ticket kiosk or a partner
+// registration site, on the /integrations endpoints. Only the hash of the key
+// is stored.
+type APIKey struct {
+	ID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name string    `gorm:"not null" json:"name"`
+	// First characters of the key, to tell keys apart
+	Prefix  string `gorm:"type:varchar(12);not null" json:"prefix"`
+	KeyHash string `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	// registration|verification
+	Scopes []string `gorm:"type:jsonb;serializer:json" json:"scopes"`
+	// Events the key may register and verify participants for
+	EventIDs []uuid.UUID `gorm:"type:jsonb;serializer:json" json:"event_ids"`
+	// Admin who issued the key
+	CreatedBy  uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// RetentionRule applies a retention action to events that ended more than
+// AfterDays days ago. Rules apply to every event of the deployment except
+// those under a LegalHold.
+// RevokedToken denylists a JWT by its jti claim until the token would have
+// expired anyway
+type RevokedToken struct {
+	JTI       string    `gorm:"type:varchar(36);primaryKey" json:"jti"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ServiceToken is a non-expiring token of a scanner service account, limited
+// to verification. Its ID is the token's jti.
+type ServiceToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name      string     `gorm:"type:varchar(100);not null" json:"name"` // e.g. the device it is installed on
+	CreatedBy uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RefreshToken lets a user who logged in with remember-me get new access
+// tokens without their password. Only its hash is stored.
+type RefreshToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	TokenHash string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null;index" json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RoleChangeRequest is a role change waiting for a second admin's approval
+type RoleChangeRequest struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	FromRole string    `gorm:"type:varchar(20);not null" json:"from_role"`
+	ToRole   string    `gorm:"type:varchar(20);not null" json:"to_role"`
+	Reason   string    `gorm:"type:text" json:"reason,omitempty"`
+	// pending|approved|rejected
+	Status      string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	RequestedBy uuid.UUID  `gorm:"type:uuid;not null" json:"requested_by"`
+	ReviewedBy  *uuid.UUID `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// Relations
+	User      *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Requester *User `gorm:"foreignKey:RequestedBy" json:"requester,omitempty"`
+}
+
+type RetentionRule struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Action    string    `gorm:"type:varchar(30);not null" json:"action"` // anonymize_pii|delete_qr_files
+	AfterDays int       `gorm:"not null" json:"after_days"`
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LegalHold exempts an event from retention rules until it is released
+type LegalHold struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID   uuid.UUID `gorm:"type:uuid;uniqueIndex;not null" json:"event_id"`
+	Reason    string    `gorm:"type:text;not null" json:"reason"`
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type ActionLog struct {
-	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
 	ParticipantID uuid.UUID `gorm:"type:uuid;index;not null" json:"participant_id"`
 	ActionID      uuid.UUID `gorm:"type:uuid;index;not null" json:"action_id"`
-	VerifiedBy    uuid.UUID `gorm:"type:uuid;index;not null" json:"verified_by"`
-	VerifiedAt    time.Time `json:"verified_at"`
-	CreatedAt     time.Time `json:"created_at"`
+	// Exactly one of VerifiedBy and APIKeyID is set: scans from kiosks are
+	// recorded against their API key rather than a user
+	VerifiedBy *uuid.UUID `gorm:"type:uuid;index" json:"verified_by"`
+	APIKeyID   *uuid.UUID `gorm:"type:uuid;index" json:"api_key_id,omitempty"`
+	// Scanner device the scan was made on, when the app sent one
+	DeviceID   *uuid.UUID `gorm:"type:uuid;index" json:"device_id,omitempty"`
+	VerifiedAt time.Time  `json:"verified_at"`
+	// scan|online. Online attendance comes from webinar reports and is
+	// recorded in the name of the organizer who set up the meeting.
+	Source    string    `gorm:"type:varchar(20);not null;default:'scan'" json:"source"`
+	CreatedAt time.Time `json:"created_at"`
 
 	// Relations
 	Participant Participant `gorm:"foreignKey:ParticipantID" json:"participant,omitempty"`
 	Action      EventAction `gorm:"foreignKey:ActionID" json:"action,omitempty"`
-	Verifier    User        `gorm:"foreignKey:VerifiedBy" json:"verifier,omitempty"`
+	// Users referenced by logs cannot be deleted for good, only soft deleted
+	Verifier User    `gorm:"foreignKey:VerifiedBy;constraint:OnDelete:RESTRICT" json:"verifier,omitempty"`
+	APIKey   *APIKey `gorm:"foreignKey:APIKeyID" json:"api_key,omitempty"`
+	Device   *Device `gorm:"foreignKey:DeviceID" json:"device,omitempty"`
+}
+
+// Device is a physical scanner registered by the scanner app
+type Device struct {
+	ID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name string    `gorm:"not null" json:"name"`
+	// android|ios|web|other
+	Platform string `gorm:"type:varchar(20);not null" json:"platform"`
+	// Installation ID generated by the app. Registering it again updates
+	// the existing device instead of adding another.
+	Identifier string `gorm:"type:varchar(100);uniqueIndex;not null" json:"identifier"`
+	// User or service account that last registered the device
+	RegisteredBy uuid.UUID  `gorm:"type:uuid;not null" json:"registered_by"`
+	LastSeenAt   *time.Time `json:"last_seen_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// AuditLog records an administrative change: who did what to which record
+type AuditLog struct {
+	ID uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	// Nil for changes made by the system or an API key
+	ActorID *uuid.UUID `gorm:"type:uuid;index" json:"actor_id,omitempty"`
+	// e.g. participant.payment_status_changed
+	Action     string                 `gorm:"type:varchar(100);index;not null" json:"action"`
+	EntityType string                 `gorm:"type:varchar(50);not null;index:idx_audit_logs_entity" json:"entity_type"`
+	EntityID   string                 `gorm:"type:varchar(64);not null;index:idx_audit_logs_entity" json:"entity_id"`
+	Details    map[string]interface{} `gorm:"type:jsonb;serializer:json" json:"details,omitempty"`
+	CreatedAt  time.Time              `gorm:"index" json:"created_at"`
+
+	// Relations
+	Actor *User `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+}
+
+type Job struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	Type        string     `gorm:"type:varchar(100);index;not null" json:"type"`
+	Payload     string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status      string     `gorm:"type:varchar(20);index;not null;default:'pending'" json:"status"` // pending|running|completed|failed
+	Attempts    int        `gorm:"default:0" json:"attempts"`
+	MaxAttempts int        `gorm:"default:5" json:"max_attempts"`
+	RunAt       time.Time  `gorm:"index;not null" json:"run_at"`
+	LastError   string     `gorm:"type:text" json:"last_error,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ImportJob is a participant file import run by the job queue
+type ImportJob struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID   uuid.UUID  `gorm:"type:uuid;index;not null" json:"event_id"`
+	CreatedBy *uuid.UUID `gorm:"type:uuid;index" json:"created_by,omitempty"`
+	Filename  string     `json:"filename"`
+	Format    string     `gorm:"type:varchar(10);not null" json:"format"` // csv|xlsx
+	// Header of the column holding each participant field, for columns not
+	// named after the field
+	ColumnMapping map[string]string `gorm:"type:jsonb;serializer:json" json:"column_mapping,omitempty"`
+	// Dry runs check every row without registering anyone; Imported then
+	// counts the rows that would be imported
+	DryRun bool `gorm:"not null;default:false" json:"dry_run"`
+	// Uploaded file, removed once the import is over
+	FilePath string `json:"-"`
+	Status   string `gorm:"type:varchar(20);index;not null;default:'pending'" json:"status"` // pending|running|completed|failed
+	// Data rows of the file, known once it has been read
+	TotalRows     int              `json:"total_rows"`
+	ProcessedRows int              `json:"processed_rows"`
+	Imported      int              `json:"imported"`
+	Failed        int              `json:"failed"`
+	RowErrors     []ImportRowError `gorm:"type:jsonb;serializer:json" json:"row_errors"`
+	// Why the whole import failed
+	Error      string     `gorm:"type:text" json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// ImportRowError is a problem with one data row of an import, numbered from
+// the first row after the header. Rows with a problem are not imported,
+// except when only their QR code failed.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+type IdempotencyKey struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Key          string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"` // sha256 of user, method, path and client key
+	RequestHash  string    `gorm:"type:varchar(64);not null" json:"-"`
+	StatusCode   int       `gorm:"default:0" json:"status_code"` // 0 while the original request is in flight
+	ResponseBody []byte    `json:"-"`
+	ContentType  string    `json:"-"`
+	ExpiresAt    time.Time `gorm:"index;not null" json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Draw is one lucky draw round of an event. Winners are picked by shuffling
+// the eligible participants, ordered by ID, with a server-generated Seed, so
+// a draw can be replayed from its recorded seed and pool to verify the result.
+type Draw struct {
+	ID            uuid.UUID    `gorm:"type:uuid;primaryKey" json:"id"`
+	EventID       uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex:idx_draws_event_round" json:"event_id"`
+	Round         int          `gorm:"not null;uniqueIndex:idx_draws_event_round" json:"round"`
+	Prize         string       `gorm:"not null" json:"prize"`
+	WinnerCount   int          `gorm:"not null" json:"winner_count"`
+	Seed          int64        `gorm:"not null" json:"seed"`
+	Criteria      DrawCriteria `gorm:"type:jsonb;serializer:json" json:"criteria"`
+	EligibleCount int          `gorm:"not null" json:"eligible_count"`
+	PoolHash      string       `gorm:"type:varchar(64);not null" json:"pool_hash"` // sha256 of the ordered eligible participant IDs
+	DrawnBy       uuid.UUID    `gorm:"type:uuid;not null" json:"drawn_by"`
+	CreatedAt     time.Time    `json:"created_at"`
+
+	// Relations
+	Winners []DrawWinner `gorm:"foreignKey:DrawID" json:"winners,omitempty"`
+}
+
+// DrawCriteria selects the participants eligible for a draw
+type DrawCriteria struct {
+	PaidOnly               bool       `json:"paid_only"`
+	CheckedInSince         *time.Time `json:"checked_in_since,omitempty"`
+	ActionID               string     `json:"action_id,omitempty"` // limits the check-in to one action
+	ActionCode             string     `json:"action_code,omitempty"`
+	ExcludePreviousWinners bool       `json:"exclude_previous_winners"`
+
+	// With ExcludePreviousWinners, only excludes the winners of rounds before
+	// this one when set; used to rebuild the pool of a past draw
+	BeforeRound int `json:"-"`
+}
+
+type DrawWinner struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	DrawID        uuid.UUID `gorm:"type:uuid;index;not null" json:"draw_id"`
+	EventID       uuid.UUID `gorm:"type:uuid;index;not null" json:"event_id"`
+	ParticipantID uuid.UUID `gorm:"type:uuid;index;not null" json:"participant_id"`
+	Position      int       `gorm:"not null" json:"position"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Relations
+	Participant Participant `gorm:"foreignKey:ParticipantID" json:"participant,omitempty"`
 }
@@ -0,0 +1,335 @@
+// Package pdf writes the subset of PDF needed for printable tickets and
+// badges: pages with text in the standard Helvetica fonts, rectangles, lines
+// and raster images. Coordinates are in points from the top left corner of
+// the page.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// Page sizes in points
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+	A6Width  = 297.64
+	A6Height = 419.53
+)
+
+// Font is one of the standard fonts every PDF reader has
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+// Document is a PDF built in memory page by page
+type Document struct {
+	width, height float64
+	pages         []*Page
+	images        []*Image
+}
+
+// New returns an empty document whose pages are width x height points
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// Page is a page of a Document
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+	images  []*Image
+}
+
+// Image is a raster image added to a Document; it can be drawn on any number
+// of pages while being stored once
+type Image struct {
+	id            int
+	Width, Height int
+	rgb           []byte
+}
+
+// AddPage appends a blank page
+func (d *Document) AddPage() *Page {
+	p := &Page{doc: d}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// AddImage stores an image in the document. Transparent pixels are drawn on
+// white.
+func (d *Document) AddImage(img image.Image) *Image {
+	bounds := img.Bounds()
+	rgb := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			blend := func(v uint8) byte {
+				return byte((int(v)*int(c.A) + 255*(255-int(c.A))) / 255)
+			}
+			rgb = append(rgb, blend(c.R), blend(c.G), blend(c.B))
+		}
+	}
+	stored := &Image{id: len(d.images), Width: bounds.Dx(), Height: bounds.Dy(), rgb: rgb}
+	d.images = append(d.images, stored)
+	return stored
+}
+
+// Text draws s with its baseline at y. Characters outside Latin-1 are
+// replaced with question marks.
+func (p *Page) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F%d %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font+1, size, x, p.doc.height-y, escape(s))
+}
+
+// TextCentered draws s centered on x
+func (p *Page) TextCentered(x, y float64, font Font, size float64, s string) {
+	p.Text(x-TextWidth(s, font, size)/2, y, font, size, s)
+}
+
+// FillRect fills a rectangle whose top left corner is at x, y
+func (p *Page) FillRect(x, y, w, h float64, c color.Color) {
+	r, g, b, _ := c.RGBA()
+	fmt.Fprintf(&p.content, "q %.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f Q\n",
+		float64(r)/0xffff, float64(g)/0xffff, float64(b)/0xffff, x, p.doc.height-y-h, w, h)
+}
+
+// StrokeRect outlines a rectangle whose top left corner is at x, y
+func (p *Page) StrokeRect(x, y, w, h, lineWidth float64) {
+	fmt.Fprintf(&p.content, "q %.2f w %.2f %.2f %.2f %.2f re S Q\n", lineWidth, x, p.doc.height-y-h, w, h)
+}
+
+// Line draws a straight line
+func (p *Page) Line(x1, y1, x2, y2, lineWidth float64) {
+	fmt.Fprintf(&p.content, "q %.2f w %.2f %.2f m %.2f %.2f l S Q\n", lineWidth, x1, p.doc.height-y1, x2, p.doc.height-y2)
+}
+
+// Image draws img scaled to w x h with its top left corner at x, y
+func (p *Page) Image(img *Image, x, y, w, h float64) {
+	p.images = append(p.images, img)
+	fmt.Fprintf(&p.content, "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", w, h, x, p.doc.height-y-h, img.id)
+}
+
+// TextWidth returns the width of s in points
+func TextWidth(s string, font Font, size float64) float64 {
+	widths := helveticaWidths
+	if font == HelveticaBold {
+		widths = helveticaBoldWidths
+	}
+	total := 0
+	for _, b := range encode(s) {
+		if b >= 32 && b <= 126 {
+			total += widths[b-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Wrap breaks s into lines no wider than width, between words where it can
+func Wrap(s string, font Font, size, width float64) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && TextWidth(candidate, font, size) > width {
+			lines = append(lines, line)
+			candidate = word
+		}
+		// Words longer than a line are cut
+		for TextWidth(candidate, font, size) > width && len([]rune(candidate)) > 1 {
+			runes := []rune(candidate)
+			cut := len(runes) - 1
+			for cut > 1 && TextWidth(string(runes[:cut]), font, size) > width {
+				cut--
+			}
+			lines = append(lines, string(runes[:cut]))
+			candidate = string(runes[cut:])
+		}
+		line = candidate
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Truncate shortens s with an ellipsis to fit width
+func Truncate(s string, font Font, size, width float64) string {
+	if TextWidth(s, font, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && TextWidth(string(runes)+"...", font, size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "..."
+}
+
+// encode converts s to the Latin-1 bytes of WinAnsiEncoding
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			out = append(out, ' ')
+		case r < 32 || r > 255 || (r >= 127 && r < 160):
+			out = append(out, '?')
+		default:
+			out = append(out, byte(r))
+		}
+	}
+	return out
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	for _, c := range encode(s) {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			if c >= 128 {
+				fmt.Fprintf(&b, "\\%03o", c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	return b.String()
+}
+
+// WriteTo writes the document as a PDF file
+func (d *Document) WriteTo(out io.Writer) (int64, error) {
+	w := &objectWriter{out: out}
+	w.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+
+	// Objects: catalog, page tree, the two fonts, the images, then a page
+	// and its content stream per page
+	const catalog, pages, fonts = 1, 2, 3
+	firstImage := fonts + 2
+	firstPage := firstImage + len(d.images)
+
+	w.object(catalog, "<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	w.object(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %.2f %.2f] >>",
+		strings.Join(kids, " "), len(d.pages), d.width, d.height))
+	w.object(fonts, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	w.object(fonts+1, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, img := range d.images {
+		data, err := deflate(img.rgb)
+		if err != nil {
+			return w.n, err
+		}
+		w.stream(firstImage+i, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode",
+			img.Width, img.Height), data)
+	}
+
+	for i, page := range d.pages {
+		var xobjects strings.Builder
+		seen := map[int]bool{}
+		for _, img := range page.images {
+			if !seen[img.id] {
+				seen[img.id] = true
+				fmt.Fprintf(&xobjects, " /Im%d %d 0 R", img.id, firstImage+img.id)
+			}
+		}
+		w.object(firstPage+2*i, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Contents %d 0 R /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> /XObject <<%s >> >> >>",
+			firstPage+2*i+1, fonts, fonts+1, xobjects.String()))
+		data, err := deflate(page.content.Bytes())
+		if err != nil {
+			return w.n, err
+		}
+		w.stream(firstPage+2*i+1, "/Filter /FlateDecode", data)
+	}
+
+	xref := w.n
+	w.printf("xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, offset := range w.offsets {
+		w.printf("%010d 00000 n \n", offset)
+	}
+	w.printf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets)+1, xref)
+	return w.n, w.err
+}
+
+// objectWriter writes numbered objects in order, remembering their offsets
+// for the cross-reference table
+type objectWriter struct {
+	out     io.Writer
+	n       int64
+	offsets []int64
+	err     error
+}
+
+func (w *objectWriter) printf(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(w.out, format, args...)
+	w.n += int64(n)
+	w.err = err
+}
+
+func (w *objectWriter) object(id int, body string) {
+	w.offsets = append(w.offsets, w.n)
+	w.printf("%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+func (w *objectWriter) stream(id int, dict string, data []byte) {
+	w.offsets = append(w.offsets, w.n)
+	w.printf("%d 0 obj\n<< %s /Length %d >>\nstream\n", id, dict, len(data))
+	if w.err == nil {
+		n, err := w.out.Write(data)
+		w.n += int64(n)
+		w.err = err
+	}
+	w.printf("\nendstream\nendobj\n")
+}
+
+func deflate(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Character widths of the standard fonts for ASCII 32 to 126, in thousandths
+// of the font size
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
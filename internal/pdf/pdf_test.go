@@ -0,0 +1,85 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestDocumentStructure(t *testing.T) {
+	doc := New(A6Width, A6Height)
+	logo := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	logo.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	img := doc.AddImage(logo)
+	for i := 0; i < 2; i++ {
+		page := doc.AddPage()
+		page.Image(img, 10, 10, 40, 20)
+		page.Text(10, 60, HelveticaBold, 12, fmt.Sprintf("Page (%d) of Café", i+1))
+		page.FillRect(0, 0, 10, 10, color.Gray{Y: 200})
+	}
+
+	var out bytes.Buffer
+	n, err := doc.WriteTo(&out)
+	if err != nil || n != int64(out.Len()) {
+		t.Fatalf("WriteTo = %d, %v; wrote %d bytes", n, err, out.Len())
+	}
+	data := out.Bytes()
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatalf("not a PDF file: %q...", data[:20])
+	}
+
+	// Every cross-reference entry must point at its object
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	if m == nil {
+		t.Fatal("no startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(data[xref:], []byte("xref\n0 10\n")) {
+		t.Fatalf("startxref does not point at a table of 10 entries: %q", data[xref:xref+10])
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xref:], -1)
+	if len(entries) != 9 {
+		t.Fatalf("%d objects in the table, want 9", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(data[offset:], []byte(want)) {
+			t.Errorf("entry %d points at %q", i+1, data[offset:offset+10])
+		}
+	}
+
+	// The first page's content: parentheses escaped, Latin-1 as octal
+	loc := regexp.MustCompile(`7 0 obj\n<< /Filter /FlateDecode /Length (\d+) >>\nstream\n`).FindSubmatchIndex(data)
+	if loc == nil {
+		t.Fatal("no content stream for the first page")
+	}
+	length, _ := strconv.Atoi(string(data[loc[2]:loc[3]]))
+	zr, err := zlib.NewReader(bytes.NewReader(data[loc[1] : loc[1]+length]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(zr)
+	if want := `(Page \(1\) of Caf\351) Tj`; !bytes.Contains(content, []byte(want)) {
+		t.Fatalf("content %q does not draw %s", content, want)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if w := TextWidth("Hello", Helvetica, 10); w != 22.78 {
+		t.Fatalf("TextWidth = %v, want 22.78", w)
+	}
+	lines := Wrap("Opening keynote and welcome  reception", Helvetica, 10, 100)
+	if want := []string{"Opening keynote and", "welcome reception"}; !reflect.DeepEqual(lines, want) {
+		t.Fatalf("Wrap = %q, want %q", lines, want)
+	}
+	if got := Truncate("Registration and breakfast", Helvetica, 10, 60); got != "Registratio..." {
+		t.Fatalf("Truncate = %q", got)
+	}
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"event-management-backend/internal/config"
+)
+
+// Limit is a token bucket: Burst requests can be made at once, after which
+// requests are allowed at Rate per second
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute returns a limit allowing n requests per minute with the given
+// burst
+func PerMinute(n, burst int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: burst}
+}
+
+// PerHour returns a limit allowing n requests per hour with the given burst
+func PerHour(n, burst int) Limit {
+	return Limit{Rate: float64(n) / 3600, Burst: burst}
+}
+
+// fillTime is how long an empty bucket takes to fill up again. Buckets idle
+// for longer are full and need not be kept.
+func (l Limit) fillTime() time.Duration {
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+// Store keeps token buckets, keyed e.g. by client IP
+type Store interface {
+	// Take removes a token from the bucket of key. When the bucket is empty
+	// it reports false and how long until a token is available.
+	Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error)
+}
+
+// NewStore returns a Redis store when RATE_LIMIT_REDIS_URL is set, so limits
+// are shared by all instances, and an in-process store otherwise
+func NewStore(cfg *config.Config) (Store, error) {
+	if cfg.RateLimitRedisURL != "" {
+		return NewRedisStore(cfg.RateLimitRedisURL, cfg.RateLimitRedisTimeout)
+	}
+	return NewMemoryStore(), nil
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	idleTTL time.Duration
+}
+
+// MemoryStore keeps buckets in process memory. Limits are per instance.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), lastSweep: time.Now()}
+}
+
+// sweepInterval is how often buckets that filled up again are dropped
+const sweepInterval = time.Minute
+
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		for k, b := range s.buckets {
+			if now.Sub(b.updated) >= b.idleTTL {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+	b.idleTTL = limit.fillTime()
+
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	b.updated = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+		return false, wait, nil
+	}
+	b.tokens--
+	return true, 0, nil
+}
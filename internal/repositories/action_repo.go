@@ -1,10 +1,18 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
 	"event-management-backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrEventDayFull is returned when a scan would admit more participants to
+// an event day than its capacity
+var ErrEventDayFull = errors.New("event day is at full capacity")
+
 type actionRepo struct {
 	db *gorm.DB
 }
@@ -14,7 +22,55 @@ func NewActionRepository(db *gorm.DB) ActionRepository {
 }
 
 func (r *actionRepo) CreateActionLog(log *models.ActionLog) error {
-	return r.db.Create(log).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkDayCapacity(tx, log); err != nil {
+			return err
+		}
+		return tx.Create(log).Error
+	})
+}
+
+// checkDayCapacity locks the event day of the logged action and returns
+// ErrEventDayFull when the day is at capacity and the participant has not
+// been admitted on it yet. It runs in the transaction inserting the log.
+func checkDayCapacity(tx *gorm.DB, log *models.ActionLog) error {
+	var action models.EventAction
+	if err := tx.Select("event_day_id").Where("id = ?", log.ActionID).First(&action).Error; err != nil {
+		return err
+	}
+
+	var day models.EventDay
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", action.EventDayID).
+		First(&day).Error; err != nil {
+		return err
+	}
+	if day.Capacity == nil {
+		return nil
+	}
+
+	dayLogs := func() *gorm.DB {
+		return tx.Model(&models.ActionLog{}).
+			Joins("JOIN event_actions ON event_actions.id = action_logs.action_id").
+			Where("event_actions.event_day_id = ?", day.ID)
+	}
+
+	var admitted int64
+	if err := dayLogs().Where("action_logs.participant_id = ?", log.ParticipantID).Count(&admitted).Error; err != nil {
+		return err
+	}
+	if admitted > 0 {
+		return nil
+	}
+
+	var attendees int64
+	if err := dayLogs().Distinct("action_logs.participant_id").Count(&attendees).Error; err != nil {
+		return err
+	}
+	if attendees >= int64(*day.Capacity) {
+		return ErrEventDayFull
+	}
+	return nil
 }
 
 func (r *actionRepo) HasActionLog(participantID, actionID string) (bool, error) {
@@ -27,9 +83,30 @@ func (r *actionRepo) HasActionLog(participantID, actionID string) (bool, error)
 	return count > 0, nil
 }
 
+func (r *actionRepo) CountAttendeesByDay(eventID string) (map[string]int64, error) {
+	var rows []struct {
+		EventDayID string
+		Count      int64
+	}
+	if err := r.db.Model(&models.ActionLog{}).
+		Select("event_actions.event_day_id, COUNT(DISTINCT action_logs.participant_id) AS count").
+		Joins("JOIN event_actions ON event_actions.id = action_logs.action_id").
+		Where("event_actions.event_id = ?", eventID).
+		Group("event_actions.event_day_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.EventDayID] = row.Count
+	}
+	return counts, nil
+}
+
 func (r *actionRepo) GetActionLogsByParticipant(participantID string) ([]*models.ActionLog, error) {
 	var logs []*models.ActionLog
-	if err := r.db.Preload("Action").Preload("Action.EventDay").
+	if err := r.db.Preload("Action").Preload("Action.EventDay").Preload("Verifier", withDeleted).Preload("APIKey").
 		Where("participant_id = ?", participantID).
 		Order("verified_at DESC").
 		Find(&logs).Error; err != nil {
@@ -51,7 +128,7 @@ func (r *actionRepo) GetActionLogsByEvent(eventID string, offset, limit int) ([]
 	}
 
 	// Get logs with pagination
-	if err := r.db.Preload("Participant").Preload("Action").Preload("Verifier").
+	if err := r.db.Preload("Participant").Preload("Action").Preload("Verifier", withDeleted).Preload("APIKey").
 		Joins("JOIN participants ON action_logs.participant_id = participants.id").
 		Where("participants.event_id = ?", eventID).
 		Offset(offset).Limit(limit).
@@ -62,3 +139,105 @@ func (r *actionRepo) GetActionLogsByEvent(eventID string, offset, limit int) ([]
 
 	return logs, total, nil
 }
+
+// GetActionLogsByEventAfter returns up to limit logs verified before the
+// cursor position, newest first. A nil cursor starts from the newest row.
+func (r *actionRepo) GetActionLogsByEventAfter(eventID string, cursor *Cursor, limit int) ([]*models.ActionLog, error) {
+	var logs []*models.ActionLog
+
+	query := r.db.Preload("Participant").Preload("Action").Preload("Verifier", withDeleted).Preload("APIKey").
+		Joins("JOIN participants ON action_logs.participant_id = participants.id").
+		Where("participants.event_id = ?", eventID)
+	if cursor != nil {
+		query = query.Where("(action_logs.verified_at, action_logs.id) < (?, ?)", cursor.Time, cursor.ID)
+	}
+
+	if err := query.
+		Order("action_logs.verified_at DESC, action_logs.id DESC").
+		Limit(limit).
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (r *actionRepo) CountActionLogsByParticipants(participantIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(participantIDs))
+	if len(participantIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ParticipantID string
+		Count         int64
+	}
+	if err := r.db.Model(&models.ActionLog{}).
+		Select("participant_id, COUNT(*) AS count").
+		Where("participant_id IN ?", participantIDs).
+		Group("participant_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ParticipantID] = row.Count
+	}
+	return counts, nil
+}
+
+// CountVerifiedParticipants counts the distinct participants of an event with
+// at least one verification
+func (r *actionRepo) CountVerifiedParticipants(eventID string) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.ActionLog{}).
+		Joins("JOIN participants ON action_logs.participant_id = participants.id").
+		Where("participants.event_id = ?", eventID).
+		Distinct("action_logs.participant_id").
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountActionLogsByActionIDs counts verifications of several actions in one query
+func (r *actionRepo) CountActionLogsByActionIDs(actionIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(actionIDs))
+	if len(actionIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ActionID string
+		Count    int64
+	}
+	if err := r.db.Model(&models.ActionLog{}).
+		Select("action_id, COUNT(*) AS count").
+		Where("action_id IN ?", actionIDs).
+		Group("action_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ActionID] = row.Count
+	}
+	return counts, nil
+}
+
+// CountActionLogsByVerifier counts the verifications a user made since the
+// given time, per event, most first
+func (r *actionRepo) CountActionLogsByVerifier(userID string, since time.Time) ([]VerifierEventCount, error) {
+	var counts []VerifierEventCount
+	if err := r.db.Model(&models.ActionLog{}).
+		Select("event_actions.event_id, events.title AS event_title, COUNT(*) AS count").
+		Joins("JOIN event_actions ON event_actions.id = action_logs.action_id").
+		Joins("JOIN events ON events.id = event_actions.event_id").
+		Where("action_logs.verified_by = ? AND action_logs.verified_at >= ?", userID, since).
+		Group("event_actions.event_id, events.title").
+		Order("count DESC").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
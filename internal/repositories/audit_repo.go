@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuditLogFilter narrows an audit log listing; empty fields match anything
+type AuditLogFilter struct {
+	ActorID    string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+}
+
+type AuditRepository interface {
+	CreateAuditLog(log *models.AuditLog) error
+	ListAuditLogs(filter AuditLogFilter, offset, limit int) ([]models.AuditLog, int64, error)
+}
+
+type auditRepo struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepo{db: db}
+}
+
+func (r *auditRepo) CreateAuditLog(log *models.AuditLog) error {
+	return r.db.Omit(clause.Associations).Create(log).Error
+}
+
+// ListAuditLogs returns matching entries newest first, with their actor
+func (r *auditRepo) ListAuditLogs(filter AuditLogFilter, offset, limit int) ([]models.AuditLog, int64, error) {
+	query := r.db.Model(&models.AuditLog{})
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []models.AuditLog
+	if err := query.Preload("Actor", withDeleted).
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
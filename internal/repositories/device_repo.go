@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DeviceRepository interface {
+	// RegisterDevice creates the device, or updates the one with the same
+	// identifier. device is filled with the stored row.
+	RegisterDevice(device *models.Device) error
+	GetDevice(id string) (*models.Device, error)
+	ListDevices() ([]models.Device, error)
+	TouchDevice(id string, at time.Time) error
+}
+
+type deviceRepo struct {
+	db *gorm.DB
+}
+
+func NewDeviceRepository(db *gorm.DB) DeviceRepository {
+	return &deviceRepo{db: db}
+}
+
+func (r *deviceRepo) RegisterDevice(device *models.Device) error {
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "identifier"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "platform", "registered_by", "updated_at"}),
+	}).Create(device).Error; err != nil {
+		return err
+	}
+	// On conflict the row keeps its original ID
+	return r.db.Where("identifier = ?", device.Identifier).First(device).Error
+}
+
+func (r *deviceRepo) GetDevice(id string) (*models.Device, error) {
+	var device models.Device
+	if err := r.db.Where("id = ?", id).First(&device).Error; err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+func (r *deviceRepo) ListDevices() ([]models.Device, error) {
+	var devices []models.Device
+	if err := r.db.Order("name, created_at").Find(&devices).Error; err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+	return devices, nil
+}
+
+// TouchDevice records when a device last scanned
+func (r *deviceRepo) TouchDevice(id string, at time.Time) error {
+	return r.db.Model(&models.Device{}).Where("id = ?", id).UpdateColumn("last_seen_at", at).Error
+}
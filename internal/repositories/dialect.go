@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Postgres is the primary database; SQLite (local development) and MySQL
+// are also supported. Dialect-specific SQL is kept in this file so the
+// repositories stay portable.
+
+// prepareDialect runs the per-dialect setup needed before migrating
+func prepareDialect(db *gorm.DB) error {
+	switch db.Dialector.Name() {
+	case "postgres":
+		// IDs are generated in Go, the extension is kept for existing
+		// Postgres schemas whose columns still default to it
+		return db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`).Error
+	}
+	return nil
+}
+
+// createSearchIndexes adds the indexes behind case-insensitive substring
+// searches. On Postgres, trigram indexes let LOWER(column) LIKE '%term%' use
+// an index; other databases scan.
+func createSearchIndexes(db *gorm.DB) error {
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+	for _, statement := range []string{
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm;`,
+		`CREATE INDEX IF NOT EXISTS idx_participants_name_trgm ON participants USING gin (LOWER(name) gin_trgm_ops);`,
+	} {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// concat joins SQL expressions into a string concatenation. MySQL treats
+// || as logical OR, so it needs CONCAT instead.
+func concat(db *gorm.DB, exprs ...string) string {
+	if db.Dialector.Name() == "mysql" {
+		return "CONCAT(" + strings.Join(exprs, ", ") + ")"
+	}
+	return "(" + strings.Join(exprs, " || ") + ")"
+}
+
+// containsFold returns a case-insensitive substring condition for column.
+// ILIKE is Postgres-only, LOWER ... LIKE works everywhere.
+func containsFold(column string) string {
+	return "LOWER(" + column + ") LIKE ?"
+}
+
+// jsonContains returns a condition matching rows whose JSON array column
+// holds the string bound to its placeholder. The @> operator is
+// Postgres-only; SQLite looks through json_each and MySQL has JSON_CONTAINS.
+func jsonContains(db *gorm.DB, column string) string {
+	switch db.Dialector.Name() {
+	case "sqlite":
+		return "EXISTS (SELECT 1 FROM json_each(" + column + ") WHERE json_each.value = ?)"
+	case "mysql":
+		return "JSON_CONTAINS(" + column + ", JSON_QUOTE(?))"
+	}
+	return column + " @> jsonb_build_array(?::text)"
+}
+
+// jsonText returns an expression for the field key of the JSON object in
+// column as text, with the arguments of its placeholders. Postgres has ->>;
+// MySQL needs a '$."key"' path; SQLite reads booleans as 1 and 0, so they are
+// turned back into true and false as on the other databases.
+func jsonText(db *gorm.DB, column, key string) (string, []interface{}) {
+	path := `$."` + strings.ReplaceAll(key, `"`, `\"`) + `"`
+	switch db.Dialector.Name() {
+	case "sqlite":
+		return "(CASE json_type(" + column + ", ?) WHEN 'true' THEN 'true' WHEN 'false' THEN 'false' ELSE json_extract(" + column + ", ?) END)",
+			[]interface{}{path, path}
+	case "mysql":
+		return "JSON_UNQUOTE(JSON_EXTRACT(" + column + ", ?))", []interface{}{path}
+	}
+	return "(" + column + " ->> ?)", []interface{}{key}
+}
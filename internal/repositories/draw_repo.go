@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"fmt"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DrawRepository interface {
+	GetEligibleParticipantIDs(eventID string, criteria models.DrawCriteria) ([]uuid.UUID, error)
+	NextDrawRound(eventID string) (int, error)
+	CreateDraw(draw *models.Draw) error
+	ListDrawsByEvent(eventID string) ([]models.Draw, error)
+}
+
+type drawRepo struct {
+	db *gorm.DB
+}
+
+func NewDrawRepository(db *gorm.DB) DrawRepository {
+	return &drawRepo{db: db}
+}
+
+// GetEligibleParticipantIDs returns the IDs of participants matching criteria
+// ordered by ID, the order the draw shuffle starts from
+func (r *drawRepo) GetEligibleParticipantIDs(eventID string, criteria models.DrawCriteria) ([]uuid.UUID, error) {
+	query := r.db.Model(&models.Participant{}).Where("participants.event_id = ?", eventID)
+
+	if criteria.PaidOnly {
+		query = query.Where("participants.payment_status = ?", "paid")
+	}
+	if criteria.CheckedInSince != nil {
+		checkedIn := r.db.Model(&models.ActionLog{}).
+			Select("1").
+			Where("action_logs.participant_id = participants.id AND action_logs.verified_at >= ?", *criteria.CheckedInSince)
+		if criteria.ActionID != "" {
+			checkedIn = checkedIn.Where("action_logs.action_id = ?", criteria.ActionID)
+		}
+		query = query.Where("EXISTS (?)", checkedIn)
+	}
+	if criteria.ExcludePreviousWinners {
+		winners := r.db.Model(&models.DrawWinner{}).Select("participant_id").Where("event_id = ?", eventID)
+		if criteria.BeforeRound > 0 {
+			winners = winners.Where("draw_id IN (?)",
+				r.db.Model(&models.Draw{}).Select("id").Where("event_id = ? AND round < ?", eventID, criteria.BeforeRound))
+		}
+		query = query.Where("participants.id NOT IN (?)", winners)
+	}
+
+	var ids []uuid.UUID
+	if err := query.Order("participants.id ASC").Pluck("participants.id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to get eligible participants: %w", err)
+	}
+
+	return ids, nil
+}
+
+// NextDrawRound returns the round number of the next draw of an event
+func (r *drawRepo) NextDrawRound(eventID string) (int, error) {
+	var last int
+	if err := r.db.Model(&models.Draw{}).
+		Where("event_id = ?", eventID).
+		Select("COALESCE(MAX(round), 0)").
+		Scan(&last).Error; err != nil {
+		return 0, fmt.Errorf("failed to get draw round: %w", err)
+	}
+
+	return last + 1, nil
+}
+
+// CreateDraw stores a draw and its winners. The unique (event_id, round)
+// index rejects a concurrent draw that picked the same round.
+func (r *drawRepo) CreateDraw(draw *models.Draw) error {
+	if err := r.db.Create(draw).Error; err != nil {
+		return fmt.Errorf("failed to create draw: %w", err)
+	}
+
+	return nil
+}
+
+// ListDrawsByEvent returns the draws of an event by round, with winners
+func (r *drawRepo) ListDrawsByEvent(eventID string) ([]models.Draw, error) {
+	var draws []models.Draw
+	if err := r.db.
+		Preload("Winners", func(db *gorm.DB) *gorm.DB {
+			return db.Order("draw_winners.position ASC")
+		}).
+		Preload("Winners.Participant").
+		Where("event_id = ?", eventID).
+		Order("round ASC").
+		Find(&draws).Error; err != nil {
+		return nil, fmt.Errorf("failed to list draws: %w", err)
+	}
+
+	return draws, nil
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"fmt"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EventImageRepository interface {
+	// CreateEventImage appends an image to the end of its event's gallery.
+	// A banner image replaces the event's previous banner.
+	CreateEventImage(image *models.EventImage) error
+	GetEventImage(id string) (*models.EventImage, error)
+	// ListEventImages returns the gallery of an event in display order
+	ListEventImages(eventID string) ([]models.EventImage, error)
+	// ReorderEventImages sets the gallery order to the given image IDs
+	ReorderEventImages(eventID string, imageIDs []uuid.UUID) error
+	// SetBannerImage makes an image the only banner of its event
+	SetBannerImage(eventID, imageID string) error
+	DeleteEventImage(id string) error
+}
+
+type eventImageRepo struct {
+	db *gorm.DB
+}
+
+func NewEventImageRepository(db *gorm.DB) EventImageRepository {
+	return &eventImageRepo{db: db}
+}
+
+func (r *eventImageRepo) CreateEventImage(image *models.EventImage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var position int
+		if err := tx.Model(&models.EventImage{}).
+			Where("event_id = ?", image.EventID).
+			Select("COALESCE(MAX(position) + 1, 0)").
+			Scan(&position).Error; err != nil {
+			return fmt.Errorf("failed to position image: %w", err)
+		}
+		image.Position = position
+
+		if image.IsBanner {
+			if err := tx.Model(&models.EventImage{}).
+				Where("event_id = ? AND is_banner = ?", image.EventID, true).
+				Update("is_banner", false).Error; err != nil {
+				return fmt.Errorf("failed to clear banner: %w", err)
+			}
+		}
+		return tx.Create(image).Error
+	})
+}
+
+func (r *eventImageRepo) GetEventImage(id string) (*models.EventImage, error) {
+	var image models.EventImage
+	if err := r.db.Where("id = ?", id).First(&image).Error; err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *eventImageRepo) ListEventImages(eventID string) ([]models.EventImage, error) {
+	var images []models.EventImage
+	if err := r.db.Where("event_id = ?", eventID).Order("position ASC, created_at ASC").Find(&images).Error; err != nil {
+		return nil, fmt.Errorf("failed to list event images: %w", err)
+	}
+	return images, nil
+}
+
+func (r *eventImageRepo) ReorderEventImages(eventID string, imageIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for position, id := range imageIDs {
+			result := tx.Model(&models.EventImage{}).
+				Where("id = ? AND event_id = ?", id, eventID).
+				Update("position", position)
+			if result.Error != nil {
+				return fmt.Errorf("failed to reorder images: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return nil
+	})
+}
+
+func (r *eventImageRepo) SetBannerImage(eventID, imageID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.EventImage{}).
+			Where("event_id = ? AND is_banner = ?", eventID, true).
+			Update("is_banner", false).Error; err != nil {
+			return fmt.Errorf("failed to clear banner: %w", err)
+		}
+		result := tx.Model(&models.EventImage{}).
+			Where("id = ? AND event_id = ?", imageID, eventID).
+			Update("is_banner", true)
+		if result.Error != nil {
+			return fmt.Errorf("failed to set banner: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *eventImageRepo) DeleteEventImage(id string) error {
+	result := r.db.Where("id = ?", id).Delete(&models.EventImage{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
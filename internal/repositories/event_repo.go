@@ -1,13 +1,17 @@
 package repositories
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"event-management-backend/internal/models"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EventRepository interface {
@@ -16,13 +20,30 @@ type EventRepository interface {
 	GetEventBySlug(slug string) (*models.Event, error)
 	ListEvents(offset, limit int, filters *EventFilters) ([]models.Event, int64, error)
 	UpdateEvent(event *models.Event) error
+	// CancelEvent saves the status of a cancelled event and cancels its live
+	// registrations in the same transaction
+	CancelEvent(event *models.Event, reason string, refund bool) (*CancellationCounts, error)
 	SoftDeleteEvent(id string) error
+	HasWidgetOrigin(origin string) (bool, error)
+	UpdateLogoVariants(id string, variants map[string]string) error
 	GetEventWithDays(id string) (*models.Event, error)
+	GetEventWithDaysBySlug(slug string) (*models.Event, error)
+	GetEventByKioskTokenHash(hash string) (*models.Event, error)
+	UpdateKioskSettings(id, tokenHash, message string) error
+	UpdateWidgetKey(id, key string) error
+	UpdateLiveStatsPublic(id string, public bool) error
+	// ImportEvent creates an event with its days, actions and sessions, and
+	// optionally participants and their action logs, in one transaction
+	ImportEvent(event *models.Event, sessions []models.Session, participants []models.Participant, logs []models.ActionLog) error
 
 	// Event Days
 	CreateEventDay(day *models.EventDay) error
+	// CreateEventDays appends days to an event in one transaction, numbering
+	// them after its last day. Labels left empty become "Day <number>".
+	CreateEventDays(eventID string, days []models.EventDay) error
 	GetEventDayByID(id string) (*models.EventDay, error)
 	GetEventDaysByEventID(eventID string) ([]models.EventDay, error)
+	GetEventDaysByEventIDs(eventIDs []string) ([]models.EventDay, error)
 	UpdateEventDay(day *models.EventDay) error
 	DeleteEventDay(id string) error
 
@@ -32,17 +53,30 @@ type EventRepository interface {
 	GetEventActionByCode(code string) (*models.EventAction, error)
 	GetEventActionsByDayID(dayID string) ([]models.EventAction, error)
 	GetEventActionsByEventID(eventID string) ([]models.EventAction, error)
+	GetEventActionsByDayIDs(dayIDs []string) ([]models.EventAction, error)
 	UpdateEventAction(action *models.EventAction) error
 	DeleteEventAction(id string) error
+	// ReorderEventActions sets the display order of a day's actions to the
+	// given action IDs
+	ReorderEventActions(dayID string, actionIDs []uuid.UUID) error
 }
 
 type EventFilters struct {
 	IsActive    *bool
+	Status      string
+	Visibility  string
 	StartsAfter *time.Time
 	EndsBefore  *time.Time
 	Search      string
 }
 
+// CancellationCounts is what cancelling an event did to its registrations
+type CancellationCounts struct {
+	Cancelled int64
+	// Paid registrations moved to refund_pending
+	Refunds int64
+}
+
 type eventRepo struct {
 	db *gorm.DB
 }
@@ -73,7 +107,7 @@ func (r *eventRepo) GetEventByID(id string) (*models.Event, error) {
 	}
 
 	var event models.Event
-	if err := r.db.Where("id = ?", id).First(&event).Error; err != nil {
+	if err := r.db.Preload("Venue").Preload("Banner", "is_banner = ?", true).Where("id = ?", id).First(&event).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("event not found with ID: %s", id)
 		}
@@ -90,7 +124,7 @@ func (r *eventRepo) GetEventBySlug(slug string) (*models.Event, error) {
 	}
 
 	var event models.Event
-	if err := r.db.Where("slug = ?", slug).First(&event).Error; err != nil {
+	if err := r.db.Preload("Venue").Preload("Banner", "is_banner = ?", true).Where("slug = ?", slug).First(&event).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("event not found with slug: %s", slug)
 		}
@@ -108,12 +142,7 @@ func (r *eventRepo) GetEventWithDays(id string) (*models.Event, error) {
 
 	var event models.Event
 	if err := r.db.
-		Preload("EventDays", func(db *gorm.DB) *gorm.DB {
-			return db.Order("event_days.day_number ASC")
-		}).
-		Preload("EventDays.EventActions", func(db *gorm.DB) *gorm.DB {
-			return db.Order("event_actions.name ASC")
-		}).
+		Scopes(withDays).
 		Where("id = ?", id).
 		First(&event).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -125,6 +154,42 @@ func (r *eventRepo) GetEventWithDays(id string) (*models.Event, error) {
 	return &event, nil
 }
 
+// GetEventWithDaysBySlug retrieves an event by slug with its associated days
+// and actions
+func (r *eventRepo) GetEventWithDaysBySlug(slug string) (*models.Event, error) {
+	if slug == "" {
+		return nil, errors.New("event slug cannot be empty")
+	}
+
+	var event models.Event
+	if err := r.db.
+		Scopes(withDays).
+		Where("slug = ?", slug).
+		First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("event not found with slug: %s", slug)
+		}
+		return nil, fmt.Errorf("failed to get event with days: %w", err)
+	}
+
+	return &event, nil
+}
+
+// withDays preloads the venue, banner, days and actions of events, in
+// display order
+func withDays(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Venue").
+		Preload("Banner", "is_banner = ?", true).
+		Preload("EventDays", func(db *gorm.DB) *gorm.DB {
+			return db.Order("event_days.day_number ASC")
+		}).
+		Preload("EventDays.Venue").
+		Preload("EventDays.EventActions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("event_actions.position ASC, event_actions.name ASC")
+		})
+}
+
 // ListEvents retrieves a paginated list of events with optional filters
 func (r *eventRepo) ListEvents(offset, limit int, filters *EventFilters) ([]models.Event, int64, error) {
 	if offset < 0 {
@@ -144,6 +209,12 @@ func (r *eventRepo) ListEvents(offset, limit int, filters *EventFilters) ([]mode
 		if filters.IsActive != nil {
 			query = query.Where("is_active = ?", *filters.IsActive)
 		}
+		if filters.Status != "" {
+			query = query.Where("status = ?", filters.Status)
+		}
+		if filters.Visibility != "" {
+			query = query.Where("visibility = ?", filters.Visibility)
+		}
 		if filters.StartsAfter != nil {
 			query = query.Where("starts_at >= ?", *filters.StartsAfter)
 		}
@@ -151,8 +222,8 @@ func (r *eventRepo) ListEvents(offset, limit int, filters *EventFilters) ([]mode
 			query = query.Where("ends_at <= ?", *filters.EndsBefore)
 		}
 		if filters.Search != "" {
-			searchTerm := "%" + filters.Search + "%"
-			query = query.Where("title ILIKE ? OR description ILIKE ?", searchTerm, searchTerm)
+			searchTerm := "%" + strings.ToLower(filters.Search) + "%"
+			query = query.Where(containsFold("title")+" OR "+containsFold("description"), searchTerm, searchTerm)
 		}
 	}
 
@@ -163,7 +234,10 @@ func (r *eventRepo) ListEvents(offset, limit int, filters *EventFilters) ([]mode
 
 	// Fetch paginated results
 	if err := query.
+		Preload("Venue").
+		Preload("Banner", "is_banner = ?", true).
 		Preload("EventDays").
+		Preload("EventDays.Venue").
 		Offset(offset).
 		Limit(limit).
 		Order("created_at DESC").
@@ -197,7 +271,228 @@ func (r *eventRepo) UpdateEvent(event *models.Event) error {
 		}
 	}
 
-	return r.db.Save(event).Error
+	// Only write when the stored version still matches the one the caller read
+	expected := event.Version
+	event.Version = expected + 1
+	result := r.db.Model(event).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Where("version = ?", expected).
+		Updates(event)
+	if result.Error != nil {
+		event.Version = expected
+		return fmt.Errorf("failed to update event: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		event.Version = expected
+		return ErrVersionConflict
+	}
+
+	return nil
+}
+
+// CancelEvent writes the status of event and cancels every registration of
+// it not cancelled yet. With refund, paid registrations move to
+// refund_pending. It fails with ErrVersionConflict when the event changed
+// since it was read.
+func (r *eventRepo) CancelEvent(event *models.Event, reason string, refund bool) (*CancellationCounts, error) {
+	counts := &CancellationCounts{}
+	expected := event.Version
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Event{}).
+			Where("id = ? AND version = ?", event.ID, expected).
+			Updates(map[string]interface{}{"status": event.Status, "version": expected + 1})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+
+		live := func() *gorm.DB {
+			return tx.Model(&models.Participant{}).Where("event_id = ? AND cancelled_at IS NULL", event.ID)
+		}
+		updates := map[string]interface{}{
+			"cancelled_at":        time.Now(),
+			"cancellation_reason": reason,
+			"version":             gorm.Expr("version + 1"),
+		}
+		if refund {
+			if err := live().Where("payment_status = ?", "paid").Count(&counts.Refunds).Error; err != nil {
+				return err
+			}
+			updates["payment_status"] = gorm.Expr("CASE WHEN payment_status = ? THEN ? ELSE payment_status END", "paid", "refund_pending")
+		}
+
+		result = live().Updates(updates)
+		counts.Cancelled = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	event.Version = expected + 1
+	return counts, nil
+}
+
+// HasWidgetOrigin reports whether any active event lists origin among its
+// widget origins
+func (r *eventRepo) HasWidgetOrigin(origin string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Event{}).
+		Where("is_active = ? AND "+concat(r.db, "','", "widget_origins", "','")+" LIKE ?", true, "%,"+origin+",%").
+		Limit(1).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check widget origin: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// UpdateLogoVariants stores the generated logo variants. The version is left
+// untouched since this is not a user edit.
+func (r *eventRepo) UpdateLogoVariants(id string, variants map[string]string) error {
+	data, err := json.Marshal(variants)
+	if err != nil {
+		return fmt.Errorf("failed to encode logo variants: %w", err)
+	}
+
+	if err := r.db.Model(&models.Event{}).
+		Where("id = ?", id).
+		UpdateColumn("logo_variants", string(data)).Error; err != nil {
+		return fmt.Errorf("failed to update logo variants: %w", err)
+	}
+
+	return nil
+}
+
+// GetEventByKioskTokenHash retrieves the active event a kiosk token belongs to
+func (r *eventRepo) GetEventByKioskTokenHash(hash string) (*models.Event, error) {
+	if hash == "" {
+		return nil, errors.New("kiosk token cannot be empty")
+	}
+
+	var event models.Event
+	if err := r.db.Where("kiosk_token_hash = ? AND is_active = ?", hash, true).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	return &event, nil
+}
+
+// UpdateKioskSettings replaces the kiosk token hash and welcome message. Like
+// logo variants these are display settings, so the version is left untouched.
+func (r *eventRepo) UpdateKioskSettings(id, tokenHash, message string) error {
+	result := r.db.Model(&models.Event{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"kiosk_token_hash": tokenHash,
+			"kiosk_message":    message,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update kiosk settings: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("event not found with ID: %s", id)
+	}
+
+	return nil
+}
+
+// UpdateWidgetKey replaces the public key of the registration widget
+func (r *eventRepo) UpdateWidgetKey(id, key string) error {
+	result := r.db.Model(&models.Event{}).
+		Where("id = ?", id).
+		UpdateColumn("widget_key", key)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update widget key: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("event not found with ID: %s", id)
+	}
+
+	return nil
+}
+
+// UpdateLiveStatsPublic opts an event in or out of the public live stats page
+func (r *eventRepo) UpdateLiveStatsPublic(id string, public bool) error {
+	result := r.db.Model(&models.Event{}).
+		Where("id = ?", id).
+		UpdateColumn("live_stats_public", public)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update live stats setting: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("event not found with ID: %s", id)
+	}
+
+	return nil
+}
+
+// ImportEvent creates an event with its days, their actions and the given
+// sessions in one transaction. All IDs must already be set.
+func (r *eventRepo) ImportEvent(event *models.Event, sessions []models.Session, participants []models.Participant, logs []models.ActionLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existingEvent models.Event
+		if err := tx.Where("slug = ?", event.Slug).First(&existingEvent).Error; err == nil {
+			return fmt.Errorf("event with slug '%s' already exists", event.Slug)
+		}
+
+		var codes []string
+		for _, day := range event.EventDays {
+			for _, action := range day.EventActions {
+				codes = append(codes, action.Code)
+			}
+		}
+		if len(codes) > 0 {
+			var taken []string
+			if err := tx.Model(&models.EventAction{}).Where("code IN ?", codes).Pluck("code", &taken).Error; err != nil {
+				return fmt.Errorf("failed to check action codes: %w", err)
+			}
+			if len(taken) > 0 {
+				return fmt.Errorf("event action codes already exist: %s", strings.Join(taken, ", "))
+			}
+		}
+
+		if err := tx.Omit(clause.Associations).Create(event).Error; err != nil {
+			return fmt.Errorf("failed to create event: %w", err)
+		}
+		for _, day := range event.EventDays {
+			if err := tx.Omit(clause.Associations).Create(&day).Error; err != nil {
+				return fmt.Errorf("failed to create event day: %w", err)
+			}
+			if len(day.EventActions) == 0 {
+				continue
+			}
+			if err := tx.Create(&day.EventActions).Error; err != nil {
+				return fmt.Errorf("failed to create event actions: %w", err)
+			}
+		}
+		if len(sessions) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&sessions).Error; err != nil {
+				return fmt.Errorf("failed to create sessions: %w", err)
+			}
+		}
+		if len(participants) > 0 {
+			if err := tx.Omit(clause.Associations).CreateInBatches(&participants, 500).Error; err != nil {
+				return fmt.Errorf("failed to create participants: %w", err)
+			}
+		}
+		if len(logs) > 0 {
+			if err := tx.Omit(clause.Associations).CreateInBatches(&logs, 500).Error; err != nil {
+				return fmt.Errorf("failed to create action logs: %w", err)
+			}
+		}
+
+		return nil
+	})
 }
 
 // SoftDeleteEvent soft deletes an event by setting is_active to false
@@ -245,6 +540,40 @@ func (r *eventRepo) CreateEventDay(day *models.EventDay) error {
 	return r.db.Create(day).Error
 }
 
+// CreateEventDays creates event days after the last day of the event
+func (r *eventRepo) CreateEventDays(eventID string, days []models.EventDay) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Locking the event serializes concurrent appends to its days
+		var event models.Event
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", eventID).First(&event).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("event not found with ID: %s", eventID)
+			}
+			return fmt.Errorf("failed to check event existence: %w", err)
+		}
+
+		var last int
+		if err := tx.Model(&models.EventDay{}).
+			Where("event_id = ?", eventID).
+			Select("COALESCE(MAX(day_number), 0)").
+			Scan(&last).Error; err != nil {
+			return fmt.Errorf("failed to number event days: %w", err)
+		}
+
+		for i := range days {
+			days[i].EventID = event.ID
+			days[i].DayNumber = last + i + 1
+			if days[i].Label == "" {
+				days[i].Label = fmt.Sprintf("Day %d", days[i].DayNumber)
+			}
+		}
+		if err := tx.Omit(clause.Associations).Create(&days).Error; err != nil {
+			return fmt.Errorf("failed to create event days: %w", err)
+		}
+		return nil
+	})
+}
+
 // GetEventDayByID retrieves an event day by its ID
 func (r *eventRepo) GetEventDayByID(id string) (*models.EventDay, error) {
 	if id == "" {
@@ -253,7 +582,9 @@ func (r *eventRepo) GetEventDayByID(id string) (*models.EventDay, error) {
 
 	var day models.EventDay
 	if err := r.db.
-		Preload("EventActions").
+		Preload("EventActions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC, name ASC")
+		}).
 		Where("id = ?", id).
 		First(&day).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -273,7 +604,9 @@ func (r *eventRepo) GetEventDaysByEventID(eventID string) ([]models.EventDay, er
 
 	var days []models.EventDay
 	if err := r.db.
-		Preload("EventActions").
+		Preload("EventActions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC, name ASC")
+		}).
 		Where("event_id = ?", eventID).
 		Order("day_number ASC").
 		Find(&days).Error; err != nil {
@@ -283,6 +616,23 @@ func (r *eventRepo) GetEventDaysByEventID(eventID string) ([]models.EventDay, er
 	return days, nil
 }
 
+// GetEventDaysByEventIDs retrieves the days of several events in one query
+func (r *eventRepo) GetEventDaysByEventIDs(eventIDs []string) ([]models.EventDay, error) {
+	var days []models.EventDay
+	if len(eventIDs) == 0 {
+		return days, nil
+	}
+
+	if err := r.db.
+		Where("event_id IN ?", eventIDs).
+		Order("day_number ASC").
+		Find(&days).Error; err != nil {
+		return nil, fmt.Errorf("failed to get event days: %w", err)
+	}
+
+	return days, nil
+}
+
 // UpdateEventDay updates an existing event day
 func (r *eventRepo) UpdateEventDay(day *models.EventDay) error {
 	if day == nil {
@@ -370,7 +720,6 @@ func (r *eventRepo) GetEventActionByID(id string) (*models.EventAction, error) {
 
 	var action models.EventAction
 	if err := r.db.
-		Preload("EventDay").
 		Where("id = ?", id).
 		First(&action).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -390,11 +739,10 @@ func (r *eventRepo) GetEventActionByCode(code string) (*models.EventAction, erro
 
 	var action models.EventAction
 	if err := r.db.
-		Preload("EventDay").
 		Where("code = ? AND is_active = ?", code, true).
 		First(&action).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("event action not found with code: %s", code)
+			return nil, fmt.Errorf("event action not found with code: %s: %w", code, err)
 		}
 		return nil, fmt.Errorf("failed to get event action: %w", err)
 	}
@@ -411,7 +759,7 @@ func (r *eventRepo) GetEventActionsByDayID(dayID string) ([]models.EventAction,
 	var actions []models.EventAction
 	if err := r.db.
 		Where("event_day_id = ? AND is_active = ?", dayID, true).
-		Order("name ASC").
+		Order("position ASC, name ASC").
 		Find(&actions).Error; err != nil {
 		return nil, fmt.Errorf("failed to get event actions: %w", err)
 	}
@@ -429,7 +777,24 @@ func (r *eventRepo) GetEventActionsByEventID(eventID string) ([]models.EventActi
 	if err := r.db.
 		Joins("JOIN event_days ON event_actions.event_day_id = event_days.id").
 		Where("event_days.event_id = ? AND event_actions.is_active = ?", eventID, true).
-		Order("event_days.day_number ASC, event_actions.name ASC").
+		Order("event_days.day_number ASC, event_actions.position ASC, event_actions.name ASC").
+		Find(&actions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get event actions: %w", err)
+	}
+
+	return actions, nil
+}
+
+// GetEventActionsByDayIDs retrieves the active actions of several event days in one query
+func (r *eventRepo) GetEventActionsByDayIDs(dayIDs []string) ([]models.EventAction, error) {
+	var actions []models.EventAction
+	if len(dayIDs) == 0 {
+		return actions, nil
+	}
+
+	if err := r.db.
+		Where("event_day_id IN ? AND is_active = ?", dayIDs, true).
+		Order("position ASC, name ASC").
 		Find(&actions).Error; err != nil {
 		return nil, fmt.Errorf("failed to get event actions: %w", err)
 	}
@@ -463,6 +828,24 @@ func (r *eventRepo) UpdateEventAction(action *models.EventAction) error {
 	return r.db.Save(action).Error
 }
 
+// ReorderEventActions numbers the actions of a day in the given order
+func (r *eventRepo) ReorderEventActions(dayID string, actionIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for position, id := range actionIDs {
+			result := tx.Model(&models.EventAction{}).
+				Where("id = ? AND event_day_id = ?", id, dayID).
+				Update("position", position)
+			if result.Error != nil {
+				return fmt.Errorf("failed to reorder event actions: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("event action not found with ID: %s", id)
+			}
+		}
+		return nil
+	})
+}
+
 // DeleteEventAction soft deletes an event action by setting is_active to false
 func (r *eventRepo) DeleteEventAction(id string) error {
 	if id == "" {
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"fmt"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type EventRevisionRepository interface {
+	CreateEventRevision(revision *models.EventRevision) error
+	// ListEventRevisions returns a page of the revisions of an event, newest
+	// first, with their total
+	ListEventRevisions(eventID string, offset, limit int) ([]models.EventRevision, int64, error)
+}
+
+type eventRevisionRepo struct {
+	db *gorm.DB
+}
+
+func NewEventRevisionRepository(db *gorm.DB) EventRevisionRepository {
+	return &eventRevisionRepo{db: db}
+}
+
+func (r *eventRevisionRepo) CreateEventRevision(revision *models.EventRevision) error {
+	return r.db.Create(revision).Error
+}
+
+func (r *eventRevisionRepo) ListEventRevisions(eventID string, offset, limit int) ([]models.EventRevision, int64, error) {
+	query := r.db.Model(&models.EventRevision{}).Where("event_id = ?", eventID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count event revisions: %w", err)
+	}
+
+	var revisions []models.EventRevision
+	if err := query.Order("created_at DESC, version DESC").Offset(offset).Limit(limit).Find(&revisions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list event revisions: %w", err)
+	}
+	return revisions, total, nil
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EventStaffRepository interface {
+	AssignEventStaff(assignment *models.EventStaff) error
+	UnassignEventStaff(eventID, userID string) error
+	ListEventStaff(eventID string) ([]models.EventStaff, error)
+	ListStaffEventIDs(userID string) ([]uuid.UUID, error)
+	IsEventStaff(eventID, userID string) (bool, error)
+}
+
+type eventStaffRepo struct {
+	db *gorm.DB
+}
+
+func NewEventStaffRepository(db *gorm.DB) EventStaffRepository {
+	return &eventStaffRepo{db: db}
+}
+
+// AssignEventStaff adds a user to an event's staff; assigning twice is a
+// no-op
+func (r *eventStaffRepo) AssignEventStaff(assignment *models.EventStaff) error {
+	return r.db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(assignment).Error
+}
+
+func (r *eventStaffRepo) UnassignEventStaff(eventID, userID string) error {
+	result := r.db.Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&models.EventStaff{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *eventStaffRepo) ListEventStaff(eventID string) ([]models.EventStaff, error) {
+	var staff []models.EventStaff
+	err := r.db.Preload("User").
+		Where("event_id = ?", eventID).
+		Order("created_at").
+		Find(&staff).Error
+	return staff, err
+}
+
+func (r *eventStaffRepo) ListStaffEventIDs(userID string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&models.EventStaff{}).
+		Where("user_id = ?", userID).
+		Pluck("event_id", &ids).Error
+	return ids, err
+}
+
+func (r *eventStaffRepo) IsEventStaff(eventID, userID string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.EventStaff{}).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository interface {
+	GetByKey(key string) (*models.IdempotencyKey, error)
+	Create(record *models.IdempotencyKey) error
+	SaveResponse(key string, statusCode int, body []byte, contentType string) error
+	Delete(key string) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type idempotencyRepo struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepo{db: db}
+}
+
+// byKey matches the record of key. key is a reserved word on MySQL, so the
+// column is left to GORM to quote.
+func byKey(key string) clause.Eq {
+	return clause.Eq{Column: clause.Column{Name: "key"}, Value: key}
+}
+
+func (r *idempotencyRepo) GetByKey(key string) (*models.IdempotencyKey, error) {
+	var record models.IdempotencyKey
+	if err := r.db.Where(byKey(key)).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Create inserts the in-flight marker for a key. The unique index on key makes
+// concurrent duplicates fail here instead of executing twice.
+func (r *idempotencyRepo) Create(record *models.IdempotencyKey) error {
+	if record == nil {
+		return errors.New("idempotency record cannot be nil")
+	}
+	return r.db.Create(record).Error
+}
+
+func (r *idempotencyRepo) SaveResponse(key string, statusCode int, body []byte, contentType string) error {
+	return r.db.Model(&models.IdempotencyKey{}).
+		Where(byKey(key)).
+		Updates(map[string]interface{}{
+			"status_code":   statusCode,
+			"response_body": body,
+			"content_type":  contentType,
+		}).Error
+}
+
+func (r *idempotencyRepo) Delete(key string) error {
+	return r.db.Where(byKey(key)).Delete(&models.IdempotencyKey{}).Error
+}
+
+func (r *idempotencyRepo) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", now).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ImportJobRepository interface {
+	CreateImportJob(job *models.ImportJob) error
+	GetImportJob(id string) (*models.ImportJob, error)
+	// UpdateImportJob saves the progress of an import
+	UpdateImportJob(job *models.ImportJob) error
+}
+
+type importJobRepo struct {
+	db *gorm.DB
+}
+
+func NewImportJobRepository(db *gorm.DB) ImportJobRepository {
+	return &importJobRepo{db: db}
+}
+
+func (r *importJobRepo) CreateImportJob(job *models.ImportJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *importJobRepo) GetImportJob(id string) (*models.ImportJob, error) {
+	var job models.ImportJob
+	if err := r.db.Where("id = ?", id).First(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *importJobRepo) UpdateImportJob(job *models.ImportJob) error {
+	result := r.db.Model(job).Select("*").Omit("id", "event_id", "created_by", "created_at").Updates(job)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepository interface {
+	CreateJob(job *models.Job) error
+	ClaimNextJob(now time.Time) (*models.Job, error)
+	MarkJobCompleted(id string) error
+	MarkJobFailed(id, lastError string, retryAt *time.Time) error
+	ListJobs(offset, limit int, status string) ([]models.Job, int64, error)
+	ResetStaleJobs(olderThan time.Time) (int64, error)
+}
+
+type jobRepo struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepo{db: db}
+}
+
+// CreateJob persists a new job
+func (r *jobRepo) CreateJob(job *models.Job) error {
+	if job == nil {
+		return errors.New("job cannot be nil")
+	}
+
+	return r.db.Create(job).Error
+}
+
+// ClaimNextJob locks the oldest due job and marks it as running. Returns nil
+// without error when no job is due. SKIP LOCKED lets several workers (or
+// server instances) poll the same table without handing out a job twice.
+func (r *jobRepo) ClaimNextJob(now time.Time) (*models.Job, error) {
+	var claimed *models.Job
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var job models.Job
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_at <= ?", "pending", now).
+			Order("run_at ASC").
+			First(&job).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		job.Status = "running"
+		job.Attempts++
+		job.StartedAt = &now
+		if err := tx.Model(&job).Updates(map[string]interface{}{
+			"status":     job.Status,
+			"attempts":   job.Attempts,
+			"started_at": job.StartedAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		claimed = &job
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim job: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// MarkJobCompleted marks a job as successfully processed
+func (r *jobRepo) MarkJobCompleted(id string) error {
+	now := time.Now()
+	return r.db.Model(&models.Job{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":      "completed",
+			"finished_at": &now,
+			"last_error":  "",
+		}).Error
+}
+
+// MarkJobFailed records a failed attempt. A non-nil retryAt puts the job back
+// in the pending state, otherwise it is marked as permanently failed.
+func (r *jobRepo) MarkJobFailed(id, lastError string, retryAt *time.Time) error {
+	updates := map[string]interface{}{
+		"last_error": lastError,
+	}
+
+	if retryAt != nil {
+		updates["status"] = "pending"
+		updates["run_at"] = *retryAt
+	} else {
+		now := time.Now()
+		updates["status"] = "failed"
+		updates["finished_at"] = &now
+	}
+
+	return r.db.Model(&models.Job{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// ListJobs retrieves a paginated list of jobs, optionally filtered by status
+func (r *jobRepo) ListJobs(offset, limit int, status string) ([]models.Job, int64, error) {
+	var jobs []models.Job
+	var total int64
+
+	query := r.db.Model(&models.Job{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count jobs: %w", err)
+	}
+
+	if err := query.
+		Offset(offset).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&jobs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	return jobs, total, nil
+}
+
+// ResetStaleJobs returns jobs stuck in the running state (e.g. after a crash)
+// to the pending queue
+func (r *jobRepo) ResetStaleJobs(olderThan time.Time) (int64, error) {
+	result := r.db.Model(&models.Job{}).
+		Where("status = ? AND started_at < ?", "running", olderThan).
+		Updates(map[string]interface{}{
+			"status": "pending",
+			"run_at": time.Now(),
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to reset stale jobs: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
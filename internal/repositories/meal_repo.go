@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrMealQuotaReached is returned when every meal of a coupon has been served
+var ErrMealQuotaReached = errors.New("meal quota reached")
+
+type MealRepository interface {
+	CreateMealCoupons(coupons []models.MealCoupon) error
+	GetMealCouponByActionID(actionID string) (*models.MealCoupon, error)
+	ListMealCouponsByEvent(eventID string) ([]models.MealCoupon, error)
+	CreateMealRedemption(coupon *models.MealCoupon, log *models.ActionLog) error
+	CountMealRedemptions(eventID string) (map[uuid.UUID]int64, error)
+	CountMealPreferences(eventID string) (map[string]int64, error)
+}
+
+type mealRepo struct {
+	db *gorm.DB
+}
+
+func NewMealRepository(db *gorm.DB) MealRepository {
+	return &mealRepo{db: db}
+}
+
+// CreateMealCoupons creates coupons together with the actions used to scan
+// them. Each coupon's Action must be set; all are created or none.
+func (r *mealRepo) CreateMealCoupons(coupons []models.MealCoupon) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range coupons {
+			action := &coupons[i].Action
+
+			var existing models.EventAction
+			if err := tx.Where("code = ?", action.Code).First(&existing).Error; err == nil {
+				return fmt.Errorf("event action with code '%s' already exists", action.Code)
+			}
+
+			if err := tx.Create(action).Error; err != nil {
+				return fmt.Errorf("failed to create meal action: %w", err)
+			}
+
+			coupons[i].ActionID = action.ID
+			if err := tx.Omit(clause.Associations).Create(&coupons[i]).Error; err != nil {
+				return fmt.Errorf("failed to create meal coupon: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *mealRepo) GetMealCouponByActionID(actionID string) (*models.MealCoupon, error) {
+	var coupon models.MealCoupon
+	if err := r.db.Where("action_id = ?", actionID).First(&coupon).Error; err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+func (r *mealRepo) ListMealCouponsByEvent(eventID string) ([]models.MealCoupon, error) {
+	var coupons []models.MealCoupon
+	if err := r.db.Preload("Action").
+		Where("event_id = ?", eventID).
+		Order("created_at ASC, preference ASC").
+		Find(&coupons).Error; err != nil {
+		return nil, fmt.Errorf("failed to list meal coupons: %w", err)
+	}
+	return coupons, nil
+}
+
+// CreateMealRedemption records a meal scan, failing with ErrMealQuotaReached
+// once the quota is used up. The coupon row is locked so several catering
+// counters cannot serve more meals than were prepared.
+func (r *mealRepo) CreateMealRedemption(coupon *models.MealCoupon, log *models.ActionLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var locked models.MealCoupon
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", coupon.ID).
+			First(&locked).Error; err != nil {
+			return err
+		}
+
+		if locked.Quota != nil {
+			var served int64
+			if err := tx.Model(&models.ActionLog{}).
+				Where("action_id = ?", locked.ActionID).
+				Count(&served).Error; err != nil {
+				return err
+			}
+			if served >= int64(*locked.Quota) {
+				return ErrMealQuotaReached
+			}
+		}
+		if err := checkDayCapacity(tx, log); err != nil {
+			return err
+		}
+
+		return tx.Omit(clause.Associations).Create(log).Error
+	})
+}
+
+// CountMealRedemptions returns the meals served per coupon of an event
+func (r *mealRepo) CountMealRedemptions(eventID string) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		CouponID uuid.UUID
+		Served   int64
+	}
+	if err := r.db.Model(&models.MealCoupon{}).
+		Select("meal_coupons.id AS coupon_id, COUNT(action_logs.id) AS served").
+		Joins("JOIN action_logs ON action_logs.action_id = meal_coupons.action_id").
+		Where("meal_coupons.event_id = ?", eventID).
+		Group("meal_coupons.id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count meal redemptions: %w", err)
+	}
+
+	served := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		served[row.CouponID] = row.Served
+	}
+	return served, nil
+}
+
+// CountMealPreferences returns how many approved participants of an event
+// chose each meal preference
+func (r *mealRepo) CountMealPreferences(eventID string) (map[string]int64, error) {
+	var rows []struct {
+		MealPreference string
+		Count          int64
+	}
+	if err := r.db.Model(&models.Participant{}).
+		Select("meal_preference, COUNT(*) AS count").
+		Where("event_id = ? AND approval_status = ?", eventID, "approved").
+		Group("meal_preference").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count meal preferences: %w", err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.MealPreference] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type actionRepo struct {
+	s *Store
+}
+
+func (r *actionRepo) CreateActionLog(log *models.ActionLog) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if r.s.dayFull(log) {
+		return repositories.ErrEventDayFull
+	}
+
+	r.s.stamp(&log.ID, &log.CreatedAt, nil)
+	stored := *log
+	stored.Participant, stored.Action, stored.Verifier, stored.APIKey = models.Participant{}, models.EventAction{}, models.User{}, nil
+	r.s.actionLogs[log.ID] = stored
+	return nil
+}
+
+func (r *actionRepo) HasActionLog(participantID, actionID string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	pid, aid := parseID(participantID), parseID(actionID)
+	for _, log := range r.s.actionLogs {
+		if log.ParticipantID == pid && log.ActionID == aid {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *actionRepo) GetActionLogsByParticipant(participantID string) ([]*models.ActionLog, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	pid := parseID(participantID)
+	logs := []*models.ActionLog{}
+	for _, log := range r.s.actionLogs {
+		if log.ParticipantID == pid {
+			log := log
+			log.Action = r.s.actions[log.ActionID]
+			if log.VerifiedBy != nil {
+				log.Verifier = r.s.users[*log.VerifiedBy]
+			}
+			if log.APIKeyID != nil {
+				key := r.s.apiKeys[*log.APIKeyID]
+				log.APIKey = &key
+			}
+			logs = append(logs, &log)
+		}
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].VerifiedAt.After(logs[j].VerifiedAt) })
+	return logs, nil
+}
+
+func (r *actionRepo) GetActionLogsByEvent(eventID string, offset, limit int) ([]*models.ActionLog, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	logs := r.s.eventActionLogs(parseID(eventID), nil)
+	sort.Slice(logs, func(i, j int) bool { return logs[i].VerifiedAt.After(logs[j].VerifiedAt) })
+	return page(logs, offset, limit), int64(len(logs)), nil
+}
+
+func (r *actionRepo) GetActionLogsByEventAfter(eventID string, cursor *repositories.Cursor, limit int) ([]*models.ActionLog, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	logs := r.s.eventActionLogs(parseID(eventID), cursor)
+	sort.Slice(logs, func(i, j int) bool {
+		return newestFirst(logs[i].VerifiedAt, logs[j].VerifiedAt, logs[i].ID, logs[j].ID)
+	})
+	return page(logs, 0, limit), nil
+}
+
+func (r *actionRepo) CountActionLogsByActionIDs(actionIDs []string) (map[string]int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]string, len(actionIDs))
+	for _, id := range actionIDs {
+		wanted[parseID(id)] = id
+	}
+
+	counts := make(map[string]int64, len(actionIDs))
+	for _, log := range r.s.actionLogs {
+		if id, ok := wanted[log.ActionID]; ok {
+			counts[id]++
+		}
+	}
+	return counts, nil
+}
+
+func (r *actionRepo) CountActionLogsByParticipants(participantIDs []string) (map[string]int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]string, len(participantIDs))
+	for _, id := range participantIDs {
+		wanted[parseID(id)] = id
+	}
+
+	counts := make(map[string]int64, len(participantIDs))
+	for _, log := range r.s.actionLogs {
+		if id, ok := wanted[log.ParticipantID]; ok {
+			counts[id]++
+		}
+	}
+	return counts, nil
+}
+
+func (r *actionRepo) CountVerifiedParticipants(eventID string) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	verified := make(map[uuid.UUID]bool)
+	for _, log := range r.s.eventActionLogs(parseID(eventID), nil) {
+		verified[log.ParticipantID] = true
+	}
+	return int64(len(verified)), nil
+}
+
+func (r *actionRepo) CountAttendeesByDay(eventID string) (map[string]int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	attendees := make(map[uuid.UUID]map[uuid.UUID]bool)
+	for _, log := range r.s.actionLogs {
+		action, ok := r.s.actions[log.ActionID]
+		if !ok || action.EventID != parseID(eventID) {
+			continue
+		}
+		if attendees[action.EventDayID] == nil {
+			attendees[action.EventDayID] = make(map[uuid.UUID]bool)
+		}
+		attendees[action.EventDayID][log.ParticipantID] = true
+	}
+
+	counts := make(map[string]int64, len(attendees))
+	for dayID, participants := range attendees {
+		counts[dayID.String()] = int64(len(participants))
+	}
+	return counts, nil
+}
+
+// dayFull reports whether the event day of the logged action is at capacity
+// and the participant has not been admitted on it yet. The caller must hold
+// the lock.
+func (s *Store) dayFull(log *models.ActionLog) bool {
+	action, ok := s.actions[log.ActionID]
+	if !ok {
+		return false
+	}
+	day, ok := s.days[action.EventDayID]
+	if !ok || day.Capacity == nil {
+		return false
+	}
+
+	attendees := make(map[uuid.UUID]bool)
+	for _, existing := range s.actionLogs {
+		if s.actions[existing.ActionID].EventDayID == day.ID {
+			attendees[existing.ParticipantID] = true
+		}
+	}
+	return !attendees[log.ParticipantID] && len(attendees) >= *day.Capacity
+}
+
+// eventActionLogs returns the logs of an event's participants verified
+// before cursor, with Participant, Action, Verifier and APIKey filled in. The caller
+// must hold the lock.
+func (s *Store) eventActionLogs(eventID uuid.UUID, cursor *repositories.Cursor) []*models.ActionLog {
+	logs := []*models.ActionLog{}
+	for _, log := range s.actionLogs {
+		participant, ok := s.participants[log.ParticipantID]
+		if !ok || participant.EventID != eventID || !before(log.VerifiedAt, log.ID, cursor) {
+			continue
+		}
+
+		log := log
+		log.Participant = participant
+		log.Action = s.actions[log.ActionID]
+		if log.VerifiedBy != nil {
+			log.Verifier = s.users[*log.VerifiedBy]
+		}
+		if log.APIKeyID != nil {
+			key := s.apiKeys[*log.APIKeyID]
+			log.APIKey = &key
+		}
+		logs = append(logs, &log)
+	}
+	return logs
+}
+
+func (r *actionRepo) CountActionLogsByVerifier(userID string, since time.Time) ([]repositories.VerifierEventCount, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	id := parseID(userID)
+	byEvent := make(map[uuid.UUID]int64)
+	for _, log := range r.s.actionLogs {
+		if log.VerifiedBy == nil || *log.VerifiedBy != id || log.VerifiedAt.Before(since) {
+			continue
+		}
+		byEvent[r.s.actions[log.ActionID].EventID]++
+	}
+
+	counts := make([]repositories.VerifierEventCount, 0, len(byEvent))
+	for eventID, count := range byEvent {
+		counts = append(counts, repositories.VerifierEventCount{
+			EventID:    eventID,
+			EventTitle: r.s.events[eventID].Title,
+			Count:      count,
+		})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].EventID.String() < counts[j].EventID.String()
+	})
+	return counts, nil
+}
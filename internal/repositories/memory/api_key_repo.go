@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type apiKeyRepo struct {
+	s *Store
+}
+
+func (r *apiKeyRepo) CreateAPIKey(key *models.APIKey) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.apiKeys {
+		if existing.KeyHash == key.KeyHash {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+
+	r.s.stamp(&key.ID, &key.CreatedAt, nil)
+	r.s.apiKeys[key.ID] = *key
+	return nil
+}
+
+func (r *apiKeyRepo) GetAPIKeyByHash(hash string) (*models.APIKey, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, key := range r.s.apiKeys {
+		if key.KeyHash == hash {
+			return &key, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *apiKeyRepo) ListAPIKeys() ([]models.APIKey, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	keys := []models.APIKey{}
+	for _, key := range r.s.apiKeys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (r *apiKeyRepo) RevokeAPIKey(id string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key, ok := r.s.apiKeys[parseID(id)]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if key.RevokedAt == nil {
+		key.RevokedAt = &at
+		r.s.apiKeys[key.ID] = key
+	}
+	return nil
+}
+
+func (r *apiKeyRepo) TouchAPIKey(id string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if key, ok := r.s.apiKeys[parseID(id)]; ok {
+		key.LastUsedAt = &at
+		r.s.apiKeys[key.ID] = key
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"sort"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+)
+
+type auditRepo struct {
+	s *Store
+}
+
+func (r *auditRepo) CreateAuditLog(log *models.AuditLog) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&log.ID, &log.CreatedAt, nil)
+	stored := *log
+	stored.Actor = nil
+	r.s.auditLogs[log.ID] = stored
+	return nil
+}
+
+func (r *auditRepo) ListAuditLogs(filter repositories.AuditLogFilter, offset, limit int) ([]models.AuditLog, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	logs := []models.AuditLog{}
+	for _, log := range r.s.auditLogs {
+		switch {
+		case filter.ActorID != "" && (log.ActorID == nil || *log.ActorID != parseID(filter.ActorID)),
+			filter.EntityType != "" && log.EntityType != filter.EntityType,
+			filter.EntityID != "" && log.EntityID != filter.EntityID,
+			filter.From != nil && log.CreatedAt.Before(*filter.From),
+			filter.To != nil && !log.CreatedAt.Before(*filter.To):
+			continue
+		}
+		if log.ActorID != nil {
+			if actor, ok := r.s.users[*log.ActorID]; ok {
+				log.Actor = &actor
+			}
+		}
+		logs = append(logs, log)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return newestFirst(logs[i].CreatedAt, logs[j].CreatedAt, logs[i].ID, logs[j].ID)
+	})
+	return page(logs, offset, limit), int64(len(logs)), nil
+}
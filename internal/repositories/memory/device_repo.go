@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type deviceRepo struct {
+	s *Store
+}
+
+func (r *deviceRepo) RegisterDevice(device *models.Device) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.devices {
+		if existing.Identifier == device.Identifier {
+			existing.Name = device.Name
+			existing.Platform = device.Platform
+			existing.RegisteredBy = device.RegisteredBy
+			existing.UpdatedAt = r.s.Now()
+			r.s.devices[existing.ID] = existing
+			*device = existing
+			return nil
+		}
+	}
+
+	r.s.stamp(&device.ID, &device.CreatedAt, &device.UpdatedAt)
+	r.s.devices[device.ID] = *device
+	return nil
+}
+
+func (r *deviceRepo) GetDevice(id string) (*models.Device, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	device, ok := r.s.devices[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &device, nil
+}
+
+func (r *deviceRepo) ListDevices() ([]models.Device, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	devices := []models.Device{}
+	for _, device := range r.s.devices {
+		devices = append(devices, device)
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].Name != devices[j].Name {
+			return devices[i].Name < devices[j].Name
+		}
+		return devices[i].CreatedAt.Before(devices[j].CreatedAt)
+	})
+	return devices, nil
+}
+
+func (r *deviceRepo) TouchDevice(id string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if device, ok := r.s.devices[parseID(id)]; ok {
+		device.LastSeenAt = &at
+		r.s.devices[device.ID] = device
+	}
+	return nil
+}
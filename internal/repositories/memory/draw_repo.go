@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type drawRepo struct {
+	s *Store
+}
+
+func (r *drawRepo) GetEligibleParticipantIDs(eventID string, criteria models.DrawCriteria) ([]uuid.UUID, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	eid := parseID(eventID)
+	won := make(map[uuid.UUID]bool)
+	for _, draw := range r.s.draws {
+		if draw.EventID != eid || (criteria.BeforeRound > 0 && draw.Round >= criteria.BeforeRound) {
+			continue
+		}
+		for _, winner := range draw.Winners {
+			won[winner.ParticipantID] = true
+		}
+	}
+
+	checkedIn := make(map[uuid.UUID]bool)
+	if criteria.CheckedInSince != nil {
+		for _, log := range r.s.actionLogs {
+			if log.VerifiedAt.Before(*criteria.CheckedInSince) {
+				continue
+			}
+			if criteria.ActionID != "" && log.ActionID != parseID(criteria.ActionID) {
+				continue
+			}
+			checkedIn[log.ParticipantID] = true
+		}
+	}
+
+	ids := []uuid.UUID{}
+	for _, participant := range r.s.eventParticipants(eid) {
+		if criteria.PaidOnly && participant.PaymentStatus != "paid" {
+			continue
+		}
+		if criteria.CheckedInSince != nil && !checkedIn[participant.ID] {
+			continue
+		}
+		if criteria.ExcludePreviousWinners && won[participant.ID] {
+			continue
+		}
+		ids = append(ids, participant.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	return ids, nil
+}
+
+func (r *drawRepo) NextDrawRound(eventID string) (int, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	last := 0
+	for _, draw := range r.s.draws {
+		if draw.EventID == parseID(eventID) && draw.Round > last {
+			last = draw.Round
+		}
+	}
+	return last + 1, nil
+}
+
+func (r *drawRepo) CreateDraw(draw *models.Draw) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.draws {
+		if existing.EventID == draw.EventID && existing.Round == draw.Round {
+			return fmt.Errorf("failed to create draw: round %d already drawn", draw.Round)
+		}
+	}
+
+	r.s.stamp(&draw.ID, &draw.CreatedAt, nil)
+	for i := range draw.Winners {
+		winner := &draw.Winners[i]
+		r.s.stamp(&winner.ID, &winner.CreatedAt, nil)
+		winner.DrawID = draw.ID
+	}
+
+	stored := *draw
+	stored.Winners = make([]models.DrawWinner, len(draw.Winners))
+	for i, winner := range draw.Winners {
+		winner.Participant = models.Participant{}
+		stored.Winners[i] = winner
+	}
+	r.s.draws[draw.ID] = stored
+	return nil
+}
+
+func (r *drawRepo) ListDrawsByEvent(eventID string) ([]models.Draw, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	draws := []models.Draw{}
+	for _, draw := range r.s.draws {
+		if draw.EventID != parseID(eventID) {
+			continue
+		}
+		winners := make([]models.DrawWinner, len(draw.Winners))
+		for i, winner := range draw.Winners {
+			winner.Participant = r.s.participants[winner.ParticipantID]
+			winners[i] = winner
+		}
+		sort.Slice(winners, func(i, j int) bool { return winners[i].Position < winners[j].Position })
+		draw.Winners = winners
+		draws = append(draws, draw)
+	}
+	sort.Slice(draws, func(i, j int) bool { return draws[i].Round < draws[j].Round })
+	return draws, nil
+}
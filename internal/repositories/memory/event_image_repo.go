@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"sort"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type eventImageRepo struct {
+	s *Store
+}
+
+func (r *eventImageRepo) CreateEventImage(image *models.EventImage) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	image.Position = 0
+	for id, existing := range r.s.eventImages {
+		if existing.EventID != image.EventID {
+			continue
+		}
+		if existing.Position >= image.Position {
+			image.Position = existing.Position + 1
+		}
+		if image.IsBanner && existing.IsBanner {
+			existing.IsBanner = false
+			r.s.eventImages[id] = existing
+		}
+	}
+	r.s.stamp(&image.ID, &image.CreatedAt, &image.UpdatedAt)
+	r.s.eventImages[image.ID] = *image
+	return nil
+}
+
+func (r *eventImageRepo) GetEventImage(id string) (*models.EventImage, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	image, ok := r.s.eventImages[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &image, nil
+}
+
+func (r *eventImageRepo) ListEventImages(eventID string) ([]models.EventImage, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.s.imagesOf(parseID(eventID)), nil
+}
+
+func (r *eventImageRepo) ReorderEventImages(eventID string, imageIDs []uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, id := range imageIDs {
+		if image, ok := r.s.eventImages[id]; !ok || image.EventID != parseID(eventID) {
+			return gorm.ErrRecordNotFound
+		}
+	}
+	for position, id := range imageIDs {
+		image := r.s.eventImages[id]
+		image.Position = position
+		image.UpdatedAt = r.s.Now()
+		r.s.eventImages[id] = image
+	}
+	return nil
+}
+
+func (r *eventImageRepo) SetBannerImage(eventID, imageID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	banner, ok := r.s.eventImages[parseID(imageID)]
+	if !ok || banner.EventID != parseID(eventID) {
+		return gorm.ErrRecordNotFound
+	}
+	for id, image := range r.s.eventImages {
+		if image.EventID == banner.EventID && image.IsBanner != (id == banner.ID) {
+			image.IsBanner = id == banner.ID
+			image.UpdatedAt = r.s.Now()
+			r.s.eventImages[id] = image
+		}
+	}
+	return nil
+}
+
+func (r *eventImageRepo) DeleteEventImage(id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	imageID := parseID(id)
+	if _, ok := r.s.eventImages[imageID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.s.eventImages, imageID)
+	return nil
+}
+
+// imagesOf returns the gallery of an event in display order. The caller must
+// hold the lock.
+func (s *Store) imagesOf(eventID uuid.UUID) []models.EventImage {
+	images := []models.EventImage{}
+	for _, image := range s.eventImages {
+		if image.EventID == eventID {
+			images = append(images, image)
+		}
+	}
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].Position != images[j].Position {
+			return images[i].Position < images[j].Position
+		}
+		return images[i].CreatedAt.Before(images[j].CreatedAt)
+	})
+	return images
+}
+
+// bannerOf returns the banner image of an event, if it has one. The caller must
+// hold the lock.
+func (s *Store) bannerOf(eventID uuid.UUID) *models.EventImage {
+	for _, image := range s.eventImages {
+		if image.EventID == eventID && image.IsBanner {
+			return &image
+		}
+	}
+	return nil
+}
@@ -0,0 +1,751 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type eventRepo struct {
+	s *Store
+}
+
+func (r *eventRepo) CreateEvent(event *models.Event) error {
+	if event == nil {
+		return errors.New("event cannot be nil")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.events {
+		if existing.Slug == event.Slug {
+			return fmt.Errorf("event with slug '%s' already exists", event.Slug)
+		}
+	}
+
+	r.s.stamp(&event.ID, &event.CreatedAt, &event.UpdatedAt)
+	if event.Version == 0 {
+		event.Version = 1
+	}
+	if event.Format == "" {
+		event.Format = "in_person"
+	}
+	if event.Status == "" {
+		event.Status = "published"
+	}
+	if event.Visibility == "" {
+		event.Visibility = "public"
+	}
+	if event.Waitlist == "" {
+		event.Waitlist = "off"
+	}
+	stored := *event
+	stored.Venue, stored.Banner = nil, nil
+	r.s.events[event.ID] = stored
+	return nil
+}
+
+func (r *eventRepo) GetEventByID(id string) (*models.Event, error) {
+	if id == "" {
+		return nil, errors.New("event ID cannot be empty")
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	event, ok := r.s.events[parseID(id)]
+	if !ok {
+		return nil, fmt.Errorf("event not found with ID: %s", id)
+	}
+	event.Venue, event.Banner = r.s.venueOf(event.VenueID), r.s.bannerOf(event.ID)
+	return &event, nil
+}
+
+func (r *eventRepo) GetEventBySlug(slug string) (*models.Event, error) {
+	if slug == "" {
+		return nil, errors.New("event slug cannot be empty")
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, event := range r.s.events {
+		if event.Slug == slug {
+			event.Venue, event.Banner = r.s.venueOf(event.VenueID), r.s.bannerOf(event.ID)
+			return &event, nil
+		}
+	}
+	return nil, fmt.Errorf("event not found with slug: %s", slug)
+}
+
+func (r *eventRepo) GetEventWithDays(id string) (*models.Event, error) {
+	if id == "" {
+		return nil, errors.New("event ID cannot be empty")
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	event, ok := r.s.events[parseID(id)]
+	if !ok {
+		return nil, fmt.Errorf("event not found with ID: %s", id)
+	}
+	r.s.withDays(&event)
+	return &event, nil
+}
+
+func (r *eventRepo) GetEventWithDaysBySlug(slug string) (*models.Event, error) {
+	if slug == "" {
+		return nil, errors.New("event slug cannot be empty")
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, event := range r.s.events {
+		if event.Slug == slug {
+			r.s.withDays(&event)
+			return &event, nil
+		}
+	}
+	return nil, fmt.Errorf("event not found with slug: %s", slug)
+}
+
+// withDays fills in the venue, banner, days and actions of an event. The
+// caller must hold the lock.
+func (s *Store) withDays(event *models.Event) {
+	event.Venue, event.Banner = s.venueOf(event.VenueID), s.bannerOf(event.ID)
+	event.EventDays = s.daysOf(event.ID)
+	for i := range event.EventDays {
+		event.EventDays[i].Venue = s.venueOf(event.EventDays[i].VenueID)
+		event.EventDays[i].EventActions = s.actionsOf(event.EventDays[i].ID, false)
+	}
+}
+
+func (r *eventRepo) ListEvents(offset, limit int, filters *repositories.EventFilters) ([]models.Event, int64, error) {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var events []models.Event
+	for _, event := range r.s.events {
+		if filters != nil {
+			if filters.IsActive != nil && event.IsActive != *filters.IsActive {
+				continue
+			}
+			if filters.Status != "" && event.Status != filters.Status {
+				continue
+			}
+			if filters.Visibility != "" && event.Visibility != filters.Visibility {
+				continue
+			}
+			if filters.StartsAfter != nil && event.StartsAt.Before(*filters.StartsAfter) {
+				continue
+			}
+			if filters.EndsBefore != nil && event.EndsAt.After(*filters.EndsBefore) {
+				continue
+			}
+			if filters.Search != "" {
+				term := strings.ToLower(filters.Search)
+				if !strings.Contains(strings.ToLower(event.Title), term) &&
+					!strings.Contains(strings.ToLower(event.Description), term) {
+					continue
+				}
+			}
+		}
+		events = append(events, event)
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].CreatedAt.After(events[j].CreatedAt) })
+	total := int64(len(events))
+
+	events = page(events, offset, limit)
+	for i := range events {
+		events[i].Venue, events[i].Banner = r.s.venueOf(events[i].VenueID), r.s.bannerOf(events[i].ID)
+		events[i].EventDays = r.s.daysOf(events[i].ID)
+		for j := range events[i].EventDays {
+			events[i].EventDays[j].Venue = r.s.venueOf(events[i].EventDays[j].VenueID)
+		}
+	}
+	return events, total, nil
+}
+
+func (r *eventRepo) UpdateEvent(event *models.Event) error {
+	if event == nil {
+		return errors.New("event cannot be nil")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.events[event.ID]
+	if !ok {
+		return fmt.Errorf("event not found with ID: %s", event.ID)
+	}
+
+	if event.Slug != existing.Slug {
+		for _, other := range r.s.events {
+			if other.Slug == event.Slug && other.ID != event.ID {
+				return fmt.Errorf("event with slug '%s' already exists", event.Slug)
+			}
+		}
+	}
+
+	if existing.Version != event.Version {
+		return repositories.ErrVersionConflict
+	}
+
+	event.Version++
+	event.CreatedAt = existing.CreatedAt
+	event.UpdatedAt = r.s.Now()
+
+	stored := *event
+	stored.EventDays, stored.Participants, stored.Venue, stored.Banner = nil, nil, nil, nil
+	r.s.events[event.ID] = stored
+	return nil
+}
+
+func (r *eventRepo) CancelEvent(event *models.Event, reason string, refund bool) (*repositories.CancellationCounts, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.events[event.ID]
+	if !ok || existing.Version != event.Version {
+		return nil, repositories.ErrVersionConflict
+	}
+	now := r.s.Now()
+	existing.Status = event.Status
+	existing.Version++
+	existing.UpdatedAt = now
+	r.s.events[event.ID] = existing
+	event.Version, event.UpdatedAt = existing.Version, now
+
+	counts := &repositories.CancellationCounts{}
+	for _, participant := range r.s.eventParticipants(event.ID) {
+		if participant.CancelledAt != nil {
+			continue
+		}
+		if refund && participant.PaymentStatus == "paid" {
+			participant.PaymentStatus = "refund_pending"
+			counts.Refunds++
+		}
+		participant.CancelledAt = &now
+		participant.CancellationReason = reason
+		participant.Version++
+		participant.UpdatedAt = now
+		r.s.participants[participant.ID] = participant
+		counts.Cancelled++
+	}
+	return counts, nil
+}
+
+func (r *eventRepo) SoftDeleteEvent(id string) error {
+	if id == "" {
+		return errors.New("event ID cannot be empty")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	event, ok := r.s.events[parseID(id)]
+	if !ok {
+		return fmt.Errorf("event not found with ID: %s", id)
+	}
+	event.IsActive = false
+	event.UpdatedAt = r.s.Now()
+	r.s.events[event.ID] = event
+	return nil
+}
+
+func (r *eventRepo) HasWidgetOrigin(origin string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, event := range r.s.events {
+		if !event.IsActive {
+			continue
+		}
+		for _, allowed := range strings.Split(event.WidgetOrigins, ",") {
+			if allowed == origin {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (r *eventRepo) UpdateLogoVariants(id string, variants map[string]string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if event, ok := r.s.events[parseID(id)]; ok {
+		event.LogoVariants = variants
+		r.s.events[event.ID] = event
+	}
+	return nil
+}
+
+func (r *eventRepo) GetEventByKioskTokenHash(hash string) (*models.Event, error) {
+	if hash == "" {
+		return nil, errors.New("kiosk token cannot be empty")
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, event := range r.s.events {
+		if event.KioskTokenHash == hash && event.IsActive {
+			return &event, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *eventRepo) UpdateKioskSettings(id, tokenHash, message string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	event, ok := r.s.events[parseID(id)]
+	if !ok {
+		return fmt.Errorf("event not found with ID: %s", id)
+	}
+	event.KioskTokenHash = tokenHash
+	event.KioskMessage = message
+	r.s.events[event.ID] = event
+	return nil
+}
+
+func (r *eventRepo) UpdateWidgetKey(id, key string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	event, ok := r.s.events[parseID(id)]
+	if !ok {
+		return fmt.Errorf("event not found with ID: %s", id)
+	}
+	event.WidgetKey = key
+	r.s.events[event.ID] = event
+	return nil
+}
+
+func (r *eventRepo) UpdateLiveStatsPublic(id string, public bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	event, ok := r.s.events[parseID(id)]
+	if !ok {
+		return fmt.Errorf("event not found with ID: %s", id)
+	}
+	event.LiveStatsPublic = public
+	r.s.events[event.ID] = event
+	return nil
+}
+
+func (r *eventRepo) ImportEvent(event *models.Event, sessions []models.Session, participants []models.Participant, logs []models.ActionLog) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.events {
+		if existing.Slug == event.Slug {
+			return fmt.Errorf("event with slug '%s' already exists", event.Slug)
+		}
+	}
+	var taken []string
+	for _, day := range event.EventDays {
+		for _, action := range day.EventActions {
+			for _, existing := range r.s.actions {
+				if existing.Code == action.Code {
+					taken = append(taken, action.Code)
+				}
+			}
+		}
+	}
+	if len(taken) > 0 {
+		return fmt.Errorf("event action codes already exist: %s", strings.Join(taken, ", "))
+	}
+
+	r.s.stamp(&event.ID, &event.CreatedAt, &event.UpdatedAt)
+	if event.Version == 0 {
+		event.Version = 1
+	}
+	stored := *event
+	stored.EventDays, stored.Participants, stored.Venue, stored.Banner = nil, nil, nil, nil
+	r.s.events[event.ID] = stored
+
+	for i := range event.EventDays {
+		day := &event.EventDays[i]
+		r.s.stamp(&day.ID, &day.CreatedAt, &day.UpdatedAt)
+		storedDay := *day
+		storedDay.EventActions, storedDay.Venue = nil, nil
+		r.s.days[day.ID] = storedDay
+
+		for j := range day.EventActions {
+			action := &day.EventActions[j]
+			r.s.stamp(&action.ID, &action.CreatedAt, &action.UpdatedAt)
+			r.s.actions[action.ID] = *action
+		}
+	}
+	for i := range sessions {
+		session := &sessions[i]
+		r.s.stamp(&session.ID, &session.CreatedAt, &session.UpdatedAt)
+		storedSession := *session
+		storedSession.Action = models.EventAction{}
+		r.s.sessions[session.ID] = storedSession
+	}
+	for i := range participants {
+		if err := (&participantRepo{r.s}).insert(&participants[i]); err != nil {
+			return err
+		}
+	}
+	for i := range logs {
+		log := &logs[i]
+		r.s.stamp(&log.ID, &log.CreatedAt, nil)
+		r.s.actionLogs[log.ID] = *log
+	}
+	return nil
+}
+
+func (r *eventRepo) CreateEventDay(day *models.EventDay) error {
+	if day == nil {
+		return errors.New("event day cannot be nil")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.events[day.EventID]; !ok {
+		return fmt.Errorf("event not found with ID: %s", day.EventID)
+	}
+	for _, existing := range r.s.days {
+		if existing.EventID == day.EventID && existing.DayNumber == day.DayNumber {
+			return fmt.Errorf("day number %d already exists for this event", day.DayNumber)
+		}
+	}
+
+	r.s.stamp(&day.ID, &day.CreatedAt, &day.UpdatedAt)
+	stored := *day
+	stored.EventActions, stored.Venue = nil, nil
+	r.s.days[day.ID] = stored
+	return nil
+}
+
+func (r *eventRepo) CreateEventDays(eventID string, days []models.EventDay) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	event, ok := r.s.events[parseID(eventID)]
+	if !ok {
+		return fmt.Errorf("event not found with ID: %s", eventID)
+	}
+	last := 0
+	for _, existing := range r.s.daysOf(event.ID) {
+		if existing.DayNumber > last {
+			last = existing.DayNumber
+		}
+	}
+
+	for i := range days {
+		days[i].EventID = event.ID
+		days[i].DayNumber = last + i + 1
+		if days[i].Label == "" {
+			days[i].Label = fmt.Sprintf("Day %d", days[i].DayNumber)
+		}
+		r.s.stamp(&days[i].ID, &days[i].CreatedAt, &days[i].UpdatedAt)
+		stored := days[i]
+		stored.EventActions, stored.Venue = nil, nil
+		r.s.days[stored.ID] = stored
+	}
+	return nil
+}
+
+func (r *eventRepo) GetEventDayByID(id string) (*models.EventDay, error) {
+	if id == "" {
+		return nil, errors.New("event day ID cannot be empty")
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	day, ok := r.s.days[parseID(id)]
+	if !ok {
+		return nil, fmt.Errorf("event day not found with ID: %s", id)
+	}
+	day.EventActions = r.s.actionsOf(day.ID, false)
+	return &day, nil
+}
+
+func (r *eventRepo) GetEventDaysByEventID(eventID string) ([]models.EventDay, error) {
+	if eventID == "" {
+		return nil, errors.New("event ID cannot be empty")
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	days := r.s.daysOf(parseID(eventID))
+	for i := range days {
+		days[i].EventActions = r.s.actionsOf(days[i].ID, false)
+	}
+	return days, nil
+}
+
+func (r *eventRepo) GetEventDaysByEventIDs(eventIDs []string) ([]models.EventDay, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	days := []models.EventDay{}
+	for _, id := range eventIDs {
+		days = append(days, r.s.daysOf(parseID(id))...)
+	}
+	sort.SliceStable(days, func(i, j int) bool { return days[i].DayNumber < days[j].DayNumber })
+	return days, nil
+}
+
+func (r *eventRepo) UpdateEventDay(day *models.EventDay) error {
+	if day == nil {
+		return errors.New("event day cannot be nil")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.days[day.ID]
+	if !ok {
+		return fmt.Errorf("event day not found with ID: %s", day.ID)
+	}
+	if day.DayNumber != existing.DayNumber {
+		for _, other := range r.s.days {
+			if other.EventID == day.EventID && other.DayNumber == day.DayNumber && other.ID != day.ID {
+				return fmt.Errorf("day number %d already exists for this event", day.DayNumber)
+			}
+		}
+	}
+
+	day.UpdatedAt = r.s.Now()
+	stored := *day
+	stored.EventActions, stored.Venue = nil, nil
+	r.s.days[day.ID] = stored
+	return nil
+}
+
+func (r *eventRepo) DeleteEventDay(id string) error {
+	if id == "" {
+		return errors.New("event day ID cannot be empty")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	dayID := parseID(id)
+	for _, action := range r.s.actions {
+		if action.EventDayID == dayID {
+			return errors.New("cannot delete event day with associated actions")
+		}
+	}
+	if _, ok := r.s.days[dayID]; !ok {
+		return fmt.Errorf("event day not found with ID: %s", id)
+	}
+	delete(r.s.days, dayID)
+	return nil
+}
+
+func (r *eventRepo) CreateEventAction(action *models.EventAction) error {
+	if action == nil {
+		return errors.New("event action cannot be nil")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.days[action.EventDayID]; !ok {
+		return fmt.Errorf("event day not found with ID: %s", action.EventDayID)
+	}
+	for _, existing := range r.s.actions {
+		if existing.Code == action.Code {
+			return fmt.Errorf("event action with code '%s' already exists", action.Code)
+		}
+	}
+
+	r.s.stamp(&action.ID, &action.CreatedAt, &action.UpdatedAt)
+	r.s.actions[action.ID] = *action
+	return nil
+}
+
+func (r *eventRepo) GetEventActionByID(id string) (*models.EventAction, error) {
+	if id == "" {
+		return nil, errors.New("event action ID cannot be empty")
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	action, ok := r.s.actions[parseID(id)]
+	if !ok {
+		return nil, fmt.Errorf("event action not found with ID: %s", id)
+	}
+	return &action, nil
+}
+
+func (r *eventRepo) GetEventActionByCode(code string) (*models.EventAction, error) {
+	if code == "" {
+		return nil, errors.New("event action code cannot be empty")
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, action := range r.s.actions {
+		if action.Code == code && action.IsActive {
+			return &action, nil
+		}
+	}
+	return nil, fmt.Errorf("event action not found with code: %s: %w", code, gorm.ErrRecordNotFound)
+}
+
+func (r *eventRepo) GetEventActionsByDayID(dayID string) ([]models.EventAction, error) {
+	if dayID == "" {
+		return nil, errors.New("event day ID cannot be empty")
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.s.actionsOf(parseID(dayID), true), nil
+}
+
+func (r *eventRepo) GetEventActionsByEventID(eventID string) ([]models.EventAction, error) {
+	if eventID == "" {
+		return nil, errors.New("event ID cannot be empty")
+	}
+
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	actions := []models.EventAction{}
+	for _, day := range r.s.daysOf(parseID(eventID)) {
+		actions = append(actions, r.s.actionsOf(day.ID, true)...)
+	}
+	return actions, nil
+}
+
+func (r *eventRepo) GetEventActionsByDayIDs(dayIDs []string) ([]models.EventAction, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	actions := []models.EventAction{}
+	for _, id := range dayIDs {
+		actions = append(actions, r.s.actionsOf(parseID(id), true)...)
+	}
+	sort.SliceStable(actions, func(i, j int) bool { return actionBefore(actions[i], actions[j]) })
+	return actions, nil
+}
+
+func (r *eventRepo) UpdateEventAction(action *models.EventAction) error {
+	if action == nil {
+		return errors.New("event action cannot be nil")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.actions[action.ID]
+	if !ok {
+		return fmt.Errorf("event action not found with ID: %s", action.ID)
+	}
+	if action.Code != existing.Code {
+		for _, other := range r.s.actions {
+			if other.Code == action.Code && other.ID != action.ID {
+				return fmt.Errorf("event action with code '%s' already exists", action.Code)
+			}
+		}
+	}
+
+	action.UpdatedAt = r.s.Now()
+	r.s.actions[action.ID] = *action
+	return nil
+}
+
+func (r *eventRepo) ReorderEventActions(dayID string, actionIDs []uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, id := range actionIDs {
+		if action, ok := r.s.actions[id]; !ok || action.EventDayID != parseID(dayID) {
+			return fmt.Errorf("event action not found with ID: %s", id)
+		}
+	}
+	for position, id := range actionIDs {
+		action := r.s.actions[id]
+		action.Position = position
+		action.UpdatedAt = r.s.Now()
+		r.s.actions[id] = action
+	}
+	return nil
+}
+
+func (r *eventRepo) DeleteEventAction(id string) error {
+	if id == "" {
+		return errors.New("event action ID cannot be empty")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	action, ok := r.s.actions[parseID(id)]
+	if !ok {
+		return fmt.Errorf("event action not found with ID: %s", id)
+	}
+	action.IsActive = false
+	action.UpdatedAt = r.s.Now()
+	r.s.actions[action.ID] = action
+	return nil
+}
+
+// daysOf returns the days of an event ordered by day number. The caller must
+// hold the lock.
+func (s *Store) daysOf(eventID uuid.UUID) []models.EventDay {
+	days := []models.EventDay{}
+	for _, day := range s.days {
+		if day.EventID == eventID {
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].DayNumber < days[j].DayNumber })
+	return days
+}
+
+// actionsOf returns the actions of a day in display order, optionally only
+// the active ones.
+// The caller must hold the lock.
+func (s *Store) actionsOf(dayID uuid.UUID, activeOnly bool) []models.EventAction {
+	actions := []models.EventAction{}
+	for _, action := range s.actions {
+		if action.EventDayID == dayID && (!activeOnly || action.IsActive) {
+			actions = append(actions, action)
+		}
+	}
+	sort.Slice(actions, func(i, j int) bool { return actionBefore(actions[i], actions[j]) })
+	return actions
+}
+
+// actionBefore orders actions by position, then name
+func actionBefore(a, b models.EventAction) bool {
+	if a.Position != b.Position {
+		return a.Position < b.Position
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"sort"
+
+	"event-management-backend/internal/models"
+)
+
+type eventRevisionRepo struct {
+	s *Store
+}
+
+func (r *eventRevisionRepo) CreateEventRevision(revision *models.EventRevision) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&revision.ID, &revision.CreatedAt, nil)
+	r.s.eventRevisions[revision.ID] = *revision
+	return nil
+}
+
+func (r *eventRevisionRepo) ListEventRevisions(eventID string, offset, limit int) ([]models.EventRevision, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	revisions := []models.EventRevision{}
+	for _, revision := range r.s.eventRevisions {
+		if revision.EventID == parseID(eventID) {
+			revisions = append(revisions, revision)
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		if !revisions[i].CreatedAt.Equal(revisions[j].CreatedAt) {
+			return revisions[i].CreatedAt.After(revisions[j].CreatedAt)
+		}
+		return revisions[i].Version > revisions[j].Version
+	})
+
+	total := int64(len(revisions))
+	return page(revisions, offset, limit), total, nil
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"sort"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// eventStaffKey is the composite primary key of event_staff
+type eventStaffKey struct {
+	EventID uuid.UUID
+	UserID  uuid.UUID
+}
+
+type eventStaffRepo struct {
+	s *Store
+}
+
+func (r *eventStaffRepo) AssignEventStaff(assignment *models.EventStaff) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := eventStaffKey{assignment.EventID, assignment.UserID}
+	if _, exists := r.s.eventStaff[key]; exists {
+		return nil
+	}
+	if assignment.CreatedAt.IsZero() {
+		assignment.CreatedAt = r.s.Now()
+	}
+	stored := *assignment
+	stored.User = models.User{}
+	r.s.eventStaff[key] = stored
+	return nil
+}
+
+func (r *eventStaffRepo) UnassignEventStaff(eventID, userID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := eventStaffKey{parseID(eventID), parseID(userID)}
+	if _, exists := r.s.eventStaff[key]; !exists {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.s.eventStaff, key)
+	return nil
+}
+
+func (r *eventStaffRepo) ListEventStaff(eventID string) ([]models.EventStaff, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	id := parseID(eventID)
+	staff := []models.EventStaff{}
+	for key, assignment := range r.s.eventStaff {
+		if key.EventID == id {
+			assignment.User = r.s.users[assignment.UserID]
+			staff = append(staff, assignment)
+		}
+	}
+	sort.Slice(staff, func(i, j int) bool {
+		return staff[i].CreatedAt.Before(staff[j].CreatedAt)
+	})
+	return staff, nil
+}
+
+func (r *eventStaffRepo) ListStaffEventIDs(userID string) ([]uuid.UUID, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	id := parseID(userID)
+	ids := []uuid.UUID{}
+	for key := range r.s.eventStaff {
+		if key.UserID == id {
+			ids = append(ids, key.EventID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *eventStaffRepo) IsEventStaff(eventID, userID string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	_, assigned := r.s.eventStaff[eventStaffKey{parseID(eventID), parseID(userID)}]
+	return assigned, nil
+}
@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/utils"
+)
+
+// Fixtures builds valid records with sensible defaults and saves them in
+// the store. Each builder takes optional functions to adjust the record
+// before it is saved:
+//
+//	event := fx.Event(func(e *models.Event) { e.TicketPrice = 50000 })
+//	day := fx.Day(event)
+//	checkIn := fx.Action(day, func(a *models.EventAction) { a.Code = "CHECKIN" })
+//	participant := fx.Participant(event, fx.Paid)
+//
+// Builders panic on failure since they only run in test setup.
+type Fixtures struct {
+	store *Store
+	seq   atomic.Int64
+}
+
+// Fixtures returns the builders for this store
+func (s *Store) Fixtures() *Fixtures {
+	return &Fixtures{store: s}
+}
+
+// FixturePassword is the plain text password of users built by Fixtures
+const FixturePassword = "password123"
+
+func (f *Fixtures) next() int64 {
+	return f.seq.Add(1)
+}
+
+// User creates a user with the given role (admin, organizer or staff)
+func (f *Fixtures) User(role string, opts ...func(*models.User)) *models.User {
+	hashed, err := utils.HashPassword(FixturePassword)
+	must(err)
+
+	user := &models.User{
+		Email:    fmt.Sprintf("%s%d@example.com", role, f.next()),
+		Password: hashed,
+		Role:     role,
+	}
+	for _, opt := range opts {
+		opt(user)
+	}
+
+	must(f.store.Repository().UserRepo.CreateUser(user))
+	return user
+}
+
+// Event creates an active, free event that started an hour ago and runs for
+// a day
+func (f *Fixtures) Event(opts ...func(*models.Event)) *models.Event {
+	n := f.next()
+	startsAt := f.store.Now().Add(-time.Hour)
+
+	event := &models.Event{
+		Title:    fmt.Sprintf("Event %d", n),
+		Slug:     fmt.Sprintf("event-%d", n),
+		StartsAt: startsAt,
+		EndsAt:   startsAt.Add(24 * time.Hour),
+		IsActive: true,
+	}
+	for _, opt := range opts {
+		opt(event)
+	}
+
+	must(f.store.Repository().EventRepo.CreateEvent(event))
+	return event
+}
+
+// Day creates the next day of event, dated today for day 1
+func (f *Fixtures) Day(event *models.Event, opts ...func(*models.EventDay)) *models.EventDay {
+	f.store.mu.RLock()
+	number := len(f.store.daysOf(event.ID)) + 1
+	f.store.mu.RUnlock()
+
+	day := &models.EventDay{
+		EventID:   event.ID,
+		DayNumber: number,
+		Label:     fmt.Sprintf("Day %d", number),
+		Date:      f.store.Now().Truncate(24*time.Hour).AddDate(0, 0, number-1),
+	}
+	for _, opt := range opts {
+		opt(day)
+	}
+
+	must(f.store.Repository().EventRepo.CreateEventDay(day))
+	return day
+}
+
+// Action creates an active action on day with a unique code
+func (f *Fixtures) Action(day *models.EventDay, opts ...func(*models.EventAction)) *models.EventAction {
+	n := f.next()
+	action := &models.EventAction{
+		EventID:    day.EventID,
+		EventDayID: day.ID,
+		Name:       fmt.Sprintf("Action %d", n),
+		Code:       fmt.Sprintf("ACTION-%d", n),
+		IsActive:   true,
+	}
+	for _, opt := range opts {
+		opt(action)
+	}
+
+	must(f.store.Repository().EventRepo.CreateEventAction(action))
+	return action
+}
+
+// Participant registers a participant to event with a unique email and a
+// QR path; the QR image itself is not written
+func (f *Fixtures) Participant(event *models.Event, opts ...func(*models.Participant)) *models.Participant {
+	n := f.next()
+	participant := &models.Participant{
+		EventID:       event.ID,
+		Name:          fmt.Sprintf("Participant %d", n),
+		Email:         fmt.Sprintf("participant%d@example.com", n),
+		Phone:         fmt.Sprintf("0812%08d", n),
+		PaymentStatus: "unpaid",
+		QRPath:        fmt.Sprintf("/qrcodes/%s", utils.NewQRCodeFilename()),
+	}
+	if event.TicketPrice == 0 {
+		participant.PaymentStatus = "paid"
+	}
+	for _, opt := range opts {
+		opt(participant)
+	}
+
+	must(f.store.Repository().ParticipantRepo.CreateParticipant(participant))
+	return participant
+}
+
+// Paid marks a participant as paid
+func (f *Fixtures) Paid(p *models.Participant) {
+	p.PaymentStatus = "paid"
+}
+
+// Verification records that verifier verified action for participant
+func (f *Fixtures) Verification(participant *models.Participant, action *models.EventAction, verifier *models.User, opts ...func(*models.ActionLog)) *models.ActionLog {
+	log := &models.ActionLog{
+		ParticipantID: participant.ID,
+		ActionID:      action.ID,
+		VerifiedBy:    &verifier.ID,
+		VerifiedAt:    f.store.Now(),
+		Source:        "scan",
+	}
+	for _, opt := range opts {
+		opt(log)
+	}
+
+	must(f.store.Repository().ActionRepo.CreateActionLog(log))
+	return log
+}
+
+func must(err error) {
+	if err != nil {
+		panic(fmt.Sprintf("memory fixtures: %v", err))
+	}
+}
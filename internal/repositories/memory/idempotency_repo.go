@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"errors"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type idempotencyRepo struct {
+	s *Store
+}
+
+func (r *idempotencyRepo) GetByKey(key string) (*models.IdempotencyKey, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	record, ok := r.s.idempotency[key]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &record, nil
+}
+
+func (r *idempotencyRepo) Create(record *models.IdempotencyKey) error {
+	if record == nil {
+		return errors.New("idempotency record cannot be nil")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, exists := r.s.idempotency[record.Key]; exists {
+		return gorm.ErrDuplicatedKey
+	}
+	r.s.stamp(&record.ID, &record.CreatedAt, nil)
+	r.s.idempotency[record.Key] = *record
+	return nil
+}
+
+func (r *idempotencyRepo) SaveResponse(key string, statusCode int, body []byte, contentType string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if record, ok := r.s.idempotency[key]; ok {
+		record.StatusCode = statusCode
+		record.ResponseBody = append([]byte(nil), body...)
+		record.ContentType = contentType
+		r.s.idempotency[key] = record
+	}
+	return nil
+}
+
+func (r *idempotencyRepo) Delete(key string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.idempotency, key)
+	return nil
+}
+
+func (r *idempotencyRepo) DeleteExpired(now time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var deleted int64
+	for key, record := range r.s.idempotency {
+		if record.ExpiresAt.Before(now) {
+			delete(r.s.idempotency, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type importJobRepo struct {
+	s *Store
+}
+
+func (r *importJobRepo) CreateImportJob(job *models.ImportJob) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&job.ID, &job.CreatedAt, &job.UpdatedAt)
+	stored := *job
+	stored.RowErrors = append([]models.ImportRowError(nil), job.RowErrors...)
+	r.s.importJobs[job.ID] = stored
+	return nil
+}
+
+func (r *importJobRepo) GetImportJob(id string) (*models.ImportJob, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	job, ok := r.s.importJobs[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	job.RowErrors = append([]models.ImportRowError(nil), job.RowErrors...)
+	return &job, nil
+}
+
+func (r *importJobRepo) UpdateImportJob(job *models.ImportJob) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.importJobs[job.ID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	job.EventID, job.CreatedBy, job.CreatedAt = existing.EventID, existing.CreatedBy, existing.CreatedAt
+	job.UpdatedAt = r.s.Now()
+	stored := *job
+	stored.RowErrors = append([]models.ImportRowError(nil), job.RowErrors...)
+	r.s.importJobs[job.ID] = stored
+	return nil
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"event-management-backend/internal/models"
+)
+
+type jobRepo struct {
+	s *Store
+}
+
+func (r *jobRepo) CreateJob(job *models.Job) error {
+	if job == nil {
+		return errors.New("job cannot be nil")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&job.ID, &job.CreatedAt, &job.UpdatedAt)
+	if job.Status == "" {
+		job.Status = "pending"
+	}
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = 5
+	}
+	r.s.jobs[job.ID] = *job
+	return nil
+}
+
+func (r *jobRepo) ClaimNextJob(now time.Time) (*models.Job, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var next *models.Job
+	for _, job := range r.s.jobs {
+		if job.Status != "pending" || job.RunAt.After(now) {
+			continue
+		}
+		if next == nil || job.RunAt.Before(next.RunAt) {
+			job := job
+			next = &job
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	started := r.s.Now()
+	next.Status = "running"
+	next.Attempts++
+	next.StartedAt = &started
+	next.UpdatedAt = started
+	r.s.jobs[next.ID] = *next
+	return next, nil
+}
+
+func (r *jobRepo) MarkJobCompleted(id string) error {
+	return r.update(id, func(job *models.Job) {
+		now := r.s.Now()
+		job.Status = "completed"
+		job.FinishedAt = &now
+		job.LastError = ""
+	})
+}
+
+func (r *jobRepo) MarkJobFailed(id, lastError string, retryAt *time.Time) error {
+	return r.update(id, func(job *models.Job) {
+		job.LastError = lastError
+		if retryAt != nil {
+			job.Status = "pending"
+			job.RunAt = *retryAt
+			return
+		}
+		now := r.s.Now()
+		job.Status = "failed"
+		job.FinishedAt = &now
+	})
+}
+
+func (r *jobRepo) ListJobs(offset, limit int, status string) ([]models.Job, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	jobs := []models.Job{}
+	for _, job := range r.s.jobs {
+		if status == "" || job.Status == status {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	return page(jobs, offset, limit), int64(len(jobs)), nil
+}
+
+func (r *jobRepo) ResetStaleJobs(olderThan time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var reset int64
+	for id, job := range r.s.jobs {
+		if job.Status == "running" && job.StartedAt != nil && job.StartedAt.Before(olderThan) {
+			job.Status = "pending"
+			job.RunAt = r.s.Now()
+			r.s.jobs[id] = job
+			reset++
+		}
+	}
+	return reset, nil
+}
+
+// update applies fn to a stored job; unknown IDs are ignored like an UPDATE
+// matching no rows
+func (r *jobRepo) update(id string, fn func(*models.Job)) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	job, ok := r.s.jobs[parseID(id)]
+	if !ok {
+		return nil
+	}
+	fn(&job)
+	job.UpdatedAt = r.s.Now()
+	r.s.jobs[job.ID] = job
+	return nil
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type mealRepo struct {
+	s *Store
+}
+
+func (r *mealRepo) CreateMealCoupons(coupons []models.MealCoupon) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	codes := make(map[string]bool, len(coupons))
+	for _, existing := range r.s.actions {
+		codes[existing.Code] = true
+	}
+	for _, coupon := range coupons {
+		if codes[coupon.Action.Code] {
+			return fmt.Errorf("event action with code '%s' already exists", coupon.Action.Code)
+		}
+		codes[coupon.Action.Code] = true
+	}
+
+	for i := range coupons {
+		action := &coupons[i].Action
+		r.s.stamp(&action.ID, &action.CreatedAt, &action.UpdatedAt)
+		r.s.actions[action.ID] = *action
+
+		coupons[i].ActionID = action.ID
+		r.s.stamp(&coupons[i].ID, &coupons[i].CreatedAt, &coupons[i].UpdatedAt)
+		stored := coupons[i]
+		stored.Action = models.EventAction{}
+		r.s.mealCoupons[stored.ID] = stored
+	}
+	return nil
+}
+
+func (r *mealRepo) GetMealCouponByActionID(actionID string) (*models.MealCoupon, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, coupon := range r.s.mealCoupons {
+		if coupon.ActionID == parseID(actionID) {
+			return &coupon, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *mealRepo) ListMealCouponsByEvent(eventID string) ([]models.MealCoupon, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	coupons := []models.MealCoupon{}
+	for _, coupon := range r.s.mealCoupons {
+		if coupon.EventID == parseID(eventID) {
+			coupon.Action = r.s.actions[coupon.ActionID]
+			coupons = append(coupons, coupon)
+		}
+	}
+	sort.Slice(coupons, func(i, j int) bool {
+		if coupons[i].CreatedAt.Equal(coupons[j].CreatedAt) {
+			return coupons[i].Preference < coupons[j].Preference
+		}
+		return coupons[i].CreatedAt.Before(coupons[j].CreatedAt)
+	})
+	return coupons, nil
+}
+
+func (r *mealRepo) CreateMealRedemption(coupon *models.MealCoupon, log *models.ActionLog) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.mealCoupons[coupon.ID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	if stored.Quota != nil && r.served(stored.ActionID) >= *stored.Quota {
+		return repositories.ErrMealQuotaReached
+	}
+	if r.s.dayFull(log) {
+		return repositories.ErrEventDayFull
+	}
+
+	r.s.stamp(&log.ID, &log.CreatedAt, nil)
+	entry := *log
+	entry.Participant, entry.Action, entry.Verifier = models.Participant{}, models.EventAction{}, models.User{}
+	r.s.actionLogs[log.ID] = entry
+	return nil
+}
+
+func (r *mealRepo) CountMealRedemptions(eventID string) (map[uuid.UUID]int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	served := make(map[uuid.UUID]int64)
+	for _, coupon := range r.s.mealCoupons {
+		if coupon.EventID != parseID(eventID) {
+			continue
+		}
+		if count := r.served(coupon.ActionID); count > 0 {
+			served[coupon.ID] = int64(count)
+		}
+	}
+	return served, nil
+}
+
+func (r *mealRepo) CountMealPreferences(eventID string) (map[string]int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	counts := make(map[string]int64)
+	for _, participant := range r.s.eventParticipants(parseID(eventID)) {
+		if participant.ApprovalStatus == "approved" {
+			counts[participant.MealPreference]++
+		}
+	}
+	return counts, nil
+}
+
+// served counts the scans of a meal action. The caller must hold the lock.
+func (r *mealRepo) served(actionID uuid.UUID) int {
+	count := 0
+	for _, log := range r.s.actionLogs {
+		if log.ActionID == actionID {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type onlineRepo struct {
+	s *Store
+}
+
+func (r *onlineRepo) CreateMeeting(meeting *models.OnlineMeeting) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.onlineMeetings {
+		if existing.ActionID == meeting.ActionID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+
+	r.s.stamp(&meeting.ID, &meeting.CreatedAt, nil)
+	stored := *meeting
+	stored.Action = models.EventAction{}
+	r.s.onlineMeetings[stored.ID] = stored
+	return nil
+}
+
+// withAction fills the action relation of a meeting. The caller must hold
+// the lock.
+func (r *onlineRepo) withAction(meeting models.OnlineMeeting) *models.OnlineMeeting {
+	meeting.Action = r.s.actions[meeting.ActionID]
+	return &meeting
+}
+
+func (r *onlineRepo) GetMeetingByID(id string) (*models.OnlineMeeting, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	meeting, ok := r.s.onlineMeetings[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.withAction(meeting), nil
+}
+
+func (r *onlineRepo) GetMeetingByExternalID(externalID string) (*models.OnlineMeeting, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, meeting := range r.s.onlineMeetings {
+		if meeting.ExternalID == externalID {
+			return r.withAction(meeting), nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *onlineRepo) ListMeetingsByEvent(eventID string) ([]models.OnlineMeeting, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	meetings := []models.OnlineMeeting{}
+	for _, meeting := range r.s.onlineMeetings {
+		if meeting.EventID == parseID(eventID) {
+			meetings = append(meetings, *r.withAction(meeting))
+		}
+	}
+	sort.Slice(meetings, func(i, j int) bool { return meetings[i].CreatedAt.Before(meetings[j].CreatedAt) })
+	return meetings, nil
+}
+
+func (r *onlineRepo) DeleteMeeting(id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.onlineMeetings[parseID(id)]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.s.onlineMeetings, parseID(id))
+	return nil
+}
+
+func (r *onlineRepo) ListJoinLinkRecipients(eventID string, paidOnly bool, limit int) ([]models.Participant, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	participants := []models.Participant{}
+	for _, participant := range r.s.eventParticipants(parseID(eventID)) {
+		if participant.ApprovalStatus != "approved" || participant.CancelledAt != nil || participant.JoinLinkSentAt != nil {
+			continue
+		}
+		if paidOnly && participant.PaymentStatus != "paid" && !participant.HasTag(models.TagComplimentary) {
+			continue
+		}
+		participants = append(participants, participant)
+	}
+	sort.Slice(participants, func(i, j int) bool {
+		if participants[i].CreatedAt.Equal(participants[j].CreatedAt) {
+			return participants[i].ID.String() < participants[j].ID.String()
+		}
+		return participants[i].CreatedAt.Before(participants[j].CreatedAt)
+	})
+	if len(participants) > limit {
+		participants = participants[:limit]
+	}
+	return participants, nil
+}
+
+func (r *onlineRepo) MarkJoinLinkSent(participantID string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	participant, ok := r.s.participants[parseID(participantID)]
+	if !ok {
+		return nil
+	}
+	participant.JoinLinkSentAt = &at
+	r.s.participants[participant.ID] = participant
+	return nil
+}
+
+func (r *onlineRepo) RecordAttendance(logs []models.ActionLog) (int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	type key struct{ participant, action uuid.UUID }
+	recorded := make(map[key]bool)
+	for _, log := range r.s.actionLogs {
+		recorded[key{log.ParticipantID, log.ActionID}] = true
+	}
+
+	created := 0
+	for i := range logs {
+		k := key{logs[i].ParticipantID, logs[i].ActionID}
+		if recorded[k] {
+			continue
+		}
+		if _, ok := r.s.participants[logs[i].ParticipantID]; !ok {
+			return 0, fmt.Errorf("failed to record attendance: participant %s not found", logs[i].ParticipantID)
+		}
+		recorded[k] = true
+
+		r.s.stamp(&logs[i].ID, &logs[i].CreatedAt, nil)
+		stored := logs[i]
+		stored.Participant, stored.Action, stored.Verifier = models.Participant{}, models.EventAction{}, models.User{}
+		r.s.actionLogs[stored.ID] = stored
+		created++
+	}
+	return created, nil
+}
+
+func (r *onlineRepo) CountAttendance(eventID string) (*repositories.AttendanceCounts, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	inPerson := make(map[uuid.UUID]bool)
+	online := make(map[uuid.UUID]bool)
+	total := make(map[uuid.UUID]bool)
+	for _, log := range r.s.actionLogs {
+		participant, ok := r.s.participants[log.ParticipantID]
+		if !ok || participant.EventID != parseID(eventID) {
+			continue
+		}
+		switch log.Source {
+		case "scan":
+			inPerson[log.ParticipantID] = true
+		case "online":
+			online[log.ParticipantID] = true
+		}
+		total[log.ParticipantID] = true
+	}
+
+	return &repositories.AttendanceCounts{
+		InPerson: int64(len(inPerson)),
+		Online:   int64(len(online)),
+		Total:    int64(len(total)),
+	}, nil
+}
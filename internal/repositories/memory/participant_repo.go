@@ -0,0 +1,770 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/fieldcrypt"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type participantRepo struct {
+	s *Store
+}
+
+// insert stores a participant, applying the defaults and hooks the database
+// and GORM would. The caller must hold the lock.
+func (r *participantRepo) insert(participant *models.Participant) error {
+	if _, exists := r.s.participants[participant.ID]; exists && participant.ID != uuid.Nil {
+		return gorm.ErrDuplicatedKey
+	}
+	if err := participant.BeforeSave(nil); err != nil {
+		return err
+	}
+
+	r.s.stamp(&participant.ID, &participant.CreatedAt, &participant.UpdatedAt)
+	if participant.PaymentStatus == "" {
+		participant.PaymentStatus = "unpaid"
+	}
+	if participant.ApprovalStatus == "" {
+		participant.ApprovalStatus = "approved"
+	}
+	if participant.MealPreference == "" {
+		participant.MealPreference = "regular"
+	}
+	if participant.Version == 0 {
+		participant.Version = 1
+	}
+
+	stored := *participant
+	stored.Event, stored.ActionLogs = models.Event{}, nil
+	r.s.participants[participant.ID] = stored
+	return nil
+}
+
+func (r *participantRepo) CreateParticipant(participant *models.Participant) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return r.insert(participant)
+}
+
+// CreateParticipantsInBatches inserts all participants or none of them
+func (r *participantRepo) CreateParticipantsInBatches(participants []models.Participant, batchSize int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	inserted := make([]uuid.UUID, 0, len(participants))
+	for i := range participants {
+		if err := r.insert(&participants[i]); err != nil {
+			for _, id := range inserted {
+				delete(r.s.participants, id)
+			}
+			return err
+		}
+		inserted = append(inserted, participants[i].ID)
+	}
+	return nil
+}
+
+func (r *participantRepo) CreateParticipantGroup(eventID string, participants []models.Participant, limit *int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.events[parseID(eventID)]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if limit != nil && r.s.ticketHolders(parseID(eventID))+len(participants) > *limit {
+		return repositories.ErrTicketQuotaExceeded
+	}
+	if err := r.s.checkTicketTypeQuotas(parseID(eventID), participants); err != nil {
+		return err
+	}
+	if err := r.s.checkQuotaRules(parseID(eventID), participants); err != nil {
+		return err
+	}
+	inserted := make([]uuid.UUID, 0, len(participants))
+	for i := range participants {
+		if err := r.insert(&participants[i]); err != nil {
+			for _, id := range inserted {
+				delete(r.s.participants, id)
+			}
+			return err
+		}
+		inserted = append(inserted, participants[i].ID)
+	}
+	return nil
+}
+
+func (r *participantRepo) GetRegisteredEmailHashes(eventID string, hashes []string) (map[string]bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(hashes))
+	for _, hash := range hashes {
+		wanted[hash] = true
+	}
+	set := make(map[string]bool)
+	for _, participant := range r.s.eventParticipants(parseID(eventID)) {
+		if wanted[participant.EmailHash] {
+			set[participant.EmailHash] = true
+		}
+	}
+	return set, nil
+}
+
+func (r *participantRepo) ClearQRPaths(ids []uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, id := range ids {
+		if participant, ok := r.s.participants[id]; ok {
+			participant.QRPath = ""
+			r.s.participants[id] = participant
+		}
+	}
+	return nil
+}
+
+func (r *participantRepo) GetParticipantByID(id string) (*models.Participant, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	participant, ok := r.s.participants[parseID(id)]
+	if !ok || participant.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &participant, nil
+}
+
+func (r *participantRepo) GetParticipantByEmailAndEvent(email, eventID string) (*models.Participant, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	hash := fieldcrypt.Hash(email)
+	for _, participant := range r.s.eventParticipants(parseID(eventID)) {
+		if participant.EmailHash == hash {
+			return &participant, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *participantRepo) FindParticipantByQRPath(qrPath string) (*models.Participant, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, participant := range r.s.participants {
+		if participant.QRPath == qrPath && !participant.DeletedAt.Valid {
+			return &participant, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *participantRepo) GetParticipantCountByEventID(eventID string) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return int64(r.s.ticketHolders(parseID(eventID))), nil
+}
+
+func (r *participantRepo) CountParticipantsByEventIDs(eventIDs []string) (map[string]int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	counts := make(map[string]int64, len(eventIDs))
+	for _, id := range eventIDs {
+		if n := len(r.s.eventParticipants(parseID(id))); n > 0 {
+			counts[id] = int64(n)
+		}
+	}
+	return counts, nil
+}
+
+func (r *participantRepo) CountParticipantsByTicketType(eventID string) (map[string]int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	counts := make(map[string]int64)
+	for _, participant := range r.s.eventParticipants(parseID(eventID)) {
+		if participant.TicketTypeID != nil && participant.CancelledAt == nil {
+			counts[participant.TicketTypeID.String()]++
+		}
+	}
+	return counts, nil
+}
+
+func (r *participantRepo) ListParticipantsByEvent(eventID string, offset, limit int, filters *repositories.ParticipantFilters) ([]models.Participant, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	participants := []models.Participant{}
+	for _, participant := range r.s.eventParticipants(parseID(eventID)) {
+		if matchesParticipantFilters(&participant, filters) {
+			participants = append(participants, participant)
+		}
+	}
+	sort.Slice(participants, func(i, j int) bool {
+		return participants[i].CreatedAt.After(participants[j].CreatedAt)
+	})
+	return page(participants, offset, limit), int64(len(participants)), nil
+}
+
+func (r *participantRepo) ListParticipantsByEventAfter(eventID string, cursor *repositories.Cursor, limit int, filters *repositories.ParticipantFilters) ([]models.Participant, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	participants := []models.Participant{}
+	for _, participant := range r.s.eventParticipants(parseID(eventID)) {
+		if before(participant.CreatedAt, participant.ID, cursor) && matchesParticipantFilters(&participant, filters) {
+			participants = append(participants, participant)
+		}
+	}
+	sort.Slice(participants, func(i, j int) bool {
+		return newestFirst(participants[i].CreatedAt, participants[j].CreatedAt, participants[i].ID, participants[j].ID)
+	})
+	return page(participants, 0, limit), nil
+}
+
+func (r *participantRepo) UpdateParticipant(participant *models.Participant) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.participants[participant.ID]
+	if !ok || existing.Version != participant.Version {
+		return repositories.ErrVersionConflict
+	}
+	if err := participant.BeforeSave(nil); err != nil {
+		return err
+	}
+
+	participant.Version++
+	participant.CreatedAt = existing.CreatedAt
+	participant.UpdatedAt = r.s.Now()
+
+	stored := *participant
+	stored.Event, stored.ActionLogs = models.Event{}, nil
+	r.s.participants[participant.ID] = stored
+	return nil
+}
+
+func (r *participantRepo) UpdatePaymentStatus(participantID, status string, version *int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	participant, ok := r.s.participants[parseID(participantID)]
+	if !ok || participant.DeletedAt.Valid || (version != nil && participant.Version != *version) {
+		if version != nil {
+			return repositories.ErrVersionConflict
+		}
+		return gorm.ErrRecordNotFound
+	}
+
+	participant.PaymentStatus = status
+	participant.Version++
+	participant.UpdatedAt = r.s.Now()
+	r.s.participants[participant.ID] = participant
+	return nil
+}
+
+func (r *participantRepo) RotateCredential(participantID string, fromVersion int, qrPath string, requireIDCheck bool, incident *models.CredentialIncident) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	participant, ok := r.s.participants[parseID(participantID)]
+	if !ok || participant.DeletedAt.Valid || participant.CredentialVersion != fromVersion {
+		return repositories.ErrVersionConflict
+	}
+
+	participant.CredentialVersion = fromVersion + 1
+	participant.QRPath = qrPath
+	participant.RequiresIDCheck = requireIDCheck
+	participant.Version++
+	participant.UpdatedAt = r.s.Now()
+	r.s.participants[participant.ID] = participant
+
+	r.s.stamp(&incident.ID, &incident.CreatedAt, nil)
+	r.s.incidents[incident.ID] = *incident
+	return nil
+}
+
+func (r *participantRepo) ListCredentialIncidents(participantID string) ([]models.CredentialIncident, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	incidents := []models.CredentialIncident{}
+	for _, incident := range r.s.incidents {
+		if incident.ParticipantID == parseID(participantID) {
+			incidents = append(incidents, incident)
+		}
+	}
+	sort.Slice(incidents, func(i, j int) bool { return incidents[i].CreatedAt.After(incidents[j].CreatedAt) })
+	return incidents, nil
+}
+
+func (r *participantRepo) ListParticipantRecords(participantID string) (*repositories.ParticipantRecords, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	id := parseID(participantID)
+	records := &repositories.ParticipantRecords{
+		WaiverSignatures: ownedRows(r.s.waiverSignatures, id, func(s models.WaiverSignature) (uuid.UUID, time.Time) { return s.ParticipantID, s.CreatedAt }),
+		Leads:            ownedRows(r.s.leads, id, func(l models.Lead) (uuid.UUID, time.Time) { return l.ParticipantID, l.CreatedAt }),
+		SeatAssignments:  ownedRows(r.s.seatAssignments, id, func(a models.SeatAssignment) (uuid.UUID, time.Time) { return a.ParticipantID, a.CreatedAt }),
+		WaitlistEntries: ownedRows(r.s.waitlist, id, func(e models.WaitlistEntry) (uuid.UUID, time.Time) {
+			if e.ParticipantID == nil {
+				return uuid.Nil, e.CreatedAt
+			}
+			return *e.ParticipantID, e.CreatedAt
+		}),
+		DrawWins: []models.DrawWinner{},
+	}
+	for _, draw := range r.s.draws {
+		for _, winner := range draw.Winners {
+			if winner.ParticipantID == id {
+				winner.Participant = models.Participant{}
+				records.DrawWins = append(records.DrawWins, winner)
+			}
+		}
+	}
+	sort.Slice(records.DrawWins, func(i, j int) bool { return records.DrawWins[i].CreatedAt.Before(records.DrawWins[j].CreatedAt) })
+	return records, nil
+}
+
+// ownedRows returns the rows of a participant, oldest first
+func ownedRows[T any](rows map[uuid.UUID]T, participantID uuid.UUID, owner func(T) (uuid.UUID, time.Time)) []T {
+	owned := []T{}
+	for _, row := range rows {
+		if id, _ := owner(row); id == participantID {
+			owned = append(owned, row)
+		}
+	}
+	sort.Slice(owned, func(i, j int) bool {
+		_, a := owner(owned[i])
+		_, b := owner(owned[j])
+		return a.Before(b)
+	})
+	return owned
+}
+
+func (r *participantRepo) CreateParticipantNote(note *models.ParticipantNote) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&note.ID, &note.CreatedAt, nil)
+	stored := *note
+	stored.Author = models.User{}
+	r.s.notes[note.ID] = stored
+	return nil
+}
+
+func (r *participantRepo) ListParticipantNotes(participantID string) ([]models.ParticipantNote, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	notes := []models.ParticipantNote{}
+	for _, note := range r.s.notes {
+		if note.ParticipantID == parseID(participantID) {
+			note.Author = r.s.users[note.AuthorID]
+			notes = append(notes, note)
+		}
+	}
+	sort.Slice(notes, func(i, j int) bool { return notes[i].CreatedAt.After(notes[j].CreatedAt) })
+	return notes, nil
+}
+
+func (r *participantRepo) TransferParticipant(participant *models.Participant, limit *int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.events[participant.EventID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if limit != nil && r.s.ticketHolders(participant.EventID) >= *limit {
+		return repositories.ErrTicketQuotaExceeded
+	}
+	if err := r.s.checkTicketTypeQuotas(participant.EventID, []models.Participant{*participant}); err != nil {
+		return err
+	}
+	if err := r.s.checkQuotaRules(participant.EventID, []models.Participant{*participant}); err != nil {
+		return err
+	}
+	existing, ok := r.s.participants[participant.ID]
+	if !ok || existing.Version != participant.Version {
+		return repositories.ErrVersionConflict
+	}
+	if err := participant.BeforeSave(nil); err != nil {
+		return err
+	}
+	for id, seat := range r.s.seatAssignments {
+		if seat.ParticipantID == participant.ID {
+			delete(r.s.seatAssignments, id)
+		}
+	}
+
+	participant.Version++
+	participant.CreatedAt = existing.CreatedAt
+	participant.UpdatedAt = r.s.Now()
+	stored := *participant
+	stored.Event, stored.ActionLogs = models.Event{}, nil
+	r.s.participants[participant.ID] = stored
+	return nil
+}
+
+func (r *participantRepo) MergeParticipants(survivor, duplicate *models.Participant) (*repositories.MergeCounts, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.participants[survivor.ID]
+	if !ok || existing.Version != survivor.Version {
+		return nil, repositories.ErrVersionConflict
+	}
+	stale, ok := r.s.participants[duplicate.ID]
+	if !ok || stale.DeletedAt.Valid || stale.Version != duplicate.Version {
+		return nil, repositories.ErrVersionConflict
+	}
+	if err := survivor.BeforeSave(nil); err != nil {
+		return nil, err
+	}
+
+	counts := &repositories.MergeCounts{}
+	from, to := duplicate.ID, survivor.ID
+	counts.ActionLogsMoved, counts.ActionLogsDropped = moveRows(r.s.actionLogs,
+		func(l *models.ActionLog) *uuid.UUID { return &l.ParticipantID },
+		func(l models.ActionLog) uuid.UUID { return l.ActionID }, from, to)
+	for _, moveTable := range []func() (int64, int64){
+		func() (int64, int64) {
+			return moveRows(r.s.leads, func(l *models.Lead) *uuid.UUID { return &l.ParticipantID },
+				func(l models.Lead) uuid.UUID { return l.SponsorID }, from, to)
+		},
+		func() (int64, int64) {
+			return moveRows(r.s.waiverSignatures, func(s *models.WaiverSignature) *uuid.UUID { return &s.ParticipantID },
+				func(s models.WaiverSignature) uuid.UUID { return s.WaiverID }, from, to)
+		},
+		func() (int64, int64) {
+			return moveRows(r.s.seatAssignments, func(a *models.SeatAssignment) *uuid.UUID { return &a.ParticipantID },
+				func(a models.SeatAssignment) uuid.UUID { return a.EventDayID }, from, to)
+		},
+		func() (int64, int64) {
+			return moveRows(r.s.incidents, func(i *models.CredentialIncident) *uuid.UUID { return &i.ParticipantID }, nil, from, to)
+		},
+		func() (int64, int64) {
+			return moveRows(r.s.notes, func(n *models.ParticipantNote) *uuid.UUID { return &n.ParticipantID }, nil, from, to)
+		},
+	} {
+		moved, dropped := moveTable()
+		counts.RecordsMoved += moved
+		counts.RecordsDropped += dropped
+	}
+	for id, draw := range r.s.draws {
+		for i := range draw.Winners {
+			if draw.Winners[i].ParticipantID == from {
+				draw.Winners[i].ParticipantID = to
+				counts.RecordsMoved++
+			}
+		}
+		r.s.draws[id] = draw
+	}
+
+	now := r.s.Now()
+	survivor.Version++
+	survivor.CreatedAt = existing.CreatedAt
+	survivor.UpdatedAt = now
+	stored := *survivor
+	stored.Event, stored.ActionLogs = models.Event{}, nil
+	r.s.participants[survivor.ID] = stored
+
+	stale.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	r.s.participants[stale.ID] = stale
+	return counts, nil
+}
+
+// moveRows hands the rows of one participant over to another, dropping rows
+// whose key matches a row the other participant already has. A nil key moves
+// every row.
+func moveRows[T any](rows map[uuid.UUID]T, owner func(*T) *uuid.UUID, key func(T) uuid.UUID, from, to uuid.UUID) (moved, dropped int64) {
+	taken := map[uuid.UUID]bool{}
+	if key != nil {
+		for _, row := range rows {
+			if *owner(&row) == to {
+				taken[key(row)] = true
+			}
+		}
+	}
+	for id, row := range rows {
+		if *owner(&row) != from {
+			continue
+		}
+		if key != nil && taken[key(row)] {
+			delete(rows, id)
+			dropped++
+			continue
+		}
+		*owner(&row) = to
+		rows[id] = row
+		moved++
+	}
+	return moved, dropped
+}
+
+func (r *participantRepo) ClearIDCheck(participantID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	participant, ok := r.s.participants[parseID(participantID)]
+	if !ok {
+		return nil
+	}
+	participant.RequiresIDCheck = false
+	r.s.participants[participant.ID] = participant
+	return nil
+}
+
+func (r *participantRepo) ListParticipantsByApprovalStatus(eventID, status string, offset, limit int) ([]models.Participant, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	participants := []models.Participant{}
+	for _, participant := range r.s.eventParticipants(parseID(eventID)) {
+		if participant.ApprovalStatus == status {
+			participants = append(participants, participant)
+		}
+	}
+	sort.Slice(participants, func(i, j int) bool {
+		return participants[i].CreatedAt.Before(participants[j].CreatedAt)
+	})
+	return page(participants, offset, limit), int64(len(participants)), nil
+}
+
+func (r *participantRepo) UpdateApprovalStatus(eventID string, participantIDs []uuid.UUID, status, reason string, reviewerID uuid.UUID) ([]uuid.UUID, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := r.s.Now()
+	updated := []uuid.UUID{}
+	for _, id := range participantIDs {
+		participant, ok := r.s.participants[id]
+		if !ok || participant.DeletedAt.Valid || participant.EventID != parseID(eventID) {
+			continue
+		}
+		participant.ApprovalStatus = status
+		participant.RejectionReason = reason
+		participant.ReviewedBy = &reviewerID
+		participant.ReviewedAt = &now
+		participant.Version++
+		participant.UpdatedAt = now
+		r.s.participants[id] = participant
+		updated = append(updated, id)
+	}
+	return updated, nil
+}
+
+func (r *participantRepo) DeleteUnconfirmedParticipants(before time.Time, limit int) ([]models.Participant, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	participants := []models.Participant{}
+	for _, participant := range r.s.participants {
+		if participant.DeletedAt.Valid || participant.ApprovalStatus != "pending_confirmation" ||
+			participant.ConfirmationExpiresAt == nil || !participant.ConfirmationExpiresAt.Before(before) {
+			continue
+		}
+		participants = append(participants, participant)
+	}
+	sort.Slice(participants, func(i, j int) bool {
+		return participants[i].ConfirmationExpiresAt.Before(*participants[j].ConfirmationExpiresAt)
+	})
+	if len(participants) > limit {
+		participants = participants[:limit]
+	}
+
+	now := r.s.Now()
+	for _, participant := range participants {
+		for id, seat := range r.s.seatAssignments {
+			if seat.ParticipantID == participant.ID {
+				delete(r.s.seatAssignments, id)
+			}
+		}
+		participant.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+		r.s.participants[participant.ID] = participant
+	}
+	return participants, nil
+}
+
+func (r *participantRepo) UpdateParticipantTags(eventID string, participantIDs []uuid.UUID, add, remove []string, maxTags int) ([]uuid.UUID, []uuid.UUID, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	updated, overLimit := []uuid.UUID{}, []uuid.UUID{}
+	for _, id := range participantIDs {
+		participant, ok := r.s.participants[id]
+		if !ok || participant.DeletedAt.Valid || participant.EventID != parseID(eventID) {
+			continue
+		}
+		tags := models.MergeTags(participant.Tags, add, remove)
+		if len(tags) > maxTags {
+			overLimit = append(overLimit, id)
+			continue
+		}
+		participant.Tags = tags
+		participant.Version++
+		participant.UpdatedAt = r.s.Now()
+		r.s.participants[id] = participant
+		updated = append(updated, id)
+	}
+	return updated, overLimit, nil
+}
+
+func (r *participantRepo) ListCancellationRecipients(eventID string, limit int) ([]models.Participant, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	participants := []models.Participant{}
+	for _, participant := range r.s.eventParticipants(parseID(eventID)) {
+		if participant.CancelledAt != nil && participant.CancellationNoticeSentAt == nil {
+			participants = append(participants, participant)
+		}
+	}
+	sort.Slice(participants, func(i, j int) bool {
+		if participants[i].CreatedAt.Equal(participants[j].CreatedAt) {
+			return participants[i].ID.String() < participants[j].ID.String()
+		}
+		return participants[i].CreatedAt.Before(participants[j].CreatedAt)
+	})
+	if len(participants) > limit {
+		participants = participants[:limit]
+	}
+	return participants, nil
+}
+
+func (r *participantRepo) MarkCancellationNoticeSent(participantID string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	participant, ok := r.s.participants[parseID(participantID)]
+	if !ok {
+		return nil
+	}
+	participant.CancellationNoticeSentAt = &at
+	r.s.participants[participant.ID] = participant
+	return nil
+}
+
+// Transaction runs txFunc without isolation or rollback. Services only use
+// the transaction as a scope and go through the repositories, which the
+// store serializes on its own.
+func (r *participantRepo) Transaction(txFunc func(*gorm.DB) error) error {
+	return txFunc(nil)
+}
+
+// eventParticipants returns the live participants of an event. The caller
+// must hold the lock.
+func (s *Store) eventParticipants(eventID uuid.UUID) []models.Participant {
+	participants := []models.Participant{}
+	for _, participant := range s.participants {
+		if participant.EventID == eventID && !participant.DeletedAt.Valid {
+			participants = append(participants, participant)
+		}
+	}
+	return participants
+}
+
+// ticketHolders counts the registrations of an event that are not cancelled;
+// the caller holds the lock
+func (s *Store) ticketHolders(eventID uuid.UUID) int {
+	n := 0
+	for _, participant := range s.eventParticipants(eventID) {
+		if participant.CancelledAt == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// checkTicketTypeQuotas refuses participants for ticket types with not
+// enough left of their quota. The caller must hold the lock.
+func (s *Store) checkTicketTypeQuotas(eventID uuid.UUID, participants []models.Participant) error {
+	wanted := make(map[uuid.UUID]int)
+	for _, participant := range participants {
+		if participant.TicketTypeID != nil {
+			wanted[*participant.TicketTypeID]++
+		}
+	}
+	for _, participant := range s.eventParticipants(eventID) {
+		if participant.TicketTypeID != nil && participant.CancelledAt == nil && wanted[*participant.TicketTypeID] > 0 {
+			wanted[*participant.TicketTypeID]++
+		}
+	}
+	for id, count := range wanted {
+		ticketType, ok := s.ticketTypes[id]
+		if ok && ticketType.EventID == eventID && ticketType.Quota != nil && count > *ticketType.Quota {
+			return repositories.ErrTicketTypeSoldOut
+		}
+	}
+	return nil
+}
+
+// checkQuotaRules refuses participants going over a quota rule of their
+// event. The caller must hold the lock.
+func (s *Store) checkQuotaRules(eventID uuid.UUID, participants []models.Participant) error {
+	for _, rule := range s.quotaRules {
+		if rule.EventID != eventID {
+			continue
+		}
+		wanted := 0
+		for _, participant := range participants {
+			if rule.Applies(participant.TicketTypeID, participant.Division) {
+				wanted++
+			}
+		}
+		if wanted > 0 && int(s.ruleRegistrations(&rule))+wanted > rule.MaxRegistrations {
+			return fmt.Errorf("%w: %s", repositories.ErrQuotaRuleReached, rule.Name)
+		}
+	}
+	return nil
+}
+
+// matchesParticipantFilters mirrors the SQL filters: part of the name, or the
+// whole email or phone number
+func matchesParticipantFilters(participant *models.Participant, filters *repositories.ParticipantFilters) bool {
+	if filters == nil {
+		return true
+	}
+	if search := strings.TrimSpace(filters.Search); search != "" {
+		digits := models.PhoneDigits(search)
+		if !strings.Contains(strings.ToLower(participant.Name), strings.ToLower(search)) &&
+			!strings.EqualFold(participant.Email, search) &&
+			(digits == "" || models.PhoneDigits(participant.Phone) != digits) {
+			return false
+		}
+	}
+	if filters.PaymentStatus != "" && participant.PaymentStatus != filters.PaymentStatus {
+		return false
+	}
+	if filters.Division != "" && !strings.EqualFold(participant.Division, strings.TrimSpace(filters.Division)) {
+		return false
+	}
+	if filters.RegisteredAfter != nil && participant.CreatedAt.Before(*filters.RegisteredAfter) {
+		return false
+	}
+	if filters.RegisteredBefore != nil && participant.CreatedAt.After(*filters.RegisteredBefore) {
+		return false
+	}
+	if filters.Tag != "" && !participant.HasTag(filters.Tag) {
+		return false
+	}
+	for key, value := range filters.Answers {
+		answer, ok := participant.Answers[key]
+		if !ok || !strings.EqualFold(models.AnswerText(answer), strings.TrimSpace(value)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type quotaRuleRepo struct {
+	s *Store
+}
+
+func (r *quotaRuleRepo) CreateQuotaRule(rule *models.QuotaRule) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+	r.s.quotaRules[rule.ID] = *rule
+	return nil
+}
+
+func (r *quotaRuleRepo) GetQuotaRule(id string) (*models.QuotaRule, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	rule, ok := r.s.quotaRules[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &rule, nil
+}
+
+func (r *quotaRuleRepo) ListQuotaRulesByEvent(eventID string) ([]models.QuotaRule, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	rules := []models.QuotaRule{}
+	for _, rule := range r.s.quotaRules {
+		if rule.EventID == parseID(eventID) {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+	return rules, nil
+}
+
+func (r *quotaRuleRepo) UpdateQuotaRule(rule *models.QuotaRule) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.quotaRules[rule.ID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	rule.EventID = existing.EventID
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = r.s.Now()
+	r.s.quotaRules[rule.ID] = *rule
+	return nil
+}
+
+func (r *quotaRuleRepo) DeleteQuotaRule(id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ruleID := parseID(id)
+	if _, ok := r.s.quotaRules[ruleID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.s.quotaRules, ruleID)
+	return nil
+}
+
+func (r *quotaRuleRepo) CountRuleRegistrations(rule *models.QuotaRule) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.s.ruleRegistrations(rule), nil
+}
+
+// ruleRegistrations counts the registrations a quota rule applies to; the
+// caller holds the lock
+func (s *Store) ruleRegistrations(rule *models.QuotaRule) int64 {
+	var count int64
+	for _, participant := range s.eventParticipants(rule.EventID) {
+		if participant.CancelledAt != nil {
+			continue
+		}
+		if rule.TicketTypeID != nil && (participant.TicketTypeID == nil || *participant.TicketTypeID != *rule.TicketTypeID) {
+			continue
+		}
+		if rule.Division != "" && !strings.EqualFold(participant.Division, rule.Division) {
+			continue
+		}
+		count++
+	}
+	return count
+}
@@ -0,0 +1,233 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type retentionRepo struct {
+	s *Store
+}
+
+func (r *retentionRepo) CreateRule(rule *models.RetentionRule) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&rule.ID, &rule.CreatedAt, nil)
+	r.s.retentionRules[rule.ID] = *rule
+	return nil
+}
+
+func (r *retentionRepo) ListRules() ([]models.RetentionRule, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	rules := []models.RetentionRule{}
+	for _, rule := range r.s.retentionRules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].CreatedAt.Before(rules[j].CreatedAt) })
+	return rules, nil
+}
+
+func (r *retentionRepo) DeleteRule(id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.retentionRules[parseID(id)]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.s.retentionRules, parseID(id))
+	return nil
+}
+
+func (r *retentionRepo) CreateLegalHold(hold *models.LegalHold) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.legalHolds {
+		if existing.EventID == hold.EventID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+
+	r.s.stamp(&hold.ID, &hold.CreatedAt, nil)
+	r.s.legalHolds[hold.ID] = *hold
+	return nil
+}
+
+func (r *retentionRepo) ListLegalHolds() ([]models.LegalHold, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	holds := []models.LegalHold{}
+	for _, hold := range r.s.legalHolds {
+		holds = append(holds, hold)
+	}
+	sort.Slice(holds, func(i, j int) bool { return holds[i].CreatedAt.Before(holds[j].CreatedAt) })
+	return holds, nil
+}
+
+func (r *retentionRepo) DeleteLegalHold(eventID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, hold := range r.s.legalHolds {
+		if hold.EventID == parseID(eventID) {
+			delete(r.s.legalHolds, id)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *retentionRepo) ListEventsEndedBefore(cutoff time.Time) ([]models.Event, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	events := []models.Event{}
+	for _, event := range r.s.events {
+		if event.EndsAt.Before(cutoff) {
+			events = append(events, event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].EndsAt.Before(events[j].EndsAt) })
+	return events, nil
+}
+
+func (r *retentionRepo) CountPendingAnonymization(eventID string) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var count int64
+	for _, participant := range r.s.participants {
+		if participant.EventID == parseID(eventID) && participant.AnonymizedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *retentionRepo) AnonymizeParticipants(eventID string, at time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var anonymized int64
+	for id, participant := range r.s.participants {
+		if participant.EventID == parseID(eventID) && r.s.anonymize(id, at) {
+			anonymized++
+		}
+	}
+	return anonymized, nil
+}
+
+func (r *retentionRepo) AnonymizeParticipant(participantID string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if !r.s.anonymize(parseID(participantID), at) {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// anonymize erases the personal data of a participant, see
+// repositories.RetentionRepository. It reports false when the participant
+// does not exist or was anonymized already. The caller must hold the lock.
+func (s *Store) anonymize(participantID uuid.UUID, at time.Time) bool {
+	participant, ok := s.participants[participantID]
+	if !ok || participant.AnonymizedAt != nil {
+		return false
+	}
+	participant.Name = repositories.AnonymizedName
+	participant.Email = ""
+	participant.EmailHash = ""
+	participant.PhoneHash = ""
+	participant.Phone = ""
+	participant.Address = ""
+	participant.Division = ""
+	participant.Answers = nil
+	participant.RejectionReason = ""
+	participant.CancellationReason = ""
+	participant.AnonymizedAt = &at
+	participant.Version++
+	s.participants[participantID] = participant
+
+	for id, note := range s.notes {
+		if note.ParticipantID == participantID {
+			delete(s.notes, id)
+		}
+	}
+	for id, lead := range s.leads {
+		if lead.ParticipantID == participantID {
+			lead.Notes = ""
+			s.leads[id] = lead
+		}
+	}
+	for id, entry := range s.waitlist {
+		if entry.ParticipantID != nil && *entry.ParticipantID == participantID {
+			entry.Name = repositories.AnonymizedName
+			entry.Email, entry.EmailHash, entry.Phone = "", "", ""
+			entry.Address, entry.Division, entry.Answers = "", "", nil
+			s.waitlist[id] = entry
+		}
+	}
+	for id, signature := range s.waiverSignatures {
+		if signature.ParticipantID == participantID {
+			signature.TypedName = ""
+			signature.IPAddress = ""
+			signature.UserAgent = ""
+			s.waiverSignatures[id] = signature
+		}
+	}
+	return true
+}
+
+func (r *retentionRepo) CountQRFiles(eventID string) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var count int64
+	for _, participant := range r.s.participants {
+		if participant.EventID == parseID(eventID) && participant.QRPath != "" {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *retentionRepo) ListQRFiles(eventID string, limit int) ([]models.Participant, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	participants := []models.Participant{}
+	for _, participant := range r.s.participants {
+		if participant.EventID == parseID(eventID) && participant.QRPath != "" {
+			participants = append(participants, models.Participant{ID: participant.ID, QRPath: participant.QRPath})
+		}
+	}
+	sort.Slice(participants, func(i, j int) bool { return participants[i].ID.String() < participants[j].ID.String() })
+	if len(participants) > limit {
+		participants = participants[:limit]
+	}
+	return participants, nil
+}
+
+func (r *retentionRepo) ClearQRPaths(ids []uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, id := range ids {
+		if participant, ok := r.s.participants[id]; ok {
+			participant.QRPath = ""
+			r.s.participants[id] = participant
+		}
+	}
+	return nil
+}
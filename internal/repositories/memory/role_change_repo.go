@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type roleChangeRepo struct {
+	s *Store
+}
+
+func (r *roleChangeRepo) CreateRoleChange(change *models.RoleChangeRequest) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&change.ID, &change.CreatedAt, nil)
+	if change.Status == "" {
+		change.Status = "pending"
+	}
+	stored := *change
+	stored.User, stored.Requester = nil, nil
+	r.s.roleChanges[change.ID] = stored
+	return nil
+}
+
+// withUsers fills the relations of a change; the caller holds the lock
+func (r *roleChangeRepo) withUsers(change models.RoleChangeRequest) models.RoleChangeRequest {
+	if user, ok := r.s.users[change.UserID]; ok {
+		change.User = &user
+	}
+	if requester, ok := r.s.users[change.RequestedBy]; ok {
+		change.Requester = &requester
+	}
+	return change
+}
+
+func (r *roleChangeRepo) GetRoleChange(id string) (*models.RoleChangeRequest, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	change, ok := r.s.roleChanges[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	change = r.withUsers(change)
+	return &change, nil
+}
+
+func (r *roleChangeRepo) HasPendingRoleChange(userID string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	id := parseID(userID)
+	for _, change := range r.s.roleChanges {
+		if change.UserID == id && change.Status == "pending" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *roleChangeRepo) ListRoleChanges(status string, offset, limit int) ([]models.RoleChangeRequest, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	changes := []models.RoleChangeRequest{}
+	for _, change := range r.s.roleChanges {
+		if status == "" || change.Status == status {
+			changes = append(changes, r.withUsers(change))
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return newestFirst(changes[i].CreatedAt, changes[j].CreatedAt, changes[i].ID, changes[j].ID)
+	})
+	return page(changes, offset, limit), int64(len(changes)), nil
+}
+
+func (r *roleChangeRepo) ReviewRoleChange(id string, reviewerID uuid.UUID, approve bool, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	change, ok := r.s.roleChanges[parseID(id)]
+	if !ok || change.Status != "pending" {
+		return gorm.ErrRecordNotFound
+	}
+
+	change.Status = "rejected"
+	if approve {
+		user, ok := r.s.users[change.UserID]
+		if !ok || user.DeletedAt.Valid || user.Role != change.FromRole {
+			return repositories.ErrVersionConflict
+		}
+		user.Role = change.ToRole
+		user.TokensRevokedAt = &at
+		user.UpdatedAt = r.s.Now()
+		r.s.users[user.ID] = user
+		change.Status = "approved"
+	}
+	change.ReviewedBy = &reviewerID
+	change.ReviewedAt = &at
+	r.s.roleChanges[change.ID] = change
+	return nil
+}
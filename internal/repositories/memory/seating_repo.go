@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"sort"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type seatingRepo struct {
+	s *Store
+}
+
+func (r *seatingRepo) CreateTable(table *models.SeatingTable) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&table.ID, &table.CreatedAt, &table.UpdatedAt)
+	r.s.seatingTables[table.ID] = *table
+	return nil
+}
+
+func (r *seatingRepo) GetTableByID(id string) (*models.SeatingTable, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	table, ok := r.s.seatingTables[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &table, nil
+}
+
+func (r *seatingRepo) ListTablesByDay(dayID string) ([]models.SeatingTable, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	tables := []models.SeatingTable{}
+	for _, table := range r.s.seatingTables {
+		if table.EventDayID == parseID(dayID) {
+			tables = append(tables, table)
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+	return tables, nil
+}
+
+func (r *seatingRepo) AssignSeat(assignment *models.SeatAssignment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	table, ok := r.s.seatingTables[assignment.TableID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	taken := []int{}
+	for _, existing := range r.s.seatAssignments {
+		if existing.TableID == table.ID && existing.ParticipantID != assignment.ParticipantID {
+			taken = append(taken, existing.SeatNumber)
+		}
+	}
+	seat, err := repositories.PickSeat(table.Capacity, taken, assignment.SeatNumber)
+	if err != nil {
+		return err
+	}
+	assignment.SeatNumber = seat
+
+	r.deleteAssignment(assignment.EventDayID.String(), assignment.ParticipantID.String())
+	r.insertAssignment(assignment)
+	return nil
+}
+
+func (r *seatingRepo) CreateAssignments(assignments []models.SeatAssignment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, assignment := range assignments {
+		for _, existing := range r.s.seatAssignments {
+			if (existing.EventDayID == assignment.EventDayID && existing.ParticipantID == assignment.ParticipantID) ||
+				(existing.TableID == assignment.TableID && existing.SeatNumber == assignment.SeatNumber) {
+				return gorm.ErrDuplicatedKey
+			}
+		}
+	}
+	for i := range assignments {
+		r.insertAssignment(&assignments[i])
+	}
+	return nil
+}
+
+func (r *seatingRepo) DeleteAssignment(dayID, participantID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.deleteAssignment(dayID, participantID)
+	return nil
+}
+
+func (r *seatingRepo) GetAssignment(dayID, participantID string) (*models.SeatAssignment, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, assignment := range r.s.seatAssignments {
+		if assignment.EventDayID == parseID(dayID) && assignment.ParticipantID == parseID(participantID) {
+			assignment.Table = r.s.seatingTables[assignment.TableID]
+			return &assignment, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *seatingRepo) ListAssignmentsByDay(dayID string) ([]models.SeatAssignment, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.assignments(func(assignment models.SeatAssignment) bool {
+		return assignment.EventDayID == parseID(dayID)
+	}), nil
+}
+
+func (r *seatingRepo) ListAssignmentsByEvent(eventID string) ([]models.SeatAssignment, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.assignments(func(assignment models.SeatAssignment) bool {
+		return r.s.seatingTables[assignment.TableID].EventID == parseID(eventID)
+	}), nil
+}
+
+// insertAssignment stores an assignment without its relations. The caller
+// must hold the lock.
+func (r *seatingRepo) insertAssignment(assignment *models.SeatAssignment) {
+	r.s.stamp(&assignment.ID, &assignment.CreatedAt, nil)
+	stored := *assignment
+	stored.Table, stored.Participant = models.SeatingTable{}, models.Participant{}
+	r.s.seatAssignments[assignment.ID] = stored
+}
+
+// deleteAssignment removes the seat of a participant for a day. The caller
+// must hold the lock.
+func (r *seatingRepo) deleteAssignment(dayID, participantID string) {
+	for id, assignment := range r.s.seatAssignments {
+		if assignment.EventDayID == parseID(dayID) && assignment.ParticipantID == parseID(participantID) {
+			delete(r.s.seatAssignments, id)
+		}
+	}
+}
+
+// assignments returns the matching assignments with their relations, by seat
+// number. The caller must hold the lock.
+func (r *seatingRepo) assignments(match func(models.SeatAssignment) bool) []models.SeatAssignment {
+	assignments := []models.SeatAssignment{}
+	for _, assignment := range r.s.seatAssignments {
+		if match(assignment) {
+			assignment.Table = r.s.seatingTables[assignment.TableID]
+			assignment.Participant = r.s.participants[assignment.ParticipantID]
+			assignments = append(assignments, assignment)
+		}
+	}
+	sort.Slice(assignments, func(i, j int) bool {
+		return assignments[i].SeatNumber < assignments[j].SeatNumber
+	})
+	return assignments
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type sessionRepo struct {
+	s *Store
+}
+
+func (r *sessionRepo) CreateSession(session *models.Session, action *models.EventAction) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.actions {
+		if existing.Code == action.Code {
+			return fmt.Errorf("event action with code '%s' already exists", action.Code)
+		}
+	}
+
+	r.s.stamp(&action.ID, &action.CreatedAt, &action.UpdatedAt)
+	r.s.actions[action.ID] = *action
+
+	session.ActionID = action.ID
+	r.s.stamp(&session.ID, &session.CreatedAt, &session.UpdatedAt)
+	stored := *session
+	stored.Action = models.EventAction{}
+	r.s.sessions[session.ID] = stored
+	return nil
+}
+
+func (r *sessionRepo) GetSessionByID(id string) (*models.Session, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	session, ok := r.s.sessions[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	session.Action = r.s.actions[session.ActionID]
+	return &session, nil
+}
+
+func (r *sessionRepo) GetSessionByActionID(actionID string) (*models.Session, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, session := range r.s.sessions {
+		if session.ActionID == parseID(actionID) {
+			return &session, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *sessionRepo) ListSessionsByEvent(eventID string) ([]models.Session, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	sessions := []models.Session{}
+	for _, session := range r.s.sessions {
+		if session.EventID == parseID(eventID) {
+			session.Action = r.s.actions[session.ActionID]
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].StartsAt.Equal(sessions[j].StartsAt) {
+			return sessions[i].Title < sessions[j].Title
+		}
+		return sessions[i].StartsAt.Before(sessions[j].StartsAt)
+	})
+	return sessions, nil
+}
+
+func (r *sessionRepo) CreateSessionCheckIn(session *models.Session, log *models.ActionLog) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.sessions[session.ID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	if stored.Capacity != nil {
+		attendance := 0
+		for _, existing := range r.s.actionLogs {
+			if existing.ActionID == stored.ActionID {
+				attendance++
+			}
+		}
+		if attendance >= *stored.Capacity {
+			return repositories.ErrSessionFull
+		}
+	}
+	if r.s.dayFull(log) {
+		return repositories.ErrEventDayFull
+	}
+
+	r.s.stamp(&log.ID, &log.CreatedAt, nil)
+	entry := *log
+	entry.Participant, entry.Action, entry.Verifier = models.Participant{}, models.EventAction{}, models.User{}
+	r.s.actionLogs[log.ID] = entry
+	return nil
+}
+
+func (r *sessionRepo) GetSessionAttendance(session *models.Session) ([]models.ActionLog, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	logs := []models.ActionLog{}
+	for _, log := range r.s.actionLogs {
+		if log.ActionID == session.ActionID {
+			log.Participant = r.s.participants[log.ParticipantID]
+			logs = append(logs, log)
+		}
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].VerifiedAt.Before(logs[j].VerifiedAt) })
+	return logs, nil
+}
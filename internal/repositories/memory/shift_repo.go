@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type shiftRepo struct {
+	s *Store
+}
+
+func (r *shiftRepo) CreateShift(shift *models.Shift) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&shift.ID, &shift.CreatedAt, &shift.UpdatedAt)
+	stored := *shift
+	stored.Action, stored.Assignments = nil, nil
+	r.s.shifts[shift.ID] = stored
+	return nil
+}
+
+func (r *shiftRepo) GetShiftByID(id string) (*models.Shift, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	shift, ok := r.s.shifts[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	r.withRelations(&shift)
+	return &shift, nil
+}
+
+func (r *shiftRepo) ListShiftsByEvent(eventID string) ([]models.Shift, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.shifts(func(shift models.Shift) bool {
+		return shift.EventID == parseID(eventID)
+	}), nil
+}
+
+func (r *shiftRepo) ListShiftsByUser(userID string, endsAfter time.Time) ([]models.Shift, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	shifts := r.shifts(func(shift models.Shift) bool {
+		return shift.EndsAt.After(endsAfter) && r.assigned(shift.ID, parseID(userID))
+	})
+	for i := range shifts {
+		shifts[i].Assignments = nil
+	}
+	return shifts, nil
+}
+
+func (r *shiftRepo) DeleteShift(id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.shifts, parseID(id))
+	delete(r.s.shiftStaff, parseID(id))
+	return nil
+}
+
+func (r *shiftRepo) AssignStaff(shiftID, userID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	id, user := parseID(shiftID), parseID(userID)
+	if r.assigned(id, user) {
+		return nil
+	}
+	r.s.shiftStaff[id] = append(r.s.shiftStaff[id], models.ShiftAssignment{
+		ShiftID:   id,
+		UserID:    user,
+		CreatedAt: r.s.Now(),
+	})
+	return nil
+}
+
+func (r *shiftRepo) UnassignStaff(shiftID, userID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	id := parseID(shiftID)
+	kept := r.s.shiftStaff[id][:0]
+	for _, assignment := range r.s.shiftStaff[id] {
+		if assignment.UserID != parseID(userID) {
+			kept = append(kept, assignment)
+		}
+	}
+	r.s.shiftStaff[id] = kept
+	return nil
+}
+
+func (r *shiftRepo) HasShifts(eventID string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, shift := range r.s.shifts {
+		if shift.EventID == parseID(eventID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *shiftRepo) IsOnShift(userID string, action *models.EventAction, at time.Time, grace time.Duration) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, shift := range r.s.shifts {
+		if shift.EventDayID != action.EventDayID || !r.assigned(shift.ID, parseID(userID)) {
+			continue
+		}
+		if shift.ActionID != nil && *shift.ActionID != action.ID {
+			continue
+		}
+		if !shift.StartsAt.After(at.Add(grace)) && !shift.EndsAt.Before(at.Add(-grace)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// assigned reports whether a user works a shift. The caller must hold the
+// lock.
+func (r *shiftRepo) assigned(shiftID, userID uuid.UUID) bool {
+	for _, assignment := range r.s.shiftStaff[shiftID] {
+		if assignment.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// withRelations fills in the gate and staff of a shift. The caller must hold
+// the lock.
+func (r *shiftRepo) withRelations(shift *models.Shift) {
+	if shift.ActionID != nil {
+		action := r.s.actions[*shift.ActionID]
+		shift.Action = &action
+	}
+	shift.Assignments = []models.ShiftAssignment{}
+	for _, assignment := range r.s.shiftStaff[shift.ID] {
+		assignment.User = r.s.users[assignment.UserID]
+		shift.Assignments = append(shift.Assignments, assignment)
+	}
+}
+
+// shifts returns the matching shifts with their relations, in rota order.
+// The caller must hold the lock.
+func (r *shiftRepo) shifts(match func(models.Shift) bool) []models.Shift {
+	shifts := []models.Shift{}
+	for _, shift := range r.s.shifts {
+		if match(shift) {
+			r.withRelations(&shift)
+			shifts = append(shifts, shift)
+		}
+	}
+	sort.Slice(shifts, func(i, j int) bool {
+		if shifts[i].StartsAt.Equal(shifts[j].StartsAt) {
+			return shifts[i].Name < shifts[j].Name
+		}
+		return shifts[i].StartsAt.Before(shifts[j].StartsAt)
+	})
+	return shifts
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"sort"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type speakerRepo struct {
+	s *Store
+}
+
+func (r *speakerRepo) CreateSpeaker(speaker *models.Speaker, sessionIDs []uuid.UUID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&speaker.ID, &speaker.CreatedAt, &speaker.UpdatedAt)
+	stored := *speaker
+	stored.Sessions = nil
+	r.s.speakers[speaker.ID] = stored
+	r.s.speakerSessions[speaker.ID] = append([]uuid.UUID(nil), sessionIDs...)
+	return nil
+}
+
+func (r *speakerRepo) GetSpeakerByID(id string) (*models.Speaker, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	speaker, ok := r.s.speakers[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	speaker.Sessions = r.s.sessionsOf(speaker.ID)
+	return &speaker, nil
+}
+
+func (r *speakerRepo) ListSpeakersByEvent(eventID string) ([]models.Speaker, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	speakers := []models.Speaker{}
+	for _, speaker := range r.s.speakers {
+		if speaker.EventID == parseID(eventID) {
+			speaker.Sessions = r.s.sessionsOf(speaker.ID)
+			speakers = append(speakers, speaker)
+		}
+	}
+	sort.Slice(speakers, func(i, j int) bool { return speakers[i].Name < speakers[j].Name })
+	return speakers, nil
+}
+
+func (r *speakerRepo) UpdateSpeakerPhotoVariants(id string, variants map[string]string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if speaker, ok := r.s.speakers[parseID(id)]; ok {
+		speaker.PhotoVariants = variants
+		r.s.speakers[speaker.ID] = speaker
+	}
+	return nil
+}
+
+func (r *speakerRepo) CreateSpeakerCheckIn(checkIn *models.SpeakerCheckIn) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&checkIn.ID, &checkIn.CreatedAt, nil)
+	stored := *checkIn
+	stored.Action = models.EventAction{}
+	r.s.speakerCheckIns[checkIn.ID] = stored
+	return nil
+}
+
+func (r *speakerRepo) ListSpeakerCheckIns(speakerID string) ([]models.SpeakerCheckIn, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	checkIns := []models.SpeakerCheckIn{}
+	for _, checkIn := range r.s.speakerCheckIns {
+		if checkIn.SpeakerID == parseID(speakerID) {
+			checkIn.Action = r.s.actions[checkIn.ActionID]
+			checkIns = append(checkIns, checkIn)
+		}
+	}
+	sort.Slice(checkIns, func(i, j int) bool { return checkIns[i].VerifiedAt.After(checkIns[j].VerifiedAt) })
+	return checkIns, nil
+}
+
+// sessionsOf returns the sessions of a speaker in chronological order. The
+// caller must hold the lock.
+func (s *Store) sessionsOf(speakerID uuid.UUID) []models.Session {
+	sessions := []models.Session{}
+	for _, id := range s.speakerSessions[speakerID] {
+		if session, ok := s.sessions[id]; ok {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].StartsAt.Before(sessions[j].StartsAt) })
+	return sessions
+}
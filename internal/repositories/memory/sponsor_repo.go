@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"sort"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type sponsorRepo struct {
+	s *Store
+}
+
+func (r *sponsorRepo) CreateSponsor(sponsor *models.Sponsor) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&sponsor.ID, &sponsor.CreatedAt, &sponsor.UpdatedAt)
+	r.s.sponsors[sponsor.ID] = *sponsor
+	return nil
+}
+
+func (r *sponsorRepo) GetSponsorByID(id string) (*models.Sponsor, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	sponsor, ok := r.s.sponsors[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &sponsor, nil
+}
+
+func (r *sponsorRepo) ListSponsorsByEvent(eventID string) ([]models.Sponsor, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	sponsors := []models.Sponsor{}
+	for _, sponsor := range r.s.sponsors {
+		if sponsor.EventID == parseID(eventID) {
+			sponsors = append(sponsors, sponsor)
+		}
+	}
+	sort.Slice(sponsors, func(i, j int) bool { return sponsors[i].Name < sponsors[j].Name })
+	return sponsors, nil
+}
+
+func (r *sponsorRepo) SaveLead(lead *models.Lead) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.leads {
+		if existing.SponsorID == lead.SponsorID && existing.ParticipantID == lead.ParticipantID {
+			lead.ID = existing.ID
+			lead.CreatedAt = existing.CreatedAt
+			break
+		}
+	}
+
+	r.s.stamp(&lead.ID, &lead.CreatedAt, &lead.UpdatedAt)
+	lead.UpdatedAt = r.s.Now()
+	stored := *lead
+	stored.Participant = models.Participant{}
+	r.s.leads[lead.ID] = stored
+	return nil
+}
+
+func (r *sponsorRepo) ListLeadsBySponsor(sponsorID string) ([]models.Lead, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	leads := []models.Lead{}
+	for _, lead := range r.s.leads {
+		if lead.SponsorID == parseID(sponsorID) {
+			lead.Participant = r.s.participants[lead.ParticipantID]
+			leads = append(leads, lead)
+		}
+	}
+	sort.Slice(leads, func(i, j int) bool { return leads[i].CapturedAt.Before(leads[j].CapturedAt) })
+	return leads, nil
+}
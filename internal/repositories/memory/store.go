@@ -0,0 +1,253 @@
+// Package memory provides in-memory implementations of the repository
+// interfaces so services can be exercised without a database.
+//
+//	store := memory.New()
+//	repo := store.Repository()
+//	fx := store.Fixtures()
+//	event := fx.Event()
+//	svc := services.NewParticipantService(repo, cfg)
+//
+// Records are copied in and out of the store, so callers must go through the
+// repository to persist changes, just like with GORM. Relations are filled in
+// where the GORM repository preloads them.
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	_ repositories.EventRepository         = (*eventRepo)(nil)
+	_ repositories.UserRepository          = (*userRepo)(nil)
+	_ repositories.ParticipantRepository   = (*participantRepo)(nil)
+	_ repositories.ActionRepository        = (*actionRepo)(nil)
+	_ repositories.JobRepository           = (*jobRepo)(nil)
+	_ repositories.IdempotencyRepository   = (*idempotencyRepo)(nil)
+	_ repositories.DrawRepository          = (*drawRepo)(nil)
+	_ repositories.SessionRepository       = (*sessionRepo)(nil)
+	_ repositories.SpeakerRepository       = (*speakerRepo)(nil)
+	_ repositories.SponsorRepository       = (*sponsorRepo)(nil)
+	_ repositories.WaiverRepository        = (*waiverRepo)(nil)
+	_ repositories.SeatingRepository       = (*seatingRepo)(nil)
+	_ repositories.MealRepository          = (*mealRepo)(nil)
+	_ repositories.ShiftRepository         = (*shiftRepo)(nil)
+	_ repositories.OnlineRepository        = (*onlineRepo)(nil)
+	_ repositories.RetentionRepository     = (*retentionRepo)(nil)
+	_ repositories.APIKeyRepository        = (*apiKeyRepo)(nil)
+	_ repositories.TokenRepository         = (*tokenRepo)(nil)
+	_ repositories.EventStaffRepository    = (*eventStaffRepo)(nil)
+	_ repositories.AuditRepository         = (*auditRepo)(nil)
+	_ repositories.RoleChangeRepository    = (*roleChangeRepo)(nil)
+	_ repositories.DeviceRepository        = (*deviceRepo)(nil)
+	_ repositories.VenueRepository         = (*venueRepo)(nil)
+	_ repositories.TicketTypeRepository    = (*ticketTypeRepo)(nil)
+	_ repositories.EventImageRepository    = (*eventImageRepo)(nil)
+	_ repositories.QuotaRuleRepository     = (*quotaRuleRepo)(nil)
+	_ repositories.EventRevisionRepository = (*eventRevisionRepo)(nil)
+	_ repositories.ImportJobRepository     = (*importJobRepo)(nil)
+)
+
+// Store holds every table of the in-memory database. It is safe for
+// concurrent use.
+type Store struct {
+	mu sync.RWMutex
+
+	users        map[uuid.UUID]models.User
+	events       map[uuid.UUID]models.Event
+	days         map[uuid.UUID]models.EventDay
+	actions      map[uuid.UUID]models.EventAction
+	participants map[uuid.UUID]models.Participant
+	actionLogs   map[uuid.UUID]models.ActionLog
+	jobs         map[uuid.UUID]models.Job
+	idempotency  map[string]models.IdempotencyKey
+	draws        map[uuid.UUID]models.Draw
+	sessions     map[uuid.UUID]models.Session
+	speakers     map[uuid.UUID]models.Speaker
+	// Session IDs of each speaker, standing in for the speaker_sessions table
+	speakerSessions  map[uuid.UUID][]uuid.UUID
+	speakerCheckIns  map[uuid.UUID]models.SpeakerCheckIn
+	sponsors         map[uuid.UUID]models.Sponsor
+	leads            map[uuid.UUID]models.Lead
+	waivers          map[uuid.UUID]models.Waiver
+	waiverSignatures map[uuid.UUID]models.WaiverSignature
+	seatingTables    map[uuid.UUID]models.SeatingTable
+	seatAssignments  map[uuid.UUID]models.SeatAssignment
+	mealCoupons      map[uuid.UUID]models.MealCoupon
+	incidents        map[uuid.UUID]models.CredentialIncident
+	notes            map[uuid.UUID]models.ParticipantNote
+	shifts           map[uuid.UUID]models.Shift
+	// Staff of each shift, standing in for the shift_assignments table
+	shiftStaff     map[uuid.UUID][]models.ShiftAssignment
+	onlineMeetings map[uuid.UUID]models.OnlineMeeting
+	retentionRules map[uuid.UUID]models.RetentionRule
+	legalHolds     map[uuid.UUID]models.LegalHold
+	apiKeys        map[uuid.UUID]models.APIKey
+	revokedTokens  map[string]models.RevokedToken
+	eventStaff     map[eventStaffKey]models.EventStaff
+	auditLogs      map[uuid.UUID]models.AuditLog
+	loginEvents    map[uuid.UUID]models.LoginEvent
+	serviceTokens  map[uuid.UUID]models.ServiceToken
+	refreshTokens  map[uuid.UUID]models.RefreshToken
+	roleChanges    map[uuid.UUID]models.RoleChangeRequest
+	devices        map[uuid.UUID]models.Device
+	venues         map[uuid.UUID]models.Venue
+	ticketTypes    map[uuid.UUID]models.TicketType
+	eventImages    map[uuid.UUID]models.EventImage
+	quotaRules     map[uuid.UUID]models.QuotaRule
+	eventRevisions map[uuid.UUID]models.EventRevision
+	importJobs     map[uuid.UUID]models.ImportJob
+	waitlist       map[uuid.UUID]models.WaitlistEntry
+
+	// Now is used for timestamps; override it for deterministic results
+	Now func() time.Time
+}
+
+// New returns an empty store
+func New() *Store {
+	return &Store{
+		users:        make(map[uuid.UUID]models.User),
+		events:       make(map[uuid.UUID]models.Event),
+		days:         make(map[uuid.UUID]models.EventDay),
+		actions:      make(map[uuid.UUID]models.EventAction),
+		participants: make(map[uuid.UUID]models.Participant),
+		actionLogs:   make(map[uuid.UUID]models.ActionLog),
+		jobs:         make(map[uuid.UUID]models.Job),
+		idempotency:  make(map[string]models.IdempotencyKey),
+		draws:        make(map[uuid.UUID]models.Draw),
+		sessions:     make(map[uuid.UUID]models.Session),
+		speakers:     make(map[uuid.UUID]models.Speaker),
+
+		speakerSessions:  make(map[uuid.UUID][]uuid.UUID),
+		speakerCheckIns:  make(map[uuid.UUID]models.SpeakerCheckIn),
+		sponsors:         make(map[uuid.UUID]models.Sponsor),
+		leads:            make(map[uuid.UUID]models.Lead),
+		waivers:          make(map[uuid.UUID]models.Waiver),
+		waiverSignatures: make(map[uuid.UUID]models.WaiverSignature),
+		seatingTables:    make(map[uuid.UUID]models.SeatingTable),
+		seatAssignments:  make(map[uuid.UUID]models.SeatAssignment),
+		mealCoupons:      make(map[uuid.UUID]models.MealCoupon),
+		incidents:        make(map[uuid.UUID]models.CredentialIncident),
+		notes:            make(map[uuid.UUID]models.ParticipantNote),
+		shifts:           make(map[uuid.UUID]models.Shift),
+		shiftStaff:       make(map[uuid.UUID][]models.ShiftAssignment),
+		onlineMeetings:   make(map[uuid.UUID]models.OnlineMeeting),
+		retentionRules:   make(map[uuid.UUID]models.RetentionRule),
+		legalHolds:       make(map[uuid.UUID]models.LegalHold),
+		apiKeys:          make(map[uuid.UUID]models.APIKey),
+		revokedTokens:    make(map[string]models.RevokedToken),
+		eventStaff:       make(map[eventStaffKey]models.EventStaff),
+		auditLogs:        make(map[uuid.UUID]models.AuditLog),
+		loginEvents:      make(map[uuid.UUID]models.LoginEvent),
+		serviceTokens:    make(map[uuid.UUID]models.ServiceToken),
+		refreshTokens:    make(map[uuid.UUID]models.RefreshToken),
+		roleChanges:      make(map[uuid.UUID]models.RoleChangeRequest),
+		devices:          make(map[uuid.UUID]models.Device),
+		venues:           make(map[uuid.UUID]models.Venue),
+		ticketTypes:      make(map[uuid.UUID]models.TicketType),
+		eventImages:      make(map[uuid.UUID]models.EventImage),
+		quotaRules:       make(map[uuid.UUID]models.QuotaRule),
+		eventRevisions:   make(map[uuid.UUID]models.EventRevision),
+		importJobs:       make(map[uuid.UUID]models.ImportJob),
+		waitlist:         make(map[uuid.UUID]models.WaitlistEntry),
+		Now:              time.Now,
+	}
+}
+
+// Repository returns a repositories.Repository backed by the store. Its DB
+// field is nil, so code paths that query the database directly are not
+// supported.
+func (s *Store) Repository() *repositories.Repository {
+	return &repositories.Repository{
+		EventRepo:         &eventRepo{s},
+		UserRepo:          &userRepo{s},
+		ParticipantRepo:   &participantRepo{s},
+		ActionRepo:        &actionRepo{s},
+		JobRepo:           &jobRepo{s},
+		IdempotencyRepo:   &idempotencyRepo{s},
+		DrawRepo:          &drawRepo{s},
+		SessionRepo:       &sessionRepo{s},
+		SpeakerRepo:       &speakerRepo{s},
+		SponsorRepo:       &sponsorRepo{s},
+		WaiverRepo:        &waiverRepo{s},
+		SeatingRepo:       &seatingRepo{s},
+		MealRepo:          &mealRepo{s},
+		ShiftRepo:         &shiftRepo{s},
+		OnlineRepo:        &onlineRepo{s},
+		RetentionRepo:     &retentionRepo{s},
+		APIKeyRepo:        &apiKeyRepo{s},
+		TokenRepo:         &tokenRepo{s},
+		EventStaffRepo:    &eventStaffRepo{s},
+		AuditRepo:         &auditRepo{s},
+		RoleChangeRepo:    &roleChangeRepo{s},
+		DeviceRepo:        &deviceRepo{s},
+		VenueRepo:         &venueRepo{s},
+		TicketTypeRepo:    &ticketTypeRepo{s},
+		EventImageRepo:    &eventImageRepo{s},
+		QuotaRuleRepo:     &quotaRuleRepo{s},
+		EventRevisionRepo: &eventRevisionRepo{s},
+		ImportJobRepo:     &importJobRepo{s},
+		WaitlistRepo:      &waitlistRepo{s},
+	}
+}
+
+// stamp fills the ID and timestamps the database would set on insert
+func (s *Store) stamp(id *uuid.UUID, createdAt, updatedAt *time.Time) {
+	now := s.Now()
+	if *id == uuid.Nil {
+		*id = uuid.New()
+	}
+	if createdAt != nil && createdAt.IsZero() {
+		*createdAt = now
+	}
+	if updatedAt != nil {
+		*updatedAt = now
+	}
+}
+
+// parseID turns a string ID into a map key. Invalid IDs simply match
+// nothing, like a WHERE id = ? query would.
+func parseID(id string) uuid.UUID {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil
+	}
+	return parsed
+}
+
+// page applies offset and limit to an already sorted slice
+func page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit >= 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// before reports whether (t, id) sorts before the cursor in a
+// "time DESC, id DESC" ordering, matching the SQL row comparison
+func before(t time.Time, id uuid.UUID, cursor *repositories.Cursor) bool {
+	if cursor == nil {
+		return true
+	}
+	if t.Equal(cursor.Time) {
+		return id.String() < cursor.ID.String()
+	}
+	return t.Before(cursor.Time)
+}
+
+// newestFirst orders by time DESC, id DESC
+func newestFirst(ti, tj time.Time, idi, idj uuid.UUID) bool {
+	if ti.Equal(tj) {
+		return idi.String() > idj.String()
+	}
+	return ti.After(tj)
+}
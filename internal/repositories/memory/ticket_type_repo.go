@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"sort"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ticketTypeRepo struct {
+	s *Store
+}
+
+func (r *ticketTypeRepo) CreateTicketType(ticketType *models.TicketType) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&ticketType.ID, &ticketType.CreatedAt, &ticketType.UpdatedAt)
+	r.s.ticketTypes[ticketType.ID] = *ticketType
+	return nil
+}
+
+func (r *ticketTypeRepo) GetTicketType(id string) (*models.TicketType, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	ticketType, ok := r.s.ticketTypes[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &ticketType, nil
+}
+
+func (r *ticketTypeRepo) ListTicketTypesByEvent(eventID string) ([]models.TicketType, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	ticketTypes := []models.TicketType{}
+	for _, ticketType := range r.s.ticketTypes {
+		if ticketType.EventID == parseID(eventID) {
+			ticketTypes = append(ticketTypes, ticketType)
+		}
+	}
+	sort.Slice(ticketTypes, func(i, j int) bool {
+		if ticketTypes[i].Price != ticketTypes[j].Price {
+			return ticketTypes[i].Price < ticketTypes[j].Price
+		}
+		return ticketTypes[i].Name < ticketTypes[j].Name
+	})
+	return ticketTypes, nil
+}
+
+func (r *ticketTypeRepo) UpdateTicketType(ticketType *models.TicketType) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.ticketTypes[ticketType.ID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	ticketType.EventID = existing.EventID
+	ticketType.CreatedAt = existing.CreatedAt
+	ticketType.UpdatedAt = r.s.Now()
+	r.s.ticketTypes[ticketType.ID] = *ticketType
+	return nil
+}
+
+func (r *ticketTypeRepo) DeleteTicketType(id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ticketTypeID := parseID(id)
+	if _, ok := r.s.ticketTypes[ticketTypeID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.s.ticketTypes, ticketTypeID)
+	return nil
+}
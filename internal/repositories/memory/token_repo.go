@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type tokenRepo struct {
+	s *Store
+}
+
+func (r *tokenRepo) RevokeToken(token *models.RevokedToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, exists := r.s.revokedTokens[token.JTI]; exists {
+		return nil
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = r.s.Now()
+	}
+	r.s.revokedTokens[token.JTI] = *token
+	return nil
+}
+
+func (r *tokenRepo) IsTokenRevoked(jti string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	_, revoked := r.s.revokedTokens[jti]
+	return revoked, nil
+}
+
+func (r *tokenRepo) DeleteExpiredRevokedTokens(now time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var deleted int64
+	for jti, token := range r.s.revokedTokens {
+		if token.ExpiresAt.Before(now) {
+			delete(r.s.revokedTokens, jti)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *tokenRepo) CreateServiceToken(token *models.ServiceToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&token.ID, &token.CreatedAt, nil)
+	r.s.serviceTokens[token.ID] = *token
+	return nil
+}
+
+func (r *tokenRepo) GetServiceToken(id string) (*models.ServiceToken, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	token, ok := r.s.serviceTokens[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &token, nil
+}
+
+func (r *tokenRepo) ListServiceTokens(userID string) ([]models.ServiceToken, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	id := parseID(userID)
+	tokens := []models.ServiceToken{}
+	for _, token := range r.s.serviceTokens {
+		if token.UserID == id {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return newestFirst(tokens[i].CreatedAt, tokens[j].CreatedAt, tokens[i].ID, tokens[j].ID)
+	})
+	return tokens, nil
+}
+
+func (r *tokenRepo) RevokeServiceToken(userID, id string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	token, ok := r.s.serviceTokens[parseID(id)]
+	if !ok || token.UserID != parseID(userID) {
+		return gorm.ErrRecordNotFound
+	}
+	if token.RevokedAt == nil {
+		token.RevokedAt = &at
+		r.s.serviceTokens[token.ID] = token
+	}
+	return nil
+}
+
+func (r *tokenRepo) CreateRefreshToken(token *models.RefreshToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&token.ID, &token.CreatedAt, nil)
+	r.s.refreshTokens[token.ID] = *token
+	return nil
+}
+
+func (r *tokenRepo) GetRefreshTokenByHash(hash string) (*models.RefreshToken, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, token := range r.s.refreshTokens {
+		if token.TokenHash == hash {
+			return &token, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *tokenRepo) RevokeRefreshToken(id string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	token, ok := r.s.refreshTokens[parseID(id)]
+	if !ok || token.RevokedAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	token.RevokedAt = &at
+	r.s.refreshTokens[token.ID] = token
+	return nil
+}
+
+func (r *tokenRepo) DeleteExpiredRefreshTokens(now time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var deleted int64
+	for id, token := range r.s.refreshTokens {
+		if token.ExpiresAt.Before(now) {
+			delete(r.s.refreshTokens, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
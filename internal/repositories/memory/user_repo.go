@@ -0,0 +1,213 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type userRepo struct {
+	s *Store
+}
+
+func (r *userRepo) GetUserByEmail(email string) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, user := range r.s.users {
+		if user.Email == email && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *userRepo) GetUserByID(id string) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	user, ok := r.s.users[parseID(id)]
+	if !ok || user.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &user, nil
+}
+
+func (r *userRepo) CreateUser(user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.users {
+		if existing.Email == user.Email {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+
+	r.s.stamp(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	if user.Role == "" {
+		user.Role = "staff"
+	}
+	// Like the column default, a zero value creates an active user
+	user.IsActive = true
+	r.s.users[user.ID] = *user
+	return nil
+}
+
+func (r *userRepo) UpdateUser(user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	r.s.users[user.ID] = *user
+	return nil
+}
+
+func (r *userRepo) ListUsers(role string, offset, limit int) ([]models.User, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range r.s.users {
+		if (role == "" || user.Role == role) && !user.DeletedAt.Valid {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].CreatedAt.After(users[j].CreatedAt)
+	})
+	return page(users, offset, limit), int64(len(users)), nil
+}
+
+func (r *userRepo) DeactivateUser(id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[parseID(id)]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	user.IsActive = false
+	user.UpdatedAt = r.s.Now()
+	r.s.users[user.ID] = user
+	return nil
+}
+
+func (r *userRepo) DeleteUser(id string, deletion repositories.UserDeletion) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[parseID(id)]
+	if !ok || user.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+
+	if deletion.ReassignTo != nil {
+		for logID, log := range r.s.actionLogs {
+			if log.VerifiedBy != nil && *log.VerifiedBy == user.ID {
+				to := *deletion.ReassignTo
+				log.VerifiedBy = &to
+				r.s.actionLogs[logID] = log
+			}
+		}
+	}
+	for key, staff := range r.s.eventStaff {
+		if staff.UserID == user.ID {
+			delete(r.s.eventStaff, key)
+		}
+	}
+
+	if deletion.Anonymize {
+		user.Email = repositories.DeletedUserEmail(user.ID)
+		user.Name = ""
+		user.LastLoginIP = ""
+		for loginID, login := range r.s.loginEvents {
+			if login.UserID == user.ID {
+				delete(r.s.loginEvents, loginID)
+			}
+		}
+	}
+	at := deletion.At
+	user.IsActive = false
+	user.TokensRevokedAt = &at
+	user.UpdatedAt = r.s.Now()
+	user.DeletedAt = gorm.DeletedAt{Time: user.UpdatedAt, Valid: true}
+	r.s.users[user.ID] = user
+	return nil
+}
+
+func (r *userRepo) RevokeUserTokens(id string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[parseID(id)]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	user.TokensRevokedAt = &at
+	user.UpdatedAt = r.s.Now()
+	r.s.users[user.ID] = user
+	return nil
+}
+
+func (r *userRepo) UseTOTPStep(userID string, step int64) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[parseID(userID)]
+	if !ok || user.TOTPLastStep >= step {
+		return false, nil
+	}
+	user.TOTPLastStep = step
+	r.s.users[user.ID] = user
+	return true, nil
+}
+
+func (r *userRepo) RecordLogin(login *models.LoginEvent) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&login.ID, &login.CreatedAt, nil)
+	r.s.loginEvents[login.ID] = *login
+	if user, ok := r.s.users[login.UserID]; ok {
+		at := login.CreatedAt
+		user.LastLoginAt = &at
+		user.LastLoginIP = login.IP
+		r.s.users[user.ID] = user
+	}
+	return nil
+}
+
+func (r *userRepo) ListLoginEvents(userID string, limit int) ([]models.LoginEvent, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	id := parseID(userID)
+	logins := []models.LoginEvent{}
+	for _, login := range r.s.loginEvents {
+		if login.UserID == id {
+			logins = append(logins, login)
+		}
+	}
+	sort.Slice(logins, func(i, j int) bool {
+		return newestFirst(logins[i].CreatedAt, logins[j].CreatedAt, logins[i].ID, logins[j].ID)
+	})
+	return page(logins, 0, limit), nil
+}
+
+func (r *userRepo) DeleteLoginEventsBefore(before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var deleted int64
+	for id, login := range r.s.loginEvents {
+		if login.CreatedAt.Before(before) {
+			delete(r.s.loginEvents, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
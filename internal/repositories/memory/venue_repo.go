@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type venueRepo struct {
+	s *Store
+}
+
+func (r *venueRepo) CreateVenue(venue *models.Venue) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&venue.ID, &venue.CreatedAt, &venue.UpdatedAt)
+	r.s.venues[venue.ID] = *venue
+	return nil
+}
+
+func (r *venueRepo) GetVenue(id string) (*models.Venue, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	venue, ok := r.s.venues[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &venue, nil
+}
+
+func (r *venueRepo) ListVenues(search string, offset, limit int) ([]models.Venue, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	term := strings.ToLower(search)
+	venues := []models.Venue{}
+	for _, venue := range r.s.venues {
+		if term == "" || strings.Contains(strings.ToLower(venue.Name), term) || strings.Contains(strings.ToLower(venue.Address), term) {
+			venues = append(venues, venue)
+		}
+	}
+	sort.Slice(venues, func(i, j int) bool {
+		if venues[i].Name != venues[j].Name {
+			return venues[i].Name < venues[j].Name
+		}
+		return venues[i].ID.String() < venues[j].ID.String()
+	})
+	return page(venues, offset, limit), int64(len(venues)), nil
+}
+
+func (r *venueRepo) UpdateVenue(venue *models.Venue) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.venues[venue.ID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	venue.CreatedAt = existing.CreatedAt
+	venue.UpdatedAt = r.s.Now()
+	r.s.venues[venue.ID] = *venue
+	return nil
+}
+
+func (r *venueRepo) DeleteVenue(id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	venueID := parseID(id)
+	if _, ok := r.s.venues[venueID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	for _, event := range r.s.events {
+		if event.VenueID != nil && *event.VenueID == venueID {
+			return repositories.ErrVenueInUse
+		}
+	}
+	for _, day := range r.s.days {
+		if day.VenueID != nil && *day.VenueID == venueID {
+			return repositories.ErrVenueInUse
+		}
+	}
+	delete(r.s.venues, venueID)
+	return nil
+}
+
+// venueOf returns the venue of id, as a preload would. The caller holds the
+// lock.
+func (s *Store) venueOf(id *uuid.UUID) *models.Venue {
+	if id == nil {
+		return nil
+	}
+	if venue, ok := s.venues[*id]; ok {
+		return &venue
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"sort"
+
+	"event-management-backend/internal/fieldcrypt"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type waitlistRepo struct {
+	s *Store
+}
+
+func (r *waitlistRepo) CreateWaitlistEntry(entry *models.WaitlistEntry) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if err := entry.BeforeSave(nil); err != nil {
+		return err
+	}
+	r.s.stamp(&entry.ID, &entry.CreatedAt, &entry.UpdatedAt)
+	if entry.Status == "" {
+		entry.Status = "waiting"
+	}
+	if entry.MealPreference == "" {
+		entry.MealPreference = "regular"
+	}
+	r.s.waitlist[entry.ID] = *entry
+	return nil
+}
+
+func (r *waitlistRepo) GetWaitlistEntry(id string) (*models.WaitlistEntry, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	entry, ok := r.s.waitlist[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &entry, nil
+}
+
+func (r *waitlistRepo) FindWaitingEntry(eventID, email string) (*models.WaitlistEntry, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	hash := fieldcrypt.Hash(email)
+	for _, entry := range r.s.waitlist {
+		if entry.EventID == parseID(eventID) && entry.EmailHash == hash && entry.Status == "waiting" {
+			return &entry, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *waitlistRepo) ListWaitingEntries(eventID string, byPriority bool) ([]models.WaitlistEntry, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	entries := []models.WaitlistEntry{}
+	for _, entry := range r.s.waitlist {
+		if entry.EventID == parseID(eventID) && entry.Status == "waiting" {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if byPriority && a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	})
+	return entries, nil
+}
+
+func (r *waitlistRepo) UpdateWaitlistPriority(id string, priority int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	entry, ok := r.s.waitlist[parseID(id)]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	entry.Priority, entry.UpdatedAt = priority, r.s.Now()
+	r.s.waitlist[entry.ID] = entry
+	return nil
+}
+
+func (r *waitlistRepo) PromoteWaitlistEntry(entry *models.WaitlistEntry, participant *models.Participant, limit *int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.events[entry.EventID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if limit != nil && r.s.ticketHolders(entry.EventID) >= *limit {
+		return repositories.ErrTicketQuotaExceeded
+	}
+	stored, ok := r.s.waitlist[entry.ID]
+	if !ok || stored.Status != "waiting" {
+		return repositories.ErrVersionConflict
+	}
+
+	participants := &participantRepo{r.s}
+	if err := participants.insert(participant); err != nil {
+		return err
+	}
+	stored.Status, stored.ParticipantID, stored.PromotedAt = "promoted", &participant.ID, &participant.CreatedAt
+	stored.UpdatedAt = r.s.Now()
+	r.s.waitlist[entry.ID] = stored
+	entry.Status, entry.ParticipantID, entry.PromotedAt = stored.Status, stored.ParticipantID, stored.PromotedAt
+	return nil
+}
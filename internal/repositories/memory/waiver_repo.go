@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"sort"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type waiverRepo struct {
+	s *Store
+}
+
+func (r *waiverRepo) CreateWaiver(waiver *models.Waiver) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&waiver.ID, &waiver.CreatedAt, &waiver.UpdatedAt)
+	r.s.waivers[waiver.ID] = *waiver
+	return nil
+}
+
+func (r *waiverRepo) GetWaiverByID(id string) (*models.Waiver, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	waiver, ok := r.s.waivers[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &waiver, nil
+}
+
+func (r *waiverRepo) ListWaiversByEvent(eventID string) ([]models.Waiver, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.waivers(eventID, func(models.Waiver) bool { return true }), nil
+}
+
+func (r *waiverRepo) SetWaiverActive(id string, active bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	waiver, ok := r.s.waivers[parseID(id)]
+	if !ok {
+		return nil
+	}
+	waiver.IsActive = active
+	waiver.UpdatedAt = r.s.Now()
+	r.s.waivers[waiver.ID] = waiver
+	return nil
+}
+
+func (r *waiverRepo) ListUnsignedWaivers(eventID, participantID string) ([]models.Waiver, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	signed := make(map[string]bool)
+	for _, signature := range r.s.waiverSignatures {
+		if signature.ParticipantID == parseID(participantID) {
+			signed[signature.WaiverID.String()] = true
+		}
+	}
+	return r.waivers(eventID, func(waiver models.Waiver) bool {
+		return waiver.IsActive && !signed[waiver.ID.String()]
+	}), nil
+}
+
+func (r *waiverRepo) CreateWaiverSignature(signature *models.WaiverSignature) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.waiverSignatures {
+		if existing.WaiverID == signature.WaiverID && existing.ParticipantID == signature.ParticipantID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+
+	r.s.stamp(&signature.ID, &signature.CreatedAt, nil)
+	stored := *signature
+	stored.Waiver, stored.Participant = models.Waiver{}, models.Participant{}
+	r.s.waiverSignatures[signature.ID] = stored
+	return nil
+}
+
+func (r *waiverRepo) GetWaiverSignatureByID(id string) (*models.WaiverSignature, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	signature, ok := r.s.waiverSignatures[parseID(id)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &signature, nil
+}
+
+func (r *waiverRepo) ListWaiverSignaturesByParticipant(participantID string) ([]models.WaiverSignature, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	signatures := []models.WaiverSignature{}
+	for _, signature := range r.s.waiverSignatures {
+		if signature.ParticipantID == parseID(participantID) {
+			signature.Waiver = r.s.waivers[signature.WaiverID]
+			signatures = append(signatures, signature)
+		}
+	}
+	sortSignatures(signatures)
+	return signatures, nil
+}
+
+func (r *waiverRepo) ListWaiverSignaturesByEvent(eventID string) ([]models.WaiverSignature, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	signatures := []models.WaiverSignature{}
+	for _, signature := range r.s.waiverSignatures {
+		if signature.EventID == parseID(eventID) {
+			signature.Waiver = r.s.waivers[signature.WaiverID]
+			signature.Participant = r.s.participants[signature.ParticipantID]
+			signatures = append(signatures, signature)
+		}
+	}
+	sortSignatures(signatures)
+	return signatures, nil
+}
+
+// waivers returns the waivers of an event matching keep, oldest first. The
+// caller must hold the lock.
+func (r *waiverRepo) waivers(eventID string, keep func(models.Waiver) bool) []models.Waiver {
+	waivers := []models.Waiver{}
+	for _, waiver := range r.s.waivers {
+		if waiver.EventID == parseID(eventID) && keep(waiver) {
+			waivers = append(waivers, waiver)
+		}
+	}
+	sort.Slice(waivers, func(i, j int) bool { return waivers[i].CreatedAt.Before(waivers[j].CreatedAt) })
+	return waivers
+}
+
+func sortSignatures(signatures []models.WaiverSignature) {
+	sort.Slice(signatures, func(i, j int) bool { return signatures[i].SignedAt.Before(signatures[j].SignedAt) })
+}
@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AttendanceCounts counts the distinct participants of an event by how they
+// attended. Participants who attended both ways are in both counts.
+type AttendanceCounts struct {
+	InPerson int64
+	Online   int64
+	Total    int64
+}
+
+type OnlineRepository interface {
+	CreateMeeting(meeting *models.OnlineMeeting) error
+	GetMeetingByID(id string) (*models.OnlineMeeting, error)
+	GetMeetingByExternalID(externalID string) (*models.OnlineMeeting, error)
+	ListMeetingsByEvent(eventID string) ([]models.OnlineMeeting, error)
+	DeleteMeeting(id string) error
+	ListJoinLinkRecipients(eventID string, paidOnly bool, limit int) ([]models.Participant, error)
+	MarkJoinLinkSent(participantID string, at time.Time) error
+	RecordAttendance(logs []models.ActionLog) (int, error)
+	CountAttendance(eventID string) (*AttendanceCounts, error)
+}
+
+type onlineRepo struct {
+	db *gorm.DB
+}
+
+func NewOnlineRepository(db *gorm.DB) OnlineRepository {
+	return &onlineRepo{db: db}
+}
+
+func (r *onlineRepo) CreateMeeting(meeting *models.OnlineMeeting) error {
+	return r.db.Omit(clause.Associations).Create(meeting).Error
+}
+
+func (r *onlineRepo) GetMeetingByID(id string) (*models.OnlineMeeting, error) {
+	var meeting models.OnlineMeeting
+	if err := r.db.Preload("Action").Where("id = ?", id).First(&meeting).Error; err != nil {
+		return nil, err
+	}
+	return &meeting, nil
+}
+
+func (r *onlineRepo) GetMeetingByExternalID(externalID string) (*models.OnlineMeeting, error) {
+	var meeting models.OnlineMeeting
+	if err := r.db.Preload("Action").Where("external_id = ?", externalID).First(&meeting).Error; err != nil {
+		return nil, err
+	}
+	return &meeting, nil
+}
+
+func (r *onlineRepo) ListMeetingsByEvent(eventID string) ([]models.OnlineMeeting, error) {
+	var meetings []models.OnlineMeeting
+	if err := r.db.Preload("Action").
+		Where("event_id = ?", eventID).
+		Order("created_at ASC").
+		Find(&meetings).Error; err != nil {
+		return nil, fmt.Errorf("failed to list online meetings: %w", err)
+	}
+	return meetings, nil
+}
+
+func (r *onlineRepo) DeleteMeeting(id string) error {
+	result := r.db.Where("id = ?", id).Delete(&models.OnlineMeeting{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListJoinLinkRecipients returns up to limit approved participants of an
+// event who have not been emailed their join link yet, oldest first.
+// Cancelled registrations are left out, and with paidOnly unpaid ones too
+// unless they hold a complimentary ticket.
+func (r *onlineRepo) ListJoinLinkRecipients(eventID string, paidOnly bool, limit int) ([]models.Participant, error) {
+	query := r.db.Where("event_id = ? AND approval_status = ? AND cancelled_at IS NULL AND join_link_sent_at IS NULL", eventID, "approved")
+	if paidOnly {
+		query = query.Where("payment_status = ? OR "+jsonContains(r.db, "tags"), "paid", models.TagComplimentary)
+	}
+
+	var participants []models.Participant
+	if err := query.Order("created_at ASC, id ASC").Limit(limit).Find(&participants).Error; err != nil {
+		return nil, err
+	}
+	return participants, nil
+}
+
+func (r *onlineRepo) MarkJoinLinkSent(participantID string, at time.Time) error {
+	return r.db.Model(&models.Participant{}).
+		Where("id = ?", participantID).
+		UpdateColumn("join_link_sent_at", at).Error
+}
+
+// RecordAttendance creates the given action logs, skipping participants
+// already verified for the action, and returns how many were created
+func (r *onlineRepo) RecordAttendance(logs []models.ActionLog) (int, error) {
+	created := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range logs {
+			var count int64
+			if err := tx.Model(&models.ActionLog{}).
+				Where("participant_id = ? AND action_id = ?", logs[i].ParticipantID, logs[i].ActionID).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				continue
+			}
+
+			if err := tx.Omit(clause.Associations).Create(&logs[i]).Error; err != nil {
+				return fmt.Errorf("failed to record attendance: %w", err)
+			}
+			created++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+func (r *onlineRepo) CountAttendance(eventID string) (*AttendanceCounts, error) {
+	var counts AttendanceCounts
+	if err := r.db.Model(&models.ActionLog{}).
+		Select(`COUNT(DISTINCT CASE WHEN action_logs.source = 'scan' THEN action_logs.participant_id END) AS in_person,
+			COUNT(DISTINCT CASE WHEN action_logs.source = 'online' THEN action_logs.participant_id END) AS online,
+			COUNT(DISTINCT action_logs.participant_id) AS total`).
+		Joins("JOIN participants ON action_logs.participant_id = participants.id").
+		Where("participants.event_id = ?", eventID).
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count attendance: %w", err)
+	}
+	return &counts, nil
+}
@@ -1,10 +1,40 @@
 package repositories
 
 import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/fieldcrypt"
 	"event-management-backend/internal/models"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// MergeCounts is what merging a duplicate participant did to its records
+type MergeCounts struct {
+	ActionLogsMoved int64 `json:"action_logs_moved"`
+	// Scans of actions the kept participant was already scanned at
+	ActionLogsDropped int64 `json:"action_logs_dropped"`
+	// Leads, waiver signatures, seat assignments, credential incidents, notes
+	// and draw wins moved
+	RecordsMoved   int64 `json:"records_moved"`
+	RecordsDropped int64 `json:"records_dropped"`
+}
+
+// ParticipantRecords are the rows other tables keep about a participant
+type ParticipantRecords struct {
+	WaiverSignatures []models.WaiverSignature `json:"waiver_signatures"`
+	Leads            []models.Lead            `json:"leads"`
+	SeatAssignments  []models.SeatAssignment  `json:"seat_assignments"`
+	DrawWins         []models.DrawWinner      `json:"draw_wins"`
+	// Waitlist entries the participant was promoted from
+	WaitlistEntries []models.WaitlistEntry `json:"waitlist_entries"`
+}
+
 type participantRepo struct {
 	db *gorm.DB
 }
@@ -17,6 +47,108 @@ func (r *participantRepo) CreateParticipant(participant *models.Participant) err
 	return r.db.Create(participant).Error
 }
 
+// CreateParticipantsInBatches inserts participants batchSize rows per
+// statement inside a single transaction
+func (r *participantRepo) CreateParticipantsInBatches(participants []models.Participant, batchSize int) error {
+	if len(participants) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(participants, batchSize).Error
+	})
+}
+
+func (r *participantRepo) CreateParticipantGroup(eventID string, participants []models.Participant, limit *int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var event models.Event
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", eventID).First(&event).Error; err != nil {
+			return err
+		}
+		if limit != nil {
+			var count int64
+			if err := tx.Model(&models.Participant{}).Where("event_id = ? AND cancelled_at IS NULL", eventID).Count(&count).Error; err != nil {
+				return err
+			}
+			if int(count)+len(participants) > *limit {
+				return ErrTicketQuotaExceeded
+			}
+		}
+		if err := checkTicketTypeQuotas(tx, eventID, participants); err != nil {
+			return err
+		}
+		if err := checkQuotaRules(tx, eventID, participants); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(&participants).Error
+	})
+}
+
+// checkTicketTypeQuotas refuses participants for ticket types with not
+// enough left of their quota. It counts through tx, which holds the event
+// locked.
+func checkTicketTypeQuotas(tx *gorm.DB, eventID string, participants []models.Participant) error {
+	wanted := make(map[uuid.UUID]int)
+	for _, participant := range participants {
+		if participant.TicketTypeID != nil {
+			wanted[*participant.TicketTypeID]++
+		}
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(wanted))
+	for id := range wanted {
+		ids = append(ids, id)
+	}
+
+	var ticketTypes []models.TicketType
+	if err := tx.Where("event_id = ? AND id IN ? AND quota IS NOT NULL", eventID, ids).Find(&ticketTypes).Error; err != nil {
+		return err
+	}
+	for _, ticketType := range ticketTypes {
+		var sold int64
+		if err := tx.Model(&models.Participant{}).
+			Where("event_id = ? AND ticket_type_id = ? AND cancelled_at IS NULL", eventID, ticketType.ID).
+			Count(&sold).Error; err != nil {
+			return err
+		}
+		if int(sold)+wanted[ticketType.ID] > *ticketType.Quota {
+			return ErrTicketTypeSoldOut
+		}
+	}
+	return nil
+}
+
+// GetRegisteredEmailHashes returns which of the email hashes are already
+// registered for an event, used to reject duplicates of an import batch up
+// front
+func (r *participantRepo) GetRegisteredEmailHashes(eventID string, hashes []string) (map[string]bool, error) {
+	set := make(map[string]bool)
+	if len(hashes) == 0 {
+		return set, nil
+	}
+
+	var registered []string
+	if err := r.db.Model(&models.Participant{}).
+		Where("event_id = ? AND email_hash IN ?", eventID, hashes).
+		Pluck("email_hash", &registered).Error; err != nil {
+		return nil, err
+	}
+	for _, hash := range registered {
+		set[hash] = true
+	}
+	return set, nil
+}
+
+// ClearQRPaths removes the QR path of participants whose QR image could not
+// be written, so they are not pointed at a missing file
+func (r *participantRepo) ClearQRPaths(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Participant{}).Where("id IN ?", ids).UpdateColumn("qr_path", "").Error
+}
+
 func (r *participantRepo) GetParticipantByID(id string) (*models.Participant, error) {
 	var participant models.Participant
 	if err := r.db.Where("id = ?", id).First(&participant).Error; err != nil {
@@ -27,7 +159,8 @@ func (r *participantRepo) GetParticipantByID(id string) (*models.Participant, er
 
 func (r *participantRepo) GetParticipantByEmailAndEvent(email, eventID string) (*models.Participant, error) {
 	var participant models.Participant
-	if err := r.db.Where("email = ? AND event_id = ?", email, eventID).First(&participant).Error; err != nil {
+	// Email is encrypted at rest, so it is matched through its keyed hash
+	if err := r.db.Where("email_hash = ? AND event_id = ?", fieldcrypt.Hash(email), eventID).First(&participant).Error; err != nil {
 		return nil, err
 	}
 	return &participant, nil
@@ -43,23 +176,70 @@ func (r *participantRepo) FindParticipantByQRPath(qrPath string) (*models.Partic
 
 func (r *participantRepo) GetParticipantCountByEventID(eventID string) (int64, error) {
 	var count int64
-	if err := r.db.Model(&models.Participant{}).Where("event_id = ?", eventID).Count(&count).Error; err != nil {
+	if err := r.db.Model(&models.Participant{}).Where("event_id = ? AND cancelled_at IS NULL", eventID).Count(&count).Error; err != nil {
 		return 0, err
 	}
 	return count, nil
 }
 
-func (r *participantRepo) ListParticipantsByEvent(eventID string, offset, limit int) ([]models.Participant, int64, error) {
+// CountParticipantsByEventIDs counts participants of several events in one query
+func (r *participantRepo) CountParticipantsByEventIDs(eventIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		EventID string
+		Count   int64
+	}
+	if err := r.db.Model(&models.Participant{}).
+		Select("event_id, COUNT(*) AS count").
+		Where("event_id IN ?", eventIDs).
+		Group("event_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.EventID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *participantRepo) CountParticipantsByTicketType(eventID string) (map[string]int64, error) {
+	var rows []struct {
+		TicketTypeID string
+		Count        int64
+	}
+	if err := r.db.Model(&models.Participant{}).
+		Select("ticket_type_id, COUNT(*) AS count").
+		Where("event_id = ? AND ticket_type_id IS NOT NULL AND cancelled_at IS NULL", eventID).
+		Group("ticket_type_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TicketTypeID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *participantRepo) ListParticipantsByEvent(eventID string, offset, limit int, filters *ParticipantFilters) ([]models.Participant, int64, error) {
 	var participants []models.Participant
 	var total int64
 
+	query := filterParticipants(r.db.Model(&models.Participant{}).Where("event_id = ?", eventID), filters)
+
 	// Count total
-	if err := r.db.Model(&models.Participant{}).Where("event_id = ?", eventID).Count(&total).Error; err != nil {
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get participants with pagination
-	if err := r.db.Where("event_id = ?", eventID).
+	if err := query.
 		Offset(offset).Limit(limit).
 		Order("created_at DESC").
 		Find(&participants).Error; err != nil {
@@ -69,16 +249,471 @@ func (r *participantRepo) ListParticipantsByEvent(eventID string, offset, limit
 	return participants, total, nil
 }
 
+// ListParticipantsByEventAfter returns up to limit participants created before
+// the cursor position, newest first. A nil cursor starts from the newest row.
+func (r *participantRepo) ListParticipantsByEventAfter(eventID string, cursor *Cursor, limit int, filters *ParticipantFilters) ([]models.Participant, error) {
+	var participants []models.Participant
+
+	query := filterParticipants(r.db.Where("event_id = ?", eventID), filters)
+	if cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", cursor.Time, cursor.ID)
+	}
+
+	if err := query.
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&participants).Error; err != nil {
+		return nil, err
+	}
+
+	return participants, nil
+}
+
+// backfillPhoneHashes sets the phone lookup hash of participants saved before
+// it existed. Phones are encrypted, so the hash is computed here rather than
+// in SQL.
+func backfillPhoneHashes(db *gorm.DB) error {
+	var batch []models.Participant
+	return db.Unscoped().Select("id", "phone").
+		Where("(phone_hash IS NULL OR phone_hash = '') AND phone <> ''").
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			for _, participant := range batch {
+				digits := models.PhoneDigits(participant.Phone)
+				if digits == "" {
+					continue
+				}
+				if err := db.Unscoped().Model(&models.Participant{}).Where("id = ?", participant.ID).
+					UpdateColumn("phone_hash", fieldcrypt.Hash(digits)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// filterParticipants applies the optional listing filters to query
+func filterParticipants(query *gorm.DB, filters *ParticipantFilters) *gorm.DB {
+	if filters == nil {
+		return query
+	}
+	if filters.Search != "" {
+		search := strings.TrimSpace(filters.Search)
+		condition := containsFold("name") + " OR email_hash = ?"
+		args := []interface{}{"%" + strings.ToLower(search) + "%", fieldcrypt.Hash(search)}
+		if digits := models.PhoneDigits(search); digits != "" {
+			condition += " OR phone_hash = ?"
+			args = append(args, fieldcrypt.Hash(digits))
+		}
+		query = query.Where(condition, args...)
+	}
+	if filters.PaymentStatus != "" {
+		query = query.Where("payment_status = ?", filters.PaymentStatus)
+	}
+	if filters.Division != "" {
+		query = query.Where("LOWER(division) = ?", strings.ToLower(strings.TrimSpace(filters.Division)))
+	}
+	if filters.RegisteredAfter != nil {
+		query = query.Where("created_at >= ?", *filters.RegisteredAfter)
+	}
+	if filters.RegisteredBefore != nil {
+		query = query.Where("created_at <= ?", *filters.RegisteredBefore)
+	}
+	if filters.Tag != "" {
+		query = query.Where(jsonContains(query, "tags"), filters.Tag)
+	}
+	keys := make([]string, 0, len(filters.Answers))
+	for key := range filters.Answers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		answer, args := jsonText(query, "answers", key)
+		args = append(args, strings.ToLower(strings.TrimSpace(filters.Answers[key])))
+		query = query.Where("LOWER("+answer+") = ?", args...)
+	}
+	return query
+}
+
+// UpdateParticipant writes all fields of participant, failing with
+// ErrVersionConflict when the stored row has moved past participant.Version
 func (r *participantRepo) UpdateParticipant(participant *models.Participant) error {
-	return r.db.Save(participant).Error
+	expected := participant.Version
+	participant.Version = expected + 1
+	result := r.db.Model(participant).
+		Select("*").
+		Omit("id", "created_at", clause.Associations).
+		Where("version = ?", expected).
+		Updates(participant)
+	if result.Error != nil {
+		participant.Version = expected
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		participant.Version = expected
+		return ErrVersionConflict
+	}
+
+	return nil
 }
 
-func (r *participantRepo) UpdatePaymentStatus(participantID, status string) error {
+// UpdatePaymentStatus sets the payment status. When version is given the
+// update only applies if it matches the stored version.
+func (r *participantRepo) UpdatePaymentStatus(participantID, status string, version *int) error {
+	query := r.db.Model(&models.Participant{}).Where("id = ?", participantID)
+	if version != nil {
+		query = query.Where("version = ?", *version)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"payment_status": status,
+		"version":        gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		if version != nil {
+			return ErrVersionConflict
+		}
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ListParticipantsByApprovalStatus returns the participants of an event with
+// the given approval status, oldest first so reviews follow registration order
+func (r *participantRepo) ListParticipantsByApprovalStatus(eventID, status string, offset, limit int) ([]models.Participant, int64, error) {
+	var participants []models.Participant
+	var total int64
+
+	query := r.db.Model(&models.Participant{}).Where("event_id = ? AND approval_status = ?", eventID, status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Offset(offset).Limit(limit).
+		Order("created_at ASC").
+		Find(&participants).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return participants, total, nil
+}
+
+// UpdateApprovalStatus records the review of the given participants of an
+// event and returns the IDs that were updated. IDs of other events are
+// ignored.
+func (r *participantRepo) UpdateApprovalStatus(eventID string, participantIDs []uuid.UUID, status, reason string, reviewerID uuid.UUID) ([]uuid.UUID, error) {
+	var updated []uuid.UUID
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Participant{}).
+			Where("event_id = ? AND id IN ?", eventID, participantIDs).
+			Pluck("id", &updated).Error; err != nil {
+			return err
+		}
+		if len(updated) == 0 {
+			return nil
+		}
+
+		return tx.Model(&models.Participant{}).Where("id IN ?", updated).Updates(map[string]interface{}{
+			"approval_status":  status,
+			"rejection_reason": reason,
+			"reviewed_by":      reviewerID,
+			"reviewed_at":      time.Now(),
+			"version":          gorm.Expr("version + 1"),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *participantRepo) DeleteUnconfirmedParticipants(before time.Time, limit int) ([]models.Participant, error) {
+	var participants []models.Participant
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("approval_status = ? AND confirmation_expires_at < ?", "pending_confirmation", before).
+			Order("confirmation_expires_at ASC").
+			Limit(limit).
+			Find(&participants).Error; err != nil {
+			return err
+		}
+		if len(participants) == 0 {
+			return nil
+		}
+		ids := make([]uuid.UUID, len(participants))
+		for i, participant := range participants {
+			ids[i] = participant.ID
+		}
+		if err := tx.Where("participant_id IN ?", ids).Delete(&models.SeatAssignment{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&models.Participant{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return participants, nil
+}
+
+func (r *participantRepo) UpdateParticipantTags(eventID string, participantIDs []uuid.UUID, add, remove []string, maxTags int) ([]uuid.UUID, []uuid.UUID, error) {
+	updated, overLimit := []uuid.UUID{}, []uuid.UUID{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var participants []models.Participant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "tags").
+			Where("event_id = ? AND id IN ?", eventID, participantIDs).
+			Find(&participants).Error; err != nil {
+			return err
+		}
+		for _, participant := range participants {
+			tags := models.MergeTags(participant.Tags, add, remove)
+			if len(tags) > maxTags {
+				overLimit = append(overLimit, participant.ID)
+				continue
+			}
+			encoded, err := json.Marshal(tags)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Participant{}).Where("id = ?", participant.ID).Updates(map[string]interface{}{
+				"tags":    string(encoded),
+				"version": gorm.Expr("version + 1"),
+			}).Error; err != nil {
+				return err
+			}
+			updated = append(updated, participant.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return updated, overLimit, nil
+}
+
+// RotateCredential moves a participant to the next credential version and
+// records the incident. It fails with ErrVersionConflict when the credential
+// was reissued concurrently since fromVersion was read.
+func (r *participantRepo) RotateCredential(participantID string, fromVersion int, qrPath string, requireIDCheck bool, incident *models.CredentialIncident) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Participant{}).
+			Where("id = ? AND credential_version = ?", participantID, fromVersion).
+			Updates(map[string]interface{}{
+				"credential_version": fromVersion + 1,
+				"qr_path":            qrPath,
+				"requires_id_check":  requireIDCheck,
+				"version":            gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+
+		return tx.Create(incident).Error
+	})
+}
+
+func (r *participantRepo) ListParticipantRecords(participantID string) (*ParticipantRecords, error) {
+	records := &ParticipantRecords{}
+	for _, query := range []interface{}{
+		&records.WaiverSignatures,
+		&records.Leads,
+		&records.SeatAssignments,
+		&records.DrawWins,
+		&records.WaitlistEntries,
+	} {
+		if err := r.db.Where("participant_id = ?", participantID).Order("created_at ASC").Find(query).Error; err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// ListCredentialIncidents returns the credential reissues of a participant,
+// newest first
+func (r *participantRepo) ListCredentialIncidents(participantID string) ([]models.CredentialIncident, error) {
+	var incidents []models.CredentialIncident
+	if err := r.db.Where("participant_id = ?", participantID).
+		Order("created_at DESC").
+		Find(&incidents).Error; err != nil {
+		return nil, err
+	}
+	return incidents, nil
+}
+
+func (r *participantRepo) CreateParticipantNote(note *models.ParticipantNote) error {
+	return r.db.Create(note).Error
+}
+
+func (r *participantRepo) ListParticipantNotes(participantID string) ([]models.ParticipantNote, error) {
+	var notes []models.ParticipantNote
+	if err := r.db.Preload("Author", withDeleted).
+		Where("participant_id = ?", participantID).
+		Order("created_at DESC").
+		Find(&notes).Error; err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+func (r *participantRepo) MergeParticipants(survivor, duplicate *models.Participant) (*MergeCounts, error) {
+	counts := &MergeCounts{}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := (&participantRepo{db: tx}).UpdateParticipant(survivor); err != nil {
+			return err
+		}
+
+		var err error
+		counts.ActionLogsMoved, counts.ActionLogsDropped, err = moveParticipantRows(tx, &models.ActionLog{}, "action_id", duplicate.ID, survivor.ID)
+		if err != nil {
+			return err
+		}
+		for _, table := range []struct {
+			model interface{}
+			key   string
+		}{
+			{&models.Lead{}, "sponsor_id"},
+			{&models.WaiverSignature{}, "waiver_id"},
+			{&models.SeatAssignment{}, "event_day_id"},
+			{&models.CredentialIncident{}, ""},
+			{&models.ParticipantNote{}, ""},
+			{&models.DrawWinner{}, ""},
+		} {
+			moved, dropped, err := moveParticipantRows(tx, table.model, table.key, duplicate.ID, survivor.ID)
+			if err != nil {
+				return err
+			}
+			counts.RecordsMoved += moved
+			counts.RecordsDropped += dropped
+		}
+
+		result := tx.Where("id = ? AND version = ?", duplicate.ID, duplicate.Version).Delete(&models.Participant{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *participantRepo) TransferParticipant(participant *models.Participant, limit *int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var event models.Event
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", participant.EventID).First(&event).Error; err != nil {
+			return err
+		}
+		if limit != nil {
+			var count int64
+			if err := tx.Model(&models.Participant{}).Where("event_id = ? AND cancelled_at IS NULL", participant.EventID).Count(&count).Error; err != nil {
+				return err
+			}
+			if int(count) >= *limit {
+				return ErrTicketQuotaExceeded
+			}
+		}
+		if err := checkTicketTypeQuotas(tx, participant.EventID.String(), []models.Participant{*participant}); err != nil {
+			return err
+		}
+		if err := checkQuotaRules(tx, participant.EventID.String(), []models.Participant{*participant}); err != nil {
+			return err
+		}
+		if err := tx.Where("participant_id = ?", participant.ID).Delete(&models.SeatAssignment{}).Error; err != nil {
+			return err
+		}
+		return (&participantRepo{db: tx}).UpdateParticipant(participant)
+	})
+}
+
+// checkQuotaRules refuses participants going over a quota rule of their
+// event. It counts through tx, which holds the event locked.
+func checkQuotaRules(tx *gorm.DB, eventID string, participants []models.Participant) error {
+	var rules []models.QuotaRule
+	if err := tx.Where("event_id = ?", eventID).Order("name ASC").Find(&rules).Error; err != nil {
+		return err
+	}
+	for i := range rules {
+		wanted := 0
+		for _, participant := range participants {
+			if rules[i].Applies(participant.TicketTypeID, participant.Division) {
+				wanted++
+			}
+		}
+		if wanted == 0 {
+			continue
+		}
+		count, err := countRuleRegistrations(tx, &rules[i])
+		if err != nil {
+			return err
+		}
+		if int(count)+wanted > rules[i].MaxRegistrations {
+			return fmt.Errorf("%w: %s", ErrQuotaRuleReached, rules[i].Name)
+		}
+	}
+	return nil
+}
+
+// moveParticipantRows moves the rows of model from one participant to
+// another, except rows whose key column matches a row the other participant
+// already has; those are deleted. An empty key moves every row.
+func moveParticipantRows(tx *gorm.DB, model interface{}, key string, from, to uuid.UUID) (moved, dropped int64, err error) {
+	query := tx.Model(model).Where("participant_id = ?", from)
+	if key != "" {
+		var taken []uuid.UUID
+		if err := tx.Model(model).Where("participant_id = ?", to).Pluck(key, &taken).Error; err != nil {
+			return 0, 0, err
+		}
+		if len(taken) > 0 {
+			query = query.Where(key+" NOT IN ?", taken)
+		}
+	}
+	result := query.Update("participant_id", to)
+	if result.Error != nil {
+		return 0, 0, result.Error
+	}
+
+	deleted := tx.Where("participant_id = ?", from).Delete(model)
+	if deleted.Error != nil {
+		return 0, 0, deleted.Error
+	}
+	return result.RowsAffected, deleted.RowsAffected, nil
+}
+
+func (r *participantRepo) ClearIDCheck(participantID string) error {
 	return r.db.Model(&models.Participant{}).
 		Where("id = ?", participantID).
-		Update("payment_status", status).Error
+		Update("requires_id_check", false).Error
+}
+
+func (r *participantRepo) ListCancellationRecipients(eventID string, limit int) ([]models.Participant, error) {
+	var participants []models.Participant
+	if err := r.db.Where("event_id = ? AND cancelled_at IS NOT NULL AND cancellation_notice_sent_at IS NULL", eventID).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&participants).Error; err != nil {
+		return nil, err
+	}
+	return participants, nil
+}
+
+func (r *participantRepo) MarkCancellationNoticeSent(participantID string, at time.Time) error {
+	return r.db.Model(&models.Participant{}).
+		Where("id = ?", participantID).
+		UpdateColumn("cancellation_notice_sent_at", at).Error
 }
 
 func (r *participantRepo) Transaction(txFunc func(*gorm.DB) error) error {
 	return r.db.Transaction(txFunc)
-}
\ No newline at end of file
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type QuotaRuleRepository interface {
+	CreateQuotaRule(rule *models.QuotaRule) error
+	GetQuotaRule(id string) (*models.QuotaRule, error)
+	// ListQuotaRulesByEvent returns the quota rules of an event by name
+	ListQuotaRulesByEvent(eventID string) ([]models.QuotaRule, error)
+	UpdateQuotaRule(rule *models.QuotaRule) error
+	DeleteQuotaRule(id string) error
+	// CountRuleRegistrations counts the registrations of the rule's event the
+	// rule applies to, leaving out cancelled ones
+	CountRuleRegistrations(rule *models.QuotaRule) (int64, error)
+}
+
+type quotaRuleRepo struct {
+	db *gorm.DB
+}
+
+func NewQuotaRuleRepository(db *gorm.DB) QuotaRuleRepository {
+	return &quotaRuleRepo{db: db}
+}
+
+func (r *quotaRuleRepo) CreateQuotaRule(rule *models.QuotaRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *quotaRuleRepo) GetQuotaRule(id string) (*models.QuotaRule, error) {
+	var rule models.QuotaRule
+	if err := r.db.Where("id = ?", id).First(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *quotaRuleRepo) ListQuotaRulesByEvent(eventID string) ([]models.QuotaRule, error) {
+	var rules []models.QuotaRule
+	if err := r.db.Where("event_id = ?", eventID).Order("name ASC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list quota rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *quotaRuleRepo) UpdateQuotaRule(rule *models.QuotaRule) error {
+	result := r.db.Model(rule).Select("*").Omit("id", "event_id", "created_at").Updates(rule)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *quotaRuleRepo) DeleteQuotaRule(id string) error {
+	result := r.db.Where("id = ?", id).Delete(&models.QuotaRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *quotaRuleRepo) CountRuleRegistrations(rule *models.QuotaRule) (int64, error) {
+	return countRuleRegistrations(r.db, rule)
+}
+
+func countRuleRegistrations(db *gorm.DB, rule *models.QuotaRule) (int64, error) {
+	query := db.Model(&models.Participant{}).Where("event_id = ? AND cancelled_at IS NULL", rule.EventID)
+	if rule.TicketTypeID != nil {
+		query = query.Where("ticket_type_id = ?", *rule.TicketTypeID)
+	}
+	if rule.Division != "" {
+		query = query.Where("LOWER(division) = ?", strings.ToLower(rule.Division))
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -1,69 +1,309 @@
 package repositories
 
 import (
+	"errors"
+	"time"
+
 	"event-management-backend/internal/models"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type Repository struct {
-	DB              *gorm.DB
-	EventRepo       EventRepository
-	UserRepo        UserRepository
-	ParticipantRepo ParticipantRepository
-	ActionRepo      ActionRepository
+	DB                *gorm.DB
+	EventRepo         EventRepository
+	UserRepo          UserRepository
+	ParticipantRepo   ParticipantRepository
+	ActionRepo        ActionRepository
+	JobRepo           JobRepository
+	IdempotencyRepo   IdempotencyRepository
+	DrawRepo          DrawRepository
+	SessionRepo       SessionRepository
+	SpeakerRepo       SpeakerRepository
+	SponsorRepo       SponsorRepository
+	WaiverRepo        WaiverRepository
+	SeatingRepo       SeatingRepository
+	MealRepo          MealRepository
+	ShiftRepo         ShiftRepository
+	OnlineRepo        OnlineRepository
+	RetentionRepo     RetentionRepository
+	APIKeyRepo        APIKeyRepository
+	TokenRepo         TokenRepository
+	EventStaffRepo    EventStaffRepository
+	AuditRepo         AuditRepository
+	RoleChangeRepo    RoleChangeRepository
+	DeviceRepo        DeviceRepository
+	VenueRepo         VenueRepository
+	TicketTypeRepo    TicketTypeRepository
+	EventImageRepo    EventImageRepository
+	QuotaRuleRepo     QuotaRuleRepository
+	EventRevisionRepo EventRevisionRepository
+	ImportJobRepo     ImportJobRepository
+	WaitlistRepo      WaitlistRepository
 }
 
 func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{
-		DB:              db,
-		EventRepo:       NewEventRepository(db),
-		UserRepo:        NewUserRepository(db),
-		ParticipantRepo: NewParticipantRepository(db),
-		ActionRepo:      NewActionRepository(db),
+		DB:                db,
+		EventRepo:         NewEventRepository(db),
+		UserRepo:          NewUserRepository(db),
+		ParticipantRepo:   NewParticipantRepository(db),
+		ActionRepo:        NewActionRepository(db),
+		JobRepo:           NewJobRepository(db),
+		IdempotencyRepo:   NewIdempotencyRepository(db),
+		DrawRepo:          NewDrawRepository(db),
+		SessionRepo:       NewSessionRepository(db),
+		SpeakerRepo:       NewSpeakerRepository(db),
+		SponsorRepo:       NewSponsorRepository(db),
+		WaiverRepo:        NewWaiverRepository(db),
+		SeatingRepo:       NewSeatingRepository(db),
+		MealRepo:          NewMealRepository(db),
+		ShiftRepo:         NewShiftRepository(db),
+		OnlineRepo:        NewOnlineRepository(db),
+		RetentionRepo:     NewRetentionRepository(db),
+		APIKeyRepo:        NewAPIKeyRepository(db),
+		TokenRepo:         NewTokenRepository(db),
+		EventStaffRepo:    NewEventStaffRepository(db),
+		AuditRepo:         NewAuditRepository(db),
+		RoleChangeRepo:    NewRoleChangeRepository(db),
+		DeviceRepo:        NewDeviceRepository(db),
+		VenueRepo:         NewVenueRepository(db),
+		TicketTypeRepo:    NewTicketTypeRepository(db),
+		EventImageRepo:    NewEventImageRepository(db),
+		QuotaRuleRepo:     NewQuotaRuleRepository(db),
+		EventRevisionRepo: NewEventRevisionRepository(db),
+		ImportJobRepo:     NewImportJobRepository(db),
+		WaitlistRepo:      NewWaitlistRepository(db),
 	}
 }
 
 func AutoMigrate(db *gorm.DB) error {
-	// Enable UUID extension
-	if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`).Error; err != nil {
+	if err := prepareDialect(db); err != nil {
+		return err
+	}
+
+	if err := db.SetupJoinTable(&models.Speaker{}, "Sessions", &models.SpeakerSession{}); err != nil {
 		return err
 	}
 
 	// Migrate models
-	return db.AutoMigrate(
+	if err := db.AutoMigrate(
 		&models.User{},
+		&models.Venue{},
 		&models.Event{},
 		&models.EventDay{},
 		&models.EventAction{},
+		&models.EventImage{},
+		&models.TicketType{},
+		&models.QuotaRule{},
+		&models.EventRevision{},
 		&models.Participant{},
+		&models.WaitlistEntry{},
 		&models.ActionLog{},
-	)
+		&models.Job{},
+		&models.ImportJob{},
+		&models.IdempotencyKey{},
+		&models.Draw{},
+		&models.DrawWinner{},
+		&models.Session{},
+		&models.Speaker{},
+		&models.SpeakerCheckIn{},
+		&models.Sponsor{},
+		&models.Lead{},
+		&models.Waiver{},
+		&models.WaiverSignature{},
+		&models.SeatingTable{},
+		&models.SeatAssignment{},
+		&models.MealCoupon{},
+		&models.Shift{},
+		&models.ShiftAssignment{},
+		&models.CredentialIncident{},
+		&models.ParticipantNote{},
+		&models.OnlineMeeting{},
+		&models.RetentionRule{},
+		&models.LegalHold{},
+		&models.APIKey{},
+		&models.RevokedToken{},
+		&models.EventStaff{},
+		&models.AuditLog{},
+		&models.LoginEvent{},
+		&models.ServiceToken{},
+		&models.RefreshToken{},
+		&models.RoleChangeRequest{},
+		&models.Device{},
+	); err != nil {
+		return err
+	}
+
+	if err := createSearchIndexes(db); err != nil {
+		return err
+	}
+	return backfillPhoneHashes(db)
+}
+
+// ErrVersionConflict is returned when an update carries a stale version,
+// meaning the record was changed by someone else since it was read
+var ErrVersionConflict = errors.New("record was modified by another request")
+
+// ErrTicketQuotaExceeded is returned when a group of participants does not fit
+// in what is left of an event's tickets
+var ErrTicketQuotaExceeded = errors.New("ticket quota exceeded")
+
+// ErrTicketTypeSoldOut is returned when participants do not fit in what is
+// left of a ticket type's quota
+var ErrTicketTypeSoldOut = errors.New("ticket type is sold out")
+
+// ErrQuotaRuleReached is returned when participants do not fit in what is
+// left of a quota rule of their event; it is wrapped with the rule's name
+var ErrQuotaRuleReached = errors.New("quota reached")
+
+// Cursor identifies the last row of a keyset-paginated page. Rows are
+// ordered by Time DESC, ID DESC so the pair is unique and stable.
+type Cursor struct {
+	Time time.Time
+	ID   uuid.UUID
 }
 
 // Interface definitions
+// UserDeletion says what happens to the records of a deleted user
+type UserDeletion struct {
+	// Verifications recorded by the user are moved to this user
+	ReassignTo *uuid.UUID
+	// The user's email is replaced by a placeholder and their login history
+	// dropped, so logs they remain on no longer identify them
+	Anonymize bool
+	At        time.Time
+}
+
+// DeletedUserEmail is the placeholder email of an anonymized user
+func DeletedUserEmail(id uuid.UUID) string {
+	return "deleted-" + id.String() + "@deleted.invalid"
+}
+
 type UserRepository interface {
 	GetUserByEmail(email string) (*models.User, error)
 	GetUserByID(id string) (*models.User, error)
 	CreateUser(user *models.User) error
 	UpdateUser(user *models.User) error
+	ListUsers(role string, offset, limit int) ([]models.User, int64, error)
+	DeactivateUser(id string) error
+	DeleteUser(id string, deletion UserDeletion) error
+	RevokeUserTokens(id string, at time.Time) error
+	UseTOTPStep(userID string, step int64) (bool, error)
+	RecordLogin(login *models.LoginEvent) error
+	ListLoginEvents(userID string, limit int) ([]models.LoginEvent, error)
+	DeleteLoginEventsBefore(before time.Time) (int64, error)
 }
 
 type ParticipantRepository interface {
 	CreateParticipant(participant *models.Participant) error
+	CreateParticipantsInBatches(participants []models.Participant, batchSize int) error
+	// CreateParticipantGroup inserts all participants of one event or none of
+	// them. The event is locked meanwhile, so concurrent registrations cannot
+	// push the event past limit registrations, failing with
+	// ErrTicketQuotaExceeded, a ticket type past its quota, failing with
+	// ErrTicketTypeSoldOut, nor a quota rule past its maximum, failing with
+	// ErrQuotaRuleReached. A nil limit is unlimited.
+	CreateParticipantGroup(eventID string, participants []models.Participant, limit *int) error
+	// GetRegisteredEmailHashes returns which of the email hashes are already
+	// registered for an event
+	GetRegisteredEmailHashes(eventID string, hashes []string) (map[string]bool, error)
+	ClearQRPaths(ids []uuid.UUID) error
 	GetParticipantByID(id string) (*models.Participant, error)
 	GetParticipantByEmailAndEvent(email, eventID string) (*models.Participant, error)
 	FindParticipantByQRPath(qrPath string) (*models.Participant, error)
+	// GetParticipantCountByEventID counts the registrations of an event
+	// holding a ticket, that is not cancelled; quotas and capacities count
+	// those
 	GetParticipantCountByEventID(eventID string) (int64, error)
-	ListParticipantsByEvent(eventID string, offset, limit int) ([]models.Participant, int64, error)
+	CountParticipantsByEventIDs(eventIDs []string) (map[string]int64, error)
+	// CountParticipantsByTicketType counts the registrations of an event
+	// that are not cancelled per ticket type ID
+	CountParticipantsByTicketType(eventID string) (map[string]int64, error)
+	ListParticipantsByEvent(eventID string, offset, limit int, filters *ParticipantFilters) ([]models.Participant, int64, error)
+	ListParticipantsByEventAfter(eventID string, cursor *Cursor, limit int, filters *ParticipantFilters) ([]models.Participant, error)
 	UpdateParticipant(participant *models.Participant) error
-	UpdatePaymentStatus(participantID, status string) error
+	UpdatePaymentStatus(participantID, status string, version *int) error
+	ListParticipantsByApprovalStatus(eventID, status string, offset, limit int) ([]models.Participant, int64, error)
+	UpdateApprovalStatus(eventID string, participantIDs []uuid.UUID, status, reason string, reviewerID uuid.UUID) ([]uuid.UUID, error)
+	// DeleteUnconfirmedParticipants deletes up to limit registrations still
+	// pending email confirmation whose confirmation expired before the given
+	// time, with their seat assignments, and returns them
+	DeleteUnconfirmedParticipants(before time.Time, limit int) ([]models.Participant, error)
+	// UpdateParticipantTags adds and removes tags on participants of an
+	// event. Participants that would end up with more than maxTags tags are
+	// left as they are and returned in overLimit.
+	UpdateParticipantTags(eventID string, participantIDs []uuid.UUID, add, remove []string, maxTags int) (updated, overLimit []uuid.UUID, err error)
+	RotateCredential(participantID string, fromVersion int, qrPath string, requireIDCheck bool, incident *models.CredentialIncident) error
+	ListCredentialIncidents(participantID string) ([]models.CredentialIncident, error)
+	// ListParticipantRecords returns the rows other tables keep about a
+	// participant, oldest first
+	ListParticipantRecords(participantID string) (*ParticipantRecords, error)
+	CreateParticipantNote(note *models.ParticipantNote) error
+	// ListParticipantNotes returns the notes of a participant, newest first,
+	// with their author
+	ListParticipantNotes(participantID string) ([]models.ParticipantNote, error)
+	// MergeParticipants saves survivor, hands the records of duplicate over to
+	// it and deletes duplicate, in one transaction. Records survivor already
+	// has a counterpart of, such as a scan of the same action, are dropped.
+	// Fails with ErrVersionConflict when either changed since it was read.
+	MergeParticipants(survivor, duplicate *models.Participant) (*MergeCounts, error)
+	// TransferParticipant saves participant after it moved to another event,
+	// locking that event like CreateParticipantGroup so it does not go past
+	// limit registrations, the quota of its ticket type or its quota rules.
+	// The seats of the participant, on the days of its former event, are
+	// released. It fails with ErrVersionConflict when the participant changed
+	// since it was read.
+	TransferParticipant(participant *models.Participant, limit *int) error
+	ClearIDCheck(participantID string) error
+	// ListCancellationRecipients returns up to limit cancelled participants
+	// of an event who have not been told yet, oldest first
+	ListCancellationRecipients(eventID string, limit int) ([]models.Participant, error)
+	MarkCancellationNoticeSent(participantID string, at time.Time) error
 	Transaction(txFunc func(*gorm.DB) error) error
 }
 
+// ParticipantFilters narrow a participant listing. Search matches part of the
+// name, or the whole email or phone number: those are encrypted and only
+// searchable through their lookup hashes.
+type ParticipantFilters struct {
+	Search           string
+	PaymentStatus    string
+	Division         string // ignoring case
+	RegisteredAfter  *time.Time
+	RegisteredBefore *time.Time
+	// Answers to registration form fields by key, ignoring case
+	Answers map[string]string
+	// Only participants with this tag
+	Tag string
+}
+
 type ActionRepository interface {
 	CreateActionLog(log *models.ActionLog) error
 	HasActionLog(participantID, actionID string) (bool, error)
 	GetActionLogsByParticipant(participantID string) ([]*models.ActionLog, error)
 	GetActionLogsByEvent(eventID string, offset, limit int) ([]*models.ActionLog, int64, error)
+	GetActionLogsByEventAfter(eventID string, cursor *Cursor, limit int) ([]*models.ActionLog, error)
+	CountActionLogsByActionIDs(actionIDs []string) (map[string]int64, error)
+	// CountActionLogsByParticipants counts the action logs per participant ID
+	CountActionLogsByParticipants(participantIDs []string) (map[string]int64, error)
+	CountVerifiedParticipants(eventID string) (int64, error)
+	// CountAttendeesByDay counts the distinct participants scanned per event
+	// day ID of an event
+	CountAttendeesByDay(eventID string) (map[string]int64, error)
+	CountActionLogsByVerifier(userID string, since time.Time) ([]VerifierEventCount, error)
+}
+
+// VerifierEventCount is the number of verifications a user made for an event
+type VerifierEventCount struct {
+	EventID    uuid.UUID `json:"event_id"`
+	EventTitle string    `json:"event_title"`
+	Count      int64     `json:"count"`
+}
+
+// withDeleted lets a preload find soft deleted rows, such as the deleted
+// verifier of an old action log
+func withDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
 }
@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AnonymizedName replaces the name of anonymized participants
+const AnonymizedName = "Anonymized participant"
+
+// RetentionRepository stores retention rules and legal holds and applies
+// retention actions. Soft-deleted participants are included, as their
+// personal data is still in the database.
+type RetentionRepository interface {
+	CreateRule(rule *models.RetentionRule) error
+	ListRules() ([]models.RetentionRule, error)
+	DeleteRule(id string) error
+	CreateLegalHold(hold *models.LegalHold) error
+	ListLegalHolds() ([]models.LegalHold, error)
+	DeleteLegalHold(eventID string) error
+	ListEventsEndedBefore(cutoff time.Time) ([]models.Event, error)
+	CountPendingAnonymization(eventID string) (int64, error)
+	AnonymizeParticipants(eventID string, at time.Time) (int64, error)
+	// AnonymizeParticipant erases the personal data of one participant like
+	// AnonymizeParticipants. It fails with gorm.ErrRecordNotFound when the
+	// participant does not exist or was anonymized already.
+	AnonymizeParticipant(participantID string, at time.Time) error
+	CountQRFiles(eventID string) (int64, error)
+	ListQRFiles(eventID string, limit int) ([]models.Participant, error)
+	ClearQRPaths(ids []uuid.UUID) error
+}
+
+type retentionRepo struct {
+	db *gorm.DB
+}
+
+func NewRetentionRepository(db *gorm.DB) RetentionRepository {
+	return &retentionRepo{db: db}
+}
+
+func (r *retentionRepo) CreateRule(rule *models.RetentionRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *retentionRepo) ListRules() ([]models.RetentionRule, error) {
+	var rules []models.RetentionRule
+	if err := r.db.Order("created_at ASC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list retention rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *retentionRepo) DeleteRule(id string) error {
+	result := r.db.Where("id = ?", id).Delete(&models.RetentionRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *retentionRepo) CreateLegalHold(hold *models.LegalHold) error {
+	return r.db.Omit(clause.Associations).Create(hold).Error
+}
+
+func (r *retentionRepo) ListLegalHolds() ([]models.LegalHold, error) {
+	var holds []models.LegalHold
+	if err := r.db.Order("created_at ASC").Find(&holds).Error; err != nil {
+		return nil, fmt.Errorf("failed to list legal holds: %w", err)
+	}
+	return holds, nil
+}
+
+func (r *retentionRepo) DeleteLegalHold(eventID string) error {
+	result := r.db.Where("event_id = ?", eventID).Delete(&models.LegalHold{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListEventsEndedBefore returns the events that ended before cutoff, oldest
+// first
+func (r *retentionRepo) ListEventsEndedBefore(cutoff time.Time) ([]models.Event, error) {
+	var events []models.Event
+	if err := r.db.Where("ends_at < ?", cutoff).Order("ends_at ASC").Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to list ended events: %w", err)
+	}
+	return events, nil
+}
+
+func (r *retentionRepo) CountPendingAnonymization(eventID string) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Participant{}).
+		Where("event_id = ? AND anonymized_at IS NULL", eventID).
+		Count(&count).Error
+	return count, err
+}
+
+// AnonymizeParticipants erases the personal data of the participants of an
+// event: their contact details and answers, the signer details of their
+// waiver signatures, the notes staff and sponsors kept on them and the
+// waitlist entries they were promoted from. Attendance records are kept, so
+// event statistics stay intact. Returns the number of participants
+// anonymized.
+func (r *retentionRepo) AnonymizeParticipants(eventID string, at time.Time) (int64, error) {
+	var anonymized int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		anonymized, err = anonymizeParticipants(tx, "event_id = ?", eventID, at)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to anonymize participants: %w", err)
+	}
+	return anonymized, nil
+}
+
+func (r *retentionRepo) AnonymizeParticipant(participantID string, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		anonymized, err := anonymizeParticipants(tx, "id = ?", participantID, at)
+		if err != nil {
+			return err
+		}
+		if anonymized == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// anonymizeParticipants erases the personal data of the participants the
+// condition selects, see AnonymizeParticipants
+func anonymizeParticipants(tx *gorm.DB, condition string, arg interface{}, at time.Time) (int64, error) {
+	result := tx.Unscoped().Model(&models.Participant{}).
+		Where(condition+" AND anonymized_at IS NULL", arg).
+		Updates(map[string]interface{}{
+			"name":                AnonymizedName,
+			"email":               "",
+			"email_hash":          "",
+			"phone_hash":          "",
+			"phone":               "",
+			"address":             "",
+			"division":            "",
+			"answers":             nil,
+			"rejection_reason":    "",
+			"cancellation_reason": "",
+			"anonymized_at":       at,
+			"version":             gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	participantIDs := tx.Unscoped().Model(&models.Participant{}).Select("id").Where(condition, arg)
+	if err := tx.Where("participant_id IN (?)", participantIDs).Delete(&models.ParticipantNote{}).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Model(&models.Lead{}).Where("participant_id IN (?)", participantIDs).Update("notes", "").Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Model(&models.WaitlistEntry{}).Where("participant_id IN (?)", participantIDs).
+		Updates(map[string]interface{}{
+			"name":       AnonymizedName,
+			"email":      "",
+			"email_hash": "",
+			"phone":      "",
+			"address":    "",
+			"division":   "",
+			"answers":    nil,
+		}).Error; err != nil {
+		return 0, err
+	}
+	err := tx.Model(&models.WaiverSignature{}).
+		Where("participant_id IN (?)", participantIDs).
+		Updates(map[string]interface{}{
+			"typed_name": "",
+			"ip_address": "",
+			"user_agent": "",
+		}).Error
+	return result.RowsAffected, err
+}
+
+func (r *retentionRepo) CountQRFiles(eventID string) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.Participant{}).
+		Where("event_id = ? AND qr_path <> ''", eventID).
+		Count(&count).Error
+	return count, err
+}
+
+// ListQRFiles returns up to limit participants of an event that still have a
+// QR code file
+func (r *retentionRepo) ListQRFiles(eventID string, limit int) ([]models.Participant, error) {
+	var participants []models.Participant
+	if err := r.db.Unscoped().Select("id", "qr_path").
+		Where("event_id = ? AND qr_path <> ''", eventID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&participants).Error; err != nil {
+		return nil, err
+	}
+	return participants, nil
+}
+
+func (r *retentionRepo) ClearQRPaths(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Unscoped().Model(&models.Participant{}).Where("id IN ?", ids).UpdateColumn("qr_path", "").Error
+}
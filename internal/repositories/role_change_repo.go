@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoleChangeRepository interface {
+	CreateRoleChange(change *models.RoleChangeRequest) error
+	GetRoleChange(id string) (*models.RoleChangeRequest, error)
+	HasPendingRoleChange(userID string) (bool, error)
+	ListRoleChanges(status string, offset, limit int) ([]models.RoleChangeRequest, int64, error)
+	// ReviewRoleChange records the decision on a pending change. Approving
+	// also sets the user's role and revokes their tokens. It returns
+	// gorm.ErrRecordNotFound when the change is no longer pending, and
+	// ErrVersionConflict when the user's role changed since the request.
+	ReviewRoleChange(id string, reviewerID uuid.UUID, approve bool, at time.Time) error
+}
+
+type roleChangeRepo struct {
+	db *gorm.DB
+}
+
+func NewRoleChangeRepository(db *gorm.DB) RoleChangeRepository {
+	return &roleChangeRepo{db: db}
+}
+
+func (r *roleChangeRepo) CreateRoleChange(change *models.RoleChangeRequest) error {
+	return r.db.Omit(clause.Associations).Create(change).Error
+}
+
+func (r *roleChangeRepo) GetRoleChange(id string) (*models.RoleChangeRequest, error) {
+	var change models.RoleChangeRequest
+	if err := r.db.Preload("User", withDeleted).Preload("Requester", withDeleted).
+		Where("id = ?", id).First(&change).Error; err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (r *roleChangeRepo) HasPendingRoleChange(userID string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.RoleChangeRequest{}).
+		Where("user_id = ? AND status = ?", userID, "pending").
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListRoleChanges returns changes newest first, optionally only those of a
+// status
+func (r *roleChangeRepo) ListRoleChanges(status string, offset, limit int) ([]models.RoleChangeRequest, int64, error) {
+	var changes []models.RoleChangeRequest
+	var total int64
+
+	query := r.db.Model(&models.RoleChangeRequest{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("User", withDeleted).Preload("Requester", withDeleted).
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&changes).Error; err != nil {
+		return nil, 0, err
+	}
+	return changes, total, nil
+}
+
+func (r *roleChangeRepo) ReviewRoleChange(id string, reviewerID uuid.UUID, approve bool, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var change models.RoleChangeRequest
+		if err := tx.Where("id = ? AND status = ?", id, "pending").First(&change).Error; err != nil {
+			return err
+		}
+
+		status := "rejected"
+		if approve {
+			status = "approved"
+			// Only applies while the user still has the role the change was
+			// requested from
+			result := tx.Model(&models.User{}).
+				Where("id = ? AND role = ?", change.UserID, change.FromRole).
+				Updates(map[string]interface{}{"role": change.ToRole, "tokens_revoked_at": at})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrVersionConflict
+			}
+		}
+
+		result := tx.Model(&models.RoleChangeRequest{}).
+			Where("id = ? AND status = ?", id, "pending").
+			Updates(map[string]interface{}{"status": status, "reviewed_by": reviewerID, "reviewed_at": at})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
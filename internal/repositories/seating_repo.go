@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrSeatTaken is returned when the requested seat belongs to someone else
+	ErrSeatTaken = errors.New("seat is already taken")
+	// ErrTableFull is returned when no seat is left at the table
+	ErrTableFull = errors.New("table is full")
+)
+
+type SeatingRepository interface {
+	CreateTable(table *models.SeatingTable) error
+	GetTableByID(id string) (*models.SeatingTable, error)
+	ListTablesByDay(dayID string) ([]models.SeatingTable, error)
+	AssignSeat(assignment *models.SeatAssignment) error
+	CreateAssignments(assignments []models.SeatAssignment) error
+	DeleteAssignment(dayID, participantID string) error
+	GetAssignment(dayID, participantID string) (*models.SeatAssignment, error)
+	ListAssignmentsByDay(dayID string) ([]models.SeatAssignment, error)
+	ListAssignmentsByEvent(eventID string) ([]models.SeatAssignment, error)
+}
+
+type seatingRepo struct {
+	db *gorm.DB
+}
+
+func NewSeatingRepository(db *gorm.DB) SeatingRepository {
+	return &seatingRepo{db: db}
+}
+
+func (r *seatingRepo) CreateTable(table *models.SeatingTable) error {
+	return r.db.Create(table).Error
+}
+
+func (r *seatingRepo) GetTableByID(id string) (*models.SeatingTable, error) {
+	var table models.SeatingTable
+	if err := r.db.Where("id = ?", id).First(&table).Error; err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+func (r *seatingRepo) ListTablesByDay(dayID string) ([]models.SeatingTable, error) {
+	var tables []models.SeatingTable
+	if err := r.db.Where("event_day_id = ?", dayID).Order("name ASC").Find(&tables).Error; err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	return tables, nil
+}
+
+// AssignSeat seats a participant, replacing their previous seat of the day.
+// A zero SeatNumber takes the lowest free seat. The table row is locked so
+// two organizers cannot hand out the same seat.
+func (r *seatingRepo) AssignSeat(assignment *models.SeatAssignment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var table models.SeatingTable
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", assignment.TableID).
+			First(&table).Error; err != nil {
+			return err
+		}
+
+		var taken []int
+		if err := tx.Model(&models.SeatAssignment{}).
+			Where("table_id = ? AND participant_id <> ?", table.ID, assignment.ParticipantID).
+			Pluck("seat_number", &taken).Error; err != nil {
+			return err
+		}
+		seat, err := PickSeat(table.Capacity, taken, assignment.SeatNumber)
+		if err != nil {
+			return err
+		}
+		assignment.SeatNumber = seat
+
+		if err := tx.Where("event_day_id = ? AND participant_id = ?", assignment.EventDayID, assignment.ParticipantID).
+			Delete(&models.SeatAssignment{}).Error; err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Create(assignment).Error
+	})
+}
+
+// CreateAssignments inserts the seats of an auto-assignment, all or none
+func (r *seatingRepo) CreateAssignments(assignments []models.SeatAssignment) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).CreateInBatches(assignments, 100).Error; err != nil {
+			return fmt.Errorf("failed to save seat assignments: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *seatingRepo) DeleteAssignment(dayID, participantID string) error {
+	return r.db.Where("event_day_id = ? AND participant_id = ?", dayID, participantID).
+		Delete(&models.SeatAssignment{}).Error
+}
+
+func (r *seatingRepo) GetAssignment(dayID, participantID string) (*models.SeatAssignment, error) {
+	var assignment models.SeatAssignment
+	if err := r.db.Preload("Table").
+		Where("event_day_id = ? AND participant_id = ?", dayID, participantID).
+		First(&assignment).Error; err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (r *seatingRepo) ListAssignmentsByDay(dayID string) ([]models.SeatAssignment, error) {
+	var assignments []models.SeatAssignment
+	if err := r.db.Preload("Participant").
+		Where("event_day_id = ?", dayID).
+		Order("seat_number ASC").
+		Find(&assignments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list seat assignments: %w", err)
+	}
+	return assignments, nil
+}
+
+// ListAssignmentsByEvent returns the seats of every day of an event with
+// their tables and participants
+func (r *seatingRepo) ListAssignmentsByEvent(eventID string) ([]models.SeatAssignment, error) {
+	var assignments []models.SeatAssignment
+	tables := r.db.Model(&models.SeatingTable{}).Select("id").Where("event_id = ?", eventID)
+	if err := r.db.Preload("Table").Preload("Participant").
+		Where("table_id IN (?)", tables).
+		Order("seat_number ASC").
+		Find(&assignments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list seat assignments: %w", err)
+	}
+	return assignments, nil
+}
+
+// PickSeat returns requested if it is free, or the lowest free seat when
+// requested is zero
+func PickSeat(capacity int, taken []int, requested int) (int, error) {
+	used := make(map[int]bool, len(taken))
+	for _, seat := range taken {
+		used[seat] = true
+	}
+
+	if requested != 0 {
+		if requested < 1 || requested > capacity {
+			return 0, fmt.Errorf("seat number must be between 1 and %d", capacity)
+		}
+		if used[requested] {
+			return 0, ErrSeatTaken
+		}
+		return requested, nil
+	}
+
+	for seat := 1; seat <= capacity; seat++ {
+		if !used[seat] {
+			return seat, nil
+		}
+	}
+	return 0, ErrTableFull
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSessionFull is returned when a check-in would exceed the room capacity
+var ErrSessionFull = errors.New("session is at full capacity")
+
+type SessionRepository interface {
+	CreateSession(session *models.Session, action *models.EventAction) error
+	GetSessionByID(id string) (*models.Session, error)
+	GetSessionByActionID(actionID string) (*models.Session, error)
+	ListSessionsByEvent(eventID string) ([]models.Session, error)
+	CreateSessionCheckIn(session *models.Session, log *models.ActionLog) error
+	GetSessionAttendance(session *models.Session) ([]models.ActionLog, error)
+}
+
+type sessionRepo struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepo{db: db}
+}
+
+// CreateSession creates a session together with the action used to scan it
+func (r *sessionRepo) CreateSession(session *models.Session, action *models.EventAction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.EventAction
+		if err := tx.Where("code = ?", action.Code).First(&existing).Error; err == nil {
+			return fmt.Errorf("event action with code '%s' already exists", action.Code)
+		}
+
+		if err := tx.Create(action).Error; err != nil {
+			return fmt.Errorf("failed to create session action: %w", err)
+		}
+
+		session.ActionID = action.ID
+		if err := tx.Omit(clause.Associations).Create(session).Error; err != nil {
+			return fmt.Errorf("failed to create session: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r *sessionRepo) GetSessionByID(id string) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.Preload("Action").Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepo) GetSessionByActionID(actionID string) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.Where("action_id = ?", actionID).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListSessionsByEvent returns the agenda of an event in chronological order
+func (r *sessionRepo) ListSessionsByEvent(eventID string) ([]models.Session, error) {
+	var sessions []models.Session
+	if err := r.db.Preload("Action").
+		Where("event_id = ?", eventID).
+		Order("starts_at ASC, title ASC").
+		Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// CreateSessionCheckIn records a session scan, failing with ErrSessionFull
+// once capacity is reached. The session row is locked so simultaneous scans
+// at several doors cannot overfill the room.
+func (r *sessionRepo) CreateSessionCheckIn(session *models.Session, log *models.ActionLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var locked models.Session
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", session.ID).
+			First(&locked).Error; err != nil {
+			return err
+		}
+
+		if locked.Capacity != nil {
+			var attendance int64
+			if err := tx.Model(&models.ActionLog{}).
+				Where("action_id = ?", locked.ActionID).
+				Count(&attendance).Error; err != nil {
+				return err
+			}
+			if attendance >= int64(*locked.Capacity) {
+				return ErrSessionFull
+			}
+		}
+		if err := checkDayCapacity(tx, log); err != nil {
+			return err
+		}
+
+		return tx.Omit(clause.Associations).Create(log).Error
+	})
+}
+
+// GetSessionAttendance returns the check-ins of a session with participants,
+// in scan order
+func (r *sessionRepo) GetSessionAttendance(session *models.Session) ([]models.ActionLog, error) {
+	var logs []models.ActionLog
+	if err := r.db.Preload("Participant").
+		Where("action_id = ?", session.ActionID).
+		Order("verified_at ASC").
+		Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get session attendance: %w", err)
+	}
+	return logs, nil
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShiftRepository interface {
+	CreateShift(shift *models.Shift) error
+	GetShiftByID(id string) (*models.Shift, error)
+	ListShiftsByEvent(eventID string) ([]models.Shift, error)
+	ListShiftsByUser(userID string, endsAfter time.Time) ([]models.Shift, error)
+	DeleteShift(id string) error
+	AssignStaff(shiftID, userID string) error
+	UnassignStaff(shiftID, userID string) error
+	HasShifts(eventID string) (bool, error)
+	IsOnShift(userID string, action *models.EventAction, at time.Time, grace time.Duration) (bool, error)
+}
+
+type shiftRepo struct {
+	db *gorm.DB
+}
+
+func NewShiftRepository(db *gorm.DB) ShiftRepository {
+	return &shiftRepo{db: db}
+}
+
+func (r *shiftRepo) CreateShift(shift *models.Shift) error {
+	return r.db.Omit(clause.Associations).Create(shift).Error
+}
+
+func (r *shiftRepo) GetShiftByID(id string) (*models.Shift, error) {
+	var shift models.Shift
+	if err := r.db.Preload("Action").Preload("Assignments.User").
+		Where("id = ?", id).
+		First(&shift).Error; err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+// ListShiftsByEvent returns the shifts of an event with their gate and
+// staff, in rota order
+func (r *shiftRepo) ListShiftsByEvent(eventID string) ([]models.Shift, error) {
+	var shifts []models.Shift
+	if err := r.db.Preload("Action").Preload("Assignments.User").
+		Where("event_id = ?", eventID).
+		Order("starts_at ASC, name ASC").
+		Find(&shifts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list shifts: %w", err)
+	}
+	return shifts, nil
+}
+
+// ListShiftsByUser returns the shifts a user works that end after the given
+// time, soonest first
+func (r *shiftRepo) ListShiftsByUser(userID string, endsAfter time.Time) ([]models.Shift, error) {
+	var shifts []models.Shift
+	assigned := r.db.Model(&models.ShiftAssignment{}).Select("shift_id").Where("user_id = ?", userID)
+	if err := r.db.Preload("Action").
+		Where("id IN (?) AND ends_at > ?", assigned, endsAfter).
+		Order("starts_at ASC").
+		Find(&shifts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list shifts: %w", err)
+	}
+	return shifts, nil
+}
+
+// DeleteShift removes a shift and its staff assignments
+func (r *shiftRepo) DeleteShift(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("shift_id = ?", id).Delete(&models.ShiftAssignment{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.Shift{}).Error
+	})
+}
+
+// AssignStaff puts a user on a shift; assigning twice is a no-op
+func (r *shiftRepo) AssignStaff(shiftID, userID string) error {
+	assignment := map[string]interface{}{
+		"shift_id":   shiftID,
+		"user_id":    userID,
+		"created_at": time.Now(),
+	}
+	return r.db.Model(&models.ShiftAssignment{}).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(assignment).Error
+}
+
+func (r *shiftRepo) UnassignStaff(shiftID, userID string) error {
+	return r.db.Where("shift_id = ? AND user_id = ?", shiftID, userID).
+		Delete(&models.ShiftAssignment{}).Error
+}
+
+func (r *shiftRepo) HasShifts(eventID string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Shift{}).Where("event_id = ?", eventID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// IsOnShift reports whether the user is assigned to a shift covering the
+// action at the given time, widened by grace on both ends
+func (r *shiftRepo) IsOnShift(userID string, action *models.EventAction, at time.Time, grace time.Duration) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Shift{}).
+		Joins("JOIN shift_assignments ON shift_assignments.shift_id = shifts.id").
+		Where("shift_assignments.user_id = ? AND shifts.event_day_id = ?", userID, action.EventDayID).
+		Where("shifts.action_id IS NULL OR shifts.action_id = ?", action.ID).
+		Where("shifts.starts_at <= ? AND shifts.ends_at >= ?", at.Add(grace), at.Add(-grace)).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
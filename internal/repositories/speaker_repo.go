@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SpeakerRepository interface {
+	CreateSpeaker(speaker *models.Speaker, sessionIDs []uuid.UUID) error
+	GetSpeakerByID(id string) (*models.Speaker, error)
+	ListSpeakersByEvent(eventID string) ([]models.Speaker, error)
+	UpdateSpeakerPhotoVariants(id string, variants map[string]string) error
+	CreateSpeakerCheckIn(checkIn *models.SpeakerCheckIn) error
+	ListSpeakerCheckIns(speakerID string) ([]models.SpeakerCheckIn, error)
+}
+
+type speakerRepo struct {
+	db *gorm.DB
+}
+
+func NewSpeakerRepository(db *gorm.DB) SpeakerRepository {
+	return &speakerRepo{db: db}
+}
+
+// CreateSpeaker creates a speaker and links them to the given sessions
+func (r *speakerRepo) CreateSpeaker(speaker *models.Speaker, sessionIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(speaker).Error; err != nil {
+			return fmt.Errorf("failed to create speaker: %w", err)
+		}
+
+		if len(sessionIDs) == 0 {
+			return nil
+		}
+		links := make([]models.SpeakerSession, 0, len(sessionIDs))
+		for _, id := range sessionIDs {
+			links = append(links, models.SpeakerSession{SpeakerID: speaker.ID, SessionID: id})
+		}
+		if err := tx.Create(&links).Error; err != nil {
+			return fmt.Errorf("failed to link speaker sessions: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *speakerRepo) GetSpeakerByID(id string) (*models.Speaker, error) {
+	var speaker models.Speaker
+	if err := r.db.Preload("Sessions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("starts_at ASC")
+	}).Where("id = ?", id).First(&speaker).Error; err != nil {
+		return nil, err
+	}
+	return &speaker, nil
+}
+
+func (r *speakerRepo) ListSpeakersByEvent(eventID string) ([]models.Speaker, error) {
+	var speakers []models.Speaker
+	if err := r.db.Preload("Sessions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("starts_at ASC")
+	}).Where("event_id = ?", eventID).
+		Order("name ASC").
+		Find(&speakers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list speakers: %w", err)
+	}
+	return speakers, nil
+}
+
+// UpdateSpeakerPhotoVariants stores the generated photo variants
+func (r *speakerRepo) UpdateSpeakerPhotoVariants(id string, variants map[string]string) error {
+	data, err := json.Marshal(variants)
+	if err != nil {
+		return fmt.Errorf("failed to encode photo variants: %w", err)
+	}
+
+	if err := r.db.Model(&models.Speaker{}).
+		Where("id = ?", id).
+		UpdateColumn("photo_variants", string(data)).Error; err != nil {
+		return fmt.Errorf("failed to update photo variants: %w", err)
+	}
+
+	return nil
+}
+
+func (r *speakerRepo) CreateSpeakerCheckIn(checkIn *models.SpeakerCheckIn) error {
+	return r.db.Omit(clause.Associations).Create(checkIn).Error
+}
+
+// ListSpeakerCheckIns returns the scans of a speaker, newest first
+func (r *speakerRepo) ListSpeakerCheckIns(speakerID string) ([]models.SpeakerCheckIn, error) {
+	var checkIns []models.SpeakerCheckIn
+	if err := r.db.Preload("Action").
+		Where("speaker_id = ?", speakerID).
+		Order("verified_at DESC").
+		Find(&checkIns).Error; err != nil {
+		return nil, fmt.Errorf("failed to list speaker check-ins: %w", err)
+	}
+	return checkIns, nil
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SponsorRepository interface {
+	CreateSponsor(sponsor *models.Sponsor) error
+	GetSponsorByID(id string) (*models.Sponsor, error)
+	ListSponsorsByEvent(eventID string) ([]models.Sponsor, error)
+	SaveLead(lead *models.Lead) error
+	ListLeadsBySponsor(sponsorID string) ([]models.Lead, error)
+}
+
+type sponsorRepo struct {
+	db *gorm.DB
+}
+
+func NewSponsorRepository(db *gorm.DB) SponsorRepository {
+	return &sponsorRepo{db: db}
+}
+
+func (r *sponsorRepo) CreateSponsor(sponsor *models.Sponsor) error {
+	return r.db.Create(sponsor).Error
+}
+
+func (r *sponsorRepo) GetSponsorByID(id string) (*models.Sponsor, error) {
+	var sponsor models.Sponsor
+	if err := r.db.Where("id = ?", id).First(&sponsor).Error; err != nil {
+		return nil, err
+	}
+	return &sponsor, nil
+}
+
+func (r *sponsorRepo) ListSponsorsByEvent(eventID string) ([]models.Sponsor, error) {
+	var sponsors []models.Sponsor
+	if err := r.db.Where("event_id = ?", eventID).Order("name ASC").Find(&sponsors).Error; err != nil {
+		return nil, fmt.Errorf("failed to list sponsors: %w", err)
+	}
+	return sponsors, nil
+}
+
+// SaveLead records a lead, or updates the consent and notes when the sponsor
+// already scanned the participant. lead.ID is set to the stored lead's ID.
+func (r *sponsorRepo) SaveLead(lead *models.Lead) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.Lead
+		err := tx.Where("sponsor_id = ? AND participant_id = ?", lead.SponsorID, lead.ParticipantID).
+			First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Omit(clause.Associations).Create(lead).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		lead.ID = existing.ID
+		lead.CreatedAt = existing.CreatedAt
+		return tx.Model(&existing).Updates(map[string]interface{}{
+			"consent":     lead.Consent,
+			"notes":       lead.Notes,
+			"captured_by": lead.CapturedBy,
+			"captured_at": lead.CapturedAt,
+		}).Error
+	})
+}
+
+// ListLeadsBySponsor returns the leads of a sponsor with participants, in
+// capture order
+func (r *sponsorRepo) ListLeadsBySponsor(sponsorID string) ([]models.Lead, error) {
+	var leads []models.Lead
+	if err := r.db.Preload("Participant").
+		Where("sponsor_id = ?", sponsorID).
+		Order("captured_at ASC").
+		Find(&leads).Error; err != nil {
+		return nil, fmt.Errorf("failed to list leads: %w", err)
+	}
+	return leads, nil
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"fmt"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type TicketTypeRepository interface {
+	CreateTicketType(ticketType *models.TicketType) error
+	GetTicketType(id string) (*models.TicketType, error)
+	// ListTicketTypesByEvent returns the ticket types of an event, cheapest
+	// first
+	ListTicketTypesByEvent(eventID string) ([]models.TicketType, error)
+	UpdateTicketType(ticketType *models.TicketType) error
+	DeleteTicketType(id string) error
+}
+
+type ticketTypeRepo struct {
+	db *gorm.DB
+}
+
+func NewTicketTypeRepository(db *gorm.DB) TicketTypeRepository {
+	return &ticketTypeRepo{db: db}
+}
+
+func (r *ticketTypeRepo) CreateTicketType(ticketType *models.TicketType) error {
+	return r.db.Create(ticketType).Error
+}
+
+func (r *ticketTypeRepo) GetTicketType(id string) (*models.TicketType, error) {
+	var ticketType models.TicketType
+	if err := r.db.Where("id = ?", id).First(&ticketType).Error; err != nil {
+		return nil, err
+	}
+	return &ticketType, nil
+}
+
+func (r *ticketTypeRepo) ListTicketTypesByEvent(eventID string) ([]models.TicketType, error) {
+	var ticketTypes []models.TicketType
+	if err := r.db.Where("event_id = ?", eventID).Order("price ASC, name ASC").Find(&ticketTypes).Error; err != nil {
+		return nil, fmt.Errorf("failed to list ticket types: %w", err)
+	}
+	return ticketTypes, nil
+}
+
+func (r *ticketTypeRepo) UpdateTicketType(ticketType *models.TicketType) error {
+	result := r.db.Model(ticketType).Select("*").Omit("id", "event_id", "created_at").Updates(ticketType)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *ticketTypeRepo) DeleteTicketType(id string) error {
+	result := r.db.Where("id = ?", id).Delete(&models.TicketType{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
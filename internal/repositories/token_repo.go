@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TokenRepository interface {
+	RevokeToken(token *models.RevokedToken) error
+	IsTokenRevoked(jti string) (bool, error)
+	DeleteExpiredRevokedTokens(now time.Time) (int64, error)
+	CreateServiceToken(token *models.ServiceToken) error
+	GetServiceToken(id string) (*models.ServiceToken, error)
+	ListServiceTokens(userID string) ([]models.ServiceToken, error)
+	RevokeServiceToken(userID, id string, at time.Time) error
+	CreateRefreshToken(token *models.RefreshToken) error
+	GetRefreshTokenByHash(hash string) (*models.RefreshToken, error)
+	// RevokeRefreshToken returns gorm.ErrRecordNotFound when the token was
+	// already revoked, so only one of concurrent refreshes succeeds
+	RevokeRefreshToken(id string, at time.Time) error
+	DeleteExpiredRefreshTokens(now time.Time) (int64, error)
+}
+
+type tokenRepo struct {
+	db *gorm.DB
+}
+
+func NewTokenRepository(db *gorm.DB) TokenRepository {
+	return &tokenRepo{db: db}
+}
+
+// RevokeToken denylists a token. Revoking it again is a no-op.
+func (r *tokenRepo) RevokeToken(token *models.RevokedToken) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+func (r *tokenRepo) IsTokenRevoked(jti string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DeleteExpiredRevokedTokens drops entries of tokens that expired, which are
+// rejected anyway
+func (r *tokenRepo) DeleteExpiredRevokedTokens(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", now).Delete(&models.RevokedToken{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *tokenRepo) CreateServiceToken(token *models.ServiceToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *tokenRepo) GetServiceToken(id string) (*models.ServiceToken, error) {
+	var token models.ServiceToken
+	if err := r.db.Where("id = ?", id).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListServiceTokens returns the tokens of a service account, newest first
+func (r *tokenRepo) ListServiceTokens(userID string) ([]models.ServiceToken, error) {
+	var tokens []models.ServiceToken
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// RevokeServiceToken revokes a token of the given service account. Revoking
+// it again keeps the first revocation time.
+func (r *tokenRepo) RevokeServiceToken(userID, id string, at time.Time) error {
+	token, err := r.GetServiceToken(id)
+	if err != nil {
+		return err
+	}
+	if token.UserID.String() != userID {
+		return gorm.ErrRecordNotFound
+	}
+	return r.db.Model(&models.ServiceToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+func (r *tokenRepo) CreateRefreshToken(token *models.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *tokenRepo) GetRefreshTokenByHash(hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *tokenRepo) RevokeRefreshToken(id string, at time.Time) error {
+	result := r.db.Model(&models.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *tokenRepo) DeleteExpiredRefreshTokens(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", now).Delete(&models.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
@@ -1,7 +1,10 @@
 package repositories
 
 import (
+	"time"
+
 	"event-management-backend/internal/models"
+
 	"gorm.io/gorm"
 )
 
@@ -35,4 +38,130 @@ func (r *userRepo) CreateUser(user *models.User) error {
 
 func (r *userRepo) UpdateUser(user *models.User) error {
 	return r.db.Save(user).Error
-}
\ No newline at end of file
+}
+
+// ListUsers returns users newest first, optionally only those of a role
+func (r *userRepo) ListUsers(role string, offset, limit int) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	query := r.db.Model(&models.User{})
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Offset(offset).Limit(limit).
+		Order("created_at DESC").
+		Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func (r *userRepo) DeactivateUser(id string) error {
+	result := r.db.Model(&models.User{}).Where("id = ?", id).Update("is_active", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteUser deactivates and soft deletes a user, revoking their tokens and
+// removing their event assignments. Their action logs are kept, or moved to
+// deletion.ReassignTo.
+func (r *userRepo) DeleteUser(id string, deletion UserDeletion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		user := models.User{}
+		if err := tx.Where("id = ?", id).First(&user).Error; err != nil {
+			return err
+		}
+
+		if deletion.ReassignTo != nil {
+			if err := tx.Model(&models.ActionLog{}).Where("verified_by = ?", id).
+				Update("verified_by", *deletion.ReassignTo).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.EventStaff{}).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"is_active":         false,
+			"tokens_revoked_at": deletion.At,
+		}
+		if deletion.Anonymize {
+			updates["email"] = DeletedUserEmail(user.ID)
+			updates["name"] = ""
+			updates["last_login_ip"] = ""
+			if err := tx.Where("user_id = ?", id).Delete(&models.LoginEvent{}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&user).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&user).Error
+	})
+}
+
+// RevokeUserTokens rejects all tokens of a user issued up to at
+func (r *userRepo) RevokeUserTokens(id string, at time.Time) error {
+	result := r.db.Model(&models.User{}).Where("id = ?", id).Update("tokens_revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UseTOTPStep records that a code of the given time step was accepted. It
+// reports false when a code of that or a later step was already used.
+func (r *userRepo) UseTOTPStep(userID string, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		UpdateColumn("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RecordLogin stores a login and sets it as the user's last login
+func (r *userRepo) RecordLogin(login *models.LoginEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(login).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", login.UserID).UpdateColumns(map[string]interface{}{
+			"last_login_at": login.CreatedAt,
+			"last_login_ip": login.IP,
+		}).Error
+	})
+}
+
+// ListLoginEvents returns a user's most recent logins, newest first
+func (r *userRepo) ListLoginEvents(userID string, limit int) ([]models.LoginEvent, error) {
+	var logins []models.LoginEvent
+	if err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&logins).Error; err != nil {
+		return nil, err
+	}
+	return logins, nil
+}
+
+func (r *userRepo) DeleteLoginEventsBefore(before time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", before).Delete(&models.LoginEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrVenueInUse is returned when deleting a venue events or days are held at
+var ErrVenueInUse = errors.New("venue is used by events")
+
+type VenueRepository interface {
+	CreateVenue(venue *models.Venue) error
+	GetVenue(id string) (*models.Venue, error)
+	// ListVenues returns venues by name, optionally only those whose name or
+	// address contains search
+	ListVenues(search string, offset, limit int) ([]models.Venue, int64, error)
+	UpdateVenue(venue *models.Venue) error
+	DeleteVenue(id string) error
+}
+
+type venueRepo struct {
+	db *gorm.DB
+}
+
+func NewVenueRepository(db *gorm.DB) VenueRepository {
+	return &venueRepo{db: db}
+}
+
+func (r *venueRepo) CreateVenue(venue *models.Venue) error {
+	return r.db.Create(venue).Error
+}
+
+func (r *venueRepo) GetVenue(id string) (*models.Venue, error) {
+	var venue models.Venue
+	if err := r.db.Where("id = ?", id).First(&venue).Error; err != nil {
+		return nil, err
+	}
+	return &venue, nil
+}
+
+func (r *venueRepo) ListVenues(search string, offset, limit int) ([]models.Venue, int64, error) {
+	var venues []models.Venue
+	var total int64
+
+	query := r.db.Model(&models.Venue{})
+	if search != "" {
+		term := "%" + strings.ToLower(search) + "%"
+		query = query.Where(containsFold("name")+" OR "+containsFold("address"), term, term)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count venues: %w", err)
+	}
+
+	if err := query.Order("name, id").Offset(offset).Limit(limit).Find(&venues).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list venues: %w", err)
+	}
+	return venues, total, nil
+}
+
+func (r *venueRepo) UpdateVenue(venue *models.Venue) error {
+	result := r.db.Model(venue).Select("*").Omit("id", "created_at").Updates(venue)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteVenue removes a venue no event or day refers to, and returns
+// ErrVenueInUse otherwise
+func (r *venueRepo) DeleteVenue(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var uses int64
+		if err := tx.Model(&models.Event{}).Where("venue_id = ?", id).Count(&uses).Error; err != nil {
+			return err
+		}
+		if uses == 0 {
+			if err := tx.Model(&models.EventDay{}).Where("venue_id = ?", id).Count(&uses).Error; err != nil {
+				return err
+			}
+		}
+		if uses > 0 {
+			return ErrVenueInUse
+		}
+
+		result := tx.Where("id = ?", id).Delete(&models.Venue{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"event-management-backend/internal/fieldcrypt"
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WaitlistRepository interface {
+	CreateWaitlistEntry(entry *models.WaitlistEntry) error
+	GetWaitlistEntry(id string) (*models.WaitlistEntry, error)
+	// FindWaitingEntry returns the entry of an email address still waiting
+	// for an event, or gorm.ErrRecordNotFound
+	FindWaitingEntry(eventID, email string) (*models.WaitlistEntry, error)
+	// ListWaitingEntries returns the entries of an event still waiting in
+	// promotion order: oldest first, after the highest priority when
+	// byPriority
+	ListWaitingEntries(eventID string, byPriority bool) ([]models.WaitlistEntry, error)
+	UpdateWaitlistPriority(id string, priority int) error
+	// PromoteWaitlistEntry registers participant for a waiting entry and
+	// marks the entry promoted, in one transaction with the event locked. It
+	// fails with ErrTicketQuotaExceeded when the event already holds limit
+	// participants, and with ErrVersionConflict when the entry is no longer
+	// waiting. A nil limit is unlimited.
+	PromoteWaitlistEntry(entry *models.WaitlistEntry, participant *models.Participant, limit *int) error
+}
+
+type waitlistRepo struct {
+	db *gorm.DB
+}
+
+func NewWaitlistRepository(db *gorm.DB) WaitlistRepository {
+	return &waitlistRepo{db: db}
+}
+
+func (r *waitlistRepo) CreateWaitlistEntry(entry *models.WaitlistEntry) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *waitlistRepo) GetWaitlistEntry(id string) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	if err := r.db.Where("id = ?", id).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *waitlistRepo) FindWaitingEntry(eventID, email string) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	if err := r.db.Where("event_id = ? AND email_hash = ? AND status = ?", eventID, fieldcrypt.Hash(email), "waiting").
+		First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *waitlistRepo) ListWaitingEntries(eventID string, byPriority bool) ([]models.WaitlistEntry, error) {
+	query := r.db.Where("event_id = ? AND status = ?", eventID, "waiting")
+	if byPriority {
+		query = query.Order("priority DESC")
+	}
+	var entries []models.WaitlistEntry
+	if err := query.Order("created_at ASC, id ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *waitlistRepo) UpdateWaitlistPriority(id string, priority int) error {
+	result := r.db.Model(&models.WaitlistEntry{}).Where("id = ?", id).Update("priority", priority)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *waitlistRepo) PromoteWaitlistEntry(entry *models.WaitlistEntry, participant *models.Participant, limit *int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var event models.Event
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", entry.EventID).First(&event).Error; err != nil {
+			return err
+		}
+		if limit != nil {
+			var count int64
+			if err := tx.Model(&models.Participant{}).Where("event_id = ? AND cancelled_at IS NULL", entry.EventID).Count(&count).Error; err != nil {
+				return err
+			}
+			if int(count) >= *limit {
+				return ErrTicketQuotaExceeded
+			}
+		}
+
+		if err := tx.Omit(clause.Associations).Create(participant).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.WaitlistEntry{}).
+			Where("id = ? AND status = ?", entry.ID, "waiting").
+			Updates(map[string]interface{}{
+				"status":         "promoted",
+				"participant_id": participant.ID,
+				"promoted_at":    participant.CreatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		entry.Status, entry.ParticipantID, entry.PromotedAt = "promoted", &participant.ID, &participant.CreatedAt
+		return nil
+	})
+}
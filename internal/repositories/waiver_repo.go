@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"fmt"
+
+	"event-management-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WaiverRepository interface {
+	CreateWaiver(waiver *models.Waiver) error
+	GetWaiverByID(id string) (*models.Waiver, error)
+	ListWaiversByEvent(eventID string) ([]models.Waiver, error)
+	SetWaiverActive(id string, active bool) error
+	ListUnsignedWaivers(eventID, participantID string) ([]models.Waiver, error)
+	CreateWaiverSignature(signature *models.WaiverSignature) error
+	GetWaiverSignatureByID(id string) (*models.WaiverSignature, error)
+	ListWaiverSignaturesByParticipant(participantID string) ([]models.WaiverSignature, error)
+	ListWaiverSignaturesByEvent(eventID string) ([]models.WaiverSignature, error)
+}
+
+type waiverRepo struct {
+	db *gorm.DB
+}
+
+func NewWaiverRepository(db *gorm.DB) WaiverRepository {
+	return &waiverRepo{db: db}
+}
+
+func (r *waiverRepo) CreateWaiver(waiver *models.Waiver) error {
+	return r.db.Create(waiver).Error
+}
+
+func (r *waiverRepo) GetWaiverByID(id string) (*models.Waiver, error) {
+	var waiver models.Waiver
+	if err := r.db.Where("id = ?", id).First(&waiver).Error; err != nil {
+		return nil, err
+	}
+	return &waiver, nil
+}
+
+func (r *waiverRepo) ListWaiversByEvent(eventID string) ([]models.Waiver, error) {
+	var waivers []models.Waiver
+	if err := r.db.Where("event_id = ?", eventID).Order("created_at ASC").Find(&waivers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list waivers: %w", err)
+	}
+	return waivers, nil
+}
+
+func (r *waiverRepo) SetWaiverActive(id string, active bool) error {
+	return r.db.Model(&models.Waiver{}).Where("id = ?", id).Update("is_active", active).Error
+}
+
+// ListUnsignedWaivers returns the active waivers of an event the participant
+// has not signed yet
+func (r *waiverRepo) ListUnsignedWaivers(eventID, participantID string) ([]models.Waiver, error) {
+	var waivers []models.Waiver
+	signed := r.db.Model(&models.WaiverSignature{}).Select("waiver_id").Where("participant_id = ?", participantID)
+	if err := r.db.Where("event_id = ? AND is_active = ?", eventID, true).
+		Where("id NOT IN (?)", signed).
+		Order("created_at ASC").
+		Find(&waivers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list unsigned waivers: %w", err)
+	}
+	return waivers, nil
+}
+
+func (r *waiverRepo) CreateWaiverSignature(signature *models.WaiverSignature) error {
+	return r.db.Omit(clause.Associations).Create(signature).Error
+}
+
+func (r *waiverRepo) GetWaiverSignatureByID(id string) (*models.WaiverSignature, error) {
+	var signature models.WaiverSignature
+	if err := r.db.Where("id = ?", id).First(&signature).Error; err != nil {
+		return nil, err
+	}
+	return &signature, nil
+}
+
+func (r *waiverRepo) ListWaiverSignaturesByParticipant(participantID string) ([]models.WaiverSignature, error) {
+	var signatures []models.WaiverSignature
+	if err := r.db.Preload("Waiver").
+		Where("participant_id = ?", participantID).
+		Order("signed_at ASC").
+		Find(&signatures).Error; err != nil {
+		return nil, fmt.Errorf("failed to list waiver signatures: %w", err)
+	}
+	return signatures, nil
+}
+
+// ListWaiverSignaturesByEvent returns the signatures of an event with their
+// waivers and participants, in signing order
+func (r *waiverRepo) ListWaiverSignaturesByEvent(eventID string) ([]models.WaiverSignature, error) {
+	var signatures []models.WaiverSignature
+	if err := r.db.Preload("Waiver").Preload("Participant").
+		Where("event_id = ?", eventID).
+		Order("signed_at ASC").
+		Find(&signatures).Error; err != nil {
+		return nil, fmt.Errorf("failed to list waiver signatures: %w", err)
+	}
+	return signatures, nil
+}
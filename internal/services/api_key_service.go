@@ -0,0 +1,190 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// API key scopes
+const (
+	ScopeRegistration = "registration"
+	ScopeVerification = "verification"
+)
+
+// apiKeyPrefix starts every key so leaked keys are easy to recognize
+const apiKeyPrefix = "emk_"
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid or revoked API key")
+	ErrAPIKeyNotFound = errors.New("API key not found")
+)
+
+// APIKeyService manages the API keys external systems use on the
+// /integrations endpoints
+type APIKeyService struct {
+	repo  *repositories.Repository
+	cfg   *config.Config
+	audit *AuditService
+}
+
+func NewAPIKeyService(repo *repositories.Repository, cfg *config.Config) *APIKeyService {
+	return &APIKeyService{repo: repo, cfg: cfg, audit: NewAuditService(repo, cfg)}
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string
+	Scopes    []string
+	EventIDs  []string
+	ExpiresAt *time.Time
+}
+
+// CreatedAPIKey holds a new key. The key itself is only returned here.
+type CreatedAPIKey struct {
+	Key    string         `json:"key"`
+	APIKey *models.APIKey `json:"api_key"`
+}
+
+// Create issues a key limited to the given scopes and events
+func (s *APIKeyService) Create(createdBy string, req CreateAPIKeyRequest) (*CreatedAPIKey, error) {
+	creatorID, err := uuid.Parse(createdBy)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := make(map[string]bool)
+	for _, scope := range req.Scopes {
+		if scope != ScopeRegistration && scope != ScopeVerification {
+			return nil, fmt.Errorf("invalid scope %q: must be registration or verification", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+
+	eventIDs := make([]uuid.UUID, 0, len(req.EventIDs))
+	for _, id := range req.EventIDs {
+		event, err := s.repo.EventRepo.GetEventByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("event %s not found", id)
+		}
+		eventIDs = append(eventIDs, event.ID)
+	}
+	if len(eventIDs) == 0 {
+		return nil, errors.New("at least one event is required")
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	apiKey := &models.APIKey{
+		ID:        uuid.New(),
+		Name:      name,
+		Prefix:    key[:len(apiKeyPrefix)+6],
+		KeyHash:   hashAPIKey(key),
+		Scopes:    scopes,
+		EventIDs:  eventIDs,
+		CreatedBy: creatorID,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.repo.APIKeyRepo.CreateAPIKey(apiKey); err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+	s.audit.Record(createdBy, AuditAPIKeyCreated, AuditEntityAPIKey, apiKey.ID.String(), map[string]interface{}{
+		"name":   name,
+		"scopes": scopes,
+	})
+
+	return &CreatedAPIKey{Key: key, APIKey: apiKey}, nil
+}
+
+func (s *APIKeyService) List() ([]models.APIKey, error) {
+	return s.repo.APIKeyRepo.ListAPIKeys()
+}
+
+// Revoke disables a key immediately on behalf of actorID
+func (s *APIKeyService) Revoke(actorID, id string) error {
+	if err := s.repo.APIKeyRepo.RevokeAPIKey(id, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+	s.audit.Record(actorID, AuditAPIKeyRevoked, AuditEntityAPIKey, id, nil)
+	return nil
+}
+
+// Authenticate returns the active key matching a presented key
+func (s *APIKeyService) Authenticate(key string) (*models.APIKey, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.repo.APIKeyRepo.GetAPIKeyByHash(hashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && !now.Before(*apiKey.ExpiresAt)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// Usage tracking is best effort and must not fail the request
+	_ = s.repo.APIKeyRepo.TouchAPIKey(apiKey.ID.String(), now)
+	return apiKey, nil
+}
+
+// APIKeyHasScope reports whether a key may call endpoints of a scope
+func APIKeyHasScope(key *models.APIKey, scope string) bool {
+	for _, s := range key.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyEventIDs returns the events a key is limited to, as strings
+func APIKeyEventIDs(key *models.APIKey) []string {
+	ids := make([]string, 0, len(key.EventIDs))
+	for _, id := range key.EventIDs {
+		ids = append(ids, id.String())
+	}
+	return ids
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// Audited actions
+const (
+	AuditEventCreated            = "event.created"
+	AuditEventUpdated            = "event.updated"
+	AuditEventDeleted            = "event.deleted"
+	AuditEventStatusChanged      = "event.status_changed"
+	AuditEventExported           = "event.exported"
+	AuditParticipantsImported    = "event.participants_imported"
+	AuditPaymentStatusChanged    = "participant.payment_status_changed"
+	AuditRegistrationApproved    = "participant.registration_approved"
+	AuditRegistrationRejected    = "participant.registration_rejected"
+	AuditRegistrationConfirmed   = "participant.registration_confirmed"
+	AuditRegistrationExpired     = "participant.registration_expired"
+	AuditParticipantMerged       = "participant.merged"
+	AuditRegistrationCancelled   = "participant.registration_cancelled"
+	AuditParticipantTransferred  = "participant.transferred"
+	AuditParticipantDataExported = "participant.data_exported"
+	AuditParticipantAnonymized   = "participant.anonymized"
+	AuditParticipantTagged       = "participant.tagged"
+	AuditTicketResent            = "participant.ticket_resent"
+	AuditCredentialRegenerated   = "participant.credential_regenerated"
+	AuditWaitlistPromoted        = "participant.waitlist_promoted"
+	AuditUserUpdated             = "user.updated"
+	AuditUserDeactivated         = "user.deactivated"
+	AuditUserDeleted             = "user.deleted"
+	AuditRoleChangeRequested     = "user.role_change_requested"
+	AuditRoleChangeApproved      = "user.role_change_approved"
+	AuditRoleChangeRejected      = "user.role_change_rejected"
+	AuditUserTokensRevoked       = "user.tokens_revoked"
+	AuditPasswordChanged         = "user.password_changed"
+	AuditServiceAccountCreated   = "service_account.created"
+	AuditServiceTokenIssued      = "service_account.token_issued"
+	AuditServiceTokenRevoked     = "service_account.token_revoked"
+	AuditAPIKeyCreated           = "api_key.created"
+	AuditAPIKeyRevoked           = "api_key.revoked"
+)
+
+// Audited entity types
+const (
+	AuditEntityEvent       = "event"
+	AuditEntityParticipant = "participant"
+	AuditEntityUser        = "user"
+	AuditEntityAPIKey      = "api_key"
+)
+
+var ErrInvalidAuditFilter = errors.New("invalid audit log filter")
+
+type AuditService struct {
+	repo *repositories.Repository
+	cfg  *config.Config
+}
+
+func NewAuditService(repo *repositories.Repository, cfg *config.Config) *AuditService {
+	return &AuditService{repo: repo, cfg: cfg}
+}
+
+// Record stores an audit entry for a change that has already been made. An
+// empty actorID records a system change. Failures are logged rather than
+// returned so they never undo or mask the change itself.
+func (s *AuditService) Record(actorID, action, entityType, entityID string, details map[string]interface{}) {
+	entry := &models.AuditLog{
+		ID:         uuid.New(),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Details:    details,
+	}
+	if actor, err := uuid.Parse(actorID); err == nil {
+		entry.ActorID = &actor
+	}
+
+	if err := s.repo.AuditRepo.CreateAuditLog(entry); err != nil {
+		logger.Log.WithError(err).WithFields(map[string]interface{}{
+			"action":      action,
+			"entity_type": entityType,
+			"entity_id":   entityID,
+		}).Error("failed to record audit log")
+	}
+}
+
+// AuditLogQuery filters an audit log listing
+type AuditLogQuery struct {
+	UserID     string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	PageSize   int
+}
+
+// List returns a page of audit entries, newest first
+func (s *AuditService) List(query AuditLogQuery) ([]models.AuditLog, int64, int, error) {
+	if query.UserID != "" {
+		if _, err := uuid.Parse(query.UserID); err != nil {
+			return nil, 0, 0, ErrInvalidAuditFilter
+		}
+	}
+	if query.From != nil && query.To != nil && !query.To.After(*query.From) {
+		return nil, 0, 0, ErrInvalidAuditFilter
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 || query.PageSize > 100 {
+		query.PageSize = 20
+	}
+
+	filter := repositories.AuditLogFilter{
+		ActorID:    query.UserID,
+		EntityType: query.EntityType,
+		EntityID:   query.EntityID,
+		From:       query.From,
+		To:         query.To,
+	}
+	offset := (query.Page - 1) * query.PageSize
+	logs, total, err := s.repo.AuditRepo.ListAuditLogs(filter, offset, query.PageSize)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	for i := range logs {
+		if logs[i].Actor != nil {
+			logs[i].Actor.Password = ""
+		}
+	}
+
+	totalPages := (int(total) + query.PageSize - 1) / query.PageSize
+	return logs, total, totalPages, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAuditLog(t *testing.T) {
+	e := newTestEnv(t)
+	audit := NewAuditService(e.repo, e.cfg)
+	admin := e.fx.User("admin")
+	staff := e.fx.User("staff")
+	event := e.fx.Event()
+	participant := e.fx.Participant(event)
+
+	email := "renamed@example.com"
+	if _, err := e.authService().UpdateUser(admin.ID.String(), staff.ID.String(), UpdateUserRequest{Email: &email}); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewParticipantService(e.repo, e.cfg).UpdatePaymentStatus(admin.ID.String(), participant.ID.String(), "paid", nil); err != nil {
+		t.Fatal(err)
+	}
+	// Failed changes are not audited
+	if err := NewParticipantService(e.repo, e.cfg).UpdatePaymentStatus(admin.ID.String(), participant.ID.String(), "refunded", nil); err == nil {
+		t.Fatal("invalid payment status accepted")
+	}
+
+	future := time.Now().Add(time.Hour)
+	tests := []struct {
+		name       string
+		query      AuditLogQuery
+		wantAction []string
+		wantErr    error
+	}{
+		{name: "all", wantAction: []string{AuditPaymentStatusChanged, AuditUserUpdated}},
+		{name: "by user", query: AuditLogQuery{UserID: admin.ID.String()}, wantAction: []string{AuditPaymentStatusChanged, AuditUserUpdated}},
+		{name: "by other user", query: AuditLogQuery{UserID: staff.ID.String()}},
+		{name: "by entity", query: AuditLogQuery{EntityType: AuditEntityUser, EntityID: staff.ID.String()}, wantAction: []string{AuditUserUpdated}},
+		{name: "in the future", query: AuditLogQuery{From: &future}},
+		{name: "invalid user", query: AuditLogQuery{UserID: "admin"}, wantErr: ErrInvalidAuditFilter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs, total, _, err := audit.List(tt.query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if int(total) != len(tt.wantAction) || len(logs) != len(tt.wantAction) {
+				t.Fatalf("got %d logs (total %d), want %v", len(logs), total, tt.wantAction)
+			}
+			for i, log := range logs {
+				if log.Action != tt.wantAction[i] {
+					t.Fatalf("log %d action = %q, want %q", i, log.Action, tt.wantAction[i])
+				}
+				if log.Actor == nil || log.Actor.ID != admin.ID {
+					t.Fatalf("log %d actor = %+v, want %s", i, log.Actor, admin.ID)
+				}
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
 	"event-management-backend/internal/config"
+	"event-management-backend/internal/jwtkeys"
 	"event-management-backend/internal/models"
 	"event-management-backend/internal/repositories"
 	"event-management-backend/internal/utils"
@@ -14,21 +16,35 @@ import (
 	"github.com/google/uuid"
 )
 
+// allowedRoles are the roles admins can give users
+var allowedRoles = map[string]bool{"admin": true, "organizer": true, "staff": true}
+
 type AuthService struct {
-	repo *repositories.Repository
-	cfg  *config.Config
+	repo  *repositories.Repository
+	keys  *jwtkeys.Keyset
+	cfg   *config.Config
+	audit *AuditService
 }
 
-func NewAuthService(repo *repositories.Repository, cfg *config.Config) *AuthService {
-	return &AuthService{repo: repo, cfg: cfg}
+func NewAuthService(repo *repositories.Repository, keys *jwtkeys.Keyset, cfg *config.Config) *AuthService {
+	return &AuthService{repo: repo, keys: keys, cfg: cfg, audit: NewAuditService(repo, cfg)}
 }
 
 type LoginResponse struct {
-	Token string       `json:"token"`
-	User  *models.User `json:"user"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Only issued to remember-me logins
+	RefreshToken     string       `json:"refresh_token,omitempty"`
+	RefreshExpiresAt *time.Time   `json:"refresh_expires_at,omitempty"`
+	User             *models.User `json:"user"`
 }
 
-func (s *AuthService) Authenticate(email, password string) (*LoginResponse, error) {
+// Authenticate checks a user's credentials and returns a token. Users with
+// two-factor authentication enabled must also give a code from their
+// authenticator app; ErrOTPRequired tells clients to ask for one. Successful
+// logins are recorded with the client's IP and user agent. With rememberMe
+// the response also has a refresh token, see Refresh.
+func (s *AuthService) Authenticate(email, password, otp string, rememberMe bool, client LoginClient) (*LoginResponse, error) {
 	email = strings.TrimSpace(strings.ToLower(email))
 
 	if email == "" || password == "" {
@@ -44,15 +60,31 @@ func (s *AuthService) Authenticate(email, password string) (*LoginResponse, erro
 		return nil, errors.New("invalid credentials")
 	}
 
-	token, err := s.generateJWT(user)
-	if err != nil {
-		return nil, errors.New("failed to generate token")
+	if !user.IsActive {
+		return nil, ErrUserDeactivated
+	}
+	if user.Role == RoleScanner {
+		return nil, ErrServiceAccountLogin
 	}
 
-	return &LoginResponse{
-		Token: token,
-		User:  user,
-	}, nil
+	if user.TOTPEnabled {
+		if otp == "" {
+			return nil, ErrOTPRequired
+		}
+		if err := s.checkTOTP(user, otp); err != nil {
+			return nil, err
+		}
+	}
+	s.rehashPassword(user, password)
+
+	resp, err := s.issueTokens(user, rememberMe)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordLogin(user, client); err != nil {
+		return nil, fmt.Errorf("failed to record login: %w", err)
+	}
+	return resp, nil
 }
 
 func (s *AuthService) CreateUser(email, password, role string) (*models.User, error) {
@@ -60,7 +92,6 @@ func (s *AuthService) CreateUser(email, password, role string) (*models.User, er
 	role = strings.TrimSpace(strings.ToLower(role))
 
 	// Validate role
-	allowedRoles := map[string]bool{"admin": true, "organizer": true, "staff": true}
 	if !allowedRoles[role] {
 		return nil, errors.New("invalid role: must be admin, organizer, or staff")
 	}
@@ -71,7 +102,7 @@ func (s *AuthService) CreateUser(email, password, role string) (*models.User, er
 	}
 
 	// Hash password
-	hashedPassword, err := utils.HashPassword(password)
+	hashedPassword, err := hashPassword(s.cfg, password)
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +112,7 @@ func (s *AuthService) CreateUser(email, password, role string) (*models.User, er
 		Email:    email,
 		Password: hashedPassword,
 		Role:     role,
+		IsActive: true,
 	}
 
 	if err := s.repo.UserRepo.CreateUser(user); err != nil {
@@ -92,17 +124,36 @@ func (s *AuthService) CreateUser(email, password, role string) (*models.User, er
 	return user, nil
 }
 
-func (s *AuthService) generateJWT(user *models.User) (string, error) {
+// generateJWT returns an access token and its expiry
+func (s *AuthService) generateJWT(user *models.User) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.cfg.AccessTokenTTL)
 	claims := jwt.MapClaims{
 		"user_id": user.ID.String(),
 		"email":   user.Email,
 		"role":    user.Role,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
-		"iat":     time.Now().Unix(),
+		// Identifies the token so it can be revoked before it expires
+		"jti": uuid.New().String(),
+		"exp": expiresAt.Unix(),
+		"iat": now.Unix(),
+	}
+
+	// Event-scoped users carry the events they are assigned to, so scanner
+	// apps know them without another request
+	if isEventScoped(user.Role) {
+		ids, err := s.repo.EventStaffRepo.ListStaffEventIDs(user.ID.String())
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		eventIDs := make([]string, 0, len(ids))
+		for _, id := range ids {
+			eventIDs = append(eventIDs, id.String())
+		}
+		claims[ClaimEventIDs] = eventIDs
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.cfg.JWTSecret))
+	token, err := s.keys.Sign(claims)
+	return token, expiresAt, err
 }
 
 func (s *AuthService) GetUserProfile(userID string) (*models.User, error) {
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/png"
+	"math"
+	"sort"
+	"strings"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/pdf"
+	"event-management-backend/internal/repositories"
+)
+
+// MaxBadgesPerSheet is how many badges one badge sheet PDF can hold; larger
+// events print theirs in parts using the participant filters
+const MaxBadgesPerSheet = 2000
+
+// mmToPoints converts millimetres, in which label sheets are specified, to
+// PDF points
+const mmToPoints = 72 / 25.4
+
+// Smallest badge that still fits a readable name and a scannable QR code, in
+// points
+const minBadgeSize = 100.0
+
+var (
+	ErrInvalidBadgeLayout = errors.New("invalid badge layout")
+	ErrTooManyBadges      = fmt.Errorf("more than %d badges, narrow the participants with the filters", MaxBadgesPerSheet)
+)
+
+// BadgeLayout is the grid of labels on an A4 sheet. Margins and gaps are in
+// millimetres; labels are as large as the grid leaves room for.
+type BadgeLayout struct {
+	Columns  int
+	Rows     int
+	MarginMM float64
+	GapMM    float64
+}
+
+// DefaultBadgeLayout is a sheet of eight labels of about 92 x 66 mm
+var DefaultBadgeLayout = BadgeLayout{Columns: 2, Rows: 4, MarginMM: 12, GapMM: 3}
+
+func (l BadgeLayout) validate() error {
+	switch {
+	case l.Columns < 1 || l.Columns > 6:
+		return fmt.Errorf("%w: columns must be between 1 and 6", ErrInvalidBadgeLayout)
+	case l.Rows < 1 || l.Rows > 12:
+		return fmt.Errorf("%w: rows must be between 1 and 12", ErrInvalidBadgeLayout)
+	// Written so that NaN is refused too
+	case !(l.MarginMM >= 0 && l.MarginMM <= 40):
+		return fmt.Errorf("%w: margin must be between 0 and 40 mm", ErrInvalidBadgeLayout)
+	case !(l.GapMM >= 0 && l.GapMM <= 20):
+		return fmt.Errorf("%w: gap must be between 0 and 20 mm", ErrInvalidBadgeLayout)
+	}
+	if width, height := l.badgeSize(); width < minBadgeSize || height < minBadgeSize/2 {
+		return fmt.Errorf("%w: labels would be too small to print a badge on", ErrInvalidBadgeLayout)
+	}
+	return nil
+}
+
+// badgeSize returns the size of one label in points
+func (l BadgeLayout) badgeSize() (float64, float64) {
+	margin, gap := l.MarginMM*mmToPoints, l.GapMM*mmToPoints
+	width := (pdf.A4Width - 2*margin - float64(l.Columns-1)*gap) / float64(l.Columns)
+	height := (pdf.A4Height - 2*margin - float64(l.Rows-1)*gap) / float64(l.Rows)
+	return width, height
+}
+
+// BadgeSheetPDF renders the badges of the participants of an event holding a
+// ticket, narrowed by the optional listing filters, on A4 label sheets laid
+// out as a grid. Each badge shows the participant's name, division and QR
+// code, with a thin outline to cut along. Badges are sorted by name.
+func (s *ParticipantService) BadgeSheetPDF(eventID string, filters *repositories.ParticipantFilters, layout BadgeLayout) ([]byte, error) {
+	if err := layout.validate(); err != nil {
+		return nil, err
+	}
+	if err := validateParticipantFilters(filters); err != nil {
+		return nil, err
+	}
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+	if err := s.validateAnswerFilters(eventID, filters); err != nil {
+		return nil, err
+	}
+
+	var participants []models.Participant
+	var cursor *repositories.Cursor
+	for {
+		batch, err := s.repo.ParticipantRepo.ListParticipantsByEventAfter(eventID, cursor, exportBatchSize, filters)
+		if err != nil {
+			return nil, err
+		}
+		for _, participant := range batch {
+			// Only ticket holders get a badge
+			if participant.CancelledAt != nil || participant.QRPath == "" {
+				continue
+			}
+			if len(participants) == MaxBadgesPerSheet {
+				return nil, ErrTooManyBadges
+			}
+			participants = append(participants, participant)
+		}
+		if len(batch) < exportBatchSize {
+			break
+		}
+		last := batch[len(batch)-1]
+		cursor = &repositories.Cursor{Time: last.CreatedAt, ID: last.ID}
+	}
+	sort.SliceStable(participants, func(i, j int) bool {
+		return strings.ToLower(participants[i].Name) < strings.ToLower(participants[j].Name)
+	})
+
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	width, height := layout.badgeSize()
+	margin, gap := layout.MarginMM*mmToPoints, layout.GapMM*mmToPoints
+	perPage := layout.Columns * layout.Rows
+	var page *pdf.Page
+	for i := range participants {
+		if i%perPage == 0 {
+			page = doc.AddPage()
+		}
+		cell := i % perPage
+		x := margin + float64(cell%layout.Columns)*(width+gap)
+		y := margin + float64(cell/layout.Columns)*(height+gap)
+		if err := s.drawBadge(doc, page, event, &participants[i], x, y, width, height); err != nil {
+			return nil, err
+		}
+	}
+	// An empty selection still gives a valid document
+	if page == nil {
+		doc.AddPage()
+	}
+
+	var out bytes.Buffer
+	if _, err := doc.WriteTo(&out); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+type badgeLine struct {
+	text string
+	font pdf.Font
+	size float64
+}
+
+// drawBadge draws one badge in the width x height label at x, y. Wide labels
+// get the QR code on the right of the text, tall ones below it.
+func (s *ParticipantService) drawBadge(doc *pdf.Document, page *pdf.Page, event *models.Event, participant *models.Participant, x, y, width, height float64) error {
+	qrData, err := ticketQRImage(s.cfg, participant)
+	if err != nil {
+		return fmt.Errorf("failed to read QR code of participant %s: %w", participant.ID, err)
+	}
+	qr, err := png.Decode(bytes.NewReader(qrData))
+	if err != nil {
+		return fmt.Errorf("failed to decode QR code of participant %s: %w", participant.ID, err)
+	}
+
+	page.StrokeRect(x, y, width, height, 0.25)
+	padding := math.Min(width, height) * 0.08
+	innerWidth, innerHeight := width-2*padding, height-2*padding
+
+	var qrSize, textWidth, textHeight float64
+	wide := width >= height
+	if wide {
+		qrSize = math.Min(innerHeight, innerWidth*0.45)
+		textWidth, textHeight = innerWidth-qrSize-padding, innerHeight
+		page.Image(doc.AddImage(qr), x+width-padding-qrSize, y+(height-qrSize)/2, qrSize, qrSize)
+	} else {
+		qrSize = math.Min(innerWidth, innerHeight*0.55)
+		textWidth, textHeight = innerWidth, innerHeight-qrSize-padding
+		page.Image(doc.AddImage(qr), x+(width-qrSize)/2, y+height-padding-qrSize, qrSize, qrSize)
+	}
+
+	nameSize := math.Max(8, math.Min(20, textHeight/3.5))
+	detailSize := math.Max(6, nameSize*0.55)
+	name := pdf.Wrap(participant.Name, pdf.HelveticaBold, nameSize, textWidth)
+	if len(name) > 2 {
+		name = []string{name[0], pdf.Truncate(strings.Join(name[1:], " "), pdf.HelveticaBold, nameSize, textWidth)}
+	}
+	lines := []badgeLine{{pdf.Truncate(event.Title, pdf.Helvetica, detailSize, textWidth), pdf.Helvetica, detailSize}}
+	for _, line := range name {
+		lines = append(lines, badgeLine{line, pdf.HelveticaBold, nameSize})
+	}
+	if participant.Division != "" {
+		divisionSize := detailSize * 1.3
+		lines = append(lines, badgeLine{pdf.Truncate(participant.Division, pdf.Helvetica, divisionSize, textWidth), pdf.Helvetica, divisionSize})
+	}
+
+	// Text is centered vertically in its area, and horizontally on tall
+	// labels
+	blockHeight := 0.0
+	for _, line := range lines {
+		blockHeight += line.size * 1.25
+	}
+	textY := y + padding + math.Max(0, (textHeight-blockHeight)/2)
+	for _, line := range lines {
+		textY += line.size * 1.25
+		if wide {
+			page.Text(x+padding, textY, line.font, line.size, line.text)
+		} else {
+			page.TextCentered(x+width/2, textY, line.font, line.size, line.text)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// participantQRPrefix marks signed participant credentials. Original tickets
+// carry the bare participant ID; reissued ones carry the ID, the credential
+// version and a signature so older badges can be refused.
+const participantQRPrefix = "PTK:"
+
+// DefaultRegenerateReason is logged for QR regenerations given no reason
+const DefaultRegenerateReason = "QR code leaked"
+
+var (
+	// ErrReissueReasonRequired is returned when a reissue carries no reason
+	ErrReissueReasonRequired = errors.New("a reason is required to reissue a credential")
+	// ErrCredentialNotIssued is returned when the participant has no QR code
+	// yet, e.g. while the registration awaits approval
+	ErrCredentialNotIssued = errors.New("participant has no credential to reissue")
+)
+
+// ReissueResult is the outcome of a lost-badge reissue
+type ReissueResult struct {
+	Participant *models.Participant        `json:"participant"`
+	QRPath      string                     `json:"qr_path"`
+	Incident    *models.CredentialIncident `json:"incident"`
+}
+
+// participantCredential is the content of a scanned participant QR code
+type participantCredential struct {
+	ParticipantID string
+	Version       int
+	Signature     string
+	Signed        bool
+}
+
+// parseParticipantCredential reads a signed credential, a bare participant
+// ID or a QR image path named after the participant ID
+func parseParticipantCredential(qrData string) (participantCredential, error) {
+	if strings.HasPrefix(qrData, participantQRPrefix) {
+		parts := strings.Split(strings.TrimPrefix(qrData, participantQRPrefix), ":")
+		if len(parts) != 3 {
+			return participantCredential{}, errors.New("invalid signed credential")
+		}
+		if _, err := uuid.Parse(parts[0]); err != nil {
+			return participantCredential{}, fmt.Errorf("invalid UUID in credential: %w", err)
+		}
+		version, err := strconv.Atoi(parts[1])
+		if err != nil || version < 1 {
+			return participantCredential{}, errors.New("invalid credential version")
+		}
+		return participantCredential{ParticipantID: parts[0], Version: version, Signature: parts[2], Signed: true}, nil
+	}
+
+	participantID, err := utils.ExtractUUIDFromQRPath(qrData)
+	if err != nil {
+		if _, err := uuid.Parse(qrData); err != nil {
+			return participantCredential{}, err
+		}
+		participantID = qrData
+	}
+	return participantCredential{ParticipantID: participantID}, nil
+}
+
+// currentFor reports whether the credential is the participant's latest one.
+// Unsigned credentials are only valid until the first reissue.
+func (c participantCredential) currentFor(secret string, participant *models.Participant) bool {
+	if !c.Signed {
+		return participant.CredentialVersion == 0
+	}
+	expected := credentialSignature(secret, participant.ID.String(), c.Version)
+	return c.Version == participant.CredentialVersion && hmac.Equal([]byte(c.Signature), []byte(expected))
+}
+
+func credentialSignature(secret, participantID string, version int) string {
+	mac := hmac.New(sha256.New, []byte("participant-credential:"+secret))
+	fmt.Fprintf(mac, "%s:%d", participantID, version)
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// signedCredential returns the QR content of a reissued credential
+func signedCredential(secret, participantID string, version int) string {
+	return fmt.Sprintf("%s%s:%d:%s", participantQRPrefix, participantID, version, credentialSignature(secret, participantID, version))
+}
+
+// credentialContent returns the content of the participant's current QR code
+func credentialContent(secret string, participant *models.Participant) string {
+	if participant.CredentialVersion == 0 {
+		return participant.ID.String()
+	}
+	return signedCredential(secret, participant.ID.String(), participant.CredentialVersion)
+}
+
+// ReissueCredential replaces a lost badge: the participant gets a new signed
+// QR code, every earlier credential is refused at the gates and the incident
+// is logged. With requireIDCheck the next scan asks staff to check an ID.
+func (s *ParticipantService) ReissueCredential(participantID, reason string, requireIDCheck bool, reportedBy string) (*ReissueResult, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReissueReasonRequired
+	}
+
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return nil, errors.New("participant not found")
+	}
+	if participant.QRPath == "" {
+		return nil, ErrCredentialNotIssued
+	}
+
+	next := participant.CredentialVersion + 1
+	filename, err := utils.GenerateQRCodeImage(signedCredential(s.cfg.CredentialSigningKey, participant.ID.String(), next), s.cfg.QRDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate QR code: %w", err)
+	}
+	qrPath := fmt.Sprintf("/qrcodes/%s", filename)
+
+	incident := &models.CredentialIncident{
+		ID:                uuid.New(),
+		EventID:           participant.EventID,
+		ParticipantID:     participant.ID,
+		Reason:            reason,
+		ReportedBy:        uuid.MustParse(reportedBy),
+		CredentialVersion: next,
+		RequireIDCheck:    requireIDCheck,
+	}
+	if err := s.repo.ParticipantRepo.RotateCredential(participantID, participant.CredentialVersion, qrPath, requireIDCheck, incident); err != nil {
+		os.Remove(filepath.Join(s.cfg.QRDir, filename))
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to reissue credential: %w", err)
+	}
+
+	// The old image is no longer served; failing to delete it is harmless
+	// since its content is refused at the gates
+	os.Remove(filepath.Join(s.cfg.QRDir, filepath.Base(participant.QRPath)))
+
+	participant, err = s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return nil, err
+	}
+	return &ReissueResult{Participant: participant, QRPath: qrPath, Incident: incident}, nil
+}
+
+// RegenerateQR rotates the QR code of a participant whose code leaked, as a
+// reissue does: the old image is deleted, a new signed code is issued and the
+// old one is refused at the gates. The rotation is logged as a credential
+// incident and audited; reason defaults to DefaultRegenerateReason.
+func (s *ParticipantService) RegenerateQR(actorID, participantID, reason string) (*ReissueResult, error) {
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return nil, ErrUnknownParticipant
+	}
+	if participant.CancelledAt != nil {
+		return nil, ErrTicketCancelled
+	}
+	if strings.TrimSpace(reason) == "" {
+		reason = DefaultRegenerateReason
+	}
+
+	result, err := s.ReissueCredential(participantID, reason, false, actorID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(actorID, AuditCredentialRegenerated, AuditEntityParticipant, participantID, map[string]interface{}{
+		"event_id":           participant.EventID.String(),
+		"reason":             result.Incident.Reason,
+		"credential_version": result.Incident.CredentialVersion,
+	})
+	return result, nil
+}
+
+// ListCredentialIncidents returns the lost-badge reissues of a participant,
+// newest first
+func (s *ParticipantService) ListCredentialIncidents(participantID string) ([]models.CredentialIncident, error) {
+	if _, err := s.repo.ParticipantRepo.GetParticipantByID(participantID); err != nil {
+		return nil, errors.New("participant not found")
+	}
+	return s.repo.ParticipantRepo.ListCredentialIncidents(participantID)
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DayAttendance is how many participants were admitted on an event day
+type DayAttendance struct {
+	EventDayID uuid.UUID `json:"event_day_id"`
+	DayNumber  int       `json:"day_number"`
+	Label      string    `json:"label"`
+	Date       time.Time `json:"date"`
+	Capacity   *int      `json:"capacity"` // nil = unlimited
+	// Distinct participants scanned on the day
+	Attendees int64 `json:"attendees"`
+	Remaining *int  `json:"remaining"` // nil = unlimited
+	Full      bool  `json:"full"`
+}
+
+// GetDayAttendance returns the admissions of each day of an event against
+// its capacity, in day order
+func (s *EventService) GetDayAttendance(eventID string) ([]DayAttendance, error) {
+	if _, err := s.repo.EventRepo.GetEventByID(eventID); err != nil {
+		return nil, ErrUnknownEvent
+	}
+
+	days, err := s.repo.EventRepo.GetEventDaysByEventID(eventID)
+	if err != nil {
+		return nil, err
+	}
+	attendees, err := s.repo.ActionRepo.CountAttendeesByDay(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	attendance := make([]DayAttendance, 0, len(days))
+	for _, day := range days {
+		item := DayAttendance{
+			EventDayID: day.ID,
+			DayNumber:  day.DayNumber,
+			Label:      day.Label,
+			Date:       day.Date,
+			Capacity:   day.Capacity,
+			Attendees:  attendees[day.ID.String()],
+		}
+		if day.Capacity != nil {
+			remaining := *day.Capacity - int(item.Attendees)
+			if remaining < 0 {
+				remaining = 0
+			}
+			item.Remaining = &remaining
+			item.Full = remaining == 0
+		}
+		attendance = append(attendance, item)
+	}
+	return attendance, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Device platforms
+const (
+	PlatformAndroid = "android"
+	PlatformIOS     = "ios"
+	PlatformWeb     = "web"
+	PlatformOther   = "other"
+)
+
+// DeviceService keeps track of the physical devices the scanner app runs on
+type DeviceService struct {
+	repo *repositories.Repository
+	cfg  *config.Config
+}
+
+func NewDeviceService(repo *repositories.Repository, cfg *config.Config) *DeviceService {
+	return &DeviceService{repo: repo, cfg: cfg}
+}
+
+type RegisterDeviceRequest struct {
+	Name       string
+	Platform   string
+	Identifier string
+}
+
+// Register records the device the app runs on, on behalf of the signed in
+// user. Registering a known identifier again renames the device and keeps
+// its ID, so the app can simply register on every start.
+func (s *DeviceService) Register(userID string, req RegisterDeviceRequest) (*models.Device, error) {
+	registeredBy, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	identifier := strings.TrimSpace(req.Identifier)
+	if identifier == "" {
+		return nil, errors.New("identifier is required")
+	}
+	platform := strings.ToLower(strings.TrimSpace(req.Platform))
+	switch platform {
+	case PlatformAndroid, PlatformIOS, PlatformWeb, PlatformOther:
+	default:
+		return nil, fmt.Errorf("invalid platform %q: must be android, ios, web or other", req.Platform)
+	}
+
+	device := &models.Device{
+		Name:         name,
+		Platform:     platform,
+		Identifier:   identifier,
+		RegisteredBy: registeredBy,
+	}
+	if err := s.repo.DeviceRepo.RegisterDevice(device); err != nil {
+		return nil, fmt.Errorf("failed to register device: %w", err)
+	}
+	return device, nil
+}
+
+func (s *DeviceService) List() ([]models.Device, error) {
+	return s.repo.DeviceRepo.ListDevices()
+}
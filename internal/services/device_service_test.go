@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestDeviceScans(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewDeviceService(e.repo, e.cfg)
+	staff := e.fx.User("staff")
+
+	device, err := svc.Register(staff.ID.String(), RegisterDeviceRequest{Name: "Gate A", Platform: "Android", Identifier: "install-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if device.Platform != PlatformAndroid || device.LastSeenAt != nil {
+		t.Fatalf("platform %q, last seen %v; want android, never", device.Platform, device.LastSeenAt)
+	}
+
+	again, err := svc.Register(staff.ID.String(), RegisterDeviceRequest{Name: "Gate B", Platform: "android", Identifier: "install-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != device.ID || again.Name != "Gate B" {
+		t.Fatalf("re-registering gave %s named %q, want %s renamed to Gate B", again.ID, again.Name, device.ID)
+	}
+
+	if _, err := svc.Register(staff.ID.String(), RegisterDeviceRequest{Name: "Gate C", Platform: "blackberry", Identifier: "install-2"}); err == nil {
+		t.Fatal("registered a device with an unknown platform")
+	}
+
+	event := e.fx.Event()
+	action := e.fx.Action(e.fx.Day(event))
+	admin := e.fx.User("admin")
+
+	_, err = e.verificationService().VerifyParticipantAction(VerifyRequest{
+		QRCodeData: e.fx.Participant(event).ID.String(),
+		ActionCode: action.Code,
+		VerifierID: admin.ID.String(),
+		DeviceID:   uuid.NewString(),
+	})
+	if code := GetVerificationErrorCode(err); code != ErrDeviceNotFound {
+		t.Fatalf("unknown device: code = %q, want %q", code, ErrDeviceNotFound)
+	}
+
+	result, err := e.verificationService().VerifyParticipantAction(VerifyRequest{
+		QRCodeData: e.fx.Participant(event).ID.String(),
+		ActionCode: action.Code,
+		VerifierID: admin.ID.String(),
+		DeviceID:   device.ID.String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log := result.ActionLog; log.DeviceID == nil || *log.DeviceID != device.ID {
+		t.Fatalf("device of the scan = %v, want %s", log.DeviceID, device.ID)
+	}
+
+	devices, err := svc.List()
+	if err != nil || len(devices) != 1 || devices[0].LastSeenAt == nil {
+		t.Fatalf("List = %+v, %v; want the device, seen", devices, err)
+	}
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoEligibleParticipants is returned when no participant matches the
+// criteria of a draw
+var ErrNoEligibleParticipants = errors.New("no eligible participants for this draw")
+
+var ErrDrawNotFound = errors.New("draw not found")
+
+// DrawService runs lucky draws. Each draw records its seed and a hash of the
+// eligible pool so the result can be verified with VerifyDraw.
+type DrawService struct {
+	repo *repositories.Repository
+	cfg  *config.Config
+}
+
+func NewDrawService(repo *repositories.Repository, cfg *config.Config) *DrawService {
+	return &DrawService{repo: repo, cfg: cfg}
+}
+
+type DrawRequest struct {
+	Prize                  string
+	Winners                int
+	PaidOnly               bool
+	CheckedInToday         bool
+	ActionCode             string // with CheckedInToday, only counts check-ins at this action
+	ExcludePreviousWinners bool
+}
+
+// Draw picks winners for the next prize round of an event
+func (s *DrawService) Draw(eventID, drawnBy string, req DrawRequest) (*models.Draw, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	drawnByID, err := uuid.Parse(drawnBy)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	if req.Winners <= 0 {
+		return nil, errors.New("winners must be greater than 0")
+	}
+
+	criteria := models.DrawCriteria{
+		PaidOnly:               req.PaidOnly,
+		ExcludePreviousWinners: req.ExcludePreviousWinners,
+	}
+	if req.CheckedInToday {
+		now := time.Now()
+		startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		criteria.CheckedInSince = &startOfDay
+	}
+	if code := strings.TrimSpace(req.ActionCode); code != "" {
+		if !req.CheckedInToday {
+			return nil, errors.New("action_code requires checked_in_today")
+		}
+		action, err := s.repo.EventRepo.GetEventActionByCode(code)
+		if err != nil || action.EventID != event.ID {
+			return nil, errors.New("action not found for this event")
+		}
+		criteria.ActionID = action.ID.String()
+		criteria.ActionCode = action.Code
+	}
+
+	pool, err := s.repo.DrawRepo.GetEligibleParticipantIDs(eventID, criteria)
+	if err != nil {
+		return nil, err
+	}
+	if len(pool) == 0 {
+		return nil, ErrNoEligibleParticipants
+	}
+
+	seed, err := drawSeed()
+	if err != nil {
+		return nil, err
+	}
+
+	round, err := s.repo.DrawRepo.NextDrawRound(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	winners := ShuffleDraw(pool, seed)
+	if len(winners) > req.Winners {
+		winners = winners[:req.Winners]
+	}
+
+	draw := &models.Draw{
+		EventID:       event.ID,
+		Round:         round,
+		Prize:         strings.TrimSpace(req.Prize),
+		WinnerCount:   len(winners),
+		Seed:          seed,
+		Criteria:      criteria,
+		EligibleCount: len(pool),
+		PoolHash:      PoolHash(pool),
+		DrawnBy:       drawnByID,
+		Winners:       make([]models.DrawWinner, 0, len(winners)),
+	}
+	for i, participantID := range winners {
+		draw.Winners = append(draw.Winners, models.DrawWinner{
+			EventID:       event.ID,
+			ParticipantID: participantID,
+			Position:      i + 1,
+		})
+	}
+
+	if err := s.repo.DrawRepo.CreateDraw(draw); err != nil {
+		return nil, err
+	}
+
+	// Return the winners with their participant details
+	for i := range draw.Winners {
+		if participant, err := s.repo.ParticipantRepo.GetParticipantByID(draw.Winners[i].ParticipantID.String()); err == nil {
+			draw.Winners[i].Participant = *participant
+		}
+	}
+
+	return draw, nil
+}
+
+func (s *DrawService) ListDraws(eventID string) ([]models.Draw, error) {
+	return s.repo.DrawRepo.ListDrawsByEvent(eventID)
+}
+
+// ShuffleDraw returns pool in the winning order for seed. The pool must be
+// ordered by participant ID, as returned by the repository, for a draw to be
+// reproducible.
+func ShuffleDraw(pool []uuid.UUID, seed int64) []uuid.UUID {
+	order := make([]uuid.UUID, len(pool))
+	copy(order, pool)
+
+	rng := mathrand.New(mathrand.NewSource(seed))
+	rng.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	return order
+}
+
+// PoolHash fingerprints the eligible pool so auditors can confirm a re-run
+// starts from the same participants
+func PoolHash(pool []uuid.UUID) string {
+	h := sha256.New()
+	for _, id := range pool {
+		h.Write(id[:])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// DrawVerification compares a recorded draw with a replay of its seed over
+// the pool it was drawn from
+type DrawVerification struct {
+	DrawID         uuid.UUID   `json:"draw_id"`
+	Round          int         `json:"round"`
+	Seed           int64       `json:"seed"`
+	PoolHash       string      `json:"pool_hash"`
+	ReplayPoolHash string      `json:"replay_pool_hash"`
+	PoolMatches    bool        `json:"pool_matches"`
+	WinnersMatch   bool        `json:"winners_match"`
+	ReplayWinners  []uuid.UUID `json:"replay_winners"`
+}
+
+// VerifyDraw re-runs a recorded draw with its stored seed without changing
+// anything. The pool is rebuilt from the draw criteria, only excluding the
+// winners of earlier rounds, so it matches unless participants or check-ins
+// changed since.
+func (s *DrawService) VerifyDraw(eventID, drawID string) (*DrawVerification, error) {
+	draws, err := s.repo.DrawRepo.ListDrawsByEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var draw *models.Draw
+	for i := range draws {
+		if draws[i].ID.String() == drawID {
+			draw = &draws[i]
+			break
+		}
+	}
+	if draw == nil {
+		return nil, ErrDrawNotFound
+	}
+
+	criteria := draw.Criteria
+	criteria.BeforeRound = draw.Round
+	pool, err := s.repo.DrawRepo.GetEligibleParticipantIDs(eventID, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	replay := ShuffleDraw(pool, draw.Seed)
+	if len(replay) > draw.WinnerCount {
+		replay = replay[:draw.WinnerCount]
+	}
+
+	verification := &DrawVerification{
+		DrawID:         draw.ID,
+		Round:          draw.Round,
+		Seed:           draw.Seed,
+		PoolHash:       draw.PoolHash,
+		ReplayPoolHash: PoolHash(pool),
+		ReplayWinners:  replay,
+	}
+	verification.PoolMatches = verification.ReplayPoolHash == draw.PoolHash
+	verification.WinnersMatch = len(replay) == len(draw.Winners)
+	for i := 0; verification.WinnersMatch && i < len(replay); i++ {
+		verification.WinnersMatch = draw.Winners[i].ParticipantID == replay[i]
+	}
+	return verification, nil
+}
+
+// drawSeed is always generated here; callers cannot pick the seed, and so
+// the winners, of a draw
+func drawSeed() (int64, error) {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return 0, fmt.Errorf("failed to generate draw seed: %w", err)
+	}
+	return int64(binary.BigEndian.Uint64(buf[:]) >> 1), nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestVerifyDraw(t *testing.T) {
+	tests := []struct {
+		name string
+		// after runs between the draw and its verification
+		after       func(e *testEnv, svc *DrawService, eventID, organizerID string)
+		wantPool    bool
+		wantWinners bool
+	}{
+		{
+			name:        "unchanged pool",
+			wantPool:    true,
+			wantWinners: true,
+		},
+		{
+			name: "later round excluding previous winners",
+			after: func(e *testEnv, svc *DrawService, eventID, organizerID string) {
+				if _, err := svc.Draw(eventID, organizerID, DrawRequest{Prize: "Mug", Winners: 2, ExcludePreviousWinners: true}); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantPool:    true,
+			wantWinners: true,
+		},
+		{
+			name: "participant registered since",
+			after: func(e *testEnv, svc *DrawService, eventID, organizerID string) {
+				event, _ := e.repo.EventRepo.GetEventByID(eventID)
+				e.fx.Participant(event)
+			},
+			wantPool: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			svc := NewDrawService(e.repo, e.cfg)
+			event := e.fx.Event()
+			for i := 0; i < 10; i++ {
+				e.fx.Participant(event)
+			}
+			organizer := e.fx.User("organizer").ID.String()
+
+			draw, err := svc.Draw(event.ID.String(), organizer, DrawRequest{Prize: "Bike", Winners: 3, ExcludePreviousWinners: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.after != nil {
+				tt.after(e, svc, event.ID.String(), organizer)
+			}
+
+			got, err := svc.VerifyDraw(event.ID.String(), draw.ID.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Seed != draw.Seed || got.PoolMatches != tt.wantPool {
+				t.Fatalf("seed %d, pool matches %v; want %d, %v", got.Seed, got.PoolMatches, draw.Seed, tt.wantPool)
+			}
+			if tt.wantPool && got.WinnersMatch != tt.wantWinners {
+				t.Fatalf("winners match = %v, want %v", got.WinnersMatch, tt.wantWinners)
+			}
+		})
+	}
+
+	t.Run("unknown draw", func(t *testing.T) {
+		e := newTestEnv(t)
+		_, err := NewDrawService(e.repo, e.cfg).VerifyDraw(e.fx.Event().ID.String(), uuid.NewString())
+		if !errors.Is(err, ErrDrawNotFound) {
+			t.Fatalf("error = %v, want %v", err, ErrDrawNotFound)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownEventDay    = errors.New("event day not found")
+	ErrUnknownEventAction = errors.New("event action not found")
+	// ErrActionOrder is returned when an action order does not list every
+	// action of the day exactly once
+	ErrActionOrder = errors.New("order must list every action of the day exactly once")
+)
+
+// EventActionRequest holds the editable fields of an event action
+type EventActionRequest struct {
+	Name string
+	Code string
+	Zone string // empty opens the action to every participant
+	// Scan window, open-ended on either side when nil
+	StartsAt *time.Time
+	EndsAt   *time.Time
+}
+
+func (req EventActionRequest) validate() error {
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return errors.New("action must end after it starts")
+	}
+	return nil
+}
+
+// checkActionWindow refuses scans outside the time window of an action
+func checkActionWindow(action *models.EventAction, now time.Time) error {
+	if action.StartsAt != nil && now.Before(*action.StartsAt) {
+		return NewVerificationError(
+			fmt.Sprintf("%s opens at %s", action.Name, action.StartsAt.Format(time.RFC3339)),
+			ErrActionNotOpen,
+			nil,
+		)
+	}
+	if action.EndsAt != nil && !now.Before(*action.EndsAt) {
+		return NewVerificationError(
+			fmt.Sprintf("%s closed at %s", action.Name, action.EndsAt.Format(time.RFC3339)),
+			ErrActionNotOpen,
+			nil,
+		)
+	}
+	return nil
+}
+
+// getEventAction returns an active action of the given event day
+func (s *EventService) getEventAction(eventID, dayID, actionID string) (*models.EventAction, error) {
+	action, err := s.repo.EventRepo.GetEventActionByID(actionID)
+	if err != nil || !action.IsActive || action.EventID.String() != eventID || action.EventDayID.String() != dayID {
+		return nil, ErrUnknownEventAction
+	}
+	return action, nil
+}
+
+// UpdateEventAction replaces the name, code, zone and scan window of an
+// event action. Codes stay unique across events.
+func (s *EventService) UpdateEventAction(eventID, dayID, actionID string, req EventActionRequest) (*models.EventAction, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	action, err := s.getEventAction(eventID, dayID, actionID)
+	if err != nil {
+		return nil, err
+	}
+
+	action.Name = strings.TrimSpace(req.Name)
+	action.Code = req.Code
+	action.Zone = strings.ToLower(req.Zone)
+	action.StartsAt = req.StartsAt
+	action.EndsAt = req.EndsAt
+	if err := s.repo.EventRepo.UpdateEventAction(action); err != nil {
+		return nil, err
+	}
+	return action, nil
+}
+
+// DeleteEventAction deactivates an event action. Its verification history
+// is kept and its code can no longer be scanned.
+func (s *EventService) DeleteEventAction(eventID, dayID, actionID string) error {
+	if _, err := s.getEventAction(eventID, dayID, actionID); err != nil {
+		return err
+	}
+	return s.repo.EventRepo.DeleteEventAction(actionID)
+}
+
+// ReorderEventActions sets the display order of a day's actions. actionIDs
+// lists every active action of the day, first to last.
+func (s *EventService) ReorderEventActions(eventID, dayID string, actionIDs []string) ([]models.EventAction, error) {
+	day, err := s.repo.EventRepo.GetEventDayByID(dayID)
+	if err != nil || day.EventID.String() != eventID {
+		return nil, ErrUnknownEventDay
+	}
+	actions, err := s.repo.EventRepo.GetEventActionsByDayID(dayID)
+	if err != nil {
+		return nil, err
+	}
+	if len(actionIDs) != len(actions) {
+		return nil, ErrActionOrder
+	}
+
+	known := make(map[uuid.UUID]bool, len(actions))
+	for _, action := range actions {
+		known[action.ID] = true
+	}
+	order := make([]uuid.UUID, 0, len(actionIDs))
+	for _, id := range actionIDs {
+		actionID, err := uuid.Parse(id)
+		if err != nil || !known[actionID] {
+			return nil, ErrActionOrder
+		}
+		delete(known, actionID)
+		order = append(order, actionID)
+	}
+
+	if err := s.repo.EventRepo.ReorderEventActions(dayID, order); err != nil {
+		return nil, err
+	}
+	return s.repo.EventRepo.GetEventActionsByDayID(dayID)
+}
@@ -0,0 +1,363 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+const (
+	eventBundleFormat  = "event-bundle"
+	eventBundleVersion = 1
+)
+
+// EventBundle is the complete export of an event for backup or migration:
+// its definition with the participants and their check-ins. Participants and
+// action logs reference the definition through the same "ref" values.
+type EventBundle struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	// Names and contact details of participants were left out
+	PIIExcluded  bool                 `json:"pii_excluded"`
+	Definition   EventExport          `json:"definition"`
+	Participants []BundledParticipant `json:"participants"`
+	ActionLogs   []BundledActionLog   `json:"action_logs"`
+}
+
+type BundledParticipant struct {
+	Ref string `json:"ref"`
+	// Left out of bundles without PII
+	Name    string `json:"name,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Phone   string `json:"phone,omitempty"`
+	Address string `json:"address,omitempty"`
+
+	Division        string    `json:"division,omitempty"`
+	PaymentStatus   string    `json:"payment_status"`
+	MealPreference  string    `json:"meal_preference,omitempty"`
+	ApprovalStatus  string    `json:"approval_status"`
+	RejectionReason string    `json:"rejection_reason,omitempty"`
+	RegisteredAt    time.Time `json:"registered_at"`
+
+	CancelledAt        *time.Time `json:"cancelled_at,omitempty"`
+	CancellationReason string     `json:"cancellation_reason,omitempty"`
+}
+
+// BundledActionLog is a check-in of a participant. Who scanned it is not
+// exported: users differ between environments.
+type BundledActionLog struct {
+	ParticipantRef string    `json:"participant_ref"`
+	ActionRef      string    `json:"action_ref"`
+	VerifiedAt     time.Time `json:"verified_at"`
+	Source         string    `json:"source"`
+}
+
+// EventBundleExport writes the bundle of one event; see ExportEventBundle
+type EventBundleExport struct {
+	repo       *repositories.Repository
+	eventID    string
+	excludePII bool
+	Definition *EventExport
+}
+
+// ExportEventBundle prepares the bundle of an event. Participants and action
+// logs are read in batches while the bundle is written, so events of any size
+// can be exported.
+func (s *EventService) ExportEventBundle(actorID, eventID string, excludePII bool) (*EventBundleExport, error) {
+	definition, err := s.ExportEvent(eventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+
+	s.audit.Record(actorID, AuditEventExported, AuditEntityEvent, eventID, map[string]interface{}{
+		"pii_excluded": excludePII,
+	})
+	return &EventBundleExport{
+		repo:       s.repo,
+		eventID:    eventID,
+		excludePII: excludePII,
+		Definition: definition,
+	}, nil
+}
+
+// Write writes the bundle as JSON. A failure part way leaves the output
+// truncated, and so invalid.
+func (b *EventBundleExport) Write(out io.Writer) error {
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+
+	header := struct {
+		Format      string       `json:"format"`
+		Version     int          `json:"version"`
+		ExportedAt  time.Time    `json:"exported_at"`
+		PIIExcluded bool         `json:"pii_excluded"`
+		Definition  *EventExport `json:"definition"`
+	}{eventBundleFormat, eventBundleVersion, b.Definition.ExportedAt, b.excludePII, b.Definition}
+	head, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	// The header object is left open for the streamed arrays
+	w.Write(head[:len(head)-1])
+
+	w.WriteString(`,"participants":[`)
+	var cursor *repositories.Cursor
+	first := true
+	for {
+		participants, err := b.repo.ParticipantRepo.ListParticipantsByEventAfter(b.eventID, cursor, exportBatchSize, nil)
+		if err != nil {
+			return err
+		}
+		for i := range participants {
+			if !first {
+				w.WriteByte(',')
+			}
+			first = false
+			if err := enc.Encode(b.participant(&participants[i])); err != nil {
+				return err
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if len(participants) < exportBatchSize {
+			break
+		}
+		last := participants[len(participants)-1]
+		cursor = &repositories.Cursor{Time: last.CreatedAt, ID: last.ID}
+	}
+
+	w.WriteString(`],"action_logs":[`)
+	cursor, first = nil, true
+	for {
+		logs, err := b.repo.ActionRepo.GetActionLogsByEventAfter(b.eventID, cursor, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, log := range logs {
+			if !first {
+				w.WriteByte(',')
+			}
+			first = false
+			if err := enc.Encode(BundledActionLog{
+				ParticipantRef: log.ParticipantID.String(),
+				ActionRef:      log.ActionID.String(),
+				VerifiedAt:     log.VerifiedAt,
+				Source:         log.Source,
+			}); err != nil {
+				return err
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if len(logs) < exportBatchSize {
+			break
+		}
+		last := logs[len(logs)-1]
+		cursor = &repositories.Cursor{Time: last.VerifiedAt, ID: last.ID}
+	}
+
+	w.WriteString("]}\n")
+	return w.Flush()
+}
+
+func (b *EventBundleExport) participant(participant *models.Participant) BundledParticipant {
+	bundled := BundledParticipant{
+		Ref:                participant.ID.String(),
+		Division:           participant.Division,
+		PaymentStatus:      participant.PaymentStatus,
+		MealPreference:     participant.MealPreference,
+		ApprovalStatus:     participant.ApprovalStatus,
+		RejectionReason:    participant.RejectionReason,
+		RegisteredAt:       participant.CreatedAt,
+		CancelledAt:        participant.CancelledAt,
+		CancellationReason: participant.CancellationReason,
+	}
+	if !b.excludePII {
+		bundled.Name = participant.Name
+		bundled.Email = participant.Email
+		bundled.Phone = participant.Phone
+		bundled.Address = participant.Address
+	}
+	return bundled
+}
+
+// BundleImportOptions adjust an event bundle while importing it
+type BundleImportOptions struct {
+	ImportOptions
+	// Participants and their action logs are imported too; only the event
+	// definition otherwise
+	Participants bool
+}
+
+// EventBundleImport is what importing a bundle created
+type EventBundleImport struct {
+	Event        *models.Event `json:"event"`
+	Participants int           `json:"participants"`
+	ActionLogs   int           `json:"action_logs"`
+}
+
+// ImportEventBundle recreates an event from a bundle produced by
+// ExportEventBundle. Every record gets a new ID and the event starts as a
+// draft. Imported action logs are recorded in the name of the importing user,
+// and approved participants get new QR codes. The bundle is validated as a
+// whole and an *EventImportError lists all problems found.
+func (s *EventService) ImportEventBundle(actorID string, bundle *EventBundle, opts BundleImportOptions) (*EventBundleImport, error) {
+	imp := newEventImport(&bundle.Definition, opts.ImportOptions)
+	if bundle.Format != eventBundleFormat {
+		imp.problem("format: expected %q", eventBundleFormat)
+	}
+	if bundle.Version != eventBundleVersion {
+		imp.problem("version: unsupported version %d", bundle.Version)
+	}
+
+	var participants []models.Participant
+	var logs []models.ActionLog
+	if opts.Participants {
+		participants, logs = imp.bundleRecords(actorID, bundle)
+	}
+	if len(imp.problems) > 0 {
+		return nil, &EventImportError{Problems: imp.problems}
+	}
+
+	filenames := make([]string, 0, len(participants))
+	withQR := make([]models.Participant, 0, len(participants))
+	for i := range participants {
+		if participants[i].ApprovalStatus != ApprovalApproved || participants[i].CancelledAt != nil {
+			continue
+		}
+		filename := utils.NewQRCodeFilename()
+		participants[i].QRPath = fmt.Sprintf("/qrcodes/%s", filename)
+		withQR = append(withQR, participants[i])
+		filenames = append(filenames, filename)
+	}
+
+	if err := s.repo.EventRepo.ImportEvent(imp.event, imp.sessions, participants, logs); err != nil {
+		return nil, err
+	}
+
+	// Participants whose QR code cannot be written keep none; it can be
+	// regenerated
+	if failed := writeQRCodes(s.cfg, withQR, filenames); len(failed) > 0 {
+		ids := make([]uuid.UUID, 0, len(failed))
+		for _, i := range failed {
+			ids = append(ids, withQR[i].ID)
+		}
+		if err := s.repo.ParticipantRepo.ClearQRPaths(ids); err != nil {
+			return nil, fmt.Errorf("failed to clear QR paths: %w", err)
+		}
+	}
+
+	return &EventBundleImport{
+		Event:        imp.event,
+		Participants: len(participants),
+		ActionLogs:   len(logs),
+	}, nil
+}
+
+// bundleRecords validates the participants and action logs of a bundle and
+// builds their records for the imported event
+func (imp *eventImport) bundleRecords(actorID string, bundle *EventBundle) ([]models.Participant, []models.ActionLog) {
+	if bundle.PIIExcluded && len(bundle.Participants) > 0 {
+		imp.problem("participants: the bundle was exported without personal data; import it without participants")
+		return nil, nil
+	}
+
+	participants := make([]models.Participant, 0, len(bundle.Participants))
+	participantIDs := make(map[string]uuid.UUID, len(bundle.Participants))
+	emails := make(map[string]bool, len(bundle.Participants))
+	for i, bundled := range bundle.Participants {
+		path := fmt.Sprintf("participants[%d]", i)
+		if bundled.Ref == "" || participantIDs[bundled.Ref] != uuid.Nil {
+			imp.problem("%s.ref: must be present and unique", path)
+		}
+		if strings.TrimSpace(bundled.Name) == "" {
+			imp.problem("%s.name: required", path)
+		}
+		email := strings.TrimSpace(bundled.Email)
+		if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+			imp.problem("%s.email: invalid email", path)
+		} else if emails[strings.ToLower(email)] {
+			imp.problem("%s.email: registered twice", path)
+		}
+		emails[strings.ToLower(email)] = true
+		switch bundled.PaymentStatus {
+		case "unpaid", "pending", "paid", "refund_pending":
+		default:
+			imp.problem("%s.payment_status: must be unpaid, pending, paid or refund_pending", path)
+		}
+		switch bundled.ApprovalStatus {
+		case ApprovalPending, ApprovalApproved, ApprovalRejected:
+		default:
+			imp.problem("%s.approval_status: must be pending, approved or rejected", path)
+		}
+		mealPreference, err := NormalizeMealPreference(bundled.MealPreference)
+		if err != nil {
+			imp.problem("%s.meal_preference: %v", path, err)
+		}
+
+		participant := models.Participant{
+			ID:                 uuid.New(),
+			EventID:            imp.event.ID,
+			Name:               strings.TrimSpace(bundled.Name),
+			Email:              email,
+			Phone:              bundled.Phone,
+			Division:           bundled.Division,
+			Address:            bundled.Address,
+			PaymentStatus:      bundled.PaymentStatus,
+			MealPreference:     mealPreference,
+			ApprovalStatus:     bundled.ApprovalStatus,
+			RejectionReason:    bundled.RejectionReason,
+			CancelledAt:        bundled.CancelledAt,
+			CancellationReason: bundled.CancellationReason,
+			Version:            1,
+			CreatedAt:          bundled.RegisteredAt,
+		}
+		participantIDs[bundled.Ref] = participant.ID
+		participants = append(participants, participant)
+	}
+
+	verifier, err := uuid.Parse(actorID)
+	if err != nil && len(bundle.ActionLogs) > 0 {
+		imp.problem("action_logs: can only be imported by a user")
+		return participants, nil
+	}
+	logs := make([]models.ActionLog, 0, len(bundle.ActionLogs))
+	for i, bundled := range bundle.ActionLogs {
+		path := fmt.Sprintf("action_logs[%d]", i)
+		participantID, ok := participantIDs[bundled.ParticipantRef]
+		if !ok {
+			imp.problem("%s.participant_ref: no participant with ref %q", path, bundled.ParticipantRef)
+		}
+		actionID, ok := imp.actionIDs[bundled.ActionRef]
+		if !ok {
+			imp.problem("%s.action_ref: no action with ref %q", path, bundled.ActionRef)
+		}
+		source := bundled.Source
+		if source == "" {
+			source = "scan"
+		}
+
+		logs = append(logs, models.ActionLog{
+			ID:            uuid.New(),
+			ParticipantID: participantID,
+			ActionID:      actionID,
+			VerifiedBy:    &verifier,
+			VerifiedAt:    bundled.VerifiedAt,
+			Source:        source,
+		})
+	}
+	return participants, logs
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/models"
+)
+
+// ErrInvalidCalendar is returned for files that are not iCalendar (.ics)
+// files
+var ErrInvalidCalendar = errors.New("invalid calendar file")
+
+// calendarEvent is a VEVENT of an iCalendar file
+type calendarEvent struct {
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	// DTSTART is a date without a time
+	AllDay bool
+}
+
+var icsText = strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+// parseCalendar reads the name and the events of an iCalendar file. Only the
+// properties needed to pre-fill an event are read; recurrence rules are
+// ignored.
+func parseCalendar(r io.Reader) (string, []calendarEvent, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	// Long lines are folded onto lines starting with a space or a tab
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+	if len(lines) == 0 || !strings.EqualFold(lines[0], "BEGIN:VCALENDAR") {
+		return "", nil, fmt.Errorf("%w: missing BEGIN:VCALENDAR", ErrInvalidCalendar)
+	}
+
+	var name string
+	var events []calendarEvent
+	var current *calendarEvent
+	var hasStart bool
+	for _, line := range lines {
+		colon := strings.IndexByte(line, ':')
+		if colon < 0 {
+			continue
+		}
+		params := strings.Split(line[:colon], ";")
+		property, value := strings.ToUpper(params[0]), line[colon+1:]
+
+		switch {
+		case property == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			current, hasStart = &calendarEvent{}, false
+		case property == "END" && strings.EqualFold(value, "VEVENT") && current != nil:
+			if !hasStart {
+				return "", nil, fmt.Errorf("%w: event %d has no DTSTART", ErrInvalidCalendar, len(events)+1)
+			}
+			if current.End.IsZero() {
+				current.End = current.Start
+				if current.AllDay {
+					current.End = current.Start.AddDate(0, 0, 1)
+				}
+			}
+			events = append(events, *current)
+			current = nil
+		case property == "X-WR-CALNAME" && current == nil:
+			name = icsText.Replace(value)
+		case current == nil:
+		case property == "SUMMARY":
+			current.Summary = strings.TrimSpace(icsText.Replace(value))
+		case property == "DESCRIPTION":
+			current.Description = icsText.Replace(value)
+		case property == "DTSTART", property == "DTEND":
+			t, allDay, err := parseCalendarTime(value, params[1:])
+			if err != nil {
+				return "", nil, fmt.Errorf("%w: event %d: %s: %v", ErrInvalidCalendar, len(events)+1, property, err)
+			}
+			if property == "DTSTART" {
+				current.Start, current.AllDay, hasStart = t, allDay, true
+			} else {
+				current.End = t
+			}
+		}
+	}
+	return name, events, nil
+}
+
+// parseCalendarTime parses a DATE or DATE-TIME value. Times without a zone
+// are taken as UTC.
+func parseCalendarTime(value string, params []string) (time.Time, bool, error) {
+	loc := time.UTC
+	for _, param := range params {
+		key, val, _ := strings.Cut(param, "=")
+		switch strings.ToUpper(key) {
+		case "VALUE":
+			if strings.EqualFold(val, "DATE") {
+				t, err := time.Parse("20060102", value)
+				return t, true, err
+			}
+		case "TZID":
+			if zone, err := time.LoadLocation(strings.Trim(val, `"`)); err == nil {
+				loc = zone
+			}
+		}
+	}
+
+	switch {
+	case len(value) == 8:
+		t, err := time.Parse("20060102", value)
+		return t, true, err
+	case strings.HasSuffix(value, "Z"):
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+var slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// calendarSlug derives an event slug from a title
+func calendarSlug(title string) string {
+	slug := strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if slug == "" {
+		return "event"
+	}
+	return slug
+}
+
+// ImportEventCalendar creates a draft event from an iCalendar (.ics) file.
+// The event spans its calendar events and gets a day for every date they
+// cover; days are named after the calendar events when there are several.
+// Actions are added afterwards.
+func (s *EventService) ImportEventCalendar(r io.Reader, opts ImportOptions) (*models.Event, error) {
+	name, events, err := parseCalendar(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: no events found", ErrInvalidCalendar)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+
+	export := &EventExport{
+		Format:  eventExportFormat,
+		Version: eventExportVersion,
+		Event: ExportedEvent{
+			Title:       events[0].Summary,
+			Description: events[0].Description,
+			StartsAt:    events[0].Start,
+			EndsAt:      events[0].End,
+			IsActive:    true,
+		},
+	}
+	if len(events) > 1 && name != "" {
+		export.Event.Title, export.Event.Description = name, ""
+	}
+
+	dates := map[time.Time]string{}
+	for _, event := range events {
+		if event.End.After(export.Event.EndsAt) {
+			export.Event.EndsAt = event.End
+		}
+		// An event ending at midnight does not cover the following date
+		last := event.End
+		if (event.AllDay || last.Equal(calendarDate(last))) && last.After(event.Start) {
+			last = last.Add(-time.Nanosecond)
+		}
+		for date := calendarDate(event.Start); !date.After(last); date = date.AddDate(0, 0, 1) {
+			key := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+			if _, ok := dates[key]; !ok {
+				dates[key] = ""
+				if len(events) > 1 && date.Equal(calendarDate(event.Start)) {
+					dates[key] = event.Summary
+				}
+			}
+		}
+	}
+
+	days := make([]time.Time, 0, len(dates))
+	for date := range dates {
+		days = append(days, date)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	for i, date := range days {
+		label := dates[date]
+		if label == "" {
+			label = fmt.Sprintf("Day %d", i+1)
+		}
+		export.Days = append(export.Days, ExportedDay{
+			Ref:       fmt.Sprintf("day-%d", i+1),
+			DayNumber: i + 1,
+			Label:     label,
+			Date:      date,
+		})
+	}
+
+	if opts.Slug == "" {
+		opts.Slug = calendarSlug(export.Event.Title)
+	}
+	imp := newEventImport(export, opts)
+	if len(imp.problems) > 0 {
+		return nil, &EventImportError{Problems: imp.problems}
+	}
+	if err := s.repo.EventRepo.ImportEvent(imp.event, nil, nil, nil); err != nil {
+		return nil, err
+	}
+	return imp.event, nil
+}
+
+// calendarDate returns midnight of the date of t, in its location
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
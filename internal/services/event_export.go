@@ -0,0 +1,428 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	eventExportFormat  = "event-definition"
+	eventExportVersion = 1
+)
+
+// Slugs and action codes of existing events may contain dashes, e.g. the
+// seeded DEMO-TECH-SUMMIT-D1-LUNCH
+var identifier = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// EventExport is the portable definition of an event. Records reference each
+// other through "ref" values, the IDs in the source environment, which are
+// replaced by new IDs on import. Participants, check-ins, uploaded files and
+// access tokens are not part of the definition.
+type EventExport struct {
+	Format     string            `json:"format"`
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Event      ExportedEvent     `json:"event"`
+	Days       []ExportedDay     `json:"days"`
+	Sessions   []ExportedSession `json:"sessions"`
+}
+
+type ExportedEvent struct {
+	Title       string    `json:"title"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	TicketPrice float64   `json:"ticket_price"`
+	TicketQuota *int      `json:"ticket_quota"`
+	IsActive    bool      `json:"is_active"`
+	// Registration window; see models.Event
+	RegistrationOpensAt  *time.Time `json:"registration_opens_at,omitempty"`
+	RegistrationClosesAt *time.Time `json:"registration_closes_at,omitempty"`
+	// Registrations wait for organizer approval
+	RequiresApproval bool `json:"requires_approval"`
+	RequiresCaptcha  bool `json:"requires_captcha,omitempty"`
+	// Hours to confirm the email address of a registration; 0 when omitted
+	ConfirmationHours int `json:"confirmation_hours,omitempty"`
+	// in_person|online|hybrid; in_person when omitted
+	Format        string   `json:"format,omitempty"`
+	WidgetOrigins []string `json:"widget_origins,omitempty"`
+	KioskMessage  string   `json:"kiosk_message,omitempty"`
+	// public|unlisted|private; public when omitted. Access codes are not
+	// exported.
+	Visibility string `json:"visibility,omitempty"`
+	// off|manual|fifo|priority; off when omitted. Waiting people are not
+	// exported.
+	Waitlist string `json:"waitlist,omitempty"`
+	// Custom questions of the registration form
+	FormFields []models.FormField `json:"form_fields,omitempty"`
+}
+
+type ExportedDay struct {
+	Ref       string    `json:"ref"`
+	DayNumber int       `json:"day_number"`
+	Label     string    `json:"label"`
+	Date      time.Time `json:"date"`
+	// In display order
+	Actions []ExportedAction `json:"actions"`
+}
+
+type ExportedAction struct {
+	Ref      string `json:"ref"`
+	Name     string `json:"name"`
+	Code     string `json:"code"`
+	Zone     string `json:"zone,omitempty"`
+	IsActive bool   `json:"is_active"`
+	// Scan window, when the action has one
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
+
+type ExportedSession struct {
+	DayRef      string    `json:"day_ref"`
+	ActionRef   string    `json:"action_ref"`
+	Title       string    `json:"title"`
+	Room        string    `json:"room"`
+	Capacity    *int      `json:"capacity"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	CreditHours float64   `json:"credit_hours"`
+}
+
+// ImportOptions adjust an event definition while importing it, so an event
+// can be copied within the same environment where slugs and action codes
+// must be unique
+type ImportOptions struct {
+	Slug       string // replaces the exported slug when set
+	CodeSuffix string // appended to every action code
+}
+
+// EventImportError lists every problem found in an event definition
+type EventImportError struct {
+	Problems []string
+}
+
+func (e *EventImportError) Error() string {
+	return fmt.Sprintf("invalid event definition: %s", strings.Join(e.Problems, "; "))
+}
+
+// ExportEvent returns the portable definition of an event
+func (s *EventService) ExportEvent(eventID string) (*EventExport, error) {
+	event, err := s.repo.EventRepo.GetEventWithDays(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("event not found")
+	}
+
+	sessions, err := s.repo.SessionRepo.ListSessionsByEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &EventExport{
+		Format:     eventExportFormat,
+		Version:    eventExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Event: ExportedEvent{
+			Title:        event.Title,
+			Slug:         event.Slug,
+			Description:  event.Description,
+			StartsAt:     event.StartsAt,
+			EndsAt:       event.EndsAt,
+			TicketPrice:  event.TicketPrice,
+			TicketQuota:  event.TicketQuota,
+			IsActive:     event.IsActive,
+			KioskMessage: event.KioskMessage,
+
+			RegistrationOpensAt:  event.RegistrationOpensAt,
+			RegistrationClosesAt: event.RegistrationClosesAt,
+			RequiresApproval:     event.RequiresApproval,
+			RequiresCaptcha:      event.RequiresCaptcha,
+			ConfirmationHours:    event.ConfirmationHours,
+			Format:               event.Format,
+			Visibility:           event.Visibility,
+			Waitlist:             event.Waitlist,
+			FormFields:           event.FormFields,
+		},
+		Days:     make([]ExportedDay, 0, len(event.EventDays)),
+		Sessions: make([]ExportedSession, 0, len(sessions)),
+	}
+	if event.WidgetOrigins != "" {
+		export.Event.WidgetOrigins = strings.Split(event.WidgetOrigins, ",")
+	}
+
+	for _, day := range event.EventDays {
+		exportedDay := ExportedDay{
+			Ref:       day.ID.String(),
+			DayNumber: day.DayNumber,
+			Label:     day.Label,
+			Date:      day.Date,
+			Actions:   make([]ExportedAction, 0, len(day.EventActions)),
+		}
+		for _, action := range day.EventActions {
+			exportedDay.Actions = append(exportedDay.Actions, ExportedAction{
+				Ref:      action.ID.String(),
+				Name:     action.Name,
+				Code:     action.Code,
+				Zone:     action.Zone,
+				IsActive: action.IsActive,
+				StartsAt: action.StartsAt,
+				EndsAt:   action.EndsAt,
+			})
+		}
+		export.Days = append(export.Days, exportedDay)
+	}
+
+	for _, session := range sessions {
+		export.Sessions = append(export.Sessions, ExportedSession{
+			DayRef:      session.EventDayID.String(),
+			ActionRef:   session.ActionID.String(),
+			Title:       session.Title,
+			Room:        session.Room,
+			Capacity:    session.Capacity,
+			StartsAt:    session.StartsAt,
+			EndsAt:      session.EndsAt,
+			CreditHours: session.CreditHours,
+		})
+	}
+
+	return export, nil
+}
+
+// ImportEvent creates a new event from a definition produced by ExportEvent.
+// Every record gets a new ID, and the event starts as a draft. The definition
+// is validated as a whole and an *EventImportError lists all problems found.
+func (s *EventService) ImportEvent(export *EventExport, opts ImportOptions) (*models.Event, error) {
+	imp := newEventImport(export, opts)
+	if len(imp.problems) > 0 {
+		return nil, &EventImportError{Problems: imp.problems}
+	}
+
+	if err := s.repo.EventRepo.ImportEvent(imp.event, imp.sessions, nil, nil); err != nil {
+		return nil, err
+	}
+
+	return imp.event, nil
+}
+
+// eventImport is an event definition turned into new records, with the
+// problems found on the way
+type eventImport struct {
+	event    *models.Event
+	sessions []models.Session
+	// New IDs of the exported actions by ref
+	actionIDs map[string]uuid.UUID
+	problems  []string
+}
+
+func (imp *eventImport) problem(format string, args ...interface{}) {
+	imp.problems = append(imp.problems, fmt.Sprintf(format, args...))
+}
+
+// newEventImport validates a definition and builds the records it describes
+func newEventImport(export *EventExport, opts ImportOptions) *eventImport {
+	imp := &eventImport{}
+	problem := imp.problem
+
+	if export.Format != eventExportFormat {
+		problem("format: expected %q", eventExportFormat)
+	}
+	if export.Version != eventExportVersion {
+		problem("version: unsupported version %d", export.Version)
+	}
+
+	slug := export.Event.Slug
+	if opts.Slug != "" {
+		slug = opts.Slug
+	}
+	if strings.TrimSpace(export.Event.Title) == "" {
+		problem("event.title: required")
+	}
+	if !identifier.MatchString(slug) {
+		problem("event.slug: must only contain letters, digits, dashes and underscores")
+	}
+	if export.Event.EndsAt.Before(export.Event.StartsAt) {
+		problem("event.ends_at: must be after starts_at")
+	}
+	if err := validateRegistrationWindow(export.Event.RegistrationOpensAt, export.Event.RegistrationClosesAt, export.Event.EndsAt); err != nil {
+		problem("event.registration_closes_at: %v", err)
+	}
+	if export.Event.TicketPrice < 0 {
+		problem("event.ticket_price: must not be negative")
+	}
+	if export.Event.TicketQuota != nil && *export.Event.TicketQuota <= 0 {
+		problem("event.ticket_quota: must be greater than 0")
+	}
+	format := export.Event.Format
+	if format == "" {
+		format = EventInPerson
+	}
+	if format != EventInPerson && format != EventOnline && format != EventHybrid {
+		problem("event.format: must be in_person, online or hybrid")
+	}
+	visibility := export.Event.Visibility
+	if visibility == "" {
+		visibility = EventPublic
+	}
+	if err := validateVisibility(visibility); err != nil {
+		problem("event.visibility: %v", err)
+	}
+	waitlist := export.Event.Waitlist
+	if waitlist == "" {
+		waitlist = WaitlistOff
+	}
+	if err := validateWaitlist(waitlist); err != nil {
+		problem("event.waitlist: %v", err)
+	}
+	if err := validateConfirmationHours(export.Event.ConfirmationHours); err != nil {
+		problem("event.confirmation_hours: %v", err)
+	}
+	if err := validateFormFields(export.Event.FormFields); err != nil {
+		problem("event.form_fields: %v", err)
+	}
+
+	event := &models.Event{
+		ID:                uuid.New(),
+		Title:             strings.TrimSpace(export.Event.Title),
+		Slug:              slug,
+		Description:       export.Event.Description,
+		StartsAt:          export.Event.StartsAt,
+		EndsAt:            export.Event.EndsAt,
+		TicketPrice:       export.Event.TicketPrice,
+		TicketQuota:       export.Event.TicketQuota,
+		IsActive:          export.Event.IsActive,
+		Status:            EventDraft,
+		RequiresApproval:  export.Event.RequiresApproval,
+		RequiresCaptcha:   export.Event.RequiresCaptcha,
+		ConfirmationHours: export.Event.ConfirmationHours,
+		Format:            format,
+		Visibility:        visibility,
+		Waitlist:          waitlist,
+		FormFields:        export.Event.FormFields,
+		WidgetOrigins:     normalizeOrigins(export.Event.WidgetOrigins),
+		KioskMessage:      export.Event.KioskMessage,
+		EventDays:         make([]models.EventDay, 0, len(export.Days)),
+
+		RegistrationOpensAt:  export.Event.RegistrationOpensAt,
+		RegistrationClosesAt: export.Event.RegistrationClosesAt,
+	}
+
+	// Source refs are mapped to the new IDs for the sessions below
+	dayIDs := make(map[string]uuid.UUID, len(export.Days))
+	actionIDs := make(map[string]uuid.UUID)
+	actionDays := make(map[string]string)
+	dayNumbers := make(map[int]bool, len(export.Days))
+	codes := make(map[string]bool)
+
+	for i, exportedDay := range export.Days {
+		path := fmt.Sprintf("days[%d]", i)
+		if exportedDay.Ref == "" || dayIDs[exportedDay.Ref] != uuid.Nil {
+			problem("%s.ref: must be present and unique", path)
+		}
+		if exportedDay.DayNumber <= 0 || dayNumbers[exportedDay.DayNumber] {
+			problem("%s.day_number: must be positive and unique", path)
+		}
+		if strings.TrimSpace(exportedDay.Label) == "" {
+			problem("%s.label: required", path)
+		}
+		dayNumbers[exportedDay.DayNumber] = true
+
+		day := models.EventDay{
+			ID:           uuid.New(),
+			EventID:      event.ID,
+			DayNumber:    exportedDay.DayNumber,
+			Label:        exportedDay.Label,
+			Date:         exportedDay.Date,
+			EventActions: make([]models.EventAction, 0, len(exportedDay.Actions)),
+		}
+		dayIDs[exportedDay.Ref] = day.ID
+
+		for j, exportedAction := range exportedDay.Actions {
+			actionPath := fmt.Sprintf("%s.actions[%d]", path, j)
+			code := exportedAction.Code + opts.CodeSuffix
+			if exportedAction.Ref == "" || actionIDs[exportedAction.Ref] != uuid.Nil {
+				problem("%s.ref: must be present and unique", actionPath)
+			}
+			if strings.TrimSpace(exportedAction.Name) == "" {
+				problem("%s.name: required", actionPath)
+			}
+			if start, end := exportedAction.StartsAt, exportedAction.EndsAt; start != nil && end != nil && !end.After(*start) {
+				problem("%s.ends_at: must be after starts_at", actionPath)
+			}
+			if !identifier.MatchString(code) || codes[code] {
+				problem("%s.code: must be unique and only contain letters, digits, dashes and underscores", actionPath)
+			}
+			if exportedAction.Zone != "" && !identifier.MatchString(exportedAction.Zone) {
+				problem("%s.zone: must only contain letters, digits, dashes and underscores", actionPath)
+			}
+			codes[code] = true
+
+			action := models.EventAction{
+				ID:         uuid.New(),
+				EventID:    event.ID,
+				EventDayID: day.ID,
+				Name:       exportedAction.Name,
+				Code:       code,
+				Zone:       strings.ToLower(exportedAction.Zone),
+				IsActive:   exportedAction.IsActive,
+				StartsAt:   exportedAction.StartsAt,
+				EndsAt:     exportedAction.EndsAt,
+				Position:   j, // actions are exported in display order
+			}
+			actionIDs[exportedAction.Ref] = action.ID
+			actionDays[exportedAction.Ref] = exportedDay.Ref
+			day.EventActions = append(day.EventActions, action)
+		}
+
+		event.EventDays = append(event.EventDays, day)
+	}
+
+	sessions := make([]models.Session, 0, len(export.Sessions))
+	sessionActions := make(map[string]bool, len(export.Sessions))
+	for i, exportedSession := range export.Sessions {
+		path := fmt.Sprintf("sessions[%d]", i)
+		dayID, ok := dayIDs[exportedSession.DayRef]
+		if !ok {
+			problem("%s.day_ref: no day with ref %q", path, exportedSession.DayRef)
+		}
+		actionID, ok := actionIDs[exportedSession.ActionRef]
+		if !ok {
+			problem("%s.action_ref: no action with ref %q", path, exportedSession.ActionRef)
+		} else if actionDays[exportedSession.ActionRef] != exportedSession.DayRef {
+			problem("%s.action_ref: action belongs to another day", path)
+		} else if sessionActions[exportedSession.ActionRef] {
+			problem("%s.action_ref: action is used by another session", path)
+		}
+		sessionActions[exportedSession.ActionRef] = true
+		if strings.TrimSpace(exportedSession.Title) == "" {
+			problem("%s.title: required", path)
+		}
+		if !exportedSession.EndsAt.After(exportedSession.StartsAt) {
+			problem("%s.ends_at: must be after starts_at", path)
+		}
+		if exportedSession.Capacity != nil && *exportedSession.Capacity <= 0 {
+			problem("%s.capacity: must be greater than 0", path)
+		}
+
+		sessions = append(sessions, models.Session{
+			ID:          uuid.New(),
+			EventID:     event.ID,
+			EventDayID:  dayID,
+			ActionID:    actionID,
+			Title:       exportedSession.Title,
+			Room:        exportedSession.Room,
+			Capacity:    exportedSession.Capacity,
+			StartsAt:    exportedSession.StartsAt,
+			EndsAt:      exportedSession.EndsAt,
+			CreditHours: exportedSession.CreditHours,
+		})
+	}
+
+	imp.event, imp.sessions, imp.actionIDs = event, sessions, actionIDs
+	return imp
+}
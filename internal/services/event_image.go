@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownEventImage = errors.New("event image not found")
+	// ErrImageOrder is returned when a gallery order does not list every
+	// image of the event exactly once
+	ErrImageOrder = errors.New("order must list every image of the event exactly once")
+)
+
+// AddEventImage appends an uploaded image to the gallery of an event. A
+// banner image replaces the event's previous banner.
+func (s *EventService) AddEventImage(eventID, path, caption string, banner bool) (*models.EventImage, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+
+	image := &models.EventImage{
+		ID:       uuid.New(),
+		EventID:  event.ID,
+		Path:     path,
+		Caption:  strings.TrimSpace(caption),
+		IsBanner: banner,
+	}
+	if err := s.repo.EventImageRepo.CreateEventImage(image); err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// ListEventImages returns the gallery of an event in display order
+func (s *EventService) ListEventImages(eventID string) ([]models.EventImage, error) {
+	if _, err := s.repo.EventRepo.GetEventByID(eventID); err != nil {
+		return nil, ErrUnknownEvent
+	}
+	return s.repo.EventImageRepo.ListEventImages(eventID)
+}
+
+// ReorderEventImages sets the gallery order of an event. imageIDs lists
+// every image of the event, first to last.
+func (s *EventService) ReorderEventImages(eventID string, imageIDs []string) ([]models.EventImage, error) {
+	images, err := s.ListEventImages(eventID)
+	if err != nil {
+		return nil, err
+	}
+	if len(imageIDs) != len(images) {
+		return nil, ErrImageOrder
+	}
+
+	known := make(map[uuid.UUID]bool, len(images))
+	for _, image := range images {
+		known[image.ID] = true
+	}
+	order := make([]uuid.UUID, 0, len(imageIDs))
+	for _, id := range imageIDs {
+		imageID, err := uuid.Parse(id)
+		if err != nil || !known[imageID] {
+			return nil, ErrImageOrder
+		}
+		delete(known, imageID)
+		order = append(order, imageID)
+	}
+
+	if err := s.repo.EventImageRepo.ReorderEventImages(eventID, order); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImageOrder
+		}
+		return nil, err
+	}
+	return s.repo.EventImageRepo.ListEventImages(eventID)
+}
+
+// SetBannerImage makes an image of the gallery the event's banner
+func (s *EventService) SetBannerImage(eventID, imageID string) (*models.EventImage, error) {
+	if _, err := s.getEventImage(eventID, imageID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.EventImageRepo.SetBannerImage(eventID, imageID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUnknownEventImage
+		}
+		return nil, err
+	}
+	return s.repo.EventImageRepo.GetEventImage(imageID)
+}
+
+// DeleteEventImage removes an image from the gallery along with its file.
+// Deleting the banner leaves the event without one.
+func (s *EventService) DeleteEventImage(eventID, imageID string) error {
+	image, err := s.getEventImage(eventID, imageID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.EventImageRepo.DeleteEventImage(imageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUnknownEventImage
+	}
+	if err != nil {
+		return err
+	}
+
+	// The image is no longer listed; a file left behind is only disk space
+	os.Remove(filepath.Join(s.cfg.ImageDir, filepath.Base(image.Path)))
+	return nil
+}
+
+// getEventImage returns an image of the given event's gallery
+func (s *EventService) getEventImage(eventID, imageID string) (*models.EventImage, error) {
+	image, err := s.repo.EventImageRepo.GetEventImage(imageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && image.EventID.String() != eventID) {
+		return nil, ErrUnknownEventImage
+	}
+	return image, err
+}
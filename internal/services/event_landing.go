@@ -0,0 +1,50 @@
+package services
+
+import "event-management-backend/internal/models"
+
+// EventLanding is everything the public event page shows, in one response
+type EventLanding struct {
+	// With its venue, banner, registration state and days; days list their
+	// active actions only
+	Event        *models.Event            `json:"event"`
+	Availability WidgetAvailability       `json:"availability"`
+	TicketTypes  []TicketTypeAvailability `json:"ticket_types,omitempty"`
+}
+
+// GetEventLanding returns the public page of an event. Drafts and deleted
+// events are not found; private events need their access code.
+func (s *EventService) GetEventLanding(slug, accessCode string) (*EventLanding, error) {
+	event, err := s.repo.EventRepo.GetEventWithDaysBySlug(slug)
+	if err != nil || !event.IsActive || event.Status == EventDraft {
+		return nil, ErrUnknownEvent
+	}
+	if err := CheckAccessCode(event, accessCode); err != nil {
+		return nil, err
+	}
+	withRegistrationState(event)
+
+	for i := range event.EventDays {
+		active := event.EventDays[i].EventActions[:0]
+		for _, action := range event.EventDays[i].EventActions {
+			if action.IsActive {
+				active = append(active, action)
+			}
+		}
+		event.EventDays[i].EventActions = active
+	}
+
+	availability, err := eventAvailability(s.repo, event)
+	if err != nil {
+		return nil, err
+	}
+	ticketTypes, err := listTicketTypeAvailability(s.repo, event.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventLanding{
+		Event:        event,
+		Availability: *availability,
+		TicketTypes:  ticketTypes,
+	}, nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"reflect"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// eventRevisionFields returns the editable fields of an event as they are
+// stored in a revision. The access code is only recorded as set or not.
+func eventRevisionFields(event *models.Event) map[string]interface{} {
+	fields := map[string]interface{}{
+		"title":                  event.Title,
+		"slug":                   event.Slug,
+		"description":            event.Description,
+		"starts_at":              revisionTime(&event.StartsAt),
+		"ends_at":                revisionTime(&event.EndsAt),
+		"ticket_price":           event.TicketPrice,
+		"ticket_quota":           nil,
+		"requires_approval":      event.RequiresApproval,
+		"requires_captcha":       event.RequiresCaptcha,
+		"confirmation_hours":     event.ConfirmationHours,
+		"format":                 event.Format,
+		"widget_origins":         event.WidgetOrigins,
+		"venue_id":               nil,
+		"registration_opens_at":  revisionTime(event.RegistrationOpensAt),
+		"registration_closes_at": revisionTime(event.RegistrationClosesAt),
+		"visibility":             event.Visibility,
+		"access_code_set":        event.AccessCodeHash != "",
+		"waitlist":               event.Waitlist,
+		"form_fields":            nil,
+	}
+	if len(event.FormFields) > 0 {
+		fields["form_fields"] = append([]models.FormField{}, event.FormFields...)
+	}
+	if event.TicketQuota != nil {
+		fields["ticket_quota"] = *event.TicketQuota
+	}
+	if event.VenueID != nil {
+		fields["venue_id"] = event.VenueID.String()
+	}
+	return fields
+}
+
+// revisionTime formats t the way it is stored in a revision, so that equal
+// instants compare equal whatever their location
+func revisionTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// diffEventFields returns the fields whose value differs between two
+// snapshots taken by eventRevisionFields
+func diffEventFields(before, after map[string]interface{}) map[string]models.FieldChange {
+	changes := map[string]models.FieldChange{}
+	for field, to := range after {
+		if from := before[field]; !reflect.DeepEqual(from, to) {
+			changes[field] = models.FieldChange{From: from, To: to}
+		}
+	}
+	return changes
+}
+
+// recordRevision stores the changes an update made to an event. Like the
+// audit log, a failure is logged and does not fail the update.
+func (s *EventService) recordRevision(actorID string, event *models.Event, changes map[string]models.FieldChange) {
+	if len(changes) == 0 {
+		return
+	}
+	revision := &models.EventRevision{
+		ID:      uuid.New(),
+		EventID: event.ID,
+		Version: event.Version,
+		Changes: changes,
+	}
+	if editor, err := uuid.Parse(actorID); err == nil {
+		revision.EditorID = &editor
+	}
+
+	if err := s.repo.EventRevisionRepo.CreateEventRevision(revision); err != nil {
+		logger.Log.WithError(err).WithField("event_id", event.ID.String()).Error("failed to record event revision")
+	}
+}
+
+// ListEventRevisions returns the change history of an event, newest first
+func (s *EventService) ListEventRevisions(eventID string, page, pageSize int) ([]models.EventRevision, int64, int, error) {
+	if _, err := s.repo.EventRepo.GetEventByID(eventID); err != nil {
+		return nil, 0, 0, ErrUnknownEvent
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	revisions, total, err := s.repo.EventRevisionRepo.ListEventRevisions(eventID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	totalPages := (int(total) + pageSize - 1) / pageSize
+	return revisions, total, totalPages, nil
+}
@@ -2,22 +2,29 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"event-management-backend/internal/config"
 	"event-management-backend/internal/models"
 	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/utils"
 
 	"github.com/google/uuid"
 )
 
 type EventService struct {
-	repo *repositories.Repository
-	cfg  *config.Config
+	repo  *repositories.Repository
+	cfg   *config.Config
+	audit *AuditService
 }
 
 func NewEventService(repo *repositories.Repository, cfg *config.Config) *EventService {
-	return &EventService{repo: repo, cfg: cfg}
+	return &EventService{repo: repo, cfg: cfg, audit: NewAuditService(repo, cfg)}
 }
 
 type CreateEventRequest struct {
@@ -29,13 +36,53 @@ type CreateEventRequest struct {
 	LogoPath    string
 	TicketPrice float64
 	TicketQuota *int
+
+	RequiresApproval bool
+	RequiresCaptcha  bool
+	// Hours to confirm the email address of a registration; 0 for none
+	ConfirmationHours int
+	Format            string // defaults to in_person
+	WidgetOrigins     []string
+	VenueID           string // optional
+
+	// Registration window, see models.Event
+	RegistrationOpensAt  *time.Time
+	RegistrationClosesAt *time.Time
+
+	Visibility string // defaults to public
+	// Only checked on private events; empty for none
+	AccessCode string
+	// off (default), manual, fifo or priority, see models.Event
+	Waitlist string
+	// Custom questions of the registration form. UpdateEvent leaves the
+	// form unchanged when nil and removes it when empty.
+	FormFields []models.FormField
 }
 
-func (s *EventService) CreateEvent(req CreateEventRequest) (*models.Event, error) {
+func (s *EventService) CreateEvent(actorID string, req CreateEventRequest) (*models.Event, error) {
 	// Validate dates
 	if req.EndsAt.Before(req.StartsAt) {
 		return nil, errors.New("end date must be after start date")
 	}
+	if err := validateRegistrationWindow(req.RegistrationOpensAt, req.RegistrationClosesAt, req.EndsAt); err != nil {
+		return nil, err
+	}
+	if err := validateVisibility(req.Visibility); err != nil {
+		return nil, err
+	}
+	if err := validateWaitlist(req.Waitlist); err != nil {
+		return nil, err
+	}
+	if err := validateConfirmationHours(req.ConfirmationHours); err != nil {
+		return nil, err
+	}
+	if err := validateFormFields(req.FormFields); err != nil {
+		return nil, err
+	}
+	venueID, err := s.venueID(req.VenueID)
+	if err != nil {
+		return nil, err
+	}
 
 	event := &models.Event{
 		ID:          uuid.New(),
@@ -48,28 +95,203 @@ func (s *EventService) CreateEvent(req CreateEventRequest) (*models.Event, error
 		TicketPrice: req.TicketPrice,
 		TicketQuota: req.TicketQuota,
 		IsActive:    true,
+		Status:      EventDraft,
+
+		RequiresApproval:  req.RequiresApproval,
+		RequiresCaptcha:   req.RequiresCaptcha,
+		Format:            req.Format,
+		ConfirmationHours: req.ConfirmationHours,
+		WidgetOrigins:     normalizeOrigins(req.WidgetOrigins),
+		VenueID:           venueID,
+
+		RegistrationOpensAt:  req.RegistrationOpensAt,
+		RegistrationClosesAt: req.RegistrationClosesAt,
+
+		Visibility:     req.Visibility,
+		AccessCodeHash: hashAccessCode(req.AccessCode),
+		Waitlist:       req.Waitlist,
+		FormFields:     req.FormFields,
+	}
+	if len(event.FormFields) == 0 {
+		event.FormFields = nil
+	}
+	if event.Format == "" {
+		event.Format = EventInPerson
+	}
+	if event.Visibility == "" {
+		event.Visibility = EventPublic
+	}
+	if event.Waitlist == "" {
+		event.Waitlist = WaitlistOff
 	}
 
 	if err := s.repo.EventRepo.CreateEvent(event); err != nil {
 		return nil, err
 	}
+	s.audit.Record(actorID, AuditEventCreated, AuditEntityEvent, event.ID.String(), map[string]interface{}{
+		"title": event.Title,
+		"slug":  event.Slug,
+	})
+
+	return event, nil
+}
+
+// ErrEventSlugTaken is returned when another event already uses a slug
+var ErrEventSlugTaken = errors.New("event slug is already in use")
+
+// UpdateEventRequest replaces the editable fields of an event
+type UpdateEventRequest struct {
+	CreateEventRequest
+	// Kept when nil and removed when empty; CreateEventRequest.AccessCode is
+	// ignored
+	AccessCode *string
+	// Version of the event the client last read; enables conflict detection
+	Version *int
+}
+
+// UpdateEvent replaces the details of an event and records the fields it
+// changed as a revision. The logo is kept. A stale version yields
+// repositories.ErrVersionConflict.
+func (s *EventService) UpdateEvent(actorID, eventID string, req UpdateEventRequest) (*models.Event, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+	if event.Status == EventArchived {
+		return nil, ErrEventArchived
+	}
+	if req.Version != nil && *req.Version != event.Version {
+		return nil, repositories.ErrVersionConflict
+	}
+
+	if req.EndsAt.Before(req.StartsAt) {
+		return nil, errors.New("end date must be after start date")
+	}
+	if err := validateRegistrationWindow(req.RegistrationOpensAt, req.RegistrationClosesAt, req.EndsAt); err != nil {
+		return nil, err
+	}
+	if err := validateVisibility(req.Visibility); err != nil {
+		return nil, err
+	}
+	if err := validateWaitlist(req.Waitlist); err != nil {
+		return nil, err
+	}
+	if err := validateConfirmationHours(req.ConfirmationHours); err != nil {
+		return nil, err
+	}
+	if err := validateFormFields(req.FormFields); err != nil {
+		return nil, err
+	}
+	venueID, err := s.venueID(req.VenueID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Slug != event.Slug {
+		if other, err := s.repo.EventRepo.GetEventBySlug(req.Slug); err == nil && other.ID != event.ID {
+			return nil, ErrEventSlugTaken
+		}
+	}
+	if req.TicketQuota != nil {
+		registered, err := s.repo.ParticipantRepo.GetParticipantCountByEventID(eventID)
+		if err != nil {
+			return nil, err
+		}
+		if int64(*req.TicketQuota) < registered {
+			return nil, fmt.Errorf("ticket quota cannot be below the %d registered participants", registered)
+		}
+	}
+
+	before := eventRevisionFields(event)
+	event.Title = req.Title
+	event.Slug = req.Slug
+	event.Description = req.Description
+	event.StartsAt = req.StartsAt
+	event.EndsAt = req.EndsAt
+	event.TicketPrice = req.TicketPrice
+	event.TicketQuota = req.TicketQuota
+	event.RequiresApproval = req.RequiresApproval
+	event.RequiresCaptcha = req.RequiresCaptcha
+	event.ConfirmationHours = req.ConfirmationHours
+	event.WidgetOrigins = normalizeOrigins(req.WidgetOrigins)
+	event.VenueID, event.Venue = venueID, nil
+	event.RegistrationOpensAt = req.RegistrationOpensAt
+	event.RegistrationClosesAt = req.RegistrationClosesAt
+	if req.Format != "" {
+		event.Format = req.Format
+	}
+	if req.Visibility != "" {
+		event.Visibility = req.Visibility
+	}
+	if req.Waitlist != "" {
+		event.Waitlist = req.Waitlist
+	}
+	if req.FormFields != nil {
+		event.FormFields = req.FormFields
+		if len(event.FormFields) == 0 {
+			event.FormFields = nil
+		}
+	}
+	if req.AccessCode != nil {
+		event.AccessCodeHash = hashAccessCode(*req.AccessCode)
+	}
+
+	if err := s.repo.EventRepo.UpdateEvent(event); err != nil {
+		return nil, err
+	}
+	s.recordRevision(actorID, event, diffEventFields(before, eventRevisionFields(event)))
+	s.audit.Record(actorID, AuditEventUpdated, AuditEntityEvent, event.ID.String(), map[string]interface{}{
+		"title": event.Title,
+		"slug":  event.Slug,
+	})
 
 	return event, nil
 }
 
-func (s *EventService) AddEventDay(eventID string, dayNumber int, label string, date time.Time) (*models.EventDay, error) {
+// DeleteEvent deactivates an event. Its participants and verifications are
+// kept.
+func (s *EventService) DeleteEvent(actorID, eventID string) error {
+	if _, err := s.repo.EventRepo.GetEventByID(eventID); err != nil {
+		return ErrUnknownEvent
+	}
+	if err := s.repo.EventRepo.SoftDeleteEvent(eventID); err != nil {
+		return err
+	}
+	s.audit.Record(actorID, AuditEventDeleted, AuditEntityEvent, eventID, nil)
+	return nil
+}
+
+// AddEventDay adds a day to an event. venueID is only set for days held
+// elsewhere than the event's venue.
+type EventDayRequest struct {
+	DayNumber int
+	Label     string
+	Date      time.Time
+	VenueID   string // optional, the event's venue applies otherwise
+	Capacity  *int   // nil = unlimited
+}
+
+func (s *EventService) AddEventDay(eventID string, req EventDayRequest) (*models.EventDay, error) {
 	// Verify event exists
 	event, err := s.repo.EventRepo.GetEventByID(eventID)
 	if err != nil {
 		return nil, errors.New("event not found")
 	}
+	if req.Capacity != nil && *req.Capacity <= 0 {
+		return nil, errors.New("capacity must be positive")
+	}
+	dayVenueID, err := s.venueID(req.VenueID)
+	if err != nil {
+		return nil, err
+	}
 
 	day := &models.EventDay{
 		ID:        uuid.New(),
 		EventID:   event.ID,
-		DayNumber: dayNumber,
-		Label:     label,
-		Date:      date,
+		DayNumber: req.DayNumber,
+		Label:     req.Label,
+		Date:      req.Date,
+		VenueID:   dayVenueID,
+		Capacity:  req.Capacity,
 	}
 
 	if err := s.repo.EventRepo.CreateEventDay(day); err != nil {
@@ -79,20 +301,121 @@ func (s *EventService) AddEventDay(eventID string, dayNumber int, label string,
 	return day, nil
 }
 
-func (s *EventService) AddEventAction(eventID, dayID, name, code string) (*models.EventAction, error) {
+// maxBulkEventDays caps the days one bulk request creates
+const maxBulkEventDays = 366
+
+// BulkEventDaysRequest adds one day per date, either every day from StartDate
+// through EndDate or the listed Dates
+type BulkEventDaysRequest struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+	Dates     []time.Time
+	// One label per date, in the order of the dates; "Day <number>" when
+	// omitted
+	Labels   []string
+	VenueID  string // optional, applies to every day
+	Capacity *int   // optional, applies to every day
+}
+
+// dates expands the request into the dates of the days to create
+func (req BulkEventDaysRequest) dates() ([]time.Time, error) {
+	if len(req.Dates) > 0 {
+		if req.StartDate != nil || req.EndDate != nil {
+			return nil, errors.New("give either a date range or a list of dates")
+		}
+		if len(req.Dates) > maxBulkEventDays {
+			return nil, fmt.Errorf("at most %d days can be added at once", maxBulkEventDays)
+		}
+		return req.Dates, nil
+	}
+
+	if req.StartDate == nil || req.EndDate == nil {
+		return nil, errors.New("start and end date are required")
+	}
+	if req.EndDate.Before(*req.StartDate) {
+		return nil, errors.New("end date must not be before start date")
+	}
+	var dates []time.Time
+	for date := *req.StartDate; !date.After(*req.EndDate); date = date.AddDate(0, 0, 1) {
+		if len(dates) == maxBulkEventDays {
+			return nil, fmt.Errorf("at most %d days can be added at once", maxBulkEventDays)
+		}
+		dates = append(dates, date)
+	}
+	return dates, nil
+}
+
+// AddEventDays adds several days to an event at once. They are numbered
+// after the event's last day, in date order.
+func (s *EventService) AddEventDays(eventID string, req BulkEventDaysRequest) ([]models.EventDay, error) {
+	if _, err := s.repo.EventRepo.GetEventByID(eventID); err != nil {
+		return nil, ErrUnknownEvent
+	}
+	dates, err := req.dates()
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Labels) > 0 && len(req.Labels) != len(dates) {
+		return nil, fmt.Errorf("expected %d labels, one per day, got %d", len(dates), len(req.Labels))
+	}
+	if req.Capacity != nil && *req.Capacity <= 0 {
+		return nil, errors.New("capacity must be positive")
+	}
+	venueID, err := s.venueID(req.VenueID)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]models.EventDay, len(dates))
+	for i, date := range dates {
+		days[i] = models.EventDay{
+			ID:       uuid.New(),
+			Date:     date,
+			VenueID:  venueID,
+			Capacity: req.Capacity,
+		}
+		if len(req.Labels) > 0 {
+			days[i].Label = strings.TrimSpace(req.Labels[i])
+		}
+	}
+	sort.SliceStable(days, func(i, j int) bool { return days[i].Date.Before(days[j].Date) })
+	if err := s.repo.EventRepo.CreateEventDays(eventID, days); err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+func (s *EventService) AddEventAction(eventID, dayID string, req EventActionRequest) (*models.EventAction, error) {
 	// Verify event and day exist
 	event, err := s.repo.EventRepo.GetEventByID(eventID)
 	if err != nil {
 		return nil, errors.New("event not found")
 	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	// New actions are listed after the day's other actions
+	position := 0
+	if actions, err := s.repo.EventRepo.GetEventActionsByDayID(dayID); err == nil {
+		for _, other := range actions {
+			if other.Position >= position {
+				position = other.Position + 1
+			}
+		}
+	}
 
 	action := &models.EventAction{
 		ID:         uuid.New(),
 		EventID:    event.ID,
 		EventDayID: uuid.MustParse(dayID),
-		Name:       name,
-		Code:       code,
+		Name:       req.Name,
+		Code:       req.Code,
+		Zone:       strings.ToLower(req.Zone),
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
 		IsActive:   true,
+		Position:   position,
 	}
 
 	if err := s.repo.EventRepo.CreateEventAction(action); err != nil {
@@ -102,7 +425,24 @@ func (s *EventService) AddEventAction(eventID, dayID, name, code string) (*model
 	return action, nil
 }
 
-func (s *EventService) ListEvents(page, pageSize int) ([]models.Event, int64, int, error) {
+// ListEvents returns events of any status matching filters, which may be nil
+func (s *EventService) ListEvents(page, pageSize int, filters *repositories.EventFilters) ([]models.Event, int64, int, error) {
+	return s.listEvents(page, pageSize, filters)
+}
+
+// ListPublishedEvents returns the public events listed publicly. The activity and
+// status of filters are overridden.
+func (s *EventService) ListPublishedEvents(page, pageSize int, filters *repositories.EventFilters) ([]models.Event, int64, int, error) {
+	published := repositories.EventFilters{}
+	if filters != nil {
+		published = *filters
+	}
+	active := true
+	published.IsActive, published.Status, published.Visibility = &active, EventPublished, EventPublic
+	return s.listEvents(page, pageSize, &published)
+}
+
+func (s *EventService) listEvents(page, pageSize int, filters *repositories.EventFilters) ([]models.Event, int64, int, error) {
 	if page <= 0 {
 		page = 1
 	}
@@ -111,19 +451,76 @@ func (s *EventService) ListEvents(page, pageSize int) ([]models.Event, int64, in
 	}
 
 	offset := (page - 1) * pageSize
-	events, total, err := s.repo.EventRepo.ListEvents(offset, pageSize, nil)
+	events, total, err := s.repo.EventRepo.ListEvents(offset, pageSize, filters)
 	if err != nil {
 		return nil, 0, 0, err
 	}
+	for i := range events {
+		withRegistrationState(&events[i])
+	}
 
 	totalPages := (int(total) + pageSize - 1) / pageSize
 	return events, total, totalPages, nil
 }
 
 func (s *EventService) GetEvent(id string) (*models.Event, error) {
-	return s.repo.EventRepo.GetEventByID(id)
+	event, err := s.repo.EventRepo.GetEventByID(id)
+	if err != nil {
+		return nil, err
+	}
+	withRegistrationState(event)
+	return event, nil
 }
 
 func (s *EventService) GetEventBySlug(slug string) (*models.Event, error) {
-	return s.repo.EventRepo.GetEventBySlug(slug)
+	event, err := s.repo.EventRepo.GetEventBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	withRegistrationState(event)
+	return event, nil
+}
+
+// GenerateLogoVariants resizes the event logo into utils.LogoVariants and
+// stores their URLs on the event
+func (s *EventService) GenerateLogoVariants(eventID string) error {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return err
+	}
+	if event.LogoPath == "" {
+		return nil
+	}
+
+	srcPath := filepath.Join(s.cfg.LogoDir, filepath.Base(event.LogoPath))
+	files, err := utils.GenerateImageVariants(srcPath, utils.LogoVariants)
+	if err != nil {
+		return err
+	}
+
+	variants := make(map[string]string, len(files))
+	for name, filename := range files {
+		variants[name] = "/logos/" + filename
+	}
+
+	return s.repo.EventRepo.UpdateLogoVariants(eventID, variants)
+}
+
+// normalizeOrigins reduces URLs to scheme://host[:port] as sent in the
+// Origin header and joins them for storage
+func normalizeOrigins(origins []string) string {
+	seen := make(map[string]bool, len(origins))
+	var normalized []string
+	for _, origin := range origins {
+		u, err := url.Parse(strings.TrimSpace(origin))
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			continue
+		}
+		o := strings.ToLower(u.Scheme + "://" + u.Host)
+		if !seen[o] {
+			seen[o] = true
+			normalized = append(normalized, o)
+		}
+	}
+	return strings.Join(normalized, ",")
 }
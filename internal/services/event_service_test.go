@@ -0,0 +1,1030 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"event-management-backend/internal/mail"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/repositories/memory"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestCreateEvent(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		req        CreateEventRequest
+		wantErr    string
+		wantFormat string
+	}{
+		{
+			name:       "defaults to in person",
+			req:        CreateEventRequest{Title: "Expo", Slug: "expo", StartsAt: start, EndsAt: start.Add(8 * time.Hour)},
+			wantFormat: EventInPerson,
+		},
+		{
+			name:       "same start and end",
+			req:        CreateEventRequest{Title: "Expo", Slug: "expo", StartsAt: start, EndsAt: start, Format: EventInPerson},
+			wantFormat: EventInPerson,
+		},
+		{
+			name:    "ends before it starts",
+			req:     CreateEventRequest{Title: "Expo", Slug: "expo", StartsAt: start, EndsAt: start.Add(-time.Hour)},
+			wantErr: "end date must be after start date",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			svc := NewEventService(e.repo, e.cfg)
+
+			event, err := svc.CreateEvent("", tt.req)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !event.IsActive || event.Status != EventDraft || event.Format != tt.wantFormat {
+				t.Fatalf("active %v, status %q, format %q; want active draft, %q", event.IsActive, event.Status, event.Format, tt.wantFormat)
+			}
+
+			stored, err := svc.GetEventBySlug(tt.req.Slug)
+			if err != nil || stored.ID != event.ID {
+				t.Fatalf("GetEventBySlug = %v, %v; want %s", stored, err, event.ID)
+			}
+		})
+	}
+}
+
+func TestUpdateEvent(t *testing.T) {
+	quota := 1
+	stale := 7
+
+	tests := []struct {
+		name    string
+		edit    func(req *UpdateEventRequest)
+		wantErr error
+	}{
+		{name: "new title and slug", edit: func(req *UpdateEventRequest) { req.Title, req.Slug = "Expo 2", "expo2" }},
+		{name: "slug of another event", edit: func(req *UpdateEventRequest) { req.Slug = "taken" }, wantErr: ErrEventSlugTaken},
+		{name: "ends before it starts", edit: func(req *UpdateEventRequest) { req.EndsAt = req.StartsAt.Add(-time.Hour) }, wantErr: errors.New("end date must be after start date")},
+		{name: "quota below registrations", edit: func(req *UpdateEventRequest) { req.TicketQuota = &quota }, wantErr: errors.New("ticket quota cannot be below the 2 registered participants")},
+		{name: "stale version", edit: func(req *UpdateEventRequest) { req.Version = &stale }, wantErr: repositories.ErrVersionConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			svc := NewEventService(e.repo, e.cfg)
+			event := e.fx.Event()
+			e.fx.Event(func(ev *models.Event) { ev.Slug = "taken" })
+			e.fx.Participant(event)
+			e.fx.Participant(event)
+
+			req := UpdateEventRequest{
+				CreateEventRequest: CreateEventRequest{Title: event.Title, Slug: event.Slug, StartsAt: event.StartsAt, EndsAt: event.EndsAt},
+				Version:            &event.Version,
+			}
+			tt.edit(&req)
+
+			updated, err := svc.UpdateEvent("", event.ID.String(), req)
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if updated.Version != event.Version+1 || updated.Format != event.Format {
+				t.Fatalf("version %d, format %q; want %d, %q", updated.Version, updated.Format, event.Version+1, event.Format)
+			}
+			if stored, err := svc.GetEventBySlug(req.Slug); err != nil || stored.Title != req.Title {
+				t.Fatalf("GetEventBySlug = %+v, %v; want title %q", stored, err, req.Title)
+			}
+		})
+	}
+}
+
+func TestEventRevisions(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+	editor := e.fx.User("organizer")
+	event := e.fx.Event()
+
+	base := CreateEventRequest{Title: event.Title, Slug: event.Slug, StartsAt: event.StartsAt, EndsAt: event.EndsAt}
+	unchanged := UpdateEventRequest{CreateEventRequest: base}
+	if _, err := svc.UpdateEvent(editor.ID.String(), event.ID.String(), unchanged); err != nil {
+		t.Fatal(err)
+	}
+
+	code := "s3cret"
+	edited := UpdateEventRequest{CreateEventRequest: base, AccessCode: &code}
+	edited.Title = "Expo 2"
+	edited.EndsAt = event.EndsAt.Add(time.Hour)
+	if _, err := svc.UpdateEvent(editor.ID.String(), event.ID.String(), edited); err != nil {
+		t.Fatal(err)
+	}
+
+	revisions, total, _, err := svc.ListEventRevisions(event.ID.String(), 1, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 {
+		t.Fatalf("%d revisions, want 1: an update without changes records none", total)
+	}
+	revision := revisions[0]
+	if revision.EditorID == nil || *revision.EditorID != editor.ID || revision.Version != event.Version+2 {
+		t.Fatalf("editor %v, version %d; want %s, %d", revision.EditorID, revision.Version, editor.ID, event.Version+2)
+	}
+	want := map[string]models.FieldChange{
+		"title":           {From: event.Title, To: "Expo 2"},
+		"ends_at":         {From: revisionTime(&event.EndsAt), To: revisionTime(&edited.EndsAt)},
+		"access_code_set": {From: false, To: true},
+	}
+	if !reflect.DeepEqual(revision.Changes, want) {
+		t.Fatalf("changes = %v, want %v", revision.Changes, want)
+	}
+
+	if _, _, _, err := svc.ListEventRevisions("00000000-0000-0000-0000-000000000000", 1, 20); !errors.Is(err, ErrUnknownEvent) {
+		t.Fatalf("unknown event: error = %v, want %v", err, ErrUnknownEvent)
+	}
+}
+
+func TestExportEventBundle(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+	staff := e.fx.User("staff")
+	event := e.fx.Event()
+	action := e.fx.Action(e.fx.Day(event))
+	// One more than a batch, so the participants are read in two
+	participants := make([]*models.Participant, exportBatchSize+1)
+	for i := range participants {
+		participants[i] = e.fx.Participant(event)
+	}
+	e.fx.Verification(participants[0], action, staff)
+
+	for _, excludePII := range []bool{false, true} {
+		export, err := svc.ExportEventBundle("", event.ID.String(), excludePII)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		if err := export.Write(&out); err != nil {
+			t.Fatal(err)
+		}
+
+		var bundle EventBundle
+		if err := json.Unmarshal(out.Bytes(), &bundle); err != nil {
+			t.Fatalf("exclude_pii=%v: invalid JSON: %v", excludePII, err)
+		}
+		if bundle.Format != eventBundleFormat || bundle.PIIExcluded != excludePII || len(bundle.Definition.Days) != 1 {
+			t.Fatalf("exclude_pii=%v: format %q, pii_excluded %v, %d days", excludePII, bundle.Format, bundle.PIIExcluded, len(bundle.Definition.Days))
+		}
+		if len(bundle.Participants) != len(participants) || len(bundle.ActionLogs) != 1 {
+			t.Fatalf("exclude_pii=%v: %d participants, %d action logs; want %d, 1", excludePII, len(bundle.Participants), len(bundle.ActionLogs), len(participants))
+		}
+		if log := bundle.ActionLogs[0]; log.ParticipantRef != participants[0].ID.String() || log.ActionRef != bundle.Definition.Days[0].Actions[0].Ref {
+			t.Fatalf("exclude_pii=%v: action log %+v does not reference the participant and action", excludePII, log)
+		}
+		for _, participant := range bundle.Participants {
+			if hasPII := participant.Email != ""; hasPII == excludePII {
+				t.Fatalf("exclude_pii=%v: participant email %q", excludePII, participant.Email)
+			}
+		}
+	}
+
+	if _, err := svc.ExportEventBundle("", "00000000-0000-0000-0000-000000000000", false); !errors.Is(err, ErrUnknownEvent) {
+		t.Fatalf("unknown event: error = %v, want %v", err, ErrUnknownEvent)
+	}
+}
+
+func TestImportEventBundle(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+	admin := e.fx.User("admin")
+	event := e.fx.Event()
+	action := e.fx.Action(e.fx.Day(event))
+	checkedIn := e.fx.Participant(event)
+	e.fx.Participant(event, func(p *models.Participant) { p.ApprovalStatus = ApprovalPending })
+	e.fx.Verification(checkedIn, action, admin)
+
+	exportBundle := func(excludePII bool) *EventBundle {
+		t.Helper()
+		export, err := svc.ExportEventBundle("", event.ID.String(), excludePII)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		if err := export.Write(&out); err != nil {
+			t.Fatal(err)
+		}
+		var bundle EventBundle
+		if err := json.Unmarshal(out.Bytes(), &bundle); err != nil {
+			t.Fatal(err)
+		}
+		return &bundle
+	}
+	copyOpts := func(slug string, participants bool) BundleImportOptions {
+		return BundleImportOptions{ImportOptions: ImportOptions{Slug: slug, CodeSuffix: "-" + slug}, Participants: participants}
+	}
+
+	imported, err := svc.ImportEventBundle(admin.ID.String(), exportBundle(false), copyOpts("copy", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Participants != 2 || imported.ActionLogs != 1 || imported.Event.Status != EventDraft {
+		t.Fatalf("imported %d participants, %d action logs, status %q; want 2, 1, draft", imported.Participants, imported.ActionLogs, imported.Event.Status)
+	}
+	participants, _, err := e.repo.ParticipantRepo.ListParticipantsByEvent(imported.Event.ID.String(), 0, 10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, participant := range participants {
+		if withQR := participant.QRPath != ""; withQR != (participant.ApprovalStatus == ApprovalApproved) {
+			t.Fatalf("%s (%s) has QR path %q", participant.Email, participant.ApprovalStatus, participant.QRPath)
+		}
+		if participant.Email == checkedIn.Email {
+			logs, err := e.repo.ActionRepo.GetActionLogsByParticipant(participant.ID.String())
+			if err != nil || len(logs) != 1 || *logs[0].VerifiedBy != admin.ID {
+				t.Fatalf("check-ins of the imported participant = %v, %v; want one by the importing admin", logs, err)
+			}
+		}
+	}
+
+	// Bundles without personal data only carry the definition
+	var importErr *EventImportError
+	if _, err := svc.ImportEventBundle(admin.ID.String(), exportBundle(true), copyOpts("anonymous", true)); !errors.As(err, &importErr) {
+		t.Fatalf("participants without PII: error = %v, want an import error", err)
+	}
+	if imported, err := svc.ImportEventBundle(admin.ID.String(), exportBundle(true), copyOpts("anonymous", false)); err != nil || imported.Participants != 0 {
+		t.Fatalf("definition only: %+v, %v", imported, err)
+	}
+}
+
+func TestImportEventCalendar(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+
+	const calendar = "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"X-WR-CALNAME:Dev Summit\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Workshops\r\n" +
+		"DTSTART:20270301T080000Z\r\n" +
+		"DTEND:20270301T170000Z\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"SUMMARY:Confer\r\n" +
+		" ence\r\n" +
+		"DTSTART;VALUE=DATE:20270302\r\n" +
+		"DTEND;VALUE=DATE:20270304\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	event, err := svc.ImportEventCalendar(strings.NewReader(calendar), ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Title != "Dev Summit" || event.Slug != "dev-summit" || event.Status != EventDraft {
+		t.Fatalf("title %q, slug %q, status %q", event.Title, event.Slug, event.Status)
+	}
+	wantEnd := time.Date(2027, 3, 4, 0, 0, 0, 0, time.UTC)
+	if !event.StartsAt.Equal(time.Date(2027, 3, 1, 8, 0, 0, 0, time.UTC)) || !event.EndsAt.Equal(wantEnd) {
+		t.Fatalf("event runs %s to %s", event.StartsAt, event.EndsAt)
+	}
+
+	var labels []string
+	for _, day := range event.EventDays {
+		labels = append(labels, fmt.Sprintf("%s %s", day.Date.Format("2006-01-02"), day.Label))
+	}
+	want := []string{"2027-03-01 Workshops", "2027-03-02 Conference", "2027-03-03 Day 3"}
+	if !reflect.DeepEqual(labels, want) {
+		t.Fatalf("days = %v, want %v", labels, want)
+	}
+
+	if _, err := svc.ImportEventCalendar(strings.NewReader("not a calendar"), ImportOptions{}); !errors.Is(err, ErrInvalidCalendar) {
+		t.Fatalf("error = %v, want %v", err, ErrInvalidCalendar)
+	}
+}
+
+func TestEventStatus(t *testing.T) {
+	tests := []struct {
+		from    string
+		to      string
+		allowed bool
+	}{
+		{from: EventDraft, to: EventPublished, allowed: true},
+		{from: EventDraft, to: EventCancelled},
+		{from: EventDraft, to: EventArchived, allowed: true},
+		{from: EventPublished, to: EventPublished},
+		{from: EventPublished, to: EventCancelled, allowed: true},
+		{from: EventCancelled, to: EventPublished},
+		{from: EventCancelled, to: EventArchived, allowed: true},
+		{from: EventArchived, to: EventPublished},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			e := newTestEnv(t)
+			svc := NewEventService(e.repo, e.cfg)
+			event := e.fx.Event(func(ev *models.Event) { ev.Status = tt.from })
+
+			change := map[string]func(actorID, eventID string) (*models.Event, error){
+				EventPublished: svc.PublishEvent,
+				EventCancelled: func(actorID, eventID string) (*models.Event, error) {
+					cancellation, err := svc.CancelEvent(actorID, eventID, CancelEventRequest{})
+					if err != nil {
+						return nil, err
+					}
+					return cancellation.Event, nil
+				},
+				EventArchived: svc.ArchiveEvent,
+			}[tt.to]
+
+			changed, err := change("", event.ID.String())
+			var transitionErr *InvalidTransitionError
+			if !tt.allowed {
+				if !errors.As(err, &transitionErr) {
+					t.Fatalf("error = %v, want an invalid transition", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stored, _ := svc.GetEvent(event.ID.String()); changed.Status != tt.to || stored.Status != tt.to {
+				t.Fatalf("status %q, stored %q; want %q", changed.Status, stored.Status, tt.to)
+			}
+		})
+	}
+
+	t.Run("public listing and registration", func(t *testing.T) {
+		e := newTestEnv(t)
+		svc := NewEventService(e.repo, e.cfg)
+		published := e.fx.Event()
+		draft := e.fx.Event(func(ev *models.Event) { ev.Status = EventDraft })
+		e.fx.Event(func(ev *models.Event) { ev.Status = EventCancelled })
+		e.fx.Event(func(ev *models.Event) { ev.IsActive = false })
+
+		events, total, _, err := svc.ListPublishedEvents(1, 20, nil)
+		if err != nil || total != 1 || events[0].ID != published.ID {
+			t.Fatalf("ListPublishedEvents = %d events, total %d, %v; want only %s", len(events), total, err, published.ID)
+		}
+
+		_, err = NewParticipantService(e.repo, e.cfg).RegisterParticipant(RegisterParticipantRequest{
+			EventID: draft.ID.String(), Name: "Ani", Email: "ani@example.com",
+		})
+		if !errors.Is(err, ErrEventNotPublished) {
+			t.Fatalf("registering for a draft: error = %v, want %v", err, ErrEventNotPublished)
+		}
+	})
+}
+
+// recordingSender keeps the emails it is asked to send
+type recordingSender struct {
+	sent []mail.Message
+}
+
+func (s *recordingSender) Send(_ context.Context, msg mail.Message) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestCancelEvent(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+	event := e.fx.Event(func(ev *models.Event) { ev.TicketPrice = 50 })
+	unpaid := e.fx.Participant(event)
+	paid := e.fx.Participant(event, e.fx.Paid)
+	other := e.fx.Participant(e.fx.Event())
+
+	cancellation, err := svc.CancelEvent("", event.ID.String(), CancelEventRequest{Reason: " Venue flooded ", Refund: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancellation.Event.Status != EventCancelled || cancellation.Cancelled != 2 || cancellation.RefundsPending != 1 {
+		t.Fatalf("cancellation = %+v, want 2 cancelled and 1 refund", cancellation)
+	}
+
+	for _, tt := range []struct {
+		participant *models.Participant
+		payment     string
+	}{
+		{participant: unpaid, payment: "unpaid"},
+		{participant: paid, payment: "refund_pending"},
+	} {
+		stored, _ := e.repo.ParticipantRepo.GetParticipantByID(tt.participant.ID.String())
+		if stored.CancelledAt == nil || stored.CancellationReason != "Venue flooded" || stored.PaymentStatus != tt.payment {
+			t.Fatalf("participant = cancelled at %v, reason %q, payment %q; want cancelled, %q", stored.CancelledAt, stored.CancellationReason, stored.PaymentStatus, tt.payment)
+		}
+	}
+	if stored, _ := e.repo.ParticipantRepo.GetParticipantByID(other.ID.String()); stored.CancelledAt != nil {
+		t.Fatal("participant of another event was cancelled")
+	}
+
+	mailer := &recordingSender{}
+	notifications := NewNotificationService(e.repo, mailer, nil, e.cfg)
+	sent, err := notifications.SendCancellationNotices(context.Background(), event.ID.String())
+	if err != nil || sent != 2 || len(mailer.sent) != 2 {
+		t.Fatalf("sent %d notices (%v), %d emails; want 2", sent, err, len(mailer.sent))
+	}
+	if sent, _ := notifications.SendCancellationNotices(context.Background(), event.ID.String()); sent != 0 {
+		t.Fatalf("resent %d notices, want 0", sent)
+	}
+
+	var transitionErr *InvalidTransitionError
+	if _, err := svc.CancelEvent("", event.ID.String(), CancelEventRequest{}); !errors.As(err, &transitionErr) {
+		t.Fatalf("cancelling twice: error = %v, want an invalid transition", err)
+	}
+}
+
+func TestDeleteEvent(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+	event := e.fx.Event()
+
+	if err := svc.DeleteEvent("", event.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := svc.GetEvent(event.ID.String()); stored.IsActive {
+		t.Fatal("deleted event is still active")
+	}
+	if err := svc.DeleteEvent("", e.fx.User("staff").ID.String()); !errors.Is(err, ErrUnknownEvent) {
+		t.Fatalf("unknown event: error = %v, want %v", err, ErrUnknownEvent)
+	}
+}
+
+func TestVenues(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+	lat := -6.2
+
+	if _, err := svc.CreateVenue(VenueRequest{Name: "Hall", Latitude: &lat}); err == nil {
+		t.Fatal("venue with latitude but no longitude was created")
+	}
+
+	venue, err := svc.CreateVenue(VenueRequest{Name: " Hall A ", Address: "Jl. Sudirman 1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if venue.Name != "Hall A" {
+		t.Fatalf("name = %q, want %q", venue.Name, "Hall A")
+	}
+
+	event := e.fx.Event()
+	req := CreateEventRequest{
+		Title:    event.Title,
+		Slug:     event.Slug,
+		StartsAt: event.StartsAt,
+		EndsAt:   event.EndsAt,
+		VenueID:  venue.ID.String(),
+	}
+	if _, err := svc.UpdateEvent("", event.ID.String(), UpdateEventRequest{CreateEventRequest: req}); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := svc.GetEvent(event.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Venue == nil || stored.Venue.ID != venue.ID {
+		t.Fatalf("event venue = %+v, want %s", stored.Venue, venue.ID)
+	}
+
+	req.VenueID = e.fx.User("staff").ID.String()
+	if _, err := svc.UpdateEvent("", event.ID.String(), UpdateEventRequest{CreateEventRequest: req}); !errors.Is(err, ErrUnknownVenue) {
+		t.Fatalf("unknown venue: error = %v, want %v", err, ErrUnknownVenue)
+	}
+
+	if err := svc.DeleteVenue(venue.ID.String()); !errors.Is(err, repositories.ErrVenueInUse) {
+		t.Fatalf("venue in use: error = %v, want %v", err, repositories.ErrVenueInUse)
+	}
+	req.VenueID = ""
+	if _, err := svc.UpdateEvent("", event.ID.String(), UpdateEventRequest{CreateEventRequest: req}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.DeleteVenue(venue.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.GetVenue(venue.ID.String()); !errors.Is(err, ErrUnknownVenue) {
+		t.Fatalf("deleted venue: error = %v, want %v", err, ErrUnknownVenue)
+	}
+}
+
+func TestEventImages(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+	event := e.fx.Event()
+	eventID := event.ID.String()
+
+	first, err := svc.AddEventImage(eventID, "/images/a.png", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := svc.AddEventImage(eventID, "/images/b.png", " Stage ", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Position != 1 || second.Caption != "Stage" {
+		t.Fatalf("second image = %+v, want position 1 and caption %q", second, "Stage")
+	}
+
+	stored, err := svc.GetEvent(eventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Banner == nil || stored.Banner.ID != second.ID {
+		t.Fatalf("banner = %+v, want the latest banner upload %s", stored.Banner, second.ID)
+	}
+
+	if _, err := svc.ReorderEventImages(eventID, []string{second.ID.String()}); !errors.Is(err, ErrImageOrder) {
+		t.Fatalf("partial order: error = %v, want %v", err, ErrImageOrder)
+	}
+	images, err := svc.ReorderEventImages(eventID, []string{second.ID.String(), first.ID.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if images[0].ID != second.ID || images[1].ID != first.ID {
+		t.Fatalf("order = %s, %s, want %s, %s", images[0].ID, images[1].ID, second.ID, first.ID)
+	}
+
+	if _, err := svc.SetBannerImage(eventID, first.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	images, err = svc.ListEventImages(eventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, image := range images {
+		if image.IsBanner != (image.ID == first.ID) {
+			t.Fatalf("image %s banner = %v after moving the banner", image.ID, image.IsBanner)
+		}
+	}
+
+	other := e.fx.Event()
+	if err := svc.DeleteEventImage(other.ID.String(), first.ID.String()); !errors.Is(err, ErrUnknownEventImage) {
+		t.Fatalf("image of another event: error = %v, want %v", err, ErrUnknownEventImage)
+	}
+	if err := svc.DeleteEventImage(eventID, first.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	if stored, err = svc.GetEvent(eventID); err != nil {
+		t.Fatal(err)
+	}
+	if stored.Banner != nil {
+		t.Fatalf("banner = %+v after deleting it, want none", stored.Banner)
+	}
+}
+
+func TestAddEventDays(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+	event := e.fx.Event()
+	e.fx.Day(event)
+	start := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 2)
+
+	if _, err := svc.AddEventDays(event.ID.String(), BulkEventDaysRequest{StartDate: &start, EndDate: &end, Labels: []string{"Opening"}}); err == nil {
+		t.Fatal("days were added with fewer labels than days")
+	}
+
+	days, err := svc.AddEventDays(event.ID.String(), BulkEventDaysRequest{StartDate: &start, EndDate: &end})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 3 {
+		t.Fatalf("added %d days, want 3", len(days))
+	}
+	for i, day := range days {
+		if day.DayNumber != i+2 || !day.Date.Equal(start.AddDate(0, 0, i)) {
+			t.Fatalf("day %d = number %d on %s, want number %d on %s", i, day.DayNumber, day.Date, i+2, start.AddDate(0, 0, i))
+		}
+	}
+	if days[0].Label != "Day 2" {
+		t.Fatalf("default label = %q, want %q", days[0].Label, "Day 2")
+	}
+
+	later := []time.Time{end.AddDate(0, 0, 7), end.AddDate(0, 0, 5)}
+	days, err = svc.AddEventDays(event.ID.String(), BulkEventDaysRequest{Dates: later, Labels: []string{"Finals", "Semis"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if days[0].DayNumber != 5 || !days[0].Date.Equal(later[1]) || days[0].Label != "Semis" {
+		t.Fatalf("first listed day = %+v, want day 5 on %s", days[0], later[1])
+	}
+
+	stored, err := e.repo.EventRepo.GetEventDaysByEventID(event.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 6 {
+		t.Fatalf("event has %d days, want 6", len(stored))
+	}
+}
+
+func TestManageEventActions(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+	event := e.fx.Event()
+	day := e.fx.Day(event)
+	eventID, dayID := event.ID.String(), day.ID.String()
+
+	lunch, err := svc.AddEventAction(eventID, dayID, EventActionRequest{Name: "Lunch", Code: "LUNCH1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := svc.AddEventAction(eventID, dayID, EventActionRequest{Name: "Entry", Code: "ENTRY1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lunch.Position != 0 || entry.Position != 1 {
+		t.Fatalf("positions = %d, %d, want 0, 1", lunch.Position, entry.Position)
+	}
+
+	if _, err := svc.ReorderEventActions(eventID, dayID, []string{entry.ID.String()}); !errors.Is(err, ErrActionOrder) {
+		t.Fatalf("partial order: error = %v, want %v", err, ErrActionOrder)
+	}
+	actions, err := svc.ReorderEventActions(eventID, dayID, []string{entry.ID.String(), lunch.ID.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actions[0].ID != entry.ID || actions[1].ID != lunch.ID {
+		t.Fatalf("order = %s, %s, want entry then lunch", actions[0].Name, actions[1].Name)
+	}
+
+	updated, err := svc.UpdateEventAction(eventID, dayID, lunch.ID.String(), EventActionRequest{Name: "Lunch break", Code: "LUNCH2", Zone: "VIP"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Code != "LUNCH2" || updated.Zone != "vip" || updated.Position != 1 {
+		t.Fatalf("updated action = %+v", updated)
+	}
+	if _, err := svc.UpdateEventAction(eventID, dayID, lunch.ID.String(), EventActionRequest{Name: "Lunch", Code: "ENTRY1"}); err == nil {
+		t.Fatal("action was given the code of another action")
+	}
+
+	other := e.fx.Day(event)
+	if err := svc.DeleteEventAction(eventID, other.ID.String(), lunch.ID.String()); !errors.Is(err, ErrUnknownEventAction) {
+		t.Fatalf("action of another day: error = %v, want %v", err, ErrUnknownEventAction)
+	}
+	if err := svc.DeleteEventAction(eventID, dayID, lunch.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.repo.EventRepo.GetEventActionByCode("LUNCH2"); err == nil {
+		t.Fatal("deleted action can still be looked up by code")
+	}
+	if err := svc.DeleteEventAction(eventID, dayID, lunch.ID.String()); !errors.Is(err, ErrUnknownEventAction) {
+		t.Fatalf("deleted twice: error = %v, want %v", err, ErrUnknownEventAction)
+	}
+}
+
+func TestGetEventLanding(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+	quota := 10
+	event := e.fx.Event(func(ev *models.Event) { ev.TicketQuota = &quota })
+	day := e.fx.Day(event)
+	e.fx.Action(day)
+	e.fx.Action(day, func(a *models.EventAction) { a.IsActive = false })
+	e.fx.Participant(event)
+
+	landing, err := svc.GetEventLanding(event.Slug, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(landing.Event.EventDays) != 1 || len(landing.Event.EventDays[0].EventActions) != 1 {
+		t.Fatalf("days = %+v, want one day with its one active action", landing.Event.EventDays)
+	}
+	if landing.Event.RegistrationState != RegistrationOpen {
+		t.Fatalf("registration state = %q, want %q", landing.Event.RegistrationState, RegistrationOpen)
+	}
+	if r := landing.Availability.Remaining; r == nil || *r != 9 {
+		t.Fatalf("remaining = %v, want 9", r)
+	}
+
+	draft := e.fx.Event(func(ev *models.Event) { ev.Status = EventDraft })
+	if _, err := svc.GetEventLanding(draft.Slug, ""); !errors.Is(err, ErrUnknownEvent) {
+		t.Fatalf("draft: error = %v, want %v", err, ErrUnknownEvent)
+	}
+}
+
+func TestEventVisibility(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+	public := e.fx.Event()
+	e.fx.Event(func(ev *models.Event) { ev.Visibility = EventUnlisted })
+	private, err := svc.CreateEvent("", CreateEventRequest{
+		Title:      "Board offsite",
+		Slug:       "offsite",
+		StartsAt:   public.StartsAt,
+		EndsAt:     public.EndsAt,
+		Visibility: EventPrivate,
+		AccessCode: " s3cret ",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.PublishEvent("", private.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	events, total, _, err := svc.ListPublishedEvents(1, 20, nil)
+	if err != nil || total != 1 || events[0].ID != public.ID {
+		t.Fatalf("listed %d events (%v), want only the public one", total, err)
+	}
+
+	for _, tt := range []struct {
+		code string
+		want error
+	}{
+		{code: "", want: ErrAccessCodeRequired},
+		{code: "guess", want: ErrInvalidAccessCode},
+		{code: "s3cret"},
+	} {
+		if _, err := svc.GetEventLanding(private.Slug, tt.code); !errors.Is(err, tt.want) {
+			t.Fatalf("code %q: error = %v, want %v", tt.code, err, tt.want)
+		}
+	}
+
+	cleared := ""
+	updated, err := svc.UpdateEvent("", private.ID.String(), UpdateEventRequest{
+		CreateEventRequest: CreateEventRequest{Title: private.Title, Slug: private.Slug, StartsAt: private.StartsAt, EndsAt: private.EndsAt},
+		AccessCode:         &cleared,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Visibility != EventPrivate || CheckAccessCode(updated, "") != nil {
+		t.Fatalf("visibility %q, code check %v; want private without a code", updated.Visibility, CheckAccessCode(updated, ""))
+	}
+}
+
+func TestAddEventDayAndAction(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+	event := e.fx.Event()
+	unknown := e.fx.User("staff").ID.String()
+
+	if _, err := svc.AddEventDay(unknown, EventDayRequest{DayNumber: 1, Label: "Day 1", Date: event.StartsAt}); err == nil || err.Error() != "event not found" {
+		t.Fatalf("AddEventDay on unknown event: error = %v", err)
+	}
+
+	day, err := svc.AddEventDay(event.ID.String(), EventDayRequest{DayNumber: 1, Label: "Day 1", Date: event.StartsAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.AddEventAction(unknown, day.ID.String(), EventActionRequest{Name: "Lunch", Code: "LUNCH"}); err == nil || err.Error() != "event not found" {
+		t.Fatalf("AddEventAction on unknown event: error = %v", err)
+	}
+
+	action, err := svc.AddEventAction(event.ID.String(), day.ID.String(), EventActionRequest{Name: "Backstage", Code: "BACK", Zone: "Backstage"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action.Zone != "backstage" || !action.IsActive {
+		t.Fatalf("zone %q, active %v; want backstage, active", action.Zone, action.IsActive)
+	}
+}
+
+func TestListEvents(t *testing.T) {
+	e := newTestEnv(t)
+	for i := 0; i < 5; i++ {
+		e.fx.Event()
+	}
+	svc := NewEventService(e.repo, e.cfg)
+
+	tests := []struct {
+		name      string
+		page      int
+		pageSize  int
+		wantLen   int
+		wantPages int
+	}{
+		{name: "defaults", page: 0, pageSize: 0, wantLen: 5, wantPages: 1},
+		{name: "page size over the limit", page: 1, pageSize: 500, wantLen: 5, wantPages: 1},
+		{name: "first page", page: 1, pageSize: 2, wantLen: 2, wantPages: 3},
+		{name: "last page", page: 3, pageSize: 2, wantLen: 1, wantPages: 3},
+		{name: "past the end", page: 4, pageSize: 2, wantLen: 0, wantPages: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, total, pages, err := svc.ListEvents(tt.page, tt.pageSize, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != tt.wantLen || total != 5 || pages != tt.wantPages {
+				t.Fatalf("got %d events, total %d, %d pages; want %d, 5, %d", len(events), total, pages, tt.wantLen, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestListEventFilters(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+	now := time.Now()
+	e.fx.Event(func(ev *models.Event) {
+		ev.Title = "Go Conference"
+		ev.StartsAt, ev.EndsAt = now.AddDate(0, 1, 0), now.AddDate(0, 1, 1)
+	})
+	e.fx.Event(func(ev *models.Event) {
+		ev.Title = "Rust Meetup"
+		ev.StartsAt, ev.EndsAt = now.AddDate(0, -1, 0), now.AddDate(0, -1, 1)
+	})
+	deleted := e.fx.Event(func(ev *models.Event) { ev.Title = "Go Workshop" })
+	if err := svc.DeleteEvent("", deleted.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	active, inactive := true, false
+
+	tests := []struct {
+		name      string
+		filters   *repositories.EventFilters
+		published bool
+		want      []string
+	}{
+		{name: "search", filters: &repositories.EventFilters{Search: "go"}, want: []string{"Go Conference", "Go Workshop"}},
+		{name: "active only", filters: &repositories.EventFilters{Search: "go", IsActive: &active}, want: []string{"Go Conference"}},
+		{name: "deleted only", filters: &repositories.EventFilters{IsActive: &inactive}, want: []string{"Go Workshop"}},
+		{name: "starts after", filters: &repositories.EventFilters{StartsAfter: &now, IsActive: &active}, want: []string{"Go Conference"}},
+		{name: "ends before", filters: &repositories.EventFilters{EndsBefore: &now}, want: []string{"Rust Meetup"}},
+		{name: "published listing ignores is_active", filters: &repositories.EventFilters{Search: "go", IsActive: &inactive}, published: true, want: []string{"Go Conference"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := svc.ListEvents
+			if tt.published {
+				list = svc.ListPublishedEvents
+			}
+			events, _, _, err := list(1, 20, tt.filters)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0, len(events))
+			for _, event := range events {
+				got = append(got, event.Title)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("events = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventStaff(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewEventService(e.repo, e.cfg)
+
+	event := e.fx.Event()
+	other := e.fx.Event()
+	admin := e.fx.User("admin")
+	staff := e.fx.User("staff")
+	organizer := e.fx.User("organizer")
+
+	assignTests := []struct {
+		name    string
+		eventID string
+		userID  string
+		wantErr error
+	}{
+		{name: "staff user", eventID: event.ID.String(), userID: staff.ID.String()},
+		{name: "assigning twice", eventID: event.ID.String(), userID: staff.ID.String()},
+		{name: "organizer", eventID: event.ID.String(), userID: organizer.ID.String(), wantErr: errors.New("only staff users can be assigned to events")},
+		{name: "unknown user", eventID: event.ID.String(), userID: other.ID.String(), wantErr: errors.New("user not found")},
+		{name: "unknown event", eventID: staff.ID.String(), userID: staff.ID.String(), wantErr: ErrUnknownEvent},
+	}
+
+	for _, tt := range assignTests {
+		t.Run("assign "+tt.name, func(t *testing.T) {
+			list, err := svc.AssignStaff(tt.eventID, tt.userID, admin.ID.String())
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(list) != 1 || list[0].UserID != staff.ID || list[0].User.Password != "" {
+				t.Fatalf("staff = %+v, want only %s without a password", list, staff.ID)
+			}
+		})
+	}
+
+	accessTests := []struct {
+		name    string
+		user    *models.User
+		eventID string
+		wantErr error
+	}{
+		{name: "assigned staff", user: staff, eventID: event.ID.String()},
+		{name: "unassigned staff", user: staff, eventID: other.ID.String(), wantErr: ErrEventAccessDenied},
+		{name: "organizer", user: organizer, eventID: other.ID.String()},
+		{name: "admin", user: admin, eventID: other.ID.String()},
+	}
+
+	for _, tt := range accessTests {
+		t.Run("access "+tt.name, func(t *testing.T) {
+			err := svc.CheckEventAccess(tt.user.ID.String(), tt.user.Role, tt.eventID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	ids, err := svc.StaffEventIDs(staff.ID.String(), "staff")
+	if err != nil || len(ids) != 1 || ids[0] != event.ID.String() {
+		t.Fatalf("StaffEventIDs = %v, %v; want [%s]", ids, err, event.ID)
+	}
+	if ids, _ := svc.StaffEventIDs(admin.ID.String(), "admin"); ids != nil {
+		t.Fatalf("StaffEventIDs for admin = %v, want nil", ids)
+	}
+
+	if err := svc.UnassignStaff(event.ID.String(), staff.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.UnassignStaff(event.ID.String(), staff.ID.String()); !errors.Is(err, ErrStaffNotAssigned) {
+		t.Fatalf("second unassign: error = %v, want %v", err, ErrStaffNotAssigned)
+	}
+	if err := svc.CheckEventAccess(staff.ID.String(), "staff", event.ID.String()); !errors.Is(err, ErrEventAccessDenied) {
+		t.Fatalf("access after unassign: error = %v, want %v", err, ErrEventAccessDenied)
+	}
+}
+
+func TestTokenEventScope(t *testing.T) {
+	e := newTestEnv(t)
+	svc := e.authService()
+	event := e.fx.Event()
+	e.fx.Event()
+	staff := e.fx.User("staff")
+	organizer := e.fx.User("organizer")
+	if _, err := NewEventService(e.repo, e.cfg).AssignStaff(event.ID.String(), staff.ID.String(), organizer.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	claimsOf := func(email string) jwt.MapClaims {
+		t.Helper()
+		login, err := svc.Authenticate(email, memory.FixturePassword, "", false, LoginClient{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		token, err := jwt.Parse(login.Token, e.keys.Keyfunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token.Claims.(jwt.MapClaims)
+	}
+
+	if ids := claimsOf(staff.Email)[ClaimEventIDs]; !reflect.DeepEqual(ids, []interface{}{event.ID.String()}) {
+		t.Fatalf("staff token event_ids = %v, want [%s]", ids, event.ID)
+	}
+	if ids, ok := claimsOf(organizer.Email)[ClaimEventIDs]; ok {
+		t.Fatalf("organizer token event_ids = %v, want none", ids)
+	}
+}
+
+func TestIntersectEventScopes(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want []string
+	}{
+		{name: "both unrestricted", want: nil},
+		{name: "one unrestricted", a: []string{"x"}, want: []string{"x"}},
+		{name: "other unrestricted", b: []string{"y"}, want: []string{"y"}},
+		{name: "overlap", a: []string{"x", "y"}, b: []string{"y", "z"}, want: []string{"y"}},
+		{name: "disjoint", a: []string{"x"}, b: []string{"z"}, want: []string{}},
+		{name: "empty scope", a: []string{"x"}, b: []string{}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IntersectEventScopes(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("IntersectEventScopes(%v, %v) = %#v, want %#v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
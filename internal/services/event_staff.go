@@ -0,0 +1,136 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownEvent      = errors.New("event not found")
+	ErrStaffNotAssigned  = errors.New("user is not assigned to this event")
+	ErrEventAccessDenied = errors.New("you are not assigned to this event")
+)
+
+// AssignStaff lets a staff user verify and list participants of an event
+func (s *EventService) AssignStaff(eventID, userID, assignedBy string) ([]models.EventStaff, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+
+	user, err := s.repo.UserRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if !isEventScoped(user.Role) {
+		return nil, errors.New("only staff users can be assigned to events")
+	}
+
+	assignerID, err := uuid.Parse(assignedBy)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	if err := s.repo.EventStaffRepo.AssignEventStaff(&models.EventStaff{
+		EventID:    event.ID,
+		UserID:     user.ID,
+		AssignedBy: assignerID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to assign staff: %w", err)
+	}
+	return s.ListStaff(eventID)
+}
+
+func (s *EventService) UnassignStaff(eventID, userID string) error {
+	if err := s.repo.EventStaffRepo.UnassignEventStaff(eventID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrStaffNotAssigned
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *EventService) ListStaff(eventID string) ([]models.EventStaff, error) {
+	if _, err := s.repo.EventRepo.GetEventByID(eventID); err != nil {
+		return nil, ErrUnknownEvent
+	}
+	staff, err := s.repo.EventStaffRepo.ListEventStaff(eventID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range staff {
+		staff[i].User.Password = ""
+	}
+	return staff, nil
+}
+
+// isEventScoped reports whether users of a role only work on the events they
+// are assigned to: staff and scanner service accounts
+func isEventScoped(role string) bool {
+	return role == "staff" || role == RoleScanner
+}
+
+// ClaimEventIDs is the access token claim listing the events of an
+// event-scoped user when the token was issued
+const ClaimEventIDs = "event_ids"
+
+// IntersectEventScopes returns the events allowed by both scopes, where a nil
+// scope allows any event. Verification limits the events of a user's current
+// assignments to those of their token: events assigned since the token was
+// issued need a token refresh, and unassigned events are refused at once.
+func IntersectEventScopes(a, b []string) []string {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	both := make([]string, 0, len(a))
+	for _, id := range a {
+		if eventAllowed(b, id) {
+			both = append(both, id)
+		}
+	}
+	return both
+}
+
+// StaffEventIDs returns the events a user may verify for. It is nil, meaning
+// any event, for organizers and admins.
+func (s *EventService) StaffEventIDs(userID, role string) ([]string, error) {
+	if !isEventScoped(role) {
+		return nil, nil
+	}
+
+	ids, err := s.repo.EventStaffRepo.ListStaffEventIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	eventIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		eventIDs = append(eventIDs, id.String())
+	}
+	return eventIDs, nil
+}
+
+// CheckEventAccess returns ErrEventAccessDenied when a staff user is not
+// assigned to an event. Organizers and admins can access every event.
+func (s *EventService) CheckEventAccess(userID, role, eventID string) error {
+	if !isEventScoped(role) {
+		return nil
+	}
+
+	assigned, err := s.repo.EventStaffRepo.IsEventStaff(eventID, userID)
+	if err != nil {
+		return err
+	}
+	if !assigned {
+		return ErrEventAccessDenied
+	}
+	return nil
+}
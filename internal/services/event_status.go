@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"event-management-backend/internal/models"
+)
+
+// Event statuses
+const (
+	EventDraft     = "draft"
+	EventPublished = "published"
+	EventCancelled = "cancelled"
+	EventArchived  = "archived"
+)
+
+var (
+	ErrEventNotPublished = errors.New("event is not open for registration")
+	ErrEventArchived     = errors.New("archived events cannot be changed")
+)
+
+// eventTransitions lists the statuses each status may move to. Drafts can be
+// archived without ever being published; cancelled events cannot come back.
+var eventTransitions = map[string][]string{
+	EventDraft:     {EventPublished, EventArchived},
+	EventPublished: {EventCancelled, EventArchived},
+	EventCancelled: {EventArchived},
+}
+
+// InvalidTransitionError is returned for a status change the lifecycle does
+// not allow
+type InvalidTransitionError struct {
+	From, To string
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("cannot change a %s event to %s", e.From, e.To)
+}
+
+// PublishEvent lists a draft event publicly and opens its registration
+func (s *EventService) PublishEvent(actorID, eventID string) (*models.Event, error) {
+	return s.setEventStatus(actorID, eventID, EventPublished)
+}
+
+// CancelEventRequest says what happens to the registrations of a cancelled
+// event
+type CancelEventRequest struct {
+	// Told to participants in their cancellation notice
+	Reason string
+	// Paid registrations move to refund_pending for the payment team
+	Refund bool
+}
+
+// EventCancellation is a cancelled event with what happened to its
+// registrations
+type EventCancellation struct {
+	Event          *models.Event `json:"event"`
+	Cancelled      int64         `json:"cancelled_participants"`
+	RefundsPending int64         `json:"refunds_pending"`
+}
+
+// CancelEvent closes registration of a published event and cancels every
+// registration for it in the same transaction, which voids their QR codes.
+// It stays readable by its ID and slug so participants can see it was
+// cancelled.
+func (s *EventService) CancelEvent(actorID, eventID string, req CancelEventRequest) (*EventCancellation, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+	if err := checkTransition(event, EventCancelled); err != nil {
+		return nil, err
+	}
+
+	from := event.Status
+	event.Status = EventCancelled
+	reason := strings.TrimSpace(req.Reason)
+	counts, err := s.repo.EventRepo.CancelEvent(event, reason, req.Refund)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(actorID, AuditEventStatusChanged, AuditEntityEvent, event.ID.String(), map[string]interface{}{
+		"from":                   from,
+		"to":                     EventCancelled,
+		"reason":                 reason,
+		"cancelled_participants": counts.Cancelled,
+		"refunds_pending":        counts.Refunds,
+	})
+
+	return &EventCancellation{Event: event, Cancelled: counts.Cancelled, RefundsPending: counts.Refunds}, nil
+}
+
+// ArchiveEvent freezes an event that is over or no longer needed
+func (s *EventService) ArchiveEvent(actorID, eventID string) (*models.Event, error) {
+	return s.setEventStatus(actorID, eventID, EventArchived)
+}
+
+func (s *EventService) setEventStatus(actorID, eventID, status string) (*models.Event, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+
+	if err := checkTransition(event, status); err != nil {
+		return nil, err
+	}
+
+	from := event.Status
+	event.Status = status
+	if err := s.repo.EventRepo.UpdateEvent(event); err != nil {
+		return nil, err
+	}
+	s.audit.Record(actorID, AuditEventStatusChanged, AuditEntityEvent, event.ID.String(), map[string]interface{}{
+		"from": from,
+		"to":   status,
+	})
+
+	return event, nil
+}
+
+// checkTransition returns an InvalidTransitionError unless event may move to
+// status
+func checkTransition(event *models.Event, status string) error {
+	for _, next := range eventTransitions[event.Status] {
+		if next == status {
+			return nil
+		}
+	}
+	return &InvalidTransitionError{From: event.Status, To: status}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"event-management-backend/internal/models"
+)
+
+// Event visibilities. Only public events are listed; unlisted and private
+// events are reached by their link, and private ones may also require an
+// access code.
+const (
+	EventPublic   = "public"
+	EventUnlisted = "unlisted"
+	EventPrivate  = "private"
+)
+
+var (
+	ErrAccessCodeRequired = errors.New("access code is required")
+	ErrInvalidAccessCode  = errors.New("invalid access code")
+)
+
+func validateVisibility(visibility string) error {
+	switch visibility {
+	case "", EventPublic, EventUnlisted, EventPrivate:
+		return nil
+	}
+	return errors.New("visibility must be public, unlisted or private")
+}
+
+// hashAccessCode returns the stored form of an access code; empty codes are
+// stored empty
+func hashAccessCode(code string) string {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckAccessCode refuses a private event unless code is its access code.
+// Public and unlisted events, and private events without a code, are open
+// to everyone.
+func CheckAccessCode(event *models.Event, code string) error {
+	if event.Visibility != EventPrivate || event.AccessCodeHash == "" {
+		return nil
+	}
+	if strings.TrimSpace(code) == "" {
+		return ErrAccessCodeRequired
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAccessCode(code)), []byte(event.AccessCodeHash)) != 1 {
+		return ErrInvalidAccessCode
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/fieldcrypt"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// MaxGroupSize is how many participants one group registration may hold
+const MaxGroupSize = 100
+
+// ErrGroupRejected is returned with the row errors when any row of a group
+// registration is refused; nobody of the group is registered then
+var ErrGroupRejected = errors.New("group registration rejected, see the errors by row")
+
+// GroupRegistrationRow is the outcome of one participant of a group
+// registration, in request order
+type GroupRegistrationRow struct {
+	// Position in the request, from 1
+	Row           int        `json:"row"`
+	Email         string     `json:"email"`
+	ParticipantID *uuid.UUID `json:"participant_id,omitempty"`
+	// Empty until the registration is approved and every waiver is signed
+	QRPath         string          `json:"qr_path,omitempty"`
+	PendingWaivers []models.Waiver `json:"pending_waivers,omitempty"`
+	Error          string          `json:"error,omitempty"`
+}
+
+// GroupRegistrationResult is the outcome of a group registration
+type GroupRegistrationResult struct {
+	Registered int                    `json:"registered"`
+	Rows       []GroupRegistrationRow `json:"rows"`
+}
+
+// RegisterGroup registers several participants for one event at once, e.g.
+// the employees of a company. Every row is checked as RegisterParticipant
+// would, and against the other rows, before anyone is registered: when any
+// row is refused the result carries the errors by row with ErrGroupRejected.
+// The group then counts against the ticket quota as a whole, in one
+// transaction, so it is registered entirely or not at all.
+func (s *ParticipantService) RegisterGroup(eventID string, rows []RegisterParticipantRequest) (*GroupRegistrationResult, error) {
+	if len(rows) == 0 || len(rows) > MaxGroupSize {
+		return nil, fmt.Errorf("a group registers 1 to %d participants", MaxGroupSize)
+	}
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+	if event.Status != EventPublished {
+		return nil, ErrEventNotPublished
+	}
+	if err := checkRegistrationWindow(event, time.Now()); err != nil {
+		return nil, err
+	}
+
+	result := &GroupRegistrationResult{Rows: make([]GroupRegistrationRow, len(rows))}
+	rejected := false
+	rowError := func(i int, message string) {
+		if result.Rows[i].Error == "" {
+			result.Rows[i].Error = message
+			rejected = true
+		}
+	}
+
+	hashes := make([]string, len(rows))
+	for i, row := range rows {
+		hashes[i] = fieldcrypt.Hash(strings.TrimSpace(row.Email))
+	}
+	registered, err := s.repo.ParticipantRepo.GetRegisteredEmailHashes(event.ID.String(), hashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registered emails: %w", err)
+	}
+	ticketTypes, err := s.repo.TicketTypeRepo.ListTicketTypesByEvent(event.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	participants := make([]models.Participant, len(rows))
+	picked := make([]*models.TicketType, len(rows))
+	for i, row := range rows {
+		email := strings.TrimSpace(row.Email)
+		result.Rows[i] = GroupRegistrationRow{Row: i + 1, Email: email}
+
+		name := strings.TrimSpace(row.Name)
+		phone := strings.TrimSpace(row.Phone)
+		switch addr, err := mail.ParseAddress(email); {
+		case name == "":
+			rowError(i, "name is required")
+		case err != nil || addr.Address != email:
+			rowError(i, "invalid email")
+		case phone == "":
+			rowError(i, "phone is required")
+		case registered[hashes[i]]:
+			rowError(i, "email already registered for this event")
+		}
+		registered[hashes[i]] = true
+
+		mealPreference, err := NormalizeMealPreference(row.MealPreference)
+		if err != nil {
+			rowError(i, err.Error())
+		}
+		ticketType, err := groupTicketType(ticketTypes, row.TicketTypeID)
+		if err != nil {
+			rowError(i, err.Error())
+		}
+		picked[i] = ticketType
+		answers, err := validateAnswers(event.FormFields, row.Answers)
+		if err != nil {
+			rowError(i, err.Error())
+		}
+
+		participants[i] = models.Participant{
+			ID:             uuid.New(),
+			EventID:        event.ID,
+			Name:           name,
+			Email:          email,
+			Phone:          phone,
+			Division:       strings.TrimSpace(row.Division),
+			Address:        strings.TrimSpace(row.Address),
+			MealPreference: mealPreference,
+			Answers:        answers,
+			PaymentStatus:  "paid",
+			ApprovalStatus: ApprovalApproved,
+		}
+		if ticketPrice(event, ticketType) > 0 {
+			participants[i].PaymentStatus = "pending"
+		}
+		if ticketType != nil {
+			participants[i].TicketTypeID = &ticketType.ID
+		}
+		if event.RequiresApproval {
+			participants[i].ApprovalStatus = ApprovalPending
+		}
+		requireConfirmation(event, &participants[i], time.Now())
+	}
+	if err := s.checkGroupQuotas(event, participants, picked, rowError); err != nil {
+		return nil, err
+	}
+	if rejected {
+		return result, ErrGroupRejected
+	}
+
+	limit, err := s.groupLimit(event, len(participants))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ParticipantRepo.CreateParticipantGroup(event.ID.String(), participants, limit); err != nil {
+		return nil, err
+	}
+
+	// Everyone is registered at this point; a participant whose QR code
+	// cannot be generated stays registered without one, as it can be
+	// generated again
+	for i := range participants {
+		result.Rows[i].ParticipantID = &participants[i].ID
+		waivers, err := s.ReleaseQR(&participants[i])
+		if err != nil {
+			result.Rows[i].Error = "registered, but failed to generate QR code"
+		}
+		result.Rows[i].QRPath, result.Rows[i].PendingWaivers = participants[i].QRPath, waivers
+	}
+	result.Registered = len(participants)
+	return result, nil
+}
+
+// groupTicketType checks a ticket type of a group row as pickTicketType
+// does; quotas are checked for the group as a whole
+func groupTicketType(ticketTypes []models.TicketType, ticketTypeID string) (*models.TicketType, error) {
+	if len(ticketTypes) == 0 {
+		if ticketTypeID != "" {
+			return nil, ErrUnknownTicketType
+		}
+		return nil, nil
+	}
+	if ticketTypeID == "" {
+		return nil, ErrTicketTypeRequired
+	}
+	for i := range ticketTypes {
+		if ticketTypes[i].ID.String() == ticketTypeID {
+			if !ticketTypeOnSale(&ticketTypes[i], time.Now()) {
+				return nil, ErrTicketTypeNotOnSale
+			}
+			return &ticketTypes[i], nil
+		}
+	}
+	return nil, ErrUnknownTicketType
+}
+
+// checkGroupQuotas refuses the rows of a group past what is left of the
+// ticket type quotas and quota rules they count against
+func (s *ParticipantService) checkGroupQuotas(event *models.Event, participants []models.Participant, ticketTypes []*models.TicketType, rowError func(int, string)) error {
+	sold, err := s.repo.ParticipantRepo.CountParticipantsByTicketType(event.ID.String())
+	if err != nil {
+		return errors.New("failed to check quota")
+	}
+	for i, ticketType := range ticketTypes {
+		if ticketType == nil || ticketType.Quota == nil {
+			continue
+		}
+		if sold[ticketType.ID.String()] >= int64(*ticketType.Quota) {
+			rowError(i, ErrTicketTypeSoldOut.Error())
+		}
+		sold[ticketType.ID.String()]++
+	}
+
+	rules, err := s.repo.QuotaRuleRepo.ListQuotaRulesByEvent(event.ID.String())
+	if err != nil {
+		return err
+	}
+	for r := range rules {
+		count, err := s.repo.QuotaRuleRepo.CountRuleRegistrations(&rules[r])
+		if err != nil {
+			return errors.New("failed to check quota")
+		}
+		for i := range participants {
+			if !quotaRuleApplies(&rules[r], ticketTypes[i], participants[i].Division) {
+				continue
+			}
+			if count >= int64(rules[r].MaxRegistrations) {
+				rowError(i, fmt.Sprintf("%s: %s", ErrQuotaRuleReached, rules[r].Name))
+			}
+			count++
+		}
+	}
+	return nil
+}
+
+// groupLimit returns how many participants the event may hold at most: its
+// ticket quota or the smallest capacity of its days. A group of size that
+// does not fit, or a single registration, is refused up front with the
+// reason; the limit is checked again as the participants are inserted.
+func (s *ParticipantService) groupLimit(event *models.Event, size int) (*int, error) {
+	count, err := s.repo.ParticipantRepo.GetParticipantCountByEventID(event.ID.String())
+	if err != nil {
+		return nil, errors.New("failed to check quota")
+	}
+	var limit *int
+	if event.TicketQuota != nil {
+		limit = event.TicketQuota
+		if left := *limit - int(count); size > left {
+			if size == 1 {
+				return nil, repositories.ErrTicketQuotaExceeded
+			}
+			if left < 0 {
+				left = 0
+			}
+			return nil, fmt.Errorf("%w: %d tickets left for a group of %d", repositories.ErrTicketQuotaExceeded, left, size)
+		}
+	}
+
+	days, err := s.repo.EventRepo.GetEventDaysByEventID(event.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	for _, day := range days {
+		if day.Capacity == nil {
+			continue
+		}
+		if int(count)+size > *day.Capacity {
+			return nil, fmt.Errorf("%w: %s", repositories.ErrEventDayFull, day.Label)
+		}
+		if limit == nil || *day.Capacity < *limit {
+			limit = day.Capacity
+		}
+	}
+	return limit, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/jwtkeys"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/repositories/memory"
+)
+
+// testEnv wires services to an in-memory store
+type testEnv struct {
+	store *memory.Store
+	repo  *repositories.Repository
+	fx    *memory.Fixtures
+	cfg   *config.Config
+	keys  *jwtkeys.Keyset
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+
+	cfg := &config.Config{
+		JWTAlgorithm:         jwtkeys.HS256,
+		JWTSecret:            "test-secret",
+		CredentialSigningKey: "test-credential-key",
+		QRDir:                t.TempDir(),
+		ImageDir:             t.TempDir(),
+		ImportDir:            t.TempDir(),
+		ImportBatchSize:      100,
+		ImportQRWorkers:      2,
+		ShiftGracePeriod:     15 * time.Minute,
+		AccessTokenTTL:       time.Hour,
+		RefreshTokenTTL:      30 * 24 * time.Hour,
+	}
+	keys, err := jwtkeys.Load(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := memory.New()
+	return &testEnv{
+		store: store,
+		repo:  store.Repository(),
+		fx:    store.Fixtures(),
+		cfg:   cfg,
+		keys:  keys,
+	}
+}
+
+func (e *testEnv) authService() *AuthService {
+	return NewAuthService(e.repo, e.keys, e.cfg)
+}
+
+func (e *testEnv) verificationService() VerificationService {
+	return NewVerificationService(
+		e.repo.ActionRepo,
+		e.repo.EventRepo,
+		e.repo.UserRepo,
+		e.repo.ParticipantRepo,
+		e.repo.SessionRepo,
+		e.repo.WaiverRepo,
+		e.repo.SeatingRepo,
+		e.repo.MealRepo,
+		e.repo.ShiftRepo,
+		e.repo.DeviceRepo,
+		e.cfg,
+	)
+}
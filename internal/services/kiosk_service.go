@@ -0,0 +1,145 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidKioskToken is returned for unknown or revoked kiosk tokens and
+// for tokens of inactive events
+var ErrInvalidKioskToken = errors.New("invalid kiosk token")
+
+// KioskService feeds lobby screens. Screens authenticate with a per-event
+// token instead of a user login, and only see first names and initials.
+type KioskService struct {
+	repo *repositories.Repository
+	cfg  *config.Config
+}
+
+func NewKioskService(repo *repositories.Repository, cfg *config.Config) *KioskService {
+	return &KioskService{repo: repo, cfg: cfg}
+}
+
+type KioskCheckIn struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"` // first name and last initial
+	Action     string    `json:"action"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+type KioskSnapshot struct {
+	EventTitle     string         `json:"event_title"`
+	WelcomeMessage string         `json:"welcome_message"`
+	Attendance     int64          `json:"attendance"`
+	RecentCheckIns []KioskCheckIn `json:"recent_check_ins"`
+}
+
+// IssueToken creates a new kiosk token for an event, replacing any previous
+// one, and sets the welcome message. The token is only returned here.
+func (s *KioskService) IssueToken(eventID, message string) (string, error) {
+	if _, err := s.repo.EventRepo.GetEventByID(eventID); err != nil {
+		return "", errors.New("event not found")
+	}
+
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate kiosk token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := s.repo.EventRepo.UpdateKioskSettings(eventID, hashKioskToken(token), strings.TrimSpace(message)); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// RevokeToken disables the kiosk display of an event
+func (s *KioskService) RevokeToken(eventID string) error {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return errors.New("event not found")
+	}
+	return s.repo.EventRepo.UpdateKioskSettings(eventID, "", event.KioskMessage)
+}
+
+// Snapshot returns the current display state of the event a token belongs to
+func (s *KioskService) Snapshot(token string) (*KioskSnapshot, error) {
+	if token == "" {
+		return nil, ErrInvalidKioskToken
+	}
+
+	event, err := s.repo.EventRepo.GetEventByKioskTokenHash(hashKioskToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidKioskToken
+		}
+		return nil, err
+	}
+
+	eventID := event.ID.String()
+	attendance, err := s.repo.ActionRepo.CountVerifiedParticipants(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count attendance: %w", err)
+	}
+
+	logs, err := s.repo.ActionRepo.GetActionLogsByEventAfter(eventID, nil, s.cfg.KioskRecentLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent check-ins: %w", err)
+	}
+
+	welcome := event.KioskMessage
+	if welcome == "" {
+		welcome = fmt.Sprintf("Welcome to %s", event.Title)
+	}
+
+	snapshot := &KioskSnapshot{
+		EventTitle:     event.Title,
+		WelcomeMessage: welcome,
+		Attendance:     attendance,
+		RecentCheckIns: make([]KioskCheckIn, 0, len(logs)),
+	}
+	for _, log := range logs {
+		snapshot.RecentCheckIns = append(snapshot.RecentCheckIns, KioskCheckIn{
+			ID:         log.ID.String(),
+			Name:       kioskDisplayName(log.Participant.Name),
+			Action:     log.Action.Name,
+			VerifiedAt: log.VerifiedAt,
+		})
+	}
+
+	return snapshot, nil
+}
+
+func hashKioskToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// kioskDisplayName shortens "Budi Santoso Wijaya" to "Budi W." so full names
+// are not shown on a public screen
+func kioskDisplayName(name string) string {
+	parts := strings.Fields(name)
+	switch len(parts) {
+	case 0:
+		return "Guest"
+	case 1:
+		return parts[0]
+	}
+
+	initial, _ := utf8.DecodeRuneInString(parts[len(parts)-1])
+	return fmt.Sprintf("%s %c.", parts[0], unicode.ToUpper(initial))
+}
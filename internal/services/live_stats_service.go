@@ -0,0 +1,156 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/repositories"
+)
+
+// ErrLiveStatsNotFound is returned for unknown or inactive events and for
+// events that have not opted in to public live stats
+var ErrLiveStatsNotFound = errors.New("live stats not found")
+
+// LiveStatsService serves the public live counters of events for their
+// websites and big screens. The page is unauthenticated and may be polled by
+// many screens at once, so stats are computed at most once per
+// LiveStatsCacheTTL and event.
+type LiveStatsService struct {
+	repo *repositories.Repository
+	cfg  *config.Config
+
+	mu      sync.Mutex
+	entries map[string]*liveStatsEntry // by slug
+}
+
+type liveStatsEntry struct {
+	// Held while the stats are computed so concurrent requests wait for one
+	// computation instead of each querying the database
+	mu        sync.Mutex
+	stats     *LiveStats
+	expiresAt time.Time
+}
+
+func NewLiveStatsService(repo *repositories.Repository, cfg *config.Config) *LiveStatsService {
+	return &LiveStatsService{repo: repo, cfg: cfg, entries: make(map[string]*liveStatsEntry)}
+}
+
+// LiveStats holds counters only, nothing about individual participants
+type LiveStats struct {
+	EventTitle         string            `json:"event_title"`
+	CheckedIn          int64             `json:"checked_in"`
+	SessionsInProgress int               `json:"sessions_in_progress"`
+	Sessions           []LiveSessionStat `json:"sessions"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+type LiveSessionStat struct {
+	Title     string    `json:"title"`
+	Room      string    `json:"room,omitempty"`
+	EndsAt    time.Time `json:"ends_at"`
+	CheckedIn int64     `json:"checked_in"`
+}
+
+// SetPublic opts an event in or out of the public live stats page
+func (s *LiveStatsService) SetPublic(eventID string, public bool) error {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return errors.New("event not found")
+	}
+	if err := s.repo.EventRepo.UpdateLiveStatsPublic(eventID, public); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.entries, event.Slug)
+	s.mu.Unlock()
+	return nil
+}
+
+// Get returns the live stats of an event, from cache when they are fresh
+func (s *LiveStatsService) Get(slug string) (*LiveStats, error) {
+	s.mu.Lock()
+	entry, ok := s.entries[slug]
+	if !ok {
+		entry = &liveStatsEntry{}
+		s.entries[slug] = entry
+	}
+	s.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	now := time.Now()
+	if entry.stats != nil && now.Before(entry.expiresAt) {
+		return entry.stats, nil
+	}
+
+	stats, err := s.compute(slug, now)
+	if err != nil {
+		// Unknown slugs are not kept, so the cache cannot be grown by
+		// requests for made-up events
+		entry.stats = nil
+		s.mu.Lock()
+		if s.entries[slug] == entry {
+			delete(s.entries, slug)
+		}
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	entry.stats = stats
+	entry.expiresAt = now.Add(s.cfg.LiveStatsCacheTTL)
+	return stats, nil
+}
+
+func (s *LiveStatsService) compute(slug string, now time.Time) (*LiveStats, error) {
+	event, err := s.repo.EventRepo.GetEventBySlug(slug)
+	if err != nil || !event.IsActive || !event.LiveStatsPublic {
+		return nil, ErrLiveStatsNotFound
+	}
+
+	eventID := event.ID.String()
+	checkedIn, err := s.repo.ActionRepo.CountVerifiedParticipants(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count attendance: %w", err)
+	}
+
+	sessions, err := s.repo.SessionRepo.ListSessionsByEvent(eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	stats := &LiveStats{
+		EventTitle: event.Title,
+		CheckedIn:  checkedIn,
+		Sessions:   make([]LiveSessionStat, 0),
+		UpdatedAt:  now,
+	}
+
+	var actionIDs []string
+	for _, session := range sessions {
+		if now.Before(session.StartsAt) || !now.Before(session.EndsAt) {
+			continue
+		}
+		stats.Sessions = append(stats.Sessions, LiveSessionStat{
+			Title:  session.Title,
+			Room:   session.Room,
+			EndsAt: session.EndsAt,
+		})
+		actionIDs = append(actionIDs, session.ActionID.String())
+	}
+	stats.SessionsInProgress = len(stats.Sessions)
+
+	counts, err := s.repo.ActionRepo.CountActionLogsByActionIDs(actionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count session check-ins: %w", err)
+	}
+	for i := range stats.Sessions {
+		stats.Sessions[i].CheckedIn = counts[actionIDs[i]]
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Meal preferences participants choose from at registration
+const (
+	MealRegular    = "regular"
+	MealVegetarian = "vegetarian"
+	MealVegan      = "vegan"
+	MealHalal      = "halal"
+	MealGlutenFree = "gluten_free"
+)
+
+// MealPreferences lists every meal preference, in report order
+var MealPreferences = []string{MealRegular, MealVegetarian, MealVegan, MealHalal, MealGlutenFree}
+
+// NormalizeMealPreference validates a meal preference, defaulting to regular
+func NormalizeMealPreference(preference string) (string, error) {
+	preference = strings.ToLower(strings.TrimSpace(preference))
+	if preference == "" {
+		return MealRegular, nil
+	}
+	for _, known := range MealPreferences {
+		if preference == known {
+			return preference, nil
+		}
+	}
+	return "", errors.New("invalid meal preference")
+}
+
+// MealService generates the meal coupons of an event and reports what
+// catering served
+type MealService struct {
+	repo *repositories.Repository
+	cfg  *config.Config
+}
+
+func NewMealService(repo *repositories.Repository, cfg *config.Config) *MealService {
+	return &MealService{repo: repo, cfg: cfg}
+}
+
+type CreateMealCouponsRequest struct {
+	Meal string
+	// Days to serve the meal on; every day of the event when empty
+	DayIDs []string
+	// Meals prepared per preference. Preferences left out get one meal per
+	// approved participant who chose them, and none when nobody did.
+	Quotas map[string]int
+}
+
+// MealCouponUsage is a row of the meal consumption report
+type MealCouponUsage struct {
+	CouponID   string `json:"coupon_id"`
+	Meal       string `json:"meal"`
+	DayLabel   string `json:"day_label"`
+	Preference string `json:"preference"`
+	ActionCode string `json:"action_code"`
+	Quota      *int   `json:"quota"`
+	Served     int64  `json:"served"`
+}
+
+// PreferenceUsage totals the meals of one preference
+type PreferenceUsage struct {
+	Preference string `json:"preference"`
+	// Approved participants who chose the preference
+	Participants int64 `json:"participants"`
+	Served       int64 `json:"served"`
+}
+
+type MealReport struct {
+	Coupons      []MealCouponUsage `json:"coupons"`
+	ByPreference []PreferenceUsage `json:"by_preference"`
+}
+
+// CreateMealCoupons creates one coupon per selected day and meal preference,
+// each scanned through its own action
+func (s *MealService) CreateMealCoupons(eventID string, req CreateMealCouponsRequest) ([]models.MealCoupon, error) {
+	event, err := s.repo.EventRepo.GetEventWithDays(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	meal := strings.TrimSpace(req.Meal)
+	if meal == "" {
+		return nil, errors.New("meal name is required")
+	}
+
+	days := event.EventDays
+	if len(req.DayIDs) > 0 {
+		byID := make(map[string]models.EventDay, len(event.EventDays))
+		for _, day := range event.EventDays {
+			byID[day.ID.String()] = day
+		}
+		days = make([]models.EventDay, 0, len(req.DayIDs))
+		for _, id := range req.DayIDs {
+			day, ok := byID[id]
+			if !ok {
+				return nil, errors.New("event day not found for this event: " + id)
+			}
+			days = append(days, day)
+		}
+	}
+	if len(days) == 0 {
+		return nil, errors.New("event has no days")
+	}
+
+	for preference, quota := range req.Quotas {
+		if known, err := NormalizeMealPreference(preference); err != nil || known != preference {
+			return nil, fmt.Errorf("invalid meal preference: %s", preference)
+		}
+		if quota <= 0 {
+			return nil, errors.New("quota must be greater than 0")
+		}
+	}
+
+	headcounts, err := s.repo.MealRepo.CountMealPreferences(eventID)
+	if err != nil {
+		return nil, err
+	}
+	quotas := make(map[string]int, len(MealPreferences))
+	for _, preference := range MealPreferences {
+		if quota, ok := req.Quotas[preference]; ok {
+			quotas[preference] = quota
+		} else if headcounts[preference] > 0 {
+			quotas[preference] = int(headcounts[preference])
+		}
+	}
+	if len(quotas) == 0 {
+		return nil, errors.New("no participant has chosen a meal preference yet; set quotas explicitly")
+	}
+
+	var coupons []models.MealCoupon
+	for _, day := range days {
+		for _, preference := range MealPreferences {
+			quota, ok := quotas[preference]
+			if !ok {
+				continue
+			}
+			code, err := mealActionCode()
+			if err != nil {
+				return nil, err
+			}
+			coupons = append(coupons, models.MealCoupon{
+				ID:         uuid.New(),
+				EventID:    event.ID,
+				EventDayID: day.ID,
+				Meal:       meal,
+				Preference: preference,
+				Quota:      &quota,
+				Action: models.EventAction{
+					EventID:    event.ID,
+					EventDayID: day.ID,
+					Name:       fmt.Sprintf("%s - %s (%s)", meal, day.Label, preference),
+					Code:       code,
+					IsActive:   true,
+				},
+			})
+		}
+	}
+
+	if err := s.repo.MealRepo.CreateMealCoupons(coupons); err != nil {
+		return nil, err
+	}
+	return coupons, nil
+}
+
+func (s *MealService) ListMealCoupons(eventID string) ([]models.MealCoupon, error) {
+	return s.repo.MealRepo.ListMealCouponsByEvent(eventID)
+}
+
+// MealReport returns the meals served per coupon and per preference
+func (s *MealService) MealReport(eventID string) (*MealReport, error) {
+	event, err := s.repo.EventRepo.GetEventWithDays(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+	labels := make(map[uuid.UUID]string, len(event.EventDays))
+	for _, day := range event.EventDays {
+		labels[day.ID] = day.Label
+	}
+
+	coupons, err := s.repo.MealRepo.ListMealCouponsByEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+	served, err := s.repo.MealRepo.CountMealRedemptions(eventID)
+	if err != nil {
+		return nil, err
+	}
+	headcounts, err := s.repo.MealRepo.CountMealPreferences(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MealReport{Coupons: make([]MealCouponUsage, 0, len(coupons))}
+	servedByPreference := make(map[string]int64, len(MealPreferences))
+	for _, coupon := range coupons {
+		report.Coupons = append(report.Coupons, MealCouponUsage{
+			CouponID:   coupon.ID.String(),
+			Meal:       coupon.Meal,
+			DayLabel:   labels[coupon.EventDayID],
+			Preference: coupon.Preference,
+			ActionCode: coupon.Action.Code,
+			Quota:      coupon.Quota,
+			Served:     served[coupon.ID],
+		})
+		servedByPreference[coupon.Preference] += served[coupon.ID]
+	}
+	for _, preference := range MealPreferences {
+		report.ByPreference = append(report.ByPreference, PreferenceUsage{
+			Preference:   preference,
+			Participants: headcounts[preference],
+			Served:       servedByPreference[preference],
+		})
+	}
+
+	return report, nil
+}
+
+func mealActionCode() (string, error) {
+	var buf [4]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", fmt.Errorf("failed to generate meal code: %w", err)
+	}
+	return "MEAL" + strings.ToUpper(hex.EncodeToString(buf[:])), nil
+}
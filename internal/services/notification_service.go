@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/mail"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/whatsapp"
+)
+
+// noticeBatchSize is how many notice recipients are loaded at a time
+const noticeBatchSize = 100
+
+// NotificationService emails participants about changes to their
+// registration and resends their tickets
+type NotificationService struct {
+	repo      *repositories.Repository
+	mailer    mail.Sender
+	messenger whatsapp.Sender
+	cfg       *config.Config
+	audit     *AuditService
+}
+
+func NewNotificationService(repo *repositories.Repository, mailer mail.Sender, messenger whatsapp.Sender, cfg *config.Config) *NotificationService {
+	return &NotificationService{repo: repo, mailer: mailer, messenger: messenger, cfg: cfg, audit: NewAuditService(repo, cfg)}
+}
+
+// SendCancellationNotices tells every cancelled participant of the event who
+// has not been told yet, and returns how many were emailed. Sending stops at
+// the first failure; participants already emailed are skipped when it is
+// retried.
+func (s *NotificationService) SendCancellationNotices(ctx context.Context, eventID string) (int, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return 0, errors.New("event not found")
+	}
+
+	sent := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+
+		participants, err := s.repo.ParticipantRepo.ListCancellationRecipients(eventID, noticeBatchSize)
+		if err != nil {
+			return sent, err
+		}
+		if len(participants) == 0 {
+			return sent, nil
+		}
+
+		for _, participant := range participants {
+			if err := s.mailer.Send(ctx, cancellationMessage(event, &participant)); err != nil {
+				return sent, err
+			}
+			if err := s.repo.ParticipantRepo.MarkCancellationNoticeSent(participant.ID.String(), time.Now()); err != nil {
+				return sent, err
+			}
+			sent++
+		}
+	}
+}
+
+func cancellationMessage(event *models.Event, participant *models.Participant) mail.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\n", participant.Name)
+	if event.Status == EventCancelled {
+		fmt.Fprintf(&body, "We are sorry to let you know that %s, planned for %s, has been cancelled.\n", event.Title, event.StartsAt.Format("Monday, 2 January 2006"))
+	} else {
+		fmt.Fprintf(&body, "Your registration for %s has been cancelled.\n", event.Title)
+	}
+	if participant.CancellationReason != "" {
+		fmt.Fprintf(&body, "\nReason: %s\n", participant.CancellationReason)
+	}
+	if participant.PaymentStatus == "refund_pending" {
+		body.WriteString("\nYour payment will be refunded to the method you paid with.\n")
+	}
+	body.WriteString("\nYour ticket and QR code are no longer valid.\n")
+
+	return mail.Message{
+		To:      participant.Email,
+		Subject: fmt.Sprintf("%s: registration cancelled", event.Title),
+		Body:    body.String(),
+	}
+}
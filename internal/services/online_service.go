@@ -0,0 +1,461 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/mail"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Event formats
+const (
+	EventInPerson = "in_person"
+	EventOnline   = "online"
+	EventHybrid   = "hybrid"
+)
+
+// Sources of attendance records
+const (
+	AttendanceScan   = "scan"
+	AttendanceOnline = "online"
+)
+
+// joinLinkBatchSize is how many join link recipients are loaded at a time
+const joinLinkBatchSize = 100
+
+var (
+	// ErrNotOnlineEvent is returned when meetings are managed on an in-person
+	// event
+	ErrNotOnlineEvent = errors.New("event is not online or hybrid")
+	// ErrMeetingNotFound is returned for unknown meetings and meetings of
+	// another event
+	ErrMeetingNotFound = errors.New("online meeting not found")
+	// ErrInvalidJoinToken is returned for forged join links and links of
+	// participants who may not attend
+	ErrInvalidJoinToken = errors.New("invalid join link")
+	// ErrNoMeeting is returned when an event has no meeting to join
+	ErrNoMeeting = errors.New("no online meeting is scheduled for this event")
+)
+
+// OnlineService runs the online part of online and hybrid events: meeting
+// links, the personal join links emailed to participants and the import of
+// webinar attendance reports into action logs.
+type OnlineService struct {
+	repo   *repositories.Repository
+	mailer mail.Sender
+	cfg    *config.Config
+}
+
+func NewOnlineService(repo *repositories.Repository, mailer mail.Sender, cfg *config.Config) *OnlineService {
+	return &OnlineService{repo: repo, mailer: mailer, cfg: cfg}
+}
+
+type CreateMeetingRequest struct {
+	ActionID   string
+	Provider   string
+	JoinURL    string
+	ExternalID string
+	MinMinutes int
+}
+
+// AttendanceRecord is one line of a webinar attendance report. Attendees are
+// matched by join token first, then by email.
+type AttendanceRecord struct {
+	JoinToken       string
+	Email           string
+	JoinedAt        time.Time
+	DurationMinutes int
+}
+
+type AttendanceImportResult struct {
+	Recorded        int `json:"recorded"`
+	AlreadyRecorded int `json:"already_recorded"`
+	// Participants who left before the meeting's minimum attendance
+	BelowMinimum int `json:"below_minimum"`
+	// Report lines that match no participant of the event
+	Unmatched int `json:"unmatched"`
+}
+
+// AttendanceSummary counts the distinct participants of an event by how
+// they attended
+type AttendanceSummary struct {
+	InPerson int64 `json:"in_person"`
+	Online   int64 `json:"online"`
+	Both     int64 `json:"both"`
+	Total    int64 `json:"total"`
+}
+
+// CreateMeeting attaches a video call to an action of an online or hybrid
+// event. On hybrid events, using the action scanned at the venue counts both
+// audiences under the same action.
+func (s *OnlineService) CreateMeeting(eventID, createdBy string, req CreateMeetingRequest) (*models.OnlineMeeting, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+	if event.Format != EventOnline && event.Format != EventHybrid {
+		return nil, ErrNotOnlineEvent
+	}
+
+	action, err := s.repo.EventRepo.GetEventActionByID(req.ActionID)
+	if err != nil || action.EventID != event.ID {
+		return nil, errors.New("action not found for this event")
+	}
+
+	meetings, err := s.repo.OnlineRepo.ListMeetingsByEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range meetings {
+		if existing.ActionID == action.ID {
+			return nil, errors.New("action already has an online meeting")
+		}
+	}
+	externalID := strings.TrimSpace(req.ExternalID)
+	if externalID != "" {
+		if _, err := s.repo.OnlineRepo.GetMeetingByExternalID(externalID); err == nil {
+			return nil, errors.New("another meeting uses this external ID")
+		}
+	}
+
+	meeting := &models.OnlineMeeting{
+		ID:         uuid.New(),
+		EventID:    event.ID,
+		ActionID:   action.ID,
+		Provider:   req.Provider,
+		JoinURL:    strings.TrimSpace(req.JoinURL),
+		ExternalID: externalID,
+		MinMinutes: req.MinMinutes,
+		CreatedBy:  uuid.MustParse(createdBy),
+	}
+	if err := s.repo.OnlineRepo.CreateMeeting(meeting); err != nil {
+		return nil, fmt.Errorf("failed to create online meeting: %w", err)
+	}
+	meeting.Action = *action
+	return meeting, nil
+}
+
+func (s *OnlineService) ListMeetings(eventID string) ([]models.OnlineMeeting, error) {
+	return s.repo.OnlineRepo.ListMeetingsByEvent(eventID)
+}
+
+func (s *OnlineService) DeleteMeeting(eventID, meetingID string) error {
+	if _, err := s.meetingOf(eventID, meetingID); err != nil {
+		return err
+	}
+	return s.repo.OnlineRepo.DeleteMeeting(meetingID)
+}
+
+// JoinLinksDueAt returns when the join links of an online or hybrid event
+// are emailed
+func (s *OnlineService) JoinLinksDueAt(eventID string) (time.Time, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return time.Time{}, errors.New("event not found")
+	}
+	if event.Format != EventOnline && event.Format != EventHybrid {
+		return time.Time{}, ErrNotOnlineEvent
+	}
+	return event.StartsAt.Add(-s.cfg.OnlineLinkLeadTime), nil
+}
+
+// JoinURL returns the personal join link of a participant
+func (s *OnlineService) JoinURL(participantID string) string {
+	return fmt.Sprintf("%s/api/v2/online/join/%s", strings.TrimRight(s.cfg.PublicBaseURL, "/"), s.joinToken(participantID))
+}
+
+func (s *OnlineService) joinToken(participantID string) string {
+	mac := hmac.New(sha256.New, []byte("online-join:"+s.cfg.CredentialSigningKey))
+	mac.Write([]byte(participantID))
+	return participantID + "." + hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// participantOfToken returns the participant ID of a join token, or false
+// when the token was not issued by this server
+func (s *OnlineService) participantOfToken(token string) (string, bool) {
+	participantID, _, found := strings.Cut(token, ".")
+	if !found {
+		return "", false
+	}
+	if _, err := uuid.Parse(participantID); err != nil {
+		return "", false
+	}
+	return participantID, hmac.Equal([]byte(token), []byte(s.joinToken(participantID)))
+}
+
+// SendJoinLinks emails their join link to every participant of the event
+// who may attend and has not received it yet, and returns how many were
+// sent. Sending stops at the first failure; participants already emailed are
+// skipped when it is retried.
+func (s *OnlineService) SendJoinLinks(ctx context.Context, eventID string) (int, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return 0, errors.New("event not found")
+	}
+	if event.Format != EventOnline && event.Format != EventHybrid {
+		return 0, ErrNotOnlineEvent
+	}
+
+	sent := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+
+		participants, err := s.repo.OnlineRepo.ListJoinLinkRecipients(eventID, event.TicketPrice > 0, joinLinkBatchSize)
+		if err != nil {
+			return sent, err
+		}
+		if len(participants) == 0 {
+			return sent, nil
+		}
+
+		for _, participant := range participants {
+			if err := s.mailer.Send(ctx, s.joinLinkMessage(event, &participant)); err != nil {
+				return sent, err
+			}
+			if err := s.repo.OnlineRepo.MarkJoinLinkSent(participant.ID.String(), time.Now()); err != nil {
+				return sent, err
+			}
+			sent++
+		}
+	}
+}
+
+func (s *OnlineService) joinLinkMessage(event *models.Event, participant *models.Participant) mail.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\n", participant.Name)
+	fmt.Fprintf(&body, "%s starts on %s. Join online with your personal link:\n\n", event.Title, event.StartsAt.Format("Monday, 2 January 2006 15:04 MST"))
+	fmt.Fprintf(&body, "%s\n\n", s.JoinURL(participant.ID.String()))
+	body.WriteString("The link is tied to your registration, please do not share it.\n")
+
+	return mail.Message{
+		To:      participant.Email,
+		Subject: fmt.Sprintf("Your join link for %s", event.Title),
+		Body:    body.String(),
+	}
+}
+
+// ResolveJoinLink returns the meeting URL a join token leads to: the meeting
+// held today, else the next one, else the last one
+func (s *OnlineService) ResolveJoinLink(token string) (string, error) {
+	participantID, ok := s.participantOfToken(token)
+	if !ok {
+		return "", ErrInvalidJoinToken
+	}
+
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return "", ErrInvalidJoinToken
+	}
+	event, err := s.repo.EventRepo.GetEventByID(participant.EventID.String())
+	if err != nil || !event.IsActive {
+		return "", ErrInvalidJoinToken
+	}
+	if participant.CancelledAt != nil || participant.ApprovalStatus != ApprovalApproved || (event.TicketPrice > 0 && !paymentSettled(participant)) {
+		return "", ErrInvalidJoinToken
+	}
+
+	meetings, err := s.repo.OnlineRepo.ListMeetingsByEvent(event.ID.String())
+	if err != nil {
+		return "", err
+	}
+	if len(meetings) == 0 {
+		return "", ErrNoMeeting
+	}
+
+	days, err := s.repo.EventRepo.GetEventDaysByEventID(event.ID.String())
+	if err != nil {
+		return "", err
+	}
+	dates := make(map[uuid.UUID]time.Time, len(days))
+	for _, day := range days {
+		dates[day.ID] = day.Date
+	}
+
+	return pickMeeting(meetings, dates, time.Now()).JoinURL, nil
+}
+
+// pickMeeting returns the first meeting held on the day of now, else the
+// earliest upcoming one, else the latest past one
+func pickMeeting(meetings []models.OnlineMeeting, dates map[uuid.UUID]time.Time, now time.Time) *models.OnlineMeeting {
+	var next, last *models.OnlineMeeting
+	for i := range meetings {
+		meeting := &meetings[i]
+		date := dates[meeting.Action.EventDayID]
+		y1, m1, d1 := date.Date()
+		y2, m2, d2 := now.In(date.Location()).Date()
+		switch {
+		case y1 == y2 && m1 == m2 && d1 == d2:
+			return meeting
+		case date.After(now):
+			if next == nil || date.Before(dates[next.Action.EventDayID]) {
+				next = meeting
+			}
+		default:
+			if last == nil || date.After(dates[last.Action.EventDayID]) {
+				last = meeting
+			}
+		}
+	}
+	if next != nil {
+		return next
+	}
+	return last
+}
+
+// VerifyWebhookSignature checks the "sha256=<hex>" HMAC of an attendance
+// webhook body. Webhooks are refused while ONLINE_WEBHOOK_SECRET is unset.
+func (s *OnlineService) VerifyWebhookSignature(body []byte, signature string) bool {
+	if s.cfg.OnlineWebhookSecret == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(s.cfg.OnlineWebhookSecret))
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(signature), []byte(expected))
+}
+
+// IngestWebhook records the attendance report of the meeting with the given
+// provider ID, in the name of the organizer who set up the meeting
+func (s *OnlineService) IngestWebhook(externalID string, records []AttendanceRecord) (*AttendanceImportResult, error) {
+	if externalID == "" {
+		return nil, ErrMeetingNotFound
+	}
+	meeting, err := s.repo.OnlineRepo.GetMeetingByExternalID(externalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMeetingNotFound
+		}
+		return nil, err
+	}
+	return s.ingest(meeting, meeting.CreatedBy, records)
+}
+
+// ImportAttendance records an attendance report uploaded by an organizer
+func (s *OnlineService) ImportAttendance(eventID, meetingID, userID string, records []AttendanceRecord) (*AttendanceImportResult, error) {
+	meeting, err := s.meetingOf(eventID, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	return s.ingest(meeting, uuid.MustParse(userID), records)
+}
+
+func (s *OnlineService) ingest(meeting *models.OnlineMeeting, recordedBy uuid.UUID, records []AttendanceRecord) (*AttendanceImportResult, error) {
+	result := &AttendanceImportResult{}
+
+	// Attendees who reconnect appear once per connection
+	type attendance struct {
+		minutes  int
+		joinedAt time.Time
+	}
+	attended := make(map[uuid.UUID]*attendance)
+	var order []uuid.UUID
+	for _, record := range records {
+		participantID, ok := s.matchAttendee(meeting.EventID, record)
+		if !ok {
+			result.Unmatched++
+			continue
+		}
+
+		entry, seen := attended[participantID]
+		if !seen {
+			entry = &attendance{joinedAt: record.JoinedAt}
+			attended[participantID] = entry
+			order = append(order, participantID)
+		}
+		entry.minutes += record.DurationMinutes
+		if !record.JoinedAt.IsZero() && (entry.joinedAt.IsZero() || record.JoinedAt.Before(entry.joinedAt)) {
+			entry.joinedAt = record.JoinedAt
+		}
+	}
+
+	logs := make([]models.ActionLog, 0, len(order))
+	for _, participantID := range order {
+		entry := attended[participantID]
+		if entry.minutes < meeting.MinMinutes {
+			result.BelowMinimum++
+			continue
+		}
+		verifiedAt := entry.joinedAt
+		if verifiedAt.IsZero() {
+			verifiedAt = time.Now()
+		}
+		logs = append(logs, models.ActionLog{
+			ID:            uuid.New(),
+			ParticipantID: participantID,
+			ActionID:      meeting.ActionID,
+			VerifiedBy:    &recordedBy,
+			VerifiedAt:    verifiedAt,
+			Source:        AttendanceOnline,
+			CreatedAt:     time.Now(),
+		})
+	}
+
+	created, err := s.repo.OnlineRepo.RecordAttendance(logs)
+	if err != nil {
+		return nil, err
+	}
+	result.Recorded = created
+	result.AlreadyRecorded = len(logs) - created
+	return result, nil
+}
+
+// matchAttendee returns the participant of an event a report line is about
+func (s *OnlineService) matchAttendee(eventID uuid.UUID, record AttendanceRecord) (uuid.UUID, bool) {
+	if record.JoinToken != "" {
+		if participantID, ok := s.participantOfToken(record.JoinToken); ok {
+			participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+			if err == nil && participant.EventID == eventID {
+				return participant.ID, true
+			}
+		}
+	}
+
+	if strings.TrimSpace(record.Email) != "" {
+		participant, err := s.repo.ParticipantRepo.GetParticipantByEmailAndEvent(record.Email, eventID.String())
+		if err == nil {
+			return participant.ID, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+// AttendanceSummary counts on-site and online attendance of an event
+func (s *OnlineService) AttendanceSummary(eventID string) (*AttendanceSummary, error) {
+	if _, err := s.repo.EventRepo.GetEventByID(eventID); err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	counts, err := s.repo.OnlineRepo.CountAttendance(eventID)
+	if err != nil {
+		return nil, err
+	}
+	return &AttendanceSummary{
+		InPerson: counts.InPerson,
+		Online:   counts.Online,
+		Both:     counts.InPerson + counts.Online - counts.Total,
+		Total:    counts.Total,
+	}, nil
+}
+
+// meetingOf returns a meeting of the given event
+func (s *OnlineService) meetingOf(eventID, meetingID string) (*models.OnlineMeeting, error) {
+	meeting, err := s.repo.OnlineRepo.GetMeetingByID(meetingID)
+	if err != nil || meeting.EventID.String() != eventID {
+		return nil, ErrMeetingNotFound
+	}
+	return meeting, nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"crypto/hmac"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// CancelRegistration cancels a registration on behalf of staff; see
+// cancelRegistration
+func (s *ParticipantService) CancelRegistration(actorID, participantID, reason string) (*models.Participant, error) {
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return nil, ErrUnknownParticipant
+	}
+	return s.cancelRegistration(actorID, participant, reason)
+}
+
+// CancelOwnRegistration cancels a registration on behalf of the participant,
+// who proves it is theirs with the ticket code printed on their ticket. A
+// missing or wrong code fails with ErrTicketCodeMismatch.
+func (s *ParticipantService) CancelOwnRegistration(participantID, code, reason string) (*models.Participant, error) {
+	id, err := uuid.Parse(participantID)
+	if err != nil {
+		return nil, ErrUnknownParticipant
+	}
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil || participant.AnonymizedAt != nil {
+		return nil, ErrUnknownParticipant
+	}
+	given := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	expected := strings.Replace(TicketCode(s.cfg.CredentialSigningKey, id), "-", "", 1)
+	if !hmac.Equal([]byte(given), []byte(expected)) {
+		return nil, ErrTicketCodeMismatch
+	}
+	return s.cancelRegistration("", participant, reason)
+}
+
+// cancelRegistration marks a registration cancelled, so it no longer counts
+// against the ticket quota and its QR code is refused at every scan. The QR
+// image is deleted; refunds are left to organizers. Registrations cancelled
+// already fail with ErrTicketCancelled. The caller promotes the
+// event's waitlist into the freed ticket.
+func (s *ParticipantService) cancelRegistration(actorID string, participant *models.Participant, reason string) (*models.Participant, error) {
+	if participant.CancelledAt != nil {
+		return nil, ErrTicketCancelled
+	}
+
+	now := time.Now()
+	qrPath := participant.QRPath
+	participant.CancelledAt = &now
+	participant.CancellationReason = strings.TrimSpace(reason)
+	// Nobody needs telling: the participant cancelled or staff did it with
+	// them, and a later cancellation of the event must not notify them again
+	participant.CancellationNoticeSentAt = &now
+	participant.QRPath = ""
+	if err := s.repo.ParticipantRepo.UpdateParticipant(participant); err != nil {
+		return nil, err
+	}
+
+	if qrPath != "" {
+		if err := os.Remove(filepath.Join(s.cfg.QRDir, filepath.Base(qrPath))); err != nil && !os.IsNotExist(err) {
+			logger.Log.WithError(err).WithField("participant_id", participant.ID.String()).Warn("failed to remove QR code of cancelled registration")
+		}
+	}
+	s.audit.Record(actorID, AuditRegistrationCancelled, AuditEntityParticipant, participant.ID.String(), map[string]interface{}{
+		"reason":       participant.CancellationReason,
+		"self_service": actorID == "",
+	})
+	return participant, nil
+}
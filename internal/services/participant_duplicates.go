@@ -0,0 +1,265 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownParticipant = errors.New("participant not found")
+	// ErrMergeSelf is returned when a participant is merged into itself
+	ErrMergeSelf = errors.New("a participant cannot be merged into itself")
+	// ErrMergeOtherEvent is returned when the participants of a merge are
+	// registered for different events
+	ErrMergeOtherEvent = errors.New("participants are registered for different events")
+	// ErrMergeCancelled is returned when the participant to keep has
+	// cancelled; merge the other way around instead
+	ErrMergeCancelled = errors.New("a cancelled registration cannot be kept")
+)
+
+// DuplicateGroup is a set of registrations of one event that look like the
+// same person
+type DuplicateGroup struct {
+	// What the registrations share: email, phone or both
+	MatchedOn []string `json:"matched_on"`
+	// Oldest registration first
+	Participants []models.Participant `json:"participants"`
+}
+
+// MergeResult is the outcome of merging a duplicate registration
+type MergeResult struct {
+	Participant *models.Participant       `json:"participant"`
+	MergedID    uuid.UUID                 `json:"merged_id"`
+	Counts      *repositories.MergeCounts `json:"counts"`
+}
+
+// FindDuplicateParticipants groups the registrations of an event that share
+// an email address or phone number once normalized: case, +tags and the dots
+// of Gmail addresses are ignored, as is the notation of phone numbers.
+// Registrations are linked transitively, so a group may hold one person's
+// registrations sharing an email with one and a phone number with another.
+// Erased participants are left out.
+func (s *ParticipantService) FindDuplicateParticipants(eventID string) ([]DuplicateGroup, error) {
+	if _, err := s.repo.EventRepo.GetEventByID(eventID); err != nil {
+		return nil, ErrUnknownEvent
+	}
+
+	var participants []models.Participant
+	var cursor *repositories.Cursor
+	for {
+		batch, err := s.repo.ParticipantRepo.ListParticipantsByEventAfter(eventID, cursor, exportBatchSize, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, participant := range batch {
+			if participant.AnonymizedAt == nil {
+				participants = append(participants, participant)
+			}
+		}
+		if len(batch) < exportBatchSize {
+			break
+		}
+		last := batch[len(batch)-1]
+		cursor = &repositories.Cursor{Time: last.CreatedAt, ID: last.ID}
+	}
+	sort.Slice(participants, func(i, j int) bool {
+		return !newerParticipant(&participants[i], &participants[j])
+	})
+
+	// Union-find over participant indexes, linked by shared keys
+	parent := make([]int, len(participants))
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	firstWith := map[string]int{}
+	link := func(i int, key string) {
+		if first, ok := firstWith[key]; ok {
+			parent[find(i)] = find(first)
+		} else {
+			firstWith[key] = i
+		}
+	}
+	for i := range participants {
+		if key := duplicateEmailKey(participants[i].Email); key != "" {
+			link(i, "email:"+key)
+		}
+		if key := duplicatePhoneKey(participants[i].Phone); key != "" {
+			link(i, "phone:"+key)
+		}
+	}
+
+	members := map[int][]int{}
+	var roots []int
+	for i := range participants {
+		root := find(i)
+		if members[root] == nil {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], i)
+	}
+
+	groups := []DuplicateGroup{}
+	for _, root := range roots {
+		if len(members[root]) < 2 {
+			continue
+		}
+		group := DuplicateGroup{MatchedOn: []string{}}
+		emails, phones := map[string]int{}, map[string]int{}
+		for _, i := range members[root] {
+			group.Participants = append(group.Participants, participants[i])
+			if key := duplicateEmailKey(participants[i].Email); key != "" {
+				emails[key]++
+			}
+			if key := duplicatePhoneKey(participants[i].Phone); key != "" {
+				phones[key]++
+			}
+		}
+		if sharesKey(emails) {
+			group.MatchedOn = append(group.MatchedOn, "email")
+		}
+		if sharesKey(phones) {
+			group.MatchedOn = append(group.MatchedOn, "phone")
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func newerParticipant(a, b *models.Participant) bool {
+	if a.CreatedAt.Equal(b.CreatedAt) {
+		return a.ID.String() > b.ID.String()
+	}
+	return a.CreatedAt.After(b.CreatedAt)
+}
+
+func sharesKey(counts map[string]int) bool {
+	for _, n := range counts {
+		if n > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// duplicateEmailKey normalizes an email address for duplicate detection
+func duplicateEmailKey(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return ""
+	}
+	local, domain := email[:at], email[at+1:]
+	if i := strings.Index(local, "+"); i > 0 {
+		local = local[:i]
+	}
+	if domain == "gmail.com" || domain == "googlemail.com" {
+		local, domain = strings.ReplaceAll(local, ".", ""), "gmail.com"
+	}
+	return local + "@" + domain
+}
+
+// duplicatePhoneKey keeps the last nine digits of a phone number, so local
+// and international notations of a number match. Numbers too short to tell
+// people apart are ignored.
+func duplicatePhoneKey(phone string) string {
+	digits := models.PhoneDigits(phone)
+	if len(digits) < 7 {
+		return ""
+	}
+	if len(digits) > 9 {
+		digits = digits[len(digits)-9:]
+	}
+	return digits
+}
+
+// MergeParticipants folds a duplicate registration into participantID, which
+// is kept. Contact details the kept participant lacks are taken from the
+// duplicate, as are a paid status and an approval. The scans, leads, waiver
+// signatures, seats, credential incidents and draw wins of the duplicate move
+// over, except where the kept participant already has their counterpart.
+// The duplicate is deleted, so its QR code is refused at the gates, while the
+// kept QR code stays valid; one is generated if the merge released it.
+func (s *ParticipantService) MergeParticipants(actorID, participantID, duplicateID string) (*MergeResult, error) {
+	survivor, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return nil, ErrUnknownParticipant
+	}
+	duplicate, err := s.repo.ParticipantRepo.GetParticipantByID(duplicateID)
+	if err != nil {
+		return nil, ErrUnknownParticipant
+	}
+	if survivor.ID == duplicate.ID {
+		return nil, ErrMergeSelf
+	}
+	if survivor.EventID != duplicate.EventID {
+		return nil, ErrMergeOtherEvent
+	}
+	if survivor.CancelledAt != nil {
+		return nil, ErrMergeCancelled
+	}
+
+	if survivor.Phone == "" {
+		survivor.Phone = duplicate.Phone
+	}
+	if survivor.Division == "" {
+		survivor.Division = duplicate.Division
+	}
+	if survivor.Address == "" {
+		survivor.Address = duplicate.Address
+	}
+	if survivor.TicketTypeID == nil {
+		survivor.TicketTypeID = duplicate.TicketTypeID
+	}
+	if duplicate.PaymentStatus == "paid" {
+		survivor.PaymentStatus = "paid"
+	}
+	survivor.Tags = models.MergeTags(survivor.Tags, duplicate.Tags, nil)
+	if survivor.ApprovalStatus != ApprovalApproved && duplicate.ApprovalStatus == ApprovalApproved {
+		survivor.ApprovalStatus, survivor.RejectionReason = ApprovalApproved, ""
+		survivor.ReviewedBy, survivor.ReviewedAt = duplicate.ReviewedBy, duplicate.ReviewedAt
+	}
+
+	counts, err := s.repo.ParticipantRepo.MergeParticipants(survivor, duplicate)
+	if err != nil {
+		return nil, err
+	}
+	log := logger.Log.WithField("participant_id", survivor.ID.String())
+
+	// The deleted duplicate's QR code no longer resolves; failing to delete
+	// its image is harmless
+	if duplicate.QRPath != "" {
+		os.Remove(filepath.Join(s.cfg.QRDir, filepath.Base(duplicate.QRPath)))
+	}
+	// Moved waiver signatures or approval may release the kept QR code
+	if _, err := s.ReleaseQR(survivor); err != nil {
+		log.WithError(err).Warn("failed to release QR code after merge")
+	}
+
+	s.audit.Record(actorID, AuditParticipantMerged, AuditEntityParticipant, survivor.ID.String(), map[string]interface{}{
+		"merged_id":           duplicate.ID.String(),
+		"action_logs_moved":   counts.ActionLogsMoved,
+		"action_logs_dropped": counts.ActionLogsDropped,
+	})
+
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(survivor.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	return &MergeResult{Participant: participant, MergedID: duplicate.ID, Counts: counts}, nil
+}
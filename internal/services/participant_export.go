@@ -0,0 +1,273 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/xlsx"
+
+	"github.com/google/uuid"
+)
+
+// exportBatchSize is how many participants or action logs are loaded at a
+// time while writing an export
+const exportBatchSize = 500
+
+var participantExportHeader = []string{
+	"id", "name", "email", "phone", "division", "payment_status", "approval_status",
+	"qr_path", "verifications", "registered_at", "cancelled_at", "tags",
+}
+
+var attendanceExportHeader = []string{"id", "name", "email", "division", "actions", "first_scan", "last_scan"}
+
+// ParticipantExport writes the participants of one event as CSV or XLSX;
+// see ExportParticipants
+type ParticipantExport struct {
+	repo    *repositories.Repository
+	eventID string
+	filters *repositories.ParticipantFilters
+	Event   *models.Event
+}
+
+// ExportParticipants prepares the export of the participants of an event,
+// narrowed by the optional listing filters. Participants are read in batches
+// while the export is written, so events of any size can be exported.
+func (s *ParticipantService) ExportParticipants(eventID string, filters *repositories.ParticipantFilters) (*ParticipantExport, error) {
+	if err := validateParticipantFilters(filters); err != nil {
+		return nil, err
+	}
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+	if err := s.validateAnswerFilters(eventID, filters); err != nil {
+		return nil, err
+	}
+	return &ParticipantExport{repo: s.repo, eventID: eventID, filters: filters, Event: event}, nil
+}
+
+// Write writes the export as CSV, newest registrations first. A failure part
+// way leaves the output truncated.
+func (e *ParticipantExport) Write(out io.Writer) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(e.header()); err != nil {
+		return err
+	}
+	return e.eachBatch(func(participants []models.Participant, verifications map[string]int64) error {
+		for i := range participants {
+			if err := w.Write(e.row(&participants[i], verifications)); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		return w.Error()
+	})
+}
+
+// attendee is a participant on a day sheet of the XLSX export
+type attendee struct {
+	participant *models.Participant
+	// Names of the actions scanned, newest first
+	actions         []string
+	first, lastScan time.Time
+}
+
+// WriteXLSX writes the export as an XLSX workbook: a Participants sheet with
+// the CSV columns, then a sheet per event day listing who attended it, the
+// actions they were scanned at and their first and last scans. Exported
+// participants are kept in memory to fill the day sheets.
+func (e *ParticipantExport) WriteXLSX(out io.Writer) error {
+	days, err := e.repo.EventRepo.GetEventDaysByEventID(e.eventID)
+	if err != nil {
+		return err
+	}
+
+	w := xlsx.NewWriter(out)
+	if err := w.AddSheet("Participants", e.header()); err != nil {
+		return err
+	}
+	exported := map[uuid.UUID]*models.Participant{}
+	err = e.eachBatch(func(participants []models.Participant, verifications map[string]int64) error {
+		for i := range participants {
+			if err := w.WriteRow(e.row(&participants[i], verifications)); err != nil {
+				return err
+			}
+			exported[participants[i].ID] = &participants[i]
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	attendance, err := e.attendance(days, exported)
+	if err != nil {
+		return err
+	}
+	for i, day := range days {
+		name := fmt.Sprintf("Day %d", day.DayNumber)
+		if day.Label != "" && day.Label != name {
+			name += " - " + day.Label
+		}
+		if err := w.AddSheet(name, attendanceExportHeader); err != nil {
+			return err
+		}
+		for _, a := range attendance[i] {
+			actions := make([]string, len(a.actions))
+			for j, action := range a.actions {
+				actions[len(actions)-1-j] = action
+			}
+			if err := w.WriteRow([]string{
+				a.participant.ID.String(),
+				a.participant.Name,
+				a.participant.Email,
+				a.participant.Division,
+				strings.Join(actions, ", "),
+				a.first.UTC().Format(time.RFC3339),
+				a.lastScan.UTC().Format(time.RFC3339),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return w.Close()
+}
+
+// attendance groups the scans of the exported participants by event day,
+// earliest arrival first
+func (e *ParticipantExport) attendance(days []models.EventDay, exported map[uuid.UUID]*models.Participant) ([][]*attendee, error) {
+	actionDays := map[uuid.UUID]int{}
+	actionNames := map[uuid.UUID]string{}
+	for i := range days {
+		for _, action := range days[i].EventActions {
+			actionDays[action.ID], actionNames[action.ID] = i, action.Name
+		}
+	}
+
+	byDay := make([]map[uuid.UUID]*attendee, len(days))
+	for i := range byDay {
+		byDay[i] = map[uuid.UUID]*attendee{}
+	}
+	var cursor *repositories.Cursor
+	for {
+		logs, err := e.repo.ActionRepo.GetActionLogsByEventAfter(e.eventID, cursor, exportBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range logs {
+			day, ok := actionDays[log.ActionID]
+			participant := exported[log.ParticipantID]
+			if !ok || participant == nil {
+				continue
+			}
+			a := byDay[day][log.ParticipantID]
+			if a == nil {
+				a = &attendee{participant: participant, first: log.VerifiedAt, lastScan: log.VerifiedAt}
+				byDay[day][log.ParticipantID] = a
+			}
+			a.actions = append(a.actions, actionNames[log.ActionID])
+			if log.VerifiedAt.Before(a.first) {
+				a.first = log.VerifiedAt
+			}
+			if log.VerifiedAt.After(a.lastScan) {
+				a.lastScan = log.VerifiedAt
+			}
+		}
+		if len(logs) < exportBatchSize {
+			break
+		}
+		last := logs[len(logs)-1]
+		cursor = &repositories.Cursor{Time: last.VerifiedAt, ID: last.ID}
+	}
+
+	attendance := make([][]*attendee, len(days))
+	for i := range byDay {
+		for _, a := range byDay[i] {
+			attendance[i] = append(attendance[i], a)
+		}
+		sort.Slice(attendance[i], func(j, k int) bool {
+			a, b := attendance[i][j], attendance[i][k]
+			if !a.first.Equal(b.first) {
+				return a.first.Before(b.first)
+			}
+			return a.participant.ID.String() < b.participant.ID.String()
+		})
+	}
+	return attendance, nil
+}
+
+// eachBatch calls fn with every batch of exported participants, newest
+// registrations first, and their verifications counted by participant ID
+func (e *ParticipantExport) eachBatch(fn func([]models.Participant, map[string]int64) error) error {
+	var cursor *repositories.Cursor
+	for {
+		participants, err := e.repo.ParticipantRepo.ListParticipantsByEventAfter(e.eventID, cursor, exportBatchSize, e.filters)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, len(participants))
+		for i := range participants {
+			ids[i] = participants[i].ID.String()
+		}
+		verifications, err := e.repo.ActionRepo.CountActionLogsByParticipants(ids)
+		if err != nil {
+			return err
+		}
+		if err := fn(participants, verifications); err != nil {
+			return err
+		}
+
+		if len(participants) < exportBatchSize {
+			return nil
+		}
+		last := participants[len(participants)-1]
+		cursor = &repositories.Cursor{Time: last.CreatedAt, ID: last.ID}
+	}
+}
+
+// header is participantExportHeader followed by a column per form field of
+// the event
+func (e *ParticipantExport) header() []string {
+	header := append([]string{}, participantExportHeader...)
+	for _, field := range e.Event.FormFields {
+		header = append(header, "answers."+field.Key)
+	}
+	return header
+}
+
+func (e *ParticipantExport) row(participant *models.Participant, verifications map[string]int64) []string {
+	row := participantExportRow(participant, verifications)
+	for _, field := range e.Event.FormFields {
+		row = append(row, models.AnswerText(participant.Answers[field.Key]))
+	}
+	return row
+}
+
+func participantExportRow(participant *models.Participant, verifications map[string]int64) []string {
+	cancelledAt := ""
+	if participant.CancelledAt != nil {
+		cancelledAt = participant.CancelledAt.UTC().Format(time.RFC3339)
+	}
+	return []string{
+		participant.ID.String(),
+		participant.Name,
+		participant.Email,
+		participant.Phone,
+		participant.Division,
+		participant.PaymentStatus,
+		participant.ApprovalStatus,
+		participant.QRPath,
+		strconv.FormatInt(verifications[participant.ID.String()], 10),
+		participant.CreatedAt.UTC().Format(time.RFC3339),
+		cancelledAt,
+		strings.Join(participant.Tags, ", "),
+	}
+}
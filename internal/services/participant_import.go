@@ -0,0 +1,496 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/xlsx"
+	"event-management-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Formats of participant import files
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatXLSX = "xlsx"
+)
+
+// importChunkSize is how many rows an import job registers between two
+// progress updates
+const importChunkSize = 500
+
+var (
+	ErrUnknownImportJob = errors.New("import job not found")
+	// ErrInvalidColumnMapping is returned for column mappings naming a field
+	// an import cannot fill
+	ErrInvalidColumnMapping = errors.New("invalid column mapping")
+)
+
+// ImportFields are the participant fields an import file can fill, in the
+// column order of ImportParticipantsCSV. Name and email are required.
+var ImportFields = []string{"name", "email", "phone", "division", "address", "meal_preference"}
+
+// importHeaderAliases are other header names taken for a field, as
+// normalized by normalizeImportHeader
+var importHeaderAliases = map[string]string{
+	"full_name":     "name",
+	"fullname":      "name",
+	"e_mail":        "email",
+	"email_address": "email",
+	"phone_number":  "phone",
+	"mobile":        "phone",
+	"department":    "division",
+	"meal":          "meal_preference",
+	"diet":          "meal_preference",
+}
+
+// importColumns locates the participant fields in the rows of an import
+type importColumns struct {
+	index map[string]int
+	// Rows shorter than this lack a column and are refused
+	width int
+}
+
+// legacyImportColumns are the fixed columns of ImportParticipantsCSV, where
+// only the meal preference may be left out
+func legacyImportColumns() importColumns {
+	columns := importColumns{index: make(map[string]int, len(ImportFields)), width: 5}
+	for i, field := range ImportFields {
+		columns.index[field] = i
+	}
+	return columns
+}
+
+// value returns the trimmed cell of field in row, or "" when the file has no
+// such column
+func (c importColumns) value(row []string, field string) string {
+	i, ok := c.index[field]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func isImportField(field string) bool {
+	for _, f := range ImportFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeImportHeader lowercases a header and turns spaces and dashes into
+// underscores, so "E-mail" and "Full Name" read as e_mail and full_name
+func normalizeImportHeader(header string) string {
+	header = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(header)
+}
+
+// validateColumnMapping checks that a column mapping only names fields of
+// ImportFields
+func validateColumnMapping(mapping map[string]string) error {
+	for field, header := range mapping {
+		if !isImportField(field) {
+			return fmt.Errorf("%w: unknown field %q, expected one of %s", ErrInvalidColumnMapping, field, strings.Join(ImportFields, ", "))
+		}
+		if strings.TrimSpace(header) == "" {
+			return fmt.Errorf("%w: no column given for %s", ErrInvalidColumnMapping, field)
+		}
+	}
+	return nil
+}
+
+// resolveImportColumns finds the participant fields in the header row of an
+// import file: under the header the mapping gives, otherwise under a header
+// named after the field or one of its aliases, ignoring case. Columns of
+// other headers are ignored, and the file needs name and email columns.
+func resolveImportColumns(header []string, mapping map[string]string) (importColumns, error) {
+	if err := validateColumnMapping(mapping); err != nil {
+		return importColumns{}, err
+	}
+
+	columns := importColumns{index: map[string]int{}}
+	claimed := map[int]bool{}
+	for _, field := range ImportFields {
+		name, ok := mapping[field]
+		if !ok {
+			continue
+		}
+		found := -1
+		for i, h := range header {
+			if normalizeImportHeader(h) == normalizeImportHeader(name) {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return importColumns{}, fmt.Errorf("column %q mapped to %s is not in the header row", name, field)
+		}
+		columns.index[field], claimed[found] = found, true
+	}
+	for i, h := range header {
+		field := normalizeImportHeader(h)
+		if alias, ok := importHeaderAliases[field]; ok {
+			field = alias
+		}
+		if claimed[i] || !isImportField(field) {
+			continue
+		}
+		if _, mapped := mapping[field]; mapped {
+			continue
+		}
+		if _, taken := columns.index[field]; taken {
+			return importColumns{}, fmt.Errorf("header row has more than one %s column", field)
+		}
+		columns.index[field] = i
+	}
+
+	for _, field := range []string{"name", "email"} {
+		if _, ok := columns.index[field]; !ok {
+			return importColumns{}, fmt.Errorf("header row has no %s column; name a column %s or map one to it", field, field)
+		}
+	}
+	for _, i := range columns.index {
+		if i >= columns.width {
+			columns.width = i + 1
+		}
+	}
+	return columns, nil
+}
+
+// StartParticipantImport stores an uploaded CSV or XLSX file of participants
+// and creates the import job that registers them; the caller enqueues it for
+// RunParticipantImport. The file starts with a header row; columns are found
+// by their header, or by the header mapping gives for a field, see
+// resolveImportColumns. A dry run checks the rows as an import would and
+// reports the problems without registering anyone.
+func (s *ParticipantService) StartParticipantImport(actorID, eventID, filename, format string, mapping map[string]string, dryRun bool, src io.Reader) (*models.ImportJob, error) {
+	if format != ImportFormatCSV && format != ImportFormatXLSX {
+		return nil, errors.New("format must be csv or xlsx")
+	}
+	if err := validateColumnMapping(mapping); err != nil {
+		return nil, err
+	}
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+
+	job := &models.ImportJob{
+		ID:            uuid.New(),
+		EventID:       event.ID,
+		Filename:      filepath.Base(filename),
+		Format:        format,
+		Status:        "pending",
+		ColumnMapping: mapping,
+		DryRun:        dryRun,
+		RowErrors:     []models.ImportRowError{},
+	}
+	if id, err := uuid.Parse(actorID); err == nil {
+		job.CreatedBy = &id
+	}
+
+	if err := os.MkdirAll(s.cfg.ImportDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create import directory: %w", err)
+	}
+	job.FilePath = filepath.Join(s.cfg.ImportDir, job.ID.String()+"."+format)
+	dst, err := os.OpenFile(job.FilePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store import file: %w", err)
+	}
+	_, err = io.Copy(dst, src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(job.FilePath)
+		return nil, fmt.Errorf("failed to store import file: %w", err)
+	}
+
+	if err := s.repo.ImportJobRepo.CreateImportJob(job); err != nil {
+		os.Remove(job.FilePath)
+		return nil, err
+	}
+	if dryRun {
+		return job, nil
+	}
+	s.audit.Record(actorID, AuditParticipantsImported, AuditEntityEvent, eventID, map[string]interface{}{
+		"import_job_id": job.ID.String(),
+		"filename":      job.Filename,
+	})
+	return job, nil
+}
+
+func (s *ParticipantService) GetImportJob(importJobID string) (*models.ImportJob, error) {
+	job, err := s.repo.ImportJobRepo.GetImportJob(importJobID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUnknownImportJob
+	}
+	return job, err
+}
+
+// RunParticipantImport registers the participants of an import job. The
+// file is streamed in chunks, each registered in one transaction before the
+// job's progress is saved, so memory use does not grow with the file. Imports
+// that cannot go on are marked failed and return nil, as retrying would not
+// help; a cancelled ctx is returned so the job runs again later and resumes
+// after the last saved chunk. Rows of a chunk interrupted part way are then
+// reported as already registered. Dry runs register nobody and start over
+// when resumed, as the rows they accepted are only known in memory.
+func (s *ParticipantService) RunParticipantImport(ctx context.Context, importJobID string) error {
+	job, err := s.GetImportJob(importJobID)
+	if err != nil {
+		return err
+	}
+	if job.Status == "completed" || job.Status == "failed" {
+		return nil
+	}
+
+	event, err := s.repo.EventRepo.GetEventByID(job.EventID.String())
+	if err != nil {
+		return s.failImport(job, "event not found")
+	}
+	// Counting first gives the progress a total and refuses unreadable files
+	// before anyone is registered
+	total, err := countImportRows(job)
+	if err != nil {
+		return s.failImport(job, err.Error())
+	}
+
+	var dryRun *importDryRun
+	if job.DryRun {
+		dryRun = &importDryRun{emails: map[string]bool{}, rules: map[uuid.UUID]int{}}
+		job.ProcessedRows, job.Imported, job.Failed, job.RowErrors = 0, 0, 0, []models.ImportRowError{}
+	}
+
+	now := time.Now()
+	job.Status, job.TotalRows, job.Error = "running", total, ""
+	if job.StartedAt == nil {
+		job.StartedAt = &now
+	}
+	if err := s.repo.ImportJobRepo.UpdateImportJob(job); err != nil {
+		return err
+	}
+
+	rows, err := openImportRows(job)
+	if err != nil {
+		return s.failImport(job, err.Error())
+	}
+	defer rows.Close()
+	columns, err := resolveImportColumns(rows.header, job.ColumnMapping)
+	if err != nil {
+		return s.failImport(job, err.Error())
+	}
+
+	chunk := make([][]string, 0, importChunkSize)
+	skip := job.ProcessedRows
+	for {
+		row, err := rows.Read()
+		if err != nil && err != io.EOF {
+			return s.failImport(job, err.Error())
+		}
+		if err == nil {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			chunk = append(chunk, row)
+			if len(chunk) < importChunkSize {
+				continue
+			}
+		}
+
+		if len(chunk) > 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			imported, failed, rowErrors, importErr := s.importParticipantRows(event, columns, chunk, job.ProcessedRows+1, dryRun)
+			job.Imported += imported
+			job.Failed += failed
+			job.RowErrors = append(job.RowErrors, rowErrors...)
+			if importErr != nil {
+				return s.failImport(job, importErr.Error())
+			}
+			job.ProcessedRows += len(chunk)
+			if err := s.repo.ImportJobRepo.UpdateImportJob(job); err != nil {
+				return err
+			}
+			chunk = chunk[:0]
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+
+	finished := time.Now()
+	job.Status, job.FinishedAt = "completed", &finished
+	if err := s.repo.ImportJobRepo.UpdateImportJob(job); err != nil {
+		return err
+	}
+	s.removeImportFile(job)
+	return nil
+}
+
+// FailParticipantImport gives up on an import job that has not finished,
+// e.g. one that could not be enqueued
+func (s *ParticipantService) FailParticipantImport(importJobID, reason string) error {
+	job, err := s.GetImportJob(importJobID)
+	if err != nil {
+		return err
+	}
+	if job.Status == "completed" || job.Status == "failed" {
+		return nil
+	}
+	return s.failImport(job, reason)
+}
+
+func (s *ParticipantService) failImport(job *models.ImportJob, reason string) error {
+	finished := time.Now()
+	job.Status, job.Error, job.FinishedAt = "failed", reason, &finished
+	if err := s.repo.ImportJobRepo.UpdateImportJob(job); err != nil {
+		return err
+	}
+	s.removeImportFile(job)
+	return nil
+}
+
+func (s *ParticipantService) removeImportFile(job *models.ImportJob) {
+	if err := os.Remove(job.FilePath); err != nil && !os.IsNotExist(err) {
+		logger.Log.WithError(err).WithField("import_job_id", job.ID.String()).Warn("failed to remove import file")
+	}
+}
+
+// importDryRun keeps what the earlier chunks of a dry run would have
+// registered, as it is not in the database for later chunks to check against
+type importDryRun struct {
+	// Hashes of the accepted emails
+	emails   map[string]bool
+	accepted int
+	// Accepted rows per quota rule ID
+	rules map[uuid.UUID]int
+}
+
+// importRows reads the data rows of an import file one at a time, skipping
+// blank ones
+type importRows struct {
+	file   *os.File
+	read   func() ([]string, error)
+	header []string
+}
+
+// openImportRows opens the file of an import job past its header row, which
+// it keeps
+func openImportRows(job *models.ImportJob) (*importRows, error) {
+	f, err := os.Open(job.FilePath)
+	if err != nil {
+		return nil, errors.New("import file is missing")
+	}
+	rows := &importRows{file: f}
+
+	switch job.Format {
+	case ImportFormatXLSX:
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		sheet, err := xlsx.OpenRows(f, info.Size())
+		if err != nil {
+			f.Close()
+			return nil, errors.New("invalid XLSX file")
+		}
+		rows.read = func() ([]string, error) {
+			row, err := sheet.Read()
+			if err != nil && err != io.EOF {
+				return nil, errors.New("invalid XLSX file")
+			}
+			return row, err
+		}
+	default:
+		reader := csv.NewReader(f)
+		// Short rows are reported by row rather than failing the file
+		reader.FieldsPerRecord = -1
+		rows.read = func() ([]string, error) {
+			row, err := reader.Read()
+			if err != nil && err != io.EOF {
+				return nil, errors.New("invalid CSV format")
+			}
+			return row, err
+		}
+	}
+
+	if rows.header, err = rows.Read(); err != nil {
+		f.Close()
+		if err == io.EOF {
+			return nil, errors.New("file is empty or missing header")
+		}
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Read returns the next row that is not blank, or io.EOF
+func (r *importRows) Read() ([]string, error) {
+	for {
+		row, err := r.read()
+		if err != nil || strings.TrimSpace(strings.Join(row, "")) != "" {
+			return row, err
+		}
+	}
+}
+
+func (r *importRows) Close() error {
+	return r.file.Close()
+}
+
+// countImportRows counts the data rows of the file of an import job
+func countImportRows(job *models.ImportJob) (int, error) {
+	rows, err := openImportRows(job)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	for {
+		_, err := rows.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		count++
+	}
+	if count == 0 {
+		return 0, errors.New("file is empty or missing header")
+	}
+	return count, nil
+}
+
+// WriteImportErrorReport writes the row problems of an import job as CSV
+func WriteImportErrorReport(job *models.ImportJob, out io.Writer) error {
+	w := csv.NewWriter(out)
+	if err := w.Write([]string{"row", "error"}); err != nil {
+		return err
+	}
+	for _, rowErr := range job.RowErrors {
+		if err := w.Write([]string{strconv.Itoa(rowErr.Row), rowErr.Message}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
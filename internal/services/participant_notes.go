@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// MaxNoteLength is the longest note, in characters, staff can keep on a
+// participant
+const MaxNoteLength = 2000
+
+var ErrNoteBodyRequired = errors.New("note must not be empty")
+
+// ParticipantDetail is a participant with what staff recorded about them
+type ParticipantDetail struct {
+	Participant *models.Participant `json:"participant"`
+	// Newest first
+	Notes []models.ParticipantNote `json:"notes"`
+}
+
+// GetParticipantDetail returns a participant with their notes
+func (s *ParticipantService) GetParticipantDetail(participantID string) (*ParticipantDetail, error) {
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return nil, ErrUnknownParticipant
+	}
+	notes, err := s.repo.ParticipantRepo.ListParticipantNotes(participantID)
+	if err != nil {
+		return nil, err
+	}
+	return &ParticipantDetail{Participant: participant, Notes: notes}, nil
+}
+
+// AddParticipantNote records a note by authorID on a participant
+func (s *ParticipantService) AddParticipantNote(authorID, participantID, body string) (*models.ParticipantNote, error) {
+	author, err := uuid.Parse(authorID)
+	if err != nil {
+		return nil, errors.New("invalid author")
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, ErrNoteBodyRequired
+	}
+	if utf8.RuneCountInString(body) > MaxNoteLength {
+		return nil, errors.New("note is too long")
+	}
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil || participant.AnonymizedAt != nil {
+		return nil, ErrUnknownParticipant
+	}
+
+	note := &models.ParticipantNote{
+		ID:            uuid.New(),
+		ParticipantID: participant.ID,
+		Body:          body,
+		AuthorID:      author,
+	}
+	if err := s.repo.ParticipantRepo.CreateParticipantNote(note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// ListParticipantNotes returns the notes kept on a participant, newest first
+func (s *ParticipantService) ListParticipantNotes(participantID string) ([]models.ParticipantNote, error) {
+	if _, err := s.repo.ParticipantRepo.GetParticipantByID(participantID); err != nil {
+		return nil, ErrUnknownParticipant
+	}
+	return s.repo.ParticipantRepo.ListParticipantNotes(participantID)
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// auditExportBatch is how many audit logs a data export reads per query
+const auditExportBatch = 200
+
+var (
+	ErrParticipantAnonymized = errors.New("participant was anonymized already")
+	// ErrEventUnderLegalHold is returned when erasing a participant of an
+	// event under legal hold; release the hold first
+	ErrEventUnderLegalHold = errors.New("event is under legal hold")
+)
+
+// ParticipantDataExport is everything stored about a participant, to answer
+// a data subject access request
+type ParticipantDataExport struct {
+	ExportedAt  time.Time           `json:"exported_at"`
+	Participant *models.Participant `json:"participant"`
+	EventTitle  string              `json:"event_title"`
+	ActionLogs  []*models.ActionLog `json:"action_logs"`
+	*repositories.ParticipantRecords
+	CredentialIncidents []models.CredentialIncident `json:"credential_incidents"`
+	Notes               []models.ParticipantNote    `json:"notes"`
+	// Changes made to the registration, oldest first
+	AuditLogs []models.AuditLog `json:"audit_logs"`
+}
+
+// ExportParticipantData collects the personal data stored about a
+// participant with the records related to them. The export is audited.
+func (s *ParticipantService) ExportParticipantData(actorID, participantID string) (*ParticipantDataExport, error) {
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return nil, ErrUnknownParticipant
+	}
+	export := &ParticipantDataExport{ExportedAt: time.Now(), Participant: participant}
+	if event, err := s.repo.EventRepo.GetEventByID(participant.EventID.String()); err == nil {
+		export.EventTitle = event.Title
+	}
+
+	if export.ActionLogs, err = s.repo.ActionRepo.GetActionLogsByParticipant(participantID); err != nil {
+		return nil, err
+	}
+	if export.ParticipantRecords, err = s.repo.ParticipantRepo.ListParticipantRecords(participantID); err != nil {
+		return nil, err
+	}
+	if export.CredentialIncidents, err = s.repo.ParticipantRepo.ListCredentialIncidents(participantID); err != nil {
+		return nil, err
+	}
+	if export.Notes, err = s.repo.ParticipantRepo.ListParticipantNotes(participantID); err != nil {
+		return nil, err
+	}
+
+	filter := repositories.AuditLogFilter{EntityType: AuditEntityParticipant, EntityID: participantID}
+	export.AuditLogs = []models.AuditLog{}
+	for offset := 0; ; offset += auditExportBatch {
+		logs, total, err := s.repo.AuditRepo.ListAuditLogs(filter, offset, auditExportBatch)
+		if err != nil {
+			return nil, err
+		}
+		export.AuditLogs = append(export.AuditLogs, logs...)
+		if len(logs) == 0 || int64(offset+len(logs)) >= total {
+			break
+		}
+	}
+	// Listed newest first
+	for i, j := 0, len(export.AuditLogs)-1; i < j; i, j = i+1, j-1 {
+		export.AuditLogs[i], export.AuditLogs[j] = export.AuditLogs[j], export.AuditLogs[i]
+	}
+
+	s.audit.Record(actorID, AuditParticipantDataExported, AuditEntityParticipant, participantID, nil)
+	return export, nil
+}
+
+// AnonymizeParticipant erases the personal data of one participant on
+// request, as retention rules do for whole events: contact details, answers,
+// waiver signer details, notes and the waitlist entry they came from. The QR
+// image is deleted. Action logs stay, so event statistics still count the
+// participant. Participants of events under legal hold are refused.
+func (s *ParticipantService) AnonymizeParticipant(actorID, participantID string) (*models.Participant, error) {
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return nil, ErrUnknownParticipant
+	}
+	if participant.AnonymizedAt != nil {
+		return nil, ErrParticipantAnonymized
+	}
+	holds, err := s.repo.RetentionRepo.ListLegalHolds()
+	if err != nil {
+		return nil, err
+	}
+	for _, hold := range holds {
+		if hold.EventID == participant.EventID {
+			return nil, ErrEventUnderLegalHold
+		}
+	}
+
+	if err := s.repo.RetentionRepo.AnonymizeParticipant(participantID, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrParticipantAnonymized
+		}
+		return nil, err
+	}
+	if participant.QRPath != "" {
+		if err := os.Remove(filepath.Join(s.cfg.QRDir, filepath.Base(participant.QRPath))); err != nil && !os.IsNotExist(err) {
+			logger.Log.WithError(err).WithField("participant_id", participantID).Warn("failed to remove QR code of anonymized participant")
+		} else if err := s.repo.RetentionRepo.ClearQRPaths([]uuid.UUID{participant.ID}); err != nil {
+			return nil, err
+		}
+	}
+
+	s.audit.Record(actorID, AuditParticipantAnonymized, AuditEntityParticipant, participantID, map[string]interface{}{
+		"event_id": participant.EventID.String(),
+	})
+	return s.repo.ParticipantRepo.GetParticipantByID(participantID)
+}
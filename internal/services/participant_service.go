@@ -3,23 +3,29 @@ package services
 import (
 	"errors"
 	"fmt"
+	"net/mail"
+	"os"
+	"runtime"
+	"sync"
+	"time"
 
 	"event-management-backend/internal/config"
+	"event-management-backend/internal/fieldcrypt"
 	"event-management-backend/internal/models"
 	"event-management-backend/internal/repositories"
 	"event-management-backend/internal/utils"
 
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
 type ParticipantService struct {
-	repo *repositories.Repository
-	cfg  *config.Config
+	repo  *repositories.Repository
+	cfg   *config.Config
+	audit *AuditService
 }
 
 func NewParticipantService(repo *repositories.Repository, cfg *config.Config) *ParticipantService {
-	return &ParticipantService{repo: repo, cfg: cfg}
+	return &ParticipantService{repo: repo, cfg: cfg, audit: NewAuditService(repo, cfg)}
 }
 
 type RegisterParticipantRequest struct {
@@ -29,117 +35,422 @@ type RegisterParticipantRequest struct {
 	Phone    string
 	Division string
 	Address  string
+	// One of MealPreferences; regular when empty
+	MealPreference string
+	// Required on events with ticket types
+	TicketTypeID string
+	// Answers to the event's form fields, see models.Participant
+	Answers map[string]interface{}
 }
 
 type RegisterParticipantResponse struct {
 	Participant *models.Participant
-	QRPath      string
+	// Ticket type registered for; nil on events without ticket types
+	TicketType *models.TicketType
+	// Empty until the registration is confirmed, approved and every waiver
+	// is signed
+	QRPath string
+	// Waivers to sign before the QR code is released
+	PendingWaivers []models.Waiver
 }
 
 func (s *ParticipantService) RegisterParticipant(req RegisterParticipantRequest) (*RegisterParticipantResponse, error) {
-	var result *RegisterParticipantResponse
+	mealPreference, err := NormalizeMealPreference(req.MealPreference)
+	if err != nil {
+		return nil, err
+	}
 
-	err := s.repo.ParticipantRepo.Transaction(func(tx *gorm.DB) error {
-		// Get event with lock for update to prevent race condition
-		event, err := s.repo.EventRepo.GetEventByID(req.EventID)
-		if err != nil {
-			return errors.New("event not found")
-		}
+	event, err := s.repo.EventRepo.GetEventByID(req.EventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+	if event.Status != EventPublished {
+		return nil, ErrEventNotPublished
+	}
+	if err := checkRegistrationWindow(event, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Check if email already registered for this event
+	existing, _ := s.repo.ParticipantRepo.GetParticipantByEmailAndEvent(req.Email, req.EventID)
+	if existing != nil {
+		return nil, errors.New("email already registered for this event")
+	}
+
+	ticketType, err := s.pickTicketType(event, req.TicketTypeID)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := validateAnswers(event.FormFields, req.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	// The event quota, day capacities and ticket type quota are checked again
+	// with the event locked as the participant is inserted
+	limit, err := s.groupLimit(event, 1)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkQuotaRules(req.EventID, ticketType, req.Division); err != nil {
+		return nil, err
+	}
 
-		// Check if email already registered for this event
-		existing, _ := s.repo.ParticipantRepo.GetParticipantByEmailAndEvent(req.Email, req.EventID)
-		if existing != nil {
-			return errors.New("email already registered for this event")
+	participants := []models.Participant{{
+		ID:             uuid.New(),
+		EventID:        event.ID,
+		Name:           req.Name,
+		Email:          req.Email,
+		Phone:          req.Phone,
+		Division:       req.Division,
+		Address:        req.Address,
+		MealPreference: mealPreference,
+		Answers:        answers,
+		PaymentStatus:  "paid",
+		ApprovalStatus: ApprovalApproved,
+	}}
+	participant := &participants[0]
+	if ticketPrice(event, ticketType) > 0 {
+		participant.PaymentStatus = "pending"
+	}
+	if ticketType != nil {
+		participant.TicketTypeID = &ticketType.ID
+	}
+	if event.RequiresApproval {
+		participant.ApprovalStatus = ApprovalPending
+	}
+	requireConfirmation(event, participant, time.Now())
+
+	if err := s.repo.ParticipantRepo.CreateParticipantGroup(req.EventID, participants, limit); err != nil {
+		return nil, err
+	}
+
+	// Generate the QR code unless approval or waivers hold it back
+	waivers, err := s.ReleaseQR(participant)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RegisterParticipantResponse{
+		Participant:    participant,
+		TicketType:     ticketType,
+		QRPath:         participant.QRPath,
+		PendingWaivers: waivers,
+	}, nil
+}
+
+// ReleaseQR generates the QR code of a participant once nothing holds it
+// back: the registration must be approved, not cancelled, and every active
+// waiver of the event signed. It returns the waivers still to sign; participant.QRPath
+// stays empty while the QR code is held back.
+func (s *ParticipantService) ReleaseQR(participant *models.Participant) ([]models.Waiver, error) {
+	waivers, err := s.repo.WaiverRepo.ListUnsignedWaivers(participant.EventID.String(), participant.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if participant.QRPath != "" || len(waivers) > 0 || participant.ApprovalStatus != ApprovalApproved || participant.CancelledAt != nil {
+		return waivers, nil
+	}
+
+	filename, err := utils.GenerateQRCodeImage(credentialContent(s.cfg.CredentialSigningKey, participant), s.cfg.QRDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate QR code: %w", err)
+	}
+
+	participant.QRPath = fmt.Sprintf("/qrcodes/%s", filename)
+	if err := s.repo.ParticipantRepo.UpdateParticipant(participant); err != nil {
+		return nil, err
+	}
+	return waivers, nil
+}
+
+// ImportParticipantsCSV registers participants from CSV or spreadsheet rows
+// (header removed). Every row is validated first, the valid rows are inserted in
+// batches and their QR codes are then written by a pool of workers, so large
+// imports avoid a transaction and a file write per row.
+func (s *ParticipantService) ImportParticipantsCSV(eventID string, rows [][]string) (int, int, []string, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		errors := make([]string, len(rows))
+		for i := range rows {
+			errors[i] = fmt.Sprintf("Row %d: event not found", i+1)
 		}
+		return 0, len(rows), errors, nil
+	}
 
-		// Check quota if applicable
-		if event.TicketQuota != nil {
-			currentCount, err := s.repo.ParticipantRepo.GetParticipantCountByEventID(req.EventID)
-			if err != nil {
-				return errors.New("failed to check quota")
-			}
-			if int(currentCount) >= *event.TicketQuota {
-				return errors.New("ticket quota exceeded")
+	imported, fail, rowErrors, err := s.importParticipantRows(event, legacyImportColumns(), rows, 1, nil)
+	errors := make([]string, len(rowErrors))
+	for i, rowErr := range rowErrors {
+		errors[i] = fmt.Sprintf("Row %d: %s", rowErr.Row, rowErr.Message)
+	}
+	return imported, fail, errors, err
+}
+
+// importParticipantRows registers participants from rows numbered from
+// firstRow, reading the fields from columns. It returns how many were
+// imported and rejected, and the problems found by row; a row whose QR code
+// failed is imported but still reported. Rows past the ticket quota or a
+// quota rule of their division are rejected. With dryRun, the rows are only
+// checked, against the database and the rows dryRun accepted before.
+func (s *ParticipantService) importParticipantRows(event *models.Event, columns importColumns, rows [][]string, firstRow int, dryRun *importDryRun) (int, int, []models.ImportRowError, error) {
+	fail := 0
+	errors := make([]models.ImportRowError, 0)
+	rowError := func(i int, message string) {
+		fail++
+		errors = append(errors, models.ImportRowError{Row: firstRow + i, Message: message})
+	}
+
+	// Only the emails of these rows are looked up, so batches of a large
+	// import each cost the same
+	hashes := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if email := columns.value(row, "email"); email != "" {
+			hashes = append(hashes, fieldcrypt.Hash(email))
+		}
+	}
+	registered, err := s.repo.ParticipantRepo.GetRegisteredEmailHashes(event.ID.String(), hashes)
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to load registered emails: %w", err)
+	}
+	if dryRun != nil {
+		for _, hash := range hashes {
+			if dryRun.emails[hash] {
+				registered[hash] = true
 			}
 		}
+	}
 
-		// Create participant
-		participant := &models.Participant{
-			ID:       uuid.New(),
-			EventID:  uuid.MustParse(req.EventID),
-			Name:     req.Name,
-			Email:    req.Email,
-			Phone:    req.Phone,
-			Division: req.Division,
-			Address:  req.Address,
-			PaymentStatus: func() string {
-				if event.TicketPrice > 0 {
-					return "pending"
-				}
-				return "paid"
-			}(),
+	remaining := -1
+	if event.TicketQuota != nil {
+		count, err := s.repo.ParticipantRepo.GetParticipantCountByEventID(event.ID.String())
+		if err != nil {
+			return 0, 0, nil, fmt.Errorf("failed to count participants: %w", err)
 		}
+		remaining = *event.TicketQuota - int(count)
+		if dryRun != nil {
+			remaining -= dryRun.accepted
+		}
+		if remaining < 0 {
+			remaining = 0
+		}
+	}
+
+	// Imported participants have no ticket type, so only the quota rules of
+	// their division apply
+	rules, err := s.repo.QuotaRuleRepo.ListQuotaRulesByEvent(event.ID.String())
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	ruleLeft := make([]int, len(rules))
+	for r := range rules {
+		count, err := s.repo.QuotaRuleRepo.CountRuleRegistrations(&rules[r])
+		if err != nil {
+			return 0, 0, nil, fmt.Errorf("failed to count quota rule registrations: %w", err)
+		}
+		ruleLeft[r] = rules[r].MaxRegistrations - int(count)
+		if dryRun != nil {
+			ruleLeft[r] -= dryRun.rules[rules[r].ID]
+		}
+	}
+
+	paymentStatus := "paid"
+	if event.TicketPrice > 0 {
+		paymentStatus = "pending"
+	}
 
-		if err := s.repo.ParticipantRepo.CreateParticipant(participant); err != nil {
-			return err
+	participants := make([]models.Participant, 0, len(rows))
+	rowNumbers := make([]int, 0, len(rows))
+	filenames := make([]string, 0, len(rows))
+
+	for i, row := range rows {
+		if len(row) < columns.width {
+			rowError(i, "insufficient data")
+			continue
 		}
 
-		// Generate QR code
-		filename, err := utils.GenerateQRCodeImage(participant.ID.String(), s.cfg.QRDir)
+		name := columns.value(row, "name")
+		email := columns.value(row, "email")
+		if name == "" {
+			rowError(i, "name is required")
+			continue
+		}
+		if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+			rowError(i, "invalid email")
+			continue
+		}
+
+		mealPreference, err := NormalizeMealPreference(columns.value(row, "meal_preference"))
 		if err != nil {
-			return fmt.Errorf("failed to generate QR code: %w", err)
+			rowError(i, err.Error())
+			continue
 		}
 
-		// Update participant with QR path
+		// Catches duplicates against the database and within the file
+		hash := fieldcrypt.Hash(email)
+		if registered[hash] {
+			rowError(i, "email already registered for this event")
+			continue
+		}
+		if remaining == 0 {
+			rowError(i, "ticket quota exceeded")
+			continue
+		}
+		division := columns.value(row, "division")
+		if r := fullQuotaRule(rules, ruleLeft, division); r >= 0 {
+			rowError(i, fmt.Sprintf("%s: %s", ErrQuotaRuleReached, rules[r].Name))
+			continue
+		}
+		registered[hash] = true
+		remaining--
+		for r := range rules {
+			if rules[r].Applies(nil, division) {
+				ruleLeft[r]--
+				if dryRun != nil {
+					dryRun.rules[rules[r].ID]++
+				}
+			}
+		}
+
+		participant := models.Participant{
+			ID:             uuid.New(),
+			EventID:        event.ID,
+			Name:           name,
+			Email:          email,
+			Phone:          columns.value(row, "phone"),
+			Division:       division,
+			Address:        columns.value(row, "address"),
+			MealPreference: mealPreference,
+			PaymentStatus:  paymentStatus,
+			Version:        1,
+		}
+
+		filename := utils.NewQRCodeFilename()
 		participant.QRPath = fmt.Sprintf("/qrcodes/%s", filename)
-		if err := s.repo.ParticipantRepo.UpdateParticipant(participant); err != nil {
-			return err
+
+		participants = append(participants, participant)
+		rowNumbers = append(rowNumbers, i)
+		filenames = append(filenames, filename)
+	}
+
+	if dryRun != nil {
+		for _, participant := range participants {
+			dryRun.emails[fieldcrypt.Hash(participant.Email)] = true
 		}
+		dryRun.accepted += len(participants)
+		return len(participants), fail, errors, nil
+	}
+	if len(participants) == 0 {
+		return 0, fail, errors, nil
+	}
+
+	if err := s.repo.ParticipantRepo.CreateParticipantsInBatches(participants, s.cfg.ImportBatchSize); err != nil {
+		return 0, fail, errors, fmt.Errorf("failed to insert participants: %w", err)
+	}
 
-		result = &RegisterParticipantResponse{
-			Participant: participant,
-			QRPath:      participant.QRPath,
+	// Rows are committed at this point; a participant whose QR code cannot be
+	// written stays registered without one, as the QR can be regenerated
+	failedQR := writeQRCodes(s.cfg, participants, filenames)
+	if len(failedQR) > 0 {
+		ids := make([]uuid.UUID, 0, len(failedQR))
+		for _, i := range failedQR {
+			ids = append(ids, participants[i].ID)
+			errors = append(errors, models.ImportRowError{Row: firstRow + rowNumbers[i], Message: "registered, but failed to generate QR code"})
 		}
-		return nil
-	})
+		if err := s.repo.ParticipantRepo.ClearQRPaths(ids); err != nil {
+			return len(participants), fail, errors, fmt.Errorf("failed to clear QR paths: %w", err)
+		}
+	}
 
-	return result, err
+	return len(participants), fail, errors, nil
 }
 
-func (s *ParticipantService) ImportParticipantsCSV(eventID string, rows [][]string) (int, int, []string, error) {
-	success := 0
-	fail := 0
-	errors := make([]string, 0)
-
-	for i, row := range rows {
-		if len(row) < 5 {
-			fail++
-			errors = append(errors, fmt.Sprintf("Row %d: insufficient data", i+1))
-			continue
+// fullQuotaRule returns the index of a rule a participant without a ticket
+// type from division counts against with nothing left, or -1
+func fullQuotaRule(rules []models.QuotaRule, left []int, division string) int {
+	for r := range rules {
+		if left[r] <= 0 && rules[r].Applies(nil, division) {
+			return r
 		}
+	}
+	return -1
+}
 
-		req := RegisterParticipantRequest{
-			EventID:  eventID,
-			Name:     row[0],
-			Email:    row[1],
-			Phone:    row[2],
-			Division: row[3],
-			Address:  row[4],
+// writeQRCodes renders the QR code of every participant with a pool of
+// workers and returns the indexes whose image could not be written
+func writeQRCodes(cfg *config.Config, participants []models.Participant, filenames []string) []int {
+	if err := os.MkdirAll(cfg.QRDir, 0755); err != nil {
+		failed := make([]int, len(participants))
+		for i := range failed {
+			failed[i] = i
 		}
+		return failed
+	}
 
-		_, err := s.RegisterParticipant(req)
-		if err != nil {
-			fail++
-			errors = append(errors, fmt.Sprintf("Row %d: %s", i+1, err.Error()))
-		} else {
-			success++
+	workers := cfg.ImportQRWorkers
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+
+	// Each index is handled by exactly one worker, so ok needs no locking
+	ok := make([]bool, len(participants))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				ok[i] = utils.WriteQRCodeImage(participants[i].ID.String(), cfg.QRDir, filenames[i]) == nil
+			}
+		}()
+	}
+
+	for i := range participants {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var failed []int
+	for i, written := range ok {
+		if !written {
+			failed = append(failed, i)
 		}
 	}
+	return failed
+}
+
+// ErrInvalidParticipantFilter is returned for participant listing filters
+// that cannot match anything valid
+var ErrInvalidParticipantFilter = errors.New("invalid participant filter")
 
-	return success, fail, errors, nil
+// validateParticipantFilters checks the payment status and the registration
+// date range of a listing
+func validateParticipantFilters(filters *repositories.ParticipantFilters) error {
+	if filters == nil {
+		return nil
+	}
+	switch filters.PaymentStatus {
+	case "", "unpaid", "pending", "paid", "refund_pending":
+	default:
+		return fmt.Errorf("%w: payment_status must be unpaid, pending, paid or refund_pending", ErrInvalidParticipantFilter)
+	}
+	if filters.RegisteredAfter != nil && filters.RegisteredBefore != nil && filters.RegisteredBefore.Before(*filters.RegisteredAfter) {
+		return fmt.Errorf("%w: registered_before must not be before registered_after", ErrInvalidParticipantFilter)
+	}
+	return nil
 }
 
-func (s *ParticipantService) ListParticipants(eventID string, page, pageSize int) ([]models.Participant, int64, int, error) {
+// ListParticipants returns one page of the participants of an event, newest
+// first, narrowed by the optional filters
+func (s *ParticipantService) ListParticipants(eventID string, page, pageSize int, filters *repositories.ParticipantFilters) ([]models.Participant, int64, int, error) {
+	if err := validateParticipantFilters(filters); err != nil {
+		return nil, 0, 0, err
+	}
+	if err := s.validateAnswerFilters(eventID, filters); err != nil {
+		return nil, 0, 0, err
+	}
 	if page <= 0 {
 		page = 1
 	}
@@ -148,7 +459,7 @@ func (s *ParticipantService) ListParticipants(eventID string, page, pageSize int
 	}
 
 	offset := (page - 1) * pageSize
-	participants, total, err := s.repo.ParticipantRepo.ListParticipantsByEvent(eventID, offset, pageSize)
+	participants, total, err := s.repo.ParticipantRepo.ListParticipantsByEvent(eventID, offset, pageSize, filters)
 	if err != nil {
 		return nil, 0, 0, err
 	}
@@ -157,11 +468,61 @@ func (s *ParticipantService) ListParticipants(eventID string, page, pageSize int
 	return participants, total, totalPages, nil
 }
 
-func (s *ParticipantService) UpdatePaymentStatus(participantID, status string) error {
+// ListParticipantsByCursor returns one keyset-paginated page of participants
+// and the cursor for the next page (empty when there are no more rows)
+func (s *ParticipantService) ListParticipantsByCursor(eventID, cursor string, pageSize int, filters *repositories.ParticipantFilters) ([]models.Participant, string, error) {
+	if err := validateParticipantFilters(filters); err != nil {
+		return nil, "", err
+	}
+	if err := s.validateAnswerFilters(eventID, filters); err != nil {
+		return nil, "", err
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	var after *repositories.Cursor
+	if cursor != "" {
+		t, id, err := utils.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = &repositories.Cursor{Time: t, ID: id}
+	}
+
+	// Fetch one extra row to know whether another page exists
+	participants, err := s.repo.ParticipantRepo.ListParticipantsByEventAfter(eventID, after, pageSize+1, filters)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(participants) > pageSize {
+		participants = participants[:pageSize]
+		last := participants[len(participants)-1]
+		nextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return participants, nextCursor, nil
+}
+
+func (s *ParticipantService) GetParticipant(id string) (*models.Participant, error) {
+	return s.repo.ParticipantRepo.GetParticipantByID(id)
+}
+
+// UpdatePaymentStatus changes the payment status. A non-nil version enables
+// optimistic locking and yields repositories.ErrVersionConflict when stale.
+func (s *ParticipantService) UpdatePaymentStatus(actorID, participantID, status string, version *int) error {
 	allowedStatus := map[string]bool{"unpaid": true, "pending": true, "paid": true}
 	if !allowedStatus[status] {
 		return errors.New("invalid payment status")
 	}
 
-	return s.repo.ParticipantRepo.UpdatePaymentStatus(participantID, status)
+	if err := s.repo.ParticipantRepo.UpdatePaymentStatus(participantID, status, version); err != nil {
+		return err
+	}
+	s.audit.Record(actorID, AuditPaymentStatusChanged, AuditEntityParticipant, participantID, map[string]interface{}{
+		"payment_status": status,
+	})
+	return nil
 }
@@ -0,0 +1,2076 @@
+package services
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/whatsapp"
+	"event-management-backend/internal/xlsx"
+
+	"github.com/google/uuid"
+)
+
+func TestRegisterParticipant(t *testing.T) {
+	quota := 1
+
+	tests := []struct {
+		name string
+		// event is nil to register for an unknown event
+		event       func(e *testEnv) *models.Event
+		req         RegisterParticipantRequest
+		wantErr     string
+		wantPayment string
+		wantStatus  string
+		wantQR      bool
+	}{
+		{
+			name:        "free event",
+			event:       func(e *testEnv) *models.Event { return e.fx.Event() },
+			req:         RegisterParticipantRequest{Name: "Ani", Email: "ani@example.com"},
+			wantPayment: "paid",
+			wantStatus:  ApprovalApproved,
+			wantQR:      true,
+		},
+		{
+			name: "paid event",
+			event: func(e *testEnv) *models.Event {
+				return e.fx.Event(func(ev *models.Event) { ev.TicketPrice = 50000 })
+			},
+			req:         RegisterParticipantRequest{Name: "Ani", Email: "ani@example.com"},
+			wantPayment: "pending",
+			wantStatus:  ApprovalApproved,
+			wantQR:      true,
+		},
+		{
+			name: "event requiring approval",
+			event: func(e *testEnv) *models.Event {
+				return e.fx.Event(func(ev *models.Event) { ev.RequiresApproval = true })
+			},
+			req:         RegisterParticipantRequest{Name: "Ani", Email: "ani@example.com"},
+			wantPayment: "paid",
+			wantStatus:  ApprovalPending,
+		},
+		{
+			name:    "unknown event",
+			req:     RegisterParticipantRequest{Name: "Ani", Email: "ani@example.com"},
+			wantErr: "event not found",
+		},
+		{
+			name:    "invalid meal preference",
+			event:   func(e *testEnv) *models.Event { return e.fx.Event() },
+			req:     RegisterParticipantRequest{Name: "Ani", Email: "ani@example.com", MealPreference: "carnivore"},
+			wantErr: "invalid meal preference",
+		},
+		{
+			name: "email already registered",
+			event: func(e *testEnv) *models.Event {
+				event := e.fx.Event()
+				e.fx.Participant(event, func(p *models.Participant) { p.Email = "ani@example.com" })
+				return event
+			},
+			req:     RegisterParticipantRequest{Name: "Ani", Email: "ani@example.com"},
+			wantErr: "email already registered for this event",
+		},
+		{
+			name: "quota exceeded",
+			event: func(e *testEnv) *models.Event {
+				event := e.fx.Event(func(ev *models.Event) { ev.TicketQuota = &quota })
+				e.fx.Participant(event)
+				return event
+			},
+			req:     RegisterParticipantRequest{Name: "Ani", Email: "ani@example.com"},
+			wantErr: "ticket quota exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			req := tt.req
+			if tt.event != nil {
+				req.EventID = tt.event(e).ID.String()
+			} else {
+				req.EventID = e.fx.User("staff").ID.String()
+			}
+
+			res, err := NewParticipantService(e.repo, e.cfg).RegisterParticipant(req)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			p := res.Participant
+			if p.PaymentStatus != tt.wantPayment || p.ApprovalStatus != tt.wantStatus {
+				t.Fatalf("payment %q, approval %q; want %q, %q", p.PaymentStatus, p.ApprovalStatus, tt.wantPayment, tt.wantStatus)
+			}
+			if p.MealPreference != MealRegular {
+				t.Fatalf("meal preference = %q, want %q", p.MealPreference, MealRegular)
+			}
+			if (res.QRPath != "") != tt.wantQR {
+				t.Fatalf("QR path = %q, want one: %v", res.QRPath, tt.wantQR)
+			}
+			if tt.wantQR {
+				if _, err := os.Stat(filepath.Join(e.cfg.QRDir, filepath.Base(res.QRPath))); err != nil {
+					t.Fatalf("QR code not written: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestListParticipantFilters(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event(func(ev *models.Event) { ev.TicketPrice = 50 })
+	lastWeek := time.Now().AddDate(0, 0, -7)
+	e.fx.Participant(event, func(p *models.Participant) {
+		p.Name, p.Division, p.PaymentStatus = "Alice Smith", "Finance", "paid"
+	})
+	e.fx.Participant(event, func(p *models.Participant) {
+		p.Name, p.Email, p.Phone, p.PaymentStatus = "Bob", "bob@example.com", "+62 812-000-111", "pending"
+	})
+	e.fx.Participant(event, func(p *models.Participant) { p.Name, p.CreatedAt = "Carol", lastWeek })
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	tests := []struct {
+		name    string
+		filters repositories.ParticipantFilters
+		want    []string
+		wantErr error
+	}{
+		{name: "part of the name", filters: repositories.ParticipantFilters{Search: "alice"}, want: []string{"Alice Smith"}},
+		{name: "whole email", filters: repositories.ParticipantFilters{Search: "BOB@example.com"}, want: []string{"Bob"}},
+		{name: "phone digits", filters: repositories.ParticipantFilters{Search: "62812000111"}, want: []string{"Bob"}},
+		{name: "part of the email", filters: repositories.ParticipantFilters{Search: "example.com"}, want: []string{}},
+		{name: "payment status", filters: repositories.ParticipantFilters{PaymentStatus: "pending"}, want: []string{"Bob"}},
+		{name: "division", filters: repositories.ParticipantFilters{Division: "finance"}, want: []string{"Alice Smith"}},
+		{name: "registered after", filters: repositories.ParticipantFilters{RegisteredAfter: &yesterday}, want: []string{"Alice Smith", "Bob"}},
+		{name: "registered before", filters: repositories.ParticipantFilters{RegisteredBefore: &yesterday}, want: []string{"Carol"}},
+		{name: "unknown payment status", filters: repositories.ParticipantFilters{PaymentStatus: "refunded"}, wantErr: ErrInvalidParticipantFilter},
+		{name: "empty range", filters: repositories.ParticipantFilters{RegisteredAfter: &yesterday, RegisteredBefore: &lastWeek}, wantErr: ErrInvalidParticipantFilter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			participants, total, _, err := svc.ListParticipants(event.ID.String(), 1, 20, &tt.filters)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			names := []string{}
+			for _, participant := range participants {
+				names = append(names, participant.Name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.want) || total != int64(len(tt.want)) {
+				t.Fatalf("listed %v (total %d), want %v", names, total, tt.want)
+			}
+
+			cursorPage, _, err := svc.ListParticipantsByCursor(event.ID.String(), "", 20, &tt.filters)
+			if err != nil || len(cursorPage) != len(tt.want) {
+				t.Fatalf("cursor listing returned %d participants (%v), want %d", len(cursorPage), err, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestExportParticipants(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event()
+	action := e.fx.Action(e.fx.Day(event))
+	staff := e.fx.User("staff")
+	alice := e.fx.Participant(event, func(p *models.Participant) {
+		p.Name, p.Email, p.Division, p.PaymentStatus = "Alice", "alice@example.com", "Finance", "paid"
+	})
+	e.fx.Participant(event, func(p *models.Participant) { p.Name, p.Division = "Bob", "Sales" })
+	e.fx.Verification(alice, action, staff)
+
+	tests := []struct {
+		name    string
+		filters *repositories.ParticipantFilters
+		want    map[string]string // name -> verifications
+	}{
+		{name: "every participant", want: map[string]string{"Alice": "1", "Bob": "0"}},
+		{name: "filtered", filters: &repositories.ParticipantFilters{Division: "sales"}, want: map[string]string{"Bob": "0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			export, err := svc.ExportParticipants(event.ID.String(), tt.filters)
+			if err != nil {
+				t.Fatalf("ExportParticipants: %v", err)
+			}
+			var out bytes.Buffer
+			if err := export.Write(&out); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			records, err := csv.NewReader(&out).ReadAll()
+			if err != nil {
+				t.Fatalf("export is not CSV: %v", err)
+			}
+			if !reflect.DeepEqual(records[0], participantExportHeader) {
+				t.Fatalf("header = %v", records[0])
+			}
+			got := map[string]string{}
+			for _, record := range records[1:] {
+				got[record[1]] = record[8]
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("exported %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := svc.ExportParticipants("00000000-0000-0000-0000-000000000000", nil); !errors.Is(err, ErrUnknownEvent) {
+		t.Fatalf("unknown event error = %v, want %v", err, ErrUnknownEvent)
+	}
+}
+
+func TestExportParticipantsXLSX(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event()
+	opening := e.fx.Day(event, func(d *models.EventDay) { d.Label = "Opening" })
+	e.fx.Day(event)
+	registration := e.fx.Action(opening, func(a *models.EventAction) { a.Name = "Registration" })
+	lunch := e.fx.Action(opening, func(a *models.EventAction) { a.Name = "Lunch" })
+	staff := e.fx.User("staff")
+	alice := e.fx.Participant(event, func(p *models.Participant) { p.Name, p.Division = "Alice", "Finance" })
+	e.fx.Participant(event, func(p *models.Participant) { p.Name = "Bob" })
+
+	arrival := time.Date(2026, 3, 2, 8, 30, 0, 0, time.UTC)
+	e.fx.Verification(alice, registration, staff, func(l *models.ActionLog) { l.VerifiedAt = arrival })
+	e.fx.Verification(alice, lunch, staff, func(l *models.ActionLog) { l.VerifiedAt = arrival.Add(4 * time.Hour) })
+
+	export, err := svc.ExportParticipants(event.ID.String(), nil)
+	if err != nil {
+		t.Fatalf("ExportParticipants: %v", err)
+	}
+	var out bytes.Buffer
+	if err := export.WriteXLSX(&out); err != nil {
+		t.Fatalf("WriteXLSX: %v", err)
+	}
+	sheets, err := xlsx.ReadSheets(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatalf("export is not a workbook: %v", err)
+	}
+
+	names := []string{}
+	for _, sheet := range sheets {
+		names = append(names, sheet.Name)
+	}
+	if want := []string{"Participants", "Day 1 - Opening", "Day 2"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("sheets = %v, want %v", names, want)
+	}
+	if rows := sheets[0].Rows; len(rows) != 3 || !reflect.DeepEqual(rows[0], participantExportHeader) {
+		t.Fatalf("participants sheet = %q", rows)
+	}
+
+	want := [][]string{
+		attendanceExportHeader,
+		{alice.ID.String(), "Alice", alice.Email, "Finance", "Registration, Lunch", "2026-03-02T08:30:00Z", "2026-03-02T12:30:00Z"},
+	}
+	if !reflect.DeepEqual(sheets[1].Rows, want) {
+		t.Fatalf("day sheet = %q, want %q", sheets[1].Rows, want)
+	}
+	if len(sheets[2].Rows) != 1 {
+		t.Fatalf("day without scans lists %q", sheets[2].Rows)
+	}
+}
+
+func TestImportParticipantsCSV(t *testing.T) {
+	quota := 2
+
+	tests := []struct {
+		name        string
+		quota       *int
+		existing    string
+		rows        [][]string
+		wantSuccess int
+		wantErrors  []string
+	}{
+		{
+			name: "valid rows",
+			rows: [][]string{
+				{"Ani", "ani@example.com", "0811", "IT", "Jakarta"},
+				{"Budi", "budi@example.com", "0812", "HR", "Bandung", "vegan"},
+			},
+			wantSuccess: 2,
+		},
+		{
+			name: "invalid rows",
+			rows: [][]string{
+				{"Ani", "ani@example.com", "0811"},
+				{"", "ani@example.com", "0811", "IT", "Jakarta"},
+				{"Ani", "not-an-email", "0811", "IT", "Jakarta"},
+				{"Ani", "ani@example.com", "0811", "IT", "Jakarta", "carnivore"},
+				{"Budi", "budi@example.com", "0812", "HR", "Bandung"},
+			},
+			wantSuccess: 1,
+			wantErrors: []string{
+				"Row 1: insufficient data",
+				"Row 2: name is required",
+				"Row 3: invalid email",
+				"Row 4: invalid meal preference",
+			},
+		},
+		{
+			name:     "duplicates",
+			existing: "ani@example.com",
+			rows: [][]string{
+				{"Ani", "ani@example.com", "0811", "IT", "Jakarta"},
+				{"Budi", "budi@example.com", "0812", "HR", "Bandung"},
+				{"Budi", "budi@example.com", "0812", "HR", "Bandung"},
+			},
+			wantSuccess: 1,
+			wantErrors: []string{
+				"Row 1: email already registered for this event",
+				"Row 3: email already registered for this event",
+			},
+		},
+		{
+			name:     "quota",
+			quota:    &quota,
+			existing: "ani@example.com",
+			rows: [][]string{
+				{"Budi", "budi@example.com", "0812", "HR", "Bandung"},
+				{"Cici", "cici@example.com", "0813", "HR", "Bandung"},
+			},
+			wantSuccess: 1,
+			wantErrors:  []string{"Row 2: ticket quota exceeded"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			event := e.fx.Event(func(ev *models.Event) { ev.TicketQuota = tt.quota })
+			if tt.existing != "" {
+				e.fx.Participant(event, func(p *models.Participant) { p.Email = tt.existing })
+			}
+
+			success, fail, errs, err := NewParticipantService(e.repo, e.cfg).ImportParticipantsCSV(event.ID.String(), tt.rows)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if success != tt.wantSuccess || fail != len(tt.wantErrors) {
+				t.Fatalf("success %d, fail %d; want %d, %d (%v)", success, fail, tt.wantSuccess, len(tt.wantErrors), errs)
+			}
+			if len(errs) != len(tt.wantErrors) {
+				t.Fatalf("errors = %v, want %v", errs, tt.wantErrors)
+			}
+			for i := range errs {
+				if errs[i] != tt.wantErrors[i] {
+					t.Fatalf("errors = %v, want %v", errs, tt.wantErrors)
+				}
+			}
+
+			count, err := e.repo.ParticipantRepo.GetParticipantCountByEventID(event.ID.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := int64(tt.wantSuccess)
+			if tt.existing != "" {
+				want++
+			}
+			if count != want {
+				t.Fatalf("event has %d participants, want %d", count, want)
+			}
+		})
+	}
+
+	t.Run("unknown event", func(t *testing.T) {
+		e := newTestEnv(t)
+		rows := [][]string{{"Ani", "ani@example.com", "0811", "IT", "Jakarta"}}
+
+		success, fail, errs, err := NewParticipantService(e.repo, e.cfg).ImportParticipantsCSV(e.fx.User("staff").ID.String(), rows)
+		if err != nil || success != 0 || fail != 1 || errs[0] != "Row 1: event not found" {
+			t.Fatalf("got %d, %d, %v, %v", success, fail, errs, err)
+		}
+	})
+}
+
+func TestParticipantImportJob(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event()
+	organizer := e.fx.User("organizer")
+
+	file := "name,email,phone,division,address\n" +
+		"Ani,ani@example.com,0811,IT,Jakarta\n" +
+		"Budi,not-an-email,0812,HR,Bandung\n" +
+		"Cici,cici@example.com,0813,HR,Bandung\n"
+	job, err := svc.StartParticipantImport(organizer.ID.String(), event.ID.String(), "people.csv", ImportFormatCSV, nil, false, strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("StartParticipantImport: %v", err)
+	}
+	if job.Status != "pending" {
+		t.Fatalf("new import is %s, want pending", job.Status)
+	}
+
+	// Shutting down leaves the import to be resumed
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := svc.RunParticipantImport(ctx, job.ID.String()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled run error = %v, want %v", err, context.Canceled)
+	}
+
+	if err := svc.RunParticipantImport(context.Background(), job.ID.String()); err != nil {
+		t.Fatalf("RunParticipantImport: %v", err)
+	}
+	job, err = svc.GetImportJob(job.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != "completed" || job.TotalRows != 3 || job.ProcessedRows != 3 || job.Imported != 2 || job.Failed != 1 {
+		t.Fatalf("finished import = %+v", job)
+	}
+	if want := []models.ImportRowError{{Row: 2, Message: "invalid email"}}; !reflect.DeepEqual(job.RowErrors, want) {
+		t.Fatalf("row errors = %v, want %v", job.RowErrors, want)
+	}
+	if _, err := os.Stat(job.FilePath); !os.IsNotExist(err) {
+		t.Fatalf("import file kept after the import: %v", err)
+	}
+	var report bytes.Buffer
+	if err := WriteImportErrorReport(job, &report); err != nil || report.String() != "row,error\n2,invalid email\n" {
+		t.Fatalf("error report = %q (%v)", report.String(), err)
+	}
+
+	// A file that cannot be read fails the import without a retry
+	job, err = svc.StartParticipantImport(organizer.ID.String(), event.ID.String(), "people.xlsx", ImportFormatXLSX, nil, false, strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("StartParticipantImport: %v", err)
+	}
+	if err := svc.RunParticipantImport(context.Background(), job.ID.String()); err != nil {
+		t.Fatalf("RunParticipantImport: %v", err)
+	}
+	if job, _ = svc.GetImportJob(job.ID.String()); job.Status != "failed" || job.Error != "invalid XLSX file" {
+		t.Fatalf("unreadable import is %s (%q), want failed", job.Status, job.Error)
+	}
+
+	if _, err := svc.StartParticipantImport(organizer.ID.String(), uuid.NewString(), "people.csv", ImportFormatCSV, nil, false, strings.NewReader(file)); !errors.Is(err, ErrUnknownEvent) {
+		t.Fatalf("unknown event error = %v, want %v", err, ErrUnknownEvent)
+	}
+}
+
+func TestParticipantImportJobStreaming(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event()
+	organizer := e.fx.User("organizer")
+
+	// A duplicate in the chunk after its original, and a short row, which
+	// used to fail the whole CSV
+	var file strings.Builder
+	file.WriteString("name,email,phone,division,address\n")
+	for i := 1; i <= importChunkSize; i++ {
+		fmt.Fprintf(&file, "Guest %d,guest%d@example.com,0811,IT,Jakarta\n", i, i)
+	}
+	file.WriteString("\nGuest 1,GUEST1@example.com,0811,IT,Jakarta\nShort,short@example.com\n")
+
+	job, err := svc.StartParticipantImport(organizer.ID.String(), event.ID.String(), "guests.csv", ImportFormatCSV, nil, false, strings.NewReader(file.String()))
+	if err != nil {
+		t.Fatalf("StartParticipantImport: %v", err)
+	}
+	if err := svc.RunParticipantImport(context.Background(), job.ID.String()); err != nil {
+		t.Fatalf("RunParticipantImport: %v", err)
+	}
+	job, _ = svc.GetImportJob(job.ID.String())
+	want := []models.ImportRowError{
+		{Row: importChunkSize + 1, Message: "email already registered for this event"},
+		{Row: importChunkSize + 2, Message: "insufficient data"},
+	}
+	if job.Status != "completed" || job.TotalRows != importChunkSize+2 || job.Imported != importChunkSize || !reflect.DeepEqual(job.RowErrors, want) {
+		t.Fatalf("finished import = %s, %d rows, %d imported, errors %v", job.Status, job.TotalRows, job.Imported, job.RowErrors)
+	}
+
+	// A resumed import skips the rows already processed
+	resumed, err := svc.StartParticipantImport(organizer.ID.String(), event.ID.String(), "late.csv", ImportFormatCSV, nil, false,
+		strings.NewReader("name,email,phone,division,address\nEarly,early@example.com,0811,IT,Jakarta\nLate,late@example.com,0811,IT,Jakarta\n"))
+	if err != nil {
+		t.Fatalf("StartParticipantImport: %v", err)
+	}
+	resumed.Status, resumed.ProcessedRows = "running", 1
+	if err := e.repo.ImportJobRepo.UpdateImportJob(resumed); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.RunParticipantImport(context.Background(), resumed.ID.String()); err != nil {
+		t.Fatalf("RunParticipantImport: %v", err)
+	}
+	if _, err := e.repo.ParticipantRepo.GetParticipantByEmailAndEvent("early@example.com", event.ID.String()); err == nil {
+		t.Fatal("row processed before the restart was imported again")
+	}
+	if _, err := e.repo.ParticipantRepo.GetParticipantByEmailAndEvent("late@example.com", event.ID.String()); err != nil {
+		t.Fatalf("row after the restart was not imported: %v", err)
+	}
+}
+
+func TestParticipantImportColumns(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event()
+	organizer := e.fx.User("organizer")
+	run := func(file string, mapping map[string]string) *models.ImportJob {
+		t.Helper()
+		job, err := svc.StartParticipantImport(organizer.ID.String(), event.ID.String(), "people.csv", ImportFormatCSV, mapping, false, strings.NewReader(file))
+		if err != nil {
+			t.Fatalf("StartParticipantImport: %v", err)
+		}
+		if err := svc.RunParticipantImport(context.Background(), job.ID.String()); err != nil {
+			t.Fatalf("RunParticipantImport: %v", err)
+		}
+		job, _ = svc.GetImportJob(job.ID.String())
+		return job
+	}
+
+	// Columns in any order, optional ones left out and unknown ones ignored
+	job := run("\ufeffNotes,E-mail,Full Name\nVIP,ani@example.com,Ani\n", nil)
+	if job.Status != "completed" || job.Imported != 1 {
+		t.Fatalf("import = %s, %d imported, %v", job.Status, job.Imported, job.RowErrors)
+	}
+	ani, err := e.repo.ParticipantRepo.GetParticipantByEmailAndEvent("ani@example.com", event.ID.String())
+	if err != nil || ani.Name != "Ani" || ani.Phone != "" {
+		t.Fatalf("imported %+v (%v)", ani, err)
+	}
+
+	job = run("Nama,Surel,Divisi\nBudi,budi@example.com,HR\n", map[string]string{"name": "nama", "email": "Surel", "division": "Divisi"})
+	if job.Status != "completed" || job.Imported != 1 {
+		t.Fatalf("mapped import = %s, %d imported, %v", job.Status, job.Imported, job.RowErrors)
+	}
+	if budi, err := e.repo.ParticipantRepo.GetParticipantByEmailAndEvent("budi@example.com", event.ID.String()); err != nil || budi.Division != "HR" {
+		t.Fatalf("imported %+v (%v)", budi, err)
+	}
+
+	for _, tt := range []struct {
+		file    string
+		mapping map[string]string
+		want    string
+	}{
+		{"Nama,Surel\nCici,cici@example.com\n", nil, "header row has no name column; name a column name or map one to it"},
+		{"name,email\nCici,cici@example.com\n", map[string]string{"phone": "Telepon"}, `column "Telepon" mapped to phone is not in the header row`},
+		{"name,email,Email\nCici,cici@example.com,c@example.com\n", nil, "header row has more than one email column"},
+	} {
+		if job := run(tt.file, tt.mapping); job.Status != "failed" || job.Error != tt.want {
+			t.Fatalf("import of %q = %s (%q), want failed with %q", tt.file, job.Status, job.Error, tt.want)
+		}
+	}
+
+	if _, err := svc.StartParticipantImport(organizer.ID.String(), event.ID.String(), "people.csv", ImportFormatCSV, map[string]string{"shoe_size": "Size"}, false, strings.NewReader("name,email\n")); !errors.Is(err, ErrInvalidColumnMapping) {
+		t.Fatalf("unknown field error = %v, want %v", err, ErrInvalidColumnMapping)
+	}
+}
+
+func TestParticipantImportDryRun(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	quota := importChunkSize + 2
+	event := e.fx.Event(func(ev *models.Event) { ev.TicketQuota = &quota })
+	organizer := e.fx.User("organizer")
+	existing := e.fx.Participant(event)
+
+	// Duplicates and the quota are checked across chunks, against the rows
+	// accepted before as well as the database
+	var file strings.Builder
+	file.WriteString("name,email\n")
+	fmt.Fprintf(&file, "Again,%s\n", existing.Email)
+	for i := 2; i <= importChunkSize; i++ {
+		fmt.Fprintf(&file, "Guest %d,guest%d@example.com\n", i, i)
+	}
+	file.WriteString("Bad,not-an-email\nGuest 2,guest2@example.com\nNew A,a@example.com\nNew B,b@example.com\nNew C,c@example.com\n")
+	want := []models.ImportRowError{
+		{Row: 1, Message: "email already registered for this event"},
+		{Row: importChunkSize + 1, Message: "invalid email"},
+		{Row: importChunkSize + 2, Message: "email already registered for this event"},
+		{Row: importChunkSize + 5, Message: "ticket quota exceeded"},
+	}
+
+	run := func(dryRun bool) *models.ImportJob {
+		t.Helper()
+		job, err := svc.StartParticipantImport(organizer.ID.String(), event.ID.String(), "guests.csv", ImportFormatCSV, nil, dryRun, strings.NewReader(file.String()))
+		if err != nil {
+			t.Fatalf("StartParticipantImport: %v", err)
+		}
+		if err := svc.RunParticipantImport(context.Background(), job.ID.String()); err != nil {
+			t.Fatalf("RunParticipantImport: %v", err)
+		}
+		job, _ = svc.GetImportJob(job.ID.String())
+		return job
+	}
+
+	job := run(true)
+	if job.Status != "completed" || !job.DryRun || job.Imported != importChunkSize+1 || job.Failed != len(want) || !reflect.DeepEqual(job.RowErrors, want) {
+		t.Fatalf("dry run = %s, %d imported, %d failed, errors %v", job.Status, job.Imported, job.Failed, job.RowErrors)
+	}
+	if count, _ := e.repo.ParticipantRepo.GetParticipantCountByEventID(event.ID.String()); count != 1 {
+		t.Fatalf("dry run registered %d participants", count-1)
+	}
+
+	// The import itself then does as the dry run said
+	job = run(false)
+	if job.Imported != importChunkSize+1 || !reflect.DeepEqual(job.RowErrors, want) {
+		t.Fatalf("import = %d imported, errors %v", job.Imported, job.RowErrors)
+	}
+}
+
+func TestParticipantImportQuotaRules(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	events := NewEventService(e.repo, e.cfg)
+	event := e.fx.Event()
+	organizer := e.fx.User("organizer")
+	e.fx.Participant(event, func(p *models.Participant) { p.Division = "Finance" })
+	if _, err := events.CreateQuotaRule(event.ID.String(), QuotaRuleRequest{Name: "Finance", Division: "Finance", MaxRegistrations: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The last Finance seat goes to the first chunk; the rule is still full
+	// in the next one
+	var file strings.Builder
+	file.WriteString("name,email,division\n")
+	file.WriteString("Guest 1,guest1@example.com,Finance\n")
+	for i := 2; i <= importChunkSize; i++ {
+		fmt.Fprintf(&file, "Guest %d,guest%d@example.com,Sales\n", i, i)
+	}
+	file.WriteString("Late,late@example.com,finance\n")
+	want := []models.ImportRowError{{Row: importChunkSize + 1, Message: "quota reached: Finance"}}
+
+	for _, dryRun := range []bool{true, false} {
+		job, err := svc.StartParticipantImport(organizer.ID.String(), event.ID.String(), "guests.csv", ImportFormatCSV, nil, dryRun, strings.NewReader(file.String()))
+		if err != nil {
+			t.Fatalf("StartParticipantImport: %v", err)
+		}
+		if err := svc.RunParticipantImport(context.Background(), job.ID.String()); err != nil {
+			t.Fatalf("RunParticipantImport: %v", err)
+		}
+		job, _ = svc.GetImportJob(job.ID.String())
+		if job.Imported != importChunkSize || !reflect.DeepEqual(job.RowErrors, want) {
+			t.Fatalf("import (dry run %v) = %d imported, errors %v", dryRun, job.Imported, job.RowErrors)
+		}
+	}
+	usage, err := events.ListQuotaRules(event.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage[0].Registered != 2 {
+		t.Fatalf("Finance registrations = %d, want 2", usage[0].Registered)
+	}
+}
+
+func TestDuplicateParticipants(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event()
+	staff := e.fx.User("staff")
+	day := e.fx.Day(event)
+	opening, lunch := e.fx.Action(day), e.fx.Action(day)
+
+	registered := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int, email, phone string) func(*models.Participant) {
+		return func(p *models.Participant) {
+			p.CreatedAt = registered.Add(time.Duration(minutes) * time.Minute)
+			p.Email, p.Phone, p.Division = email, phone, ""
+		}
+	}
+	kept := e.fx.Participant(event, at(0, "Dewi.Lestari@gmail.com", "081200000001"))
+	sameEmail := e.fx.Participant(event, at(1, "dewilestari+events@googlemail.com", "+62 813 1111 2222"), func(p *models.Participant) { p.Division = "IT" })
+	samePhone := e.fx.Participant(event, at(2, "dewi@example.com", "0813-1111-2222"))
+	other := e.fx.Participant(event, at(3, "budi@example.com", "081200000009"), func(p *models.Participant) { p.CancelledAt = &registered })
+	// Erased participants are not reported
+	e.fx.Participant(event, at(4, "budi@example.com", ""), func(p *models.Participant) { p.AnonymizedAt = &registered })
+	e.fx.Participant(e.fx.Event(), at(5, "dewi.lestari@gmail.com", ""))
+
+	groups, err := svc.FindDuplicateParticipants(event.ID.String())
+	if err != nil {
+		t.Fatalf("FindDuplicateParticipants: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("found %d groups, want 1: %+v", len(groups), groups)
+	}
+	var ids []uuid.UUID
+	for _, p := range groups[0].Participants {
+		ids = append(ids, p.ID)
+	}
+	if want := []uuid.UUID{kept.ID, sameEmail.ID, samePhone.ID}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("group = %v, want %v", ids, want)
+	}
+	if want := []string{"email", "phone"}; !reflect.DeepEqual(groups[0].MatchedOn, want) {
+		t.Fatalf("matched on %v, want %v", groups[0].MatchedOn, want)
+	}
+
+	// Scans of an action both were scanned at are dropped, others move over
+	e.fx.Verification(kept, opening, staff)
+	e.fx.Verification(sameEmail, opening, staff)
+	e.fx.Verification(sameEmail, lunch, staff)
+	result, err := svc.MergeParticipants(staff.ID.String(), kept.ID.String(), sameEmail.ID.String())
+	if err != nil {
+		t.Fatalf("MergeParticipants: %v", err)
+	}
+	if c := result.Counts; c.ActionLogsMoved != 1 || c.ActionLogsDropped != 1 {
+		t.Fatalf("merge counts = %+v", c)
+	}
+	if result.Participant.Division != "IT" || result.Participant.QRPath != kept.QRPath || result.Participant.Version != kept.Version+1 {
+		t.Fatalf("kept participant = %+v", result.Participant)
+	}
+	logs, _ := e.repo.ActionRepo.GetActionLogsByParticipant(kept.ID.String())
+	if len(logs) != 2 {
+		t.Fatalf("kept participant has %d scans, want 2", len(logs))
+	}
+	if _, err := svc.GetParticipant(sameEmail.ID.String()); err == nil {
+		t.Fatal("merged duplicate still exists")
+	}
+
+	for _, tc := range []struct {
+		name, keep, duplicate string
+		want                  error
+	}{
+		{"merged duplicate", kept.ID.String(), sameEmail.ID.String(), ErrUnknownParticipant},
+		{"itself", kept.ID.String(), kept.ID.String(), ErrMergeSelf},
+		{"other event", kept.ID.String(), e.fx.Participant(e.fx.Event()).ID.String(), ErrMergeOtherEvent},
+		{"cancelled", other.ID.String(), samePhone.ID.String(), ErrMergeCancelled},
+	} {
+		_, err := svc.MergeParticipants(staff.ID.String(), tc.keep, tc.duplicate)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("%s: error = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+type recordingMessenger struct {
+	sent []whatsapp.Message
+}
+
+func (s *recordingMessenger) Send(_ context.Context, msg whatsapp.Message) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestResendTicket(t *testing.T) {
+	e := newTestEnv(t)
+	mailer, messenger := &recordingSender{}, &recordingMessenger{}
+	svc := NewNotificationService(e.repo, mailer, messenger, e.cfg)
+	event := e.fx.Event()
+	staff := e.fx.User("staff")
+	participant := e.fx.Participant(event, func(p *models.Participant) { p.Phone = "0812-3456-7890" })
+
+	// The fixture's QR image was never written, so it is rendered again
+	delivery, err := svc.ResendTicket(context.Background(), staff.ID.String(), participant.ID.String())
+	if err != nil {
+		t.Fatalf("ResendTicket: %v", err)
+	}
+	if *delivery != (TicketDelivery{Channel: TicketChannelEmail, To: participant.Email}) || len(mailer.sent) != 1 {
+		t.Fatalf("delivery = %+v, %d emails", delivery, len(mailer.sent))
+	}
+	attachments := mailer.sent[0].Attachments
+	if len(attachments) != 1 || !bytes.HasPrefix(attachments[0].Data, []byte("\x89PNG")) {
+		t.Fatalf("attachments = %d, want the QR code", len(attachments))
+	}
+	if _, err := os.Stat(filepath.Join(e.cfg.QRDir, filepath.Base(participant.QRPath))); err != nil {
+		t.Fatalf("QR image not rendered again: %v", err)
+	}
+
+	e.cfg.TicketChannel, e.cfg.WhatsAppDefaultCountryCode = TicketChannelWhatsApp, "62"
+	delivery, err = svc.ResendTicket(context.Background(), staff.ID.String(), participant.ID.String())
+	if err != nil {
+		t.Fatalf("ResendTicket: %v", err)
+	}
+	if delivery.Channel != TicketChannelWhatsApp || len(messenger.sent) != 1 || messenger.sent[0].To != "6281234567890" || len(messenger.sent[0].Image) == 0 {
+		t.Fatalf("delivery = %+v, messages = %+v", delivery, messenger.sent)
+	}
+	// Participants without a phone number get an email
+	noPhone := e.fx.Participant(event, func(p *models.Participant) { p.Phone = "" })
+	if delivery, err := svc.ResendTicket(context.Background(), staff.ID.String(), noPhone.ID.String()); err != nil || delivery.Channel != TicketChannelEmail {
+		t.Fatalf("delivery without phone = %+v (%v), want email", delivery, err)
+	}
+
+	now := time.Now()
+	for _, tc := range []struct {
+		name        string
+		participant func(*models.Participant)
+		want        error
+	}{
+		{"cancelled", func(p *models.Participant) { p.CancelledAt = &now }, ErrTicketCancelled},
+		{"awaiting approval", func(p *models.Participant) { p.QRPath, p.ApprovalStatus = "", ApprovalPending }, ErrTicketNotIssued},
+	} {
+		p := e.fx.Participant(event, tc.participant)
+		if _, err := svc.ResendTicket(context.Background(), staff.ID.String(), p.ID.String()); !errors.Is(err, tc.want) {
+			t.Errorf("%s: error = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+	if _, err := svc.ResendTicket(context.Background(), staff.ID.String(), uuid.NewString()); !errors.Is(err, ErrUnknownParticipant) {
+		t.Errorf("unknown participant: error = %v, want %v", err, ErrUnknownParticipant)
+	}
+}
+
+func TestLookupTicket(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event()
+	participant := e.fx.Participant(event)
+	code := TicketCode(e.cfg.CredentialSigningKey, participant.ID)
+
+	lookup, err := svc.LookupTicket(event.Slug, strings.ToUpper(participant.Email), strings.ToLower(code))
+	if err != nil {
+		t.Fatalf("LookupTicket: %v", err)
+	}
+	if lookup.Status != TicketConfirmed || lookup.QRPath != participant.QRPath || lookup.EventTitle != event.Title {
+		t.Fatalf("lookup = %+v", lookup)
+	}
+
+	// Without the code only the participant is identified, to send the ticket
+	lookup, err = svc.LookupTicket(event.Slug, participant.Email, "")
+	if !errors.Is(err, ErrTicketCodeMismatch) || lookup.ParticipantID != participant.ID || lookup.QRPath != "" {
+		t.Fatalf("lookup without code = %+v (%v)", lookup, err)
+	}
+	if _, err := svc.LookupTicket(event.Slug, participant.Email, TicketCode(e.cfg.CredentialSigningKey, uuid.New())); !errors.Is(err, ErrTicketCodeMismatch) {
+		t.Fatalf("wrong code: error = %v, want %v", err, ErrTicketCodeMismatch)
+	}
+	for _, tc := range []struct{ name, slug, email string }{
+		{"unknown email", event.Slug, "nobody@example.com"},
+		{"other event", e.fx.Event().Slug, participant.Email},
+		{"unknown event", "no-such-event", participant.Email},
+	} {
+		if _, err := svc.LookupTicket(tc.slug, tc.email, code); !errors.Is(err, ErrTicketNotFound) {
+			t.Errorf("%s: error = %v, want %v", tc.name, err, ErrTicketNotFound)
+		}
+	}
+
+	pending := e.fx.Participant(event, func(p *models.Participant) { p.QRPath, p.ApprovalStatus = "", ApprovalPending })
+	lookup, err = svc.LookupTicket(event.Slug, pending.Email, TicketCode(e.cfg.CredentialSigningKey, pending.ID))
+	if err != nil || lookup.Status != TicketPendingApproval || lookup.QRPath != "" {
+		t.Fatalf("pending lookup = %+v (%v)", lookup, err)
+	}
+}
+
+func TestRegistrationWindow(t *testing.T) {
+	e := newTestEnv(t)
+	events := NewEventService(e.repo, e.cfg)
+	participants := NewParticipantService(e.repo, e.cfg)
+	now := time.Now()
+	later, earlier := now.Add(time.Hour), now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		event   func(*models.Event)
+		state   string
+		wantErr error
+	}{
+		{"no window", func(ev *models.Event) {}, RegistrationOpen, nil},
+		{"not open yet", func(ev *models.Event) { ev.RegistrationOpensAt = &later }, RegistrationUpcoming, ErrRegistrationNotOpen},
+		{"closed", func(ev *models.Event) { ev.RegistrationClosesAt = &earlier }, RegistrationClosed, ErrRegistrationClosed},
+		{"event ended", func(ev *models.Event) {
+			ev.StartsAt, ev.EndsAt = now.Add(-48*time.Hour), earlier
+		}, RegistrationClosed, ErrRegistrationClosed},
+		{"open window", func(ev *models.Event) {
+			ev.RegistrationOpensAt, ev.RegistrationClosesAt = &earlier, &later
+		}, RegistrationOpen, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := e.fx.Event(tt.event)
+
+			stored, err := events.GetEvent(event.ID.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.RegistrationState != tt.state {
+				t.Fatalf("state = %q, want %q", stored.RegistrationState, tt.state)
+			}
+
+			_, err = participants.RegisterParticipant(RegisterParticipantRequest{
+				EventID: event.ID.String(),
+				Name:    "Ani",
+				Email:   "ani@example.com",
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("register: error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegisterWithTicketTypes(t *testing.T) {
+	e := newTestEnv(t)
+	events := NewEventService(e.repo, e.cfg)
+	participants := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event()
+	quota := 1
+	ended := time.Now().Add(-time.Hour)
+
+	vip, err := events.CreateTicketType(event.ID.String(), TicketTypeRequest{Name: "VIP", Price: 250000, Quota: &quota})
+	if err != nil {
+		t.Fatal(err)
+	}
+	earlyBird, err := events.CreateTicketType(event.ID.String(), TicketTypeRequest{Name: "Early Bird", Price: 50000, SalesEndAt: &ended})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	register := func(email, ticketTypeID string) (*RegisterParticipantResponse, error) {
+		return participants.RegisterParticipant(RegisterParticipantRequest{
+			EventID:      event.ID.String(),
+			Name:         "Ani",
+			Email:        email,
+			TicketTypeID: ticketTypeID,
+		})
+	}
+
+	if _, err := register("a@example.com", ""); !errors.Is(err, ErrTicketTypeRequired) {
+		t.Fatalf("no ticket type: error = %v, want %v", err, ErrTicketTypeRequired)
+	}
+	if _, err := register("a@example.com", earlyBird.ID.String()); !errors.Is(err, ErrTicketTypeNotOnSale) {
+		t.Fatalf("sale ended: error = %v, want %v", err, ErrTicketTypeNotOnSale)
+	}
+
+	res, err := register("a@example.com", vip.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := res.Participant; p.TicketTypeID == nil || *p.TicketTypeID != vip.ID || p.PaymentStatus != "pending" {
+		t.Fatalf("ticket type %v, payment %q; want %s, pending", p.TicketTypeID, p.PaymentStatus, vip.ID)
+	}
+
+	if _, err := register("b@example.com", vip.ID.String()); !errors.Is(err, ErrTicketTypeSoldOut) {
+		t.Fatalf("quota reached: error = %v, want %v", err, ErrTicketTypeSoldOut)
+	}
+	if err := events.DeleteTicketType(event.ID.String(), vip.ID.String()); !errors.Is(err, ErrTicketTypeSold) {
+		t.Fatalf("delete sold type: error = %v, want %v", err, ErrTicketTypeSold)
+	}
+
+	availability, err := events.ListTicketTypes(event.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(availability) != 2 || availability[0].ID != earlyBird.ID || availability[0].OnSale || !availability[1].SoldOut {
+		t.Fatalf("availability = %+v", availability)
+	}
+}
+
+func TestRegisterConcurrentlyWithinTicketTypeQuota(t *testing.T) {
+	e := newTestEnv(t)
+	events := NewEventService(e.repo, e.cfg)
+	participants := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event()
+	quota := 3
+
+	vip, err := events.CreateTicketType(event.ID.String(), TicketTypeRequest{Name: "VIP", Quota: &quota})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const attempts = 50
+	start := make(chan struct{})
+	errs := make([]error, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = participants.RegisterParticipant(RegisterParticipantRequest{
+				EventID:      event.ID.String(),
+				Name:         "Ani",
+				Email:        fmt.Sprintf("p%d@example.com", i),
+				TicketTypeID: vip.ID.String(),
+			})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	registered := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			registered++
+		case !errors.Is(err, ErrTicketTypeSoldOut):
+			t.Fatalf("concurrent registration: error = %v, want nil or %v", err, ErrTicketTypeSoldOut)
+		}
+	}
+	sold, err := e.repo.ParticipantRepo.CountParticipantsByTicketType(event.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if registered != quota || sold[vip.ID.String()] != int64(quota) {
+		t.Fatalf("%d registered, %d sold; want %d of each", registered, sold[vip.ID.String()], quota)
+	}
+}
+
+func TestQuotaRules(t *testing.T) {
+	e := newTestEnv(t)
+	events := NewEventService(e.repo, e.cfg)
+	participants := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event()
+	eventID := event.ID.String()
+
+	vip, err := events.CreateTicketType(eventID, TicketTypeRequest{Name: "VIP"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	regular, err := events.CreateTicketType(eventID, TicketTypeRequest{Name: "Regular"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := events.CreateQuotaRule(eventID, QuotaRuleRequest{Name: "Anyone", MaxRegistrations: 5}); err == nil {
+		t.Fatal("rule without a ticket type or division was accepted")
+	}
+	finance, err := events.CreateQuotaRule(eventID, QuotaRuleRequest{Name: "Finance", Division: "Finance", MaxRegistrations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := events.CreateQuotaRule(eventID, QuotaRuleRequest{Name: "Finance VIP", TicketTypeID: vip.ID.String(), Division: "finance", MaxRegistrations: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	register := func(division string, ticketType *models.TicketType) error {
+		n++
+		_, err := participants.RegisterParticipant(RegisterParticipantRequest{
+			EventID:      eventID,
+			Name:         "Ani",
+			Email:        fmt.Sprintf("p%d@example.com", n),
+			Division:     division,
+			TicketTypeID: ticketType.ID.String(),
+		})
+		return err
+	}
+
+	if err := register("FINANCE", regular); err != nil {
+		t.Fatal(err)
+	}
+	if err := register("Finance", vip); !errors.Is(err, ErrQuotaRuleReached) {
+		t.Fatalf("division full: error = %v, want %v", err, ErrQuotaRuleReached)
+	}
+	if err := register("Sales", vip); err != nil {
+		t.Fatalf("other division: %v", err)
+	}
+
+	usage, err := events.ListQuotaRules(eventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usage) != 2 || usage[0].ID != finance.ID || usage[0].Registered != 1 || !usage[0].Full || usage[1].Registered != 0 {
+		t.Fatalf("usage = %+v", usage)
+	}
+
+	if _, err := events.UpdateQuotaRule(eventID, finance.ID.String(), QuotaRuleRequest{Name: "Finance", Division: "Finance", MaxRegistrations: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := register("Finance", vip); err != nil {
+		t.Fatalf("after raising the quota: %v", err)
+	}
+	if err := events.DeleteQuotaRule(e.fx.Event().ID.String(), finance.ID.String()); !errors.Is(err, ErrUnknownQuotaRule) {
+		t.Fatalf("delete from another event: error = %v, want %v", err, ErrUnknownQuotaRule)
+	}
+}
+
+func TestTicketPDF(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	// The logo file is missing, so the ticket is printed without it
+	event := e.fx.Event(func(ev *models.Event) { ev.LogoPath = "/logos/missing.png" })
+	day := e.fx.Day(event)
+	session := &models.Session{
+		EventID:    event.ID,
+		EventDayID: day.ID,
+		Title:      "Opening keynote",
+		Room:       "Hall A",
+		StartsAt:   day.Date.Add(9 * time.Hour),
+		EndsAt:     day.Date.Add(10 * time.Hour),
+	}
+	action := &models.EventAction{EventDayID: day.ID, Name: session.Title, Code: "KEYNOTE"}
+	if err := e.repo.SessionRepo.CreateSession(session, action); err != nil {
+		t.Fatal(err)
+	}
+	participant := e.fx.Participant(event)
+
+	ticket, err := svc.TicketPDF(participant.ID.String())
+	if err != nil {
+		t.Fatalf("TicketPDF: %v", err)
+	}
+	if !bytes.HasPrefix(ticket, []byte("%PDF-")) || !bytes.HasSuffix(ticket, []byte("%%EOF\n")) {
+		t.Fatal("ticket is not a PDF file")
+	}
+
+	content := pdfContent(ticket)
+	code := TicketCode(e.cfg.CredentialSigningKey, participant.ID)
+	for _, want := range []string{event.Title, participant.Name, code, "Opening keynote \\(Hall A\\)", "09:00 - 10:00"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("ticket does not show %q", want)
+		}
+	}
+
+	now := time.Now()
+	for _, tc := range []struct {
+		name        string
+		participant func(*models.Participant)
+		want        error
+	}{
+		{"cancelled", func(p *models.Participant) { p.CancelledAt = &now }, ErrTicketCancelled},
+		{"awaiting approval", func(p *models.Participant) { p.QRPath, p.ApprovalStatus = "", ApprovalPending }, ErrTicketNotIssued},
+	} {
+		p := e.fx.Participant(event, tc.participant)
+		if _, err := svc.TicketPDF(p.ID.String()); !errors.Is(err, tc.want) {
+			t.Errorf("%s: error = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+	if _, err := svc.TicketPDF(uuid.NewString()); !errors.Is(err, ErrUnknownParticipant) {
+		t.Errorf("unknown participant: error = %v, want %v", err, ErrUnknownParticipant)
+	}
+}
+
+// pdfContent inflates every stream of a PDF, for tests to look for the text
+// drawn on its pages
+func pdfContent(doc []byte) string {
+	var content bytes.Buffer
+	rest := doc
+	for {
+		start := bytes.Index(rest, []byte("stream\n"))
+		if start < 0 {
+			break
+		}
+		rest = rest[start+len("stream\n"):]
+		end := bytes.Index(rest, []byte("\nendstream"))
+		if zr, err := zlib.NewReader(bytes.NewReader(rest[:end])); err == nil {
+			io.Copy(&content, zr)
+		}
+		rest = rest[end+len("\nendstream"):]
+	}
+	return content.String()
+}
+
+func TestBadgeSheetPDF(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event()
+	now := time.Now()
+	var names []string
+	for i := 0; i < 5; i++ {
+		p := e.fx.Participant(event, func(p *models.Participant) { p.Division = "Engineering" })
+		names = append(names, p.Name)
+	}
+	e.fx.Participant(event, func(p *models.Participant) { p.Name, p.CancelledAt = "Cancelled Person", &now })
+	e.fx.Participant(event, func(p *models.Participant) {
+		p.Name, p.QRPath, p.ApprovalStatus = "Pending Person", "", ApprovalPending
+	})
+
+	sheet, err := svc.BadgeSheetPDF(event.ID.String(), nil, BadgeLayout{Columns: 2, Rows: 2, MarginMM: 10, GapMM: 2})
+	if err != nil {
+		t.Fatalf("BadgeSheetPDF: %v", err)
+	}
+	if !bytes.HasPrefix(sheet, []byte("%PDF-")) {
+		t.Fatal("badge sheet is not a PDF file")
+	}
+	// Five badges four to a page
+	if !bytes.Contains(sheet, []byte("/Count 2 ")) {
+		t.Error("badges are not printed on 2 pages")
+	}
+	content := pdfContent(sheet)
+	for _, want := range append(names, "Engineering") {
+		if !strings.Contains(content, want) {
+			t.Errorf("badges do not show %q", want)
+		}
+	}
+	for _, unwanted := range []string{"Cancelled Person", "Pending Person"} {
+		if strings.Contains(content, unwanted) {
+			t.Errorf("badge printed for %q, who holds no ticket", unwanted)
+		}
+	}
+
+	// Tall labels lay the badge out differently and must fit too
+	if _, err := svc.BadgeSheetPDF(event.ID.String(), nil, BadgeLayout{Columns: 3, Rows: 2}); err != nil {
+		t.Errorf("tall labels: %v", err)
+	}
+
+	for _, layout := range []BadgeLayout{
+		{Columns: 0, Rows: 4},
+		{Columns: 2, Rows: 13},
+		{Columns: 6, Rows: 12, MarginMM: 40},
+		{Columns: 2, Rows: 4, GapMM: math.NaN()},
+	} {
+		if _, err := svc.BadgeSheetPDF(event.ID.String(), nil, layout); !errors.Is(err, ErrInvalidBadgeLayout) {
+			t.Errorf("layout %+v: error = %v, want %v", layout, err, ErrInvalidBadgeLayout)
+		}
+	}
+	if _, err := svc.BadgeSheetPDF(uuid.NewString(), nil, DefaultBadgeLayout); !errors.Is(err, ErrUnknownEvent) {
+		t.Errorf("unknown event: error = %v, want %v", err, ErrUnknownEvent)
+	}
+}
+
+func TestRegisterGroup(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	quota := 4
+	event := e.fx.Event(func(ev *models.Event) { ev.TicketQuota = &quota })
+	existing := e.fx.Participant(event)
+
+	member := func(n int) RegisterParticipantRequest {
+		return RegisterParticipantRequest{
+			Name:     fmt.Sprintf("Employee %d", n),
+			Email:    fmt.Sprintf("employee%d@example.com", n),
+			Phone:    fmt.Sprintf("0813%08d", n),
+			Division: "Sales",
+		}
+	}
+	count := func() int64 {
+		n, _ := e.repo.ParticipantRepo.GetParticipantCountByEventID(event.ID.String())
+		return n
+	}
+
+	// Every row is checked, and nobody registered when one is refused
+	bad := []RegisterParticipantRequest{member(1), member(2), member(1), member(3), member(4)}
+	bad[1].Email = "not an email"
+	bad[3].Email = existing.Email
+	bad[4].Name = ""
+	result, err := svc.RegisterGroup(event.ID.String(), bad)
+	if !errors.Is(err, ErrGroupRejected) {
+		t.Fatalf("RegisterGroup error = %v, want %v", err, ErrGroupRejected)
+	}
+	var got []string
+	for _, row := range result.Rows {
+		got = append(got, row.Error)
+	}
+	want := []string{"", "invalid email", "email already registered for this event", "email already registered for this event", "name is required"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("row errors = %q, want %q", got, want)
+	}
+	if count() != 1 {
+		t.Fatalf("%d participants after a rejected group, want 1", count())
+	}
+
+	// The group must fit in the quota as a whole
+	if _, err := svc.RegisterGroup(event.ID.String(), []RegisterParticipantRequest{member(1), member(2), member(3), member(4)}); !errors.Is(err, repositories.ErrTicketQuotaExceeded) {
+		t.Fatalf("group over quota: error = %v, want %v", err, repositories.ErrTicketQuotaExceeded)
+	}
+	if count() != 1 {
+		t.Fatalf("%d participants after a group over quota, want 1", count())
+	}
+
+	result, err = svc.RegisterGroup(event.ID.String(), []RegisterParticipantRequest{member(1), member(2), member(3)})
+	if err != nil {
+		t.Fatalf("RegisterGroup: %v", err)
+	}
+	if result.Registered != 3 || count() != 4 {
+		t.Fatalf("registered %d, %d participants; want 3 and 4", result.Registered, count())
+	}
+	for _, row := range result.Rows {
+		if row.ParticipantID == nil || row.QRPath == "" || row.Error != "" {
+			t.Fatalf("row %+v, want a participant with a QR code", row)
+		}
+		if _, err := os.Stat(filepath.Join(e.cfg.QRDir, filepath.Base(row.QRPath))); err != nil {
+			t.Fatalf("QR image of row %d: %v", row.Row, err)
+		}
+	}
+
+	// Quota rules count the rows they apply to
+	rules := e.fx.Event()
+	if err := e.repo.QuotaRuleRepo.CreateQuotaRule(&models.QuotaRule{EventID: rules.ID, Name: "Sales seats", Division: "sales", MaxRegistrations: 1}); err != nil {
+		t.Fatal(err)
+	}
+	other := member(6)
+	other.Division = "Finance"
+	result, err = svc.RegisterGroup(rules.ID.String(), []RegisterParticipantRequest{member(5), other, member(7)})
+	if !errors.Is(err, ErrGroupRejected) || result.Rows[0].Error != "" || result.Rows[1].Error != "" || !strings.Contains(result.Rows[2].Error, "Sales seats") {
+		t.Fatalf("quota rule: error = %v, rows = %+v", err, result.Rows)
+	}
+
+	if _, err := svc.RegisterGroup(event.ID.String(), nil); err == nil {
+		t.Fatal("empty group registered")
+	}
+}
+
+func TestWaitlist(t *testing.T) {
+	e := newTestEnv(t)
+	// A ticking clock keeps the order of arrival unambiguous
+	clock := time.Now()
+	e.store.Now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	svc := NewParticipantService(e.repo, e.cfg)
+	quota := 1
+	event := e.fx.Event(func(ev *models.Event) {
+		ev.TicketQuota = &quota
+		ev.Waitlist = WaitlistFIFO
+	})
+	e.fx.Participant(event)
+
+	waiting := func(n int) RegisterParticipantRequest {
+		return RegisterParticipantRequest{
+			EventID: event.ID.String(),
+			Name:    fmt.Sprintf("Waiting %d", n),
+			Email:   fmt.Sprintf("waiting%d@example.com", n),
+			Phone:   fmt.Sprintf("0815%08d", n),
+		}
+	}
+
+	if _, err := svc.RegisterParticipant(waiting(1)); !errors.Is(err, repositories.ErrTicketQuotaExceeded) {
+		t.Fatalf("registration over quota: error = %v, want %v", err, repositories.ErrTicketQuotaExceeded)
+	}
+	var entries []*WaitlistPosition
+	for n := 1; n <= 3; n++ {
+		position, err := svc.JoinWaitlist(waiting(n))
+		if err != nil {
+			t.Fatalf("JoinWaitlist %d: %v", n, err)
+		}
+		if position.Position != n {
+			t.Fatalf("entry %d at position %d", n, position.Position)
+		}
+		entries = append(entries, position)
+	}
+	again, err := svc.JoinWaitlist(waiting(2))
+	if err != nil || again.ID != entries[1].ID || again.Position != 2 {
+		t.Fatalf("joining again = %+v, %v; want position 2 of the same entry", again, err)
+	}
+
+	// Promotion by hand still respects the quota
+	if _, err := svc.PromoteWaitlistEntry("", entries[2].ID.String()); !errors.Is(err, repositories.ErrTicketQuotaExceeded) {
+		t.Fatalf("promotion over quota: error = %v, want %v", err, repositories.ErrTicketQuotaExceeded)
+	}
+
+	// Freed tickets go to the oldest entries first
+	quota = 3
+	event.TicketQuota = &quota
+	if err := e.repo.EventRepo.UpdateEvent(event); err != nil {
+		t.Fatal(err)
+	}
+	promoted, err := svc.PromoteWaitlist(event.ID.String())
+	if err != nil {
+		t.Fatalf("PromoteWaitlist: %v", err)
+	}
+	var got []string
+	for _, participant := range promoted {
+		got = append(got, participant.Email)
+		if participant.QRPath == "" {
+			t.Fatalf("promoted participant %s has no QR code", participant.Email)
+		}
+	}
+	if want := []string{"waiting1@example.com", "waiting2@example.com"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("promoted %q, want %q", got, want)
+	}
+	if _, err := svc.SetWaitlistPriority(entries[0].ID.String(), 5); !errors.Is(err, ErrWaitlistEntryPromoted) {
+		t.Fatalf("priority of a promoted entry: error = %v, want %v", err, ErrWaitlistEntryPromoted)
+	}
+	waitlist, err := svc.GetWaitlist(event.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(waitlist.Entries) != 1 || waitlist.Entries[0].ID != entries[2].ID || *waitlist.TicketsLeft != 0 {
+		t.Fatalf("waitlist = %+v, want entry 3 and no ticket left", waitlist)
+	}
+
+	// Priority events promote the highest priority first
+	event.Waitlist = WaitlistPriority
+	quota = 4
+	if err := e.repo.EventRepo.UpdateEvent(event); err != nil {
+		t.Fatal(err)
+	}
+	late, err := svc.JoinWaitlist(waiting(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SetWaitlistPriority(late.ID.String(), 10); err != nil {
+		t.Fatalf("SetWaitlistPriority: %v", err)
+	}
+	promoted, err = svc.PromoteWaitlist(event.ID.String())
+	if err != nil {
+		t.Fatalf("PromoteWaitlist: %v", err)
+	}
+	if len(promoted) != 1 || promoted[0].Email != "waiting4@example.com" {
+		t.Fatalf("promoted %+v, want the priority entry", promoted)
+	}
+
+	// Events without a waitlist refuse to put anyone on it
+	closed := e.fx.Event(func(ev *models.Event) { ev.TicketQuota = &quota })
+	req := waiting(5)
+	req.EventID = closed.ID.String()
+	if _, err := svc.JoinWaitlist(req); !errors.Is(err, ErrWaitlistOff) {
+		t.Fatalf("JoinWaitlist without waitlist: error = %v, want %v", err, ErrWaitlistOff)
+	}
+}
+
+func TestRegistrationFormAnswers(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	fields := []models.FormField{
+		{Key: "company", Label: "Company", Type: FormFieldText, Required: true},
+		{Key: "age", Label: "Age", Type: FormFieldNumber},
+		{Key: "vip", Label: "VIP", Type: FormFieldBoolean},
+		{Key: "shirt", Label: "Shirt size", Type: FormFieldSelect, Options: []string{"S", "M", "L"}},
+		{Key: "arrival", Label: "Arrival", Type: FormFieldDate},
+	}
+	if err := validateFormFields(fields); err != nil {
+		t.Fatalf("validateFormFields: %v", err)
+	}
+	for _, bad := range [][]models.FormField{
+		{{Key: "Company", Label: "Company", Type: FormFieldText}},
+		{{Key: "a", Label: "A", Type: FormFieldText}, {Key: "a", Label: "Again", Type: FormFieldText}},
+		{{Key: "size", Label: "Size", Type: FormFieldSelect}},
+		{{Key: "size", Label: " ", Type: FormFieldText}},
+		{{Key: "size", Label: "Size", Type: "color"}},
+	} {
+		if err := validateFormFields(bad); err == nil {
+			t.Fatalf("validateFormFields(%+v) accepted", bad)
+		}
+	}
+	event := e.fx.Event(func(ev *models.Event) { ev.FormFields = fields })
+
+	register := func(n int, answers map[string]interface{}) (*RegisterParticipantResponse, error) {
+		return svc.RegisterParticipant(RegisterParticipantRequest{
+			EventID: event.ID.String(),
+			Name:    fmt.Sprintf("Guest %d", n),
+			Email:   fmt.Sprintf("guest%d@example.com", n),
+			Phone:   fmt.Sprintf("0816%08d", n),
+			Answers: answers,
+		})
+	}
+	for _, answers := range []map[string]interface{}{
+		nil,
+		{"company": "  "},
+		{"company": "Acme", "team": "Blue"},
+		{"company": "Acme", "age": "30"},
+		{"company": "Acme", "vip": "yes"},
+		{"company": "Acme", "shirt": "XL"},
+		{"company": "Acme", "arrival": "12/05/2026"},
+	} {
+		if _, err := register(0, answers); err == nil {
+			t.Fatalf("registration with answers %v accepted", answers)
+		}
+	}
+
+	result, err := register(1, map[string]interface{}{"company": " Acme ", "age": float64(30), "shirt": "m", "vip": nil})
+	if err != nil {
+		t.Fatalf("RegisterParticipant: %v", err)
+	}
+	want := map[string]interface{}{"company": "Acme", "age": float64(30), "shirt": "M"}
+	if !reflect.DeepEqual(result.Participant.Answers, want) {
+		t.Fatalf("answers = %v, want %v", result.Participant.Answers, want)
+	}
+	if _, err := register(2, map[string]interface{}{"company": "Globex", "vip": true, "arrival": "2026-05-12"}); err != nil {
+		t.Fatalf("RegisterParticipant: %v", err)
+	}
+
+	list := func(answers map[string]string) []string {
+		t.Helper()
+		participants, _, _, err := svc.ListParticipants(event.ID.String(), 1, 20, &repositories.ParticipantFilters{Answers: answers})
+		if err != nil {
+			t.Fatalf("ListParticipants(%v): %v", answers, err)
+		}
+		var names []string
+		for _, participant := range participants {
+			names = append(names, participant.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+	if got := list(map[string]string{"company": "acme"}); !reflect.DeepEqual(got, []string{"Guest 1"}) {
+		t.Fatalf("company filter = %v", got)
+	}
+	if got := list(map[string]string{"vip": "true"}); !reflect.DeepEqual(got, []string{"Guest 2"}) {
+		t.Fatalf("vip filter = %v", got)
+	}
+	if got := list(map[string]string{"age": "30", "shirt": "S"}); len(got) != 0 {
+		t.Fatalf("age and shirt filter = %v, want nobody", got)
+	}
+	if _, _, _, err := svc.ListParticipants(event.ID.String(), 1, 20, &repositories.ParticipantFilters{Answers: map[string]string{"team": "Blue"}}); !errors.Is(err, ErrInvalidParticipantFilter) {
+		t.Fatalf("unknown field filter: error = %v, want %v", err, ErrInvalidParticipantFilter)
+	}
+
+	// The export has a column per form field
+	export, err := svc.ExportParticipants(event.ID.String(), &repositories.ParticipantFilters{Answers: map[string]string{"company": "Acme"}})
+	if err != nil {
+		t.Fatalf("ExportParticipants: %v", err)
+	}
+	var out bytes.Buffer
+	if err := export.Write(&out); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not CSV: %v", err)
+	}
+	n := len(participantExportHeader)
+	if got := records[0][n:]; !reflect.DeepEqual(got, []string{"answers.company", "answers.age", "answers.vip", "answers.shirt", "answers.arrival"}) {
+		t.Fatalf("answer columns = %v", got)
+	}
+	if len(records) != 2 || !reflect.DeepEqual(records[1][n:], []string{"Acme", "30", "", "M", ""}) {
+		t.Fatalf("exported rows = %v", records[1:])
+	}
+}
+
+func TestCancelRegistration(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	quota := 1
+	event := e.fx.Event(func(ev *models.Event) {
+		ev.TicketQuota = &quota
+		ev.Waitlist = WaitlistFIFO
+	})
+	guest := func(n int) RegisterParticipantRequest {
+		return RegisterParticipantRequest{
+			EventID: event.ID.String(),
+			Name:    fmt.Sprintf("Guest %d", n),
+			Email:   fmt.Sprintf("guest%d@example.com", n),
+			Phone:   fmt.Sprintf("0817%08d", n),
+		}
+	}
+
+	result, err := svc.RegisterParticipant(guest(1))
+	if err != nil {
+		t.Fatalf("RegisterParticipant: %v", err)
+	}
+	participant := result.Participant
+	qrFile := filepath.Join(e.cfg.QRDir, filepath.Base(participant.QRPath))
+	if _, err := svc.RegisterParticipant(guest(2)); !errors.Is(err, repositories.ErrTicketQuotaExceeded) {
+		t.Fatalf("registration over quota: error = %v, want %v", err, repositories.ErrTicketQuotaExceeded)
+	}
+	if _, err := svc.JoinWaitlist(guest(2)); err != nil {
+		t.Fatalf("JoinWaitlist: %v", err)
+	}
+
+	if _, err := svc.CancelOwnRegistration(participant.ID.String(), "AAAA-AAAA", ""); !errors.Is(err, ErrTicketCodeMismatch) {
+		t.Fatalf("wrong ticket code: error = %v, want %v", err, ErrTicketCodeMismatch)
+	}
+	code := strings.ToLower(TicketCode(e.cfg.CredentialSigningKey, participant.ID))
+	cancelled, err := svc.CancelOwnRegistration(participant.ID.String(), code, " Cannot make it ")
+	if err != nil {
+		t.Fatalf("CancelOwnRegistration: %v", err)
+	}
+	if cancelled.CancelledAt == nil || cancelled.CancellationReason != "Cannot make it" || cancelled.QRPath != "" {
+		t.Fatalf("cancelled participant = %+v", cancelled)
+	}
+	if _, err := os.Stat(qrFile); !os.IsNotExist(err) {
+		t.Fatalf("QR image of the cancelled registration: %v, want it removed", err)
+	}
+	if _, err := svc.CancelRegistration("", participant.ID.String(), ""); !errors.Is(err, ErrTicketCancelled) {
+		t.Fatalf("cancelling twice: error = %v, want %v", err, ErrTicketCancelled)
+	}
+	if _, err := svc.ReleaseQR(cancelled); err != nil || cancelled.QRPath != "" {
+		t.Fatalf("ReleaseQR of a cancelled registration = %q, %v; want no QR code", cancelled.QRPath, err)
+	}
+
+	// The freed ticket goes to the waitlist
+	promoted, err := svc.PromoteWaitlist(event.ID.String())
+	if err != nil {
+		t.Fatalf("PromoteWaitlist: %v", err)
+	}
+	if len(promoted) != 1 || promoted[0].Email != "guest2@example.com" {
+		t.Fatalf("promoted %+v, want guest 2", promoted)
+	}
+
+	// Staff cancel without a ticket code
+	if _, err := svc.CancelRegistration("", promoted[0].ID.String(), "Duplicate booking"); err != nil {
+		t.Fatalf("CancelRegistration: %v", err)
+	}
+	if _, err := svc.RegisterParticipant(guest(3)); err != nil {
+		t.Fatalf("registration after cancellations: %v", err)
+	}
+}
+
+func TestRegenerateQR(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event()
+	action := e.fx.Action(e.fx.Day(event))
+	staff := e.fx.User("staff")
+
+	result, err := svc.RegisterParticipant(RegisterParticipantRequest{
+		EventID: event.ID.String(),
+		Name:    "Guest",
+		Email:   "guest@example.com",
+		Phone:   "081700000001",
+	})
+	if err != nil {
+		t.Fatalf("RegisterParticipant: %v", err)
+	}
+	participant := result.Participant
+	oldQR := filepath.Join(e.cfg.QRDir, filepath.Base(participant.QRPath))
+
+	rotated, err := svc.RegenerateQR(staff.ID.String(), participant.ID.String(), "")
+	if err != nil {
+		t.Fatalf("RegenerateQR: %v", err)
+	}
+	if rotated.Participant.CredentialVersion != 1 || rotated.Incident.Reason != DefaultRegenerateReason {
+		t.Fatalf("regenerated credential version %d, reason %q", rotated.Participant.CredentialVersion, rotated.Incident.Reason)
+	}
+	if _, err := os.Stat(oldQR); !os.IsNotExist(err) {
+		t.Fatalf("old QR image: %v, want it removed", err)
+	}
+	if _, err := os.Stat(filepath.Join(e.cfg.QRDir, filepath.Base(rotated.QRPath))); err != nil {
+		t.Fatalf("new QR image: %v", err)
+	}
+	logs, _, err := e.repo.AuditRepo.ListAuditLogs(repositories.AuditLogFilter{EntityType: AuditEntityParticipant, EntityID: participant.ID.String()}, 0, 10)
+	if err != nil || len(logs) != 1 || logs[0].Action != AuditCredentialRegenerated {
+		t.Fatalf("audit logs = %+v (%v), want the regeneration", logs, err)
+	}
+
+	// Rotating again revokes the first regenerated code too
+	if _, err := svc.RegenerateQR(staff.ID.String(), participant.ID.String(), "posted on social media"); err != nil {
+		t.Fatalf("RegenerateQR: %v", err)
+	}
+	verify := func(qrData string) error {
+		_, err := e.verificationService().VerifyParticipantAction(VerifyRequest{QRCodeData: qrData, ActionCode: action.Code, VerifierID: staff.ID.String()})
+		return err
+	}
+	for _, old := range []string{participant.ID.String(), signedCredential(e.cfg.CredentialSigningKey, participant.ID.String(), 1)} {
+		if code := GetVerificationErrorCode(verify(old)); code != ErrCredentialRevoked {
+			t.Errorf("old QR code %q: error code = %q, want %q", old, code, ErrCredentialRevoked)
+		}
+	}
+	if err := verify(signedCredential(e.cfg.CredentialSigningKey, participant.ID.String(), 2)); err != nil {
+		t.Errorf("current QR code refused: %v", err)
+	}
+	if incidents, _ := svc.ListCredentialIncidents(participant.ID.String()); len(incidents) != 2 {
+		t.Errorf("logged %d incidents, want 2", len(incidents))
+	}
+
+	now := time.Now()
+	cancelled := e.fx.Participant(event, func(p *models.Participant) { p.CancelledAt = &now })
+	if _, err := svc.RegenerateQR(staff.ID.String(), cancelled.ID.String(), ""); !errors.Is(err, ErrTicketCancelled) {
+		t.Errorf("cancelled participant: error = %v, want %v", err, ErrTicketCancelled)
+	}
+	if _, err := svc.RegenerateQR(staff.ID.String(), uuid.NewString(), ""); !errors.Is(err, ErrUnknownParticipant) {
+		t.Errorf("unknown participant: error = %v, want %v", err, ErrUnknownParticipant)
+	}
+}
+
+func TestTransferParticipant(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	source := e.fx.Event()
+	day := e.fx.Day(source)
+	quota := 1
+	full := e.fx.Event(func(ev *models.Event) { ev.TicketQuota = &quota })
+	e.fx.Participant(full)
+	target := e.fx.Event(func(ev *models.Event) { ev.TicketPrice = 100000 })
+
+	result, err := svc.RegisterParticipant(RegisterParticipantRequest{
+		EventID: source.ID.String(),
+		Name:    "Guest",
+		Email:   "guest@example.com",
+		Phone:   "081700000001",
+	})
+	if err != nil {
+		t.Fatalf("RegisterParticipant: %v", err)
+	}
+	participant := result.Participant
+	oldQR := filepath.Join(e.cfg.QRDir, filepath.Base(participant.QRPath))
+	table := &models.SeatingTable{EventID: source.ID, EventDayID: day.ID, Name: "Table 1", Capacity: 4}
+	if err := e.repo.SeatingRepo.CreateTable(table); err != nil {
+		t.Fatalf("CreateTable: %v", err)
+	}
+	if err := e.repo.SeatingRepo.AssignSeat(&models.SeatAssignment{EventDayID: day.ID, ParticipantID: participant.ID, TableID: table.ID}); err != nil {
+		t.Fatalf("AssignSeat: %v", err)
+	}
+
+	transfer := func(eventID string) (*TransferResult, error) {
+		return svc.TransferParticipant("", participant.ID.String(), TransferParticipantRequest{EventID: eventID})
+	}
+	if _, err := transfer(source.ID.String()); !errors.Is(err, ErrTransferSameEvent) {
+		t.Fatalf("transfer to the same event: error = %v, want %v", err, ErrTransferSameEvent)
+	}
+	if _, err := transfer(full.ID.String()); !errors.Is(err, repositories.ErrTicketQuotaExceeded) {
+		t.Fatalf("transfer to a full event: error = %v, want %v", err, repositories.ErrTicketQuotaExceeded)
+	}
+
+	moved, err := transfer(target.ID.String())
+	if err != nil {
+		t.Fatalf("TransferParticipant: %v", err)
+	}
+	got := moved.Participant
+	if got.EventID != target.ID || moved.FromEventID != source.ID || got.CredentialVersion != 1 {
+		t.Fatalf("transferred participant = %+v, from %s", got, moved.FromEventID)
+	}
+	// The new ticket costs more than the free one that was paid for
+	if got.PaymentStatus != "pending" || moved.AmountDue != 100000 {
+		t.Fatalf("payment after transfer = %q, %v due; want pending, 100000", got.PaymentStatus, moved.AmountDue)
+	}
+	if _, err := os.Stat(oldQR); !os.IsNotExist(err) {
+		t.Fatalf("old QR image: %v, want it removed", err)
+	}
+	if got.QRPath == "" || filepath.Join(e.cfg.QRDir, filepath.Base(got.QRPath)) == oldQR {
+		t.Fatalf("QR path after transfer = %q, want a new one", got.QRPath)
+	}
+	if _, err := e.repo.SeatingRepo.GetAssignment(day.ID.String(), participant.ID.String()); err == nil {
+		t.Fatal("seat of the former event still assigned")
+	}
+	if n, _ := e.repo.ParticipantRepo.GetParticipantCountByEventID(source.ID.String()); n != 0 {
+		t.Fatalf("former event holds %d participants, want 0", n)
+	}
+
+	// Scanned participants stay where they were scanned
+	scanned := e.fx.Participant(source)
+	e.fx.Verification(scanned, e.fx.Action(day), e.fx.User("staff"))
+	if _, err := svc.TransferParticipant("", scanned.ID.String(), TransferParticipantRequest{EventID: target.ID.String()}); !errors.Is(err, ErrTransferCheckedIn) {
+		t.Fatalf("transfer of a scanned participant: error = %v, want %v", err, ErrTransferCheckedIn)
+	}
+
+	// Events requiring approval review the transferred registration before
+	// issuing a ticket
+	reviewed := e.fx.Event(func(ev *models.Event) { ev.RequiresApproval = true })
+	registered, err := svc.RegisterParticipant(RegisterParticipantRequest{
+		EventID: source.ID.String(),
+		Name:    "Second Guest",
+		Email:   "second@example.com",
+		Phone:   "081700000002",
+	})
+	if err != nil {
+		t.Fatalf("RegisterParticipant: %v", err)
+	}
+	moved, err = svc.TransferParticipant("", registered.Participant.ID.String(), TransferParticipantRequest{EventID: reviewed.ID.String()})
+	if err != nil {
+		t.Fatalf("TransferParticipant to an event requiring approval: %v", err)
+	}
+	if got := moved.Participant; got.ApprovalStatus != ApprovalPending || got.QRPath != "" {
+		t.Fatalf("transferred for review: approval %q, QR %q; want pending without a QR code", got.ApprovalStatus, got.QRPath)
+	}
+}
+
+func TestParticipantNotes(t *testing.T) {
+	e := newTestEnv(t)
+	clock := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	e.store.Now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	svc := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event()
+	participant := e.fx.Participant(event)
+	staff, supervisor := e.fx.User("staff"), e.fx.User("organizer")
+
+	if _, err := svc.AddParticipantNote(staff.ID.String(), participant.ID.String(), "  "); !errors.Is(err, ErrNoteBodyRequired) {
+		t.Fatalf("blank note: error = %v, want %v", err, ErrNoteBodyRequired)
+	}
+	if _, err := svc.AddParticipantNote(staff.ID.String(), uuid.NewString(), "Lost"); !errors.Is(err, ErrUnknownParticipant) {
+		t.Fatalf("note on an unknown participant: error = %v, want %v", err, ErrUnknownParticipant)
+	}
+	if _, err := svc.AddParticipantNote(staff.ID.String(), participant.ID.String(), " ID mismatch "); err != nil {
+		t.Fatalf("AddParticipantNote: %v", err)
+	}
+	if _, err := svc.AddParticipantNote(supervisor.ID.String(), participant.ID.String(), "Approved by supervisor"); err != nil {
+		t.Fatalf("AddParticipantNote: %v", err)
+	}
+
+	detail, err := svc.GetParticipantDetail(participant.ID.String())
+	if err != nil {
+		t.Fatalf("GetParticipantDetail: %v", err)
+	}
+	notes := detail.Notes
+	if len(notes) != 2 || notes[0].Body != "Approved by supervisor" || notes[1].Body != "ID mismatch" {
+		t.Fatalf("notes = %+v, want the supervisor's first", notes)
+	}
+	if notes[0].AuthorID != supervisor.ID || notes[0].Author.Email != supervisor.Email {
+		t.Fatalf("author = %s %q, want %s", notes[0].AuthorID, notes[0].Author.Email, supervisor.ID)
+	}
+
+	// Notes follow a merge into the kept participant
+	kept := e.fx.Participant(event)
+	if _, err := svc.MergeParticipants("", kept.ID.String(), participant.ID.String()); err != nil {
+		t.Fatalf("MergeParticipants: %v", err)
+	}
+	if notes, err := svc.ListParticipantNotes(kept.ID.String()); err != nil || len(notes) != 2 {
+		t.Fatalf("notes of the kept participant = %d, %v; want 2", len(notes), err)
+	}
+
+	// and are erased with the participant's personal data
+	if _, err := e.repo.RetentionRepo.AnonymizeParticipants(event.ID.String(), clock); err != nil {
+		t.Fatalf("AnonymizeParticipants: %v", err)
+	}
+	if notes, err := svc.ListParticipantNotes(kept.ID.String()); err != nil || len(notes) != 0 {
+		t.Fatalf("notes after anonymization = %d, %v; want none", len(notes), err)
+	}
+}
+
+func TestParticipantDataExportAndErasure(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	admin, staff := e.fx.User("admin"), e.fx.User("staff")
+	event := e.fx.Event()
+	result, err := svc.RegisterParticipant(RegisterParticipantRequest{
+		EventID: event.ID.String(),
+		Name:    "Jane Doe",
+		Email:   "jane@example.com",
+		Phone:   "081700000001",
+		Address: "1 Main Street",
+	})
+	if err != nil {
+		t.Fatalf("RegisterParticipant: %v", err)
+	}
+	participant := result.Participant
+	qrFile := filepath.Join(e.cfg.QRDir, filepath.Base(participant.QRPath))
+	e.fx.Verification(participant, e.fx.Action(e.fx.Day(event)), staff)
+	if _, err := svc.AddParticipantNote(staff.ID.String(), participant.ID.String(), "ID mismatch"); err != nil {
+		t.Fatalf("AddParticipantNote: %v", err)
+	}
+	if err := svc.UpdatePaymentStatus(admin.ID.String(), participant.ID.String(), "pending", nil); err != nil {
+		t.Fatalf("UpdatePaymentStatus: %v", err)
+	}
+
+	export, err := svc.ExportParticipantData(admin.ID.String(), participant.ID.String())
+	if err != nil {
+		t.Fatalf("ExportParticipantData: %v", err)
+	}
+	if export.Participant.Email != "jane@example.com" || export.EventTitle != event.Title {
+		t.Fatalf("exported participant = %q of %q", export.Participant.Email, export.EventTitle)
+	}
+	if len(export.ActionLogs) != 1 || len(export.Notes) != 1 || len(export.AuditLogs) != 1 {
+		t.Fatalf("exported %d action logs, %d notes, %d audit logs; want 1 each", len(export.ActionLogs), len(export.Notes), len(export.AuditLogs))
+	}
+	if _, err := svc.ExportParticipantData(admin.ID.String(), uuid.NewString()); !errors.Is(err, ErrUnknownParticipant) {
+		t.Fatalf("export of an unknown participant: error = %v, want %v", err, ErrUnknownParticipant)
+	}
+
+	// Events under legal hold keep their participants' data
+	hold := &models.LegalHold{EventID: event.ID, Reason: "Dispute", CreatedBy: admin.ID}
+	if err := e.repo.RetentionRepo.CreateLegalHold(hold); err != nil {
+		t.Fatalf("CreateLegalHold: %v", err)
+	}
+	if _, err := svc.AnonymizeParticipant(admin.ID.String(), participant.ID.String()); !errors.Is(err, ErrEventUnderLegalHold) {
+		t.Fatalf("erasure under legal hold: error = %v, want %v", err, ErrEventUnderLegalHold)
+	}
+	if err := e.repo.RetentionRepo.DeleteLegalHold(event.ID.String()); err != nil {
+		t.Fatalf("DeleteLegalHold: %v", err)
+	}
+
+	other := e.fx.Participant(event)
+	anonymized, err := svc.AnonymizeParticipant(admin.ID.String(), participant.ID.String())
+	if err != nil {
+		t.Fatalf("AnonymizeParticipant: %v", err)
+	}
+	if anonymized.AnonymizedAt == nil || anonymized.Name != repositories.AnonymizedName || anonymized.Email != "" ||
+		anonymized.Phone != "" || anonymized.Address != "" || anonymized.QRPath != "" {
+		t.Fatalf("anonymized participant = %+v", anonymized)
+	}
+	if _, err := os.Stat(qrFile); !os.IsNotExist(err) {
+		t.Fatalf("QR image of the anonymized participant: %v, want it removed", err)
+	}
+	if _, err := svc.AnonymizeParticipant(admin.ID.String(), participant.ID.String()); !errors.Is(err, ErrParticipantAnonymized) {
+		t.Fatalf("anonymizing twice: error = %v, want %v", err, ErrParticipantAnonymized)
+	}
+
+	// Scans stay for statistics; notes go; other participants are untouched
+	after, err := svc.ExportParticipantData(admin.ID.String(), participant.ID.String())
+	if err != nil {
+		t.Fatalf("ExportParticipantData: %v", err)
+	}
+	if len(after.ActionLogs) != 1 || len(after.Notes) != 0 {
+		t.Fatalf("after erasure: %d action logs, %d notes; want 1, 0", len(after.ActionLogs), len(after.Notes))
+	}
+	if kept, _ := e.repo.ParticipantRepo.GetParticipantByID(other.ID.String()); kept.AnonymizedAt != nil || kept.Email != other.Email {
+		t.Fatalf("other participant = %+v, want it untouched", kept)
+	}
+}
+
+func TestTagParticipants(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	event := e.fx.Event()
+	speaker, guest := e.fx.Participant(event), e.fx.Participant(event)
+	outsider := e.fx.Participant(e.fx.Event())
+	ids := []string{speaker.ID.String(), guest.ID.String(), outsider.ID.String()}
+
+	if _, err := svc.TagParticipants("", event.ID.String(), ids, nil, nil); !errors.Is(err, ErrNoTagChanges) {
+		t.Fatalf("no tags: error = %v, want %v", err, ErrNoTagChanges)
+	}
+	if _, err := svc.TagParticipants("", event.ID.String(), ids, []string{"vip, press"}, nil); err == nil {
+		t.Fatal("tag with a comma was accepted")
+	}
+
+	result, err := svc.TagParticipants("", event.ID.String(), ids, []string{" VIP ", "press", "vip"}, nil)
+	if err != nil {
+		t.Fatalf("TagParticipants: %v", err)
+	}
+	if len(result.Updated) != 2 || !reflect.DeepEqual(result.NotFound, []string{outsider.ID.String()}) {
+		t.Fatalf("result = %+v, want 2 updated and the other event's participant not found", result)
+	}
+	if _, err := svc.TagParticipants("", event.ID.String(), []string{speaker.ID.String()}, []string{"speaker"}, []string{"press"}); err != nil {
+		t.Fatalf("TagParticipants: %v", err)
+	}
+
+	got, _ := e.repo.ParticipantRepo.GetParticipantByID(speaker.ID.String())
+	if !reflect.DeepEqual(got.Tags, []string{"vip", "speaker"}) {
+		t.Fatalf("speaker tags = %v, want [vip speaker]", got.Tags)
+	}
+	participants, total, _, err := svc.ListParticipants(event.ID.String(), 1, 20, &repositories.ParticipantFilters{Tag: "press"})
+	if err != nil || total != 1 || participants[0].ID != guest.ID {
+		t.Fatalf("participants tagged press = %d, %v; want the guest", total, err)
+	}
+
+	// Participants are capped at MaxTagsPerParticipant tags
+	many := make([]string, MaxTagsPerParticipant)
+	for i := range many {
+		many[i] = fmt.Sprintf("tag-%d", i)
+	}
+	result, err = svc.TagParticipants("", event.ID.String(), []string{speaker.ID.String()}, many, nil)
+	if err != nil {
+		t.Fatalf("TagParticipants: %v", err)
+	}
+	if len(result.Updated) != 0 || len(result.OverLimit) != 1 {
+		t.Fatalf("result = %+v, want the speaker over the limit", result)
+	}
+}
+
+func TestCheckEmailDomain(t *testing.T) {
+	e := newTestEnv(t)
+	e.cfg.DisposableEmailDomains = " Mailinator.com, yopmail.com ,"
+	for email, want := range map[string]error{
+		"guest@example.com":         nil,
+		"guest@mailinator.com":      ErrDisposableEmail,
+		"guest@MAILINATOR.COM":      ErrDisposableEmail,
+		"guest@eu.yopmail.com":      ErrDisposableEmail,
+		"guest@notmailinator.com":   nil,
+		"guest@mailinator.com.evil": nil,
+	} {
+		if err := CheckEmailDomain(e.cfg, email); err != want {
+			t.Errorf("CheckEmailDomain(%q) = %v, want %v", email, err, want)
+		}
+	}
+
+	e.cfg.DisposableEmailDomains = ""
+	if err := CheckEmailDomain(e.cfg, "guest@mailinator.com"); err != nil {
+		t.Errorf("with no domains listed: %v", err)
+	}
+}
+
+func TestReviewDecisionNotice(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	mailer := &recordingSender{}
+	notifications := NewNotificationService(e.repo, mailer, nil, e.cfg)
+	event := e.fx.Event(func(ev *models.Event) { ev.RequiresApproval = true })
+	organizer := e.fx.User("organizer")
+	pending := func() *models.Participant {
+		return e.fx.Participant(event, func(p *models.Participant) { p.QRPath, p.ApprovalStatus = "", ApprovalPending })
+	}
+	approved, rejected, waiting := pending(), pending(), pending()
+
+	if _, err := svc.ApproveRegistrations(event.ID.String(), []string{approved.ID.String()}, organizer.ID.String()); err != nil {
+		t.Fatalf("ApproveRegistrations: %v", err)
+	}
+	if _, err := svc.RejectRegistrations(event.ID.String(), []string{rejected.ID.String()}, "Invitation only", organizer.ID.String()); err != nil {
+		t.Fatalf("RejectRegistrations: %v", err)
+	}
+	// Approved but not issued a QR code, as waivers remain to sign
+	unsigned := e.fx.Participant(event, func(p *models.Participant) { p.QRPath, p.ApprovalStatus = "", ApprovalApproved })
+
+	for _, p := range []*models.Participant{approved, rejected, unsigned} {
+		if err := notifications.SendReviewDecision(context.Background(), p.ID.String()); err != nil {
+			t.Fatalf("SendReviewDecision(%s): %v", p.Name, err)
+		}
+	}
+	if len(mailer.sent) != 3 {
+		t.Fatalf("sent %d emails, want 3", len(mailer.sent))
+	}
+	if ticket := mailer.sent[0]; ticket.To != approved.Email || len(ticket.Attachments) != 1 {
+		t.Errorf("approved participant got %q with %d attachments, want their ticket", ticket.Subject, len(ticket.Attachments))
+	}
+	if notice := mailer.sent[1]; notice.To != rejected.Email || !strings.Contains(notice.Body, "not approved") || !strings.Contains(notice.Body, "Invitation only") {
+		t.Errorf("rejection notice = %q", notice.Body)
+	}
+	if notice := mailer.sent[2]; notice.To != unsigned.Email || !strings.Contains(notice.Body, "has been approved") || len(notice.Attachments) != 0 {
+		t.Errorf("approval notice = %q", notice.Body)
+	}
+
+	if err := notifications.SendReviewDecision(context.Background(), waiting.ID.String()); !errors.Is(err, ErrNoReviewDecision) {
+		t.Errorf("pending registration: error = %v, want %v", err, ErrNoReviewDecision)
+	}
+}
+
+func TestRegistrationConfirmation(t *testing.T) {
+	e := newTestEnv(t)
+	svc := NewParticipantService(e.repo, e.cfg)
+	mailer := &recordingSender{}
+	notifications := NewNotificationService(e.repo, mailer, nil, e.cfg)
+	quota := 1
+	event := e.fx.Event(func(ev *models.Event) { ev.TicketQuota, ev.ConfirmationHours = &quota, 24 })
+	register := func(email string) (*RegisterParticipantResponse, error) {
+		return svc.RegisterParticipant(RegisterParticipantRequest{EventID: event.ID.String(), Name: "Sari", Email: email, Phone: "08120000001"})
+	}
+
+	result, err := register("sari@example.com")
+	if err != nil {
+		t.Fatalf("RegisterParticipant: %v", err)
+	}
+	participant := result.Participant
+	if participant.ApprovalStatus != ApprovalPendingConfirmation || participant.ConfirmationExpiresAt == nil || result.QRPath != "" {
+		t.Fatalf("registration = %s, expires %v, QR %q; want pending confirmation without QR code", participant.ApprovalStatus, participant.ConfirmationExpiresAt, result.QRPath)
+	}
+	// Unconfirmed registrations hold their ticket
+	if _, err := register("other@example.com"); !errors.Is(err, repositories.ErrTicketQuotaExceeded) {
+		t.Fatalf("second registration: error = %v, want %v", err, repositories.ErrTicketQuotaExceeded)
+	}
+
+	if err := notifications.SendRegistrationConfirmation(context.Background(), participant.ID.String()); err != nil {
+		t.Fatalf("SendRegistrationConfirmation: %v", err)
+	}
+	token := confirmationToken(e.cfg, participant.ID.String())
+	if len(mailer.sent) != 1 || !strings.Contains(mailer.sent[0].Body, token) {
+		t.Fatalf("sent %+v, want the confirmation link", mailer.sent)
+	}
+
+	if _, _, err := svc.ConfirmRegistration(participant.ID.String() + ".forged"); !errors.Is(err, ErrInvalidConfirmationLink) {
+		t.Fatalf("forged link: error = %v, want %v", err, ErrInvalidConfirmationLink)
+	}
+	confirmed, ok, err := svc.ConfirmRegistration(token)
+	if err != nil || !ok {
+		t.Fatalf("ConfirmRegistration = %v, %v", ok, err)
+	}
+	if confirmed.ApprovalStatus != ApprovalApproved || confirmed.QRPath == "" || confirmed.ConfirmationExpiresAt != nil {
+		t.Fatalf("confirmed registration = %s, QR %q; want approved with a QR code", confirmed.ApprovalStatus, confirmed.QRPath)
+	}
+	if _, ok, err := svc.ConfirmRegistration(token); err != nil || ok {
+		t.Fatalf("confirming again = %v, %v; want no change", ok, err)
+	}
+	if err := notifications.SendRegistrationConfirmation(context.Background(), participant.ID.String()); !errors.Is(err, ErrRegistrationNotPendingConfirmation) {
+		t.Fatalf("email after confirmation: error = %v, want %v", err, ErrRegistrationNotPendingConfirmation)
+	}
+
+	// Registrations not confirmed in time are deleted and free their ticket
+	other := e.fx.Event(func(ev *models.Event) { ev.TicketQuota, ev.ConfirmationHours = &quota, 1 })
+	result, err = svc.RegisterParticipant(RegisterParticipantRequest{EventID: other.ID.String(), Name: "Bot", Email: "bot@example.com", Phone: "08120000002"})
+	if err != nil {
+		t.Fatalf("RegisterParticipant: %v", err)
+	}
+	stale := result.Participant
+	past := time.Now().Add(-time.Minute)
+	stale.ConfirmationExpiresAt = &past
+	if err := e.repo.ParticipantRepo.UpdateParticipant(stale); err != nil {
+		t.Fatalf("UpdateParticipant: %v", err)
+	}
+	if _, _, err := svc.ConfirmRegistration(confirmationToken(e.cfg, stale.ID.String())); !errors.Is(err, ErrInvalidConfirmationLink) {
+		t.Fatalf("expired link: error = %v, want %v", err, ErrInvalidConfirmationLink)
+	}
+	expired, err := svc.ExpireUnconfirmedRegistrations(context.Background())
+	if err != nil || len(expired) != 1 || expired[0].ID != stale.ID {
+		t.Fatalf("ExpireUnconfirmedRegistrations = %d, %v; want the stale registration", len(expired), err)
+	}
+	if _, err := svc.GetParticipant(stale.ID.String()); err == nil {
+		t.Fatal("expired registration still exists")
+	}
+	if _, err := svc.RegisterParticipant(RegisterParticipantRequest{EventID: other.ID.String(), Name: "Sari", Email: "sari@example.com", Phone: "08120000001"}); err != nil {
+		t.Fatalf("registration after expiry: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxTagsPerParticipant is how many tags a participant can carry
+	MaxTagsPerParticipant = 20
+	// MaxTagLength is the longest tag, in characters
+	MaxTagLength = 50
+)
+
+var ErrNoTagChanges = errors.New("no tags to add or remove")
+
+// TagResult is the outcome of tagging participants in bulk
+type TagResult struct {
+	Updated []string `json:"updated"`
+	// IDs that are not participants of the event
+	NotFound []string `json:"not_found,omitempty"`
+	// Participants left unchanged as they would carry more than
+	// MaxTagsPerParticipant tags
+	OverLimit []string `json:"over_limit,omitempty"`
+}
+
+// NormalizeTag trims and lowercases a tag and checks it holds only letters,
+// digits, dashes and underscores
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", errors.New("tag must not be empty")
+	}
+	if utf8.RuneCountInString(tag) > MaxTagLength {
+		return "", errors.New("tag is too long: " + tag)
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return "", errors.New("tag may only contain letters, digits, dashes and underscores: " + tag)
+		}
+	}
+	return tag, nil
+}
+
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, tag)
+	}
+	return normalized, nil
+}
+
+// paymentSettled reports whether a participant of a paid event may be let in:
+// they paid, or were given a complimentary ticket
+func paymentSettled(participant *models.Participant) bool {
+	return participant.PaymentStatus == "paid" || participant.HasTag(models.TagComplimentary)
+}
+
+// TagParticipants adds and removes tags on participants of an event. A tag
+// both added and removed is removed.
+func (s *ParticipantService) TagParticipants(actorID, eventID string, participantIDs, add, remove []string) (*TagResult, error) {
+	if _, err := s.repo.EventRepo.GetEventByID(eventID); err != nil {
+		return nil, errors.New("event not found")
+	}
+	add, err := normalizeTags(add)
+	if err != nil {
+		return nil, err
+	}
+	if remove, err = normalizeTags(remove); err != nil {
+		return nil, err
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, ErrNoTagChanges
+	}
+
+	ids := make([]uuid.UUID, 0, len(participantIDs))
+	seen := make(map[uuid.UUID]bool, len(participantIDs))
+	for _, raw := range participantIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, errors.New("invalid participant ID: " + raw)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	updated, overLimit, err := s.repo.ParticipantRepo.UpdateParticipantTags(eventID, ids, add, remove, MaxTagsPerParticipant)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TagResult{Updated: make([]string, 0, len(updated))}
+	done := make(map[uuid.UUID]bool, len(updated)+len(overLimit))
+	for _, id := range updated {
+		done[id] = true
+		result.Updated = append(result.Updated, id.String())
+		s.audit.Record(actorID, AuditParticipantTagged, AuditEntityParticipant, id.String(), map[string]interface{}{
+			"event_id": eventID,
+			"added":    add,
+			"removed":  remove,
+		})
+	}
+	for _, id := range overLimit {
+		done[id] = true
+		result.OverLimit = append(result.OverLimit, id.String())
+	}
+	for _, id := range ids {
+		if !done[id] {
+			result.NotFound = append(result.NotFound, id.String())
+		}
+	}
+	return result, nil
+}
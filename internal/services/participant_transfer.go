@@ -0,0 +1,175 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrTransferSameEvent is returned when a participant is transferred to
+	// the event they are registered for
+	ErrTransferSameEvent = errors.New("participant is already registered for this event")
+	// ErrTransferCheckedIn is returned for participants who were scanned at
+	// their event already
+	ErrTransferCheckedIn = errors.New("participants who were scanned cannot be transferred")
+)
+
+type TransferParticipantRequest struct {
+	// Event to move the participant to
+	EventID string
+	// Required when the target event has ticket types
+	TicketTypeID string
+}
+
+// TransferResult is the outcome of transferring a participant
+type TransferResult struct {
+	Participant *models.Participant `json:"participant"`
+	// Event the participant was registered for before
+	FromEventID uuid.UUID `json:"from_event_id"`
+	// What the participant still owes: the target ticket price when the
+	// transfer left them unpaid, otherwise 0
+	AmountDue float64 `json:"amount_due"`
+}
+
+// TransferParticipant moves a registration to another published event, such
+// as a later batch of the same event. The target's ticket quota, day
+// capacities, ticket type and quota rules are checked as for a new
+// registration; its registration window is not, as staff move people on
+// request. A paid participant stays paid unless the new ticket costs more,
+// when payment is pending again. Targets requiring approval put the
+// registration up for review again, holding the new QR code back until it is
+// approved; otherwise pending approvals are granted. Answers the target's
+// form does not ask for are dropped, seats of the former event released, and
+// a new QR code generated; the old one is refused at the gates. The caller
+// promotes the former event's waitlist into the freed ticket.
+func (s *ParticipantService) TransferParticipant(actorID, participantID string, req TransferParticipantRequest) (*TransferResult, error) {
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil || participant.AnonymizedAt != nil {
+		return nil, ErrUnknownParticipant
+	}
+	if participant.CancelledAt != nil {
+		return nil, ErrTicketCancelled
+	}
+	if participant.EventID.String() == req.EventID {
+		return nil, ErrTransferSameEvent
+	}
+	scans, err := s.repo.ActionRepo.GetActionLogsByParticipant(participantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(scans) > 0 {
+		return nil, ErrTransferCheckedIn
+	}
+
+	source, err := s.repo.EventRepo.GetEventByID(participant.EventID.String())
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.repo.EventRepo.GetEventByID(req.EventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+	if target.Status != EventPublished {
+		return nil, ErrEventNotPublished
+	}
+	if existing, _ := s.repo.ParticipantRepo.GetParticipantByEmailAndEvent(participant.Email, req.EventID); existing != nil {
+		return nil, errors.New("email already registered for this event")
+	}
+
+	ticketType, err := s.pickTicketType(target, req.TicketTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkQuotaRules(req.EventID, ticketType, participant.Division); err != nil {
+		return nil, err
+	}
+	limit, err := s.groupLimit(target, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var oldTicketType *models.TicketType
+	if participant.TicketTypeID != nil {
+		oldTicketType, _ = s.repo.TicketTypeRepo.GetTicketType(participant.TicketTypeID.String())
+	}
+	oldPrice, newPrice := ticketPrice(source, oldTicketType), ticketPrice(target, ticketType)
+	switch {
+	case newPrice == 0:
+		participant.PaymentStatus = "paid"
+	case participant.PaymentStatus != "paid" || newPrice > oldPrice:
+		participant.PaymentStatus = "pending"
+	}
+
+	answers := map[string]interface{}{}
+	for _, field := range target.FormFields {
+		if answer, ok := participant.Answers[field.Key]; ok {
+			answers[field.Key] = answer
+		}
+	}
+	if len(answers) == 0 {
+		answers = nil
+	}
+	participant.Answers = answers
+
+	// Unconfirmed registrations still have to be confirmed, and rejected
+	// ones stay rejected unless the target reviews them again
+	switch {
+	case participant.ApprovalStatus == ApprovalPendingConfirmation:
+	case target.RequiresApproval:
+		participant.ApprovalStatus = ApprovalPending
+		participant.RejectionReason = ""
+		participant.ReviewedBy, participant.ReviewedAt = nil, nil
+	case participant.ApprovalStatus == ApprovalPending:
+		participant.ApprovalStatus = ApprovalApproved
+	}
+
+	fromEventID := participant.EventID
+	qrPath := participant.QRPath
+	participant.EventID, participant.Event = target.ID, models.Event{}
+	participant.TicketTypeID = nil
+	if ticketType != nil {
+		participant.TicketTypeID = &ticketType.ID
+	}
+	// A new credential version refuses the QR code already sent out
+	participant.CredentialVersion++
+	participant.QRPath = ""
+	if err := s.repo.ParticipantRepo.TransferParticipant(participant, limit); err != nil {
+		return nil, err
+	}
+	log := logger.Log.WithField("participant_id", participant.ID.String())
+
+	if qrPath != "" {
+		if err := os.Remove(filepath.Join(s.cfg.QRDir, filepath.Base(qrPath))); err != nil && !os.IsNotExist(err) {
+			log.WithError(err).Warn("failed to remove QR code of transferred participant")
+		}
+	}
+	// Registrations awaiting review get their QR code once approved; waivers
+	// of the target event may hold it back too
+	if participant.ApprovalStatus != ApprovalPending {
+		if _, err := s.ReleaseQR(participant); err != nil {
+			log.WithError(err).Warn("failed to release QR code after transfer")
+		}
+	}
+
+	details := map[string]interface{}{
+		"from_event_id":  fromEventID.String(),
+		"to_event_id":    target.ID.String(),
+		"payment_status": participant.PaymentStatus,
+	}
+	if ticketType != nil {
+		details["ticket_type_id"] = ticketType.ID.String()
+	}
+	s.audit.Record(actorID, AuditParticipantTransferred, AuditEntityParticipant, participant.ID.String(), details)
+
+	result := &TransferResult{Participant: participant, FromEventID: fromEventID}
+	if participant.PaymentStatus != "paid" {
+		result.AmountDue = newPrice
+	}
+	return result, nil
+}
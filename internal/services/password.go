@@ -0,0 +1,89 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/utils"
+	"event-management-backend/pkg/logger"
+)
+
+var ErrWrongPassword = errors.New("current password is incorrect")
+
+// NewPasswordHasher returns the configured hasher for new passwords
+func NewPasswordHasher(cfg *config.Config) utils.PasswordHasher {
+	if cfg.PasswordHash == "argon2id" {
+		return utils.Argon2idHasher{
+			Memory:      uint32(cfg.Argon2Memory / 1024),
+			Iterations:  uint32(cfg.Argon2Iterations),
+			Parallelism: uint8(cfg.Argon2Parallelism),
+		}
+	}
+	return utils.BcryptHasher{Cost: cfg.BcryptCost}
+}
+
+// hashPassword checks a new password against the configured policy and
+// hashes it
+func hashPassword(cfg *config.Config, password string) (string, error) {
+	policy := utils.PasswordPolicy{
+		MinLength:        cfg.PasswordMinLength,
+		RequireMixedCase: cfg.PasswordRequireMixedCase,
+		RequireDigit:     cfg.PasswordRequireDigit,
+		RequireSymbol:    cfg.PasswordRequireSymbol,
+	}
+	if err := policy.Check(password); err != nil {
+		return "", err
+	}
+	return NewPasswordHasher(cfg).Hash(password)
+}
+
+// rehashPassword replaces a user's password hash made with other hashing
+// settings, once the password was checked at login. Failures are logged
+// and leave the old hash, which keeps working.
+func (s *AuthService) rehashPassword(user *models.User, password string) {
+	hasher := NewPasswordHasher(s.cfg)
+	if !hasher.NeedsRehash(user.Password) {
+		return
+	}
+	hashed, err := hasher.Hash(password)
+	if err == nil {
+		user.Password = hashed
+		err = s.repo.UserRepo.UpdateUser(user)
+	}
+	if err != nil {
+		logger.Log.WithError(err).WithField("user_id", user.ID).Warn("failed to rehash password")
+	}
+}
+
+// ChangePassword sets a user's own password after checking the current one.
+// All of the user's tokens are revoked, so other devices are logged out and
+// the user logs in again with the new password.
+func (s *AuthService) ChangePassword(userID, currentPassword, newPassword string) error {
+	user, err := s.repo.UserRepo.GetUserByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if err := utils.CheckPassword(currentPassword, user.Password); err != nil {
+		return ErrWrongPassword
+	}
+	if newPassword == currentPassword {
+		return fmt.Errorf("%w: must differ from the current password", utils.ErrWeakPassword)
+	}
+
+	hashedPassword, err := hashPassword(s.cfg, newPassword)
+	if err != nil {
+		return err
+	}
+	user.Password = hashedPassword
+	if err := s.repo.UserRepo.UpdateUser(user); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	if err := s.revokeUserTokens(userID); err != nil {
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+
+	s.audit.Record(userID, AuditPasswordChanged, AuditEntityUser, userID, nil)
+	return nil
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-management-backend/internal/repositories/memory"
+	"event-management-backend/internal/utils"
+)
+
+func TestChangePassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		new     string
+		wantErr error
+	}{
+		{name: "valid", current: memory.FixturePassword, new: "Better-Passw0rd"},
+		{name: "wrong current password", current: "guess", new: "Better-Passw0rd", wantErr: ErrWrongPassword},
+		{name: "unchanged", current: memory.FixturePassword, new: memory.FixturePassword, wantErr: utils.ErrWeakPassword},
+		{name: "too short", current: memory.FixturePassword, new: "Sh0rt!", wantErr: utils.ErrWeakPassword},
+		{name: "single case", current: memory.FixturePassword, new: "better-passw0rd", wantErr: utils.ErrWeakPassword},
+		{name: "no digit", current: memory.FixturePassword, new: "Better-Password", wantErr: utils.ErrWeakPassword},
+		{name: "no symbol", current: memory.FixturePassword, new: "BetterPassw0rd", wantErr: utils.ErrWeakPassword},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			e.cfg.PasswordMinLength = 10
+			e.cfg.PasswordRequireMixedCase = true
+			e.cfg.PasswordRequireDigit = true
+			e.cfg.PasswordRequireSymbol = true
+			svc := e.authService()
+			user := e.fx.User("staff")
+			issuedAt := time.Now().Add(-time.Minute)
+
+			err := svc.ChangePassword(user.ID.String(), tt.current, tt.new)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if _, err := svc.Authenticate(user.Email, tt.new, "", false, LoginClient{}); err != nil {
+				t.Fatalf("login with the new password: %v", err)
+			}
+			if _, err := svc.Authenticate(user.Email, tt.current, "", false, LoginClient{}); err == nil {
+				t.Fatal("old password still accepted")
+			}
+			if err := svc.ValidateToken(user.ID.String(), "", issuedAt); !errors.Is(err, ErrTokenRevoked) {
+				t.Fatalf("ValidateToken of an earlier token = %v, want %v", err, ErrTokenRevoked)
+			}
+		})
+	}
+}
+
+func TestRehashOnLogin(t *testing.T) {
+	e := newTestEnv(t)
+	svc := e.authService()
+	user := e.fx.User("staff")
+
+	e.cfg.PasswordHash = "argon2id"
+	e.cfg.Argon2Memory = 8 * 1024
+	e.cfg.Argon2Iterations = 1
+	e.cfg.Argon2Parallelism = 1
+	hasher := NewPasswordHasher(e.cfg)
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.Authenticate(user.Email, memory.FixturePassword, "", false, LoginClient{}); err != nil {
+			t.Fatalf("login %d: %v", i+1, err)
+		}
+		stored, _ := e.repo.UserRepo.GetUserByID(user.ID.String())
+		if hasher.NeedsRehash(stored.Password) {
+			t.Fatalf("login %d: hash %q was not upgraded to argon2id", i+1, stored.Password)
+		}
+	}
+
+	if _, err := svc.Authenticate(user.Email, "wrong-password", "", false, LoginClient{}); err == nil {
+		t.Fatal("wrong password accepted after rehash")
+	}
+
+	// Changing the parameters again upgrades the argon2id hash
+	old, _ := e.repo.UserRepo.GetUserByID(user.ID.String())
+	e.cfg.Argon2Iterations = 2
+	if _, err := svc.Authenticate(user.Email, memory.FixturePassword, "", false, LoginClient{}); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := e.repo.UserRepo.GetUserByID(user.ID.String())
+	if stored.Password == old.Password || NewPasswordHasher(e.cfg).NeedsRehash(stored.Password) {
+		t.Fatalf("hash %q was not upgraded to the new parameters", stored.Password)
+	}
+}
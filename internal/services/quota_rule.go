@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownQuotaRule = errors.New("quota rule not found")
+	// ErrQuotaRuleReached is returned when a registration would go over a
+	// quota rule of the event
+	ErrQuotaRuleReached = repositories.ErrQuotaRuleReached
+)
+
+type QuotaRuleRequest struct {
+	Name             string
+	TicketTypeID     string // optional
+	Division         string // optional
+	MaxRegistrations int
+}
+
+func (req QuotaRuleRequest) validate() error {
+	if strings.TrimSpace(req.Name) == "" {
+		return errors.New("name is required")
+	}
+	if req.TicketTypeID == "" && strings.TrimSpace(req.Division) == "" {
+		return errors.New("a ticket type or a division is required; use the event's ticket quota to cap every registration")
+	}
+	if req.MaxRegistrations <= 0 {
+		return errors.New("max registrations must be positive")
+	}
+	return nil
+}
+
+// QuotaRuleUsage is a quota rule with the registrations counted against it
+type QuotaRuleUsage struct {
+	models.QuotaRule
+	Registered int64 `json:"registered"`
+	Remaining  int   `json:"remaining"`
+	Full       bool  `json:"full"`
+}
+
+// quotaRuleApplies reports whether a registration for ticketType from
+// division counts against rule
+func quotaRuleApplies(rule *models.QuotaRule, ticketType *models.TicketType, division string) bool {
+	if ticketType == nil {
+		return rule.Applies(nil, division)
+	}
+	return rule.Applies(&ticketType.ID, division)
+}
+
+// ListQuotaRules returns the quota rules of an event with their usage
+func (s *EventService) ListQuotaRules(eventID string) ([]QuotaRuleUsage, error) {
+	if _, err := s.repo.EventRepo.GetEventByID(eventID); err != nil {
+		return nil, ErrUnknownEvent
+	}
+	rules, err := s.repo.QuotaRuleRepo.ListQuotaRulesByEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make([]QuotaRuleUsage, 0, len(rules))
+	for i := range rules {
+		registered, err := s.repo.QuotaRuleRepo.CountRuleRegistrations(&rules[i])
+		if err != nil {
+			return nil, err
+		}
+		remaining := rules[i].MaxRegistrations - int(registered)
+		if remaining < 0 {
+			remaining = 0
+		}
+		usage = append(usage, QuotaRuleUsage{
+			QuotaRule:  rules[i],
+			Registered: registered,
+			Remaining:  remaining,
+			Full:       remaining == 0,
+		})
+	}
+	return usage, nil
+}
+
+func (s *EventService) CreateQuotaRule(eventID string, req QuotaRuleRequest) (*models.QuotaRule, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+
+	rule := &models.QuotaRule{
+		ID:      uuid.New(),
+		EventID: event.ID,
+	}
+	if err := s.applyQuotaRule(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.QuotaRuleRepo.CreateQuotaRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateQuotaRule replaces a quota rule. Rules may be tightened below the
+// registrations already made; they then only refuse new ones.
+func (s *EventService) UpdateQuotaRule(eventID, ruleID string, req QuotaRuleRequest) (*models.QuotaRule, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	rule, err := s.getQuotaRule(eventID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyQuotaRule(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.QuotaRuleRepo.UpdateQuotaRule(rule); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUnknownQuotaRule
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *EventService) DeleteQuotaRule(eventID, ruleID string) error {
+	if _, err := s.getQuotaRule(eventID, ruleID); err != nil {
+		return err
+	}
+	err := s.repo.QuotaRuleRepo.DeleteQuotaRule(ruleID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUnknownQuotaRule
+	}
+	return err
+}
+
+// getQuotaRule returns a quota rule of the given event
+func (s *EventService) getQuotaRule(eventID, ruleID string) (*models.QuotaRule, error) {
+	rule, err := s.repo.QuotaRuleRepo.GetQuotaRule(ruleID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && rule.EventID.String() != eventID) {
+		return nil, ErrUnknownQuotaRule
+	}
+	return rule, err
+}
+
+// applyQuotaRule copies a validated request onto rule, checking its ticket
+// type belongs to the rule's event
+func (s *EventService) applyQuotaRule(rule *models.QuotaRule, req QuotaRuleRequest) error {
+	rule.TicketTypeID = nil
+	if req.TicketTypeID != "" {
+		ticketType, err := s.getTicketType(rule.EventID.String(), req.TicketTypeID)
+		if err != nil {
+			return err
+		}
+		rule.TicketTypeID = &ticketType.ID
+	}
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.Division = strings.TrimSpace(req.Division)
+	rule.MaxRegistrations = req.MaxRegistrations
+	return nil
+}
+
+// checkQuotaRules refuses a registration for ticketType from division once
+// any quota rule of the event it counts against is full. The rules are
+// checked again with the event locked as the participant is inserted.
+func (s *ParticipantService) checkQuotaRules(eventID string, ticketType *models.TicketType, division string) error {
+	rules, err := s.repo.QuotaRuleRepo.ListQuotaRulesByEvent(eventID)
+	if err != nil {
+		return err
+	}
+	for i := range rules {
+		if !quotaRuleApplies(&rules[i], ticketType, division) {
+			continue
+		}
+		registered, err := s.repo.QuotaRuleRepo.CountRuleRegistrations(&rules[i])
+		if err != nil {
+			return errors.New("failed to check quota")
+		}
+		if registered >= int64(rules[i].MaxRegistrations) {
+			return fmt.Errorf("%w: %s", ErrQuotaRuleReached, rules[i].Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+// issueTokens returns a login response for a user, with a refresh token
+// expiring after RefreshTokenTTL when withRefresh is set
+func (s *AuthService) issueTokens(user *models.User, withRefresh bool) (*LoginResponse, error) {
+	token, expiresAt, err := s.generateJWT(user)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+	resp := &LoginResponse{Token: token, ExpiresAt: expiresAt, User: user}
+
+	if withRefresh {
+		refresh, refreshExpiresAt, err := s.createRefreshToken(user, time.Now().Add(s.cfg.RefreshTokenTTL))
+		if err != nil {
+			return nil, err
+		}
+		resp.RefreshToken = refresh
+		resp.RefreshExpiresAt = &refreshExpiresAt
+	}
+	return resp, nil
+}
+
+func (s *AuthService) createRefreshToken(user *models.User, expiresAt time.Time) (string, time.Time, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := s.repo.TokenRepo.CreateRefreshToken(&models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hashRefreshToken(token),
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// Refresh exchanges a refresh token for a new access token. The refresh
+// token is rotated: the response has a new one with the same expiry, and the
+// presented one is rejected from now on. Revoking all of a user's tokens or
+// deactivating them also rejects their refresh tokens.
+func (s *AuthService) Refresh(refreshToken string) (*LoginResponse, error) {
+	stored, err := s.repo.TokenRepo.GetRefreshTokenByHash(hashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if stored.RevokedAt != nil || !now.Before(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.repo.UserRepo.GetUserByID(stored.UserID.String())
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if !user.IsActive {
+		return nil, ErrUserDeactivated
+	}
+	if user.TokensRevokedAt != nil && !stored.CreatedAt.After(*user.TokensRevokedAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if err := s.repo.TokenRepo.RevokeRefreshToken(stored.ID.String(), now); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	token, expiresAt, err := s.generateJWT(user)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+	refresh, refreshExpiresAt, err := s.createRefreshToken(user, stored.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResponse{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: &refreshExpiresAt,
+		User:             user,
+	}, nil
+}
+
+// RevokeRefreshToken ends a remember-me login of a user. Unknown and already
+// revoked tokens are ignored.
+func (s *AuthService) RevokeRefreshToken(userID, refreshToken string) error {
+	stored, err := s.repo.TokenRepo.GetRefreshTokenByHash(hashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if stored.UserID.String() != userID {
+		return nil
+	}
+	if err := s.repo.TokenRepo.RevokeRefreshToken(stored.ID.String(), time.Now()); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
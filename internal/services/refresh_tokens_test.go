@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-management-backend/internal/repositories/memory"
+)
+
+func TestRefreshToken(t *testing.T) {
+	e := newTestEnv(t)
+	svc := e.authService()
+	user := e.fx.User("staff")
+
+	plain, err := svc.Authenticate(user.Email, memory.FixturePassword, "", false, LoginClient{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.RefreshToken != "" {
+		t.Fatal("refresh token issued without remember-me")
+	}
+	if d := time.Until(plain.ExpiresAt); d <= 0 || d > e.cfg.AccessTokenTTL {
+		t.Fatalf("access token expires in %s, want at most %s", d, e.cfg.AccessTokenTTL)
+	}
+
+	login, err := svc.Authenticate(user.Email, memory.FixturePassword, "", true, LoginClient{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if login.RefreshToken == "" || login.RefreshExpiresAt == nil {
+		t.Fatal("no refresh token issued with remember-me")
+	}
+
+	refreshed, err := svc.Refresh(login.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshed.Token == "" || refreshed.RefreshToken == login.RefreshToken || !refreshed.RefreshExpiresAt.Equal(*login.RefreshExpiresAt) {
+		t.Fatalf("refresh = %+v; want a new token and refresh token with the same expiry", refreshed)
+	}
+	if _, err := svc.Refresh(login.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("reusing a rotated token: error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+
+	if err := svc.RevokeRefreshToken(user.ID.String(), refreshed.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Refresh(refreshed.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("after logout: error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+
+	login, err = svc.Authenticate(user.Email, memory.FixturePassword, "", true, LoginClient{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.RevokeUserTokens("", user.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Refresh(login.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("after revoking all tokens: error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"event-management-backend/internal/config"
+)
+
+// ErrDisposableEmail is returned for email addresses of the throwaway email
+// services listed in DISPOSABLE_EMAIL_DOMAINS
+var ErrDisposableEmail = errors.New("disposable email addresses are not accepted, please use a permanent one")
+
+// CheckEmailDomain refuses email addresses at a domain, or a subdomain of a
+// domain, listed in DISPOSABLE_EMAIL_DOMAINS. Public registrations are
+// checked; organizers importing or registering participants are not.
+func CheckEmailDomain(cfg *config.Config, email string) error {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return nil
+	}
+	domain := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(email[at+1:])), ".")
+	for _, listed := range cfg.DisposableEmailDomainList() {
+		if domain == listed || strings.HasSuffix(domain, "."+listed) {
+			return ErrDisposableEmail
+		}
+	}
+	return nil
+}
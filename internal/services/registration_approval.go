@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"event-management-backend/internal/mail"
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Approval statuses of a registration
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+)
+
+// ErrNoReviewDecision is returned when notifying the decision on a
+// registration that is neither approved nor rejected
+var ErrNoReviewDecision = errors.New("registration has no review decision")
+
+// ReviewResult reports the outcome of a bulk approval or rejection
+type ReviewResult struct {
+	Updated []string `json:"updated"`
+	// IDs that are not registrations of the event
+	NotFound []string `json:"not_found,omitempty"`
+	// Approved participants whose QR code could not be issued; approving them
+	// again retries
+	QRFailed []string `json:"qr_failed,omitempty"`
+}
+
+// ListRegistrationsForReview returns the registrations of an event with the
+// given approval status, oldest first
+func (s *ParticipantService) ListRegistrationsForReview(eventID, status string, page, pageSize int) ([]models.Participant, int64, int, error) {
+	if status != ApprovalPending && status != ApprovalApproved && status != ApprovalRejected && status != ApprovalPendingConfirmation {
+		return nil, 0, 0, errors.New("invalid approval status")
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+	participants, total, err := s.repo.ParticipantRepo.ListParticipantsByApprovalStatus(eventID, status, offset, pageSize)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	totalPages := (int(total) + pageSize - 1) / pageSize
+	return participants, total, totalPages, nil
+}
+
+// ApproveRegistrations approves registrations of an event and issues the QR
+// codes of those that have signed every waiver
+func (s *ParticipantService) ApproveRegistrations(eventID string, participantIDs []string, reviewerID string) (*ReviewResult, error) {
+	result, err := s.review(eventID, participantIDs, ApprovalApproved, "", reviewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range result.Updated {
+		participant, err := s.repo.ParticipantRepo.GetParticipantByID(id)
+		if err != nil {
+			result.QRFailed = append(result.QRFailed, id)
+			continue
+		}
+		if _, err := s.ReleaseQR(participant); err != nil {
+			result.QRFailed = append(result.QRFailed, id)
+		}
+	}
+
+	return result, nil
+}
+
+// RejectRegistrations rejects registrations of an event. Rejected
+// participants are refused at verification even if they hold a QR code.
+func (s *ParticipantService) RejectRegistrations(eventID string, participantIDs []string, reason, reviewerID string) (*ReviewResult, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("rejection reason is required")
+	}
+	return s.review(eventID, participantIDs, ApprovalRejected, reason, reviewerID)
+}
+
+func (s *ParticipantService) review(eventID string, participantIDs []string, status, reason, reviewerID string) (*ReviewResult, error) {
+	if _, err := s.repo.EventRepo.GetEventByID(eventID); err != nil {
+		return nil, errors.New("event not found")
+	}
+	reviewer, err := uuid.Parse(reviewerID)
+	if err != nil {
+		return nil, errors.New("invalid reviewer")
+	}
+
+	ids := make([]uuid.UUID, 0, len(participantIDs))
+	seen := make(map[uuid.UUID]bool, len(participantIDs))
+	for _, raw := range participantIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, errors.New("invalid participant ID: " + raw)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	updated, err := s.repo.ParticipantRepo.UpdateApprovalStatus(eventID, ids, status, reason, reviewer)
+	if err != nil {
+		return nil, err
+	}
+
+	action := AuditRegistrationApproved
+	if status == ApprovalRejected {
+		action = AuditRegistrationRejected
+	}
+
+	result := &ReviewResult{Updated: make([]string, 0, len(updated))}
+	done := make(map[uuid.UUID]bool, len(updated))
+	for _, id := range updated {
+		done[id] = true
+		result.Updated = append(result.Updated, id.String())
+		s.audit.Record(reviewerID, action, AuditEntityParticipant, id.String(), map[string]interface{}{
+			"event_id": eventID,
+			"reason":   reason,
+		})
+	}
+	for _, id := range ids {
+		if !done[id] {
+			result.NotFound = append(result.NotFound, id.String())
+		}
+	}
+
+	return result, nil
+}
+
+// SendReviewDecision tells a participant the decision on their registration.
+// Approved participants holding a QR code are sent their ticket; those still
+// to sign waivers and rejected ones get an email, with the reason for a
+// rejection. The current status is sent, so a registration reviewed again
+// before the notice went out gets the latest decision.
+func (s *NotificationService) SendReviewDecision(ctx context.Context, participantID string) error {
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return ErrUnknownParticipant
+	}
+	if participant.CancelledAt != nil {
+		return ErrTicketCancelled
+	}
+	switch participant.ApprovalStatus {
+	case ApprovalApproved:
+		if participant.QRPath != "" {
+			_, err := s.ResendTicket(ctx, "", participantID)
+			return err
+		}
+	case ApprovalRejected:
+	default:
+		return ErrNoReviewDecision
+	}
+	event, err := s.repo.EventRepo.GetEventByID(participant.EventID.String())
+	if err != nil {
+		return ErrUnknownEvent
+	}
+	return s.mailer.Send(ctx, reviewDecisionMessage(event, participant))
+}
+
+func reviewDecisionMessage(event *models.Event, participant *models.Participant) mail.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\n", participant.Name)
+	if participant.ApprovalStatus == ApprovalApproved {
+		fmt.Fprintf(&body, "Your registration for %s has been approved. Your ticket will be sent to you once you have signed the event's waivers.\n", event.Title)
+		return mail.Message{
+			To:      participant.Email,
+			Subject: fmt.Sprintf("Your registration for %s is approved", event.Title),
+			Body:    body.String(),
+		}
+	}
+
+	fmt.Fprintf(&body, "We are sorry, your registration for %s was not approved.\n", event.Title)
+	if participant.RejectionReason != "" {
+		fmt.Fprintf(&body, "\nReason: %s\n", participant.RejectionReason)
+	}
+	return mail.Message{
+		To:      participant.Email,
+		Subject: fmt.Sprintf("Your registration for %s", event.Title),
+		Body:    body.String(),
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/mail"
+	"event-management-backend/internal/models"
+	"event-management-backend/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// ApprovalPendingConfirmation is the approval status of registrations whose
+// email address is not confirmed yet. They hold a ticket but get no QR code
+// until confirmed, and are deleted when not confirmed in time.
+const ApprovalPendingConfirmation = "pending_confirmation"
+
+// MaxConfirmationHours is the longest an event can give registrants to
+// confirm their email address
+const MaxConfirmationHours = 168
+
+// expiryBatchSize is how many unconfirmed registrations are deleted at a time
+const expiryBatchSize = 200
+
+var (
+	// ErrInvalidConfirmationLink is returned for forged confirmation links
+	// and links of registrations that expired
+	ErrInvalidConfirmationLink = errors.New("invalid or expired confirmation link")
+	// ErrRegistrationNotPendingConfirmation is returned when sending the
+	// confirmation email of a registration that needs none
+	ErrRegistrationNotPendingConfirmation = errors.New("registration is not awaiting email confirmation")
+)
+
+func validateConfirmationHours(hours int) error {
+	if hours < 0 || hours > MaxConfirmationHours {
+		return fmt.Errorf("confirmation hours must be between 0 and %d", MaxConfirmationHours)
+	}
+	return nil
+}
+
+// requireConfirmation holds a new registration of an event requiring email
+// confirmation until it is confirmed
+func requireConfirmation(event *models.Event, participant *models.Participant, now time.Time) {
+	if event.ConfirmationHours <= 0 {
+		return
+	}
+	expiresAt := now.Add(time.Duration(event.ConfirmationHours) * time.Hour)
+	participant.ApprovalStatus = ApprovalPendingConfirmation
+	participant.ConfirmationExpiresAt = &expiresAt
+}
+
+// ConfirmationURL returns the link a participant follows to confirm their
+// email address
+func ConfirmationURL(cfg *config.Config, participantID string) string {
+	return fmt.Sprintf("%s/api/v2/register/confirm/%s", strings.TrimRight(cfg.PublicBaseURL, "/"), confirmationToken(cfg, participantID))
+}
+
+func confirmationToken(cfg *config.Config, participantID string) string {
+	mac := hmac.New(sha256.New, []byte("registration-confirm:"+cfg.CredentialSigningKey))
+	mac.Write([]byte(participantID))
+	return participantID + "." + hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// ConfirmRegistration confirms the email address of the registration a
+// confirmation link was sent for. The registration then waits for approval
+// when the event requires it, otherwise its QR code is issued. Following the
+// link again returns the registration unchanged; confirmed tells whether this
+// call confirmed it.
+func (s *ParticipantService) ConfirmRegistration(token string) (participant *models.Participant, confirmed bool, err error) {
+	participantID, _, found := strings.Cut(token, ".")
+	if !found {
+		return nil, false, ErrInvalidConfirmationLink
+	}
+	if _, err := uuid.Parse(participantID); err != nil {
+		return nil, false, ErrInvalidConfirmationLink
+	}
+	if !hmac.Equal([]byte(token), []byte(confirmationToken(s.cfg, participantID))) {
+		return nil, false, ErrInvalidConfirmationLink
+	}
+
+	participant, err = s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil || participant.CancelledAt != nil {
+		return nil, false, ErrInvalidConfirmationLink
+	}
+	if participant.ApprovalStatus != ApprovalPendingConfirmation {
+		return participant, false, nil
+	}
+	// Expired registrations may not have been deleted yet
+	if participant.ConfirmationExpiresAt != nil && time.Now().After(*participant.ConfirmationExpiresAt) {
+		return nil, false, ErrInvalidConfirmationLink
+	}
+	event, err := s.repo.EventRepo.GetEventByID(participant.EventID.String())
+	if err != nil {
+		return nil, false, err
+	}
+
+	participant.ApprovalStatus = ApprovalApproved
+	if event.RequiresApproval {
+		participant.ApprovalStatus = ApprovalPending
+	}
+	participant.ConfirmationExpiresAt = nil
+	if err := s.repo.ParticipantRepo.UpdateParticipant(participant); err != nil {
+		return nil, false, err
+	}
+	if _, err := s.ReleaseQR(participant); err != nil {
+		logger.Log.WithError(err).WithField("participant_id", participantID).Warn("failed to release QR code after confirmation")
+	}
+
+	s.audit.Record("", AuditRegistrationConfirmed, AuditEntityParticipant, participantID, map[string]interface{}{
+		"event_id": participant.EventID.String(),
+	})
+	return participant, true, nil
+}
+
+// ExpireUnconfirmedRegistrations deletes the registrations whose email
+// address was not confirmed in time, freeing their tickets, and returns them
+func (s *ParticipantService) ExpireUnconfirmedRegistrations(ctx context.Context) ([]models.Participant, error) {
+	expired := []models.Participant{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return expired, err
+		}
+		participants, err := s.repo.ParticipantRepo.DeleteUnconfirmedParticipants(time.Now(), expiryBatchSize)
+		if err != nil {
+			return expired, err
+		}
+		for _, participant := range participants {
+			s.audit.Record("", AuditRegistrationExpired, AuditEntityParticipant, participant.ID.String(), map[string]interface{}{
+				"event_id": participant.EventID.String(),
+			})
+		}
+		expired = append(expired, participants...)
+		if len(participants) < expiryBatchSize {
+			return expired, nil
+		}
+	}
+}
+
+// SendRegistrationConfirmation emails a participant the link confirming
+// their email address
+func (s *NotificationService) SendRegistrationConfirmation(ctx context.Context, participantID string) error {
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return ErrUnknownParticipant
+	}
+	if participant.ApprovalStatus != ApprovalPendingConfirmation || participant.ConfirmationExpiresAt == nil {
+		return ErrRegistrationNotPendingConfirmation
+	}
+	event, err := s.repo.EventRepo.GetEventByID(participant.EventID.String())
+	if err != nil {
+		return ErrUnknownEvent
+	}
+	return s.mailer.Send(ctx, s.confirmationMessage(event, participant))
+}
+
+func (s *NotificationService) confirmationMessage(event *models.Event, participant *models.Participant) mail.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\n", participant.Name)
+	fmt.Fprintf(&body, "Please confirm your registration for %s by following this link:\n\n", event.Title)
+	fmt.Fprintf(&body, "%s\n\n", ConfirmationURL(s.cfg, participant.ID.String()))
+	fmt.Fprintf(&body, "Registrations not confirmed by %s expire and free their ticket. If you did not register, you can ignore this email.\n",
+		participant.ConfirmationExpiresAt.Format("Monday, 2 January 2006 15:04 MST"))
+
+	return mail.Message{
+		To:      participant.Email,
+		Subject: fmt.Sprintf("Confirm your registration for %s", event.Title),
+		Body:    body.String(),
+	}
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+)
+
+// Types of registration form fields, see models.FormField
+const (
+	FormFieldText    = "text"
+	FormFieldNumber  = "number"
+	FormFieldBoolean = "boolean"
+	FormFieldSelect  = "select"
+	FormFieldDate    = "date"
+)
+
+const (
+	// MaxFormFields is how many custom questions a registration form holds
+	MaxFormFields = 50
+	// maxAnswerLength caps text answers, in characters
+	maxAnswerLength = 2000
+)
+
+var formFieldKey = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// validateFormFields checks the custom questions of a registration form and
+// trims their labels and options
+func validateFormFields(fields []models.FormField) error {
+	if len(fields) > MaxFormFields {
+		return fmt.Errorf("a registration form has at most %d fields", MaxFormFields)
+	}
+	seen := map[string]bool{}
+	for i := range fields {
+		field := &fields[i]
+		if !formFieldKey.MatchString(field.Key) {
+			return fmt.Errorf("form field key %q must be lowercase letters, digits and underscores", field.Key)
+		}
+		if seen[field.Key] {
+			return fmt.Errorf("form field key %q is used twice", field.Key)
+		}
+		seen[field.Key] = true
+		if field.Label = strings.TrimSpace(field.Label); field.Label == "" {
+			return fmt.Errorf("form field %s: label is required", field.Key)
+		}
+
+		switch field.Type {
+		case FormFieldText, FormFieldNumber, FormFieldBoolean, FormFieldDate:
+			field.Options = nil
+		case FormFieldSelect:
+			if len(field.Options) == 0 {
+				return fmt.Errorf("form field %s: select fields need options", field.Key)
+			}
+			for j := range field.Options {
+				if field.Options[j] = strings.TrimSpace(field.Options[j]); field.Options[j] == "" {
+					return fmt.Errorf("form field %s: options must not be empty", field.Key)
+				}
+			}
+		default:
+			return fmt.Errorf("form field %s: type must be text, number, boolean, select or date", field.Key)
+		}
+	}
+	return nil
+}
+
+// validateAnswers checks the answers of a registration against the event's
+// form fields and returns them normalized: text trimmed, numbers as float64,
+// dates as YYYY-MM-DD. Unanswered optional fields are left out; nil is
+// returned when nothing is answered.
+func validateAnswers(fields []models.FormField, answers map[string]interface{}) (map[string]interface{}, error) {
+	known := map[string]bool{}
+	for _, field := range fields {
+		known[field.Key] = true
+	}
+	for key := range answers {
+		if !known[key] {
+			return nil, fmt.Errorf("answers: unknown field %q", key)
+		}
+	}
+
+	var normalized map[string]interface{}
+	for _, field := range fields {
+		value, err := normalizeAnswer(field, answers[field.Key])
+		if err != nil {
+			return nil, fmt.Errorf("answers.%s: %v", field.Key, err)
+		}
+		if value == nil {
+			if field.Required {
+				return nil, fmt.Errorf("answers.%s: %s is required", field.Key, field.Label)
+			}
+			continue
+		}
+		if normalized == nil {
+			normalized = map[string]interface{}{}
+		}
+		normalized[field.Key] = value
+	}
+	return normalized, nil
+}
+
+// normalizeAnswer checks one answer; nil and blank strings count as no
+// answer and yield nil
+func normalizeAnswer(field models.FormField, answer interface{}) (interface{}, error) {
+	if s, ok := answer.(string); ok {
+		if answer = strings.TrimSpace(s); answer == "" {
+			return nil, nil
+		}
+	}
+	if answer == nil {
+		return nil, nil
+	}
+
+	switch field.Type {
+	case FormFieldNumber:
+		if n, ok := answer.(float64); ok {
+			return n, nil
+		}
+		return nil, errors.New("must be a number")
+	case FormFieldBoolean:
+		if b, ok := answer.(bool); ok {
+			return b, nil
+		}
+		return nil, errors.New("must be true or false")
+	}
+
+	s, ok := answer.(string)
+	if !ok {
+		return nil, errors.New("must be a string")
+	}
+	switch field.Type {
+	case FormFieldSelect:
+		for _, option := range field.Options {
+			if strings.EqualFold(s, option) {
+				return option, nil
+			}
+		}
+		return nil, fmt.Errorf("must be one of %s", strings.Join(field.Options, ", "))
+	case FormFieldDate:
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return nil, errors.New("must be a date as YYYY-MM-DD")
+		}
+	default:
+		if len([]rune(s)) > maxAnswerLength {
+			return nil, fmt.Errorf("must be at most %d characters", maxAnswerLength)
+		}
+	}
+	return s, nil
+}
+
+// validateAnswerFilters checks that the answer filters of a participant
+// listing name fields of the event's form
+func (s *ParticipantService) validateAnswerFilters(eventID string, filters *repositories.ParticipantFilters) error {
+	if filters == nil || len(filters.Answers) == 0 {
+		return nil
+	}
+	known := map[string]bool{}
+	if event, err := s.repo.EventRepo.GetEventByID(eventID); err == nil {
+		for _, field := range event.FormFields {
+			known[field.Key] = true
+		}
+	}
+	keys := make([]string, 0, len(filters.Answers))
+	for key := range filters.Answers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !known[key] {
+			return fmt.Errorf("%w: the registration form has no field %q", ErrInvalidParticipantFilter, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"event-management-backend/internal/models"
+)
+
+// Registration states of an event
+const (
+	RegistrationUpcoming = "upcoming"
+	RegistrationOpen     = "open"
+	RegistrationClosed   = "closed"
+)
+
+var (
+	ErrRegistrationNotOpen = errors.New("registration has not opened yet")
+	ErrRegistrationClosed  = errors.New("registration is closed")
+)
+
+// registrationClosesAt is when registration for an event ends: its
+// registration_closes_at, or the end of the event
+func registrationClosesAt(event *models.Event) time.Time {
+	if event.RegistrationClosesAt != nil {
+		return *event.RegistrationClosesAt
+	}
+	return event.EndsAt
+}
+
+// registrationState reports whether an event takes registrations at now.
+// Only published events ever do.
+func registrationState(event *models.Event, now time.Time) string {
+	switch {
+	case event.Status != EventPublished:
+		return RegistrationClosed
+	case event.RegistrationOpensAt != nil && now.Before(*event.RegistrationOpensAt):
+		return RegistrationUpcoming
+	case !now.Before(registrationClosesAt(event)):
+		return RegistrationClosed
+	}
+	return RegistrationOpen
+}
+
+// checkRegistrationWindow refuses registrations outside the event's
+// registration window
+func checkRegistrationWindow(event *models.Event, now time.Time) error {
+	switch registrationState(event, now) {
+	case RegistrationUpcoming:
+		return ErrRegistrationNotOpen
+	case RegistrationClosed:
+		return ErrRegistrationClosed
+	}
+	return nil
+}
+
+// validateRegistrationWindow checks a window against the event's dates
+func validateRegistrationWindow(opensAt, closesAt *time.Time, endsAt time.Time) error {
+	if opensAt != nil && closesAt != nil && !closesAt.After(*opensAt) {
+		return errors.New("registration must close after it opens")
+	}
+	if opensAt != nil && !opensAt.Before(endsAt) {
+		return errors.New("registration must open before the event ends")
+	}
+	return nil
+}
+
+// withRegistrationState fills in the registration state of events
+func withRegistrationState(events ...*models.Event) {
+	now := time.Now()
+	for _, event := range events {
+		event.RegistrationState = registrationState(event, now)
+	}
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Retention actions
+const (
+	RetentionAnonymizePII  = "anonymize_pii"
+	RetentionDeleteQRFiles = "delete_qr_files"
+)
+
+// qrDeleteBatch is how many QR files are removed per query
+const qrDeleteBatch = 500
+
+var (
+	ErrInvalidRetentionAction = errors.New("action must be anonymize_pii or delete_qr_files")
+	ErrRetentionRuleNotFound  = errors.New("retention rule not found")
+	ErrLegalHoldExists        = errors.New("event is already under legal hold")
+	ErrLegalHoldNotFound      = errors.New("event is not under legal hold")
+	ErrRetentionRunning       = errors.New("a retention run is already in progress")
+)
+
+// RetentionService applies retention rules to ended events. Events under a
+// legal hold are skipped and listed in the report.
+type RetentionService struct {
+	repo *repositories.Repository
+	cfg  *config.Config
+
+	mu      sync.Mutex
+	running bool
+	lastRun *RetentionReport
+}
+
+func NewRetentionService(repo *repositories.Repository, cfg *config.Config) *RetentionService {
+	return &RetentionService{repo: repo, cfg: cfg}
+}
+
+// RetentionReport lists what a run changed, or would change when DryRun is
+// set
+type RetentionReport struct {
+	DryRun bool                  `json:"dry_run"`
+	RanAt  time.Time             `json:"ran_at"`
+	Rules  []RetentionRuleReport `json:"rules"`
+	Held   []HeldEvent           `json:"held"`
+	Error  string                `json:"error,omitempty"`
+}
+
+type RetentionRuleReport struct {
+	RuleID    uuid.UUID              `json:"rule_id"`
+	Action    string                 `json:"action"`
+	AfterDays int                    `json:"after_days"`
+	Events    []RetentionEventReport `json:"events"`
+}
+
+type RetentionEventReport struct {
+	EventID    uuid.UUID `json:"event_id"`
+	EventTitle string    `json:"event_title"`
+	EndedAt    time.Time `json:"ended_at"`
+	// Participants anonymized or QR files deleted
+	Affected int64 `json:"affected"`
+}
+
+// HeldEvent is an ended event skipped because of a legal hold
+type HeldEvent struct {
+	EventID    uuid.UUID `json:"event_id"`
+	EventTitle string    `json:"event_title"`
+	Reason     string    `json:"reason"`
+}
+
+// CreateRule adds a retention rule
+func (s *RetentionService) CreateRule(createdBy, action string, afterDays int) (*models.RetentionRule, error) {
+	if action != RetentionAnonymizePII && action != RetentionDeleteQRFiles {
+		return nil, ErrInvalidRetentionAction
+	}
+	if afterDays < 1 {
+		return nil, errors.New("after_days must be at least 1")
+	}
+	userUUID, err := uuid.Parse(createdBy)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	rule := &models.RetentionRule{
+		ID:        uuid.New(),
+		Action:    action,
+		AfterDays: afterDays,
+		CreatedBy: userUUID,
+	}
+	if err := s.repo.RetentionRepo.CreateRule(rule); err != nil {
+		return nil, fmt.Errorf("failed to create retention rule: %w", err)
+	}
+	return rule, nil
+}
+
+func (s *RetentionService) ListRules() ([]models.RetentionRule, error) {
+	return s.repo.RetentionRepo.ListRules()
+}
+
+func (s *RetentionService) DeleteRule(id string) error {
+	if err := s.repo.RetentionRepo.DeleteRule(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRetentionRuleNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// PlaceLegalHold exempts an event from retention until the hold is released
+func (s *RetentionService) PlaceLegalHold(eventID, reason, createdBy string) (*models.LegalHold, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+	userUUID, err := uuid.Parse(createdBy)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	holds, err := s.repo.RetentionRepo.ListLegalHolds()
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range holds {
+		if existing.EventID == event.ID {
+			return nil, ErrLegalHoldExists
+		}
+	}
+
+	hold := &models.LegalHold{
+		ID:        uuid.New(),
+		EventID:   event.ID,
+		Reason:    reason,
+		CreatedBy: userUUID,
+	}
+	if err := s.repo.RetentionRepo.CreateLegalHold(hold); err != nil {
+		return nil, fmt.Errorf("failed to place legal hold: %w", err)
+	}
+	return hold, nil
+}
+
+func (s *RetentionService) ReleaseLegalHold(eventID string) error {
+	if err := s.repo.RetentionRepo.DeleteLegalHold(eventID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrLegalHoldNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *RetentionService) ListLegalHolds() ([]models.LegalHold, error) {
+	return s.repo.RetentionRepo.ListLegalHolds()
+}
+
+// LastRun returns the report of the latest run that was not a dry run
+func (s *RetentionService) LastRun() *RetentionReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRun
+}
+
+// Run applies every retention rule. With dryRun, nothing is changed and the
+// report counts what a real run would affect.
+func (s *RetentionService) Run(ctx context.Context, dryRun bool) (*RetentionReport, error) {
+	if !dryRun {
+		s.mu.Lock()
+		if s.running {
+			s.mu.Unlock()
+			return nil, ErrRetentionRunning
+		}
+		s.running = true
+		s.mu.Unlock()
+	}
+
+	report, err := s.run(ctx, dryRun)
+
+	if !dryRun {
+		if err != nil {
+			report.Error = err.Error()
+		}
+		s.mu.Lock()
+		s.running = false
+		s.lastRun = report
+		s.mu.Unlock()
+	}
+	return report, err
+}
+
+func (s *RetentionService) run(ctx context.Context, dryRun bool) (*RetentionReport, error) {
+	now := time.Now()
+	report := &RetentionReport{
+		DryRun: dryRun,
+		RanAt:  now,
+		Rules:  make([]RetentionRuleReport, 0),
+		Held:   make([]HeldEvent, 0),
+	}
+
+	rules, err := s.repo.RetentionRepo.ListRules()
+	if err != nil {
+		return report, err
+	}
+	holds, err := s.repo.RetentionRepo.ListLegalHolds()
+	if err != nil {
+		return report, err
+	}
+	held := make(map[uuid.UUID]string, len(holds))
+	for _, hold := range holds {
+		held[hold.EventID] = hold.Reason
+	}
+	reported := make(map[uuid.UUID]bool)
+
+	for _, rule := range rules {
+		ruleReport := RetentionRuleReport{
+			RuleID:    rule.ID,
+			Action:    rule.Action,
+			AfterDays: rule.AfterDays,
+			Events:    make([]RetentionEventReport, 0),
+		}
+
+		cutoff := now.AddDate(0, 0, -rule.AfterDays)
+		events, err := s.repo.RetentionRepo.ListEventsEndedBefore(cutoff)
+		if err != nil {
+			return report, err
+		}
+
+		for _, event := range events {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			if reason, ok := held[event.ID]; ok {
+				if !reported[event.ID] {
+					reported[event.ID] = true
+					report.Held = append(report.Held, HeldEvent{EventID: event.ID, EventTitle: event.Title, Reason: reason})
+				}
+				continue
+			}
+
+			affected, err := s.apply(rule.Action, event.ID.String(), now, dryRun)
+			if err != nil {
+				return report, fmt.Errorf("event %s: %w", event.ID, err)
+			}
+			if affected > 0 {
+				ruleReport.Events = append(ruleReport.Events, RetentionEventReport{
+					EventID:    event.ID,
+					EventTitle: event.Title,
+					EndedAt:    event.EndsAt,
+					Affected:   affected,
+				})
+			}
+		}
+
+		report.Rules = append(report.Rules, ruleReport)
+	}
+
+	return report, nil
+}
+
+// apply runs one retention action on an event and returns the number of
+// participants it affected
+func (s *RetentionService) apply(action, eventID string, now time.Time, dryRun bool) (int64, error) {
+	switch action {
+	case RetentionAnonymizePII:
+		if dryRun {
+			return s.repo.RetentionRepo.CountPendingAnonymization(eventID)
+		}
+		return s.repo.RetentionRepo.AnonymizeParticipants(eventID, now)
+	case RetentionDeleteQRFiles:
+		if dryRun {
+			return s.repo.RetentionRepo.CountQRFiles(eventID)
+		}
+		return s.deleteQRFiles(eventID)
+	}
+	return 0, ErrInvalidRetentionAction
+}
+
+// deleteQRFiles removes the QR code images of an event's participants and
+// clears their QR paths. Files already gone are treated as deleted.
+func (s *RetentionService) deleteQRFiles(eventID string) (int64, error) {
+	var deleted int64
+	for {
+		participants, err := s.repo.RetentionRepo.ListQRFiles(eventID, qrDeleteBatch)
+		if err != nil {
+			return deleted, err
+		}
+		if len(participants) == 0 {
+			return deleted, nil
+		}
+
+		ids := make([]uuid.UUID, 0, len(participants))
+		for _, participant := range participants {
+			path := filepath.Join(s.cfg.QRDir, filepath.Base(participant.QRPath))
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return deleted, fmt.Errorf("failed to delete QR file: %w", err)
+			}
+			ids = append(ids, participant.ID)
+		}
+		if err := s.repo.RetentionRepo.ClearQRPaths(ids); err != nil {
+			return deleted, err
+		}
+		deleted += int64(len(ids))
+	}
+}
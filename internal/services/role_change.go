@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Statuses of a role change request
+const (
+	RoleChangePending  = "pending"
+	RoleChangeApproved = "approved"
+	RoleChangeRejected = "rejected"
+)
+
+var (
+	// ErrRoleChangeNeedsApproval is returned by UpdateUser for promotions,
+	// which go through RequestRoleChange
+	ErrRoleChangeNeedsApproval = errors.New("promotions need a second admin's approval; request a role change instead")
+	ErrRoleChangeNotFound      = errors.New("role change request not found")
+	ErrRoleChangePending       = errors.New("user already has a pending role change")
+	ErrRoleChangeReviewed      = errors.New("role change request was already reviewed")
+	ErrRoleChangeSelfReview    = errors.New("role changes must be reviewed by another admin")
+	ErrRoleChangeStale         = errors.New("the user's role changed since the request; reject it and request again")
+)
+
+// roleRanks orders roles by privilege. Other roles, like sponsor and
+// scanner, rank below staff.
+var roleRanks = map[string]int{"staff": 1, "organizer": 2, "admin": 3}
+
+// isPromotion reports whether changing from one role to another grants more
+// privileges
+func isPromotion(from, to string) bool {
+	return roleRanks[to] > roleRanks[from]
+}
+
+// RequestRoleChange asks for a user's role to be changed. The change is only
+// applied once another admin approves it.
+func (s *AuthService) RequestRoleChange(actorID, userID, role, reason string) (*models.RoleChangeRequest, error) {
+	requester, err := uuid.Parse(actorID)
+	if err != nil {
+		return nil, errors.New("invalid requester")
+	}
+	role = strings.TrimSpace(strings.ToLower(role))
+	if !allowedRoles[role] {
+		return nil, errors.New("invalid role: must be admin, organizer, or staff")
+	}
+
+	user, err := s.repo.UserRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if userID == actorID {
+		return nil, ErrModifyOwnAccount
+	}
+	if !user.IsActive {
+		return nil, ErrUserDeactivated
+	}
+	if user.Role == role {
+		return nil, fmt.Errorf("user already has the %s role", role)
+	}
+	pending, err := s.repo.RoleChangeRepo.HasPendingRoleChange(userID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, ErrRoleChangePending
+	}
+
+	change := &models.RoleChangeRequest{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		FromRole:    user.Role,
+		ToRole:      role,
+		Reason:      strings.TrimSpace(reason),
+		Status:      RoleChangePending,
+		RequestedBy: requester,
+	}
+	if err := s.repo.RoleChangeRepo.CreateRoleChange(change); err != nil {
+		return nil, fmt.Errorf("failed to create role change request: %w", err)
+	}
+	s.audit.Record(actorID, AuditRoleChangeRequested, AuditEntityUser, userID, map[string]interface{}{
+		"request_id": change.ID.String(),
+		"from_role":  change.FromRole,
+		"to_role":    change.ToRole,
+	})
+	return change, nil
+}
+
+// ListRoleChanges returns a page of role change requests, newest first. An
+// empty status lists all of them.
+func (s *AuthService) ListRoleChanges(status string, page, pageSize int) ([]models.RoleChangeRequest, int64, int, error) {
+	switch status {
+	case "", RoleChangePending, RoleChangeApproved, RoleChangeRejected:
+	default:
+		return nil, 0, 0, errors.New("status must be pending, approved or rejected")
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	changes, total, err := s.repo.RoleChangeRepo.ListRoleChanges(status, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	totalPages := (int(total) + pageSize - 1) / pageSize
+	return changes, total, totalPages, nil
+}
+
+// ApproveRoleChange applies a pending role change and revokes the user's
+// tokens, which were issued for the old role
+func (s *AuthService) ApproveRoleChange(actorID, id string) (*models.RoleChangeRequest, error) {
+	return s.reviewRoleChange(actorID, id, true)
+}
+
+// RejectRoleChange closes a pending role change without applying it
+func (s *AuthService) RejectRoleChange(actorID, id string) (*models.RoleChangeRequest, error) {
+	return s.reviewRoleChange(actorID, id, false)
+}
+
+func (s *AuthService) reviewRoleChange(actorID, id string, approve bool) (*models.RoleChangeRequest, error) {
+	reviewer, err := uuid.Parse(actorID)
+	if err != nil {
+		return nil, errors.New("invalid reviewer")
+	}
+	change, err := s.repo.RoleChangeRepo.GetRoleChange(id)
+	if err != nil {
+		return nil, ErrRoleChangeNotFound
+	}
+	if change.Status != RoleChangePending {
+		return nil, ErrRoleChangeReviewed
+	}
+	if change.RequestedBy == reviewer || change.UserID == reviewer {
+		return nil, ErrRoleChangeSelfReview
+	}
+
+	if err := s.repo.RoleChangeRepo.ReviewRoleChange(id, reviewer, approve, time.Now()); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, ErrRoleChangeReviewed
+		case errors.Is(err, repositories.ErrVersionConflict):
+			return nil, ErrRoleChangeStale
+		}
+		return nil, err
+	}
+
+	action := AuditRoleChangeRejected
+	if approve {
+		action = AuditRoleChangeApproved
+	}
+	s.audit.Record(actorID, action, AuditEntityUser, change.UserID.String(), map[string]interface{}{
+		"request_id": id,
+		"from_role":  change.FromRole,
+		"to_role":    change.ToRole,
+	})
+
+	return s.repo.RoleChangeRepo.GetRoleChange(id)
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRoleChange(t *testing.T) {
+	e := newTestEnv(t)
+	svc := e.authService()
+	requester := e.fx.User("admin")
+	reviewer := e.fx.User("admin")
+	staff := e.fx.User("staff")
+	issuedAt := time.Now().Add(-time.Minute)
+
+	organizer := "organizer"
+	if _, err := svc.UpdateUser(requester.ID.String(), staff.ID.String(), UpdateUserRequest{Role: &organizer}); !errors.Is(err, ErrRoleChangeNeedsApproval) {
+		t.Fatalf("promotion by update: error = %v, want %v", err, ErrRoleChangeNeedsApproval)
+	}
+
+	change, err := svc.RequestRoleChange(requester.ID.String(), staff.ID.String(), organizer, "Runs the Bandung expo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.RequestRoleChange(reviewer.ID.String(), staff.ID.String(), "admin", ""); !errors.Is(err, ErrRoleChangePending) {
+		t.Fatalf("second request: error = %v, want %v", err, ErrRoleChangePending)
+	}
+	if user, _ := svc.GetUserProfile(staff.ID.String()); user.Role != "staff" {
+		t.Fatalf("role = %q before approval, want staff", user.Role)
+	}
+
+	if _, err := svc.ApproveRoleChange(requester.ID.String(), change.ID.String()); !errors.Is(err, ErrRoleChangeSelfReview) {
+		t.Fatalf("approval by the requester: error = %v, want %v", err, ErrRoleChangeSelfReview)
+	}
+	approved, err := svc.ApproveRoleChange(reviewer.ID.String(), change.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.Status != RoleChangeApproved || approved.ReviewedBy == nil || *approved.ReviewedBy != reviewer.ID {
+		t.Fatalf("change = %+v, want approved by %s", approved, reviewer.ID)
+	}
+	if user, _ := svc.GetUserProfile(staff.ID.String()); user.Role != organizer {
+		t.Fatalf("role = %q after approval, want %s", user.Role, organizer)
+	}
+	if err := svc.ValidateToken(staff.ID.String(), "", issuedAt); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("old token: error = %v, want %v", err, ErrTokenRevoked)
+	}
+	if _, err := svc.RejectRoleChange(reviewer.ID.String(), change.ID.String()); !errors.Is(err, ErrRoleChangeReviewed) {
+		t.Fatalf("reviewing twice: error = %v, want %v", err, ErrRoleChangeReviewed)
+	}
+
+	// A change requested from a role the user no longer has is not applied
+	stale, err := svc.RequestRoleChange(requester.ID.String(), staff.ID.String(), "admin", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	staffRole := "staff"
+	if _, err := svc.UpdateUser(requester.ID.String(), staff.ID.String(), UpdateUserRequest{Role: &staffRole}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.ApproveRoleChange(reviewer.ID.String(), stale.ID.String()); !errors.Is(err, ErrRoleChangeStale) {
+		t.Fatalf("stale change: error = %v, want %v", err, ErrRoleChangeStale)
+	}
+
+	pending, total, _, err := svc.ListRoleChanges(RoleChangePending, 1, 20)
+	if err != nil || total != 1 || pending[0].ID != stale.ID {
+		t.Fatalf("pending = %v (%d), %v; want only %s", pending, total, err, stale.ID)
+	}
+
+	logs, _, _, err := NewAuditService(e.repo, e.cfg).List(AuditLogQuery{EntityType: AuditEntityUser, EntityID: staff.ID.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var approvals int
+	for _, log := range logs {
+		if log.Action == AuditRoleChangeApproved {
+			approvals++
+		}
+	}
+	if approvals != 1 {
+		t.Fatalf("%d approvals audited, want 1", approvals)
+	}
+}
@@ -0,0 +1,348 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrSeatingDayNotFound is returned when the day does not exist or belongs
+	// to another event
+	ErrSeatingDayNotFound = errors.New("event day not found")
+	// ErrTableNotFound is returned when the table does not exist or belongs to
+	// another day
+	ErrTableNotFound = errors.New("table not found")
+)
+
+// SeatingService manages the tables of seated event days, such as gala
+// dinners, and who sits where
+type SeatingService struct {
+	repo *repositories.Repository
+	cfg  *config.Config
+}
+
+func NewSeatingService(repo *repositories.Repository, cfg *config.Config) *SeatingService {
+	return &SeatingService{repo: repo, cfg: cfg}
+}
+
+type CreateTableRequest struct {
+	Name     string
+	Capacity int
+	Division string
+}
+
+// TableSeating is a table with its occupied seats
+type TableSeating struct {
+	models.SeatingTable
+	Seats     []SeatOccupant `json:"seats"`
+	FreeSeats int            `json:"free_seats"`
+}
+
+type SeatOccupant struct {
+	SeatNumber      int    `json:"seat_number"`
+	ParticipantID   string `json:"participant_id"`
+	ParticipantName string `json:"participant_name"`
+	Division        string `json:"division"`
+}
+
+// DaySeating is the seating plan of an event day
+type DaySeating struct {
+	Tables []TableSeating `json:"tables"`
+	// Approved participants without a seat
+	Unseated int `json:"unseated"`
+}
+
+// AutoAssignResult reports the outcome of an auto-assignment
+type AutoAssignResult struct {
+	Assigned int `json:"assigned"`
+	// Participants left without a seat because the tables are full
+	Unseated []string `json:"unseated,omitempty"`
+}
+
+// BadgeSeat is a row of the seat export used to print badges
+type BadgeSeat struct {
+	ParticipantID   string `json:"participant_id"`
+	ParticipantName string `json:"participant_name"`
+	Division        string `json:"division"`
+	DayLabel        string `json:"day_label"`
+	TableName       string `json:"table_name"`
+	SeatNumber      int    `json:"seat_number"`
+}
+
+func (s *SeatingService) CreateTable(eventID, dayID string, req CreateTableRequest) (*models.SeatingTable, error) {
+	day, err := s.day(eventID, dayID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Capacity <= 0 {
+		return nil, errors.New("capacity must be positive")
+	}
+
+	table := &models.SeatingTable{
+		ID:         uuid.New(),
+		EventID:    day.EventID,
+		EventDayID: day.ID,
+		Name:       strings.TrimSpace(req.Name),
+		Capacity:   req.Capacity,
+		Division:   strings.TrimSpace(req.Division),
+	}
+	if err := s.repo.SeatingRepo.CreateTable(table); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+func (s *SeatingService) ListTables(eventID, dayID string) ([]models.SeatingTable, error) {
+	if _, err := s.day(eventID, dayID); err != nil {
+		return nil, err
+	}
+	return s.repo.SeatingRepo.ListTablesByDay(dayID)
+}
+
+// DaySeating returns every table of the day with its occupants
+func (s *SeatingService) DaySeating(eventID, dayID string) (*DaySeating, error) {
+	if _, err := s.day(eventID, dayID); err != nil {
+		return nil, err
+	}
+	tables, err := s.repo.SeatingRepo.ListTablesByDay(dayID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.repo.SeatingRepo.ListAssignmentsByDay(dayID)
+	if err != nil {
+		return nil, err
+	}
+
+	seats := make(map[uuid.UUID][]SeatOccupant, len(tables))
+	seated := make(map[uuid.UUID]bool, len(assignments))
+	for _, assignment := range assignments {
+		seated[assignment.ParticipantID] = true
+		seats[assignment.TableID] = append(seats[assignment.TableID], SeatOccupant{
+			SeatNumber:      assignment.SeatNumber,
+			ParticipantID:   assignment.ParticipantID.String(),
+			ParticipantName: assignment.Participant.Name,
+			Division:        assignment.Participant.Division,
+		})
+	}
+
+	plan := &DaySeating{Tables: make([]TableSeating, 0, len(tables))}
+	for _, table := range tables {
+		occupants := seats[table.ID]
+		if occupants == nil {
+			occupants = []SeatOccupant{}
+		}
+		plan.Tables = append(plan.Tables, TableSeating{
+			SeatingTable: table,
+			Seats:        occupants,
+			FreeSeats:    table.Capacity - len(occupants),
+		})
+	}
+
+	participants, _, err := s.repo.ParticipantRepo.ListParticipantsByApprovalStatus(eventID, ApprovalApproved, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+	for _, participant := range participants {
+		if !seated[participant.ID] {
+			plan.Unseated++
+		}
+	}
+
+	return plan, nil
+}
+
+// AssignSeat seats a participant at a table, moving them if they already have
+// a seat that day. A zero seat number takes the first free seat.
+func (s *SeatingService) AssignSeat(eventID, dayID, participantID, tableID string, seatNumber int) (*models.SeatAssignment, error) {
+	day, err := s.day(eventID, dayID)
+	if err != nil {
+		return nil, err
+	}
+	table, err := s.repo.SeatingRepo.GetTableByID(tableID)
+	if err != nil || table.EventDayID != day.ID {
+		return nil, ErrTableNotFound
+	}
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil || participant.EventID != day.EventID {
+		return nil, errors.New("participant not found")
+	}
+
+	assignment := &models.SeatAssignment{
+		ID:            uuid.New(),
+		EventDayID:    day.ID,
+		ParticipantID: participant.ID,
+		TableID:       table.ID,
+		SeatNumber:    seatNumber,
+	}
+	if err := s.repo.SeatingRepo.AssignSeat(assignment); err != nil {
+		return nil, err
+	}
+	assignment.Table = *table
+	return assignment, nil
+}
+
+func (s *SeatingService) Unassign(eventID, dayID, participantID string) error {
+	if _, err := s.day(eventID, dayID); err != nil {
+		return err
+	}
+	return s.repo.SeatingRepo.DeleteAssignment(dayID, participantID)
+}
+
+// AutoAssign seats every approved participant who has no seat yet, keeping
+// divisions together. A division goes to the tables reserved for it first,
+// then to open tables it already occupies, then to the open table with the
+// most free seats. Tables reserved for another division are never used.
+func (s *SeatingService) AutoAssign(eventID, dayID string) (*AutoAssignResult, error) {
+	day, err := s.day(eventID, dayID)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := s.repo.SeatingRepo.ListTablesByDay(dayID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.repo.SeatingRepo.ListAssignmentsByDay(dayID)
+	if err != nil {
+		return nil, err
+	}
+	participants, _, err := s.repo.ParticipantRepo.ListParticipantsByApprovalStatus(eventID, ApprovalApproved, 0, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make(map[uuid.UUID]map[int]bool, len(tables))
+	hosts := make(map[uuid.UUID]map[string]bool, len(tables))
+	for _, table := range tables {
+		taken[table.ID] = make(map[int]bool)
+		hosts[table.ID] = make(map[string]bool)
+	}
+	seated := make(map[uuid.UUID]bool, len(assignments))
+	for _, assignment := range assignments {
+		seated[assignment.ParticipantID] = true
+		taken[assignment.TableID][assignment.SeatNumber] = true
+		hosts[assignment.TableID][assignment.Participant.Division] = true
+	}
+
+	// Participants come oldest first; group them by division, keeping that
+	// order within each division
+	divisions := make(map[string][]models.Participant)
+	for _, participant := range participants {
+		if !seated[participant.ID] {
+			divisions[participant.Division] = append(divisions[participant.Division], participant)
+		}
+	}
+	names := make([]string, 0, len(divisions))
+	for name := range divisions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	free := func(table models.SeatingTable) int {
+		return table.Capacity - len(taken[table.ID])
+	}
+	pick := func(division string) *models.SeatingTable {
+		var best *models.SeatingTable
+		rank := func(table models.SeatingTable) int {
+			switch {
+			case table.Division != "" && table.Division == division:
+				return 2
+			case table.Division == "" && hosts[table.ID][division]:
+				return 1
+			default:
+				return 0
+			}
+		}
+		for i := range tables {
+			table := tables[i]
+			if free(table) == 0 || (table.Division != "" && table.Division != division) {
+				continue
+			}
+			if best == nil || rank(table) > rank(*best) ||
+				(rank(table) == rank(*best) && free(table) > free(*best)) {
+				best = &tables[i]
+			}
+		}
+		return best
+	}
+
+	result := &AutoAssignResult{}
+	var created []models.SeatAssignment
+	for _, division := range names {
+		for _, participant := range divisions[division] {
+			table := pick(division)
+			if table == nil {
+				result.Unseated = append(result.Unseated, participant.ID.String())
+				continue
+			}
+			seat := 1
+			for taken[table.ID][seat] {
+				seat++
+			}
+			taken[table.ID][seat] = true
+			hosts[table.ID][division] = true
+			created = append(created, models.SeatAssignment{
+				ID:            uuid.New(),
+				EventDayID:    day.ID,
+				ParticipantID: participant.ID,
+				TableID:       table.ID,
+				SeatNumber:    seat,
+			})
+		}
+	}
+
+	if err := s.repo.SeatingRepo.CreateAssignments(created); err != nil {
+		return nil, err
+	}
+	result.Assigned = len(created)
+	return result, nil
+}
+
+// BadgeSeats returns the seat of every seated participant of an event, one
+// row per seated day, for printing on badges
+func (s *SeatingService) BadgeSeats(eventID string) ([]BadgeSeat, error) {
+	event, err := s.repo.EventRepo.GetEventWithDays(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+	labels := make(map[uuid.UUID]string, len(event.EventDays))
+	for _, day := range event.EventDays {
+		labels[day.ID] = day.Label
+	}
+
+	assignments, err := s.repo.SeatingRepo.ListAssignmentsByEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]BadgeSeat, 0, len(assignments))
+	for _, assignment := range assignments {
+		rows = append(rows, BadgeSeat{
+			ParticipantID:   assignment.ParticipantID.String(),
+			ParticipantName: assignment.Participant.Name,
+			Division:        assignment.Participant.Division,
+			DayLabel:        labels[assignment.EventDayID],
+			TableName:       assignment.Table.Name,
+			SeatNumber:      assignment.SeatNumber,
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].ParticipantName < rows[j].ParticipantName
+	})
+	return rows, nil
+}
+
+// day returns an event day, making sure it belongs to the event
+func (s *SeatingService) day(eventID, dayID string) (*models.EventDay, error) {
+	day, err := s.repo.EventRepo.GetEventDayByID(dayID)
+	if err != nil || day.EventID.String() != eventID {
+		return nil, ErrSeatingDayNotFound
+	}
+	return day, nil
+}
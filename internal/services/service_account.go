@@ -0,0 +1,170 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RoleScanner is the role of service accounts for shared scanner devices.
+// They cannot log in; they use service tokens, which are only accepted by
+// the verification endpoints.
+const RoleScanner = "scanner"
+
+// ScopeVerify is the scope claim of service tokens
+const ScopeVerify = "verify"
+
+var (
+	ErrServiceAccountNotFound = errors.New("service account not found")
+	ErrServiceTokenNotFound   = errors.New("service token not found")
+	ErrServiceAccountLogin    = errors.New("service accounts cannot log in")
+)
+
+// IssuedServiceToken is returned once, when the token is created
+type IssuedServiceToken struct {
+	Token        string               `json:"token"`
+	ServiceToken *models.ServiceToken `json:"service_token"`
+}
+
+// CreateServiceAccount creates a scanner account. Like staff, it can only
+// verify for the events it is assigned to.
+func (s *AuthService) CreateServiceAccount(actorID, name string) (*models.User, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	// The password is never handed out; service accounts use tokens
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	hashedPassword, err := NewPasswordHasher(s.cfg).Hash(base64.RawURLEncoding.EncodeToString(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	id := uuid.New()
+	user := &models.User{
+		ID:       id,
+		Email:    fmt.Sprintf("scanner-%s@service-accounts.invalid", id),
+		Name:     name,
+		Password: hashedPassword,
+		Role:     RoleScanner,
+		IsActive: true,
+	}
+	if err := s.repo.UserRepo.CreateUser(user); err != nil {
+		return nil, err
+	}
+	s.audit.Record(actorID, AuditServiceAccountCreated, AuditEntityUser, id.String(), map[string]interface{}{
+		"name": name,
+	})
+
+	user.Password = ""
+	return user, nil
+}
+
+func (s *AuthService) getServiceAccount(id string) (*models.User, error) {
+	user, err := s.repo.UserRepo.GetUserByID(id)
+	if err != nil || user.Role != RoleScanner {
+		return nil, ErrServiceAccountNotFound
+	}
+	return user, nil
+}
+
+// IssueServiceToken creates a token for a service account. It does not
+// expire; revoke it when the device is lost or retired.
+func (s *AuthService) IssueServiceToken(actorID, accountID, name string) (*IssuedServiceToken, error) {
+	account, err := s.getServiceAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.IsActive {
+		return nil, ErrUserDeactivated
+	}
+	creator, err := uuid.Parse(actorID)
+	if err != nil {
+		return nil, errors.New("invalid creator")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	token := &models.ServiceToken{
+		ID:        uuid.New(),
+		UserID:    account.ID,
+		Name:      name,
+		CreatedBy: creator,
+		CreatedAt: time.Now(),
+	}
+	signed, err := s.keys.Sign(jwt.MapClaims{
+		"user_id": account.ID.String(),
+		"role":    RoleScanner,
+		"scope":   ScopeVerify,
+		"jti":     token.ID.String(),
+		"iat":     token.CreatedAt.Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign token: %w", err)
+	}
+	if err := s.repo.TokenRepo.CreateServiceToken(token); err != nil {
+		return nil, err
+	}
+	s.audit.Record(actorID, AuditServiceTokenIssued, AuditEntityUser, account.ID.String(), map[string]interface{}{
+		"token_id": token.ID.String(),
+		"name":     name,
+	})
+
+	return &IssuedServiceToken{Token: signed, ServiceToken: token}, nil
+}
+
+// ListServiceTokens returns the tokens of a service account, newest first
+func (s *AuthService) ListServiceTokens(accountID string) ([]models.ServiceToken, error) {
+	if _, err := s.getServiceAccount(accountID); err != nil {
+		return nil, err
+	}
+	return s.repo.TokenRepo.ListServiceTokens(accountID)
+}
+
+// RevokeServiceToken rejects a service token from now on
+func (s *AuthService) RevokeServiceToken(actorID, accountID, tokenID string) error {
+	if err := s.repo.TokenRepo.RevokeServiceToken(accountID, tokenID, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrServiceTokenNotFound
+		}
+		return err
+	}
+	s.audit.Record(actorID, AuditServiceTokenRevoked, AuditEntityUser, accountID, map[string]interface{}{
+		"token_id": tokenID,
+	})
+	return nil
+}
+
+// checkServiceToken rejects service account tokens that were not issued as
+// service tokens or have been revoked
+func (s *AuthService) checkServiceToken(user *models.User, jti string) error {
+	if jti == "" {
+		return ErrTokenRevoked
+	}
+	token, err := s.repo.TokenRepo.GetServiceToken(jti)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTokenRevoked
+		}
+		return err
+	}
+	if token.UserID != user.ID || token.RevokedAt != nil {
+		return ErrTokenRevoked
+	}
+	return nil
+}
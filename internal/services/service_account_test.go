@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-management-backend/internal/repositories/memory"
+)
+
+func TestServiceAccount(t *testing.T) {
+	e := newTestEnv(t)
+	svc := e.authService()
+	admin := e.fx.User("admin")
+
+	account, err := svc.CreateServiceAccount(admin.ID.String(), "Gate scanners")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Role != RoleScanner || account.Password != "" {
+		t.Fatalf("role %q, password %q; want %q and no password", account.Role, account.Password, RoleScanner)
+	}
+	scanner := e.fx.User(RoleScanner)
+	if _, err := svc.Authenticate(scanner.Email, memory.FixturePassword, "", false, LoginClient{}); !errors.Is(err, ErrServiceAccountLogin) {
+		t.Fatalf("login: error = %v, want %v", err, ErrServiceAccountLogin)
+	}
+
+	if _, err := svc.IssueServiceToken(admin.ID.String(), admin.ID.String(), "Gate 1"); !errors.Is(err, ErrServiceAccountNotFound) {
+		t.Fatalf("token for a regular user: error = %v, want %v", err, ErrServiceAccountNotFound)
+	}
+	issued, err := svc.IssueServiceToken(admin.ID.String(), account.ID.String(), "Gate 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenID := issued.ServiceToken.ID.String()
+	issuedAt := issued.ServiceToken.CreatedAt.Truncate(time.Second)
+
+	if err := svc.ValidateToken(account.ID.String(), tokenID, issuedAt); err != nil {
+		t.Fatalf("active token: %v", err)
+	}
+	if err := svc.ValidateToken(account.ID.String(), "", issuedAt); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("token without a jti: error = %v, want %v", err, ErrTokenRevoked)
+	}
+
+	if err := svc.RevokeServiceToken(admin.ID.String(), account.ID.String(), tokenID); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ValidateToken(account.ID.String(), tokenID, issuedAt); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("revoked token: error = %v, want %v", err, ErrTokenRevoked)
+	}
+	if err := svc.RevokeServiceToken(admin.ID.String(), account.ID.String(), admin.ID.String()); !errors.Is(err, ErrServiceTokenNotFound) {
+		t.Fatalf("unknown token: error = %v, want %v", err, ErrServiceTokenNotFound)
+	}
+
+	events := NewEventService(e.repo, e.cfg)
+	event := e.fx.Event()
+	other := e.fx.Event()
+	if _, err := events.AssignStaff(event.ID.String(), account.ID.String(), admin.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := events.CheckEventAccess(account.ID.String(), RoleScanner, other.ID.String()); !errors.Is(err, ErrEventAccessDenied) {
+		t.Fatalf("unassigned event: error = %v, want %v", err, ErrEventAccessDenied)
+	}
+	if err := events.CheckEventAccess(account.ID.String(), RoleScanner, event.ID.String()); err != nil {
+		t.Fatalf("assigned event: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+)
+
+// SessionService manages agenda sessions. Each session is scanned through an
+// EventAction created alongside it, so check-in reuses the verification flow.
+type SessionService struct {
+	repo *repositories.Repository
+	cfg  *config.Config
+}
+
+func NewSessionService(repo *repositories.Repository, cfg *config.Config) *SessionService {
+	return &SessionService{repo: repo, cfg: cfg}
+}
+
+type CreateSessionRequest struct {
+	EventDayID  string
+	Title       string
+	Room        string
+	Capacity    *int
+	StartsAt    time.Time
+	EndsAt      time.Time
+	CreditHours float64
+	ActionCode  string // generated when empty
+}
+
+// SessionAttendee is one line of a session attendance report
+type SessionAttendee struct {
+	ParticipantID string    `json:"participant_id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	Division      string    `json:"division"`
+	CheckedInAt   time.Time `json:"checked_in_at"`
+	CreditHours   float64   `json:"credit_hours"`
+}
+
+type SessionAttendance struct {
+	Session   *models.Session   `json:"session"`
+	Attendees []SessionAttendee `json:"attendees"`
+	Count     int               `json:"count"`
+}
+
+func (s *SessionService) CreateSession(eventID string, req CreateSessionRequest) (*models.Session, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	day, err := s.repo.EventRepo.GetEventDayByID(req.EventDayID)
+	if err != nil || day.EventID != event.ID {
+		return nil, errors.New("event day not found for this event")
+	}
+
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, errors.New("ends_at must be after starts_at")
+	}
+	if req.Capacity != nil && *req.Capacity <= 0 {
+		return nil, errors.New("capacity must be greater than 0")
+	}
+
+	code := strings.TrimSpace(req.ActionCode)
+	if code == "" {
+		if code, err = sessionActionCode(); err != nil {
+			return nil, err
+		}
+	}
+
+	action := &models.EventAction{
+		EventID:    event.ID,
+		EventDayID: day.ID,
+		Name:       strings.TrimSpace(req.Title),
+		Code:       code,
+		IsActive:   true,
+	}
+	session := &models.Session{
+		EventID:     event.ID,
+		EventDayID:  day.ID,
+		Title:       strings.TrimSpace(req.Title),
+		Room:        strings.TrimSpace(req.Room),
+		Capacity:    req.Capacity,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		CreditHours: req.CreditHours,
+	}
+
+	if err := s.repo.SessionRepo.CreateSession(session, action); err != nil {
+		return nil, err
+	}
+	session.Action = *action
+
+	return session, nil
+}
+
+func (s *SessionService) ListSessions(eventID string) ([]models.Session, error) {
+	return s.repo.SessionRepo.ListSessionsByEvent(eventID)
+}
+
+// GetAttendance returns who attended a session, with the credit each earned
+func (s *SessionService) GetAttendance(eventID, sessionID string) (*SessionAttendance, error) {
+	session, err := s.repo.SessionRepo.GetSessionByID(sessionID)
+	if err != nil || session.EventID.String() != eventID {
+		return nil, errors.New("session not found")
+	}
+
+	logs, err := s.repo.SessionRepo.GetSessionAttendance(session)
+	if err != nil {
+		return nil, err
+	}
+
+	attendees := make([]SessionAttendee, 0, len(logs))
+	for _, log := range logs {
+		attendees = append(attendees, SessionAttendee{
+			ParticipantID: log.ParticipantID.String(),
+			Name:          log.Participant.Name,
+			Email:         log.Participant.Email,
+			Division:      log.Participant.Division,
+			CheckedInAt:   log.VerifiedAt,
+			CreditHours:   session.CreditHours,
+		})
+	}
+
+	return &SessionAttendance{
+		Session:   session,
+		Attendees: attendees,
+		Count:     len(attendees),
+	}, nil
+}
+
+func sessionActionCode() (string, error) {
+	var buf [4]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", fmt.Errorf("failed to generate session code: %w", err)
+	}
+	return "SES" + strings.ToUpper(hex.EncodeToString(buf[:])), nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ErrShiftNotFound is returned when the shift does not exist or belongs to
+// another event
+var ErrShiftNotFound = errors.New("shift not found")
+
+// ShiftService manages the staff rota. Once an event has shifts, staff may
+// only scan at the gates and times of the shifts they are assigned to.
+type ShiftService struct {
+	repo *repositories.Repository
+	cfg  *config.Config
+}
+
+func NewShiftService(repo *repositories.Repository, cfg *config.Config) *ShiftService {
+	return &ShiftService{repo: repo, cfg: cfg}
+}
+
+type CreateShiftRequest struct {
+	EventDayID string
+	// Gate worked during the shift; every action of the day when empty
+	ActionID string
+	Name     string
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+func (s *ShiftService) CreateShift(eventID string, req CreateShiftRequest) (*models.Shift, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	day, err := s.repo.EventRepo.GetEventDayByID(req.EventDayID)
+	if err != nil || day.EventID != event.ID {
+		return nil, errors.New("event day not found for this event")
+	}
+
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, errors.New("ends_at must be after starts_at")
+	}
+
+	shift := &models.Shift{
+		ID:         uuid.New(),
+		EventID:    event.ID,
+		EventDayID: day.ID,
+		Name:       strings.TrimSpace(req.Name),
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+	}
+	if req.ActionID != "" {
+		action, err := s.repo.EventRepo.GetEventActionByID(req.ActionID)
+		if err != nil || action.EventDayID != day.ID {
+			return nil, errors.New("action not found for this event day")
+		}
+		shift.ActionID = &action.ID
+		shift.Action = action
+	}
+
+	if err := s.repo.ShiftRepo.CreateShift(shift); err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+func (s *ShiftService) ListShifts(eventID string) ([]models.Shift, error) {
+	return s.repo.ShiftRepo.ListShiftsByEvent(eventID)
+}
+
+func (s *ShiftService) DeleteShift(eventID, shiftID string) error {
+	if _, err := s.shift(eventID, shiftID); err != nil {
+		return err
+	}
+	return s.repo.ShiftRepo.DeleteShift(shiftID)
+}
+
+// AssignStaff puts a staff user on a shift
+func (s *ShiftService) AssignStaff(eventID, shiftID, userID string) (*models.Shift, error) {
+	if _, err := s.shift(eventID, shiftID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.UserRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.Role != "staff" {
+		return nil, errors.New("only staff users can be assigned to shifts")
+	}
+
+	if err := s.repo.ShiftRepo.AssignStaff(shiftID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ShiftRepo.GetShiftByID(shiftID)
+}
+
+func (s *ShiftService) UnassignStaff(eventID, shiftID, userID string) error {
+	if _, err := s.shift(eventID, shiftID); err != nil {
+		return err
+	}
+	return s.repo.ShiftRepo.UnassignStaff(shiftID, userID)
+}
+
+// MyShifts returns the current and upcoming shifts of a user, including
+// shifts that ended within the grace period
+func (s *ShiftService) MyShifts(userID string) ([]models.Shift, error) {
+	return s.repo.ShiftRepo.ListShiftsByUser(userID, time.Now().Add(-s.cfg.ShiftGracePeriod))
+}
+
+// shift returns a shift, making sure it belongs to the event
+func (s *ShiftService) shift(eventID, shiftID string) (*models.Shift, error) {
+	shift, err := s.repo.ShiftRepo.GetShiftByID(shiftID)
+	if err != nil || shift.EventID.String() != eventID {
+		return nil, ErrShiftNotFound
+	}
+	return shift, nil
+}
+
+// checkOnShift refuses scans by staff outside their shifts. Only events with
+// shifts are restricted, and only the staff role: organizers and admins may
+// always scan.
+func checkOnShift(shifts repositories.ShiftRepository, grace time.Duration, verifier *models.User, action *models.EventAction) error {
+	if verifier.Role != "staff" {
+		return nil
+	}
+
+	restricted, err := shifts.HasShifts(action.EventID.String())
+	if err != nil {
+		return NewVerificationError("failed to check shifts", ErrDatabaseError, err)
+	}
+	if !restricted {
+		return nil
+	}
+
+	onShift, err := shifts.IsOnShift(verifier.ID.String(), action, time.Now(), grace)
+	if err != nil {
+		return NewVerificationError("failed to check shifts", ErrDatabaseError, err)
+	}
+	if !onShift {
+		return NewVerificationError(
+			fmt.Sprintf("verifier is not on shift at: %s", action.Name),
+			ErrNotOnShift,
+			nil,
+		)
+	}
+	return nil
+}
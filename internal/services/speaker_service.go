@@ -0,0 +1,224 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// speakerQRPrefix marks the content of speaker QR codes, telling them apart
+// from participant tickets at the scanner
+const speakerQRPrefix = "SPK:"
+
+// DefaultSpeakerZones are granted to speakers created without explicit zones
+var DefaultSpeakerZones = []string{"backstage"}
+
+// SpeakerService manages the speaker registry of events and checks speakers
+// in with their QR credentials
+type SpeakerService struct {
+	repo *repositories.Repository
+	cfg  *config.Config
+}
+
+func NewSpeakerService(repo *repositories.Repository, cfg *config.Config) *SpeakerService {
+	return &SpeakerService{repo: repo, cfg: cfg}
+}
+
+type CreateSpeakerRequest struct {
+	Name       string
+	Email      string
+	Bio        string
+	PhotoPath  string
+	SessionIDs []string
+	Zones      []string // DefaultSpeakerZones when empty
+}
+
+type SpeakerCheckInResult struct {
+	Success     bool                   `json:"success"`
+	Message     string                 `json:"message"`
+	CheckIn     *models.SpeakerCheckIn `json:"check_in"`
+	Speaker     *models.Speaker        `json:"speaker"`
+	EventAction *models.EventAction    `json:"event_action"`
+	Timestamp   time.Time              `json:"timestamp"`
+}
+
+// SpeakerProfile is a speaker with their check-in history
+type SpeakerProfile struct {
+	*models.Speaker
+	CheckIns []models.SpeakerCheckIn `json:"check_ins"`
+}
+
+// IsSpeakerCredential reports whether scanned QR content is a speaker
+// credential rather than a participant ticket
+func IsSpeakerCredential(qrData string) bool {
+	return strings.HasPrefix(qrData, speakerQRPrefix)
+}
+
+func (s *SpeakerService) CreateSpeaker(eventID string, req CreateSpeakerRequest) (*models.Speaker, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	sessions := make([]models.Session, 0, len(req.SessionIDs))
+	sessionIDs := make([]uuid.UUID, 0, len(req.SessionIDs))
+	for _, id := range req.SessionIDs {
+		session, err := s.repo.SessionRepo.GetSessionByID(id)
+		if err != nil || session.EventID != event.ID {
+			return nil, fmt.Errorf("session %s not found for this event", id)
+		}
+		sessions = append(sessions, *session)
+		sessionIDs = append(sessionIDs, session.ID)
+	}
+
+	zones := DefaultSpeakerZones
+	if len(req.Zones) > 0 {
+		zones = make([]string, 0, len(req.Zones))
+		for _, zone := range req.Zones {
+			if zone = strings.ToLower(strings.TrimSpace(zone)); zone != "" {
+				zones = append(zones, zone)
+			}
+		}
+	}
+
+	speaker := &models.Speaker{
+		ID:        uuid.New(),
+		EventID:   event.ID,
+		Name:      strings.TrimSpace(req.Name),
+		Email:     strings.TrimSpace(req.Email),
+		Bio:       strings.TrimSpace(req.Bio),
+		PhotoPath: req.PhotoPath,
+		Zones:     zones,
+	}
+
+	filename, err := utils.GenerateQRCodeImage(speakerQRPrefix+speaker.ID.String(), s.cfg.QRDir)
+	if err != nil {
+		return nil, err
+	}
+	speaker.QRPath = fmt.Sprintf("/qrcodes/%s", filename)
+
+	if err := s.repo.SpeakerRepo.CreateSpeaker(speaker, sessionIDs); err != nil {
+		return nil, err
+	}
+	speaker.Sessions = sessions
+
+	return speaker, nil
+}
+
+// GeneratePhotoVariants crops the speaker photo into utils.PhotoVariants and
+// stores their URLs on the speaker
+func (s *SpeakerService) GeneratePhotoVariants(speakerID string) error {
+	speaker, err := s.repo.SpeakerRepo.GetSpeakerByID(speakerID)
+	if err != nil {
+		return err
+	}
+	if speaker.PhotoPath == "" {
+		return nil
+	}
+
+	srcPath := filepath.Join(s.cfg.PhotoDir, filepath.Base(speaker.PhotoPath))
+	files, err := utils.GenerateImageVariants(srcPath, utils.PhotoVariants)
+	if err != nil {
+		return err
+	}
+
+	variants := make(map[string]string, len(files))
+	for name, filename := range files {
+		variants[name] = "/photos/" + filename
+	}
+
+	return s.repo.SpeakerRepo.UpdateSpeakerPhotoVariants(speakerID, variants)
+}
+
+func (s *SpeakerService) ListSpeakers(eventID string) ([]models.Speaker, error) {
+	return s.repo.SpeakerRepo.ListSpeakersByEvent(eventID)
+}
+
+func (s *SpeakerService) GetSpeaker(eventID, speakerID string) (*SpeakerProfile, error) {
+	speaker, err := s.repo.SpeakerRepo.GetSpeakerByID(speakerID)
+	if err != nil || speaker.EventID.String() != eventID {
+		return nil, errors.New("speaker not found")
+	}
+
+	checkIns, err := s.repo.SpeakerRepo.ListSpeakerCheckIns(speakerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SpeakerProfile{Speaker: speaker, CheckIns: checkIns}, nil
+}
+
+// CheckIn records a scan of a speaker credential at an action. Unlike
+// participant tickets, speakers are admitted at actions in their zones and
+// may be scanned at the same action more than once.
+func (s *SpeakerService) CheckIn(req VerifyRequest) (*SpeakerCheckInResult, error) {
+	speakerID, err := uuid.Parse(strings.TrimPrefix(req.QRCodeData, speakerQRPrefix))
+	if err != nil {
+		return nil, NewVerificationError("invalid speaker QR code", ErrInvalidQRCode, err)
+	}
+
+	speaker, err := s.repo.SpeakerRepo.GetSpeakerByID(speakerID.String())
+	if err != nil {
+		return nil, NewVerificationError("speaker not found", ErrSpeakerNotFound, err)
+	}
+
+	action, err := s.repo.EventRepo.GetEventActionByCode(req.ActionCode)
+	if err != nil {
+		return nil, NewVerificationError("action not found", ErrActionNotFound, err)
+	}
+	if !action.IsActive {
+		return nil, NewVerificationError("action is not active", ErrActionInactive, nil)
+	}
+	if err := checkActionWindow(action, time.Now()); err != nil {
+		return nil, err
+	}
+	if action.EventID != speaker.EventID {
+		return nil, NewVerificationError("action does not belong to speaker's event", ErrEventMismatch, nil)
+	}
+	if !eventAllowed(req.EventIDs, action.EventID.String()) {
+		return nil, NewVerificationError("not allowed to verify for this event", ErrPermissionDenied, nil)
+	}
+	if !ActionAdmits(action, speaker.Zones) {
+		return nil, NewVerificationError(
+			fmt.Sprintf("speaker has no access to zone: %s", action.Zone),
+			ErrZoneRestricted,
+			nil,
+		)
+	}
+
+	verifier, err := s.repo.UserRepo.GetUserByID(req.VerifierID)
+	if err != nil {
+		return nil, NewVerificationError("verifier not found", ErrVerifierNotFound, err)
+	}
+	if err := checkOnShift(s.repo.ShiftRepo, s.cfg.ShiftGracePeriod, verifier, action); err != nil {
+		return nil, err
+	}
+
+	checkIn := &models.SpeakerCheckIn{
+		SpeakerID:  speaker.ID,
+		ActionID:   action.ID,
+		VerifiedBy: verifier.ID,
+		VerifiedAt: time.Now(),
+	}
+	if err := s.repo.SpeakerRepo.CreateSpeakerCheckIn(checkIn); err != nil {
+		return nil, NewVerificationError("failed to create check-in record", ErrDatabaseError, err)
+	}
+
+	return &SpeakerCheckInResult{
+		Success:     true,
+		Message:     fmt.Sprintf("Successfully checked in speaker %s at %s", speaker.Name, action.Name),
+		CheckIn:     checkIn,
+		Speaker:     speaker,
+		EventAction: action,
+		Timestamp:   checkIn.VerifiedAt,
+	}, nil
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// RoleSponsor is the role of sponsor booth staff. They can capture and export
+// leads for their own sponsor and nothing else.
+const RoleSponsor = "sponsor"
+
+// ErrNotSponsorStaff is returned when a user without a sponsor captures leads
+var ErrNotSponsorStaff = errors.New("user is not assigned to a sponsor")
+
+// SponsorService manages event sponsors, their booth staff accounts and the
+// leads they capture
+type SponsorService struct {
+	repo *repositories.Repository
+	cfg  *config.Config
+}
+
+func NewSponsorService(repo *repositories.Repository, cfg *config.Config) *SponsorService {
+	return &SponsorService{repo: repo, cfg: cfg}
+}
+
+type CaptureLeadRequest struct {
+	QRCodeData string
+	Consent    bool
+	Notes      string
+}
+
+// LeadView is a lead as shown to sponsors. Contact details are only filled
+// in when the participant consented.
+type LeadView struct {
+	ID            string    `json:"id"`
+	ParticipantID string    `json:"participant_id"`
+	Name          string    `json:"name"`
+	Division      string    `json:"division"`
+	Email         string    `json:"email,omitempty"`
+	Phone         string    `json:"phone,omitempty"`
+	Consent       bool      `json:"consent"`
+	Notes         string    `json:"notes"`
+	CapturedAt    time.Time `json:"captured_at"`
+}
+
+func newLeadView(lead *models.Lead) LeadView {
+	view := LeadView{
+		ID:            lead.ID.String(),
+		ParticipantID: lead.ParticipantID.String(),
+		Name:          lead.Participant.Name,
+		Division:      lead.Participant.Division,
+		Consent:       lead.Consent,
+		Notes:         lead.Notes,
+		CapturedAt:    lead.CapturedAt,
+	}
+	if lead.Consent {
+		view.Email = lead.Participant.Email
+		view.Phone = lead.Participant.Phone
+	}
+	return view
+}
+
+func (s *SponsorService) CreateSponsor(eventID, name string) (*models.Sponsor, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	sponsor := &models.Sponsor{
+		EventID: event.ID,
+		Name:    strings.TrimSpace(name),
+	}
+	if err := s.repo.SponsorRepo.CreateSponsor(sponsor); err != nil {
+		return nil, err
+	}
+	return sponsor, nil
+}
+
+func (s *SponsorService) ListSponsors(eventID string) ([]models.Sponsor, error) {
+	return s.repo.SponsorRepo.ListSponsorsByEvent(eventID)
+}
+
+// GetSponsor returns a sponsor of the given event
+func (s *SponsorService) GetSponsor(eventID, sponsorID string) (*models.Sponsor, error) {
+	sponsor, err := s.repo.SponsorRepo.GetSponsorByID(sponsorID)
+	if err != nil || sponsor.EventID.String() != eventID {
+		return nil, errors.New("sponsor not found")
+	}
+	return sponsor, nil
+}
+
+// CreateStaff creates a booth staff account scoped to a sponsor
+func (s *SponsorService) CreateStaff(eventID, sponsorID, email, password string) (*models.User, error) {
+	sponsor, err := s.GetSponsor(eventID, sponsorID)
+	if err != nil {
+		return nil, err
+	}
+
+	email = strings.TrimSpace(strings.ToLower(email))
+	if existing, _ := s.repo.UserRepo.GetUserByEmail(email); existing != nil {
+		return nil, errors.New("email already registered")
+	}
+
+	hashedPassword, err := hashPassword(s.cfg, password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		ID:        uuid.New(),
+		Email:     email,
+		Password:  hashedPassword,
+		Role:      RoleSponsor,
+		SponsorID: &sponsor.ID,
+	}
+	if err := s.repo.UserRepo.CreateUser(user); err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
+// SponsorOf returns the sponsor a booth staff user captures leads for
+func (s *SponsorService) SponsorOf(userID string) (*models.Sponsor, error) {
+	user, err := s.repo.UserRepo.GetUserByID(userID)
+	if err != nil || user.SponsorID == nil {
+		return nil, ErrNotSponsorStaff
+	}
+	return s.repo.SponsorRepo.GetSponsorByID(user.SponsorID.String())
+}
+
+// CaptureLead records a participant scanned at the booth of the user's
+// sponsor. Scanning the same participant again updates consent and notes.
+func (s *SponsorService) CaptureLead(userID string, req CaptureLeadRequest) (*LeadView, error) {
+	sponsor, err := s.SponsorOf(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := parseParticipantCredential(req.QRCodeData)
+	if err != nil {
+		return nil, errors.New("invalid QR code format")
+	}
+
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(credential.ParticipantID)
+	if err != nil || participant.EventID != sponsor.EventID {
+		return nil, errors.New("participant not found for this event")
+	}
+	if !credential.currentFor(s.cfg.CredentialSigningKey, participant) {
+		return nil, errors.New("credential has been revoked")
+	}
+
+	lead := &models.Lead{
+		SponsorID:     sponsor.ID,
+		ParticipantID: participant.ID,
+		EventID:       sponsor.EventID,
+		CapturedBy:    uuid.MustParse(userID),
+		Consent:       req.Consent,
+		Notes:         strings.TrimSpace(req.Notes),
+		CapturedAt:    time.Now(),
+	}
+	if err := s.repo.SponsorRepo.SaveLead(lead); err != nil {
+		return nil, err
+	}
+	lead.Participant = *participant
+
+	view := newLeadView(lead)
+	return &view, nil
+}
+
+func (s *SponsorService) ListLeads(sponsorID string) ([]LeadView, error) {
+	leads, err := s.repo.SponsorRepo.ListLeadsBySponsor(sponsorID)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]LeadView, 0, len(leads))
+	for i := range leads {
+		views = append(views, newLeadView(&leads[i]))
+	}
+	return views, nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/mail"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/utils"
+	"event-management-backend/internal/whatsapp"
+)
+
+// Channels tickets are sent through, see TICKET_CHANNEL
+const (
+	TicketChannelEmail    = "email"
+	TicketChannelWhatsApp = "whatsapp"
+)
+
+var (
+	// ErrTicketNotIssued is returned when the participant has no QR code
+	// yet, e.g. while the registration awaits approval
+	ErrTicketNotIssued = errors.New("participant has no ticket yet")
+	// ErrTicketCancelled is returned for cancelled registrations, whose QR
+	// code is refused at the gates
+	ErrTicketCancelled = errors.New("registration is cancelled")
+)
+
+// TicketDelivery is where a ticket was sent
+type TicketDelivery struct {
+	Channel string `json:"channel"`
+	To      string `json:"to"`
+}
+
+// ResendTicket sends a participant their ticket again with the QR code
+// attached: on WhatsApp when TICKET_CHANNEL is whatsapp and they gave a phone
+// number, by email otherwise. A QR image that went missing is rendered again
+// from the current credential, so the ticket stays valid.
+func (s *NotificationService) ResendTicket(ctx context.Context, actorID, participantID string) (*TicketDelivery, error) {
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return nil, ErrUnknownParticipant
+	}
+	if participant.CancelledAt != nil {
+		return nil, ErrTicketCancelled
+	}
+	if participant.QRPath == "" {
+		return nil, ErrTicketNotIssued
+	}
+	event, err := s.repo.EventRepo.GetEventByID(participant.EventID.String())
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+
+	image, err := ticketQRImage(s.cfg, participant)
+	if err != nil {
+		return nil, err
+	}
+	filename := "ticket-" + event.Slug + ".png"
+
+	delivery := &TicketDelivery{Channel: TicketChannelEmail, To: participant.Email}
+	phone := whatsapp.PhoneNumber(participant.Phone, s.cfg.WhatsAppDefaultCountryCode)
+	if s.cfg.TicketChannel == TicketChannelWhatsApp && phone != "" {
+		delivery = &TicketDelivery{Channel: TicketChannelWhatsApp, To: phone}
+		err = s.messenger.Send(ctx, whatsapp.Message{
+			To:        phone,
+			Text:      ticketText(event, participant, TicketCode(s.cfg.CredentialSigningKey, participant.ID)),
+			Image:     image,
+			ImageName: filename,
+		})
+	} else {
+		err = s.mailer.Send(ctx, mail.Message{
+			To:          participant.Email,
+			Subject:     fmt.Sprintf("Your ticket for %s", event.Title),
+			Body:        ticketText(event, participant, TicketCode(s.cfg.CredentialSigningKey, participant.ID)),
+			Attachments: []mail.Attachment{{Filename: filename, ContentType: "image/png", Data: image}},
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to send ticket: %w", err)
+	}
+
+	s.audit.Record(actorID, AuditTicketResent, AuditEntityParticipant, participantID, map[string]interface{}{
+		"channel": delivery.Channel,
+	})
+	return delivery, nil
+}
+
+// ticketQRImage reads the QR image of a participant, rendering it again when
+// the file is gone
+func ticketQRImage(cfg *config.Config, participant *models.Participant) ([]byte, error) {
+	filename := filepath.Base(participant.QRPath)
+	image, err := os.ReadFile(filepath.Join(cfg.QRDir, filename))
+	if err == nil || !os.IsNotExist(err) {
+		return image, err
+	}
+
+	if err := os.MkdirAll(cfg.QRDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create QR directory: %w", err)
+	}
+	if err := utils.WriteQRCodeImage(credentialContent(cfg.CredentialSigningKey, participant), cfg.QRDir, filename); err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(cfg.QRDir, filename))
+}
+
+func ticketText(event *models.Event, participant *models.Participant, code string) string {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\n", participant.Name)
+	fmt.Fprintf(&body, "Here is your ticket for %s on %s.\n", event.Title, event.StartsAt.Format("Monday, 2 January 2006 15:04 MST"))
+	if participant.RequiresIDCheck {
+		body.WriteString("Please bring an ID, staff will check it at the entrance.\n")
+	}
+	body.WriteString("\nShow the attached QR code at the entrance. It is tied to your registration, please do not share it.\n")
+	fmt.Fprintf(&body, "\nYour ticket code is %s. Enter it with your email address to look your ticket up again.\n", code)
+	return body.String()
+}
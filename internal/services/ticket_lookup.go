@@ -0,0 +1,103 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"strings"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Registration states shown by a ticket lookup
+const (
+	TicketConfirmed       = "confirmed"
+	TicketAwaitingWaivers = "awaiting_waivers"
+	TicketPendingApproval = "pending_approval"
+	// Waiting for the participant to confirm their email address
+	TicketPendingConfirmation = "pending_confirmation"
+	TicketRejected            = "rejected"
+	TicketCancelled           = "cancelled"
+)
+
+var (
+	// ErrTicketNotFound is returned when no registration matches a lookup
+	ErrTicketNotFound = errors.New("no registration matches")
+	// ErrTicketCodeMismatch is returned when a lookup carries no ticket code
+	// or a wrong one
+	ErrTicketCodeMismatch = errors.New("ticket code does not match")
+)
+
+// TicketLookup is what participants see of their registration when they look
+// it up
+type TicketLookup struct {
+	// Also addresses the participant's waivers, see /waivers
+	ParticipantID uuid.UUID `json:"participant_id"`
+	Name          string    `json:"name"`
+	EventTitle    string    `json:"event_title"`
+	// One of the Ticket* states
+	Status         string          `json:"status"`
+	PaymentStatus  string          `json:"payment_status"`
+	QRPath         string          `json:"qr_path,omitempty"`
+	PendingWaivers []models.Waiver `json:"pending_waivers,omitempty"`
+}
+
+// TicketCode returns the short code printed on the tickets of a participant,
+// e.g. K7QD-2MXA, with which they can look their ticket up
+func TicketCode(secret string, participantID uuid.UUID) string {
+	mac := hmac.New(sha256.New, []byte("ticket-code:"+secret))
+	mac.Write([]byte(participantID.String()))
+	code := base32.StdEncoding.EncodeToString(mac.Sum(nil)[:5])
+	return code[:4] + "-" + code[4:]
+}
+
+// LookupTicket finds the registration of an email address for the event with
+// the slug. Only with the participant's ticket code does it reveal the
+// registration and its QR code, so knowing someone's email is not enough to
+// enter in their name. Without the code, or with a wrong one, it fails with
+// ErrTicketCodeMismatch and a lookup carrying only the participant ID, so the
+// caller can send the ticket to the participant's own contact instead.
+func (s *ParticipantService) LookupTicket(eventSlug, email, code string) (*TicketLookup, error) {
+	event, err := s.repo.EventRepo.GetEventBySlug(eventSlug)
+	if err != nil || event.Status != EventPublished {
+		return nil, ErrTicketNotFound
+	}
+	participant, err := s.repo.ParticipantRepo.GetParticipantByEmailAndEvent(email, event.ID.String())
+	if err != nil || participant.AnonymizedAt != nil {
+		return nil, ErrTicketNotFound
+	}
+
+	given := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	expected := strings.Replace(TicketCode(s.cfg.CredentialSigningKey, participant.ID), "-", "", 1)
+	if !hmac.Equal([]byte(given), []byte(expected)) {
+		return &TicketLookup{ParticipantID: participant.ID}, ErrTicketCodeMismatch
+	}
+
+	lookup := &TicketLookup{
+		ParticipantID: participant.ID,
+		Name:          participant.Name,
+		EventTitle:    event.Title,
+		PaymentStatus: participant.PaymentStatus,
+	}
+	switch {
+	case participant.CancelledAt != nil:
+		lookup.Status = TicketCancelled
+	case participant.ApprovalStatus == ApprovalPendingConfirmation:
+		lookup.Status = TicketPendingConfirmation
+	case participant.ApprovalStatus == ApprovalPending:
+		lookup.Status = TicketPendingApproval
+	case participant.ApprovalStatus == ApprovalRejected:
+		lookup.Status = TicketRejected
+	case participant.QRPath == "":
+		lookup.Status = TicketAwaitingWaivers
+		if lookup.PendingWaivers, err = s.repo.WaiverRepo.ListUnsignedWaivers(event.ID.String(), participant.ID.String()); err != nil {
+			return nil, err
+		}
+	default:
+		lookup.Status, lookup.QRPath = TicketConfirmed, participant.QRPath
+	}
+	return lookup, nil
+}
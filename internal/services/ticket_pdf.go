@@ -0,0 +1,236 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/pdf"
+	"event-management-backend/pkg/logger"
+)
+
+// Layout of printed tickets, in points
+const (
+	ticketMargin   = 50.0
+	ticketQRSize   = 170.0
+	ticketLogoSize = 64.0
+)
+
+var ticketAccent = color.RGBA{R: 0x1f, G: 0x3a, B: 0x5f, A: 0xff}
+
+// TicketPDF renders a participant's ticket as a printable A4 PDF: the event
+// logo and details, the participant, their QR code and ticket code, and the
+// schedule of every event day. Like ResendTicket, a QR image that went missing
+// is rendered again from the current credential.
+func (s *ParticipantService) TicketPDF(participantID string) ([]byte, error) {
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return nil, ErrUnknownParticipant
+	}
+	if participant.CancelledAt != nil {
+		return nil, ErrTicketCancelled
+	}
+	if participant.QRPath == "" {
+		return nil, ErrTicketNotIssued
+	}
+	event, err := s.repo.EventRepo.GetEventByID(participant.EventID.String())
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+	days, err := s.repo.EventRepo.GetEventDaysByEventID(event.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.repo.SessionRepo.ListSessionsByEvent(event.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	qrData, err := ticketQRImage(s.cfg, participant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read QR code: %w", err)
+	}
+	qr, err := png.Decode(bytes.NewReader(qrData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode QR code: %w", err)
+	}
+
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	page := doc.AddPage()
+	width := pdf.A4Width - 2*ticketMargin
+
+	// Header: logo and event
+	y := ticketMargin
+	textX := ticketMargin
+	if logo := s.ticketLogo(event); logo != nil {
+		w, h := fitBox(logo.Bounds().Dx(), logo.Bounds().Dy(), ticketLogoSize)
+		page.Image(doc.AddImage(logo), ticketMargin, y, w, h)
+		textX += w + 16
+	}
+	y += 22
+	for _, line := range pdf.Wrap(event.Title, pdf.HelveticaBold, 20, pdf.A4Width-ticketMargin-textX) {
+		page.Text(textX, y, pdf.HelveticaBold, 20, line)
+		y += 24
+	}
+	page.Text(textX, y, pdf.Helvetica, 11, ticketDateRange(event))
+	y += 15
+	if event.Venue != nil {
+		page.Text(textX, y, pdf.Helvetica, 11, pdf.Truncate(venueLine(event.Venue), pdf.Helvetica, 11, pdf.A4Width-ticketMargin-textX))
+		y += 15
+	}
+	if y < ticketMargin+ticketLogoSize {
+		y = ticketMargin + ticketLogoSize
+	}
+	y += 12
+	page.FillRect(ticketMargin, y, width, 3, ticketAccent)
+	y += 30
+
+	// Participant details beside the QR code
+	qrX := pdf.A4Width - ticketMargin - ticketQRSize
+	page.Image(doc.AddImage(qr), qrX, y, ticketQRSize, ticketQRSize)
+	page.StrokeRect(qrX, y, ticketQRSize, ticketQRSize, 0.5)
+	detailsWidth := qrX - ticketMargin - 20
+	top := y
+	y += 10
+	page.Text(ticketMargin, y, pdf.Helvetica, 9, "PARTICIPANT")
+	y += 22
+	for _, line := range pdf.Wrap(participant.Name, pdf.HelveticaBold, 18, detailsWidth) {
+		page.Text(ticketMargin, y, pdf.HelveticaBold, 18, line)
+		y += 22
+	}
+	details := []string{participant.Email}
+	if participant.Division != "" {
+		details = append(details, participant.Division)
+	}
+	if participant.TicketTypeID != nil {
+		if ticketType, err := s.repo.TicketTypeRepo.GetTicketType(participant.TicketTypeID.String()); err == nil {
+			details = append(details, ticketType.Name)
+		}
+	}
+	for _, line := range details {
+		page.Text(ticketMargin, y, pdf.Helvetica, 11, pdf.Truncate(line, pdf.Helvetica, 11, detailsWidth))
+		y += 16
+	}
+	y += 14
+	page.Text(ticketMargin, y, pdf.Helvetica, 9, "TICKET CODE")
+	y += 20
+	page.Text(ticketMargin, y, pdf.HelveticaBold, 16, TicketCode(s.cfg.CredentialSigningKey, participant.ID))
+	y += 18
+	if participant.RequiresIDCheck {
+		y += 10
+		page.Text(ticketMargin, y, pdf.HelveticaBold, 10, "Please bring an ID, staff will check it at the entrance.")
+		y += 14
+	}
+	if y < top+ticketQRSize {
+		y = top + ticketQRSize
+	}
+	y += 18
+	for _, line := range pdf.Wrap("Show this QR code at the entrance. It is tied to your registration, please do not share it.", pdf.Helvetica, 10, width) {
+		page.Text(ticketMargin, y, pdf.Helvetica, 10, line)
+		y += 14
+	}
+
+	// Schedule, continued on new pages as needed
+	if len(days) > 0 {
+		byDay := map[string][]models.Session{}
+		for _, session := range sessions {
+			byDay[session.EventDayID.String()] = append(byDay[session.EventDayID.String()], session)
+		}
+		newLine := func(height float64) {
+			if y+height > pdf.A4Height-ticketMargin {
+				page = doc.AddPage()
+				y = ticketMargin
+			}
+			y += height
+		}
+
+		newLine(40)
+		page.Text(ticketMargin, y, pdf.HelveticaBold, 14, "Schedule")
+		newLine(6)
+		page.Line(ticketMargin, y, ticketMargin+width, y, 0.5)
+		for _, day := range days {
+			newLine(24)
+			page.Text(ticketMargin, y, pdf.HelveticaBold, 11, fmt.Sprintf("%s, %s", day.Label, day.Date.Format("Monday 2 January 2006")))
+			daySessions := byDay[day.ID.String()]
+			sort.Slice(daySessions, func(i, j int) bool {
+				return daySessions[i].StartsAt.Before(daySessions[j].StartsAt)
+			})
+			for _, session := range daySessions {
+				title := session.Title
+				if session.Room != "" {
+					title += " (" + session.Room + ")"
+				}
+				for i, line := range pdf.Wrap(title, pdf.Helvetica, 10, width-80) {
+					newLine(14)
+					if i == 0 {
+						page.Text(ticketMargin, y, pdf.Helvetica, 10, session.StartsAt.Format("15:04")+" - "+session.EndsAt.Format("15:04"))
+					}
+					page.Text(ticketMargin+80, y, pdf.Helvetica, 10, line)
+				}
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	if _, err := doc.WriteTo(&out); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// ticketLogo decodes the event logo, preferring its medium variant. Tickets
+// are printed without a logo that is missing or cannot be decoded.
+func (s *ParticipantService) ticketLogo(event *models.Event) image.Image {
+	path := event.LogoVariants["medium"]
+	if path == "" {
+		path = event.LogoPath
+	}
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(filepath.Join(s.cfg.LogoDir, filepath.Base(path)))
+	if err != nil {
+		logger.Log.WithError(err).WithField("event_id", event.ID.String()).Warn("failed to open logo for ticket")
+		return nil
+	}
+	defer f.Close()
+	logo, _, err := image.Decode(f)
+	if err != nil {
+		logger.Log.WithError(err).WithField("event_id", event.ID.String()).Warn("failed to decode logo for ticket")
+		return nil
+	}
+	return logo
+}
+
+// fitBox scales width x height pixels to fit in a size x size box
+func fitBox(width, height int, size float64) (float64, float64) {
+	if width >= height {
+		return size, size * float64(height) / float64(width)
+	}
+	return size * float64(width) / float64(height), size
+}
+
+func ticketDateRange(event *models.Event) string {
+	start := event.StartsAt.Format("Monday 2 January 2006, 15:04")
+	switch {
+	case event.EndsAt.IsZero():
+		return start
+	case event.EndsAt.Format("2006-01-02") == event.StartsAt.Format("2006-01-02"):
+		return start + " - " + event.EndsAt.Format("15:04")
+	}
+	return start + " - " + event.EndsAt.Format("Monday 2 January 2006, 15:04")
+}
+
+func venueLine(venue *models.Venue) string {
+	if venue.Address == "" {
+		return venue.Name
+	}
+	return venue.Name + ", " + venue.Address
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownTicketType = errors.New("ticket type not found")
+	// ErrTicketTypeRequired is returned when registering for an event with
+	// ticket types without picking one
+	ErrTicketTypeRequired  = errors.New("ticket type is required")
+	ErrTicketTypeNotOnSale = errors.New("ticket type is not on sale")
+	ErrTicketTypeSoldOut   = repositories.ErrTicketTypeSoldOut
+	// ErrTicketTypeSold is returned when deleting a ticket type participants
+	// registered for
+	ErrTicketTypeSold = errors.New("ticket type has registrations")
+)
+
+type TicketTypeRequest struct {
+	Name         string
+	Price        float64
+	Quota        *int // nil = unlimited
+	SalesStartAt *time.Time
+	SalesEndAt   *time.Time
+}
+
+func (req TicketTypeRequest) validate() error {
+	if strings.TrimSpace(req.Name) == "" {
+		return errors.New("name is required")
+	}
+	if req.Price < 0 {
+		return errors.New("price must not be negative")
+	}
+	if req.Quota != nil && *req.Quota <= 0 {
+		return errors.New("quota must be positive")
+	}
+	if req.SalesStartAt != nil && req.SalesEndAt != nil && !req.SalesEndAt.After(*req.SalesStartAt) {
+		return errors.New("sales end must be after sales start")
+	}
+	return nil
+}
+
+// TicketTypeAvailability is a ticket type with what is left of it
+type TicketTypeAvailability struct {
+	models.TicketType
+	Sold      int64 `json:"sold"`
+	Remaining *int  `json:"remaining"` // nil = unlimited
+	OnSale    bool  `json:"on_sale"`
+	SoldOut   bool  `json:"sold_out"`
+}
+
+// ticketTypeOnSale reports whether the sale window of a ticket type is open
+// at now
+func ticketTypeOnSale(ticketType *models.TicketType, now time.Time) bool {
+	if ticketType.SalesStartAt != nil && now.Before(*ticketType.SalesStartAt) {
+		return false
+	}
+	return ticketType.SalesEndAt == nil || now.Before(*ticketType.SalesEndAt)
+}
+
+// ticketPrice is what a registration costs: the price of its ticket type, or
+// the event's ticket price on events without ticket types
+func ticketPrice(event *models.Event, ticketType *models.TicketType) float64 {
+	if ticketType != nil {
+		return ticketType.Price
+	}
+	return event.TicketPrice
+}
+
+// listTicketTypeAvailability returns the ticket types of an event with their
+// registrations counted
+func listTicketTypeAvailability(repo *repositories.Repository, eventID string) ([]TicketTypeAvailability, error) {
+	ticketTypes, err := repo.TicketTypeRepo.ListTicketTypesByEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+	sold, err := repo.ParticipantRepo.CountParticipantsByTicketType(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	availability := make([]TicketTypeAvailability, 0, len(ticketTypes))
+	for i := range ticketTypes {
+		item := TicketTypeAvailability{
+			TicketType: ticketTypes[i],
+			Sold:       sold[ticketTypes[i].ID.String()],
+			OnSale:     ticketTypeOnSale(&ticketTypes[i], now),
+		}
+		if quota := ticketTypes[i].Quota; quota != nil {
+			remaining := *quota - int(item.Sold)
+			if remaining < 0 {
+				remaining = 0
+			}
+			item.Remaining = &remaining
+			item.SoldOut = remaining == 0
+		}
+		availability = append(availability, item)
+	}
+	return availability, nil
+}
+
+func (s *EventService) CreateTicketType(eventID string, req TicketTypeRequest) (*models.TicketType, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+
+	ticketType := &models.TicketType{
+		ID:           uuid.New(),
+		EventID:      event.ID,
+		Name:         strings.TrimSpace(req.Name),
+		Price:        req.Price,
+		Quota:        req.Quota,
+		SalesStartAt: req.SalesStartAt,
+		SalesEndAt:   req.SalesEndAt,
+	}
+	if err := s.repo.TicketTypeRepo.CreateTicketType(ticketType); err != nil {
+		return nil, err
+	}
+	return ticketType, nil
+}
+
+// ListTicketTypes returns the ticket types of an event, cheapest first
+func (s *EventService) ListTicketTypes(eventID string) ([]TicketTypeAvailability, error) {
+	if _, err := s.repo.EventRepo.GetEventByID(eventID); err != nil {
+		return nil, ErrUnknownEvent
+	}
+	return listTicketTypeAvailability(s.repo, eventID)
+}
+
+// getTicketType returns a ticket type of the given event
+func (s *EventService) getTicketType(eventID, ticketTypeID string) (*models.TicketType, error) {
+	ticketType, err := s.repo.TicketTypeRepo.GetTicketType(ticketTypeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && ticketType.EventID.String() != eventID) {
+		return nil, ErrUnknownTicketType
+	}
+	return ticketType, err
+}
+
+// UpdateTicketType replaces the details of a ticket type. The quota cannot
+// drop below the registrations already made for it.
+func (s *EventService) UpdateTicketType(eventID, ticketTypeID string, req TicketTypeRequest) (*models.TicketType, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	ticketType, err := s.getTicketType(eventID, ticketTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Quota != nil {
+		sold, err := s.repo.ParticipantRepo.CountParticipantsByTicketType(eventID)
+		if err != nil {
+			return nil, err
+		}
+		if int64(*req.Quota) < sold[ticketTypeID] {
+			return nil, errors.New("quota cannot be less than registered participants")
+		}
+	}
+
+	ticketType.Name = strings.TrimSpace(req.Name)
+	ticketType.Price = req.Price
+	ticketType.Quota = req.Quota
+	ticketType.SalesStartAt = req.SalesStartAt
+	ticketType.SalesEndAt = req.SalesEndAt
+	if err := s.repo.TicketTypeRepo.UpdateTicketType(ticketType); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUnknownTicketType
+		}
+		return nil, err
+	}
+	return ticketType, nil
+}
+
+// DeleteTicketType removes a ticket type nobody registered for yet; close
+// its sale window instead once it has registrations
+func (s *EventService) DeleteTicketType(eventID, ticketTypeID string) error {
+	if _, err := s.getTicketType(eventID, ticketTypeID); err != nil {
+		return err
+	}
+
+	sold, err := s.repo.ParticipantRepo.CountParticipantsByTicketType(eventID)
+	if err != nil {
+		return err
+	}
+	if sold[ticketTypeID] > 0 {
+		return ErrTicketTypeSold
+	}
+
+	err = s.repo.TicketTypeRepo.DeleteTicketType(ticketTypeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUnknownTicketType
+	}
+	return err
+}
+
+// pickTicketType returns the ticket type a registration is for, checking it
+// is on sale and not sold out; the quota is checked again as the participant
+// is inserted. Events without ticket types return nil and must not be given
+// one.
+func (s *ParticipantService) pickTicketType(event *models.Event, ticketTypeID string) (*models.TicketType, error) {
+	ticketTypes, err := s.repo.TicketTypeRepo.ListTicketTypesByEvent(event.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(ticketTypes) == 0 {
+		if ticketTypeID != "" {
+			return nil, ErrUnknownTicketType
+		}
+		return nil, nil
+	}
+	if ticketTypeID == "" {
+		return nil, ErrTicketTypeRequired
+	}
+
+	var ticketType *models.TicketType
+	for i := range ticketTypes {
+		if ticketTypes[i].ID.String() == ticketTypeID {
+			ticketType = &ticketTypes[i]
+			break
+		}
+	}
+	if ticketType == nil {
+		return nil, ErrUnknownTicketType
+	}
+	if !ticketTypeOnSale(ticketType, time.Now()) {
+		return nil, ErrTicketTypeNotOnSale
+	}
+
+	if ticketType.Quota != nil {
+		sold, err := s.repo.ParticipantRepo.CountParticipantsByTicketType(event.ID.String())
+		if err != nil {
+			return nil, errors.New("failed to check quota")
+		}
+		if sold[ticketTypeID] >= int64(*ticketType.Quota) {
+			return nil, ErrTicketTypeSoldOut
+		}
+	}
+	return ticketType, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTokenRevoked = errors.New("token has been revoked")
+	// ErrTokenNotRevocable is returned for tokens issued without a jti claim,
+	// which cannot be denylisted individually
+	ErrTokenNotRevocable = errors.New("token cannot be revoked; log in again to get a revocable token")
+)
+
+// Logout revokes a single token until it would have expired
+func (s *AuthService) Logout(userID, jti string, expiresAt time.Time) error {
+	if jti == "" {
+		return ErrTokenNotRevocable
+	}
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+
+	return s.repo.TokenRepo.RevokeToken(&models.RevokedToken{
+		JTI:       jti,
+		UserID:    parsedUserID,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// RevokeUserTokens invalidates every token issued to a user so far, e.g.
+// after their credentials leaked. Logging in again issues a valid token.
+func (s *AuthService) RevokeUserTokens(actorID, userID string) error {
+	if err := s.revokeUserTokens(userID); err != nil {
+		return err
+	}
+	s.audit.Record(actorID, AuditUserTokensRevoked, AuditEntityUser, userID, nil)
+	return nil
+}
+
+func (s *AuthService) revokeUserTokens(userID string) error {
+	if err := s.repo.UserRepo.RevokeUserTokens(userID, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ValidateToken rejects tokens that were revoked, by logout or for all of
+// the user's tokens, and tokens of deactivated users
+func (s *AuthService) ValidateToken(userID, jti string, issuedAt time.Time) error {
+	if jti != "" {
+		revoked, err := s.repo.TokenRepo.IsTokenRevoked(jti)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return ErrTokenRevoked
+		}
+	}
+
+	user, err := s.repo.UserRepo.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if !user.IsActive {
+		return ErrUserDeactivated
+	}
+	if user.Role == RoleScanner {
+		if err := s.checkServiceToken(user, jti); err != nil {
+			return err
+		}
+	}
+	// iat has second precision, so tokens issued in the second of the
+	// revocation are rejected as well
+	if user.TokensRevokedAt != nil && !issuedAt.After(user.TokensRevokedAt.Truncate(time.Second)) {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// DeleteExpiredRevokedTokens prunes denylist entries of expired tokens and
+// expired refresh tokens
+func (s *AuthService) DeleteExpiredRevokedTokens() (int64, error) {
+	now := time.Now()
+	revoked, err := s.repo.TokenRepo.DeleteExpiredRevokedTokens(now)
+	if err != nil {
+		return revoked, err
+	}
+	refresh, err := s.repo.TokenRepo.DeleteExpiredRefreshTokens(now)
+	return revoked + refresh, err
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/utils"
+)
+
+var (
+	// ErrOTPRequired is returned by Authenticate when the user has two-factor
+	// authentication enabled and no code was given
+	ErrOTPRequired        = errors.New("two-factor code required")
+	ErrInvalidOTP         = errors.New("invalid two-factor code")
+	ErrTOTPAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTOTPNotSetUp       = errors.New("two-factor authentication has not been set up")
+)
+
+// TOTPSetup holds what an authenticator app needs to enroll
+type TOTPSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// SetupTOTP generates a new authenticator secret for a user. The secret stays
+// pending, and login unchanged, until ConfirmTOTP succeeds. Calling it again
+// replaces a pending secret.
+func (s *AuthService) SetupTOTP(userID string) (*TOTPSetup, error) {
+	user, err := s.repo.UserRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := utils.NewTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+	user.TOTPSecret = secret
+	if err := s.repo.UserRepo.UpdateUser(user); err != nil {
+		return nil, fmt.Errorf("failed to save secret: %w", err)
+	}
+
+	return &TOTPSetup{
+		Secret: secret,
+		URI:    utils.TOTPURI(s.cfg.TOTPIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication once the user proves their
+// authenticator app produces valid codes
+func (s *AuthService) ConfirmTOTP(userID, code string) error {
+	user, err := s.repo.UserRepo.GetUserByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if user.TOTPEnabled {
+		return ErrTOTPAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return ErrTOTPNotSetUp
+	}
+
+	if err := s.checkTOTP(user, code); err != nil {
+		return err
+	}
+
+	// Reload so the step recorded by checkTOTP is not overwritten
+	user, err = s.repo.UserRepo.GetUserByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	user.TOTPEnabled = true
+	return s.repo.UserRepo.UpdateUser(user)
+}
+
+// checkTOTP validates a code of the user's secret. Each code is accepted only
+// once.
+func (s *AuthService) checkTOTP(user *models.User, code string) error {
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return ErrInvalidOTP
+	}
+
+	fresh, err := s.repo.UserRepo.UseTOTPStep(user.ID.String(), step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrInvalidOTP
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Logins listed in a user's activity
+	recentLoginLimit = 20
+	// Logins older than this are deleted by the login cleanup job
+	loginHistoryRetention = 90 * 24 * time.Hour
+)
+
+// LoginClient identifies where a login came from
+type LoginClient struct {
+	IP        string
+	UserAgent string
+}
+
+// UserActivity summarizes how a user account is being used
+type UserActivity struct {
+	UserID       uuid.UUID           `json:"user_id"`
+	Email        string              `json:"email"`
+	Role         string              `json:"role"`
+	IsActive     bool                `json:"is_active"`
+	LastLoginAt  *time.Time          `json:"last_login_at"`
+	LastLoginIP  string              `json:"last_login_ip"`
+	RecentLogins []models.LoginEvent `json:"recent_logins"`
+	// Verifications made since Since, per event
+	Since              time.Time                         `json:"since"`
+	VerificationsTotal int64                             `json:"verifications_total"`
+	Verifications      []repositories.VerifierEventCount `json:"verifications"`
+}
+
+func (s *AuthService) recordLogin(user *models.User, client LoginClient) error {
+	userAgent := client.UserAgent
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	login := &models.LoginEvent{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		IP:        client.IP,
+		UserAgent: userAgent,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.UserRepo.RecordLogin(login); err != nil {
+		return err
+	}
+
+	user.LastLoginAt = &login.CreatedAt
+	user.LastLoginIP = login.IP
+	return nil
+}
+
+// GetUserActivity returns a user's recent logins and the verifications they
+// made in the last days
+func (s *AuthService) GetUserActivity(userID string, days int) (*UserActivity, error) {
+	if days <= 0 || days > 365 {
+		days = 30
+	}
+
+	user, err := s.repo.UserRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	logins, err := s.repo.UserRepo.ListLoginEvents(userID, recentLoginLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	counts, err := s.repo.ActionRepo.CountActionLogsByVerifier(userID, since)
+	if err != nil {
+		return nil, err
+	}
+	var total int64
+	for _, count := range counts {
+		total += count.Count
+	}
+
+	return &UserActivity{
+		UserID:             user.ID,
+		Email:              user.Email,
+		Role:               user.Role,
+		IsActive:           user.IsActive,
+		LastLoginAt:        user.LastLoginAt,
+		LastLoginIP:        user.LastLoginIP,
+		RecentLogins:       logins,
+		Since:              since,
+		VerificationsTotal: total,
+		Verifications:      counts,
+	}, nil
+}
+
+// DeleteOldLogins prunes the login history
+func (s *AuthService) DeleteOldLogins() (int64, error) {
+	return s.repo.UserRepo.DeleteLoginEventsBefore(time.Now().Add(-loginHistoryRetention))
+}
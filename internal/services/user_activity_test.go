@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories/memory"
+)
+
+func TestGetUserActivity(t *testing.T) {
+	e := newTestEnv(t)
+	svc := e.authService()
+	staff := e.fx.User("staff")
+	other := e.fx.User("staff")
+
+	busy, quiet := e.fx.Event(), e.fx.Event()
+	busyAction := e.fx.Action(e.fx.Day(busy))
+	quietAction := e.fx.Action(e.fx.Day(quiet))
+	for i := 0; i < 3; i++ {
+		e.fx.Verification(e.fx.Participant(busy), busyAction, staff)
+	}
+	e.fx.Verification(e.fx.Participant(quiet), quietAction, staff)
+	e.fx.Verification(e.fx.Participant(quiet), quietAction, other)
+	e.fx.Verification(e.fx.Participant(quiet), quietAction, staff, func(l *models.ActionLog) {
+		l.VerifiedAt = time.Now().AddDate(0, 0, -60)
+	})
+
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+		if _, err := svc.Authenticate(staff.Email, memory.FixturePassword, "", false, LoginClient{IP: ip, UserAgent: "Scanner"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	activity, err := svc.GetUserActivity(staff.ID.String(), 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if activity.LastLoginAt == nil || activity.LastLoginIP != "10.0.0.2" {
+		t.Fatalf("last login %v from %q, want one from 10.0.0.2", activity.LastLoginAt, activity.LastLoginIP)
+	}
+	if len(activity.RecentLogins) != 2 || activity.RecentLogins[0].IP != "10.0.0.2" {
+		t.Fatalf("recent logins = %+v, want 2, newest first", activity.RecentLogins)
+	}
+	if activity.VerificationsTotal != 4 || len(activity.Verifications) != 2 {
+		t.Fatalf("verifications = %d %+v, want 4 over 2 events", activity.VerificationsTotal, activity.Verifications)
+	}
+	if v := activity.Verifications[0]; v.EventID != busy.ID || v.Count != 3 || v.EventTitle != busy.Title {
+		t.Fatalf("busiest event = %+v, want 3 for %s", v, busy.ID)
+	}
+
+	if activity, _ := svc.GetUserActivity(other.ID.String(), 30); activity.LastLoginAt != nil || len(activity.RecentLogins) != 0 {
+		t.Fatalf("user who never logged in has activity %+v", activity)
+	}
+	if _, err := svc.GetUserActivity(busy.ID.String(), 30); err != ErrUserNotFound {
+		t.Fatalf("unknown user: error = %v, want %v", err, ErrUserNotFound)
+	}
+}
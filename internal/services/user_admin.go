@@ -0,0 +1,211 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user not found")
+	ErrUserDeactivated = errors.New("account is deactivated")
+	// Admins cannot lock themselves out by demoting or deactivating their
+	// own account
+	ErrModifyOwnAccount = errors.New("admins cannot demote or deactivate their own account")
+)
+
+// What DeleteUser does with the verifications a user recorded
+const (
+	VerificationsKeep      = "keep"      // they stay in the deleted user's name
+	VerificationsReassign  = "reassign"  // they move to another user
+	VerificationsAnonymize = "anonymize" // the deleted user no longer identifies them
+)
+
+// DeleteUserRequest holds the options of DeleteUser
+type DeleteUserRequest struct {
+	// keep when empty
+	Verifications string
+	// Required with reassign
+	ReassignTo string
+}
+
+// UpdateUserRequest holds the fields to change; nil fields are kept
+type UpdateUserRequest struct {
+	Email    *string
+	Password *string
+	Role     *string
+	IsActive *bool
+}
+
+// ListUsers returns a page of users, newest first. An empty role lists all
+// users.
+func (s *AuthService) ListUsers(role string, page, pageSize int) ([]models.User, int64, int, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+	users, total, err := s.repo.UserRepo.ListUsers(strings.TrimSpace(strings.ToLower(role)), offset, pageSize)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	totalPages := (int(total) + pageSize - 1) / pageSize
+	return users, total, totalPages, nil
+}
+
+// UpdateUser changes a user's email, password, role or active state on
+// behalf of the admin actorID. Changing the password or role revokes the
+// user's tokens, which were issued for the old ones. Promotions are
+// rejected with ErrRoleChangeNeedsApproval; see RequestRoleChange.
+func (s *AuthService) UpdateUser(actorID, id string, req UpdateUserRequest) (*models.User, error) {
+	user, err := s.repo.UserRepo.GetUserByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	revokeTokens := false
+	// Names of the changed fields, never their values
+	changed := []string{}
+
+	if req.Email != nil {
+		email := strings.TrimSpace(strings.ToLower(*req.Email))
+		if email == "" {
+			return nil, errors.New("email cannot be empty")
+		}
+		if existing, _ := s.repo.UserRepo.GetUserByEmail(email); existing != nil && existing.ID != user.ID {
+			return nil, errors.New("email already registered")
+		}
+		if email != user.Email {
+			changed = append(changed, "email")
+		}
+		user.Email = email
+	}
+
+	if req.Role != nil {
+		role := strings.TrimSpace(strings.ToLower(*req.Role))
+		if !allowedRoles[role] {
+			return nil, errors.New("invalid role: must be admin, organizer, or staff")
+		}
+		if role != user.Role {
+			if id == actorID {
+				return nil, ErrModifyOwnAccount
+			}
+			if isPromotion(user.Role, role) {
+				return nil, ErrRoleChangeNeedsApproval
+			}
+			revokeTokens = true
+			changed = append(changed, "role")
+		}
+		user.Role = role
+	}
+
+	if req.IsActive != nil {
+		if id == actorID && !*req.IsActive {
+			return nil, ErrModifyOwnAccount
+		}
+		if *req.IsActive != user.IsActive {
+			changed = append(changed, "is_active")
+		}
+		user.IsActive = *req.IsActive
+	}
+
+	if req.Password != nil {
+		hashedPassword, err := hashPassword(s.cfg, *req.Password)
+		if err != nil {
+			return nil, err
+		}
+		user.Password = hashedPassword
+		revokeTokens = true
+		changed = append(changed, "password")
+	}
+
+	if err := s.repo.UserRepo.UpdateUser(user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+	if revokeTokens {
+		if err := s.revokeUserTokens(id); err != nil {
+			return nil, fmt.Errorf("failed to revoke tokens: %w", err)
+		}
+	}
+	if len(changed) > 0 {
+		s.audit.Record(actorID, AuditUserUpdated, AuditEntityUser, id, map[string]interface{}{
+			"fields":         changed,
+			"tokens_revoked": revokeTokens,
+		})
+	}
+
+	// Remove password from response
+	user.Password = ""
+	return user, nil
+}
+
+// DeactivateUser blocks a user from logging in. The account and everything
+// recorded in its name are kept, and it can be reactivated with UpdateUser.
+func (s *AuthService) DeactivateUser(actorID, id string) error {
+	if id == actorID {
+		return ErrModifyOwnAccount
+	}
+
+	if err := s.repo.UserRepo.DeactivateUser(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	s.audit.Record(actorID, AuditUserDeactivated, AuditEntityUser, id, nil)
+	return nil
+}
+
+// DeleteUser removes a user on behalf of actorID. The account is soft
+// deleted: it is deactivated, hidden from listings and its event assignments
+// are removed, but the verifications it recorded are kept according to
+// req.Verifications.
+func (s *AuthService) DeleteUser(actorID, id string, req DeleteUserRequest) error {
+	if id == actorID {
+		return ErrModifyOwnAccount
+	}
+	if _, err := s.repo.UserRepo.GetUserByID(id); err != nil {
+		return ErrUserNotFound
+	}
+
+	deletion := repositories.UserDeletion{At: time.Now()}
+	details := map[string]interface{}{}
+	switch req.Verifications {
+	case "", VerificationsKeep:
+		details["verifications"] = VerificationsKeep
+	case VerificationsReassign:
+		target, err := s.repo.UserRepo.GetUserByID(req.ReassignTo)
+		if err != nil || target.ID.String() == id {
+			return errors.New("reassign_to must be another existing user")
+		}
+		if !target.IsActive {
+			return fmt.Errorf("cannot reassign to %s: %w", target.Email, ErrUserDeactivated)
+		}
+		deletion.ReassignTo = &target.ID
+		details["verifications"] = VerificationsReassign
+		details["reassigned_to"] = target.ID.String()
+	case VerificationsAnonymize:
+		deletion.Anonymize = true
+		details["verifications"] = VerificationsAnonymize
+	default:
+		return errors.New("verifications must be keep, reassign or anonymize")
+	}
+
+	if err := s.repo.UserRepo.DeleteUser(id, deletion); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	s.audit.Record(actorID, AuditUserDeleted, AuditEntityUser, id, details)
+	return nil
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-management-backend/internal/repositories/memory"
+)
+
+func TestUpdateUserRevokesTokens(t *testing.T) {
+	email := "renamed@example.com"
+	password := "new-password-123"
+	organizer := "organizer"
+	staff := "staff"
+
+	tests := []struct {
+		name string
+		// staff when empty
+		role        string
+		req         UpdateUserRequest
+		wantRevoked bool
+	}{
+		{name: "email", req: UpdateUserRequest{Email: &email}},
+		{name: "same role", req: UpdateUserRequest{Role: &staff}},
+		{name: "password", req: UpdateUserRequest{Password: &password}, wantRevoked: true},
+		{name: "demotion", role: organizer, req: UpdateUserRequest{Role: &staff}, wantRevoked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			svc := e.authService()
+			admin := e.fx.User("admin")
+			role := tt.role
+			if role == "" {
+				role = staff
+			}
+			user := e.fx.User(role)
+			issuedAt := time.Now().Add(-time.Minute)
+
+			if _, err := svc.UpdateUser(admin.ID.String(), user.ID.String(), tt.req); err != nil {
+				t.Fatal(err)
+			}
+
+			err := svc.ValidateToken(user.ID.String(), "", issuedAt)
+			if revoked := errors.Is(err, ErrTokenRevoked); revoked != tt.wantRevoked {
+				t.Fatalf("ValidateToken = %v, want revoked: %v", err, tt.wantRevoked)
+			}
+		})
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          func(other string) DeleteUserRequest
+		wantErr      bool
+		wantVerifier func(user, other string) string
+		wantEmail    func(user string) string
+	}{
+		{
+			name:         "keep",
+			req:          func(string) DeleteUserRequest { return DeleteUserRequest{} },
+			wantVerifier: func(user, _ string) string { return user },
+		},
+		{
+			name: "reassign",
+			req: func(other string) DeleteUserRequest {
+				return DeleteUserRequest{Verifications: VerificationsReassign, ReassignTo: other}
+			},
+			wantVerifier: func(_, other string) string { return other },
+		},
+		{
+			name:    "reassign without a target",
+			req:     func(string) DeleteUserRequest { return DeleteUserRequest{Verifications: VerificationsReassign} },
+			wantErr: true,
+		},
+		{
+			name:         "anonymize",
+			req:          func(string) DeleteUserRequest { return DeleteUserRequest{Verifications: VerificationsAnonymize} },
+			wantVerifier: func(user, _ string) string { return user },
+			wantEmail:    func(user string) string { return "deleted-" + user + "@deleted.invalid" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			svc := e.authService()
+			admin := e.fx.User("admin")
+			staff := e.fx.User("staff")
+			other := e.fx.User("staff")
+
+			event := e.fx.Event()
+			action := e.fx.Action(e.fx.Day(event))
+			e.fx.Verification(e.fx.Participant(event), action, staff)
+			if _, err := NewEventService(e.repo, e.cfg).AssignStaff(event.ID.String(), staff.ID.String(), admin.ID.String()); err != nil {
+				t.Fatal(err)
+			}
+
+			err := svc.DeleteUser(admin.ID.String(), staff.ID.String(), tt.req(other.ID.String()))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := svc.GetUserProfile(staff.ID.String()); err == nil {
+				t.Fatal("deleted user is still found")
+			}
+			if _, err := svc.Authenticate(staff.Email, memory.FixturePassword, "", false, LoginClient{}); err == nil {
+				t.Fatal("deleted user logged in")
+			}
+			if ids, _ := NewEventService(e.repo, e.cfg).StaffEventIDs(staff.ID.String(), "staff"); len(ids) != 0 {
+				t.Fatalf("deleted user is still assigned to %v", ids)
+			}
+
+			logs, _, err := e.repo.ActionRepo.GetActionLogsByEvent(event.ID.String(), 0, 10)
+			if err != nil || len(logs) != 1 {
+				t.Fatalf("logs = %v, %v; want 1", logs, err)
+			}
+			want := tt.wantVerifier(staff.ID.String(), other.ID.String())
+			if logs[0].VerifiedBy.String() != want || logs[0].Verifier.ID.String() != want {
+				t.Fatalf("verifier %s (%s), want %s", logs[0].VerifiedBy, logs[0].Verifier.ID, want)
+			}
+			wantEmail := staff.Email
+			if tt.wantEmail != nil {
+				wantEmail = tt.wantEmail(staff.ID.String())
+			}
+			if want == staff.ID.String() && logs[0].Verifier.Email != wantEmail {
+				t.Fatalf("verifier email %q, want %q", logs[0].Verifier.Email, wantEmail)
+			}
+		})
+	}
+
+	t.Run("own account", func(t *testing.T) {
+		e := newTestEnv(t)
+		admin := e.fx.User("admin")
+		if err := e.authService().DeleteUser(admin.ID.String(), admin.ID.String(), DeleteUserRequest{}); !errors.Is(err, ErrModifyOwnAccount) {
+			t.Fatalf("error = %v, want %v", err, ErrModifyOwnAccount)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"event-management-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrUnknownVenue = errors.New("venue not found")
+
+type VenueRequest struct {
+	Name      string
+	Address   string
+	Latitude  *float64
+	Longitude *float64
+	Capacity  *int
+}
+
+func (req VenueRequest) validate() error {
+	if strings.TrimSpace(req.Name) == "" {
+		return errors.New("name is required")
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return errors.New("latitude and longitude must be given together")
+	}
+	if req.Latitude != nil && (*req.Latitude < -90 || *req.Latitude > 90) {
+		return errors.New("latitude must be between -90 and 90")
+	}
+	if req.Longitude != nil && (*req.Longitude < -180 || *req.Longitude > 180) {
+		return errors.New("longitude must be between -180 and 180")
+	}
+	if req.Capacity != nil && *req.Capacity <= 0 {
+		return errors.New("capacity must be positive")
+	}
+	return nil
+}
+
+func (s *EventService) CreateVenue(req VenueRequest) (*models.Venue, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	venue := &models.Venue{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(req.Name),
+		Address:   strings.TrimSpace(req.Address),
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		Capacity:  req.Capacity,
+	}
+	if err := s.repo.VenueRepo.CreateVenue(venue); err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
+
+func (s *EventService) GetVenue(id string) (*models.Venue, error) {
+	venue, err := s.repo.VenueRepo.GetVenue(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUnknownVenue
+	}
+	return venue, err
+}
+
+func (s *EventService) ListVenues(search string, page, pageSize int) ([]models.Venue, int64, int, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	venues, total, err := s.repo.VenueRepo.ListVenues(strings.TrimSpace(search), (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	totalPages := (int(total) + pageSize - 1) / pageSize
+	return venues, total, totalPages, nil
+}
+
+// UpdateVenue replaces the details of a venue. Events held there show the
+// change right away.
+func (s *EventService) UpdateVenue(id string, req VenueRequest) (*models.Venue, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	venue, err := s.GetVenue(id)
+	if err != nil {
+		return nil, err
+	}
+
+	venue.Name = strings.TrimSpace(req.Name)
+	venue.Address = strings.TrimSpace(req.Address)
+	venue.Latitude = req.Latitude
+	venue.Longitude = req.Longitude
+	venue.Capacity = req.Capacity
+	if err := s.repo.VenueRepo.UpdateVenue(venue); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUnknownVenue
+		}
+		return nil, err
+	}
+	return venue, nil
+}
+
+// DeleteVenue removes a venue. Venues still used by an event or day yield
+// repositories.ErrVenueInUse.
+func (s *EventService) DeleteVenue(id string) error {
+	err := s.repo.VenueRepo.DeleteVenue(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUnknownVenue
+	}
+	return err
+}
+
+// venueID resolves the optional venue of an event or day
+func (s *EventService) venueID(id string) (*uuid.UUID, error) {
+	if id == "" {
+		return nil, nil
+	}
+	venue, err := s.GetVenue(id)
+	if err != nil {
+		return nil, err
+	}
+	return &venue.ID, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"event-management-backend/internal/config"
@@ -18,7 +19,9 @@ import (
 type VerificationService interface {
 	VerifyParticipantAction(req VerifyRequest) (*VerificationResult, error)
 	GetParticipantVerificationHistory(participantID string) ([]*models.ActionLog, error)
+	GetParticipantStatus(participantID string) (*ParticipantStatus, error)
 	GetEventVerifications(eventID string, filters *VerificationFilters) (*VerificationList, error)
+	GetEventVerificationsByCursor(eventID, cursor string, pageSize int) (*VerificationList, error)
 	GetVerificationStats(eventID string) (*VerificationStats, error)
 	CanVerifyParticipant(participantID, actionID string) (bool, error)
 	RevertVerification(verificationID, adminID string) error
@@ -28,6 +31,13 @@ type VerifyRequest struct {
 	QRCodeData string `json:"qr_code_data" validate:"required"`
 	ActionCode string `json:"action_code" validate:"required"`
 	VerifierID string `json:"-"`
+	// Set instead of VerifierID for scans made with an API key
+	APIKeyID string `json:"-"`
+	// Limits the scan to actions of these events, e.g. for API keys; nil
+	// allows any event
+	EventIDs []string `json:"-"`
+	// Registered device the scan was made on, if any
+	DeviceID string `json:"-"`
 }
 
 type VerificationResult struct {
@@ -36,7 +46,19 @@ type VerificationResult struct {
 	ActionLog   *models.ActionLog   `json:"action_log,omitempty"`
 	Participant *models.Participant `json:"participant,omitempty"`
 	EventAction *models.EventAction `json:"event_action,omitempty"`
-	Timestamp   time.Time           `json:"timestamp"`
+	// Set when the participant has a seat on the day of the action
+	Seat *SeatInfo `json:"seat,omitempty"`
+	// Set when the participant's badge was reissued and staff must check an
+	// ID before letting them in. Only the first scan after the reissue asks.
+	IDCheckRequired bool      `json:"id_check_required,omitempty"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+// SeatInfo tells the door staff where a participant sits
+type SeatInfo struct {
+	TableID    string `json:"table_id"`
+	TableName  string `json:"table_name"`
+	SeatNumber int    `json:"seat_number"`
 }
 
 type VerificationFilters struct {
@@ -54,6 +76,7 @@ type VerificationList struct {
 	Page          int                 `json:"page"`
 	PageSize      int                 `json:"page_size"`
 	TotalPages    int                 `json:"total_pages"`
+	NextCursor    string              `json:"next_cursor,omitempty"`
 }
 
 type VerificationStats struct {
@@ -73,6 +96,12 @@ type verificationService struct {
 	eventRepo       repositories.EventRepository
 	userRepo        repositories.UserRepository
 	participantRepo repositories.ParticipantRepository
+	sessionRepo     repositories.SessionRepository
+	waiverRepo      repositories.WaiverRepository
+	seatingRepo     repositories.SeatingRepository
+	mealRepo        repositories.MealRepository
+	shiftRepo       repositories.ShiftRepository
+	deviceRepo      repositories.DeviceRepository
 	cfg             *config.Config
 }
 
@@ -82,6 +111,12 @@ func NewVerificationService(
 	eventRepo repositories.EventRepository,
 	userRepo repositories.UserRepository,
 	participantRepo repositories.ParticipantRepository,
+	sessionRepo repositories.SessionRepository,
+	waiverRepo repositories.WaiverRepository,
+	seatingRepo repositories.SeatingRepository,
+	mealRepo repositories.MealRepository,
+	shiftRepo repositories.ShiftRepository,
+	deviceRepo repositories.DeviceRepository,
 	cfg *config.Config,
 ) VerificationService {
 	return &verificationService{
@@ -89,6 +124,12 @@ func NewVerificationService(
 		eventRepo:       eventRepo,
 		userRepo:        userRepo,
 		participantRepo: participantRepo,
+		sessionRepo:     sessionRepo,
+		waiverRepo:      waiverRepo,
+		seatingRepo:     seatingRepo,
+		mealRepo:        mealRepo,
+		shiftRepo:       shiftRepo,
+		deviceRepo:      deviceRepo,
 		cfg:             cfg,
 	}
 }
@@ -111,11 +152,29 @@ func (s *verificationService) VerifyParticipantAction(req VerifyRequest) (*Verif
 	if err != nil {
 		return nil, err
 	}
+	if !eventAllowed(req.EventIDs, action.EventID.String()) {
+		return nil, NewVerificationError("not allowed to verify for this event", ErrPermissionDenied, nil)
+	}
 
-	// Step 4: Get verifier information
-	verifier, err := s.userRepo.GetUserByID(req.VerifierID)
-	if err != nil {
-		return nil, NewVerificationError("verifier not found", ErrVerifierNotFound, err)
+	// Step 4: Get verifier information. API keys are limited to their
+	// events instead of staff shifts.
+	var verifier *models.User
+	if req.APIKeyID == "" {
+		verifier, err = s.userRepo.GetUserByID(req.VerifierID)
+		if err != nil {
+			return nil, NewVerificationError("verifier not found", ErrVerifierNotFound, err)
+		}
+		if err := checkOnShift(s.shiftRepo, s.cfg.ShiftGracePeriod, verifier, action); err != nil {
+			return nil, err
+		}
+	}
+
+	var device *models.Device
+	if req.DeviceID != "" {
+		device, err = s.deviceRepo.GetDevice(req.DeviceID)
+		if err != nil {
+			return nil, NewVerificationError("device not registered", ErrDeviceNotFound, err)
+		}
 	}
 
 	// Step 5: Perform comprehensive verification checks
@@ -124,22 +183,48 @@ func (s *verificationService) VerifyParticipantAction(req VerifyRequest) (*Verif
 	}
 
 	// Step 6: Create verification record
-	actionLog, err := s.createVerificationRecord(participant, action, verifier)
+	actionLog, err := s.createVerificationRecord(participant, action, verifier, req.APIKeyID, device)
 	if err != nil {
 		return nil, err
 	}
+	if device != nil {
+		_ = s.deviceRepo.TouchDevice(device.ID.String(), actionLog.VerifiedAt)
+	}
+
+	// Step 7: Consume a pending ID check. Should clearing fail, the next
+	// scan asks again, which errs on the safe side.
+	idCheck := participant.RequiresIDCheck
+	if idCheck {
+		_ = s.participantRepo.ClearIDCheck(participant.ID.String())
+	}
 
-	// Step 7: Return successful result
+	// Step 8: Return successful result
 	return &VerificationResult{
-		Success:     true,
-		Message:     fmt.Sprintf("Successfully verified %s for participant %s", action.Name, participant.Name),
-		ActionLog:   actionLog,
-		Participant: participant,
-		EventAction: action,
-		Timestamp:   time.Now(),
+		Success:         true,
+		Message:         fmt.Sprintf("Successfully verified %s for participant %s", action.Name, participant.Name),
+		ActionLog:       actionLog,
+		Participant:     participant,
+		EventAction:     action,
+		Seat:            s.seatOf(participant, action),
+		IDCheckRequired: idCheck,
+		Timestamp:       time.Now(),
 	}, nil
 }
 
+// seatOf returns the seat of the participant on the day of the action, or
+// nil when the day is not seated or the participant has no seat
+func (s *verificationService) seatOf(participant *models.Participant, action *models.EventAction) *SeatInfo {
+	assignment, err := s.seatingRepo.GetAssignment(action.EventDayID.String(), participant.ID.String())
+	if err != nil {
+		return nil
+	}
+	return &SeatInfo{
+		TableID:    assignment.TableID.String(),
+		TableName:  assignment.Table.Name,
+		SeatNumber: assignment.SeatNumber,
+	}
+}
+
 // GetParticipantVerificationHistory returns all verification records for a participant
 func (s *verificationService) GetParticipantVerificationHistory(participantID string) ([]*models.ActionLog, error) {
 	if participantID == "" {
@@ -159,6 +244,113 @@ func (s *verificationService) GetParticipantVerificationHistory(participantID st
 	return verifications, nil
 }
 
+// ParticipantStatus is the checklist of a participant: every active action of
+// their event, in the order of the agenda, and whether it was verified
+type ParticipantStatus struct {
+	Participant *models.Participant `json:"participant"`
+	// Actions verified out of Total
+	Verified int            `json:"verified"`
+	Total    int            `json:"total"`
+	Actions  []ActionStatus `json:"actions"`
+}
+
+// ActionStatus tells whether a participant was verified for one action
+type ActionStatus struct {
+	ActionID   uuid.UUID `json:"action_id"`
+	ActionName string    `json:"action_name"`
+	ActionCode string    `json:"action_code"`
+	Zone       string    `json:"zone,omitempty"`
+	EventDayID uuid.UUID `json:"event_day_id"`
+	DayLabel   string    `json:"day_label"`
+	Verified   bool      `json:"verified"`
+	// Latest verification, when there is one
+	VerifiedAt *time.Time      `json:"verified_at,omitempty"`
+	VerifiedBy *ActionVerifier `json:"verified_by,omitempty"`
+	// scan|online
+	Source string `json:"source,omitempty"`
+}
+
+// ActionVerifier is who verified an action: a user or, for kiosks, an API key
+type ActionVerifier struct {
+	UserID   *uuid.UUID `json:"user_id,omitempty"`
+	APIKeyID *uuid.UUID `json:"api_key_id,omitempty"`
+	// Name or email of the user, or name of the API key
+	Name string `json:"name"`
+}
+
+// GetParticipantStatus returns the checklist of a participant, for the
+// scanner app to show after a scan. Verifications of actions deactivated
+// since are left out.
+func (s *verificationService) GetParticipantStatus(participantID string) (*ParticipantStatus, error) {
+	participant, err := s.participantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return nil, NewVerificationError("participant not found", ErrParticipantNotFound, err)
+	}
+	eventID := participant.EventID.String()
+
+	days, err := s.eventRepo.GetEventDaysByEventID(eventID)
+	if err != nil {
+		return nil, NewVerificationError("failed to get event days", ErrDatabaseError, err)
+	}
+	dayLabels := make(map[uuid.UUID]string, len(days))
+	for _, day := range days {
+		dayLabels[day.ID] = day.Label
+	}
+	actions, err := s.eventRepo.GetEventActionsByEventID(eventID)
+	if err != nil {
+		return nil, NewVerificationError("failed to get event actions", ErrDatabaseError, err)
+	}
+	logs, err := s.actionRepo.GetActionLogsByParticipant(participantID)
+	if err != nil {
+		return nil, NewVerificationError("failed to get verification history", ErrDatabaseError, err)
+	}
+	// Logs come newest first, so the first of each action is its latest
+	latest := make(map[uuid.UUID]*models.ActionLog, len(logs))
+	for _, log := range logs {
+		if _, ok := latest[log.ActionID]; !ok {
+			latest[log.ActionID] = log
+		}
+	}
+
+	status := &ParticipantStatus{Participant: participant, Total: len(actions), Actions: make([]ActionStatus, 0, len(actions))}
+	for _, action := range actions {
+		item := ActionStatus{
+			ActionID:   action.ID,
+			ActionName: action.Name,
+			ActionCode: action.Code,
+			Zone:       action.Zone,
+			EventDayID: action.EventDayID,
+			DayLabel:   dayLabels[action.EventDayID],
+		}
+		if log, ok := latest[action.ID]; ok {
+			verifiedAt := log.VerifiedAt
+			item.Verified, item.VerifiedAt, item.Source = true, &verifiedAt, log.Source
+			item.VerifiedBy = actionVerifier(log)
+			status.Verified++
+		}
+		status.Actions = append(status.Actions, item)
+	}
+	return status, nil
+}
+
+func actionVerifier(log *models.ActionLog) *ActionVerifier {
+	switch {
+	case log.VerifiedBy != nil:
+		name := log.Verifier.Name
+		if name == "" {
+			name = log.Verifier.Email
+		}
+		return &ActionVerifier{UserID: log.VerifiedBy, Name: name}
+	case log.APIKeyID != nil:
+		verifier := &ActionVerifier{APIKeyID: log.APIKeyID}
+		if log.APIKey != nil {
+			verifier.Name = log.APIKey.Name
+		}
+		return verifier
+	}
+	return nil
+}
+
 // GetEventVerifications returns paginated verification records for an event with filters
 func (s *verificationService) GetEventVerifications(eventID string, filters *VerificationFilters) (*VerificationList, error) {
 	if eventID == "" {
@@ -204,6 +396,51 @@ func (s *verificationService) GetEventVerifications(eventID string, filters *Ver
 	}, nil
 }
 
+// GetEventVerificationsByCursor returns one keyset-paginated page of verification
+// records for an event. Counts are not computed in this mode.
+func (s *verificationService) GetEventVerificationsByCursor(eventID, cursor string, pageSize int) (*VerificationList, error) {
+	if eventID == "" {
+		return nil, NewVerificationError("event ID is required", ErrInvalidInput, nil)
+	}
+
+	// Validate event exists
+	if _, err := s.eventRepo.GetEventByID(eventID); err != nil {
+		return nil, NewVerificationError("event not found", ErrEventNotFound, err)
+	}
+
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	var after *repositories.Cursor
+	if cursor != "" {
+		t, id, err := utils.DecodeCursor(cursor)
+		if err != nil {
+			return nil, NewVerificationError("invalid cursor", ErrInvalidInput, err)
+		}
+		after = &repositories.Cursor{Time: t, ID: id}
+	}
+
+	// Fetch one extra row to know whether another page exists
+	verifications, err := s.actionRepo.GetActionLogsByEventAfter(eventID, after, pageSize+1)
+	if err != nil {
+		return nil, NewVerificationError("failed to get event verifications", ErrDatabaseError, err)
+	}
+
+	nextCursor := ""
+	if len(verifications) > pageSize {
+		verifications = verifications[:pageSize]
+		last := verifications[len(verifications)-1]
+		nextCursor = utils.EncodeCursor(last.VerifiedAt, last.ID)
+	}
+
+	return &VerificationList{
+		Verifications: verifications,
+		PageSize:      pageSize,
+		NextCursor:    nextCursor,
+	}, nil
+}
+
 // GetVerificationStats returns verification statistics for an event
 func (s *verificationService) GetVerificationStats(eventID string) (*VerificationStats, error) {
 	if eventID == "" {
@@ -252,7 +489,7 @@ func (s *verificationService) CanVerifyParticipant(participantID, actionID strin
 	}
 
 	// Check payment status for paid events
-	if s.isPaidEvent(participant.EventID.String()) && participant.PaymentStatus != "paid" {
+	if s.isPaidEvent(participant.EventID.String()) && !paymentSettled(participant) {
 		return false, NewVerificationError("participant has not paid", ErrPaymentRequired, nil)
 	}
 
@@ -301,6 +538,19 @@ func (s *verificationService) RevertVerification(verificationID, adminID string)
 
 // Private helper methods
 
+// eventAllowed reports whether an event is in allowed; nil allows any event
+func eventAllowed(allowed []string, eventID string) bool {
+	if allowed == nil {
+		return true
+	}
+	for _, id := range allowed {
+		if id == eventID {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *verificationService) validateVerifyRequest(req VerifyRequest) error {
 	if req.QRCodeData == "" {
 		return NewVerificationError("QR code data is required", ErrInvalidInput, nil)
@@ -310,7 +560,11 @@ func (s *verificationService) validateVerifyRequest(req VerifyRequest) error {
 		return NewVerificationError("action code is required", ErrInvalidInput, nil)
 	}
 
-	if req.VerifierID == "" {
+	if req.APIKeyID != "" {
+		if _, err := uuid.Parse(req.APIKeyID); err != nil {
+			return NewVerificationError("invalid API key ID", ErrInvalidInput, err)
+		}
+	} else if req.VerifierID == "" {
 		return NewVerificationError("verifier ID is required", ErrInvalidInput, nil)
 	}
 
@@ -318,18 +572,12 @@ func (s *verificationService) validateVerifyRequest(req VerifyRequest) error {
 }
 
 func (s *verificationService) extractParticipantFromQR(qrData string) (*models.Participant, error) {
-	// Try different methods to extract participant ID from QR data
-	participantID, err := utils.ExtractUUIDFromQRPath(qrData)
+	credential, err := parseParticipantCredential(qrData)
 	if err != nil {
-		// If extraction fails, try direct UUID parsing
-		if _, err := uuid.Parse(qrData); err == nil {
-			participantID = qrData
-		} else {
-			return nil, NewVerificationError("invalid QR code format", ErrInvalidQRCode, err)
-		}
+		return nil, NewVerificationError("invalid QR code format", ErrInvalidQRCode, err)
 	}
 
-	participant, err := s.participantRepo.GetParticipantByID(participantID)
+	participant, err := s.participantRepo.GetParticipantByID(credential.ParticipantID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, NewVerificationError("participant not found", ErrParticipantNotFound, err)
@@ -337,6 +585,14 @@ func (s *verificationService) extractParticipantFromQR(qrData string) (*models.P
 		return nil, NewVerificationError("failed to get participant", ErrDatabaseError, err)
 	}
 
+	// Badges replaced after a loss must not get in anymore
+	if !credential.currentFor(s.cfg.CredentialSigningKey, participant) {
+		return nil, NewVerificationError("credential has been revoked, a newer badge was issued", ErrCredentialRevoked, nil)
+	}
+	if participant.CancelledAt != nil {
+		return nil, NewVerificationError("registration has been cancelled", ErrRegistrationCancelled, nil)
+	}
+
 	return participant, nil
 }
 
@@ -353,12 +609,35 @@ func (s *verificationService) getAndValidateAction(actionCode string) (*models.E
 		return nil, NewVerificationError("action is not active", ErrActionInactive, nil)
 	}
 
+	// Participant tickets carry no zone access
+	if !ActionAdmits(action, nil) {
+		return nil, NewVerificationError(
+			fmt.Sprintf("action is restricted to zone: %s", action.Zone),
+			ErrZoneRestricted,
+			nil,
+		)
+	}
+
 	return action, nil
 }
 
+// ActionAdmits reports whether a credential granting zones may be scanned at
+// action. Actions without a zone admit every credential.
+func ActionAdmits(action *models.EventAction, zones []string) bool {
+	if action.Zone == "" {
+		return true
+	}
+	for _, zone := range zones {
+		if strings.EqualFold(zone, action.Zone) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *verificationService) performVerificationChecks(participant *models.Participant, action *models.EventAction) error {
-	// Check payment status for paid events
-	if s.isPaidEvent(participant.EventID.String()) && participant.PaymentStatus != "paid" {
+	// Check payment status for paid events; complimentary tickets are let in
+	if s.isPaidEvent(participant.EventID.String()) && !paymentSettled(participant) {
 		return NewVerificationError(
 			fmt.Sprintf("participant payment status is '%s'", participant.PaymentStatus),
 			ErrPaymentRequired,
@@ -366,6 +645,28 @@ func (s *verificationService) performVerificationChecks(participant *models.Part
 		)
 	}
 
+	// Registrations awaiting review or rejected by the organizer are refused
+	if participant.ApprovalStatus != ApprovalApproved {
+		return NewVerificationError(
+			fmt.Sprintf("registration is %s", participant.ApprovalStatus),
+			ErrNotApproved,
+			nil,
+		)
+	}
+
+	// Participants must have signed the event's waivers
+	unsigned, err := s.waiverRepo.ListUnsignedWaivers(participant.EventID.String(), participant.ID.String())
+	if err != nil {
+		return NewVerificationError("failed to check waivers", ErrDatabaseError, err)
+	}
+	if len(unsigned) > 0 {
+		return NewVerificationError(
+			fmt.Sprintf("participant has not signed waiver: %s", unsigned[0].Title),
+			ErrWaiverNotSigned,
+			nil,
+		)
+	}
+
 	// Check if already verified for this action
 	alreadyVerified, err := s.actionRepo.HasActionLog(participant.ID.String(), action.ID.String())
 	if err != nil {
@@ -394,6 +695,11 @@ func (s *verificationService) performVerificationChecks(participant *models.Part
 		return err
 	}
 
+	// Actions with a time window only accept scans within it
+	if err := checkActionWindow(action, time.Now()); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -429,26 +735,88 @@ func (s *verificationService) checkEventDayValidity(eventDayID string) error {
 	return nil
 }
 
-func (s *verificationService) createVerificationRecord(participant *models.Participant, action *models.EventAction, verifier *models.User) (*models.ActionLog, error) {
+// createVerificationRecord records the scan against verifier, or against
+// apiKeyID when verifier is nil. device is nil when the app did not send one.
+func (s *verificationService) createVerificationRecord(participant *models.Participant, action *models.EventAction, verifier *models.User, apiKeyID string, device *models.Device) (*models.ActionLog, error) {
 	actionLog := &models.ActionLog{
 		ID:            uuid.New(),
 		ParticipantID: participant.ID,
 		ActionID:      action.ID,
-		VerifiedBy:    verifier.ID,
 		VerifiedAt:    time.Now(),
+		Source:        AttendanceScan,
 		CreatedAt:     time.Now(),
 	}
-
-	if err := s.actionRepo.CreateActionLog(actionLog); err != nil {
-		return nil, NewVerificationError("failed to create verification record", ErrDatabaseError, err)
+	if verifier != nil {
+		actionLog.VerifiedBy = &verifier.ID
+	} else {
+		keyID := uuid.MustParse(apiKeyID)
+		actionLog.APIKeyID = &keyID
+	}
+	if device != nil {
+		actionLog.DeviceID = &device.ID
+	}
+
+	// Meal coupons are served to their preference only, and quota-checked in
+	// the same transaction as the insert
+	coupon, err := s.mealRepo.GetMealCouponByActionID(action.ID.String())
+	switch {
+	case err == nil:
+		if participant.MealPreference != coupon.Preference {
+			return nil, NewVerificationError(
+				fmt.Sprintf("meal is for %s participants, participant chose %s", coupon.Preference, participant.MealPreference),
+				ErrMealMismatch,
+				nil,
+			)
+		}
+		if err := s.mealRepo.CreateMealRedemption(coupon, actionLog); err != nil {
+			if errors.Is(err, repositories.ErrMealQuotaReached) {
+				return nil, NewVerificationError("no meals left for this coupon", ErrMealQuotaReached, err)
+			}
+			if errors.Is(err, repositories.ErrEventDayFull) {
+				return nil, NewVerificationError("event day is at full capacity", ErrDayFull, err)
+			}
+			return nil, NewVerificationError("failed to create verification record", ErrDatabaseError, err)
+		}
+		return s.withRelations(actionLog, participant, action, verifier), nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, NewVerificationError("failed to look up meal coupon", ErrDatabaseError, err)
+	}
+
+	// Session actions are capacity-checked in the same transaction as the insert
+	session, err := s.sessionRepo.GetSessionByActionID(action.ID.String())
+	switch {
+	case err == nil:
+		if err := s.sessionRepo.CreateSessionCheckIn(session, actionLog); err != nil {
+			if errors.Is(err, repositories.ErrSessionFull) {
+				return nil, NewVerificationError("session is at full capacity", ErrSessionFull, err)
+			}
+			if errors.Is(err, repositories.ErrEventDayFull) {
+				return nil, NewVerificationError("event day is at full capacity", ErrDayFull, err)
+			}
+			return nil, NewVerificationError("failed to create verification record", ErrDatabaseError, err)
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err := s.actionRepo.CreateActionLog(actionLog); err != nil {
+			if errors.Is(err, repositories.ErrEventDayFull) {
+				return nil, NewVerificationError("event day is at full capacity", ErrDayFull, err)
+			}
+			return nil, NewVerificationError("failed to create verification record", ErrDatabaseError, err)
+		}
+	default:
+		return nil, NewVerificationError("failed to look up session", ErrDatabaseError, err)
 	}
 
-	// Load relationships for the response
+	return s.withRelations(actionLog, participant, action, verifier), nil
+}
+
+// withRelations loads the relationships of a new action log for the response
+func (s *verificationService) withRelations(actionLog *models.ActionLog, participant *models.Participant, action *models.EventAction, verifier *models.User) *models.ActionLog {
 	actionLog.Participant = *participant
 	actionLog.Action = *action
-	actionLog.Verifier = *verifier
-
-	return actionLog, nil
+	if verifier != nil {
+		actionLog.Verifier = *verifier
+	}
+	return actionLog
 }
 
 func (s *verificationService) calculateVerificationStatistics(eventID string, totalParticipants int64) (*VerificationStats, error) {
@@ -484,20 +852,33 @@ func (s *verificationService) calculateVerificationStatistics(eventID string, to
 type VerificationErrorType string
 
 const (
-	ErrInvalidInput        VerificationErrorType = "INVALID_INPUT"
-	ErrInvalidQRCode       VerificationErrorType = "INVALID_QR_CODE"
-	ErrParticipantNotFound VerificationErrorType = "PARTICIPANT_NOT_FOUND"
-	ErrActionNotFound      VerificationErrorType = "ACTION_NOT_FOUND"
-	ErrActionInactive      VerificationErrorType = "ACTION_INACTIVE"
-	ErrVerifierNotFound    VerificationErrorType = "VERIFIER_NOT_FOUND"
-	ErrPaymentRequired     VerificationErrorType = "PAYMENT_REQUIRED"
-	ErrAlreadyVerified     VerificationErrorType = "ALREADY_VERIFIED"
-	ErrEventNotFound       VerificationErrorType = "EVENT_NOT_FOUND"
-	ErrEventMismatch       VerificationErrorType = "EVENT_MISMATCH"
-	ErrEventNotStarted     VerificationErrorType = "EVENT_NOT_STARTED"
-	ErrDatabaseError       VerificationErrorType = "DATABASE_ERROR"
-	ErrPermissionDenied    VerificationErrorType = "PERMISSION_DENIED"
-	ErrNotImplemented      VerificationErrorType = "NOT_IMPLEMENTED"
+	ErrInvalidInput          VerificationErrorType = "INVALID_INPUT"
+	ErrInvalidQRCode         VerificationErrorType = "INVALID_QR_CODE"
+	ErrCredentialRevoked     VerificationErrorType = "CREDENTIAL_REVOKED"
+	ErrParticipantNotFound   VerificationErrorType = "PARTICIPANT_NOT_FOUND"
+	ErrSpeakerNotFound       VerificationErrorType = "SPEAKER_NOT_FOUND"
+	ErrActionNotFound        VerificationErrorType = "ACTION_NOT_FOUND"
+	ErrActionInactive        VerificationErrorType = "ACTION_INACTIVE"
+	ErrActionNotOpen         VerificationErrorType = "ACTION_NOT_OPEN"
+	ErrVerifierNotFound      VerificationErrorType = "VERIFIER_NOT_FOUND"
+	ErrDeviceNotFound        VerificationErrorType = "DEVICE_NOT_FOUND"
+	ErrPaymentRequired       VerificationErrorType = "PAYMENT_REQUIRED"
+	ErrAlreadyVerified       VerificationErrorType = "ALREADY_VERIFIED"
+	ErrSessionFull           VerificationErrorType = "SESSION_FULL"
+	ErrDayFull               VerificationErrorType = "EVENT_DAY_FULL"
+	ErrMealQuotaReached      VerificationErrorType = "MEAL_QUOTA_REACHED"
+	ErrMealMismatch          VerificationErrorType = "MEAL_PREFERENCE_MISMATCH"
+	ErrWaiverNotSigned       VerificationErrorType = "WAIVER_NOT_SIGNED"
+	ErrNotApproved           VerificationErrorType = "REGISTRATION_NOT_APPROVED"
+	ErrRegistrationCancelled VerificationErrorType = "REGISTRATION_CANCELLED"
+	ErrZoneRestricted        VerificationErrorType = "ZONE_RESTRICTED"
+	ErrNotOnShift            VerificationErrorType = "NOT_ON_SHIFT"
+	ErrEventNotFound         VerificationErrorType = "EVENT_NOT_FOUND"
+	ErrEventMismatch         VerificationErrorType = "EVENT_MISMATCH"
+	ErrEventNotStarted       VerificationErrorType = "EVENT_NOT_STARTED"
+	ErrDatabaseError         VerificationErrorType = "DATABASE_ERROR"
+	ErrPermissionDenied      VerificationErrorType = "PERMISSION_DENIED"
+	ErrNotImplemented        VerificationErrorType = "NOT_IMPLEMENTED"
 )
 
 type VerificationError struct {
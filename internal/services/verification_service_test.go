@@ -0,0 +1,466 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+func TestVerifyParticipantAction(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup returns the request to verify
+		setup    func(e *testEnv) VerifyRequest
+		wantCode VerificationErrorType
+	}{
+		{
+			name: "free event",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event()
+				action := e.fx.Action(e.fx.Day(event))
+				participant := e.fx.Participant(event)
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String()}
+			},
+		},
+		{
+			name: "QR image path",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event()
+				action := e.fx.Action(e.fx.Day(event))
+				participant := e.fx.Participant(event)
+				return VerifyRequest{QRCodeData: "/qrcodes/" + participant.ID.String() + ".png", ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String()}
+			},
+		},
+		{
+			name: "missing verifier",
+			setup: func(e *testEnv) VerifyRequest {
+				return VerifyRequest{QRCodeData: "x", ActionCode: "x"}
+			},
+			wantCode: ErrInvalidInput,
+		},
+		{
+			name: "malformed QR code",
+			setup: func(e *testEnv) VerifyRequest {
+				return VerifyRequest{QRCodeData: "not-a-ticket", ActionCode: "x", VerifierID: e.fx.User("staff").ID.String()}
+			},
+			wantCode: ErrInvalidQRCode,
+		},
+		{
+			name: "unknown action",
+			setup: func(e *testEnv) VerifyRequest {
+				participant := e.fx.Participant(e.fx.Event())
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: "NOPE", VerifierID: e.fx.User("staff").ID.String()}
+			},
+			wantCode: ErrActionNotFound,
+		},
+		{
+			// the repository only looks up active actions
+			name: "inactive action",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event()
+				action := e.fx.Action(e.fx.Day(event), func(a *models.EventAction) { a.IsActive = false })
+				participant := e.fx.Participant(event)
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String()}
+			},
+			wantCode: ErrActionNotFound,
+		},
+		{
+			name: "zone restricted action",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event()
+				action := e.fx.Action(e.fx.Day(event), func(a *models.EventAction) { a.Zone = "backstage" })
+				participant := e.fx.Participant(event)
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String()}
+			},
+			wantCode: ErrZoneRestricted,
+		},
+		{
+			name: "action window not open yet",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event()
+				opens := time.Now().Add(time.Hour)
+				action := e.fx.Action(e.fx.Day(event), func(a *models.EventAction) { a.StartsAt = &opens })
+				participant := e.fx.Participant(event)
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String()}
+			},
+			wantCode: ErrActionNotOpen,
+		},
+		{
+			name: "action window closed",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event()
+				closed := time.Now().Add(-time.Minute)
+				action := e.fx.Action(e.fx.Day(event), func(a *models.EventAction) { a.EndsAt = &closed })
+				participant := e.fx.Participant(event)
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String()}
+			},
+			wantCode: ErrActionNotOpen,
+		},
+		{
+			name: "within action window",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event()
+				opens, closes := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+				action := e.fx.Action(e.fx.Day(event), func(a *models.EventAction) { a.StartsAt, a.EndsAt = &opens, &closes })
+				participant := e.fx.Participant(event)
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String()}
+			},
+		},
+		{
+			name: "event not allowed",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event()
+				action := e.fx.Action(e.fx.Day(event))
+				participant := e.fx.Participant(event)
+				other := e.fx.Event()
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String(), EventIDs: []string{other.ID.String()}}
+			},
+			wantCode: ErrPermissionDenied,
+		},
+		{
+			name: "unknown verifier",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event()
+				action := e.fx.Action(e.fx.Day(event))
+				participant := e.fx.Participant(event)
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: event.ID.String()}
+			},
+			wantCode: ErrVerifierNotFound,
+		},
+		{
+			name: "unpaid participant of paid event",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event(func(ev *models.Event) { ev.TicketPrice = 50000 })
+				action := e.fx.Action(e.fx.Day(event))
+				participant := e.fx.Participant(event)
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String()}
+			},
+			wantCode: ErrPaymentRequired,
+		},
+		{
+			name: "paid participant of paid event",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event(func(ev *models.Event) { ev.TicketPrice = 50000 })
+				action := e.fx.Action(e.fx.Day(event))
+				participant := e.fx.Participant(event, e.fx.Paid)
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String()}
+			},
+		},
+		{
+			name: "complimentary participant of paid event",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event(func(ev *models.Event) { ev.TicketPrice = 50000 })
+				action := e.fx.Action(e.fx.Day(event))
+				participant := e.fx.Participant(event, func(p *models.Participant) { p.Tags = []string{models.TagComplimentary} })
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String()}
+			},
+		},
+		{
+			name: "registration pending approval",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event()
+				action := e.fx.Action(e.fx.Day(event))
+				participant := e.fx.Participant(event, func(p *models.Participant) { p.ApprovalStatus = ApprovalPending })
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String()}
+			},
+			wantCode: ErrNotApproved,
+		},
+		{
+			name: "already verified",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event()
+				action := e.fx.Action(e.fx.Day(event))
+				participant := e.fx.Participant(event)
+				verifier := e.fx.User("staff")
+				e.fx.Verification(participant, action, verifier)
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: verifier.ID.String()}
+			},
+			wantCode: ErrAlreadyVerified,
+		},
+		{
+			name: "action of another event",
+			setup: func(e *testEnv) VerifyRequest {
+				action := e.fx.Action(e.fx.Day(e.fx.Event()))
+				participant := e.fx.Participant(e.fx.Event())
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String()}
+			},
+			wantCode: ErrEventMismatch,
+		},
+		{
+			name: "superseded credential",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event()
+				action := e.fx.Action(e.fx.Day(event))
+				participant := e.fx.Participant(event, func(p *models.Participant) { p.CredentialVersion = 1 })
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String()}
+			},
+			wantCode: ErrCredentialRevoked,
+		},
+		{
+			name: "cancelled registration",
+			setup: func(e *testEnv) VerifyRequest {
+				event := e.fx.Event()
+				action := e.fx.Action(e.fx.Day(event))
+				participant := e.fx.Participant(event, func(p *models.Participant) {
+					cancelled := time.Now()
+					p.CancelledAt = &cancelled
+				})
+				return VerifyRequest{QRCodeData: participant.ID.String(), ActionCode: action.Code, VerifierID: e.fx.User("staff").ID.String()}
+			},
+			wantCode: ErrRegistrationCancelled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t)
+			req := tt.setup(e)
+
+			result, err := e.verificationService().VerifyParticipantAction(req)
+			if tt.wantCode != "" {
+				if code := GetVerificationErrorCode(err); code != tt.wantCode {
+					t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Success || result.ActionLog == nil {
+				t.Fatalf("result = %+v, want a successful verification", result)
+			}
+
+			logs, err := e.repo.ActionRepo.GetActionLogsByParticipant(result.Participant.ID.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(logs) != 1 {
+				t.Fatalf("got %d action logs, want 1", len(logs))
+			}
+		})
+	}
+}
+
+func TestGetEventVerifications(t *testing.T) {
+	e := newTestEnv(t)
+	event := e.fx.Event()
+	action := e.fx.Action(e.fx.Day(event))
+	verifier := e.fx.User("staff")
+	for i := 0; i < 5; i++ {
+		e.fx.Verification(e.fx.Participant(event), action, verifier)
+	}
+	e.fx.Verification(e.fx.Participant(e.fx.Event()), e.fx.Action(e.fx.Day(e.fx.Event())), verifier)
+
+	svc := e.verificationService()
+
+	tests := []struct {
+		name      string
+		filters   *VerificationFilters
+		wantLen   int
+		wantTotal int64
+		wantPages int
+	}{
+		{name: "defaults", filters: nil, wantLen: 5, wantTotal: 5, wantPages: 1},
+		{name: "first page", filters: &VerificationFilters{Page: 1, PageSize: 2}, wantLen: 2, wantTotal: 5, wantPages: 3},
+		{name: "last page", filters: &VerificationFilters{Page: 3, PageSize: 2}, wantLen: 1, wantTotal: 5, wantPages: 3},
+		{name: "past the end", filters: &VerificationFilters{Page: 4, PageSize: 2}, wantLen: 0, wantTotal: 5, wantPages: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := svc.GetEventVerifications(event.ID.String(), tt.filters)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list.Verifications) != tt.wantLen || list.TotalCount != tt.wantTotal || list.TotalPages != tt.wantPages {
+				t.Fatalf("got %d rows, total %d, %d pages; want %d, %d, %d",
+					len(list.Verifications), list.TotalCount, list.TotalPages, tt.wantLen, tt.wantTotal, tt.wantPages)
+			}
+		})
+	}
+
+	t.Run("cursor walks every row once", func(t *testing.T) {
+		seen := map[string]bool{}
+		cursor := ""
+		for {
+			list, err := svc.GetEventVerificationsByCursor(event.ID.String(), cursor, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, log := range list.Verifications {
+				if seen[log.ID.String()] {
+					t.Fatalf("row %s returned twice", log.ID)
+				}
+				seen[log.ID.String()] = true
+			}
+			if list.NextCursor == "" {
+				break
+			}
+			cursor = list.NextCursor
+		}
+		if len(seen) != 5 {
+			t.Fatalf("walked %d rows, want 5", len(seen))
+		}
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		_, err := svc.GetEventVerifications(verifier.ID.String(), nil)
+		if code := GetVerificationErrorCode(err); code != ErrEventNotFound {
+			t.Fatalf("error code = %q, want %q", code, ErrEventNotFound)
+		}
+	})
+}
+
+func TestCanVerifyParticipant(t *testing.T) {
+	e := newTestEnv(t)
+	event := e.fx.Event()
+	action := e.fx.Action(e.fx.Day(event))
+	verifier := e.fx.User("staff")
+	fresh := e.fx.Participant(event)
+	verified := e.fx.Participant(event)
+	e.fx.Verification(verified, action, verifier)
+	stranger := e.fx.Participant(e.fx.Event())
+
+	svc := e.verificationService()
+
+	tests := []struct {
+		name        string
+		participant *models.Participant
+		wantCode    VerificationErrorType
+	}{
+		{name: "not verified yet", participant: fresh},
+		{name: "already verified", participant: verified, wantCode: ErrAlreadyVerified},
+		{name: "other event", participant: stranger, wantCode: ErrEventMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := svc.CanVerifyParticipant(tt.participant.ID.String(), action.ID.String())
+			if code := GetVerificationErrorCode(err); code != tt.wantCode {
+				t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantCode)
+			}
+			if ok != (tt.wantCode == "") {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantCode == "")
+			}
+		})
+	}
+}
+
+func TestVerifyWithAPIKey(t *testing.T) {
+	e := newTestEnv(t)
+	event := e.fx.Event()
+	action := e.fx.Action(e.fx.Day(event))
+	participant := e.fx.Participant(event)
+	keyID := uuid.New()
+
+	result, err := e.verificationService().VerifyParticipantAction(VerifyRequest{
+		QRCodeData: participant.ID.String(),
+		ActionCode: action.Code,
+		APIKeyID:   keyID.String(),
+		EventIDs:   []string{event.ID.String()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log := result.ActionLog; log.VerifiedBy != nil || log.APIKeyID == nil || *log.APIKeyID != keyID {
+		t.Fatalf("verified by %v, API key %v; want no user and key %s", log.VerifiedBy, log.APIKeyID, keyID)
+	}
+}
+
+func TestDayCapacity(t *testing.T) {
+	e := newTestEnv(t)
+	event := e.fx.Event()
+	capacity := 1
+	day := e.fx.Day(event, func(d *models.EventDay) { d.Capacity = &capacity })
+	entrance, lunch := e.fx.Action(day), e.fx.Action(day)
+	first, second := e.fx.Participant(event), e.fx.Participant(event)
+
+	verify := func(participant *models.Participant, action *models.EventAction) error {
+		_, err := e.verificationService().VerifyParticipantAction(VerifyRequest{
+			QRCodeData: participant.ID.String(),
+			ActionCode: action.Code,
+			APIKeyID:   uuid.NewString(),
+			EventIDs:   []string{event.ID.String()},
+		})
+		return err
+	}
+
+	if err := verify(first, entrance); err != nil {
+		t.Fatal(err)
+	}
+	// Participants admitted on the day can still be scanned at its actions
+	if err := verify(first, lunch); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(second, entrance); GetVerificationErrorCode(err) != ErrDayFull {
+		t.Fatalf("full day: error = %v, want %s", err, ErrDayFull)
+	}
+
+	attendance, err := NewEventService(e.repo, e.cfg).GetDayAttendance(event.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attendance) != 1 || attendance[0].Attendees != 1 || !attendance[0].Full {
+		t.Fatalf("attendance = %+v", attendance)
+	}
+
+	_, err = NewParticipantService(e.repo, e.cfg).RegisterParticipant(RegisterParticipantRequest{
+		EventID: event.ID.String(),
+		Name:    "Ani",
+		Email:   "ani@example.com",
+	})
+	if !errors.Is(err, repositories.ErrEventDayFull) {
+		t.Fatalf("registration: error = %v, want %v", err, repositories.ErrEventDayFull)
+	}
+}
+
+func TestGetParticipantStatus(t *testing.T) {
+	e := newTestEnv(t)
+	event := e.fx.Event()
+	day1, day2 := e.fx.Day(event), e.fx.Day(event)
+	entry := e.fx.Action(day1)
+	lunch := e.fx.Action(day1, func(a *models.EventAction) { a.Position = 1 })
+	closing := e.fx.Action(day2)
+	e.fx.Action(day2, func(a *models.EventAction) { a.IsActive = false })
+	verifier := e.fx.User("staff")
+	participant := e.fx.Participant(event)
+	e.fx.Verification(participant, entry, verifier)
+	e.fx.Verification(participant, closing, verifier)
+
+	svc := e.verificationService()
+	status, err := svc.GetParticipantStatus(participant.ID.String())
+	if err != nil {
+		t.Fatalf("GetParticipantStatus: %v", err)
+	}
+	if status.Total != 3 || status.Verified != 2 || len(status.Actions) != 3 {
+		t.Fatalf("status: %d of %d verified over %d actions, want 2 of 3", status.Verified, status.Total, len(status.Actions))
+	}
+	want := []struct {
+		id       uuid.UUID
+		verified bool
+	}{{entry.ID, true}, {lunch.ID, false}, {closing.ID, true}}
+	for i, w := range want {
+		got := status.Actions[i]
+		if got.ActionID != w.id || got.Verified != w.verified {
+			t.Fatalf("action %d = %s verified %v, want %s verified %v", i, got.ActionID, got.Verified, w.id, w.verified)
+		}
+		if got.Verified && (got.VerifiedAt == nil || got.VerifiedBy == nil || *got.VerifiedBy.UserID != verifier.ID || got.VerifiedBy.Name != verifier.Email) {
+			t.Fatalf("action %d verification = %+v, %+v", i, got.VerifiedAt, got.VerifiedBy)
+		}
+		if !got.Verified && (got.VerifiedAt != nil || got.VerifiedBy != nil) {
+			t.Fatalf("unverified action %d has a verification: %+v", i, got)
+		}
+	}
+	if status.Actions[2].DayLabel != day2.Label {
+		t.Fatalf("day label = %q, want %q", status.Actions[2].DayLabel, day2.Label)
+	}
+
+	if _, err := svc.GetParticipantStatus(uuid.NewString()); GetVerificationErrorCode(err) != ErrParticipantNotFound {
+		t.Fatalf("unknown participant: error = %v, want %s", err, ErrParticipantNotFound)
+	}
+}
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Waitlist modes of an event, see models.Event
+const (
+	WaitlistOff      = "off"
+	WaitlistManual   = "manual"
+	WaitlistFIFO     = "fifo"
+	WaitlistPriority = "priority"
+)
+
+// Statuses of a waitlist entry
+const (
+	WaitlistWaiting  = "waiting"
+	WaitlistPromoted = "promoted"
+)
+
+var (
+	ErrUnknownWaitlistEntry = errors.New("waitlist entry not found")
+	// ErrWaitlistOff is returned when joining the waitlist of an event
+	// without one
+	ErrWaitlistOff = errors.New("event has no waitlist")
+	// ErrWaitlistEntryPromoted is returned for entries that are no longer
+	// waiting
+	ErrWaitlistEntryPromoted = errors.New("waitlist entry was already promoted")
+)
+
+// WaitlistPosition is a waitlist entry with its place in line, from 1
+type WaitlistPosition struct {
+	models.WaitlistEntry
+	Position int `json:"position"`
+}
+
+// Waitlist is the line of people waiting for tickets of an event
+type Waitlist struct {
+	Mode string `json:"mode"`
+	// nil when the event has no ticket quota
+	TicketsLeft *int               `json:"tickets_left"`
+	Entries     []WaitlistPosition `json:"entries"`
+}
+
+func validateWaitlist(mode string) error {
+	switch mode {
+	case "", WaitlistOff, WaitlistManual, WaitlistFIFO, WaitlistPriority:
+		return nil
+	}
+	return errors.New("waitlist must be off, manual, fifo or priority")
+}
+
+// autoPromotes tells whether an event fills freed tickets from its waitlist
+func autoPromotes(event *models.Event) bool {
+	return event.Waitlist == WaitlistFIFO || event.Waitlist == WaitlistPriority
+}
+
+// JoinWaitlist puts someone who could not register for a full event on its
+// waitlist, with the details of their registration. Joining again with the
+// same email returns the place already held.
+func (s *ParticipantService) JoinWaitlist(req RegisterParticipantRequest) (*WaitlistPosition, error) {
+	event, err := s.repo.EventRepo.GetEventByID(req.EventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+	if event.Waitlist == "" || event.Waitlist == WaitlistOff {
+		return nil, ErrWaitlistOff
+	}
+	if event.Status != EventPublished {
+		return nil, ErrEventNotPublished
+	}
+	if err := checkRegistrationWindow(event, time.Now()); err != nil {
+		return nil, err
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if existing, _ := s.repo.ParticipantRepo.GetParticipantByEmailAndEvent(email, req.EventID); existing != nil {
+		return nil, errors.New("email already registered for this event")
+	}
+	if entry, err := s.repo.WaitlistRepo.FindWaitingEntry(req.EventID, email); err == nil {
+		return s.waitlistPosition(event, entry)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	mealPreference, err := NormalizeMealPreference(req.MealPreference)
+	if err != nil {
+		return nil, err
+	}
+	ticketTypes, err := s.repo.TicketTypeRepo.ListTicketTypesByEvent(req.EventID)
+	if err != nil {
+		return nil, err
+	}
+	ticketType, err := groupTicketType(ticketTypes, req.TicketTypeID)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := validateAnswers(event.FormFields, req.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.WaitlistEntry{
+		EventID:        event.ID,
+		Name:           strings.TrimSpace(req.Name),
+		Email:          email,
+		Phone:          strings.TrimSpace(req.Phone),
+		Division:       strings.TrimSpace(req.Division),
+		Address:        strings.TrimSpace(req.Address),
+		MealPreference: mealPreference,
+		Answers:        answers,
+		Status:         WaitlistWaiting,
+	}
+	if ticketType != nil {
+		entry.TicketTypeID = &ticketType.ID
+	}
+	if err := s.repo.WaitlistRepo.CreateWaitlistEntry(entry); err != nil {
+		return nil, err
+	}
+	return s.waitlistPosition(event, entry)
+}
+
+func (s *ParticipantService) waitlistPosition(event *models.Event, entry *models.WaitlistEntry) (*WaitlistPosition, error) {
+	entries, err := s.repo.WaitlistRepo.ListWaitingEntries(event.ID.String(), event.Waitlist == WaitlistPriority)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].ID == entry.ID {
+			return &WaitlistPosition{WaitlistEntry: entries[i], Position: i + 1}, nil
+		}
+	}
+	return &WaitlistPosition{WaitlistEntry: *entry}, nil
+}
+
+// GetWaitlist returns the people waiting for tickets of an event, in the
+// order they are promoted: by priority on events promoting by priority,
+// oldest first otherwise
+func (s *ParticipantService) GetWaitlist(eventID string) (*Waitlist, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+	entries, err := s.repo.WaitlistRepo.ListWaitingEntries(eventID, event.Waitlist == WaitlistPriority)
+	if err != nil {
+		return nil, err
+	}
+
+	waitlist := &Waitlist{Mode: event.Waitlist, Entries: make([]WaitlistPosition, len(entries))}
+	for i := range entries {
+		waitlist.Entries[i] = WaitlistPosition{WaitlistEntry: entries[i], Position: i + 1}
+	}
+	if event.TicketQuota != nil {
+		count, err := s.repo.ParticipantRepo.GetParticipantCountByEventID(eventID)
+		if err != nil {
+			return nil, err
+		}
+		left := *event.TicketQuota - int(count)
+		if left < 0 {
+			left = 0
+		}
+		waitlist.TicketsLeft = &left
+	}
+	return waitlist, nil
+}
+
+// SetWaitlistPriority changes the priority of a waiting entry; higher goes
+// first on events promoting by priority
+func (s *ParticipantService) SetWaitlistPriority(entryID string, priority int) (*models.WaitlistEntry, error) {
+	entry, err := s.getWaitingEntry(entryID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.WaitlistRepo.UpdateWaitlistPriority(entryID, priority); err != nil {
+		return nil, err
+	}
+	entry.Priority = priority
+	return entry, nil
+}
+
+// PromoteWaitlistEntry registers a waiting entry as a participant ahead of
+// its turn. The ticket quota and day capacities still apply.
+func (s *ParticipantService) PromoteWaitlistEntry(actorID, entryID string) (*RegisterParticipantResponse, error) {
+	entry, err := s.getWaitingEntry(entryID)
+	if err != nil {
+		return nil, err
+	}
+	event, err := s.repo.EventRepo.GetEventByID(entry.EventID.String())
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+	return s.promote(actorID, event, entry)
+}
+
+// PromoteWaitlist fills the free tickets of an event from its waitlist, in
+// order, when the event promotes automatically. It returns the participants
+// registered.
+func (s *ParticipantService) PromoteWaitlist(eventID string) ([]models.Participant, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+	if !autoPromotes(event) || event.Status != EventPublished {
+		return nil, nil
+	}
+	entries, err := s.repo.WaitlistRepo.ListWaitingEntries(eventID, event.Waitlist == WaitlistPriority)
+	if err != nil {
+		return nil, err
+	}
+
+	var promoted []models.Participant
+	for i := range entries {
+		result, err := s.promote("", event, &entries[i])
+		switch {
+		case errors.Is(err, repositories.ErrTicketQuotaExceeded), errors.Is(err, repositories.ErrEventDayFull):
+			return promoted, nil
+		case errors.Is(err, repositories.ErrVersionConflict):
+			// Promoted by hand meanwhile
+			continue
+		case err != nil:
+			return promoted, err
+		}
+		promoted = append(promoted, *result.Participant)
+	}
+	return promoted, nil
+}
+
+func (s *ParticipantService) getWaitingEntry(entryID string) (*models.WaitlistEntry, error) {
+	entry, err := s.repo.WaitlistRepo.GetWaitlistEntry(entryID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUnknownWaitlistEntry
+	}
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status != WaitlistWaiting {
+		return nil, ErrWaitlistEntryPromoted
+	}
+	return entry, nil
+}
+
+// promote registers the participant of a waitlist entry, if the event has a
+// ticket left for them
+func (s *ParticipantService) promote(actorID string, event *models.Event, entry *models.WaitlistEntry) (*RegisterParticipantResponse, error) {
+	if event.Status != EventPublished {
+		return nil, ErrEventNotPublished
+	}
+	limit, err := s.groupLimit(event, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	var ticketType *models.TicketType
+	if entry.TicketTypeID != nil {
+		if ticketType, err = s.repo.TicketTypeRepo.GetTicketType(entry.TicketTypeID.String()); err != nil {
+			return nil, ErrUnknownTicketType
+		}
+	}
+	participant := &models.Participant{
+		ID:             uuid.New(),
+		EventID:        event.ID,
+		Name:           entry.Name,
+		Email:          entry.Email,
+		Phone:          entry.Phone,
+		Division:       entry.Division,
+		Address:        entry.Address,
+		MealPreference: entry.MealPreference,
+		Answers:        entry.Answers,
+		TicketTypeID:   entry.TicketTypeID,
+		PaymentStatus:  "paid",
+		ApprovalStatus: ApprovalApproved,
+	}
+	if ticketPrice(event, ticketType) > 0 {
+		participant.PaymentStatus = "pending"
+	}
+	if event.RequiresApproval {
+		participant.ApprovalStatus = ApprovalPending
+	}
+	if err := s.repo.WaitlistRepo.PromoteWaitlistEntry(entry, participant, limit); err != nil {
+		return nil, err
+	}
+
+	waivers, err := s.ReleaseQR(participant)
+	if err != nil {
+		// Registered already; the QR code can be generated again
+		logger.Log.WithError(err).WithField("participant_id", participant.ID.String()).Warn("failed to generate QR code of promoted participant")
+	}
+	s.audit.Record(actorID, AuditWaitlistPromoted, AuditEntityParticipant, participant.ID.String(), map[string]interface{}{
+		"waitlist_entry_id": entry.ID.String(),
+		"automatic":         actorID == "",
+	})
+	return &RegisterParticipantResponse{
+		Participant:    participant,
+		TicketType:     ticketType,
+		QRPath:         participant.QRPath,
+		PendingWaivers: waivers,
+	}, nil
+}
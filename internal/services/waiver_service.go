@@ -0,0 +1,223 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+)
+
+var (
+	// ErrWaiverNotFound is returned when the waiver does not exist, is
+	// inactive or belongs to another event than the participant
+	ErrWaiverNotFound = errors.New("waiver not found")
+	// ErrWaiverAlreadySigned is returned when a participant signs a waiver twice
+	ErrWaiverAlreadySigned = errors.New("waiver already signed")
+	// ErrSignatureRequired is returned when a waiver requiring a drawn
+	// signature is signed with a typed name only
+	ErrSignatureRequired = errors.New("this waiver must be signed with a drawn signature")
+)
+
+// WaiverService manages the documents participants accept before their QR
+// code is released, and their signatures
+type WaiverService struct {
+	repo           *repositories.Repository
+	participantSvc *ParticipantService
+	cfg            *config.Config
+}
+
+func NewWaiverService(repo *repositories.Repository, participantSvc *ParticipantService, cfg *config.Config) *WaiverService {
+	return &WaiverService{repo: repo, participantSvc: participantSvc, cfg: cfg}
+}
+
+type CreateWaiverRequest struct {
+	Title                 string
+	Body                  string
+	RequireDrawnSignature bool
+}
+
+type SignWaiverRequest struct {
+	ParticipantID string
+	WaiverID      string
+	TypedName     string
+	// File name of the drawn signature, already saved in SIGNATURE_DIR
+	SignatureFile string
+	IPAddress     string
+	UserAgent     string
+}
+
+// ParticipantWaivers is what a participant still has to sign. QRPath is only
+// set once every active waiver of the event has been signed and the
+// registration is approved.
+type ParticipantWaivers struct {
+	ParticipantID string                   `json:"participant_id"`
+	Pending       []models.Waiver          `json:"pending"`
+	Signed        []models.WaiverSignature `json:"signed"`
+	QRPath        string                   `json:"qr_path,omitempty"`
+}
+
+// SignedWaiver is a row of the signed-waiver report
+type SignedWaiver struct {
+	SignatureID     string    `json:"signature_id"`
+	WaiverID        string    `json:"waiver_id"`
+	WaiverTitle     string    `json:"waiver_title"`
+	ParticipantID   string    `json:"participant_id"`
+	ParticipantName string    `json:"participant_name"`
+	Email           string    `json:"email"`
+	TypedName       string    `json:"typed_name"`
+	DrawnSignature  bool      `json:"drawn_signature"`
+	IPAddress       string    `json:"ip_address"`
+	UserAgent       string    `json:"user_agent"`
+	SignedAt        time.Time `json:"signed_at"`
+}
+
+func (s *WaiverService) CreateWaiver(eventID string, req CreateWaiverRequest) (*models.Waiver, error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, errors.New("event not found")
+	}
+
+	waiver := &models.Waiver{
+		EventID:               event.ID,
+		Title:                 strings.TrimSpace(req.Title),
+		Body:                  req.Body,
+		RequireDrawnSignature: req.RequireDrawnSignature,
+		IsActive:              true,
+	}
+	if err := s.repo.WaiverRepo.CreateWaiver(waiver); err != nil {
+		return nil, fmt.Errorf("failed to create waiver: %w", err)
+	}
+
+	return waiver, nil
+}
+
+func (s *WaiverService) ListWaivers(eventID string) ([]models.Waiver, error) {
+	return s.repo.WaiverRepo.ListWaiversByEvent(eventID)
+}
+
+// DeactivateWaiver stops requiring a waiver. Existing signatures are kept for
+// the report.
+func (s *WaiverService) DeactivateWaiver(eventID, waiverID string) error {
+	waiver, err := s.repo.WaiverRepo.GetWaiverByID(waiverID)
+	if err != nil || waiver.EventID.String() != eventID {
+		return ErrWaiverNotFound
+	}
+	return s.repo.WaiverRepo.SetWaiverActive(waiverID, false)
+}
+
+// ParticipantWaivers returns the signed and pending waivers of a participant
+func (s *WaiverService) ParticipantWaivers(participantID string) (*ParticipantWaivers, error) {
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return nil, errors.New("participant not found")
+	}
+	return s.participantWaivers(participant)
+}
+
+// Sign records a participant accepting a waiver. Signing the last pending
+// waiver releases the participant's QR code.
+func (s *WaiverService) Sign(req SignWaiverRequest) (*ParticipantWaivers, error) {
+	participant, err := s.repo.ParticipantRepo.GetParticipantByID(req.ParticipantID)
+	if err != nil {
+		return nil, errors.New("participant not found")
+	}
+
+	waiver, err := s.repo.WaiverRepo.GetWaiverByID(req.WaiverID)
+	if err != nil || waiver.EventID != participant.EventID || !waiver.IsActive {
+		return nil, ErrWaiverNotFound
+	}
+
+	typedName := strings.TrimSpace(req.TypedName)
+	if waiver.RequireDrawnSignature && req.SignatureFile == "" {
+		return nil, ErrSignatureRequired
+	}
+	if typedName == "" && req.SignatureFile == "" {
+		return nil, errors.New("typed name or drawn signature is required")
+	}
+
+	signatures, err := s.repo.WaiverRepo.ListWaiverSignaturesByParticipant(req.ParticipantID)
+	if err != nil {
+		return nil, err
+	}
+	for _, signature := range signatures {
+		if signature.WaiverID == waiver.ID {
+			return nil, ErrWaiverAlreadySigned
+		}
+	}
+
+	signature := &models.WaiverSignature{
+		WaiverID:      waiver.ID,
+		ParticipantID: participant.ID,
+		EventID:       participant.EventID,
+		TypedName:     typedName,
+		SignatureFile: req.SignatureFile,
+		IPAddress:     req.IPAddress,
+		UserAgent:     req.UserAgent,
+		SignedAt:      time.Now(),
+	}
+	if err := s.repo.WaiverRepo.CreateWaiverSignature(signature); err != nil {
+		return nil, fmt.Errorf("failed to record signature: %w", err)
+	}
+
+	return s.participantWaivers(participant)
+}
+
+// SignedWaivers returns every signature collected for an event
+func (s *WaiverService) SignedWaivers(eventID string) ([]SignedWaiver, error) {
+	signatures, err := s.repo.WaiverRepo.ListWaiverSignaturesByEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := make([]SignedWaiver, 0, len(signatures))
+	for _, signature := range signatures {
+		report = append(report, SignedWaiver{
+			SignatureID:     signature.ID.String(),
+			WaiverID:        signature.WaiverID.String(),
+			WaiverTitle:     signature.Waiver.Title,
+			ParticipantID:   signature.ParticipantID.String(),
+			ParticipantName: signature.Participant.Name,
+			Email:           signature.Participant.Email,
+			TypedName:       signature.TypedName,
+			DrawnSignature:  signature.SignatureFile != "",
+			IPAddress:       signature.IPAddress,
+			UserAgent:       signature.UserAgent,
+			SignedAt:        signature.SignedAt,
+		})
+	}
+	return report, nil
+}
+
+// SignatureImagePath returns the file of a drawn signature of an event
+func (s *WaiverService) SignatureImagePath(eventID, signatureID string) (string, error) {
+	signature, err := s.repo.WaiverRepo.GetWaiverSignatureByID(signatureID)
+	if err != nil || signature.EventID.String() != eventID || signature.SignatureFile == "" {
+		return "", errors.New("signature not found")
+	}
+	return filepath.Join(s.cfg.SignatureDir, filepath.Base(signature.SignatureFile)), nil
+}
+
+// participantWaivers lists the waivers of participant and releases their QR
+// code when nothing holds it back anymore
+func (s *WaiverService) participantWaivers(participant *models.Participant) (*ParticipantWaivers, error) {
+	pending, err := s.participantSvc.ReleaseQR(participant)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := s.repo.WaiverRepo.ListWaiverSignaturesByParticipant(participant.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &ParticipantWaivers{
+		ParticipantID: participant.ID.String(),
+		Pending:       pending,
+		Signed:        signed,
+		QRPath:        participant.QRPath,
+	}, nil
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+)
+
+var (
+	// ErrWidgetNotFound is returned for unknown or inactive events and for
+	// events without a widget key
+	ErrWidgetNotFound = errors.New("registration widget not found")
+	// ErrInvalidWidgetKey is returned when the request carries the wrong key
+	ErrInvalidWidgetKey = errors.New("invalid widget key")
+	// ErrWidgetOriginNotAllowed is returned when the embedding site is not
+	// among the event's widget origins
+	ErrWidgetOriginNotAllowed = errors.New("origin not allowed for this widget")
+)
+
+// WidgetService backs the registration form organizers embed on their own
+// sites. Widget requests are authorized per event by a public key and the
+// event's widget origins rather than by a user login.
+type WidgetService struct {
+	repo           *repositories.Repository
+	participantSvc *ParticipantService
+	cfg            *config.Config
+}
+
+func NewWidgetService(repo *repositories.Repository, participantSvc *ParticipantService, cfg *config.Config) *WidgetService {
+	return &WidgetService{repo: repo, participantSvc: participantSvc, cfg: cfg}
+}
+
+type WidgetAvailability struct {
+	Quota      *int  `json:"quota"` // nil = unlimited
+	Registered int64 `json:"registered"`
+	Remaining  *int  `json:"remaining"` // nil = unlimited
+	SoldOut    bool  `json:"sold_out"`
+}
+
+// eventAvailability counts the registrations of an event against its
+// ticket quota
+func eventAvailability(repo *repositories.Repository, event *models.Event) (*WidgetAvailability, error) {
+	registered, err := repo.ParticipantRepo.GetParticipantCountByEventID(event.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	availability := &WidgetAvailability{
+		Quota:      event.TicketQuota,
+		Registered: registered,
+	}
+	if event.TicketQuota != nil {
+		remaining := *event.TicketQuota - int(registered)
+		if remaining < 0 {
+			remaining = 0
+		}
+		availability.Remaining = &remaining
+		availability.SoldOut = remaining == 0
+	}
+	return availability, nil
+}
+
+type WidgetInfo struct {
+	Title        string             `json:"title"`
+	Slug         string             `json:"slug"`
+	Description  string             `json:"description"`
+	StartsAt     time.Time          `json:"starts_at"`
+	EndsAt       time.Time          `json:"ends_at"`
+	LogoPath     string             `json:"logo_path,omitempty"`
+	TicketPrice  float64            `json:"ticket_price"`
+	Availability WidgetAvailability `json:"availability"`
+	// upcoming|open|closed
+	RegistrationState string `json:"registration_state"`
+	// Choices for the meal preference field
+	MealPreferences []string `json:"meal_preferences"`
+	// Choices for the ticket type field; the event's ticket price applies
+	// when there are none
+	TicketTypes []TicketTypeAvailability `json:"ticket_types,omitempty"`
+	// Custom questions to ask, answered in the registration's answers
+	FormFields []models.FormField `json:"form_fields,omitempty"`
+}
+
+type WidgetPayment struct {
+	Required bool    `json:"required"`
+	Amount   float64 `json:"amount"`
+	Status   string  `json:"status"`
+	// Page the widget redirects to for paid tickets; empty when no payment
+	// page is configured and the organizer collects payment offline
+	HandoffURL string `json:"handoff_url,omitempty"`
+}
+
+type WidgetRegistration struct {
+	ParticipantID string `json:"participant_id"`
+	Name          string `json:"name"`
+	QRPath        string `json:"qr_path"`
+	// pending while the organizer reviews the registration
+	ApprovalStatus string        `json:"approval_status"`
+	Payment        WidgetPayment `json:"payment"`
+	// Waivers to sign before the QR code is released
+	PendingWaivers []models.Waiver `json:"pending_waivers,omitempty"`
+}
+
+// IssueKey creates a new widget key for an event, replacing any previous one,
+// and returns it with the event slug. The key is public: it is published in
+// the organizer's embed code.
+func (s *WidgetService) IssueKey(eventID string) (key, slug string, err error) {
+	event, err := s.repo.EventRepo.GetEventByID(eventID)
+	if err != nil {
+		return "", "", errors.New("event not found")
+	}
+
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", fmt.Errorf("failed to generate widget key: %w", err)
+	}
+	key = "pk_" + hex.EncodeToString(raw)
+
+	if err := s.repo.EventRepo.UpdateWidgetKey(eventID, key); err != nil {
+		return "", "", err
+	}
+	return key, event.Slug, nil
+}
+
+// RevokeKey disables the registration widget of an event
+func (s *WidgetService) RevokeKey(eventID string) error {
+	if _, err := s.repo.EventRepo.GetEventByID(eventID); err != nil {
+		return errors.New("event not found")
+	}
+	return s.repo.EventRepo.UpdateWidgetKey(eventID, "")
+}
+
+// Authorize returns the event of a widget request. origin is the request's
+// Origin header; requests without one do not come from a browser and are
+// only checked by key.
+func (s *WidgetService) Authorize(slug, key, origin string) (*models.Event, error) {
+	event, err := s.repo.EventRepo.GetEventBySlug(slug)
+	if err != nil || !event.IsActive || event.WidgetKey == "" {
+		return nil, ErrWidgetNotFound
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key), []byte(event.WidgetKey)) != 1 {
+		return nil, ErrInvalidWidgetKey
+	}
+
+	if origin != "" && !widgetOriginAllowed(event.WidgetOrigins, origin) {
+		return nil, ErrWidgetOriginNotAllowed
+	}
+
+	return event, nil
+}
+
+// Info returns what the widget shows before registration
+func (s *WidgetService) Info(event *models.Event) (*WidgetInfo, error) {
+	availability, err := eventAvailability(s.repo, event)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketTypes, err := listTicketTypeAvailability(s.repo, event.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &WidgetInfo{
+		Title:           event.Title,
+		Slug:            event.Slug,
+		Description:     event.Description,
+		StartsAt:        event.StartsAt,
+		EndsAt:          event.EndsAt,
+		LogoPath:        event.LogoPath,
+		TicketPrice:     event.TicketPrice,
+		Availability:    *availability,
+		MealPreferences: MealPreferences,
+		TicketTypes:     ticketTypes,
+		FormFields:      event.FormFields,
+
+		RegistrationState: registrationState(event, time.Now()),
+	}, nil
+}
+
+// Register signs a participant up through the widget. Paid registrations
+// come back pending with the payment page to hand off to.
+func (s *WidgetService) Register(event *models.Event, req RegisterParticipantRequest) (*WidgetRegistration, error) {
+	req.EventID = event.ID.String()
+	result, err := s.participantSvc.RegisterParticipant(req)
+	if err != nil {
+		return nil, err
+	}
+
+	participant := result.Participant
+	amount := ticketPrice(event, result.TicketType)
+	registration := &WidgetRegistration{
+		ParticipantID:  participant.ID.String(),
+		Name:           participant.Name,
+		QRPath:         result.QRPath,
+		ApprovalStatus: participant.ApprovalStatus,
+		PendingWaivers: result.PendingWaivers,
+		Payment: WidgetPayment{
+			Required: amount > 0,
+			Amount:   amount,
+			Status:   participant.PaymentStatus,
+		},
+	}
+	if registration.Payment.Required && s.cfg.WidgetPaymentURL != "" {
+		registration.Payment.HandoffURL = strings.NewReplacer(
+			"{participant_id}", url.QueryEscape(participant.ID.String()),
+			"{event_slug}", url.QueryEscape(event.Slug),
+			"{amount}", strconv.FormatFloat(amount, 'f', -1, 64),
+		).Replace(s.cfg.WidgetPaymentURL)
+	}
+
+	return registration, nil
+}
+
+// widgetOriginAllowed reports whether origin is among the comma separated,
+// normalized widget origins of an event
+func widgetOriginAllowed(origins, origin string) bool {
+	origin = strings.ToLower(strings.TrimSpace(origin))
+	for _, allowed := range strings.Split(origins, ",") {
+		if allowed != "" && allowed == origin {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrCaptchaFailed is returned by a CaptchaVerifier for a missing, invalid or
+// expired token
+var ErrCaptchaFailed = errors.New("CAPTCHA verification failed")
+
+// CaptchaVerifier checks the token a CAPTCHA widget gave the client
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+var captchaVerifyURLs = map[string]string{
+	"recaptcha": "https://www.google.com/recaptcha/api/siteverify",
+	"hcaptcha":  "https://api.hcaptcha.com/siteverify",
+}
+
+// NewCaptchaVerifier returns a verifier for a provider (recaptcha or
+// hcaptcha), or nil when the provider is empty
+func NewCaptchaVerifier(provider, secret string, timeout time.Duration) CaptchaVerifier {
+	verifyURL, ok := captchaVerifyURLs[provider]
+	if !ok {
+		return nil
+	}
+	return &SiteVerifyCaptcha{URL: verifyURL, Secret: secret, Client: &http.Client{Timeout: timeout}}
+}
+
+// SiteVerifyCaptcha checks tokens with a siteverify API, which reCAPTCHA and
+// hCaptcha share
+type SiteVerifyCaptcha struct {
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+func (v *SiteVerifyCaptcha) Verify(ctx context.Context, token, remoteIP string) error {
+	if token == "" {
+		return ErrCaptchaFailed
+	}
+
+	form := url.Values{"secret": {v.Secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("CAPTCHA verification request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("CAPTCHA verification returned %s", resp.Status)
+	}
+
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&result); err != nil {
+		return fmt.Errorf("invalid CAPTCHA verification response: %w", err)
+	}
+	if result.Success {
+		return nil
+	}
+	for _, code := range result.ErrorCodes {
+		// A wrong secret is our misconfiguration, not the client's fault
+		if code == "missing-input-secret" || code == "invalid-input-secret" {
+			return fmt.Errorf("CAPTCHA provider rejected the secret: %s", code)
+		}
+	}
+	return ErrCaptchaFailed
+}
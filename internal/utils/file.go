@@ -2,8 +2,13 @@ package utils
 
 import (
 	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
 	"io"
 	"mime/multipart"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,25 +16,68 @@ import (
 	"github.com/google/uuid"
 )
 
+// MaxImageDimension is the largest accepted width or height of an uploaded image
+const MaxImageDimension = 4096
+
+// allowedImageTypes maps the allowed extensions to the content type the
+// file's magic bytes must sniff as
+var allowedImageTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+}
+
+// ValidateImageFile checks the extension against the allow list, sniffs the
+// real content type from the file's magic bytes instead of trusting the
+// client header, and rejects images larger than MaxImageDimension.
 func ValidateImageFile(file *multipart.FileHeader) error {
-	allowedTypes := map[string]bool{
-		"image/jpeg": true,
-		"image/jpg":  true,
-		"image/png":  true,
-		"image/gif":  true,
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	expected, ok := allowedImageTypes[ext]
+	if !ok {
+		return fmt.Errorf("file extension not allowed: %s", ext)
 	}
 
-	contentType := file.Header.Get("Content-Type")
-	if !allowedTypes[contentType] {
-		return fmt.Errorf("file type not allowed: %s", contentType)
+	src, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+
+	contentType := http.DetectContentType(head[:n])
+	if contentType != expected {
+		return fmt.Errorf("file content does not match its extension: %s", contentType)
+	}
+
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	cfg, _, err := image.DecodeConfig(src)
+	if err != nil {
+		return fmt.Errorf("invalid image: %w", err)
+	}
+	if cfg.Width > MaxImageDimension || cfg.Height > MaxImageDimension {
+		return fmt.Errorf("image dimensions %dx%d exceed the %dpx limit", cfg.Width, cfg.Height, MaxImageDimension)
 	}
 
 	return nil
 }
 
+// GenerateUniqueFilename keeps the base name and extension of the client
+// filename, stripped of any directory components, and adds a UUID
 func GenerateUniqueFilename(originalName string) string {
-	ext := filepath.Ext(originalName)
-	filename := strings.TrimSuffix(originalName, ext)
+	originalName = filepath.Base(filepath.Clean("/" + strings.ReplaceAll(originalName, "\\", "/")))
+	if originalName == "/" {
+		originalName = "upload"
+	}
+	ext := strings.ToLower(filepath.Ext(originalName))
+	filename := strings.TrimSuffix(originalName, filepath.Ext(originalName))
 	return fmt.Sprintf("%s_%s%s", filename, uuid.New().String(), ext)
 }
 
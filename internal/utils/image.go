@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImageVariant describes a derived image. Images are scaled down to fit in a
+// Size x Size box, or center-cropped to a Size x Size square when Square is
+// set. Images are never scaled up.
+type ImageVariant struct {
+	Name   string
+	Size   int
+	Square bool
+}
+
+// LogoVariants are generated for every uploaded event logo
+var LogoVariants = []ImageVariant{
+	{Name: "thumb", Size: 64},
+	{Name: "small", Size: 256},
+	{Name: "medium", Size: 512},
+}
+
+// PhotoVariants are square crops generated for every uploaded speaker photo
+var PhotoVariants = []ImageVariant{
+	{Name: "thumb", Size: 96, Square: true},
+	{Name: "medium", Size: 320, Square: true},
+}
+
+// GenerateImageVariants writes each variant next to srcPath as
+// <name>_<variant><ext> and returns the generated filenames keyed by variant
+// name. GIFs are re-encoded as PNG.
+func GenerateImageVariants(srcPath string, variants []ImageVariant) (map[string]string, error) {
+	f, err := os.Open(srcPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	src, format, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	dir := filepath.Dir(srcPath)
+	ext := filepath.Ext(srcPath)
+	base := strings.TrimSuffix(filepath.Base(srcPath), ext)
+	if format != "jpeg" {
+		ext = ".png"
+	}
+
+	result := make(map[string]string, len(variants))
+	for _, v := range variants {
+		img := src
+		if v.Square {
+			img = cropSquare(img)
+		}
+		img = fitWithin(img, v.Size)
+
+		filename := fmt.Sprintf("%s_%s%s", base, v.Name, ext)
+		if err := writeImage(filepath.Join(dir, filename), img, format); err != nil {
+			return nil, err
+		}
+		result[v.Name] = filename
+	}
+
+	return result, nil
+}
+
+func writeImage(path string, img image.Image, format string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create image variant: %w", err)
+	}
+	defer out.Close()
+
+	if format == "jpeg" {
+		err = jpeg.Encode(out, img, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(out, img)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode image variant: %w", err)
+	}
+	return nil
+}
+
+// cropSquare returns the centered square of img
+func cropSquare(img image.Image) image.Image {
+	b := img.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+	rect := image.Rect(x0, y0, x0+side, y0+side)
+
+	if sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			dst.Set(x, y, img.At(x0+x, y0+y))
+		}
+	}
+	return dst
+}
+
+// fitWithin scales img down, keeping its aspect ratio, so that neither side
+// exceeds size. Each destination pixel averages the source pixels it covers.
+func fitWithin(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return img
+	}
+
+	dw, dh := size, h*size/w
+	if h > w {
+		dw, dh = w*size/h, size
+	}
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		sy0 := b.Min.Y + y*h/dh
+		sy1 := b.Min.Y + (y+1)*h/dh
+		for x := 0; x < dw; x++ {
+			sx0 := b.Min.X + x*w/dw
+			sx1 := b.Min.X + (x+1)*w/dw
+
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					bl += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			if n == 0 {
+				continue
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(bl / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -14,16 +14,29 @@ func GenerateQRCodeImage(content, dirPath string) (string, error) {
 		return "", fmt.Errorf("failed to create QR directory: %w", err)
 	}
 
-	filename := fmt.Sprintf("%s.png", uuid.New().String())
-	fullPath := filepath.Join(dirPath, filename)
-
-	if err := qrcode.WriteFile(content, qrcode.Medium, 256, fullPath); err != nil {
-		return "", fmt.Errorf("failed to generate QR code: %w", err)
+	filename := NewQRCodeFilename()
+	if err := WriteQRCodeImage(content, dirPath, filename); err != nil {
+		return "", err
 	}
 
 	return filename, nil
 }
 
+// NewQRCodeFilename returns a random file name for a QR code image, letting
+// callers record the path before the image is written
+func NewQRCodeFilename() string {
+	return fmt.Sprintf("%s.png", uuid.New().String())
+}
+
+// WriteQRCodeImage renders content as a QR code into dirPath/filename. The
+// directory must already exist.
+func WriteQRCodeImage(content, dirPath, filename string) error {
+	if err := qrcode.WriteFile(content, qrcode.Medium, 256, filepath.Join(dirPath, filename)); err != nil {
+		return fmt.Errorf("failed to generate QR code: %w", err)
+	}
+	return nil
+}
+
 func ExtractUUIDFromQRPath(qrPath string) (string, error) {
 	filename := filepath.Base(qrPath)
 	uuidStr := filepath.Ext(filename)
@@ -15,6 +15,9 @@ type Meta struct {
 	PageSize  int   `json:"page_size,omitempty"`
 	Total     int64 `json:"total,omitempty"`
 	TotalPage int   `json:"total_page,omitempty"`
+
+	// Set only in cursor pagination mode; empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 func Success(c *fiber.Ctx, data interface{}, message string, statusCode ...int) error {
@@ -43,6 +46,23 @@ func SuccessWithMeta(c *fiber.Ctx, data interface{}, meta *Meta, message string)
 	return c.Status(fiber.StatusOK).JSON(resp)
 }
 
+// ErrorWithData returns an error response that also carries a payload, e.g.
+// the latest state of a record on a version conflict
+func ErrorWithData(c *fiber.Ctx, message string, data interface{}, statusCode ...int) error {
+	code := fiber.StatusBadRequest
+	if len(statusCode) > 0 {
+		code = statusCode[0]
+	}
+
+	resp := Response{
+		Success: false,
+		Error:   message,
+		Data:    data,
+	}
+
+	return c.Status(code).JSON(resp)
+}
+
 func Error(c *fiber.Ctx, message string, statusCode ...int) error {
 	code := fiber.StatusBadRequest
 	if len(statusCode) > 0 {
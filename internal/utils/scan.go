@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"strings"
+	"time"
+)
+
+// ErrInfected is returned by a Scanner when the content contains malware
+var ErrInfected = errors.New("file is infected")
+
+// Scanner inspects uploaded content for malware before it is stored
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) error
+}
+
+// NewScanner returns a ClamAV scanner for the given clamd TCP address, or a
+// scanner that accepts everything when addr is empty
+func NewScanner(addr string, timeout time.Duration) Scanner {
+	if addr == "" {
+		return noopScanner{}
+	}
+	return &ClamAVScanner{Addr: addr, Timeout: timeout}
+}
+
+type noopScanner struct{}
+
+func (noopScanner) Scan(context.Context, io.Reader) error { return nil }
+
+// ClamAVScanner streams content to clamd using the INSTREAM command
+type ClamAVScanner struct {
+	Addr    string
+	Timeout time.Duration
+}
+
+const clamChunkSize = 64 * 1024
+
+func (s *ClamAVScanner) Scan(ctx context.Context, r io.Reader) error {
+	dialer := net.Dialer{Timeout: s.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(s.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return fmt.Errorf("failed to send scan command: %w", err)
+	}
+
+	buf := make([]byte, clamChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return fmt.Errorf("failed to stream file to clamd: %w", err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return fmt.Errorf("failed to stream file to clamd: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("failed to read file: %w", readErr)
+		}
+	}
+
+	// A zero-length chunk terminates the stream
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return fmt.Errorf("failed to stream file to clamd: %w", err)
+	}
+
+	reply, err := io.ReadAll(conn)
+	if err != nil {
+		return fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	result := strings.TrimSpace(string(bytes.TrimRight(reply, "\x00")))
+
+	switch {
+	case strings.HasSuffix(result, "OK"):
+		return nil
+	case strings.HasSuffix(result, "FOUND"):
+		return fmt.Errorf("%w: %s", ErrInfected, strings.TrimSuffix(strings.TrimPrefix(result, "stream: "), " FOUND"))
+	default:
+		return fmt.Errorf("unexpected clamd reply: %s", result)
+	}
+}
+
+// ScanUploadedFile runs scanner over an uploaded multipart file
+func ScanUploadedFile(ctx context.Context, scanner Scanner, file *multipart.FileHeader) error {
+	src, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer src.Close()
+
+	return scanner.Scan(ctx, src)
+}
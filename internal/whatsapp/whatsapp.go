@@ -0,0 +1,168 @@
+// Package whatsapp sends messages to participants through the WhatsApp Cloud
+// API.
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Message is a text to one phone number, sent as the caption of Image when
+// there is one
+type Message struct {
+	// International number, digits only; see PhoneNumber
+	To   string
+	Text string
+	// PNG image, optional
+	Image     []byte
+	ImageName string
+}
+
+// Sender delivers WhatsApp messages
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewSender returns a Cloud API sender for WHATSAPP_API_URL, or a sender that
+// only logs messages when no URL is configured
+func NewSender(cfg *config.Config) Sender {
+	if cfg.WhatsAppAPIURL == "" {
+		return logSender{}
+	}
+	return &CloudSender{
+		URL:    strings.TrimRight(cfg.WhatsAppAPIURL, "/"),
+		Token:  cfg.WhatsAppToken,
+		Client: &http.Client{Timeout: cfg.WhatsAppTimeout},
+	}
+}
+
+// PhoneNumber turns a phone number as participants enter it into the
+// international digits the API expects, replacing the leading 0 of local
+// numbers with countryCode. It returns "" when no digits are left.
+func PhoneNumber(phone, countryCode string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+	switch {
+	case strings.HasPrefix(digits, "00"):
+		return digits[2:]
+	case strings.HasPrefix(digits, "0") && !strings.HasPrefix(strings.TrimSpace(phone), "+"):
+		return countryCode + digits[1:]
+	}
+	return digits
+}
+
+type logSender struct{}
+
+func (logSender) Send(_ context.Context, msg Message) error {
+	logger.Log.WithFields(logrus.Fields{
+		"to":    msg.To,
+		"image": msg.ImageName,
+	}).Info("WHATSAPP_API_URL not set, WhatsApp message not sent")
+	return nil
+}
+
+// CloudSender sends through the WhatsApp Cloud API. Images are uploaded as
+// media first and then sent with the text as caption.
+type CloudSender struct {
+	// API URL up to the sender's phone number ID
+	URL    string
+	Token  string
+	Client *http.Client
+}
+
+func (s *CloudSender) Send(ctx context.Context, msg Message) error {
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                msg.To,
+	}
+	if len(msg.Image) > 0 {
+		mediaID, err := s.upload(ctx, msg)
+		if err != nil {
+			return err
+		}
+		payload["type"] = "image"
+		payload["image"] = map[string]string{"id": mediaID, "caption": msg.Text}
+	} else {
+		payload["type"] = "text"
+		payload["text"] = map[string]string{"body": msg.Text}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return s.post(ctx, "/messages", "application/json", bytes.NewReader(body), nil)
+}
+
+// upload stores the image of msg as WhatsApp media and returns its ID
+func (s *CloudSender) upload(ctx context.Context, msg Message) (string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("messaging_product", "whatsapp")
+	form.WriteField("type", "image/png")
+	part, err := form.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {fmt.Sprintf(`form-data; name="file"; filename=%q`, msg.ImageName)},
+		"Content-Type":        {"image/png"},
+	})
+	if err != nil {
+		return "", err
+	}
+	part.Write(msg.Image)
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	var media struct {
+		ID string `json:"id"`
+	}
+	if err := s.post(ctx, "/media", form.FormDataContentType(), &body, &media); err != nil {
+		return "", err
+	}
+	if media.ID == "" {
+		return "", fmt.Errorf("WhatsApp media upload returned no ID")
+	}
+	return media.ID, nil
+}
+
+func (s *CloudSender) post(ctx context.Context, path, contentType string, body io.Reader, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+s.Token)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("WhatsApp request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("WhatsApp API returned %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(out); err != nil {
+		return fmt.Errorf("invalid WhatsApp API response: %w", err)
+	}
+	return nil
+}
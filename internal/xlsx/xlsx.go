@@ -0,0 +1,521 @@
+// Package xlsx reads and writes the subset of Office Open XML spreadsheets
+// (.xlsx) needed for participant imports and exports: the cell text of
+// worksheets, and plain text sheets with a styled header row.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of .xlsx files
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// ErrInvalidWorkbook is returned for files that are not .xlsx workbooks
+var ErrInvalidWorkbook = errors.New("invalid xlsx workbook")
+
+// maxSheetName is the longest sheet name spreadsheet applications accept
+const maxSheetName = 31
+
+// Writer writes a workbook sheet by sheet, row by row. Rows go straight to
+// the output; only the sheet names are kept until Close.
+type Writer struct {
+	zw     *zip.Writer
+	sheet  io.Writer
+	sheets []string
+	rows   int
+}
+
+func NewWriter(out io.Writer) *Writer {
+	return &Writer{zw: zip.NewWriter(out)}
+}
+
+// AddSheet starts a new sheet whose first row is header, in bold and frozen
+// while scrolling. Names are cut to 31 characters, characters sheet names
+// cannot hold are replaced and repeated names are numbered.
+func (w *Writer) AddSheet(name string, header []string) error {
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+
+	name = w.sheetName(name)
+	w.sheets = append(w.sheets, name)
+	sheet, err := w.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)))
+	if err != nil {
+		return err
+	}
+	w.sheet, w.rows = sheet, 0
+
+	if _, err := io.WriteString(sheet, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`+
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`+
+		`<sheetData>`); err != nil {
+		return err
+	}
+	return w.writeRow(header, 1)
+}
+
+// WriteRow appends a row of text cells to the current sheet
+func (w *Writer) WriteRow(cells []string) error {
+	if w.sheet == nil {
+		return errors.New("xlsx: no sheet added")
+	}
+	return w.writeRow(cells, 0)
+}
+
+func (w *Writer) writeRow(cells []string, style int) error {
+	w.rows++
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, w.rows)
+	for i, cell := range cells {
+		if cell == "" {
+			continue
+		}
+		fmt.Fprintf(&b, `<c r="%s%d" t="inlineStr"`, columnName(i), w.rows)
+		if style != 0 {
+			fmt.Fprintf(&b, ` s="%d"`, style)
+		}
+		b.WriteString(`><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(&b, []byte(cell)); err != nil {
+			return err
+		}
+		b.WriteString(`</t></is></c>`)
+	}
+	b.WriteString(`</row>`)
+	_, err := io.WriteString(w.sheet, b.String())
+	return err
+}
+
+func (w *Writer) endSheet() error {
+	if w.sheet == nil {
+		return nil
+	}
+	_, err := io.WriteString(w.sheet, `</sheetData></worksheet>`)
+	w.sheet = nil
+	return err
+}
+
+func (w *Writer) sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", len(w.sheets)+1)
+	}
+
+	taken := func(name string) bool {
+		for _, sheet := range w.sheets {
+			if strings.EqualFold(sheet, name) {
+				return true
+			}
+		}
+		return false
+	}
+	base := truncate(name, maxSheetName)
+	name = base
+	for n := 2; taken(name); n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		name = truncate(base, maxSheetName-len(suffix)) + suffix
+	}
+	return name
+}
+
+// Close writes the parts describing the workbook. The output is not usable
+// until Close returns.
+func (w *Writer) Close() error {
+	if len(w.sheets) == 0 {
+		return errors.New("xlsx: a workbook needs at least one sheet")
+	}
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+
+	var types, workbook, rels strings.Builder
+	types.WriteString(xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	workbook.WriteString(xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	rels.WriteString(xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`)
+	for i, name := range w.sheets {
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		workbook.WriteString(`<sheet name="`)
+		xml.EscapeText(&workbook, []byte(name))
+		fmt.Fprintf(&workbook, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	types.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	rels.WriteString(`</Relationships>`)
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", types.String()},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+		{"xl/styles.xml", styles},
+	}
+	for _, part := range parts {
+		f, err := w.zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return err
+		}
+	}
+	return w.zw.Close()
+}
+
+// styles holds the default cell format and, at index 1, the header format:
+// bold on a light grey fill with a bottom border
+const styles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="3"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill>` +
+	`<fill><patternFill patternType="solid"><fgColor rgb="FFD9D9D9"/><bgColor indexed="64"/></patternFill></fill></fills>` +
+	`<borders count="2"><border><left/><right/><top/><bottom/><diagonal/></border>` +
+	`<border><left/><right/><top/><bottom style="thin"><color auto="1"/></bottom><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="2" borderId="1" xfId="0" applyFont="1" applyFill="1" applyBorder="1"/></cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
+
+// columnName returns the letters of the zero-based column i, e.g. 27 is AB
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// columnIndex returns the zero-based column of a cell reference such as
+// "AB12", or -1 when ref has no column letters
+func columnIndex(ref string) int {
+	i := 0
+	n := 0
+	for ; n < len(ref) && ref[n] >= 'A' && ref[n] <= 'Z'; n++ {
+		i = i*26 + int(ref[n]-'A') + 1
+	}
+	return i - 1
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) > max {
+		return strings.TrimSpace(string(runes[:max]))
+	}
+	return s
+}
+
+// Sheet is a worksheet read from a workbook
+type Sheet struct {
+	Name string
+	Rows [][]string
+}
+
+// ReadRows returns the cell text of the first worksheet of a workbook, row by
+// row. Empty rows are kept so row numbers match the spreadsheet, and cells
+// hold their stored text: numbers as written, formulas as their last value.
+func ReadRows(r io.ReaderAt, size int64) ([][]string, error) {
+	rows, err := OpenRows(r, size)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return rows.ReadAll()
+}
+
+// OpenRows opens the first worksheet of a workbook to read it row by row, as
+// ReadRows does, without holding every row in memory
+func OpenRows(r io.ReaderAt, size int64) (*RowReader, error) {
+	book, err := openWorkbook(r, size)
+	if err != nil {
+		return nil, err
+	}
+	if len(book.sheets) == 0 {
+		return nil, fmt.Errorf("%w: no worksheets", ErrInvalidWorkbook)
+	}
+	return newRowReader(book.sheets[0].part, book.shared)
+}
+
+// ReadSheets returns every worksheet of a workbook, in order, read as by
+// ReadRows
+func ReadSheets(r io.ReaderAt, size int64) ([]Sheet, error) {
+	book, err := openWorkbook(r, size)
+	if err != nil {
+		return nil, err
+	}
+	sheets := make([]Sheet, len(book.sheets))
+	for i, sheet := range book.sheets {
+		sheets[i].Name = sheet.name
+		rows, err := newRowReader(sheet.part, book.shared)
+		if err != nil {
+			return nil, err
+		}
+		sheets[i].Rows, err = rows.ReadAll()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sheets, nil
+}
+
+type workbook struct {
+	sheets []sheetPart
+	shared []string
+}
+
+type sheetPart struct {
+	name string
+	part *zip.File
+}
+
+// openWorkbook finds the worksheets of a workbook and loads the strings
+// their cells share
+func openWorkbook(r io.ReaderAt, size int64) (*workbook, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWorkbook, err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var listed struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodePart(files["xl/workbook.xml"], &listed); err != nil {
+		return nil, err
+	}
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodePart(files["xl/_rels/workbook.xml.rels"], &rels); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		// Targets are relative to xl/ unless absolute within the package
+		targets[rel.ID] = path.Join("xl", rel.Target)
+		if strings.HasPrefix(rel.Target, "/") {
+			targets[rel.ID] = strings.TrimPrefix(rel.Target, "/")
+		}
+	}
+
+	book := &workbook{}
+	for _, sheet := range listed.Sheets {
+		part := files[targets[sheet.ID]]
+		if part == nil {
+			return nil, fmt.Errorf("%w: worksheet %q not found", ErrInvalidWorkbook, sheet.Name)
+		}
+		book.sheets = append(book.sheets, sheetPart{name: sheet.Name, part: part})
+	}
+	if book.shared, err = sharedStrings(files["xl/sharedStrings.xml"]); err != nil {
+		return nil, err
+	}
+	return book, nil
+}
+
+// RowReader reads a worksheet one row at a time
+type RowReader struct {
+	src    io.ReadCloser
+	dec    *xml.Decoder
+	shared []string
+	// Number of the last row returned
+	row int
+	// Row read ahead while returning the empty rows left out before it
+	next    []string
+	nextRow int
+}
+
+func newRowReader(sheet *zip.File, shared []string) (*RowReader, error) {
+	src, err := sheet.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWorkbook, err)
+	}
+	return &RowReader{src: src, dec: xml.NewDecoder(src), shared: shared}, nil
+}
+
+// Read returns the next row, or io.EOF after the last one. Empty rows are
+// returned as nil.
+func (r *RowReader) Read() ([]string, error) {
+	for r.nextRow == 0 {
+		tok, err := r.dec.Token()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidWorkbook, err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		// Rows may skip numbers when empty rows are left out
+		r.nextRow = r.row + 1
+		if n, err := strconv.Atoi(attr(start, "r")); err == nil && n > r.row {
+			r.nextRow = n
+		}
+		if r.next, err = r.readCells(); err != nil {
+			return nil, err
+		}
+	}
+
+	r.row++
+	if r.row < r.nextRow {
+		return nil, nil
+	}
+	row := r.next
+	r.next, r.nextRow = nil, 0
+	return row, nil
+}
+
+// ReadAll returns the remaining rows
+func (r *RowReader) ReadAll() ([][]string, error) {
+	var rows [][]string
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+func (r *RowReader) Close() error {
+	return r.src.Close()
+}
+
+// readCells reads the cells of the row just started
+func (r *RowReader) readCells() ([]string, error) {
+	var row []string
+	for {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidWorkbook, err)
+		}
+		switch tok := tok.(type) {
+		case xml.EndElement:
+			if tok.Name.Local == "row" {
+				return row, nil
+			}
+		case xml.StartElement:
+			if tok.Name.Local != "c" {
+				continue
+			}
+			var c cell
+			if err := r.dec.DecodeElement(&c, &tok); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidWorkbook, err)
+			}
+			col := columnIndex(c.Ref)
+			if col < 0 {
+				col = len(row)
+			}
+			for len(row) <= col {
+				row = append(row, "")
+			}
+			row[col] = c.text(r.shared)
+		}
+	}
+}
+
+type cell struct {
+	Ref    string `xml:"r,attr"`
+	Type   string `xml:"t,attr"`
+	Value  string `xml:"v"`
+	Inline struct {
+		Text string   `xml:"t"`
+		Runs []string `xml:"r>t"`
+	} `xml:"is"`
+}
+
+func (c cell) text(shared []string) string {
+	switch c.Type {
+	case "s":
+		if i, err := strconv.Atoi(strings.TrimSpace(c.Value)); err == nil && i >= 0 && i < len(shared) {
+			return shared[i]
+		}
+		return ""
+	case "inlineStr":
+		return c.Inline.Text + strings.Join(c.Inline.Runs, "")
+	case "b":
+		if c.Value == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	return c.Value
+}
+
+func attr(start xml.StartElement, name string) string {
+	for _, a := range start.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// sharedStrings loads the strings cells refer to by index; workbooks
+// without any have no such part
+func sharedStrings(f *zip.File) ([]string, error) {
+	if f == nil {
+		return nil, nil
+	}
+	var table struct {
+		Items []struct {
+			Text string   `xml:"t"`
+			Runs []string `xml:"r>t"`
+		} `xml:"si"`
+	}
+	if err := decodePart(f, &table); err != nil {
+		return nil, err
+	}
+	shared := make([]string, len(table.Items))
+	for i, item := range table.Items {
+		shared[i] = item.Text + strings.Join(item.Runs, "")
+	}
+	return shared, nil
+}
+
+func decodePart(f *zip.File, v interface{}) error {
+	if f == nil {
+		return fmt.Errorf("%w: missing part", ErrInvalidWorkbook)
+	}
+	src, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWorkbook, err)
+	}
+	defer src.Close()
+	if err := xml.NewDecoder(src).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidWorkbook, f.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWriterRoundTrip(t *testing.T) {
+	var out bytes.Buffer
+	w := NewWriter(&out)
+	if err := w.AddSheet("People", []string{"name", "email"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range [][]string{{"Alice & Bob", " spaced "}, {"", "carol@example.com"}} {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Invalid characters are replaced and repeated names numbered
+	for _, name := range []string{"Day 1: Opening / Keynote", "people"} {
+		if err := w.AddSheet(name, []string{"id"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	sheets, err := ReadSheets(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatalf("ReadSheets: %v", err)
+	}
+	want := []Sheet{
+		{Name: "People", Rows: [][]string{{"name", "email"}, {"Alice & Bob", " spaced "}, {"", "carol@example.com"}}},
+		{Name: "Day 1- Opening - Keynote", Rows: [][]string{{"id"}}},
+		{Name: "people (2)", Rows: [][]string{{"id"}}},
+	}
+	if !reflect.DeepEqual(sheets, want) {
+		t.Fatalf("read %+v, want %+v", sheets, want)
+	}
+}
+
+func TestReadRowsSharedStrings(t *testing.T) {
+	// As spreadsheet applications save them: shared strings, numbers, rich
+	// text and empty rows left out
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Import" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>name</t></si><si><t>phone</t></si><si><r><t>Dewi </t></r><r><t>Lestari</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>` +
+			`<row r="3"><c r="A3" t="s"><v>2</v></c><c r="C3"><v>6281200011</v></c><c r="D3" t="b"><v>1</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	var file bytes.Buffer
+	zw := zip.NewWriter(&file)
+	for name, body := range parts {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := ReadRows(bytes.NewReader(file.Bytes()), int64(file.Len()))
+	if err != nil {
+		t.Fatalf("ReadRows: %v", err)
+	}
+	want := [][]string{{"name", "phone"}, nil, {"Dewi Lestari", "", "6281200011", "TRUE"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("read %q, want %q", rows, want)
+	}
+
+	if _, err := ReadRows(bytes.NewReader([]byte("name,email\n")), 11); !errors.Is(err, ErrInvalidWorkbook) {
+		t.Fatalf("CSV read as a workbook: error = %v, want %v", err, ErrInvalidWorkbook)
+	}
+}
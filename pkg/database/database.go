@@ -0,0 +1,88 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+
+	"event-management-backend/internal/config"
+	applog "event-management-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// NewDB connects to the database selected by DB_DRIVER. Postgres is used in
+// production; MySQL is supported for hosts without Postgres and SQLite for
+// local development and tests.
+func NewDB(cfg *config.Config) (*gorm.DB, error) {
+	var (
+		db  *gorm.DB
+		err error
+	)
+
+	switch cfg.DBDriver {
+	case "sqlite":
+		db, err = NewSQLiteDB(cfg)
+	case "mysql":
+		db, err = NewMySQLDB(cfg)
+	default:
+		db, err = NewPostgresDB(cfg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := registerUUIDCallback(db); err != nil {
+		return nil, fmt.Errorf("failed to register uuid callback: %w", err)
+	}
+
+	return db, nil
+}
+
+func newGormConfig(cfg *config.Config) *gorm.Config {
+	logLevel := logger.Warn
+	if cfg.Env == "development" {
+		logLevel = logger.Info
+	}
+
+	return &gorm.Config{
+		Logger: logger.New(applog.Log, logger.Config{
+			SlowThreshold:             cfg.DBSlowQueryThreshold,
+			LogLevel:                  logLevel,
+			IgnoreRecordNotFoundError: true,
+		}),
+	}
+}
+
+// registerUUIDCallback assigns a new UUID to zero uuid.UUID primary keys on
+// create, so IDs never depend on a database-side generator
+func registerUUIDCallback(db *gorm.DB) error {
+	return db.Callback().Create().Before("gorm:create").Register("app:uuid", func(tx *gorm.DB) {
+		if tx.Statement.Schema == nil {
+			return
+		}
+		field := tx.Statement.Schema.PrioritizedPrimaryField
+		if field == nil || field.FieldType != reflect.TypeOf(uuid.UUID{}) {
+			return
+		}
+
+		assign := func(rv reflect.Value) {
+			if _, isZero := field.ValueOf(tx.Statement.Context, rv); isZero {
+				if err := field.Set(tx.Statement.Context, rv, uuid.New()); err != nil {
+					tx.AddError(err)
+				}
+			}
+		}
+
+		rv := tx.Statement.ReflectValue
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				assign(reflect.Indirect(rv.Index(i)))
+			}
+		case reflect.Struct:
+			assign(rv)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"event-management-backend/internal/config"
+	applog "event-management-backend/pkg/logger"
+
+	gomysql "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+// NewMySQLDB connects to MySQL 8 or MariaDB 10.6+ for deployment targets
+// without Postgres
+func NewMySQLDB(cfg *config.Config) (*gorm.DB, error) {
+	dsnConfig := gomysql.NewConfig()
+	dsnConfig.User = cfg.DBUser
+	dsnConfig.Passwd = cfg.DBPass
+	dsnConfig.Net = "tcp"
+	dsnConfig.Addr = fmt.Sprintf("%s:%s", cfg.DBHost, cfg.DBPort)
+	dsnConfig.DBName = cfg.DBName
+	dsnConfig.ParseTime = true
+	dsnConfig.Loc = time.UTC
+	dsnConfig.Params = map[string]string{"charset": "utf8mb4"}
+	if cfg.DBStatementTimeout > 0 {
+		// Session variable, only applies to read-only SELECT statements
+		dsnConfig.Params["max_execution_time"] = fmt.Sprint(cfg.DBStatementTimeout.Milliseconds())
+	}
+
+	dialector := mysql.New(mysql.Config{
+		DSNConfig: dsnConfig,
+		// Indexed strings need a bounded length; 191 characters fit the
+		// index key limit with utf8mb4
+		DefaultStringSize: 191,
+	}).(*mysql.Dialector)
+
+	db, err := gorm.Open(mysqlDialector{Dialector: *dialector}, newGormConfig(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
+	}
+
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+
+	if err := sqlDB.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	applog.Log.Info("MySQL database connected successfully")
+	return db, nil
+}
+
+// mysqlDialector maps the Postgres column types used in the models to their
+// MySQL equivalents, so the models keep a single set of tags
+type mysqlDialector struct {
+	mysql.Dialector
+}
+
+func (d mysqlDialector) DataTypeOf(field *schema.Field) string {
+	switch field.DataType {
+	case "uuid":
+		return "char(36)"
+	case "jsonb":
+		return "json"
+	}
+	return d.Dialector.DataTypeOf(field)
+}
+
+// Migrator is overridden so AutoMigrate resolves column types through
+// mysqlDialector rather than the embedded dialector
+func (d mysqlDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return mysql.Migrator{
+		Migrator: migrator.Migrator{
+			Config: migrator.Config{
+				DB:        db,
+				Dialector: d,
+			},
+		},
+		Dialector: d.Dialector,
+	}
+}
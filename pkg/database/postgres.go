@@ -2,14 +2,12 @@ package database
 
 import (
 	"fmt"
-	"log"
-	"time"
 
 	"event-management-backend/internal/config"
+	applog "event-management-backend/pkg/logger"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 func NewPostgresDB(cfg *config.Config) (*gorm.DB, error) {
@@ -17,13 +15,12 @@ func NewPostgresDB(cfg *config.Config) (*gorm.DB, error) {
 		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
 		cfg.DBHost, cfg.DBUser, cfg.DBPass, cfg.DBName, cfg.DBPort, cfg.DBSSLMode,
 	)
-
-	gormConfig := &gorm.Config{}
-	if cfg.Env == "development" {
-		gormConfig.Logger = logger.Default.LogMode(logger.Info)
+	if cfg.DBStatementTimeout > 0 {
+		// Passed to the server as a runtime parameter on every connection
+		dsn += fmt.Sprintf(" statement_timeout=%d", cfg.DBStatementTimeout.Milliseconds())
 	}
 
-	db, err := gorm.Open(postgres.Open(dsn), gormConfig)
+	db, err := gorm.Open(postgres.Open(dsn), newGormConfig(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -34,15 +31,15 @@ func NewPostgresDB(cfg *config.Config) (*gorm.DB, error) {
 	}
 
 	// Connection pool settings
-	sqlDB.SetMaxOpenConns(25)
-	sqlDB.SetMaxIdleConns(5)
-	sqlDB.SetConnMaxLifetime(5 * time.Minute)
+	sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
 
 	// Test connection
 	if err := sqlDB.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Println("✅ Database connected successfully")
+	applog.Log.Info("Database connected successfully")
 	return db, nil
 }
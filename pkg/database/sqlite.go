@@ -0,0 +1,42 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"event-management-backend/internal/config"
+	applog "event-management-backend/pkg/logger"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// NewSQLiteDB opens a SQLite database file for local development and tests.
+// Use ":memory:" as DB_PATH for a throwaway database.
+func NewSQLiteDB(cfg *config.Config) (*gorm.DB, error) {
+	dsn := cfg.DBPath
+	if dsn != ":memory:" {
+		if err := os.MkdirAll(filepath.Dir(dsn), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+	dsn += "?_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL"
+
+	db, err := gorm.Open(sqlite.Open(dsn), newGormConfig(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database instance: %w", err)
+	}
+
+	// SQLite allows a single writer; one connection avoids "database is
+	// locked" errors and keeps :memory: databases shared
+	sqlDB.SetMaxOpenConns(1)
+
+	applog.Log.WithField("path", cfg.DBPath).Info("SQLite database opened")
+	return db, nil
+}
@@ -8,12 +8,30 @@ import (
 
 var Log *logrus.Logger
 
-func Init() {
+func init() {
+	// Provide a usable logger before Init is called (e.g. while loading config)
 	Log = logrus.New()
 	Log.SetOutput(os.Stdout)
-	Log.SetFormatter(&logrus.JSONFormatter{
-		TimestampFormat: "2006-01-02 15:04:05",
-	})
+}
+
+// Init configures the global logger. Production uses JSON output so log
+// aggregators can index fields; other environments use readable text.
+func Init(level, env string) {
+	Log = logrus.New()
+	Log.SetOutput(os.Stdout)
+
+	if env == "production" {
+		Log.SetFormatter(&logrus.JSONFormatter{
+			TimestampFormat: "2006-01-02 15:04:05",
+		})
+	} else {
+		Log.SetFormatter(&logrus.TextFormatter{
+			FullTimestamp:   true,
+			TimestampFormat: "2006-01-02 15:04:05",
+		})
+	}
+
+	SetLevel(level)
 }
 
 func SetLevel(level string) {
@@ -30,3 +48,8 @@ func SetLevel(level string) {
 		Log.SetLevel(logrus.InfoLevel)
 	}
 }
+
+// With returns an entry carrying the given structured fields
+func With(fields logrus.Fields) *logrus.Entry {
+	return Log.WithFields(fields)
+}
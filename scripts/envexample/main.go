@@ -0,0 +1,17 @@
+package main
+
+import (
+	"os"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/pkg/logger"
+)
+
+// Regenerates .env.example from the configuration definitions:
+//
+//	go run ./scripts/envexample > .env.example
+func main() {
+	if err := config.WriteEnvExample(os.Stdout); err != nil {
+		logger.Log.Fatalf("Failed to write .env.example: %v", err)
+	}
+}
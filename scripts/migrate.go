@@ -1,50 +1,51 @@
 package main
 
 import (
-	"log"
-
 	"event-management-backend/internal/config"
 	"event-management-backend/internal/models"
 	"event-management-backend/internal/repositories"
-	"event-management-backend/internal/utils"
+	"event-management-backend/internal/services"
 	"event-management-backend/pkg/database"
+	"event-management-backend/pkg/logger"
 
 	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 func main() {
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
-		log.Printf("Warning: .env file not found: %v", err)
+		logger.Log.Warnf(".env file not found: %v", err)
 	}
 
 	// Load configuration
 	cfg, err := config.NewConfigFromEnv()
 	if err != nil {
-		log.Fatalf("Config error: %v", err)
+		logger.Log.Fatalf("Config error: %v", err)
 	}
+	logger.Init(cfg.LogLevel, cfg.Env)
 
 	// Initialize database
-	db, err := database.NewPostgresDB(cfg)
+	db, err := database.NewDB(cfg)
 	if err != nil {
-		log.Fatalf("Database connection error: %v", err)
+		logger.Log.Fatalf("Database connection error: %v", err)
 	}
 
 	// Run migrations
 	if err := repositories.AutoMigrate(db); err != nil {
-		log.Fatalf("Migration error: %v", err)
+		logger.Log.Fatalf("Migration error: %v", err)
 	}
 
-	log.Println("✅ Database migrations completed successfully")
+	logger.Log.Info("Database migrations completed successfully")
 
 	// Create default admin user if not exists
 	if err := createDefaultAdmin(db, cfg); err != nil {
-		log.Fatalf("Failed to create default admin: %v", err)
+		logger.Log.Fatalf("Failed to create default admin: %v", err)
 	}
 
-	log.Println("✅ Default admin user created (if not exists)")
-	log.Println("🎉 Migration process completed!")
+	logger.Log.Info("Default admin user created (if not exists)")
+	logger.Log.Info("Migration process completed")
 }
 
 func createDefaultAdmin(db *gorm.DB, cfg *config.Config) error {
@@ -54,12 +55,12 @@ func createDefaultAdmin(db *gorm.DB, cfg *config.Config) error {
 	// Check if admin already exists
 	var existingAdmin models.User
 	if err := db.Where("email = ?", adminEmail).First(&existingAdmin).Error; err == nil {
-		log.Println("ℹ️  Default admin user already exists")
+		logger.Log.Info("Default admin user already exists")
 		return nil
 	}
 
 	// Hash password
-	hashedPassword, err := utils.HashPassword(adminPassword)
+	hashedPassword, err := services.NewPasswordHasher(cfg).Hash(adminPassword)
 	if err != nil {
 		return err
 	}
@@ -75,10 +76,11 @@ func createDefaultAdmin(db *gorm.DB, cfg *config.Config) error {
 		return err
 	}
 
-	log.Printf("✅ Default admin user created:")
-	log.Printf("   Email: %s", adminEmail)
-	log.Printf("   Password: %s", adminPassword)
-	log.Printf("   Role: %s", admin.Role)
+	logger.Log.WithFields(logrus.Fields{
+		"email":    adminEmail,
+		"password": adminPassword,
+		"role":     admin.Role,
+	}).Info("Default admin user created")
 
 	return nil
 }
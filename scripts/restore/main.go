@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"event-management-backend/internal/backup"
+	"event-management-backend/internal/config"
+	"event-management-backend/pkg/logger"
+
+	"github.com/joho/godotenv"
+)
+
+// Restores a backup created by the backup job. Stop the API first, then:
+//
+//	go run ./scripts/restore -list
+//	go run ./scripts/restore -name backup-20240101T000000Z.tar.gz -confirm
+//
+// The database is restored with pg_restore --clean, dropping objects that
+// exist in the backup before recreating them. Upload files are written over
+// QR_DIR and LOGO_DIR unless -skip-uploads is given. Storage settings are
+// read from the same BACKUP_* variables as the server.
+func main() {
+	list := flag.Bool("list", false, "list available backups and exit")
+	name := flag.String("name", "", "backup to restore")
+	skipUploads := flag.Bool("skip-uploads", false, "restore only the database")
+	confirm := flag.Bool("confirm", false, "required to overwrite the current database")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		logger.Log.Warnf(".env file not found: %v", err)
+	}
+
+	cfg, err := config.NewConfigFromEnv()
+	if err != nil {
+		logger.Log.Fatalf("Config error: %v", err)
+	}
+	logger.Init(cfg.LogLevel, cfg.Env)
+
+	svc := backup.NewService(backup.NewStore(cfg), cfg)
+	ctx := context.Background()
+
+	if *list {
+		backups, err := svc.List(ctx)
+		if err != nil {
+			logger.Log.Fatalf("Failed to list backups: %v", err)
+		}
+		for _, b := range backups {
+			fmt.Printf("%s\t%d bytes\t%s\n", b.Name, b.Size, b.LastModified.Format("2006-01-02 15:04:05"))
+		}
+		return
+	}
+
+	if *name == "" {
+		logger.Log.Fatal("-name is required (use -list to see available backups)")
+	}
+	if !*confirm {
+		logger.Log.Fatalf("Restoring %s overwrites database %s; re-run with -confirm", *name, cfg.DBName)
+	}
+
+	if err := svc.Restore(ctx, *name, *skipUploads); err != nil {
+		logger.Log.Fatalf("Restore failed: %v", err)
+	}
+
+	logger.Log.WithField("backup", *name).Info("Restore completed")
+}
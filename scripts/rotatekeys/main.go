@@ -0,0 +1,163 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"flag"
+	"fmt"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/fieldcrypt"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/pkg/database"
+	"event-management-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Re-encrypts participant PII with the primary key and backfills email
+// hashes. Rotating keys:
+//
+//  1. go run ./scripts/rotatekeys -generate        # print a new key
+//  2. add it to PII_ENCRYPTION_KEYS and point PII_PRIMARY_KEY at it
+//  3. go run ./scripts/rotatekeys                  # re-encrypt existing rows
+//  4. remove the old key from PII_ENCRYPTION_KEYS
+//
+// Running it after enabling encryption for the first time encrypts the
+// existing plaintext rows.
+
+type piiRow struct {
+	ID        uuid.UUID
+	Email     string
+	Phone     string
+	Address   string
+	EmailHash string
+}
+
+func main() {
+	generate := flag.Bool("generate", false, "print a new random base64 key and exit")
+	batchSize := flag.Int("batch", 500, "rows per batch")
+	dryRun := flag.Bool("dry-run", false, "only count rows that need re-encryption")
+	flag.Parse()
+
+	if *generate {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			logger.Log.Fatalf("Failed to generate key: %v", err)
+		}
+		fmt.Println(base64.StdEncoding.EncodeToString(key))
+		return
+	}
+
+	if err := godotenv.Load(); err != nil {
+		logger.Log.Warnf(".env file not found: %v", err)
+	}
+
+	cfg, err := config.NewConfigFromEnv()
+	if err != nil {
+		logger.Log.Fatalf("Config error: %v", err)
+	}
+	logger.Init(cfg.LogLevel, cfg.Env)
+
+	if err := fieldcrypt.Configure(cfg.PIIEncryptionKeys, cfg.PIIPrimaryKey, cfg.PIIHashKey); err != nil {
+		logger.Log.Fatalf("Encryption key error: %v", err)
+	}
+	if !fieldcrypt.Enabled() {
+		logger.Log.Fatal("PII_ENCRYPTION_KEYS is not set, nothing to rotate")
+	}
+
+	db, err := database.NewDB(cfg)
+	if err != nil {
+		logger.Log.Fatalf("Database connection error: %v", err)
+	}
+
+	if err := repositories.AutoMigrate(db); err != nil {
+		logger.Log.Fatalf("Migration error: %v", err)
+	}
+
+	scanned, rotated, err := rotate(db, *batchSize, *dryRun)
+	if err != nil {
+		logger.Log.Fatalf("Key rotation failed after %d rows: %v", scanned, err)
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"scanned": scanned,
+		"rotated": rotated,
+		"dry_run": *dryRun,
+	}).Info("Key rotation completed")
+}
+
+// rotate walks the participants table by primary key, reading the raw column
+// values so rows already encrypted with the primary key can be skipped
+func rotate(db *gorm.DB, batchSize int, dryRun bool) (int, int, error) {
+	scanned, rotated := 0, 0
+	lastID := uuid.Nil
+
+	for {
+		var rows []piiRow
+		if err := db.Table("participants").
+			Select("id, email, phone, address, email_hash").
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(batchSize).
+			Scan(&rows).Error; err != nil {
+			return scanned, rotated, err
+		}
+		if len(rows) == 0 {
+			return scanned, rotated, nil
+		}
+
+		for _, row := range rows {
+			scanned++
+			lastID = row.ID
+
+			if !needsRotation(row) {
+				continue
+			}
+			rotated++
+			if dryRun {
+				continue
+			}
+
+			updates, err := reencrypt(row)
+			if err != nil {
+				return scanned, rotated, fmt.Errorf("participant %s: %w", row.ID, err)
+			}
+			if err := db.Table("participants").Where("id = ?", row.ID).UpdateColumns(updates).Error; err != nil {
+				return scanned, rotated, fmt.Errorf("participant %s: %w", row.ID, err)
+			}
+		}
+	}
+}
+
+func needsRotation(row piiRow) bool {
+	return row.EmailHash == "" ||
+		fieldcrypt.NeedsRotation(row.Email) ||
+		fieldcrypt.NeedsRotation(row.Phone) ||
+		fieldcrypt.NeedsRotation(row.Address)
+}
+
+func reencrypt(row piiRow) (map[string]interface{}, error) {
+	updates := make(map[string]interface{}, 4)
+
+	for column, raw := range map[string]string{"email": row.Email, "phone": row.Phone, "address": row.Address} {
+		plaintext, err := fieldcrypt.Decrypt(raw)
+		if err != nil {
+			return nil, err
+		}
+		if column == "email" {
+			updates["email_hash"] = fieldcrypt.Hash(plaintext)
+		}
+
+		encrypted, err := fieldcrypt.Encrypt(plaintext)
+		if err != nil {
+			return nil, err
+		}
+		updates[column] = encrypted
+	}
+
+	return updates, nil
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"event-management-backend/internal/config"
+	"event-management-backend/internal/fieldcrypt"
+	"event-management-backend/internal/models"
+	"event-management-backend/internal/repositories"
+	"event-management-backend/internal/utils"
+	"event-management-backend/pkg/database"
+	"event-management-backend/pkg/logger"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Seeds demo data for local development and demo environments:
+//
+//	go run ./scripts/seed -participants 300
+//
+// Events are keyed by slug, so running the seeder twice leaves existing demo
+// events untouched.
+
+const demoPassword = "demo1234"
+
+var (
+	firstNames = []string{"Andi", "Budi", "Citra", "Dewi", "Eko", "Fajar", "Gita", "Hadi", "Indah", "Joko", "Kartika", "Lestari", "Made", "Nur", "Oka", "Putri", "Rizky", "Sari", "Teguh", "Wulan"}
+	lastNames  = []string{"Pratama", "Saputra", "Wijaya", "Santoso", "Hidayat", "Kusuma", "Nugroho", "Lestari", "Siregar", "Halim", "Gunawan", "Setiawan"}
+	divisions  = []string{"Engineering", "Marketing", "Finance", "Operations", "Sales", "HR"}
+	cities     = []string{"Jakarta", "Bandung", "Surabaya", "Yogyakarta", "Medan", "Denpasar", "Makassar"}
+)
+
+type demoEvent struct {
+	title       string
+	slug        string
+	description string
+	startsIn    time.Duration
+	days        int
+	price       float64
+	quota       *int
+	actions     []string
+}
+
+func main() {
+	participants := flag.Int("participants", 300, "participants to create per event")
+	verifiedRatio := flag.Float64("verified", 0.6, "share of participants with verification history")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "random seed for reproducible data")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		logger.Log.Warnf(".env file not found: %v", err)
+	}
+
+	cfg, err := config.NewConfigFromEnv()
+	if err != nil {
+		logger.Log.Fatalf("Config error: %v", err)
+	}
+	logger.Init(cfg.LogLevel, cfg.Env)
+
+	if err := fieldcrypt.Configure(cfg.PIIEncryptionKeys, cfg.PIIPrimaryKey, cfg.PIIHashKey); err != nil {
+		logger.Log.Fatalf("Encryption key error: %v", err)
+	}
+
+	if cfg.Env == "production" {
+		logger.Log.Fatal("Refusing to seed demo data in production")
+	}
+
+	db, err := database.NewDB(cfg)
+	if err != nil {
+		logger.Log.Fatalf("Database connection error: %v", err)
+	}
+
+	if err := repositories.AutoMigrate(db); err != nil {
+		logger.Log.Fatalf("Migration error: %v", err)
+	}
+
+	rng := rand.New(rand.NewSource(*seed))
+
+	staff, err := seedUsers(db)
+	if err != nil {
+		logger.Log.Fatalf("Failed to seed users: %v", err)
+	}
+
+	quota := 500
+	events := []demoEvent{
+		{
+			title:       "Tech Summit 2024",
+			slug:        "demo-tech-summit",
+			description: "Two days of talks and workshops on cloud, data and AI.",
+			startsIn:    14 * 24 * time.Hour,
+			days:        2,
+			price:       250000,
+			quota:       &quota,
+			actions:     []string{"Check-in", "Lunch", "Workshop", "Merchandise"},
+		},
+		{
+			title:       "Company Family Gathering",
+			slug:        "demo-family-gathering",
+			description: "Annual gathering for employees and their families.",
+			startsIn:    -2 * 24 * time.Hour,
+			days:        1,
+			actions:     []string{"Check-in", "Doorprize", "Dinner"},
+		},
+	}
+
+	for _, e := range events {
+		if err := seedEvent(db, cfg, rng, e, staff, *participants, *verifiedRatio); err != nil {
+			logger.Log.Fatalf("Failed to seed event %s: %v", e.slug, err)
+		}
+	}
+
+	logger.Log.WithField("seed", *seed).Info("Demo data seeding completed")
+}
+
+// seedUsers creates one user per role and returns the staff user used as the
+// verifier of the generated history
+func seedUsers(db *gorm.DB) (*models.User, error) {
+	hashedPassword, err := utils.HashPassword(demoPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	var staff *models.User
+	for _, role := range []string{"admin", "organizer", "staff"} {
+		user := models.User{
+			Email:    fmt.Sprintf("%s@demo.event.com", role),
+			Password: hashedPassword,
+			Role:     role,
+		}
+		if err := db.Where("email = ?", user.Email).FirstOrCreate(&user).Error; err != nil {
+			return nil, err
+		}
+		if role == "staff" {
+			staff = &user
+		}
+
+		logger.Log.WithFields(logrus.Fields{
+			"email":    user.Email,
+			"password": demoPassword,
+			"role":     role,
+		}).Info("Demo user ready")
+	}
+
+	return staff, nil
+}
+
+func seedEvent(db *gorm.DB, cfg *config.Config, rng *rand.Rand, e demoEvent, verifier *models.User, participantCount int, verifiedRatio float64) error {
+	var existing models.Event
+	if err := db.Where("slug = ?", e.slug).First(&existing).Error; err == nil {
+		logger.Log.WithField("slug", e.slug).Info("Demo event already exists, skipping")
+		return nil
+	}
+
+	startsAt := time.Now().Add(e.startsIn).Truncate(24 * time.Hour).Add(8 * time.Hour)
+	event := &models.Event{
+		ID:          uuid.New(),
+		Title:       e.title,
+		Slug:        e.slug,
+		Description: e.description,
+		StartsAt:    startsAt,
+		EndsAt:      startsAt.Add(time.Duration(e.days-1)*24*time.Hour + 10*time.Hour),
+		TicketPrice: e.price,
+		TicketQuota: e.quota,
+		IsActive:    true,
+	}
+
+	var actions []models.EventAction
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+
+		for day := 1; day <= e.days; day++ {
+			eventDay := &models.EventDay{
+				ID:        uuid.New(),
+				EventID:   event.ID,
+				DayNumber: day,
+				Label:     fmt.Sprintf("Day %d", day),
+				Date:      startsAt.Add(time.Duration(day-1) * 24 * time.Hour),
+			}
+			if err := tx.Create(eventDay).Error; err != nil {
+				return err
+			}
+
+			for _, name := range e.actions {
+				action := models.EventAction{
+					ID:         uuid.New(),
+					EventID:    event.ID,
+					EventDayID: eventDay.ID,
+					Name:       name,
+					Code:       fmt.Sprintf("%s-D%d-%s", strings.ToUpper(e.slug), day, strings.ToUpper(strings.ReplaceAll(name, " ", "-"))),
+					IsActive:   true,
+				}
+				if err := tx.Create(&action).Error; err != nil {
+					return err
+				}
+				actions = append(actions, action)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	participants := make([]models.Participant, 0, participantCount)
+	for i := 0; i < participantCount; i++ {
+		first := firstNames[rng.Intn(len(firstNames))]
+		last := lastNames[rng.Intn(len(lastNames))]
+
+		participant := models.Participant{
+			ID:            uuid.New(),
+			EventID:       event.ID,
+			Name:          fmt.Sprintf("%s %s", first, last),
+			Email:         fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(first), strings.ToLower(last), i+1),
+			Phone:         fmt.Sprintf("08%010d", rng.Int63n(1e10)),
+			Division:      divisions[rng.Intn(len(divisions))],
+			Address:       cities[rng.Intn(len(cities))],
+			PaymentStatus: randomPaymentStatus(rng, e.price),
+			CreatedAt:     event.StartsAt.Add(-time.Duration(rng.Intn(30*24)) * time.Hour),
+		}
+
+		filename, err := utils.GenerateQRCodeImage(participant.ID.String(), cfg.QRDir)
+		if err != nil {
+			return err
+		}
+		participant.QRPath = fmt.Sprintf("/qrcodes/%s", filename)
+
+		participants = append(participants, participant)
+	}
+
+	if err := db.CreateInBatches(participants, 100).Error; err != nil {
+		return fmt.Errorf("failed to create participants: %w", err)
+	}
+
+	// Verification history only makes sense for events that already started
+	var logs []models.ActionLog
+	if event.StartsAt.Before(time.Now()) {
+		for _, participant := range participants {
+			if rng.Float64() >= verifiedRatio {
+				continue
+			}
+			for _, action := range actions {
+				if rng.Intn(3) == 0 {
+					continue
+				}
+				verifiedAt := event.StartsAt.Add(time.Duration(rng.Intn(10*60)) * time.Minute)
+				logs = append(logs, models.ActionLog{
+					ID:            uuid.New(),
+					ParticipantID: participant.ID,
+					ActionID:      action.ID,
+					VerifiedBy:    &verifier.ID,
+					VerifiedAt:    verifiedAt,
+					CreatedAt:     verifiedAt,
+				})
+			}
+		}
+
+		if len(logs) > 0 {
+			if err := db.CreateInBatches(logs, 200).Error; err != nil {
+				return fmt.Errorf("failed to create verification history: %w", err)
+			}
+		}
+	}
+
+	logger.Log.WithFields(logrus.Fields{
+		"slug":          event.Slug,
+		"actions":       len(actions),
+		"participants":  len(participants),
+		"verifications": len(logs),
+	}).Info("Demo event seeded")
+
+	return nil
+}
+
+func randomPaymentStatus(rng *rand.Rand, price float64) string {
+	if price == 0 {
+		return "paid"
+	}
+	switch n := rng.Intn(10); {
+	case n < 7:
+		return "paid"
+	case n < 9:
+		return "pending"
+	default:
+		return "unpaid"
+	}
+}